@@ -0,0 +1,346 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+// trustFileEnvVar é a variável de ambiente que substitui o caminho do registro de arquivos autorizados.
+const trustFileEnvVar = "LOCENV_TRUST_FILE"
+
+/*
+sensitiveKeys são os padrões de path.Match das variáveis que alteram o comportamento do shell ou dos programas
+que ele executa, e que export só escreve quando autorizadas com allow -keys
+*/
+var sensitiveKeys = []string{
+	"PATH", "CDPATH", "IFS", "ENV", "BASH_ENV", "SHELLOPTS", "BASHOPTS", "GLOBIGNORE", "ZDOTDIR",
+	"PROMPT_COMMAND", "PS0", "PS1", "PS2", "PS3", "PS4", "PROMPT", "RPROMPT",
+	"LD_*", "DYLD_*", "PSModulePath", "NODE_OPTIONS", "PYTHONSTARTUP", "PYTHONPATH", "PERL5OPT", "PERL5LIB", "RUBYOPT",
+	"GIT_SSH", "GIT_SSH_COMMAND", "GIT_EXEC_PATH", "GIT_EXTERNAL_DIFF", "GIT_PAGER", "PAGER", "EDITOR", "VISUAL",
+	loadedEnvVar,
+}
+
+/*
+trustEntry registra a autorização de um arquivo .env
+
+Hash string - O SHA-256 do conteúdo autorizado, em hexadecimal
+Keys []string - As variáveis sensíveis autorizadas no arquivo
+*/
+type trustEntry struct {
+	Hash string   `json:"sha256"`
+	Keys []string `json:"keys,omitempty"`
+}
+
+/*
+trustStore é o registro dos arquivos .env autorizados por allow, gravado em JSON
+
+path string - O caminho do registro
+Files map[string]trustEntry - As autorizações, pelo caminho absoluto do arquivo
+*/
+type trustStore struct {
+	path  string
+	Files map[string]trustEntry `json:"files"`
+}
+
+/*
+openTrustStore lê o registro de $LOCENV_TRUST_FILE ou de <diretório de configuração do usuário>/locenv/allowed.json
+
+Um registro inexistente é tratado como vazio.
+
+@return *trustStore - O registro
+@return error - Um erro se o caminho não puder ser determinado ou o registro for inválido
+*/
+func openTrustStore() (*trustStore, error) {
+	file := os.Getenv(trustFileEnvVar)
+	if file == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, err
+		}
+		file = filepath.Join(dir, "locenv", "allowed.json")
+	}
+
+	store := &trustStore{path: file, Files: make(map[string]trustEntry)}
+	content, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, store); err != nil {
+		return nil, fmt.Errorf("registro de autorizações inválido em %s: %w", file, err)
+	}
+	if store.Files == nil {
+		store.Files = make(map[string]trustEntry)
+	}
+
+	return store, nil
+}
+
+/*
+save grava o registro com permissão 0600, com uma troca atômica
+
+@return error - Um erro se o registro não puder ser gravado
+*/
+func (s *trustStore) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".allowed-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(content, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
+
+/*
+trusted informa se o conteúdo atual de um arquivo foi autorizado
+
+@param file string - O caminho absoluto do arquivo
+
+@return trustEntry - A autorização
+@return bool - Se o arquivo foi autorizado e não mudou desde então
+*/
+func (s *trustStore) trusted(file string) (trustEntry, bool) {
+	entry, ok := s.Files[file]
+	if !ok {
+		return trustEntry{}, false
+	}
+	hash, err := fileHash(file)
+
+	return entry, err == nil && hash == entry.Hash
+}
+
+/*
+fileHash calcula o SHA-256 do conteúdo de um arquivo
+
+@param file string - O caminho do arquivo
+
+@return string - O hash, em hexadecimal
+@return error - Um erro se o arquivo não puder ser lido
+*/
+func fileHash(file string) (string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+/*
+isSensitiveKey informa se uma variável corresponde a sensitiveKeys
+
+@param key string - O nome da variável
+
+@return bool - Se a variável é sensível
+*/
+func isSensitiveKey(key string) bool {
+	for _, pattern := range sensitiveKeys {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+projectFiles retorna os arquivos .env que o perfil do diretório atual carregaria, com caminhos absolutos
+
+@return []string - Os arquivos
+@return string - O ambiente do perfil
+@return error - config.ErrEnvNotFound fora de um projeto, ou um erro se a descoberta falhar
+*/
+func projectFiles() ([]string, string, error) {
+	if !insideProject() {
+		return nil, "", config.ErrEnvNotFound
+	}
+	plan, err := config.NewEnvLoader(config.WithSilent()).Plan()
+	if err != nil {
+		return nil, "", err
+	}
+
+	files := make([]string, len(plan.Files))
+	for i, file := range plan.Files {
+		if files[i], err = filepath.Abs(file); err != nil {
+			return nil, "", err
+		}
+	}
+
+	return files, plan.Env, nil
+}
+
+// globLiteral protege os metacaracteres de filepath.Glob de um caminho, para que WithFiles o leia literalmente.
+func globLiteral(file string) string {
+	return strings.NewReplacer("*", "[*]", "?", "[?]", "[", "[[]").Replace(file)
+}
+
+/*
+runAllow executa o subcomando allow, que autoriza os arquivos .env carregados pelo hook do shell
+
+Sem argumentos, autoriza os arquivos do perfil do diretório atual. O hash de cada arquivo é registrado, e export
+ignora os arquivos não autorizados ou alterados desde a autorização, como o direnv. As variáveis sensíveis, como
+PATH, PROMPT_COMMAND e LD_PRELOAD, só são exportadas quando listadas em -keys.
+
+@param args []string - Os argumentos do subcomando: os arquivos a autorizar
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - 0 em caso de sucesso, 1 se algum arquivo não puder ser lido ou o registro gravado, 2 em caso de erro de uso
+*/
+func runAllow(args []string, stdout io.Writer, stderr io.Writer) int {
+	return changeTrust("allow", args, stdout, stderr)
+}
+
+/*
+runDeny executa o subcomando deny, que remove a autorização dos arquivos .env
+
+@param args []string - Os argumentos do subcomando: os arquivos; sem eles, os do perfil do diretório atual
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - 0 em caso de sucesso, 1 se o registro não puder ser gravado, 2 em caso de erro de uso
+*/
+func runDeny(args []string, stdout io.Writer, stderr io.Writer) int {
+	return changeTrust("deny", args, stdout, stderr)
+}
+
+/*
+changeTrust autoriza ou remove a autorização de arquivos .env, para allow e deny
+
+@param name string - O subcomando, allow ou deny
+@param args []string - Os argumentos do subcomando
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - O código de saída do subcomando
+*/
+func changeTrust(name string, args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var keys *string
+	if name == "allow" {
+		keys = flags.String("keys", "", "variáveis sensíveis autorizadas nos arquivos, separadas por vírgula (ex.: PATH,LD_LIBRARY_PATH)")
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	files := flags.Args()
+	if len(files) == 0 {
+		var err error
+		if files, _, err = projectFiles(); err != nil {
+			fmt.Fprintf(stderr, "locenv: %s\n", err)
+			return 1
+		}
+	}
+
+	store, err := openTrustStore()
+	if err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 1
+	}
+	for _, file := range files {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			fmt.Fprintf(stderr, "locenv: %s\n", err)
+			return 1
+		}
+		if name == "deny" {
+			delete(store.Files, abs)
+			fmt.Fprintf(stdout, "Autorização removida: %s\n", abs)
+			continue
+		}
+
+		hash, err := fileHash(abs)
+		if err != nil {
+			fmt.Fprintf(stderr, "locenv: %s\n", err)
+			return 1
+		}
+		allowed := splitList(*keys)
+		sort.Strings(allowed)
+		store.Files[abs] = trustEntry{Hash: hash, Keys: allowed}
+		fmt.Fprintf(stdout, "Autorizado: %s\n", abs)
+	}
+	if err := store.save(); err != nil {
+		fmt.Fprintf(stderr, "locenv: erro ao gravar o registro de autorizações: %s\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+/*
+trustedLoader carrega o perfil do diretório atual apenas com os arquivos autorizados
+
+Os arquivos não autorizados ou alterados desde a autorização são ignorados, com um aviso em stderr. Se nenhum
+arquivo restar, o retorno é config.ErrEnvNotFound.
+
+@param stderr io.Writer - A saída dos avisos
+
+@return config.Reader - A configuração carregada
+@return map[string]trustEntry - A autorização de cada arquivo carregado
+@return error - config.ErrEnvNotFound, ou um erro se a descoberta ou o carregamento falhar
+*/
+func trustedLoader(stderr io.Writer) (config.Reader, map[string]trustEntry, error) {
+	files, env, err := projectFiles()
+	if err != nil {
+		return nil, nil, err
+	}
+	store, err := openTrustStore()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	approved := make(map[string]trustEntry)
+	var allowed []string
+	for _, file := range files {
+		entry, ok := store.trusted(file)
+		if !ok {
+			fmt.Fprintf(stderr, "locenv: %s não autorizado ou alterado; revise o arquivo e execute locenv allow\n", file)
+			continue
+		}
+		approved[file] = entry
+		allowed = append(allowed, globLiteral(file))
+	}
+	if len(allowed) == 0 {
+		return nil, nil, config.ErrEnvNotFound
+	}
+
+	loader := config.NewEnvLoader(config.WithSilent(), config.WithFiles(allowed...), config.WithEnv(env))
+	if err := loader.LoadEnv(); err != nil {
+		return nil, nil, err
+	}
+
+	return loader, approved, nil
+}
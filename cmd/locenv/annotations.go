@@ -0,0 +1,151 @@
+package main
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var (
+	// githubDataEscaper escapa a mensagem de um comando de workflow do GitHub Actions.
+	githubDataEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	// githubPropertyEscaper escapa as propriedades (file, line, title) de um comando de workflow.
+	githubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+/*
+gitlabIssue é um problema no formato do relatório de Code Quality do GitLab
+
+Description string - A descrição exibida no merge request
+CheckName string - O nome da verificação (locenv-<subcomando>)
+Fingerprint string - O identificador estável do problema, usado pelo GitLab para comparar as execuções
+Severity string - info, minor, major, critical ou blocker
+Location gitlabLocation - O arquivo e a linha
+*/
+type gitlabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitlabLocation `json:"location"`
+}
+
+// gitlabLocation é a posição de um problema do relatório de Code Quality do GitLab.
+type gitlabLocation struct {
+	Path  string `json:"path"`
+	Lines struct {
+		Begin int `json:"begin"`
+	} `json:"lines"`
+}
+
+/*
+writeGitHub escreve os problemas como comandos de workflow do GitHub Actions
+
+Cada problema vira uma linha ::error ou ::warning com o arquivo e a linha, que o GitHub exibe junto ao trecho
+do pull request. Os problemas sem arquivo aparecem no resumo da execução.
+
+@param w io.Writer - A saída em que os comandos são escritos
+
+@return error - Um erro de escrita
+*/
+func (r *report) writeGitHub(w io.Writer) error {
+	for _, p := range r.Problems {
+		properties := []string{"title=" + githubPropertyEscaper.Replace(r.title(p))}
+		if p.File != "" {
+			properties = append(properties, "file="+githubPropertyEscaper.Replace(annotationPath(p.File)))
+			if p.Line > 0 {
+				properties = append(properties, "line="+strconv.Itoa(p.Line))
+			}
+		}
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", p.Severity, strings.Join(properties, ","), githubDataEscaper.Replace(p.describe())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+writeGitLab escreve os problemas no formato do relatório de Code Quality do GitLab
+
+O resultado é a lista JSON esperada por artifacts:reports:codequality. O GitLab exige um arquivo em cada
+problema, de modo que os problemas sem arquivo apontam para a raiz do repositório.
+
+@param w io.Writer - A saída em que o relatório é escrito
+
+@return error - Um erro de escrita
+*/
+func (r *report) writeGitLab(w io.Writer) error {
+	issues := make([]gitlabIssue, 0, len(r.Problems))
+	for _, p := range r.Problems {
+		issue := gitlabIssue{
+			Description: p.describe(),
+			CheckName:   "locenv-" + strings.ReplaceAll(r.Command, " ", "-"),
+			Severity:    "major",
+		}
+		if p.Severity == severityWarning {
+			issue.Severity = "minor"
+		}
+		issue.Location.Path, issue.Location.Lines.Begin = ".", 1
+		if p.File != "" {
+			issue.Location.Path = annotationPath(p.File)
+		}
+		if p.Line > 0 {
+			issue.Location.Lines.Begin = p.Line
+		}
+
+		sum := md5.Sum([]byte(strings.Join([]string{issue.CheckName, p.Severity, p.File, strconv.Itoa(p.Line), p.Key, p.Env, p.Message}, "\x00")))
+		issue.Fingerprint = hex.EncodeToString(sum[:])
+		issues = append(issues, issue)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(issues)
+}
+
+// title retorna o título de uma anotação: o subcomando e, quando conhecida, a variável.
+func (r *report) title(p reportProblem) string {
+	if p.Key != "" {
+		return "locenv " + r.Command + ": " + p.Key
+	}
+
+	return "locenv " + r.Command
+}
+
+// describe retorna a mensagem do problema, precedida do ambiente quando o relatório cobre mais de um.
+func (p reportProblem) describe() string {
+	if p.Env != "" {
+		return "[" + p.Env + "] " + p.Message
+	}
+
+	return p.Message
+}
+
+/*
+annotationPath converte o caminho de um problema para o formato esperado pelas anotações
+
+O GitHub e o GitLab esperam caminhos relativos à raiz do repositório, com barras; os caminhos absolutos dentro
+do diretório atual, onde a CI executa a verificação, são convertidos para relativos.
+
+@param file string - O caminho do arquivo
+
+@return string - O caminho relativo, com barras, ou o caminho original se estiver fora do diretório atual
+*/
+func annotationPath(file string) string {
+	if filepath.IsAbs(file) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, file); err == nil && !strings.HasPrefix(rel, "..") {
+				file = rel
+			}
+		}
+	}
+
+	return filepath.ToSlash(filepath.Clean(file))
+}
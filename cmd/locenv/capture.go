@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+// captureKeyEnvVar é a variável de ambiente que guarda a chave de assinatura das capturas.
+const captureKeyEnvVar = "LOCENV_CAPTURE_KEY"
+
+/*
+runCapture executa o subcomando capture, que registra a configuração efetiva para anexar a um relato de problema
+
+A captura contém a trilha da descoberta, os arquivos carregados e a origem de cada variável, com os segredos
+mascarados, e é assinada com a chave de -key ou de $LOCENV_CAPTURE_KEY. A equipe de suporte reproduz a
+configuração com config.LoadFromCapture. Um carregamento que falha também é capturado, com o erro registrado.
+
+@param args []string - Os argumentos do subcomando
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - 0 em caso de sucesso, 1 se a captura não puder ser escrita, 2 em caso de erro de uso
+*/
+func runCapture(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("capture", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	output := flags.String("o", "", "arquivo em que a captura é escrita (padrão: saída padrão)")
+	key := flags.String("key", "", "chave de assinatura compartilhada com o suporte (padrão: $"+captureKeyEnvVar+")")
+	secretKeys := flags.String("secret-keys", strings.Join(config.DefaultSecretPatterns, ","), "padrões de nomes de variáveis secretas, separados por vírgula")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if err := applyProjectConfig(flags); err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 2
+	}
+
+	if *key == "" {
+		*key = os.Getenv(captureKeyEnvVar)
+	}
+
+	c := config.NewCapture(config.WithSilent(), config.WithSecretKeys(splitList(*secretKeys)...))
+	c.Sign([]byte(*key))
+	if c.Error != "" {
+		fmt.Fprintf(stderr, "locenv: o carregamento falhou e o erro foi registrado na captura: %s\n", c.Error)
+	}
+
+	w := stdout
+	if *output != "" {
+		file, err := os.OpenFile(*output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			fmt.Fprintf(stderr, "locenv: %s\n", err)
+			return 1
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if _, err := c.WriteTo(w); err != nil {
+		fmt.Fprintf(stderr, "locenv: erro ao escrever a captura: %s\n", err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+// keyEnvVar é a variável de ambiente que guarda a chave do backend local, em base64.
+const keyEnvVar = "LOCENV_KEY"
+
+/*
+cipherFlags são as opções que escolhem o backend de cifra dos subcomandos encrypt e decrypt
+
+backend *string - O nome do backend usado no marcador enc:<backend>:
+key *string - A chave do backend local, em base64
+keyFile *string - O arquivo com a chave do backend local, em base64
+encryptCmd *string - O comando externo que cifra (ex.: "age -r age1...")
+decryptCmd *string - O comando externo que decifra (ex.: "age -d -i key.txt")
+*/
+type cipherFlags struct {
+	backend    *string
+	key        *string
+	keyFile    *string
+	encryptCmd *string
+	decryptCmd *string
+}
+
+/*
+newCipherFlags registra as opções de backend no conjunto de flags
+
+@param flags *flag.FlagSet - O conjunto de flags do subcomando
+
+@return *cipherFlags - As opções registradas
+*/
+func newCipherFlags(flags *flag.FlagSet) *cipherFlags {
+	return &cipherFlags{
+		backend:    flags.String("backend", "local", "nome do backend usado no marcador enc:<backend>:"),
+		key:        flags.String("key", "", "chave AES do backend local, em base64 (padrão: $"+keyEnvVar+")"),
+		keyFile:    flags.String("key-file", "", "arquivo com a chave AES do backend local, em base64"),
+		encryptCmd: flags.String("encrypt-cmd", "", "comando externo que cifra a entrada padrão (ex.: \"age -r age1...\")"),
+		decryptCmd: flags.String("decrypt-cmd", "", "comando externo que decifra a entrada padrão (ex.: \"age -d -i key.txt\")"),
+	}
+}
+
+/*
+cipher cria o Encrypter e o Decrypter do backend escolhido
+
+Com -encrypt-cmd ou -decrypt-cmd, a cifra é delegada ao comando externo (age, gpg, CLIs de KMS); os argumentos
+são separados por espaços, sem interpretação de aspas. Caso contrário, é usada a chave AES local.
+
+@return config.Encrypter - O Encrypter do backend
+@return config.Decrypter - O Decrypter do backend
+@return error - Um erro se a chave local não puder ser obtida
+*/
+func (c *cipherFlags) cipher() (config.Encrypter, config.Decrypter, error) {
+	if *c.encryptCmd != "" || *c.decryptCmd != "" {
+		cmd := config.CommandCipher{EncryptCommand: strings.Fields(*c.encryptCmd), DecryptCommand: strings.Fields(*c.decryptCmd)}
+		return cmd, cmd, nil
+	}
+
+	encoded := *c.key
+	switch {
+	case *c.keyFile != "":
+		content, err := os.ReadFile(*c.keyFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		encoded = string(content)
+	case encoded == "":
+		encoded = os.Getenv(keyEnvVar)
+	}
+	if strings.TrimSpace(encoded) == "" {
+		return nil, nil, fmt.Errorf("nenhuma chave informada: use -key, -key-file ou $%s (gere uma com locenv encrypt -generate-key)", keyEnvVar)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, nil, fmt.Errorf("chave inválida: %w", err)
+	}
+	local, err := config.NewLocalKeyDecrypter(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("chave inválida: %w", err)
+	}
+
+	return local, local, nil
+}
+
+/*
+runEncrypt executa o subcomando encrypt, que cifra um valor avulso ou os segredos de arquivos .env
+
+Com -value, o valor cifrado é escrito na saída padrão ("-" lê o valor da entrada padrão). Com arquivos, os valores
+das variáveis cujos nomes correspondem a -keys são cifrados no próprio arquivo, preservando os comentários;
+valores já cifrados e vazios não são alterados.
+
+@param args []string - Os argumentos do subcomando
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - 0 em caso de sucesso, 1 se a cifra falhar, 2 em caso de erro de uso
+*/
+func runEncrypt(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("encrypt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	cf := newCipherFlags(flags)
+	value := flags.String("value", "", "valor avulso a cifrar (\"-\" lê da entrada padrão)")
+	keys := flags.String("keys", strings.Join(config.DefaultSecretPatterns, ","), "padrões de nomes das variáveis a cifrar nos arquivos, separados por vírgula")
+	generate := flags.Bool("generate-key", false, "gera uma nova chave AES-256 para o backend local e a escreve na saída padrão")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *generate {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			fmt.Fprintf(stderr, "locenv: %s\n", err)
+			return 1
+		}
+		fmt.Fprintln(stdout, base64.StdEncoding.EncodeToString(key))
+		return 0
+	}
+
+	encrypter, _, err := cf.cipher()
+	if err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 2
+	}
+
+	patterns := splitList(*keys)
+	transform := func(key, current string) (string, bool, error) {
+		if current == "" || config.IsEncrypted(current) || !matchesAny(key, patterns) {
+			return "", false, nil
+		}
+		encrypted, err := config.EncryptValue(*cf.backend, encrypter, current)
+		return encrypted, err == nil, err
+	}
+
+	return transformValues("cifrado", *value, flags.Args(), transform, stdout, stderr)
+}
+
+/*
+runDecrypt executa o subcomando decrypt, que decifra um valor avulso ou os valores cifrados de arquivos .env
+
+Com -value, o valor em claro é escrito na saída padrão ("-" lê o valor da entrada padrão). Com arquivos, os valores
+"enc:<backend>:" do backend escolhido são decifrados no próprio arquivo, preservando os comentários; valores de
+outros backends não são alterados.
+
+@param args []string - Os argumentos do subcomando
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - 0 em caso de sucesso, 1 se a decifragem falhar, 2 em caso de erro de uso
+*/
+func runDecrypt(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("decrypt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	cf := newCipherFlags(flags)
+	value := flags.String("value", "", "valor avulso a decifrar (\"-\" lê da entrada padrão)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	_, decrypter, err := cf.cipher()
+	if err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 2
+	}
+
+	marker := "enc:" + *cf.backend + ":"
+	transform := func(key, current string) (string, bool, error) {
+		if !strings.HasPrefix(current, marker) {
+			return "", false, nil
+		}
+		plaintext, err := config.DecryptValue(current, *cf.backend, decrypter)
+		return plaintext, err == nil, err
+	}
+
+	return transformValues("decifrado", *value, flags.Args(), transform, stdout, stderr)
+}
+
+/*
+transformValues aplica uma transformação a um valor avulso ou aos valores de arquivos .env
+
+@param verb string - O particípio usado no relatório (cifrado ou decifrado)
+@param value string - O valor avulso, "-" para a entrada padrão, ou uma string vazia para usar os arquivos
+@param files []string - Os arquivos .env a reescrever
+@param transform func(key, value string) (string, bool, error) - A transformação, no formato de config.RewriteFile
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - O código de saída
+*/
+func transformValues(verb string, value string, files []string, transform func(key, value string) (string, bool, error), stdout io.Writer, stderr io.Writer) int {
+	if value != "" {
+		if value == "-" {
+			content, err := io.ReadAll(stdin)
+			if err != nil {
+				fmt.Fprintf(stderr, "locenv: %s\n", err)
+				return 2
+			}
+			value = strings.TrimRight(string(content), "\r\n")
+		}
+
+		result, changed, err := transform("", value)
+		if err == nil && !changed {
+			err = fmt.Errorf("o valor não pode ser %s com o backend informado", verb)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "locenv: %s\n", err)
+			return 1
+		}
+		fmt.Fprintln(stdout, result)
+		return 0
+	}
+
+	if len(files) == 0 {
+		fmt.Fprintln(stderr, "locenv: informe -value ou ao menos um arquivo .env")
+		return 2
+	}
+
+	for _, file := range files {
+		n, err := config.RewriteFile(file, transform)
+		if err != nil {
+			fmt.Fprintf(stderr, "locenv: %s\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "✔ %s: %d valor(es) %s(s)\n", file, n, verb)
+	}
+
+	return 0
+}
+
+/*
+matchesAny informa se um nome corresponde a algum dos padrões, na sintaxe de path.Match
+
+Um nome vazio, usado para valores avulsos, corresponde a qualquer lista de padrões.
+
+@param key string - O nome da variável
+@param patterns []string - Os padrões
+
+@return bool - Se algum padrão corresponde
+*/
+func matchesAny(key string, patterns []string) bool {
+	if key == "" {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+runDoctor executa o subcomando doctor, que produz um relatório único sobre a configuração do ambiente
+
+O relatório mostra o valor de APP_ENV, os arquivos candidatos e qual seria selecionado, os erros de sintaxe,
+as variáveis obrigatórias ausentes (declaradas no esquema .env.example ou em -require), os valores que não
+respeitam o @type do esquema, os problemas de permissão e os arquivos com segredos em texto claro expostos ao git.
+Com -output json, o relatório segue o esquema de report, com o diagnóstico em data.
+
+@param args []string - Os argumentos do subcomando
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - 0 se nenhum problema for encontrado, 1 se houver problemas, 2 em caso de erro
+*/
+func runDoctor(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dir := flags.String("dir", ".", "diretório raiz da verificação do git")
+	secretKeys := flags.String("secret-keys", strings.Join(config.DefaultSecretPatterns, ","), "padrões de nomes de variáveis secretas, separados por vírgula")
+	schema := flags.String("schema", ".env.example", "esquema anotado com as variáveis obrigatórias, secretas e tipadas (ignorado se não existir)")
+	require := flags.String("require", "", "variáveis obrigatórias adicionais, separadas por vírgula")
+	format := outputFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if err := applyProjectConfig(flags); err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 2
+	}
+	structured, ok := structuredOutput(*format, stderr)
+	if !ok {
+		return 2
+	}
+
+	var specs []config.KeySpec
+	if _, err := os.Stat(*schema); err == nil {
+		specs, err = config.LoadSchema(*schema)
+		if err != nil {
+			fmt.Fprintf(stderr, "erro ao ler o esquema %s: %s\n", *schema, err)
+			return 2
+		}
+	}
+
+	patterns := splitList(*secretKeys)
+	diagnosis := config.Diagnose(config.WithSilent(), config.WithSecretKeys(patterns...), config.WithRequired(splitList(*require)...), config.WithSchema(specs), config.WithGitSafetyCheck())
+
+	risks, err := config.ScanGitRisks(*dir, patterns)
+	if err != nil {
+		fmt.Fprintf(stderr, "erro ao verificar o repositório: %s\n", err)
+		return 2
+	}
+
+	if structured {
+		return diagnosisReport(diagnosis, risks).write(stdout, stderr, *format)
+	}
+	printDiagnosis(stdout, diagnosis, risks)
+
+	if !diagnosis.OK() || len(risks) > 0 {
+		return 1
+	}
+	return 0
+}
+
+/*
+printDiagnosis escreve o relatório do subcomando doctor
+
+@param w io.Writer - A saída em que o relatório é escrito
+@param d *config.Diagnosis - O diagnóstico do carregador
+@param risks []config.GitRisk - Os riscos encontrados no repositório
+*/
+func printDiagnosis(w io.Writer, d *config.Diagnosis, risks []config.GitRisk) {
+	fmt.Fprintf(w, "APP_ENV: %q\n", d.AppEnv)
+
+	fmt.Fprintln(w, "\nCandidatos:")
+	if len(d.Candidates) == 0 {
+		fmt.Fprintln(w, "  (nenhum arquivo .env.<ambiente> encontrado)")
+	}
+	for _, c := range d.Candidates {
+		mark := "·"
+		if c.Selected {
+			mark = "✔"
+		}
+		fmt.Fprintf(w, "  %s %s — %s\n", mark, c.Path, c.Reason)
+	}
+
+	if len(d.Files) > 0 {
+		fmt.Fprintln(w, "\nArquivos carregados, em ordem:")
+		for _, file := range d.Files {
+			fmt.Fprintf(w, "  %s\n", file)
+		}
+	}
+
+	for _, risk := range risks {
+		d.Problems = append(d.Problems, risk.String())
+	}
+
+	fmt.Fprintln(w, "\nProblemas:")
+	if len(d.Problems) == 0 {
+		fmt.Fprintln(w, "  ✔ nenhum problema encontrado")
+	}
+	for _, problem := range d.Problems {
+		fmt.Fprintf(w, "  ✘ %s\n", problem)
+	}
+
+	if len(d.Warnings) > 0 {
+		fmt.Fprintln(w, "\nAvisos:")
+		for _, warning := range d.Warnings {
+			fmt.Fprintf(w, "  ! %s\n", warning)
+		}
+	}
+}
+
+/*
+diagnosisReport converte o diagnóstico do subcomando doctor para o esquema de -output json
+
+@param d *config.Diagnosis - O diagnóstico do carregador
+@param risks []config.GitRisk - Os riscos encontrados no repositório
+
+@return *report - O relatório, com o ambiente, os candidatos, os arquivos e os riscos em data
+*/
+func diagnosisReport(d *config.Diagnosis, risks []config.GitRisk) *report {
+	r := newReport("doctor")
+	for _, problem := range d.Problems {
+		r.add(severityError, problem)
+	}
+	for _, risk := range risks {
+		r.add(severityError, risk.String()).File = risk.File
+	}
+	for _, warning := range d.Warnings {
+		r.add(severityWarning, warning)
+	}
+
+	if risks == nil {
+		risks = []config.GitRisk{}
+	}
+	r.Data = struct {
+		AppEnv     string             `json:"app_env"`
+		Candidates []config.Candidate `json:"candidates"`
+		Files      []string           `json:"files"`
+		GitRisks   []config.GitRisk   `json:"git_risks"`
+	}{d.AppEnv, d.Candidates, d.Files, risks}
+
+	return r
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+runFmt executa o subcomando fmt, que normaliza o estilo de arquivos .env com config.FormatFile
+
+Sem argumentos, formata o .env e os arquivos .env.* do diretório atual. Com -check, nenhum arquivo é alterado:
+os arquivos fora do estilo canônico são listados e o código de saída é 1, para uso em hooks de pre-commit e na CI.
+Com -output json, cada arquivo fora do estilo é um problema, e os arquivos alterados ficam em data.
+
+@param args []string - Os argumentos do subcomando
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - 0 em caso de sucesso, 1 se algum arquivo não puder ser formatado ou, com -check, não estiver formatado, 2 em caso de erro de uso
+*/
+func runFmt(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	check := flags.Bool("check", false, "apenas lista os arquivos que não estão formatados, sem alterá-los")
+	format := outputFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	structured, ok := structuredOutput(*format, stderr)
+	if !ok {
+		return 2
+	}
+
+	files := flags.Args()
+	if len(files) == 0 {
+		files = envFilesInDir(".")
+	}
+
+	code := 0
+	r := newReport("fmt")
+	changedFiles := []string{}
+	fail := func(file string, err error) {
+		if structured {
+			r.add(severityError, err.Error()).File = file
+		} else {
+			fmt.Fprintf(stderr, "locenv: %s: %s\n", file, err)
+		}
+		code = 1
+	}
+	for _, file := range files {
+		if *check {
+			content, err := os.ReadFile(file)
+			if err == nil {
+				var formatted []byte
+				if formatted, err = config.Format(content); err == nil && string(formatted) != string(content) {
+					if structured {
+						r.add(severityError, "fora do estilo canônico: execute locenv fmt "+file).File = file
+					} else {
+						fmt.Fprintln(stdout, file)
+					}
+					code = 1
+				}
+			}
+			if err != nil {
+				fail(file, err)
+			}
+			continue
+		}
+
+		changed, err := config.FormatFile(file)
+		if err != nil {
+			fail(file, err)
+			continue
+		}
+		if changed {
+			changedFiles = append(changedFiles, file)
+			if !structured {
+				fmt.Fprintf(stdout, "✔ %s formatado\n", file)
+			}
+		}
+	}
+
+	if structured {
+		r.Data = struct {
+			Formatted []string `json:"formatted"`
+		}{changedFiles}
+		return r.write(stdout, stderr, *format)
+	}
+	return code
+}
+
+/*
+envFilesInDir lista o .env e os arquivos .env.* de um diretório
+
+@param dir string - O diretório
+
+@return []string - Os caminhos dos arquivos, em ordem alfabética
+*/
+func envFilesInDir(dir string) []string {
+	var files []string
+	for _, pattern := range []string{".env", ".env.*"} {
+		matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && info.Mode().IsRegular() {
+				files = append(files, match)
+			}
+		}
+	}
+
+	return files
+}
@@ -0,0 +1,398 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+// stdin é a entrada lida pelos subcomandos interativos.
+var stdin io.Reader = os.Stdin
+
+/*
+runInit executa o subcomando init, que cria os arquivos de ambiente de um novo projeto
+
+As variáveis são obtidas de uma struct Go com tags env (-struct arquivo.go ou arquivo.go:Tipo), de um esquema
+existente (-schema) ou, na falta de ambos, perguntadas ao usuário. Para cada ambiente de -envs, o valor de cada
+variável é perguntado, com o valor do ambiente anterior como sugestão. Em seguida, o subcomando cria os arquivos
+.env.<ambiente>, o .env.example com as chaves sem valores e o .locenv.yaml, e adiciona ao .gitignore os arquivos
+que declaram segredos. Os valores dos segredos são lidos sem exibir a digitação, e a sugestão do ambiente anterior
+aparece mascarada. Arquivos existentes só são sobrescritos com -force. Em um projeto que já tem o .locenv.yaml,
+os seus ambientes, esquema e padrões de segredo são os padrões de -envs, -schema e -secret-keys.
+
+@param args []string - Os argumentos do subcomando
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - 0 em caso de sucesso, 2 em caso de erro
+*/
+func runInit(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("init", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	structRef := flags.String("struct", "", "struct Go com tags env usada como fonte das variáveis (arquivo.go ou arquivo.go:Tipo)")
+	schema := flags.String("schema", "", "arquivo .env cujas chaves são usadas como fonte das variáveis")
+	envs := flags.String("envs", "development,test", "ambientes a criar, separados por vírgula")
+	secretKeys := flags.String("secret-keys", strings.Join(config.DefaultSecretPatterns, ","), "padrões de nomes de variáveis secretas, separados por vírgula")
+	yes := flags.Bool("yes", false, "não pergunta nada e deixa os valores vazios")
+	force := flags.Bool("force", false, "sobrescreve os arquivos existentes")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if err := applyProjectConfig(flags); err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 2
+	}
+
+	in := bufio.NewReader(stdin)
+	ask := func(question string, suggestion string, secret bool) (string, error) {
+		if *yes {
+			return suggestion, nil
+		}
+		switch {
+		case suggestion != "" && secret:
+			fmt.Fprintf(stdout, "%s [******]: ", question)
+		case suggestion != "":
+			fmt.Fprintf(stdout, "%s [%s]: ", question, suggestion)
+		default:
+			fmt.Fprintf(stdout, "%s: ", question)
+		}
+		if secret {
+			restore, err := hideInput(stdout)
+			if err != nil {
+				fmt.Fprintf(stderr, "locenv: não foi possível ocultar a digitação: %s\n", err)
+			} else {
+				defer restore()
+			}
+		}
+
+		answer, err := in.ReadString('\n')
+		if err != nil && (err != io.EOF || answer == "") {
+			return "", fmt.Errorf("entrada interrompida: %w", err)
+		}
+		if answer = strings.TrimRight(answer, "\r\n"); answer == "" {
+			return suggestion, nil
+		}
+		return answer, nil
+	}
+
+	keys, err := initKeys(*structRef, *schema)
+	if err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 2
+	}
+	if len(keys) == 0 {
+		answer, err := ask("Variáveis do projeto, separadas por vírgula", "", false)
+		if err != nil {
+			fmt.Fprintf(stderr, "locenv: %s\n", err)
+			return 2
+		}
+		keys = splitList(answer)
+	}
+
+	patterns := splitList(*secretKeys)
+	secrets := make(map[string]bool)
+	for _, key := range secretKeysIn(keys, patterns) {
+		secrets[key] = true
+	}
+	files := make(map[string]string)
+	var order, ignored []string
+
+	previous := make(map[string]string, len(keys))
+	for _, env := range splitList(*envs) {
+		values := make(map[string]string, len(keys))
+		for _, key := range keys {
+			value, err := ask(fmt.Sprintf("%s em %s", key, env), previous[key], secrets[key])
+			if err != nil {
+				fmt.Fprintf(stderr, "locenv: %s\n", err)
+				return 2
+			}
+			values[key] = value
+		}
+		previous = values
+
+		name := ".env." + env
+		files[name] = renderEnvFile(keys, values)
+		order = append(order, name)
+		if len(secrets) > 0 {
+			ignored = append(ignored, name)
+		}
+	}
+
+	files[".env.example"] = renderEnvFile(keys, nil)
+	files[projectConfigFile] = renderProjectConfig(splitList(*envs), patterns)
+	order = append(order, ".env.example", projectConfigFile)
+
+	for _, name := range order {
+		if _, err := os.Stat(name); err == nil && !*force {
+			fmt.Fprintf(stdout, "· %s já existe, mantido (use -force para sobrescrever)\n", name)
+			continue
+		}
+		if err := os.WriteFile(name, []byte(files[name]), 0o600); err != nil {
+			fmt.Fprintf(stderr, "locenv: %s\n", err)
+			return 2
+		}
+		fmt.Fprintf(stdout, "✔ %s criado\n", name)
+	}
+
+	added, err := appendGitignore(".gitignore", ignored)
+	if err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 2
+	}
+	for _, name := range added {
+		fmt.Fprintf(stdout, "✔ %s adicionado ao .gitignore\n", name)
+	}
+
+	return 0
+}
+
+/*
+hideInput desativa a exibição da digitação no terminal, para que os segredos não apareçam na tela
+
+Quando a entrada não é um terminal, como em um pipe ou nos testes, nada é alterado.
+
+@param stdout io.Writer - A saída padrão, que recebe a quebra de linha que a digitação oculta não exibiu
+
+@return func() - Restaura a exibição da digitação
+@return error - Um erro se o modo do terminal não puder ser alterado, como em sistemas sem stty
+*/
+func hideInput(stdout io.Writer) (func(), error) {
+	file, ok := stdin.(*os.File)
+	if !ok {
+		return func() {}, nil
+	}
+	if info, err := file.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return func() {}, nil
+	}
+
+	stty := func(mode string) error {
+		cmd := exec.Command("stty", mode)
+		cmd.Stdin = file
+		return cmd.Run()
+	}
+	if err := stty("-echo"); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		stty("echo")
+		fmt.Fprintln(stdout)
+	}, nil
+}
+
+/*
+initKeys reúne as variáveis declaradas na struct e no esquema informados, sem repetições
+
+@param structRef string - A referência à struct (arquivo.go ou arquivo.go:Tipo), ou uma string vazia
+@param schema string - O caminho do esquema, ou uma string vazia
+
+@return []string - As variáveis, na ordem em que foram declaradas
+@return error - Um erro se a struct ou o esquema não puderem ser lidos
+*/
+func initKeys(structRef string, schema string) ([]string, error) {
+	var keys []string
+	if structRef != "" {
+		file, typeName, _ := strings.Cut(structRef, ":")
+		structKeys, err := structEnvKeys(file, typeName)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, structKeys...)
+	}
+
+	if schema != "" {
+		schemaKeys, err := config.ExampleKeys(schema)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, schemaKeys...)
+	}
+
+	seen := make(map[string]bool, len(keys))
+	unique := keys[:0]
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, key)
+		}
+	}
+
+	return unique, nil
+}
+
+/*
+structEnvKeys lê as variáveis declaradas pelas tags env das structs de um arquivo Go
+
+A tag segue a convenção env:"NOME,opções"; apenas o nome é usado. Campos sem a tag ou com env:"-" são ignorados.
+
+@param file string - O caminho do arquivo Go
+@param typeName string - O nome da struct, ou uma string vazia para considerar todas as structs do arquivo
+
+@return []string - As variáveis, na ordem em que foram declaradas
+@return error - Um erro se o arquivo não puder ser lido ou a struct não existir
+*/
+func structEnvKeys(file string, typeName string) ([]string, error) {
+	parsed, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	found := false
+	ast.Inspect(parsed, func(node ast.Node) bool {
+		spec, ok := node.(*ast.TypeSpec)
+		if !ok || (typeName != "" && spec.Name.Name != typeName) {
+			return true
+		}
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return true
+		}
+
+		found = true
+		for _, field := range st.Fields.List {
+			if field.Tag == nil {
+				continue
+			}
+			tag, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				continue
+			}
+			name, _, _ := strings.Cut(reflect.StructTag(tag).Get("env"), ",")
+			if name != "" && name != "-" {
+				keys = append(keys, name)
+			}
+		}
+		return true
+	})
+
+	if typeName != "" && !found {
+		return nil, fmt.Errorf("struct %s não encontrada em %s", typeName, file)
+	}
+
+	return keys, nil
+}
+
+/*
+renderEnvFile gera o conteúdo de um arquivo .env com as variáveis informadas
+
+@param keys []string - As variáveis, na ordem em que devem ser escritas
+@param values map[string]string - Os valores, ou nil para escrever as chaves sem valores
+
+@return string - O conteúdo do arquivo
+*/
+func renderEnvFile(keys []string, values map[string]string) string {
+	var b strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s=%s\n", key, config.QuoteValue(values[key]))
+	}
+
+	return b.String()
+}
+
+/*
+renderProjectConfig gera o conteúdo do .locenv.yaml
+
+@param envs []string - Os ambientes do projeto
+@param patterns []string - Os padrões de nomes de variáveis secretas
+
+@return string - O conteúdo do arquivo
+*/
+func renderProjectConfig(envs []string, patterns []string) string {
+	var b strings.Builder
+	b.WriteString("# Configuração do locenv para este projeto.\n")
+	b.WriteString("schema: .env.example\n")
+	b.WriteString("environments:\n")
+	for _, env := range envs {
+		fmt.Fprintf(&b, "  - %s\n", env)
+	}
+	b.WriteString("secret_keys:\n")
+	for _, pattern := range patterns {
+		fmt.Fprintf(&b, "  - %q\n", pattern)
+	}
+
+	return b.String()
+}
+
+/*
+secretKeysIn retorna as variáveis cujos nomes correspondem a algum dos padrões de segredo
+
+@param keys []string - As variáveis
+@param patterns []string - Os padrões, na sintaxe de path.Match
+
+@return []string - As variáveis secretas
+*/
+func secretKeysIn(keys []string, patterns []string) []string {
+	var secrets []string
+	for _, key := range keys {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, key); ok {
+				secrets = append(secrets, key)
+				break
+			}
+		}
+	}
+
+	return secrets
+}
+
+/*
+appendGitignore adiciona ao .gitignore as entradas que ainda não estão presentes
+
+@param file string - O caminho do .gitignore, criado se não existir
+@param entries []string - As entradas a adicionar
+
+@return []string - As entradas efetivamente adicionadas
+@return error - Um erro se o arquivo não puder ser lido ou escrito
+*/
+func appendGitignore(file string, entries []string) ([]string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	present := make(map[string]bool)
+	for _, line := range strings.Split(string(content), "\n") {
+		present[strings.TrimPrefix(strings.TrimSpace(line), "/")] = true
+	}
+
+	var added []string
+	var b strings.Builder
+	for _, entry := range entries {
+		if !present[entry] {
+			present[entry] = true
+			added = append(added, entry)
+			fmt.Fprintln(&b, entry)
+		}
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		f.WriteString("\n")
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		return nil, err
+	}
+
+	return added, nil
+}
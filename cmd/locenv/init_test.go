@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+/*
+chdirTemp muda o diretório de trabalho para um diretório temporário, restaurando o original ao fim do teste
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+
+@return string - O diretório temporário
+*/
+func chdirTemp(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Não foi possível ler o diretório de trabalho: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Não foi possível alterar o diretório de trabalho: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	return dir
+}
+
+/*
+TestInitCreatesProject verifica se init cria os arquivos de cada ambiente, o .env.example, o .locenv.yaml e o
+.gitignore, e se o valor de um segredo não é exibido como sugestão no ambiente seguinte.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestInitCreatesProject(t *testing.T) {
+	dir := chdirTemp(t)
+	stdin = strings.NewReader("APP_NAME,DB_PASSWORD\napp\ns3cr3t\n\n\n")
+	t.Cleanup(func() { stdin = os.Stdin })
+
+	var stdout, stderr bytes.Buffer
+	if code := runInit([]string{"-envs", "dev,prod"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("init retornou %d: %s", code, stderr.String())
+	}
+	if strings.Contains(stdout.String(), "s3cr3t") || !strings.Contains(stdout.String(), "DB_PASSWORD em prod [******]") {
+		t.Errorf("A sugestão do segredo deveria aparecer mascarada:\n%s", stdout.String())
+	}
+
+	want := map[string]string{
+		".env.dev":     "APP_NAME=app\nDB_PASSWORD=s3cr3t\n",
+		".env.prod":    "APP_NAME=app\nDB_PASSWORD=s3cr3t\n",
+		".env.example": "APP_NAME=\nDB_PASSWORD=\n",
+		".gitignore":   ".env.dev\n.env.prod\n",
+	}
+	for name, content := range want {
+		if got, err := os.ReadFile(filepath.Join(dir, name)); err != nil || string(got) != content {
+			t.Errorf("Conteúdo inesperado de %s: %q (%v)", name, got, err)
+		}
+	}
+
+	cfg, err := findProjectConfig()
+	if err != nil || cfg == nil {
+		t.Fatalf("Esperava o .locenv.yaml criado por init, obteve %v (%v)", cfg, err)
+	}
+	if cfg.Schema != ".env.example" || !reflect.DeepEqual(cfg.Environments, []string{"dev", "prod"}) || len(cfg.SecretKeys) == 0 {
+		t.Errorf("Configuração do projeto inesperada: %+v", cfg)
+	}
+}
+
+/*
+TestApplyProjectConfig verifica se o .locenv.yaml fornece os padrões das opções não informadas, com o esquema
+relativo ao diretório do arquivo, e se rejeita chaves desconhecidas.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestApplyProjectConfig(t *testing.T) {
+	dir := chdirTemp(t)
+	content := "schema: config/.env.schema # esquema\nenvironments: [dev, 'prod']\nsecret_keys:\n  - \"*_TOKEN\"\n  - API_KEY\n"
+	if err := os.WriteFile(filepath.Join(dir, projectConfigFile), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join(dir, "sub")); err != nil {
+		t.Fatal(err)
+	}
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	schema := flags.String("schema", ".env.example", "")
+	secretKeys := flags.String("secret-keys", "*_PASSWORD", "")
+	envs := flags.String("envs", "development", "")
+	if err := flags.Parse([]string{"-envs", "qa"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyProjectConfig(flags); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if *schema != filepath.Join("..", "config", ".env.schema") || *secretKeys != "*_TOKEN,API_KEY" || *envs != "qa" {
+		t.Errorf("Padrões inesperados: schema=%s secret-keys=%s envs=%s", *schema, *secretKeys, *envs)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, projectConfigFile), []byte("schema: a\nsecrets: [b]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyProjectConfig(flags); err == nil || !strings.Contains(err.Error(), "linha 2") {
+		t.Errorf("Esperava um erro para a chave desconhecida, obteve %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+/*
+locenv é a ferramenta de linha de comando da biblioteca go-locEnv
+
+Uso:
+
+	locenv <comando> [opções]
+
+Comandos:
+
+	init         Cria os arquivos de ambiente, o esquema e o .locenv.yaml de um novo projeto
+	migrate      Converte config.yaml, settings.toml ou um .env monolítico para arquivos .env.<ambiente>
+	encrypt      Cifra um valor avulso ou os segredos de arquivos .env, preservando os comentários
+	decrypt      Decifra um valor avulso ou os valores cifrados de arquivos .env
+	fmt          Normaliza o estilo de arquivos .env, preservando os comentários e o agrupamento
+	doctor       Diagnostica a configuração do ambiente e relata problemas
+	validate     Valida o ambiente atual ou, com -all, todos os ambientes, em uma matriz de variáveis
+	scan         Procura valores com cara de credenciais reais nos arquivos .env versionados pelo git
+	placeholders Lista os valores de exemplo, como <YOUR_KEY_HERE> e changeme, e pergunta os valores reais com -fill
+	sync         Compara o arquivo .env local com uma loja remota, como o Vault, e sincroniza com -pull ou -push
+	capture      Registra a configuração efetiva, com os segredos mascarados, para anexar a um relato de problema
+	render       Gera a configuração resolvida de todos os ambientes, como dotenv, Kubernetes ou values do Helm, para um repositório GitOps
+	allow        Autoriza os arquivos .env do diretório atual a serem carregados pelo hook do shell
+	deny         Remove a autorização dos arquivos .env do diretório atual
+	export       Escreve os comandos de shell que carregam o perfil do diretório atual
+	hook         Escreve o script que carrega o perfil ao entrar em um diretório do projeto, ou instala o hook de pre-commit
+	completion   Escreve o script de autocompletar para bash, zsh, fish ou powershell
+
+O .locenv.yaml criado por init, procurado no diretório atual e nos seus ancestrais, define os padrões de -schema,
+-secret-keys e init -envs, de modo que o projeto não precisa repeti-los em cada chamada; as opções informadas na
+linha de comando prevalecem.
+
+Para carregar o perfil automaticamente, avalie o hook na inicialização do shell:
+
+	eval "$(locenv hook bash)"          # ~/.bashrc
+	eval "$(locenv hook zsh)"           # ~/.zshrc
+	locenv hook fish | source           # ~/.config/fish/config.fish
+	locenv hook powershell | Out-String | Invoke-Expression  # $PROFILE
+
+O hook só carrega os arquivos autorizados, e um arquivo alterado precisa ser autorizado de novo depois de revisado:
+
+	locenv allow
+	locenv allow -keys PATH   # autoriza também variáveis sensíveis, como PATH e LD_LIBRARY_PATH
+
+Para verificar os arquivos .env antes de cada commit, instale o hook de pre-commit do git:
+
+	locenv hook install
+
+Os subcomandos de verificação (fmt, doctor, validate, scan, placeholders, sync e hook pre-commit) aceitam
+-output json, que escreve um relatório com um esquema estável para a CI e os bots que anotam pull requests:
+
+	{"version": 1, "command": "doctor", "ok": false, "problems": [{"severity": "error", "message": "...", "file": ".env", "line": 3}], "data": {...}}
+
+Com -output github, os problemas viram comandos de workflow do GitHub Actions (::error file=.env,line=3::...), e
+com -output gitlab, um relatório de Code Quality do GitLab, para que apareçam junto ao trecho na revisão:
+
+	locenv validate -all -output github                        # passo de um workflow do GitHub Actions
+	locenv validate -all -output gitlab > gl-code-quality.json  # job do GitLab CI, com artifacts:reports:codequality
+*/
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+/*
+command descreve um subcomando da ferramenta locenv
+
+name string - O nome usado na linha de comando
+summary string - A descrição exibida na ajuda
+run func(args []string, stdout io.Writer, stderr io.Writer) int - A função que executa o subcomando e retorna o código de saída
+*/
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout io.Writer, stderr io.Writer) int
+}
+
+// commands são os subcomandos disponíveis, na ordem exibida pela ajuda.
+var commands []command
+
+// init preenche commands fora da declaração porque completion lê a própria lista de subcomandos.
+func init() {
+	commands = []command{
+		{name: "init", summary: "Cria os arquivos de ambiente, o esquema e o .locenv.yaml de um novo projeto", run: runInit},
+		{name: "migrate", summary: "Converte config.yaml, settings.toml ou um .env monolítico para arquivos .env.<ambiente>", run: runMigrate},
+		{name: "encrypt", summary: "Cifra um valor avulso ou os segredos de arquivos .env, preservando os comentários", run: runEncrypt},
+		{name: "decrypt", summary: "Decifra um valor avulso ou os valores cifrados de arquivos .env", run: runDecrypt},
+		{name: "fmt", summary: "Normaliza o estilo de arquivos .env, preservando os comentários e o agrupamento", run: runFmt},
+		{name: "doctor", summary: "Diagnostica a configuração do ambiente e relata problemas", run: runDoctor},
+		{name: "validate", summary: "Valida o ambiente atual ou, com -all, todos os ambientes, em uma matriz de variáveis", run: runValidate},
+		{name: "scan", summary: "Procura valores com cara de credenciais reais nos arquivos .env versionados pelo git", run: runScan},
+		{name: "placeholders", summary: "Lista os valores de exemplo, como <YOUR_KEY_HERE> e changeme, e pergunta os valores reais com -fill", run: runPlaceholders},
+		{name: "sync", summary: "Compara o arquivo .env local com uma loja remota, como o Vault, e sincroniza com -pull ou -push", run: runSync},
+		{name: "capture", summary: "Registra a configuração efetiva, com os segredos mascarados, para anexar a um relato de problema", run: runCapture},
+		{name: "render", summary: "Gera a configuração resolvida de todos os ambientes, como dotenv, Kubernetes ou values do Helm, para um repositório GitOps", run: runRender},
+		{name: "allow", summary: "Autoriza os arquivos .env do diretório atual a serem carregados pelo hook do shell", run: runAllow},
+		{name: "deny", summary: "Remove a autorização dos arquivos .env do diretório atual", run: runDeny},
+		{name: "export", summary: "Escreve os comandos de shell que carregam o perfil do diretório atual", run: runExport},
+		{name: "hook", summary: "Escreve o script que carrega o perfil ao entrar em um diretório do projeto, ou instala o hook de pre-commit", run: runHook},
+		{name: "completion", summary: "Escreve o script de autocompletar para bash, zsh, fish ou powershell", run: runCompletion},
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+/*
+run seleciona e executa o subcomando informado
+
+@param args []string - Os argumentos da linha de comando, sem o nome do programa
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - O código de saída do processo
+*/
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stdout)
+		return 0
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:], stdout, stderr)
+		}
+	}
+
+	fmt.Fprintf(stderr, "comando desconhecido: %s\n\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+/*
+usage exibe a ajuda com a lista de subcomandos
+
+@param w io.Writer - A saída em que a ajuda é escrita
+*/
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Uso: locenv <comando> [opções]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Comandos:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-12s %s\n", cmd.name, cmd.summary)
+	}
+}
+
+/*
+splitList separa uma lista de valores separados por vírgula, descartando os itens vazios
+
+@param value string - A lista
+
+@return []string - Os itens da lista
+*/
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
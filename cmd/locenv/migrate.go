@@ -0,0 +1,660 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+// migrateSources são os arquivos procurados por locenv migrate quando -from não é informado, em ordem de prioridade.
+var migrateSources = []string{"config.yaml", "config.yml", "config.toml", "settings.yaml", "settings.yml", "settings.toml", ".env"}
+
+// envSectionHeader reconhece os cabeçalhos de seção de um .env monolítico, como "# [production]".
+var envSectionHeader = regexp.MustCompile(`^#\s*\[([A-Za-z0-9_.-]+)\]\s*$`)
+
+/*
+migrateEntry é uma variável convertida de um layout de configuração
+
+key string - O nome da variável, já achatado e em maiúsculas
+value string - O valor da variável
+comments []string - Os comentários que precediam a declaração original
+raw string - Um trecho de .env copiado literalmente, usado no lugar de key e value quando não está vazio
+*/
+type migrateEntry struct {
+	key      string
+	value    string
+	comments []string
+	raw      string
+}
+
+/*
+migrateLayout é o resultado da leitura de um arquivo de configuração
+
+common []migrateEntry - As variáveis comuns a todos os ambientes
+sections map[string][]migrateEntry - As variáveis específicas de cada ambiente, que sobrepõem as comuns
+*/
+type migrateLayout struct {
+	common   []migrateEntry
+	sections map[string][]migrateEntry
+}
+
+/*
+runMigrate executa o subcomando migrate, que converte um layout de configuração existente para arquivos .env.<ambiente>
+
+São aceitos arquivos YAML e TOML no estilo do viper (config.yaml, settings.toml, config.production.yaml) e um .env
+monolítico. As chaves aninhadas são achatadas com _ e convertidas para maiúsculas, como em
+viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_")), e as listas viram valores separados por vírgula.
+As seções de primeiro nível cujo nome está em -envs (ou, em um .env, os blocos iniciados por "# [ambiente]")
+geram um arquivo por ambiente; as demais chaves são comuns e entram em todos os arquivos. Sem seções, todas as
+variáveis vão para o ambiente de -env, ou para o ambiente presente no nome do arquivo (config.production.yaml).
+Os comentários são preservados sempre que precedem uma chave.
+
+@param args []string - Os argumentos do subcomando
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - 0 em caso de sucesso, 2 em caso de erro
+*/
+func runMigrate(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	from := flags.String("from", "", "arquivo a converter (padrão: o primeiro de "+strings.Join(migrateSources, ", ")+" que existir)")
+	envs := flags.String("envs", "development,dev,test,testing,staging,stage,production,prod", "nomes de seções tratadas como ambientes, separados por vírgula")
+	defaultEnv := flags.String("env", "development", "ambiente usado quando o arquivo não tem seções de ambiente")
+	dryRun := flags.Bool("dry-run", false, "escreve os arquivos gerados na saída padrão em vez de criá-los")
+	force := flags.Bool("force", false, "sobrescreve os arquivos existentes")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	source := *from
+	if source == "" {
+		for _, candidate := range migrateSources {
+			if _, err := os.Stat(candidate); err == nil {
+				source = candidate
+				break
+			}
+		}
+		if source == "" {
+			fmt.Fprintf(stderr, "locenv: nenhum arquivo de configuração encontrado; use -from\n")
+			return 2
+		}
+	}
+
+	envNames := make(map[string]bool)
+	for _, env := range splitList(*envs) {
+		envNames[strings.ToLower(env)] = true
+	}
+
+	file, err := os.Open(source)
+	if err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 2
+	}
+	defer file.Close()
+
+	var layout *migrateLayout
+	switch ext := strings.ToLower(filepath.Ext(source)); {
+	case ext == ".yaml" || ext == ".yml":
+		layout, err = parseYAMLLayout(file, envNames)
+	case ext == ".toml":
+		layout, err = parseTOMLLayout(file, envNames)
+	case strings.HasPrefix(filepath.Base(source), ".env"):
+		layout, err = parseEnvLayout(file)
+	default:
+		err = fmt.Errorf("formato não suportado: %s", source)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "locenv: %s: %s\n", source, err)
+		return 2
+	}
+
+	if len(layout.sections) == 0 {
+		env := *defaultEnv
+		if named := envFromFileName(source, envNames); named != "" {
+			env = named
+		}
+		layout.sections = map[string][]migrateEntry{env: nil}
+	}
+
+	envList := make([]string, 0, len(layout.sections))
+	for env := range layout.sections {
+		envList = append(envList, env)
+	}
+	sort.Strings(envList)
+
+	for _, env := range envList {
+		name := ".env." + env
+		content := renderMigrated(source, mergeEntries(layout.common, layout.sections[env]))
+
+		if *dryRun {
+			fmt.Fprintf(stdout, "# ---- %s ----\n%s\n", name, content)
+			continue
+		}
+		if _, err := os.Stat(name); err == nil && !*force {
+			fmt.Fprintf(stdout, "· %s já existe, mantido (use -force para sobrescrever)\n", name)
+			continue
+		}
+		if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+			fmt.Fprintf(stderr, "locenv: %s\n", err)
+			return 2
+		}
+		fmt.Fprintf(stdout, "✔ %s criado a partir de %s\n", name, source)
+	}
+
+	return 0
+}
+
+/*
+envFromFileName extrai o ambiente de nomes como config.production.yaml
+
+@param source string - O caminho do arquivo
+@param envNames map[string]bool - Os nomes reconhecidos como ambientes
+
+@return string - O ambiente, ou uma string vazia se o nome não contiver um ambiente conhecido
+*/
+func envFromFileName(source string, envNames map[string]bool) string {
+	base := strings.TrimSuffix(filepath.Base(source), filepath.Ext(source))
+	if i := strings.LastIndexByte(base, '.'); i >= 0 && envNames[strings.ToLower(base[i+1:])] {
+		return strings.ToLower(base[i+1:])
+	}
+
+	return ""
+}
+
+/*
+mergeEntries combina as variáveis comuns com as de um ambiente, em que as do ambiente prevalecem
+
+A variável sobreposta mantém a posição da declaração comum, e os comentários das duas declarações são preservados.
+
+@param common []migrateEntry - As variáveis comuns
+@param section []migrateEntry - As variáveis do ambiente
+
+@return []migrateEntry - As variáveis resultantes
+*/
+func mergeEntries(common []migrateEntry, section []migrateEntry) []migrateEntry {
+	merged := append([]migrateEntry(nil), common...)
+	index := make(map[string]int, len(merged))
+	for i, e := range merged {
+		if e.raw == "" {
+			index[e.key] = i
+		}
+	}
+
+	for _, e := range section {
+		if i, ok := index[e.key]; ok && e.raw == "" {
+			merged[i].value = e.value
+			merged[i].comments = append(append([]string(nil), merged[i].comments...), e.comments...)
+			continue
+		}
+		if e.raw == "" {
+			index[e.key] = len(merged)
+		}
+		merged = append(merged, e)
+	}
+
+	return merged
+}
+
+/*
+renderMigrated gera o conteúdo de um arquivo .env a partir das variáveis convertidas
+
+@param source string - O arquivo de origem, citado no cabeçalho
+@param entries []migrateEntry - As variáveis
+
+@return string - O conteúdo do arquivo
+*/
+func renderMigrated(source string, entries []migrateEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Gerado por locenv migrate a partir de %s\n", filepath.Base(source))
+	for _, e := range entries {
+		if e.raw != "" {
+			b.WriteString(e.raw)
+			continue
+		}
+		for _, comment := range e.comments {
+			fmt.Fprintf(&b, "# %s\n", comment)
+		}
+		fmt.Fprintf(&b, "%s=%s\n", e.key, config.QuoteValue(e.value))
+	}
+
+	return b.String()
+}
+
+/*
+layoutBuilder distribui as variáveis lidas entre as seções comuns e de ambiente
+
+envNames map[string]bool - Os nomes de seções tratados como ambientes
+layout *migrateLayout - O resultado em construção
+pending []string - Os comentários lidos que ainda não foram associados a uma variável
+*/
+type layoutBuilder struct {
+	envNames map[string]bool
+	layout   *migrateLayout
+	pending  []string
+}
+
+func newLayoutBuilder(envNames map[string]bool) *layoutBuilder {
+	return &layoutBuilder{envNames: envNames, layout: &migrateLayout{sections: make(map[string][]migrateEntry)}}
+}
+
+// comment guarda um comentário para a próxima variável.
+func (b *layoutBuilder) comment(text string) {
+	b.pending = append(b.pending, strings.TrimSpace(text))
+}
+
+// add registra uma variável pelo caminho completo, separando a seção de ambiente quando houver.
+func (b *layoutBuilder) add(path []string, value string) {
+	entry := migrateEntry{value: value, comments: b.pending}
+	b.pending = nil
+
+	if len(path) > 1 && b.envNames[strings.ToLower(path[0])] {
+		env := strings.ToLower(path[0])
+		entry.key = config.FlattenKey(path[1:]...)
+		b.layout.sections[env] = append(b.layout.sections[env], entry)
+		return
+	}
+
+	entry.key = config.FlattenKey(path...)
+	b.layout.common = append(b.layout.common, entry)
+}
+
+/*
+parseYAMLLayout lê o subconjunto de YAML usado em arquivos de configuração
+
+São suportados mapas aninhados por indentação, escalares com ou sem aspas, listas em bloco (- item) e em linha
+([a, b]) e comentários. Âncoras, blocos literais (| e >) e mapas em linha resultam em erro com o número da linha.
+
+@param r io.Reader - O conteúdo YAML
+@param envNames map[string]bool - Os nomes de seções tratados como ambientes
+
+@return *migrateLayout - As variáveis lidas
+@return error - Um erro se o conteúdo usar construções não suportadas
+*/
+func parseYAMLLayout(r io.Reader, envNames map[string]bool) (*migrateLayout, error) {
+	b := newLayoutBuilder(envNames)
+
+	type level struct {
+		indent int
+		key    string
+	}
+	var stack []level
+	// open é a chave sem valor mais recente, que pode abrir um mapa aninhado, uma lista em bloco ou ser nula.
+	var open []string
+	var openIndent int
+	var listItems []string
+
+	// closeOpen registra a chave aberta como lista ou valor nulo, a menos que a linha atual seja filha dela.
+	closeOpen := func(indent int, child bool) {
+		switch {
+		case open == nil:
+			return
+		case len(listItems) > 0:
+			b.add(open, strings.Join(listItems, ","))
+		case child && indent > openIndent:
+			// A chave abriu um mapa aninhado.
+		default:
+			b.add(open, "")
+		}
+		open, listItems = nil, nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		raw := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimLeft(raw, " ")
+		indent := len(raw) - len(trimmed)
+
+		switch {
+		case trimmed == "" || trimmed == "---":
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			b.comment(strings.TrimPrefix(trimmed, "#"))
+			continue
+		case strings.HasPrefix(trimmed, "\t"):
+			return nil, fmt.Errorf("linha %d: indentação com tabulação não é suportada", lineNo)
+		}
+
+		if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			if open == nil {
+				return nil, fmt.Errorf("linha %d: item de lista fora de uma chave", lineNo)
+			}
+			item, err := yamlScalar(strings.TrimSpace(strings.TrimPrefix(trimmed, "-")), lineNo)
+			if err != nil {
+				return nil, err
+			}
+			listItems = append(listItems, item)
+			continue
+		}
+		closeOpen(indent, true)
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			return nil, fmt.Errorf("linha %d: esperado \"chave: valor\"", lineNo)
+		}
+		key = strings.Trim(strings.TrimSpace(key), `"'`)
+		value = strings.TrimSpace(value)
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		path := make([]string, 0, len(stack)+1)
+		for _, l := range stack {
+			path = append(path, l.key)
+		}
+		path = append(path, key)
+
+		if value == "" || strings.HasPrefix(value, "#") {
+			// Um mapa aninhado ou uma lista em bloco começa na próxima linha.
+			stack = append(stack, level{indent: indent, key: key})
+			open, openIndent = path, indent
+			continue
+		}
+
+		scalar, err := yamlScalar(value, lineNo)
+		if err != nil {
+			return nil, err
+		}
+		b.add(path, scalar)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	closeOpen(0, false)
+
+	return b.layout, nil
+}
+
+/*
+yamlScalar interpreta um valor escalar ou uma lista em linha de YAML
+
+@param value string - O valor, sem espaços nas extremidades
+@param lineNo int - A linha do valor, usada nas mensagens de erro
+
+@return string - O valor interpretado; listas são unidas por vírgulas
+@return error - Um erro se o valor usar construções não suportadas
+*/
+func yamlScalar(value string, lineNo int) (string, error) {
+	switch {
+	case value == "":
+		return "", nil
+	case value[0] == '|' || value[0] == '>':
+		return "", fmt.Errorf("linha %d: blocos literais (| e >) não são suportados", lineNo)
+	case value[0] == '&' || value[0] == '*':
+		return "", fmt.Errorf("linha %d: âncoras e aliases não são suportados", lineNo)
+	case value[0] == '{':
+		return "", fmt.Errorf("linha %d: mapas em linha não são suportados", lineNo)
+	case value[0] == '[':
+		return yamlFlowList(value, lineNo)
+	case value[0] == '"' || value[0] == '\'':
+		end := closingQuote(value)
+		if end < 0 {
+			return "", fmt.Errorf("linha %d: aspas não fechadas", lineNo)
+		}
+		if rest := strings.TrimSpace(value[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("linha %d: conteúdo inesperado depois das aspas: %s", lineNo, rest)
+		}
+		return unquoteScalar(value[:end+1]), nil
+	}
+
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	if value == "~" || value == "null" {
+		return "", nil
+	}
+
+	return value, nil
+}
+
+/*
+yamlFlowList interpreta uma lista em linha, como [a, "b, c", 'd'], respeitando as vírgulas entre aspas
+
+@param value string - A lista, começando por [
+@param lineNo int - A linha do valor, usada nas mensagens de erro
+
+@return string - Os itens unidos por vírgulas
+@return error - Um erro se a lista não for fechada, tiver listas ou mapas aninhados ou um item contiver vírgula
+*/
+func yamlFlowList(value string, lineNo int) (string, error) {
+	var items []string
+	i := 1
+	for {
+		for i < len(value) && (value[i] == ' ' || value[i] == '\t') {
+			i++
+		}
+		if i >= len(value) {
+			return "", fmt.Errorf("linha %d: lista em linha sem ]", lineNo)
+		}
+		if value[i] == ']' && len(items) == 0 {
+			break
+		}
+
+		var item string
+		switch value[i] {
+		case '"', '\'':
+			end := closingQuote(value[i:])
+			if end < 0 {
+				return "", fmt.Errorf("linha %d: aspas não fechadas", lineNo)
+			}
+			item = unquoteScalar(value[i : i+end+1])
+			i += end + 1
+		case '[', '{':
+			return "", fmt.Errorf("linha %d: listas e mapas aninhados não são suportados", lineNo)
+		default:
+			end := strings.IndexAny(value[i:], ",]")
+			if end < 0 {
+				return "", fmt.Errorf("linha %d: lista em linha sem ]", lineNo)
+			}
+			item = strings.TrimSpace(value[i : i+end])
+			i += end
+		}
+		if strings.Contains(item, ",") {
+			return "", fmt.Errorf("linha %d: o item %q contém vírgula e não pode ser unido aos demais", lineNo, item)
+		}
+		if item != "" {
+			items = append(items, item)
+		}
+
+		for i < len(value) && (value[i] == ' ' || value[i] == '\t') {
+			i++
+		}
+		if i >= len(value) {
+			return "", fmt.Errorf("linha %d: lista em linha sem ]", lineNo)
+		}
+		if value[i] == ']' {
+			break
+		}
+		if value[i] != ',' {
+			return "", fmt.Errorf("linha %d: esperado , ou ] na lista em linha", lineNo)
+		}
+		i++
+	}
+
+	if rest := strings.TrimSpace(value[i+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+		return "", fmt.Errorf("linha %d: conteúdo inesperado depois da lista: %s", lineNo, rest)
+	}
+
+	return strings.Join(items, ","), nil
+}
+
+/*
+closingQuote encontra a aspa que fecha a string iniciada no primeiro byte do valor
+
+Nas aspas simples, duas aspas seguidas representam uma aspa; nas duplas, a barra invertida escapa o caractere seguinte.
+
+@param value string - O valor, começando pela aspa de abertura
+
+@return int - O índice da aspa de fechamento, ou -1 se a string não for fechada
+*/
+func closingQuote(value string) int {
+	quote := value[0]
+	for i := 1; i < len(value); i++ {
+		switch {
+		case quote == '"' && value[i] == '\\':
+			i++
+		case value[i] != quote:
+		case quote == '\'' && i+1 < len(value) && value[i+1] == '\'':
+			i++
+		default:
+			return i
+		}
+	}
+
+	return -1
+}
+
+/*
+unquoteScalar remove as aspas de um valor, interpretando os escapes básicos das aspas duplas
+
+@param value string - O valor
+
+@return string - O valor sem aspas
+*/
+func unquoteScalar(value string) string {
+	if len(value) < 2 || (value[0] != '"' && value[0] != '\'') || value[len(value)-1] != value[0] {
+		return value
+	}
+
+	inner := value[1 : len(value)-1]
+	if value[0] == '\'' {
+		return strings.ReplaceAll(inner, "''", "'")
+	}
+
+	return strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\n`, "\n", `\t`, "\t").Replace(inner)
+}
+
+/*
+parseTOMLLayout lê o subconjunto de TOML usado em arquivos de configuração
+
+São suportadas tabelas ([a.b]), chaves pontuadas, strings, números, booleanos, arrays em uma linha e comentários.
+Arrays de tabelas e strings multilinha resultam em erro com o número da linha.
+
+@param r io.Reader - O conteúdo TOML
+@param envNames map[string]bool - Os nomes de seções tratados como ambientes
+
+@return *migrateLayout - As variáveis lidas
+@return error - Um erro se o conteúdo usar construções não suportadas
+*/
+func parseTOMLLayout(r io.Reader, envNames map[string]bool) (*migrateLayout, error) {
+	b := newLayoutBuilder(envNames)
+	var table []string
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#"):
+			b.comment(strings.TrimPrefix(line, "#"))
+			continue
+		case strings.HasPrefix(line, "[["):
+			return nil, fmt.Errorf("linha %d: arrays de tabelas não são suportados", lineNo)
+		case strings.HasPrefix(line, "["):
+			end := strings.IndexByte(line, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("linha %d: tabela sem ]", lineNo)
+			}
+			table = tomlKeyPath(line[1:end])
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("linha %d: esperado \"chave = valor\"", lineNo)
+		}
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, `"""`) || strings.HasPrefix(value, "'''") {
+			return nil, fmt.Errorf("linha %d: strings multilinha não são suportadas", lineNo)
+		}
+		if strings.HasPrefix(value, "{") {
+			return nil, fmt.Errorf("linha %d: tabelas em linha não são suportadas", lineNo)
+		}
+
+		scalar, err := yamlScalar(value, lineNo)
+		if err != nil {
+			return nil, err
+		}
+
+		b.add(append(append([]string(nil), table...), tomlKeyPath(key)...), scalar)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return b.layout, nil
+}
+
+/*
+tomlKeyPath separa uma chave pontuada de TOML em seus componentes
+
+@param key string - A chave, como a.b ou "a.b".c
+
+@return []string - Os componentes, sem aspas
+*/
+func tomlKeyPath(key string) []string {
+	var parts []string
+	for _, part := range strings.Split(key, ".") {
+		parts = append(parts, unquoteScalar(strings.TrimSpace(part)))
+	}
+
+	return parts
+}
+
+/*
+parseEnvLayout separa um .env monolítico em seções de ambiente
+
+As linhas antes do primeiro cabeçalho "# [ambiente]" são comuns; as demais pertencem ao último cabeçalho lido.
+Cada bloco é copiado como texto, sem reinterpretar os valores, de modo que comentários, aspas, valores multilinha
+e referências ${VAR} são preservados. Nos arquivos gerados, o bloco do ambiente vem depois do bloco comum e,
+portanto, prevalece sobre ele.
+
+@param r io.Reader - O conteúdo do .env
+
+@return *migrateLayout - Os blocos lidos
+@return error - Um erro se o conteúdo não puder ser lido
+*/
+func parseEnvLayout(r io.Reader) (*migrateLayout, error) {
+	blocks := map[string]*strings.Builder{"": {}}
+	var order []string
+	current := blocks[""]
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := envSectionHeader.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			env := strings.ToLower(match[1])
+			if blocks[env] == nil {
+				blocks[env] = &strings.Builder{}
+				order = append(order, env)
+			}
+			current = blocks[env]
+			continue
+		}
+		current.WriteString(line)
+		current.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	layout := &migrateLayout{sections: make(map[string][]migrateEntry)}
+	if text := blocks[""].String(); strings.TrimSpace(text) != "" {
+		layout.common = []migrateEntry{{raw: text}}
+	}
+	for _, env := range order {
+		layout.sections[env] = []migrateEntry{{raw: blocks[env].String()}}
+	}
+
+	return layout, nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+/*
+TestYAMLScalar verifica se os valores entre aspas terminam na aspa de fechamento correta, considerando os escapes
+e os comentários, e se as listas em linha respeitam as vírgulas entre aspas.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestYAMLScalar(t *testing.T) {
+	cases := map[string]string{
+		`plain # comentário`:      "plain",
+		`"a" # "b"`:               "a",
+		`'it''s' # 'x'`:           "it's",
+		`"say \"hi\"" # "x"`:      `say "hi"`,
+		`"a # b"`:                 "a # b",
+		`[a, b]`:                  "a,b",
+		`["x]", 'y''z', w] # [c]`: "x],y'z,w",
+		`[ "a" , b ,]`:            "a,b",
+		`[]`:                      "",
+		`~`:                       "",
+	}
+	for value, want := range cases {
+		got, err := yamlScalar(value, 1)
+		if err != nil || got != want {
+			t.Errorf("yamlScalar(%s) = %q (%v), esperado %q", value, got, err, want)
+		}
+	}
+
+	for _, value := range []string{`"aberta`, `"a" b`, `'a''`, `[a, "b`, `[a, b`, `["a, b"]`, `[[a]]`, `[a] b`, `["a" "b"]`} {
+		if got, err := yamlScalar(value, 7); err == nil || !strings.Contains(err.Error(), "linha 7") {
+			t.Errorf("yamlScalar(%s) = %q, esperado um erro com a linha", value, got)
+		}
+	}
+}
+
+/*
+TestParseYAMLLayout verifica se um config.yaml com seções de ambiente, listas e comentários é separado nas
+variáveis comuns e nas de cada ambiente.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestParseYAMLLayout(t *testing.T) {
+	content := `# Banco de dados
+database:
+  host: "db.local" # padrão
+  hosts: ["a:1", 'b:2']
+servers:
+  - one
+  - "two # três"
+production:
+  database:
+    host: 'prod''s db'
+`
+	layout, err := parseYAMLLayout(strings.NewReader(content), map[string]bool{"production": true})
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	var common []string
+	for _, e := range layout.common {
+		common = append(common, e.key+"="+e.value)
+	}
+	want := "DATABASE_HOST=db.local;DATABASE_HOSTS=a:1,b:2;SERVERS=one,two # três"
+	if got := strings.Join(common, ";"); got != want {
+		t.Errorf("Variáveis comuns inesperadas: %s", got)
+	}
+	if comments := layout.common[0].comments; len(comments) != 1 || comments[0] != "Banco de dados" {
+		t.Errorf("Comentários inesperados: %v", comments)
+	}
+
+	production := layout.sections["production"]
+	if len(production) != 1 || production[0].key != "DATABASE_HOST" || production[0].value != "prod's db" {
+		t.Errorf("Seção production inesperada: %+v", production)
+	}
+}
+
+/*
+TestParseTOMLLayout verifica se um settings.toml com tabelas, strings com escapes e arrays em linha é convertido.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestParseTOMLLayout(t *testing.T) {
+	content := `title = "app \"v2\"" # nome
+[cache]
+ports = [6379, "6380"]
+
+[staging.cache]
+host = "cache.staging"
+`
+	layout, err := parseTOMLLayout(strings.NewReader(content), map[string]bool{"staging": true})
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	var common []string
+	for _, e := range layout.common {
+		common = append(common, e.key+"="+e.value)
+	}
+	if got := strings.Join(common, ";"); got != `TITLE=app "v2";CACHE_PORTS=6379,6380` {
+		t.Errorf("Variáveis comuns inesperadas: %s", got)
+	}
+	if staging := layout.sections["staging"]; len(staging) != 1 || staging[0].key != "CACHE_HOST" || staging[0].value != "cache.staging" {
+		t.Errorf("Seção staging inesperada: %+v", staging)
+	}
+
+	if _, err := parseTOMLLayout(strings.NewReader("a = \"x\" y\n"), nil); err == nil {
+		t.Error("Esperado um erro para o conteúdo depois das aspas")
+	}
+}
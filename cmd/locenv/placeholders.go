@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+runPlaceholders executa o subcomando placeholders, que lista as variáveis com valores de exemplo
+
+Valores como <YOUR_KEY_HERE>, TODO e changeme costumam sobrar nos arquivos copiados de um .env.example. Com
+-fill, o subcomando pergunta no terminal o valor real de cada variável e grava as respostas na sobreposição
+local do usuário (.env.local.<usuário>, carregada com config.WithUserOverlays) ou no arquivo de -o, sem alterar
+os arquivos versionados. Uma resposta vazia mantém o valor de exemplo. Com -output json, github ou gitlab, que
+não podem ser combinados com -fill, cada variável é um problema com o arquivo, a linha e a variável.
+
+@param args []string - Os argumentos do subcomando
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - 0 se nenhum valor de exemplo restar, 1 se restarem, 2 em caso de erro
+*/
+func runPlaceholders(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("placeholders", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	fill := flags.Bool("fill", false, "pergunta os valores reais no terminal e os grava no arquivo local")
+	output := flags.String("o", "", "arquivo em que os valores são gravados (padrão: .env.local.<usuário>)")
+	format := outputFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	structured, ok := structuredOutput(*format, stderr)
+	if !ok {
+		return 2
+	}
+	if structured && *fill {
+		fmt.Fprintln(stderr, "locenv: -fill só pode ser usado com -output text")
+		return 2
+	}
+
+	found, err := config.FindPlaceholders(config.WithSilent(), config.WithUserOverlays())
+	if err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 2
+	}
+	if structured {
+		r := newReport("placeholders")
+		for _, p := range found {
+			r.add(severityError, p.File+":"+strconv.Itoa(p.Line)+": variável "+p.Key+" ainda tem o valor de exemplo "+strconv.Quote(p.Value)).Key = p.Key
+		}
+		return r.write(stdout, stderr, *format)
+	}
+	if len(found) == 0 {
+		fmt.Fprintln(stdout, "Nenhum valor de exemplo encontrado.")
+		return 0
+	}
+
+	if !*fill {
+		for _, p := range found {
+			fmt.Fprintln(stdout, p)
+		}
+		fmt.Fprintf(stdout, "\n%d variável(is) com valores de exemplo; preencha-as com locenv placeholders -fill.\n", len(found))
+		return 1
+	}
+
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		fmt.Fprintln(stderr, "locenv: -fill precisa de um terminal interativo")
+		return 2
+	}
+	target := *output
+	if target == "" {
+		loader := config.NewEnvLoader(config.WithSilent()).(*config.FileEnvLoader)
+		if target, err = loader.LocalOverlayFile(); err != nil {
+			fmt.Fprintf(stderr, "locenv: %s\n", err)
+			return 2
+		}
+	}
+
+	values := make(map[string]string)
+	reader := bufio.NewReader(os.Stdin)
+	for _, p := range found {
+		fmt.Fprintf(stdout, "%s (%s:%d, atual %q): ", p.Key, p.File, p.Line, p.Value)
+		answer, err := reader.ReadString('\n')
+		if answer = strings.TrimRight(answer, "\r\n"); answer != "" {
+			values[p.Key] = answer
+		}
+		if err != nil {
+			fmt.Fprintln(stdout)
+			break
+		}
+	}
+
+	if err := config.SetValues(target, values); err != nil {
+		fmt.Fprintf(stderr, "locenv: erro ao gravar %s: %s\n", target, err)
+		return 2
+	}
+	fmt.Fprintf(stdout, "%d valor(es) gravado(s) em %s.\n", len(values), target)
+
+	if len(values) < len(found) {
+		return 1
+	}
+	return 0
+}
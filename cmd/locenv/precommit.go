@@ -0,0 +1,130 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+// preCommitMarker identifica os hooks de pre-commit escritos por locenv hook install.
+const preCommitMarker = "# Instalado por locenv hook install."
+
+// preCommitHook é o script do hook de pre-commit.
+const preCommitHook = `#!/bin/sh
+` + preCommitMarker + `
+# Verifica a sintaxe, o estilo, os segredos em texto claro e a sincronia com o .env.example
+# dos arquivos .env preparados para o commit. Use git commit --no-verify para ignorar.
+exec locenv hook pre-commit
+`
+
+/*
+runHookInstall executa o subcomando hook install, que instala o hook de pre-commit do git
+
+O hook é gravado no diretório de hooks do repositório, respeitando core.hooksPath, e executa locenv hook
+pre-commit. Um hook de pre-commit existente que não foi escrito pelo locenv só é substituído com -force.
+
+@param args []string - Os argumentos do subcomando
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - 0 em caso de sucesso, 1 se o hook não puder ser gravado, 2 em caso de erro de uso
+*/
+func runHookInstall(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("hook install", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	force := flags.Bool("force", false, "substitui um hook de pre-commit existente")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	out, err := exec.Command("git", "rev-parse", "--git-path", "hooks").Output()
+	if err != nil {
+		fmt.Fprintln(stderr, "locenv: o diretório atual não está em um repositório git")
+		return 1
+	}
+	dir := strings.TrimSpace(string(out))
+	file := filepath.Join(dir, "pre-commit")
+
+	if existing, err := os.ReadFile(file); err == nil && !strings.Contains(string(existing), preCommitMarker) && !*force {
+		fmt.Fprintf(stderr, "locenv: %s já existe e não foi escrito pelo locenv; use -force para substituí-lo\n", file)
+		return 1
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 1
+	}
+	if err := os.WriteFile(file, []byte(preCommitHook), 0o755); err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 1
+	}
+	if err := os.Chmod(file, 0o755); err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "✔ hook de pre-commit instalado em %s\n", file)
+	return 0
+}
+
+/*
+runPreCommit executa o subcomando hook pre-commit, chamado pelo hook instalado por hook install
+
+Os arquivos .env preparados para o commit são verificados com config.CheckStagedEnvFiles. Qualquer problema
+interrompe o commit. Com -output json, os problemas são escritos na saída padrão no esquema de report.
+
+@param args []string - Os argumentos do subcomando
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - 0 se nenhum problema for encontrado, 1 se houver problemas, 2 em caso de erro
+*/
+func runPreCommit(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("hook pre-commit", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	secretKeys := flags.String("secret-keys", strings.Join(config.DefaultSecretPatterns, ","), "padrões de nomes de variáveis secretas, separados por vírgula")
+	example := flags.String("example", ".env.example", "arquivo de exemplo, relativo à raiz do repositório")
+	format := outputFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if err := applyProjectConfig(flags); err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 2
+	}
+	structured, ok := structuredOutput(*format, stderr)
+	if !ok {
+		return 2
+	}
+
+	files, problems, err := config.CheckStagedEnvFiles(".", splitList(*secretKeys), *example)
+	if err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 2
+	}
+	if structured {
+		r := newReport("hook pre-commit")
+		for _, problem := range problems {
+			r.add(severityError, problem)
+		}
+		r.Data = struct {
+			Files []string `json:"files"`
+		}{files}
+		return r.write(stdout, stderr, *format)
+	}
+	if len(problems) == 0 {
+		return 0
+	}
+
+	fmt.Fprintf(stderr, "locenv: %d problema(s) em %d arquivo(s) .env preparado(s) para o commit:\n", len(problems), len(files))
+	for _, problem := range problems {
+		fmt.Fprintf(stderr, "  ✖ %s\n", problem)
+	}
+	return 1
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// projectConfigFile é o arquivo de configuração do projeto criado por locenv init.
+const projectConfigFile = ".locenv.yaml"
+
+/*
+projectConfig é o conteúdo do .locenv.yaml, que fornece os padrões das opções dos subcomandos
+
+dir string - O diretório do arquivo, base dos caminhos relativos
+Schema string - O esquema usado por -schema
+Environments []string - Os ambientes usados por init -envs
+SecretKeys []string - Os padrões usados por -secret-keys
+*/
+type projectConfig struct {
+	dir          string
+	Schema       string
+	Environments []string
+	SecretKeys   []string
+}
+
+/*
+findProjectConfig procura o .locenv.yaml no diretório atual e nos seus ancestrais
+
+@return *projectConfig - A configuração, ou nil se nenhum arquivo existir
+@return error - Um erro se o arquivo não puder ser lido ou usar chaves desconhecidas
+*/
+func findProjectConfig() (*projectConfig, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		file := filepath.Join(dir, projectConfigFile)
+		content, err := os.ReadFile(file)
+		if err == nil {
+			cfg, err := parseProjectConfig(string(content))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+			cfg.dir = dir
+			return cfg, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+/*
+parseProjectConfig interpreta o .locenv.yaml gerado por renderProjectConfig
+
+São aceitas as chaves schema, com um valor, e environments e secret_keys, com uma lista em bloco (- item) ou em
+linha ([a, b]).
+
+@param content string - O conteúdo do arquivo
+
+@return *projectConfig - A configuração
+@return error - Um erro com o número da linha se uma chave for desconhecida ou um valor for inválido
+*/
+func parseProjectConfig(content string) (*projectConfig, error) {
+	cfg := &projectConfig{}
+	var list *[]string
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "- ") && list != nil && line != trimmed {
+			item, err := yamlScalar(strings.TrimSpace(strings.TrimPrefix(trimmed, "-")), lineNo)
+			if err != nil {
+				return nil, err
+			}
+			*list = append(*list, item)
+			continue
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok || line != trimmed {
+			return nil, fmt.Errorf("linha %d: esperado \"chave: valor\"", lineNo)
+		}
+		scalar, err := yamlScalar(strings.TrimSpace(value), lineNo)
+		if err != nil {
+			return nil, err
+		}
+
+		list = nil
+		switch strings.TrimSpace(key) {
+		case "schema":
+			cfg.Schema = scalar
+		case "environments":
+			list = &cfg.Environments
+			*list = splitList(scalar)
+		case "secret_keys":
+			list = &cfg.SecretKeys
+			*list = splitList(scalar)
+		default:
+			return nil, fmt.Errorf("linha %d: chave desconhecida %q", lineNo, strings.TrimSpace(key))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+/*
+applyProjectConfig usa o .locenv.yaml do projeto como padrão das opções -schema, -secret-keys e -envs
+
+Só as opções que o subcomando declara e que não foram informadas na linha de comando são alteradas; o caminho do
+esquema é relativo ao diretório do .locenv.yaml.
+
+@param flags *flag.FlagSet - As opções do subcomando, já interpretadas
+
+@return error - Um erro se o .locenv.yaml não puder ser lido
+*/
+func applyProjectConfig(flags *flag.FlagSet) error {
+	cfg, err := findProjectConfig()
+	if err != nil || cfg == nil {
+		return err
+	}
+
+	schema := cfg.Schema
+	if schema != "" && !filepath.IsAbs(schema) {
+		schema = filepath.Join(cfg.dir, schema)
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, schema); err == nil {
+				schema = rel
+			}
+		}
+	}
+	defaults := map[string]string{
+		"schema":      schema,
+		"secret-keys": strings.Join(cfg.SecretKeys, ","),
+		"envs":        strings.Join(cfg.Environments, ","),
+	}
+
+	explicit := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	for name, value := range defaults {
+		if value == "" || explicit[name] || flags.Lookup(name) == nil {
+			continue
+		}
+		if err := flags.Set(name, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
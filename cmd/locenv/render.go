@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+runRender executa o subcomando render, que gera a configuração resolvida de todos os ambientes para um repositório GitOps
+
+Cada arquivo .env.<ambiente> de -dir é resolvido com config.RenderBundle e gravado em -out/<ambiente>, como
+arquivos dotenv, com -format kubernetes, como um ConfigMap e um Secret, ou, com -format helm, como um values.yaml
+com os caminhos de -helm-map (ex.: -helm-map DB_HOST=database.host -helm-map 'REDIS_*=redis.*'). Os segredos só
+são gerados com -secrets; sem ela, os nomes dos omitidos são listados. A saída é determinística, para ser
+versionada.
+
+@param args []string - Os argumentos do subcomando
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - 0 em caso de sucesso, 1 se algum ambiente não resolver ou a gravação falhar, 2 em caso de erro de uso
+*/
+func runRender(args []string, stdout io.Writer, stderr io.Writer) int {
+	helm := optionsFlag{}
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dir := flags.String("dir", ".", "diretório com os arquivos .env")
+	out := flags.String("out", "", "diretório de destino, com um subdiretório por ambiente")
+	format := flags.String("format", string(config.BundleDotenv), "formato dos arquivos: dotenv, kubernetes ou helm")
+	name := flags.String("name", "app", "nome do ConfigMap e do Secret")
+	namespace := flags.String("namespace", "", "namespace do ConfigMap e do Secret")
+	flags.Var(helm, "helm-map", "com -format helm, o caminho de values.yaml de uma variável ou prefixo, no formato VARIÁVEL=caminho (repetível)")
+	secrets := flags.Bool("secrets", false, "gera também os segredos, em arquivos separados")
+	schema := flags.String("schema", ".env.example", "esquema anotado com as variáveis obrigatórias, secretas e tipadas (ignorado se não existir)")
+	secretKeys := flags.String("secret-keys", strings.Join(config.DefaultSecretPatterns, ","), "padrões de nomes de variáveis secretas, separados por vírgula")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if err := applyProjectConfig(flags); err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 2
+	}
+	if *out == "" {
+		fmt.Fprintln(stderr, "locenv: informe o diretório de destino com -out")
+		return 2
+	}
+	switch config.BundleFormat(*format) {
+	case config.BundleDotenv, config.BundleKubernetes, config.BundleHelm:
+	default:
+		fmt.Fprintf(stderr, "locenv: formato desconhecido: %s\n", *format)
+		return 2
+	}
+
+	opts := []config.Option{config.WithSecretKeys(splitList(*secretKeys)...)}
+	if _, err := os.Stat(*schema); err == nil {
+		specs, err := config.LoadSchema(*schema)
+		if err != nil {
+			fmt.Fprintf(stderr, "erro ao ler o esquema %s: %s\n", *schema, err)
+			return 2
+		}
+		opts = append(opts, config.WithSchema(specs))
+	}
+
+	bundle, err := config.RenderBundle(*dir, config.BundleOptions{
+		Format:         config.BundleFormat(*format),
+		Name:           *name,
+		Namespace:      *namespace,
+		IncludeSecrets: *secrets,
+		Helm:           config.HelmMapping(helm),
+	}, opts...)
+	if err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 1
+	}
+	if err := bundle.Write(*out); err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 1
+	}
+
+	for _, file := range bundle.Files {
+		fmt.Fprintf(stdout, "%s/%s\n", strings.TrimSuffix(*out, "/"), file.Path)
+	}
+	for _, env := range bundle.Envs {
+		if omitted := bundle.Omitted[env]; len(omitted) > 0 {
+			fmt.Fprintf(stdout, "%s: segredos omitidos (use -secrets): %s\n", env, strings.Join(omitted, ", "))
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// reportVersion é a versão do esquema de -output json; ela só muda quando um campo é removido ou muda de sentido.
+const reportVersion = 1
+
+const (
+	// severityError marca um problema que faz o subcomando terminar com código 1.
+	severityError = "error"
+	// severityWarning marca um aviso, que não altera o código de saída.
+	severityWarning = "warning"
+)
+
+var (
+	// problemPrefix separa o prefixo arquivo:linha: das mensagens de problema produzidas pela biblioteca.
+	problemPrefix = regexp.MustCompile(`^([^\s:]*[./][^\s:]*)(?::(\d+)(?::\d+)?)?: (.+)$`)
+	// problemLocation encontra uma posição arquivo:linha: citada no meio de uma mensagem, como a de um erro de sintaxe.
+	problemLocation = regexp.MustCompile(`([^\s:"]*[./][^\s:"]*):(\d+)(?::\d+)?: `)
+)
+
+/*
+report é o documento escrito pelos subcomandos com -output json, github ou gitlab
+
+O esquema é o mesmo para todos os subcomandos, para que a CI e os bots possam anotar pull requests sem conhecer
+cada um deles: os problemas ficam sempre em problems, com o arquivo e a linha quando conhecidos, e os detalhes
+próprios do subcomando ficam em data.
+
+Version int - A versão do esquema (reportVersion)
+Command string - O subcomando que produziu o relatório
+OK bool - Se nenhum problema com severidade error foi encontrado
+Problems []reportProblem - Os problemas e avisos, na ordem em que foram encontrados
+Data any - Os detalhes do subcomando, omitidos quando não há nenhum
+*/
+type report struct {
+	Version  int             `json:"version"`
+	Command  string          `json:"command"`
+	OK       bool            `json:"ok"`
+	Problems []reportProblem `json:"problems"`
+	Data     any             `json:"data,omitempty"`
+}
+
+/*
+reportProblem é um problema ou aviso de report
+
+Severity string - error ou warning
+Message string - A descrição, sem o prefixo arquivo:linha:
+File string - O arquivo relacionado, quando conhecido
+Line int - A linha no arquivo, quando conhecida
+Key string - A variável relacionada, quando conhecida
+Env string - O ambiente relacionado, quando o relatório cobre mais de um
+*/
+type reportProblem struct {
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Key      string `json:"key,omitempty"`
+	Env      string `json:"env,omitempty"`
+}
+
+/*
+outputFlag registra a opção -output em um subcomando
+
+@param flags *flag.FlagSet - As opções do subcomando
+
+@return *string - O formato escolhido
+*/
+func outputFlag(flags *flag.FlagSet) *string {
+	return flags.String("output", "text", "formato da saída: text, json, github (comandos de workflow do GitHub Actions) ou gitlab (Code Quality do GitLab)")
+}
+
+/*
+structuredOutput interpreta o valor de -output
+
+@param format string - O valor da opção
+@param stderr io.Writer - A saída em que um valor inválido é relatado
+
+@return bool - Se o formato produz um relatório (json, github ou gitlab) em vez do texto do subcomando
+@return bool - Se o formato é válido
+*/
+func structuredOutput(format string, stderr io.Writer) (bool, bool) {
+	switch format {
+	case "json", "github", "gitlab":
+		return true, true
+	case "text":
+		return false, true
+	default:
+		fmt.Fprintf(stderr, "locenv: formato de saída desconhecido %q: use text, json, github ou gitlab\n", format)
+		return false, false
+	}
+}
+
+/*
+newReport cria o relatório de um subcomando, sem problemas
+
+@param command string - O nome do subcomando
+
+@return *report - O relatório
+*/
+func newReport(command string) *report {
+	return &report{Version: reportVersion, Command: command, OK: true, Problems: []reportProblem{}}
+}
+
+/*
+add inclui um problema descrito em texto, separando o prefixo arquivo:linha: quando houver
+
+Quando a posição aparece no meio da mensagem, o arquivo e a linha são preenchidos e a mensagem fica inteira.
+
+@param severity string - severityError ou severityWarning
+@param text string - A descrição do problema
+
+@return *reportProblem - O problema incluído, para que o chamador complete a variável ou o ambiente
+*/
+func (r *report) add(severity string, text string) *reportProblem {
+	problem := reportProblem{Severity: severity, Message: text}
+	if m := problemPrefix.FindStringSubmatch(text); m != nil {
+		problem.File, problem.Message = m[1], m[3]
+		problem.Line, _ = strconv.Atoi(m[2])
+	} else if m := problemLocation.FindStringSubmatch(text); m != nil {
+		problem.File = m[1]
+		problem.Line, _ = strconv.Atoi(m[2])
+	}
+	if severity == severityError {
+		r.OK = false
+	}
+	r.Problems = append(r.Problems, problem)
+
+	return &r.Problems[len(r.Problems)-1]
+}
+
+/*
+write escreve o relatório no formato de -output
+
+Com json, o relatório é escrito inteiro, em JSON indentado; com github e gitlab, apenas os problemas são
+escritos, como anotações (veja writeGitHub e writeGitLab).
+
+@param w io.Writer - A saída em que o relatório é escrito
+@param stderr io.Writer - A saída em que um erro de escrita é relatado
+@param format string - O formato: json, github ou gitlab
+
+@return int - 0 se o relatório não tiver erros, 1 se tiver, 2 se não puder ser escrito
+*/
+func (r *report) write(w io.Writer, stderr io.Writer, format string) int {
+	var err error
+	switch format {
+	case "github":
+		err = r.writeGitHub(w)
+	case "gitlab":
+		err = r.writeGitLab(w)
+	default:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(r)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "locenv: erro ao escrever o relatório: %s\n", err)
+		return 2
+	}
+
+	if !r.OK {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+runScan executa o subcomando scan, que procura valores com cara de credenciais reais em arquivos .env
+
+Sem argumentos, verifica os arquivos .env versionados pelo git com config.ScanTrackedFiles; com argumentos,
+verifica os arquivos informados. A verificação olha para os valores (chaves da AWS, JWTs, chaves privadas,
+senhas em strings de conexão e strings de alta entropia), e não para os nomes das variáveis. Os valores
+encontrados nunca são exibidos. Com -output json, cada achado é um problema com o arquivo, a linha e a variável.
+
+@param args []string - Os argumentos do subcomando
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - 0 se nada for encontrado, 1 se houver achados, 2 em caso de erro
+*/
+func runScan(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("scan", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dir := flags.String("dir", ".", "diretório do repositório git verificado quando nenhum arquivo é informado")
+	format := outputFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	structured, ok := structuredOutput(*format, stderr)
+	if !ok {
+		return 2
+	}
+
+	var findings []config.CredentialFinding
+	if flags.NArg() == 0 {
+		var err error
+		if findings, err = config.ScanTrackedFiles(*dir); err != nil {
+			fmt.Fprintf(stderr, "locenv: %s\n", err)
+			return 2
+		}
+	}
+	for _, file := range flags.Args() {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(stderr, "locenv: %s\n", err)
+			return 2
+		}
+		found, err := config.ScanContent(file, content)
+		if err != nil {
+			fmt.Fprintf(stderr, "locenv: %s\n", err)
+			return 2
+		}
+		findings = append(findings, found...)
+	}
+
+	if structured {
+		r := newReport("scan")
+		if findings == nil {
+			findings = []config.CredentialFinding{}
+		}
+		for _, finding := range findings {
+			r.add(severityError, finding.String()).Key = finding.Key
+		}
+		r.Data = struct {
+			Findings []config.CredentialFinding `json:"findings"`
+		}{findings}
+		return r.write(stdout, stderr, *format)
+	}
+	if len(findings) == 0 {
+		fmt.Fprintln(stdout, "✔ nenhuma credencial encontrada")
+		return 0
+	}
+
+	for _, finding := range findings {
+		fmt.Fprintf(stdout, "✖ %s\n", finding)
+	}
+	return 1
+}
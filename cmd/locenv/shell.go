@@ -0,0 +1,589 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+// cacheKeyEnvVar é a variável de ambiente que guarda a chave do cache em disco, em base64.
+const cacheKeyEnvVar = "LOCENV_CACHE_KEY"
+
+// loadedEnvVar guarda, no shell, as variáveis definidas pelo último export, para que possam ser removidas depois.
+const loadedEnvVar = "LOCENV_LOADED"
+
+/*
+shell descreve a sintaxe de um shell suportado pelos subcomandos export, hook e completion
+
+name string - O nome do shell na linha de comando
+set func(key, value string) string - Gera o comando que define uma variável
+unset func(key string) string - Gera o comando que remove uma variável
+hook string - O script que executa locenv export ao entrar em um diretório
+completion func(cmds []command, names []string) string - Gera o script de autocompletar a partir dos subcomandos e dos nomes dos shells
+*/
+type shell struct {
+	name       string
+	set        func(key, value string) string
+	unset      func(key string) string
+	hook       string
+	completion func(cmds []command, names []string) string
+}
+
+// shells são os shells suportados, na ordem exibida pela ajuda e pelo autocompletar.
+var shells = []shell{
+	{
+		name:       "bash",
+		set:        func(key, value string) string { return fmt.Sprintf("export %s=%s;", key, posixQuote(value)) },
+		unset:      func(key string) string { return fmt.Sprintf("unset %s;", key) },
+		hook:       bashHook,
+		completion: bashCompletion,
+	},
+	{
+		name:       "zsh",
+		set:        func(key, value string) string { return fmt.Sprintf("export %s=%s;", key, posixQuote(value)) },
+		unset:      func(key string) string { return fmt.Sprintf("unset %s;", key) },
+		hook:       zshHook,
+		completion: zshCompletion,
+	},
+	{
+		name:       "fish",
+		set:        func(key, value string) string { return fmt.Sprintf("set -gx %s %s;", key, fishQuote(value)) },
+		unset:      func(key string) string { return fmt.Sprintf("set -e %s;", key) },
+		hook:       fishHook,
+		completion: fishCompletion,
+	},
+	{
+		name:       "powershell",
+		set:        func(key, value string) string { return fmt.Sprintf("$env:%s = %s", key, powershellQuote(value)) },
+		unset:      func(key string) string { return fmt.Sprintf("Remove-Item Env:%s -ErrorAction SilentlyContinue", key) },
+		hook:       powershellHook,
+		completion: powershellCompletion,
+	},
+}
+
+/*
+findShell retorna o shell com o nome informado
+
+@param name string - O nome do shell
+
+@return shell - O shell encontrado
+@return error - Um erro se o shell não for suportado
+*/
+func findShell(name string) (shell, error) {
+	for _, sh := range shells {
+		if sh.name == name {
+			return sh, nil
+		}
+	}
+
+	return shell{}, fmt.Errorf("shell não suportado: %q (use %s)", name, strings.Join(shellNames(), ", "))
+}
+
+/*
+shellNames retorna os nomes dos shells suportados
+
+@return []string - Os nomes, na ordem de shells
+*/
+func shellNames() []string {
+	names := make([]string, len(shells))
+	for i, sh := range shells {
+		names[i] = sh.name
+	}
+
+	return names
+}
+
+/*
+shellArg lê o único argumento posicional dos subcomandos hook e completion, o nome do shell
+
+@param name string - O nome do subcomando, usado nas mensagens de erro
+@param args []string - Os argumentos do subcomando
+@param stderr io.Writer - A saída de erros
+
+@return shell - O shell informado
+@return bool - Se o argumento é válido
+*/
+func shellArg(name string, args []string, stderr io.Writer) (shell, bool) {
+	if len(args) != 1 {
+		fmt.Fprintf(stderr, "uso: locenv %s <%s>\n", name, strings.Join(shellNames(), "|"))
+		return shell{}, false
+	}
+
+	sh, err := findShell(args[0])
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return shell{}, false
+	}
+
+	return sh, true
+}
+
+/*
+runExport executa o subcomando export, que escreve os comandos de shell que carregam o perfil do diretório atual
+
+As variáveis definidas por um export anterior, registradas em LOCENV_LOADED, são removidas antes da nova
+resolução, de modo que trocar de projeto ou de perfil não deixa valores antigos no shell. Variáveis que já
+existiam no shell antes do primeiro export nunca são alteradas, como em LoadEnv. Se nenhum arquivo .env for
+encontrado, apenas a remoção das variáveis anteriores é escrita. A descoberta só é executada quando o diretório
+atual ou um dos seus ancestrais contém um arquivo .env.*, para que o hook não percorra o disco fora de projetos.
+Como o hook executa a cada prompt, só são carregados os arquivos autorizados com locenv allow e não alterados desde
+então; os demais são ignorados com um aviso. Variáveis sensíveis, como PATH, PROMPT_COMMAND, BASH_ENV, LD_* e
+DYLD_*, só são escritas quando autorizadas com allow -keys no arquivo que as define.
+Com -from-cache, as variáveis são lidas do cache cifrado gravado por config.WithSnapshotCache, sem ler os arquivos
+.env e sem acesso aos provedores remotos; sem -cache, o cache é o do projeto e do ambiente atuais, em
+config.DefaultCachePath. A chave é lida de -cache-key ou de $LOCENV_CACHE_KEY, em base64.
+As variáveis são escritas na ordem dos arquivos .env, ou em ordem alfabética com -sort, para que a saída seja
+estável entre execuções.
+
+@param args []string - Os argumentos do subcomando
+@param stdout io.Writer - A saída padrão, que deve ser avaliada pelo shell
+@param stderr io.Writer - A saída de erros
+
+@return int - 0 em caso de sucesso, 1 se o perfil não puder ser carregado, 2 em caso de erro de uso
+*/
+func runExport(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	shellName := flags.String("shell", "bash", "shell de destino ("+strings.Join(shellNames(), ", ")+")")
+	fromCache := flags.Bool("from-cache", false, "lê as variáveis do cache cifrado em vez de carregar os arquivos .env")
+	cachePath := flags.String("cache", "", "caminho do cache usado com -from-cache (padrão: o cache do projeto e do ambiente atuais)")
+	cacheKey := flags.String("cache-key", "", "chave AES do cache, em base64 (padrão: $"+cacheKeyEnvVar+")")
+	lexical := flags.Bool("sort", false, "escreve as variáveis em ordem alfabética em vez da ordem dos arquivos")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	sh, err := findShell(*shellName)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	previous := splitList(os.Getenv(loadedEnvVar))
+	for _, key := range previous {
+		os.Unsetenv(key)
+	}
+
+	var loader config.Reader
+	var approved map[string]trustEntry
+	if *fromCache {
+		loader, err = cachedReader(*cachePath, *cacheKey)
+	} else {
+		loader, approved, err = trustedLoader(stderr)
+	}
+	if err != nil && !errors.Is(err, config.ErrEnvNotFound) {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 1
+	}
+
+	var loaded []string
+	if err == nil {
+		for _, key := range loader.Keys() {
+			if source, _ := loader.Source(key); source == config.SourceProcess {
+				continue
+			}
+			if !isShellName(key) {
+				fmt.Fprintf(stderr, "locenv: %s ignorada: não é um nome de variável válido no shell\n", key)
+				continue
+			}
+			if isSensitiveKey(key) && !sensitiveAllowed(loader, approved, key) {
+				fmt.Fprintf(stderr, "locenv: %s ignorada: variável sensível; autorize-a com locenv allow -keys %s\n", key, key)
+				continue
+			}
+			loaded = append(loaded, key)
+		}
+		if *lexical {
+			sort.Strings(loaded)
+		}
+	}
+
+	current := make(map[string]bool, len(loaded))
+	for _, key := range loaded {
+		current[key] = true
+	}
+	for _, key := range previous {
+		if !current[key] && isShellName(key) {
+			fmt.Fprintln(stdout, sh.unset(key))
+		}
+	}
+
+	for _, key := range loaded {
+		value, _ := loader.Lookup(key)
+		fmt.Fprintln(stdout, sh.set(key, value))
+	}
+
+	if len(loaded) > 0 {
+		fmt.Fprintln(stdout, sh.set(loadedEnvVar, strings.Join(loaded, ",")))
+	} else if len(previous) > 0 {
+		fmt.Fprintln(stdout, sh.unset(loadedEnvVar))
+	}
+
+	return 0
+}
+
+/*
+sensitiveAllowed informa se uma variável sensível foi autorizada com allow -keys no arquivo que a definiu
+
+@param loader config.Reader - A configuração carregada
+@param approved map[string]trustEntry - A autorização de cada arquivo carregado, ou nil para o cache
+@param key string - O nome da variável
+
+@return bool - Se a variável pode ser exportada
+*/
+func sensitiveAllowed(loader config.Reader, approved map[string]trustEntry, key string) bool {
+	source, _ := loader.Source(key)
+	file, err := filepath.Abs(source)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range approved[file].Keys {
+		if allowed == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+cachedReader lê o cache cifrado usado por export -from-cache
+
+@param path string - O caminho do cache, ou uma string vazia para o cache do projeto e do ambiente atuais
+@param encodedKey string - A chave AES em base64, ou uma string vazia para $LOCENV_CACHE_KEY
+
+@return config.Reader - A configuração gravada no cache
+@return error - Um erro se a chave não for informada ou o cache não puder ser lido
+*/
+func cachedReader(path string, encodedKey string) (config.Reader, error) {
+	if encodedKey == "" {
+		encodedKey = os.Getenv(cacheKeyEnvVar)
+	}
+	if strings.TrimSpace(encodedKey) == "" {
+		return nil, fmt.Errorf("nenhuma chave do cache informada: use -cache-key ou $%s", cacheKeyEnvVar)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+	if err != nil {
+		return nil, fmt.Errorf("chave do cache inválida: %w", err)
+	}
+
+	view, _, err := config.LoadSnapshotCache(path, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return view, nil
+}
+
+/*
+runHook executa o subcomando hook, que escreve o script que carrega o perfil ao entrar em um diretório
+
+O script deve ser avaliado na inicialização do shell, por exemplo com eval "$(locenv hook bash)" no ~/.bashrc.
+Os argumentos install e pre-commit tratam do hook de pre-commit do git (veja runHookInstall e runPreCommit).
+
+@param args []string - Os argumentos do subcomando
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - 0 em caso de sucesso, 2 em caso de erro de uso
+*/
+func runHook(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "install":
+			return runHookInstall(args[1:], stdout, stderr)
+		case "pre-commit":
+			return runPreCommit(args[1:], stdout, stderr)
+		}
+	}
+
+	sh, ok := shellArg("hook", args, stderr)
+	if !ok {
+		return 2
+	}
+
+	fmt.Fprint(stdout, sh.hook)
+	return 0
+}
+
+/*
+runCompletion executa o subcomando completion, que escreve o script de autocompletar do shell informado
+
+@param args []string - Os argumentos do subcomando
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - 0 em caso de sucesso, 2 em caso de erro de uso
+*/
+func runCompletion(args []string, stdout io.Writer, stderr io.Writer) int {
+	sh, ok := shellArg("completion", args, stderr)
+	if !ok {
+		return 2
+	}
+
+	fmt.Fprint(stdout, sh.completion(commands, shellNames()))
+	return 0
+}
+
+/*
+insideProject informa se o diretório atual ou algum dos seus ancestrais contém diretamente um arquivo .env.*
+
+@return bool - Se algum arquivo .env.* foi encontrado
+*/
+func insideProject() bool {
+	dir, err := os.Getwd()
+	if err != nil {
+		return false
+	}
+
+	for {
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasPrefix(entry.Name(), ".env.") {
+				return true
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
+/*
+isShellName informa se uma chave pode ser usada como nome de variável em todos os shells suportados
+
+@param key string - O nome da variável
+
+@return bool - Se o nome é formado por letras ASCII, dígitos e _, sem começar por dígito
+*/
+func isShellName(key string) bool {
+	if key == "" || (key[0] >= '0' && key[0] <= '9') {
+		return false
+	}
+
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// powershellQuotes são os caracteres que encerram uma string entre aspas simples no PowerShell: o apóstrofo ASCII
+// e as aspas simples tipográficas U+2018 a U+201B.
+var powershellQuotes = strings.NewReplacer(
+	"'", "''",
+	"\u2018", "\u2018\u2018",
+	"\u2019", "\u2019\u2019",
+	"\u201a", "\u201a\u201a",
+	"\u201b", "\u201b\u201b",
+)
+
+/*
+posixQuote protege um valor entre aspas simples para bash e zsh
+
+Nenhum caractere é especial entre aspas simples; cada aspa do valor encerra a string, é escrita escapada e abre
+uma nova string.
+
+@param value string - O valor
+
+@return string - O valor entre aspas
+*/
+func posixQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+/*
+fishQuote protege um valor entre aspas simples para o fish, que reconhece \\ e \' dentro delas
+
+@param value string - O valor
+
+@return string - O valor entre aspas
+*/
+func fishQuote(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(value) + "'"
+}
+
+/*
+powershellQuote protege um valor entre aspas simples para o PowerShell
+
+Cada caractere de powershellQuotes é duplicado, a forma de escrevê-lo sem encerrar a string.
+
+@param value string - O valor
+
+@return string - O valor entre aspas
+*/
+func powershellQuote(value string) string {
+	return "'" + powershellQuotes.Replace(value) + "'"
+}
+
+const bashHook = `_locenv_hook() {
+  local status=$?
+  if [[ "$PWD" != "${_locenv_last_dir:-}" ]]; then
+    _locenv_last_dir="$PWD"
+    eval "$(locenv export -shell bash)"
+  fi
+  return $status
+}
+if [[ ";${PROMPT_COMMAND:-};" != *";_locenv_hook;"* ]]; then
+  PROMPT_COMMAND="_locenv_hook${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+fi
+`
+
+const zshHook = `_locenv_hook() {
+  eval "$(locenv export -shell zsh)"
+}
+typeset -ag chpwd_functions
+if (( ! ${chpwd_functions[(I)_locenv_hook]} )); then
+  chpwd_functions=(_locenv_hook $chpwd_functions)
+fi
+_locenv_hook
+`
+
+const fishHook = `function __locenv_hook --on-variable PWD
+    locenv export -shell fish | source
+end
+__locenv_hook
+`
+
+const powershellHook = `$global:__locenvLastDir = $null
+$global:__locenvPrompt = $function:prompt
+function global:prompt {
+    if ($PWD.Path -ne $global:__locenvLastDir) {
+        $global:__locenvLastDir = $PWD.Path
+        locenv export -shell powershell | Out-String | Invoke-Expression
+    }
+    & $global:__locenvPrompt
+}
+`
+
+/*
+bashCompletion gera o script de autocompletar do bash, com os subcomandos e os shells de completion e hook
+
+@param cmds []command - Os subcomandos
+@param names []string - Os nomes dos shells
+
+@return string - O script
+*/
+func bashCompletion(cmds []command, names []string) string {
+	return fmt.Sprintf(`_locenv() {
+  local cur="${COMP_WORDS[COMP_CWORD]}"
+  if [[ $COMP_CWORD -eq 1 ]]; then
+    COMPREPLY=($(compgen -W "%s" -- "$cur"))
+  elif [[ $COMP_CWORD -eq 2 && ( "${COMP_WORDS[1]}" == completion || "${COMP_WORDS[1]}" == hook ) ]]; then
+    COMPREPLY=($(compgen -W "%s" -- "$cur"))
+  fi
+}
+complete -F _locenv locenv
+`, strings.Join(commandNames(cmds), " "), strings.Join(names, " "))
+}
+
+/*
+zshCompletion gera o script de autocompletar do zsh, com a descrição de cada subcomando
+
+@param cmds []command - Os subcomandos
+@param names []string - Os nomes dos shells
+
+@return string - O script
+*/
+func zshCompletion(cmds []command, names []string) string {
+	var described []string
+	for _, cmd := range cmds {
+		described = append(described, fmt.Sprintf("'%s:%s'", cmd.name, strings.ReplaceAll(cmd.summary, ":", `\:`)))
+	}
+
+	return fmt.Sprintf(`#compdef locenv
+_locenv() {
+  local -a commands
+  commands=(%s)
+  if (( CURRENT == 2 )); then
+    _describe 'comando' commands
+  elif (( CURRENT == 3 )) && [[ $words[2] == (completion|hook) ]]; then
+    _values 'shell' %s
+  fi
+}
+compdef _locenv locenv
+`, strings.Join(described, " "), strings.Join(names, " "))
+}
+
+/*
+fishCompletion gera os comandos complete do fish, com a descrição de cada subcomando
+
+@param cmds []command - Os subcomandos
+@param names []string - Os nomes dos shells
+
+@return string - O script
+*/
+func fishCompletion(cmds []command, names []string) string {
+	var b strings.Builder
+	b.WriteString("complete -c locenv -f\n")
+	for _, cmd := range cmds {
+		fmt.Fprintf(&b, "complete -c locenv -n __fish_use_subcommand -a %s -d %s\n", cmd.name, fishQuote(cmd.summary))
+	}
+	fmt.Fprintf(&b, "complete -c locenv -n '__fish_seen_subcommand_from completion hook' -a '%s'\n", strings.Join(names, " "))
+
+	return b.String()
+}
+
+/*
+powershellCompletion gera o Register-ArgumentCompleter do PowerShell para o locenv
+
+@param cmds []command - Os subcomandos
+@param names []string - Os nomes dos shells
+
+@return string - O script
+*/
+func powershellCompletion(cmds []command, names []string) string {
+	quote := func(names []string) string {
+		quoted := make([]string, len(names))
+		for i, name := range names {
+			quoted[i] = powershellQuote(name)
+		}
+		return strings.Join(quoted, ", ")
+	}
+
+	return fmt.Sprintf(`Register-ArgumentCompleter -Native -CommandName locenv -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    $words = @($commandAst.CommandElements | ForEach-Object { $_.ToString() })
+    if ($words.Count -eq 1 -or ($words.Count -eq 2 -and $wordToComplete)) {
+        $candidates = @(%s)
+    } elseif ($words[1] -in @('completion', 'hook')) {
+        $candidates = @(%s)
+    } else {
+        $candidates = @()
+    }
+    $candidates | Where-Object { $_ -like "$wordToComplete*" } | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`, quote(commandNames(cmds)), quote(names))
+}
+
+/*
+commandNames retorna os nomes dos subcomandos, incluindo help
+
+@param cmds []command - Os subcomandos
+
+@return []string - Os nomes, na ordem de cmds
+*/
+func commandNames(cmds []command) []string {
+	names := make([]string, 0, len(cmds)+1)
+	for _, cmd := range cmds {
+		names = append(names, cmd.name)
+	}
+
+	return append(names, "help")
+}
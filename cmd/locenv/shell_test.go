@@ -0,0 +1,161 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// quotingCases são os valores que não podem escapar das aspas em nenhum shell.
+var quotingCases = []string{
+	"",
+	"simples",
+	"com espaço e $HOME e `cmd` e $(cmd)",
+	"it's",
+	`barra \ invertida \'`,
+	"’; Write-Host PWNED; ’",
+	"‘a’ ‚b‛ 'c'",
+	"linha\nnova",
+}
+
+/*
+unquoteSingle interpreta uma string entre aspas simples, retornando o conteúdo e o restante da entrada
+
+@param s string - A entrada, começando pela aspa de abertura
+@param isQuote func(rune) bool - Os caracteres que abrem e fecham a string
+@param escape func(s []rune) (string, int) - Trata um escape no início de s, retornando o texto e o número de caracteres consumidos, ou 0
+
+@return string - O conteúdo
+@return string - O que vem depois da aspa de fechamento
+@return bool - Se a string foi fechada
+*/
+func unquoteSingle(s string, isQuote func(rune) bool, escape func(s []rune) (string, int)) (string, string, bool) {
+	runes := []rune(s)
+	if len(runes) == 0 || !isQuote(runes[0]) {
+		return "", s, false
+	}
+
+	var b strings.Builder
+	for i := 1; i < len(runes); i++ {
+		if escape != nil {
+			if text, n := escape(runes[i:]); n > 0 {
+				b.WriteString(text)
+				i += n - 1
+				continue
+			}
+		}
+		if isQuote(runes[i]) {
+			return b.String(), string(runes[i+1:]), true
+		}
+		b.WriteRune(runes[i])
+	}
+
+	return "", "", false
+}
+
+/*
+TestPowershellQuote verifica se nenhum valor encerra a string entre aspas simples do PowerShell antes do fim.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestPowershellQuote(t *testing.T) {
+	isQuote := func(r rune) bool { return r == '\'' || (r >= '‘' && r <= '‛') }
+	escape := func(s []rune) (string, int) {
+		if len(s) >= 2 && isQuote(s[0]) && isQuote(s[1]) {
+			return string(s[0]), 2
+		}
+		return "", 0
+	}
+
+	for _, value := range quotingCases {
+		quoted := powershellQuote(value)
+		got, rest, ok := unquoteSingle(quoted, isQuote, escape)
+		if !ok || rest != "" || got != value {
+			t.Errorf("powershellQuote(%q) = %s: interpretado como %q, restante %q", value, quoted, got, rest)
+		}
+	}
+}
+
+/*
+TestFishQuote verifica se nenhum valor encerra a string entre aspas simples do fish antes do fim.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestFishQuote(t *testing.T) {
+	isQuote := func(r rune) bool { return r == '\'' }
+	escape := func(s []rune) (string, int) {
+		if len(s) >= 2 && s[0] == '\\' && (s[1] == '\\' || s[1] == '\'') {
+			return string(s[1]), 2
+		}
+		return "", 0
+	}
+
+	for _, value := range quotingCases {
+		quoted := fishQuote(value)
+		got, rest, ok := unquoteSingle(quoted, isQuote, escape)
+		if !ok || rest != "" || got != value {
+			t.Errorf("fishQuote(%q) = %s: interpretado como %q, restante %q", value, quoted, got, rest)
+		}
+	}
+}
+
+/*
+TestPosixQuote verifica se bash e zsh recebem o valor intacto, com o sh do sistema quando disponível.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestPosixQuote(t *testing.T) {
+	for _, value := range quotingCases {
+		quoted := posixQuote(value)
+
+		var got strings.Builder
+		rest := quoted
+		for rest != "" {
+			if strings.HasPrefix(rest, `\'`) {
+				got.WriteByte('\'')
+				rest = rest[2:]
+				continue
+			}
+			part, after, ok := unquoteSingle(rest, func(r rune) bool { return r == '\'' }, nil)
+			if !ok {
+				t.Fatalf("posixQuote(%q) = %s: aspas sem fechamento", value, quoted)
+			}
+			got.WriteString(part)
+			rest = after
+		}
+		if got.String() != value {
+			t.Errorf("posixQuote(%q) = %s: interpretado como %q", value, quoted, got.String())
+		}
+	}
+
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh não encontrado")
+	}
+	for _, value := range quotingCases {
+		out, err := exec.Command(sh, "-c", "printf %s "+posixQuote(value)).Output()
+		if err != nil || string(out) != value {
+			t.Errorf("sh interpretou %s como %q (%v)", posixQuote(value), out, err)
+		}
+	}
+}
+
+/*
+TestShellSetQuotesValues verifica o comando completo de cada shell para um valor com aspas tipográficas.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestShellSetQuotesValues(t *testing.T) {
+	value := "’; Write-Host PWNED; ’"
+	want := map[string]string{
+		"bash":       "export KEY='’; Write-Host PWNED; ’';",
+		"zsh":        "export KEY='’; Write-Host PWNED; ’';",
+		"fish":       "set -gx KEY '’; Write-Host PWNED; ’';",
+		"powershell": "$env:KEY = '’’; Write-Host PWNED; ’’'",
+	}
+	for _, sh := range shells {
+		if line := sh.set("KEY", value); line != want[sh.name] {
+			t.Errorf("comando inesperado para %s: %s", sh.name, line)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+optionsFlag é uma opção de linha de comando repetível no formato chave=valor
+
+A opção -option path=/mnt/.env.{env} -option region=sa-east-1 produz as opções passadas a config.OpenStore.
+*/
+type optionsFlag map[string]string
+
+// String formata as opções no formato aceito por Set.
+func (o optionsFlag) String() string {
+	var items []string
+	for key, value := range o {
+		items = append(items, key+"="+value)
+	}
+
+	return strings.Join(items, ",")
+}
+
+// Set interpreta uma opção chave=valor.
+func (o optionsFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || strings.TrimSpace(key) == "" {
+		return fmt.Errorf("esperado chave=valor, obtido %q", value)
+	}
+	o[strings.TrimSpace(key)] = val
+
+	return nil
+}
+
+/*
+runSync executa o subcomando sync, que compara o arquivo .env local com uma fonte de verdade remota
+
+A loja é escolhida com -provider entre as registradas com config.RegisterStore (file e command, além das
+importadas pela aplicação), com as opções de -option. Com -check, o padrão, o subcomando lista as diferenças,
+com os segredos mascarados. Com -pull, grava no arquivo local os valores da loja que faltam ou diferem, e com
+-push envia à loja os valores locais que faltam ou diferem; as duas operações mostram as diferenças e pedem
+confirmação, dispensada com -yes. Os valores locais enc: são decifrados com o backend de -backend e a chave de
+-key, -key-file ou $LOCENV_KEY (ou com -decrypt-cmd) antes da comparação: -push envia o valor em claro, nunca o
+texto cifrado, e -pull não substitui as linhas com enc: ou @generate. Com -output json, github ou gitlab, cada diferença é um problema com a
+variável, as diferenças e as variáveis sincronizadas ficam em data, e -pull e -push exigem -yes.
+
+@param args []string - Os argumentos do subcomando
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - 0 se não houver diferenças ou a sincronização for concluída, 1 se houver diferenças com -check, 2 em caso de erro
+*/
+func runSync(args []string, stdout io.Writer, stderr io.Writer) int {
+	options := optionsFlag{}
+	flags := flag.NewFlagSet("sync", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	provider := flags.String("provider", "", "nome da loja remota (registradas: "+strings.Join(config.Stores(), ", ")+")")
+	flags.Var(options, "option", "opção da loja no formato chave=valor (repetível)")
+	file := flags.String("file", "", "arquivo .env local (padrão: o arquivo do ambiente atual)")
+	env := flags.String("env", "", "ambiente enviado à loja (padrão: APP_ENV)")
+	flags.Bool("check", false, "apenas lista as diferenças (padrão)")
+	pull := flags.Bool("pull", false, "grava no arquivo local os valores da loja")
+	push := flags.Bool("push", false, "envia à loja os valores do arquivo local")
+	yes := flags.Bool("yes", false, "não pede confirmação para -pull e -push")
+	secretKeys := flags.String("secret-keys", strings.Join(config.DefaultSecretPatterns, ","), "padrões de nomes de variáveis secretas, separados por vírgula")
+	cf := newCipherFlags(flags)
+	format := outputFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if err := applyProjectConfig(flags); err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 2
+	}
+	structured, ok := structuredOutput(*format, stderr)
+	if !ok {
+		return 2
+	}
+	if *provider == "" {
+		fmt.Fprintln(stderr, "locenv: informe a loja com -provider")
+		return 2
+	}
+	if *pull && *push {
+		fmt.Fprintln(stderr, "locenv: use -pull ou -push, não os dois")
+		return 2
+	}
+	if structured && (*pull || *push) && !*yes {
+		fmt.Fprintln(stderr, "locenv: fora de -output text, -pull e -push exigem -yes")
+		return 2
+	}
+
+	loader := config.NewEnvLoader(config.WithSilent()).(*config.FileEnvLoader)
+	if *env == "" {
+		*env = loader.GetEnv()
+	}
+	if *file == "" {
+		envFile, err := loader.EnvFile()
+		if err != nil {
+			fmt.Fprintf(stderr, "locenv: %s\n", err)
+			return 2
+		}
+		*file = envFile
+	}
+
+	store, err := config.OpenStore(*provider, options)
+	if err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 2
+	}
+
+	var opts []config.Option
+	_, decrypter, cipherErr := cf.cipher()
+	if cipherErr == nil {
+		opts = append(opts, config.WithDecrypter(*cf.backend, decrypter))
+	}
+
+	diff, err := config.CompareWithStore(*file, store, *env, splitList(*secretKeys), opts...)
+	if err != nil {
+		var decryptErr *config.DecryptError
+		if errors.As(err, &decryptErr) && cipherErr != nil {
+			err = fmt.Errorf("%w (%s)", err, cipherErr)
+		}
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 2
+	}
+	if structured {
+		return syncReport(diff, store, *file, *provider, *env, *pull, *push, *format, opts, stdout, stderr)
+	}
+	if len(diff.Changes) == 0 {
+		fmt.Fprintf(stdout, "%s está em dia com a loja %s (ambiente %s).\n", *file, *provider, *env)
+		return 0
+	}
+
+	fmt.Fprintf(stdout, "Diferenças entre %s (-) e a loja %s (+), ambiente %s:\n%s\n", *file, *provider, *env, diff)
+	if !*pull && !*push {
+		return 1
+	}
+
+	action := "Gravar em " + *file + " os valores da loja"
+	if *push {
+		action = "Enviar à loja " + *provider + " os valores de " + *file
+	}
+	if !*yes && !confirm(stdout, action) {
+		fmt.Fprintln(stdout, "Nada foi alterado.")
+		return 1
+	}
+
+	var keys []string
+	if *pull {
+		keys, err = config.PullFromStore(*file, store, *env, opts...)
+	} else {
+		keys, err = config.PushToStore(*file, store, *env, opts...)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 2
+	}
+	fmt.Fprintf(stdout, "%d variável(is) sincronizada(s): %s\n", len(keys), strings.Join(keys, ", "))
+
+	return 0
+}
+
+/*
+syncReport conclui o subcomando sync com um relatório de -output
+
+Com -pull ou -push, as diferenças são sincronizadas e deixam de ser problemas; sem eles, cada diferença é um
+problema.
+
+@param diff config.ConfigDiff - As diferenças entre o arquivo local e a loja
+@param store config.SecretStore - A loja remota
+@param file string - O arquivo .env local
+@param provider string - O nome da loja
+@param env string - O ambiente enviado à loja
+@param pull bool - Se os valores da loja devem ser gravados no arquivo local
+@param push bool - Se os valores locais devem ser enviados à loja
+@param format string - O formato de -output
+@param opts []config.Option - As opções que decifram os valores locais
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - O código de saída do subcomando
+*/
+func syncReport(diff config.ConfigDiff, store config.SecretStore, file, provider, env string, pull, push bool, format string, opts []config.Option, stdout io.Writer, stderr io.Writer) int {
+	synced := []string{}
+	if len(diff.Changes) > 0 && (pull || push) {
+		var err error
+		if pull {
+			synced, err = config.PullFromStore(file, store, env, opts...)
+		} else {
+			synced, err = config.PushToStore(file, store, env, opts...)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "locenv: %s\n", err)
+			return 2
+		}
+	}
+
+	r := newReport("sync")
+	if diff.Changes == nil {
+		diff.Changes = []config.KeyChange{}
+	}
+	if !pull && !push {
+		for _, change := range diff.Changes {
+			p := r.add(severityError, fmt.Sprintf("variável %s %s na loja %s", change.Key, syncKinds[change.Kind], provider))
+			p.File, p.Key = file, change.Key
+		}
+	}
+	r.Data = struct {
+		File     string             `json:"file"`
+		Provider string             `json:"provider"`
+		Env      string             `json:"env"`
+		Changes  []config.KeyChange `json:"changes"`
+		Synced   []string           `json:"synced"`
+	}{file, provider, env, diff.Changes, synced}
+
+	return r.write(stdout, stderr, format)
+}
+
+// syncKinds descreve cada tipo de diferença do ponto de vista do arquivo local, que é a configuração antiga.
+var syncKinds = map[config.DiffKind]string{
+	config.DiffAdded:   "existe apenas",
+	config.DiffRemoved: "está ausente",
+	config.DiffChanged: "tem outro valor",
+}
+
+/*
+confirm pergunta ao usuário se uma ação deve ser executada
+
+@param w io.Writer - A saída em que a pergunta é escrita
+@param action string - A descrição da ação
+
+@return bool - Se o usuário respondeu s ou sim
+*/
+func confirm(w io.Writer, action string) bool {
+	fmt.Fprintf(w, "%s? [s/N] ", action)
+	answer, _ := bufio.NewReader(stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "s" || answer == "sim" || answer == "y" || answer == "yes"
+}
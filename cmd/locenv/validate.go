@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+runValidate executa o subcomando validate, que verifica o ambiente atual ou, com -all, todos os ambientes
+
+As variáveis obrigatórias e os tipos vêm do esquema (-schema) e de -require. Com -all, cada arquivo
+.env.<ambiente> de -dir é validado e o relatório mostra uma matriz com as variáveis declaradas em cada
+ambiente, seguida dos problemas. Com -strict, uma variável que existe em um ambiente e falta em outro também
+é um problema. Com -output json, o relatório segue o esquema de report, com a matriz em data.
+
+@param args []string - Os argumentos do subcomando
+@param stdout io.Writer - A saída padrão
+@param stderr io.Writer - A saída de erros
+
+@return int - 0 se a validação passar, 1 se houver problemas, 2 em caso de erro
+*/
+func runValidate(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	all := flags.Bool("all", false, "valida todos os arquivos .env.<ambiente> de -dir")
+	dir := flags.String("dir", ".", "diretório com os arquivos .env usado por -all")
+	schema := flags.String("schema", ".env.example", "esquema anotado com as variáveis obrigatórias, secretas e tipadas (ignorado se não existir)")
+	require := flags.String("require", "", "variáveis obrigatórias adicionais, separadas por vírgula")
+	strict := flags.Bool("strict", false, "com -all, falha se uma variável faltar em algum ambiente")
+	format := outputFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if err := applyProjectConfig(flags); err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 2
+	}
+	structured, ok := structuredOutput(*format, stderr)
+	if !ok {
+		return 2
+	}
+
+	opts := []config.Option{config.WithSilent(), config.WithRequired(splitList(*require)...)}
+	if _, err := os.Stat(*schema); err == nil {
+		specs, err := config.LoadSchema(*schema)
+		if err != nil {
+			fmt.Fprintf(stderr, "erro ao ler o esquema %s: %s\n", *schema, err)
+			return 2
+		}
+		opts = append(opts, config.WithSchema(specs))
+	}
+
+	if !*all {
+		loader := config.NewEnvLoader(opts...).(*config.FileEnvLoader)
+		_, err := loader.Plan()
+		var validation *config.ValidationError
+		if err != nil && !errors.As(err, &validation) {
+			fmt.Fprintf(stderr, "locenv: %s\n", err)
+			return 2
+		}
+		if structured {
+			r := newReport("validate")
+			if validation != nil {
+				for _, problem := range validation.Problems {
+					r.add(severityError, problem)
+				}
+			}
+			r.Data = struct {
+				Env string `json:"env"`
+			}{loader.GetEnv()}
+			return r.write(stdout, stderr, *format)
+		}
+		if validation != nil {
+			fmt.Fprintf(stdout, "Ambiente %s inválido:\n  %s\n", loader.GetEnv(), strings.Join(validation.Problems, "\n  "))
+			return 1
+		}
+		fmt.Fprintf(stdout, "Ambiente %s válido.\n", loader.GetEnv())
+		return 0
+	}
+
+	matrix, err := config.ValidateAll(*dir, opts...)
+	if err != nil {
+		fmt.Fprintf(stderr, "locenv: %s\n", err)
+		return 2
+	}
+	if structured {
+		return matrixReport(matrix, *strict).write(stdout, stderr, *format)
+	}
+	fmt.Fprintln(stdout, matrix)
+
+	failed := !matrix.OK()
+	if *strict {
+		for _, env := range matrix.Envs {
+			if len(env.Missing) > 0 {
+				fmt.Fprintf(stdout, "\n%s: variáveis ausentes: %s", env.Env, strings.Join(env.Missing, ", "))
+				failed = true
+			}
+		}
+		if !matrix.Complete() {
+			fmt.Fprintln(stdout)
+		}
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+/*
+matrixReport converte a matriz de validate -all para o esquema de -output json
+
+@param matrix *config.ValidationMatrix - A matriz de ValidateAll
+@param strict bool - Se as variáveis ausentes em algum ambiente são problemas
+
+@return *report - O relatório, com a matriz em data e o ambiente de cada problema
+*/
+func matrixReport(matrix *config.ValidationMatrix, strict bool) *report {
+	r := newReport("validate")
+	for _, env := range matrix.Envs {
+		for _, problem := range env.Problems {
+			r.add(severityError, problem).Env = env.Env
+		}
+		severity := severityWarning
+		if strict {
+			severity = severityError
+		}
+		for _, key := range env.Missing {
+			p := r.add(severity, "variável "+key+" ausente")
+			p.Key, p.Env = key, env.Env
+		}
+	}
+	r.Data = matrix
+
+	return r
+}
@@ -0,0 +1,212 @@
+package config
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+WithAssets carrega os arquivos .env de um pacote de recursos do aplicativo, como em Android e iOS
+
+É a combinação de WithFS, a partir da raiz de fsys, com WithNoProcessEnv: os runtimes móveis restringem a
+manipulação do ambiente do processo, então os valores ficam apenas no carregador. Use com um embed.FS, com um
+fs.FS que leia o pacote de recursos da plataforma ou com um AssetBundle preenchido a partir de bytes. Como APP_ENV
+normalmente não pode ser definido em um aplicativo, o ambiente costuma ser informado com WithEnv.
+
+@param fsys fs.FS - Os recursos do aplicativo
+
+@return Option - A opção que habilita o modo de recursos
+*/
+func WithAssets(fsys fs.FS) Option {
+	return func(f *FileEnvLoader) {
+		WithFS(fsys, ".")(f)
+		WithNoProcessEnv()(f)
+	}
+}
+
+/*
+WithEnv define o ambiente do carregador, em vez de lê-lo de APP_ENV
+
+@param env string - O ambiente, normalizado como APP_ENV
+
+@return Option - A opção que define o ambiente
+*/
+func WithEnv(env string) Option {
+	return func(f *FileEnvLoader) {
+		f.Env = normalizeEnv(env)
+		f.envPinned = true
+	}
+}
+
+/*
+AssetBundle é um sistema de arquivos em memória preenchido a partir de bytes
+
+Os métodos usam apenas strings e []byte, de modo que o código nativo de um aplicativo (por meio de gomobile bind)
+pode entregar o conteúdo dos recursos lidos pela plataforma. AssetBundle implementa fs.FS e fs.ReadDirFS e é
+seguro para uso concorrente.
+*/
+type AssetBundle struct {
+	mu    sync.RWMutex
+	files map[string][]byte
+}
+
+// NewAssetBundle cria um AssetBundle vazio.
+func NewAssetBundle() *AssetBundle {
+	return &AssetBundle{files: make(map[string][]byte)}
+}
+
+/*
+Add adiciona ou substitui um arquivo do pacote
+
+@param name string - O caminho do arquivo, com barras (ex.: ".env.production" ou "config/.env.production")
+@param content []byte - O conteúdo do arquivo, que é copiado
+*/
+func (b *AssetBundle) Add(name string, content []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.files[path.Clean(strings.TrimPrefix(name, "/"))] = append([]byte(nil), content...)
+}
+
+// Open abre um arquivo ou diretório do pacote.
+func (b *AssetBundle) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if content, ok := b.files[name]; ok {
+		return &assetFile{info: assetInfo{name: path.Base(name), size: int64(len(content))}, Reader: bytes.NewReader(content)}, nil
+	}
+
+	entries := b.readDir(name)
+	if entries == nil && name != "." {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return &assetDir{info: assetInfo{name: path.Base(name), dir: true}, entries: entries}, nil
+}
+
+// ReadDir lista o conteúdo de um diretório do pacote, em ordem alfabética.
+func (b *AssetBundle) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	entries := b.readDir(name)
+	if entries == nil && name != "." {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return entries, nil
+}
+
+/*
+readDir lista os filhos imediatos de um diretório, deduzidos dos caminhos dos arquivos
+
+@param dir string - O diretório
+
+@return []fs.DirEntry - Os filhos, em ordem alfabética, ou nil se o diretório não existir
+*/
+func (b *AssetBundle) readDir(dir string) []fs.DirEntry {
+	prefix := dir + "/"
+	if dir == "." {
+		prefix = ""
+	}
+
+	children := make(map[string]fs.DirEntry)
+	for name, content := range b.files {
+		rest, ok := strings.CutPrefix(name, prefix)
+		if !ok || rest == "" {
+			continue
+		}
+		if child, _, isDir := strings.Cut(rest, "/"); isDir {
+			children[child] = fs.FileInfoToDirEntry(assetInfo{name: child, dir: true})
+		} else {
+			children[child] = fs.FileInfoToDirEntry(assetInfo{name: child, size: int64(len(content))})
+		}
+	}
+	if len(children) == 0 {
+		return nil
+	}
+
+	entries := make([]fs.DirEntry, 0, len(children))
+	for _, entry := range children {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	return entries
+}
+
+// assetInfo descreve um arquivo ou diretório de um AssetBundle.
+type assetInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (i assetInfo) Name() string       { return i.name }
+func (i assetInfo) Size() int64        { return i.size }
+func (i assetInfo) ModTime() time.Time { return time.Time{} }
+func (i assetInfo) IsDir() bool        { return i.dir }
+func (i assetInfo) Sys() any           { return nil }
+
+func (i assetInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+// assetFile é um arquivo aberto de um AssetBundle.
+type assetFile struct {
+	*bytes.Reader
+	info assetInfo
+}
+
+func (f *assetFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *assetFile) Close() error               { return nil }
+
+// assetDir é um diretório aberto de um AssetBundle.
+type assetDir struct {
+	info    assetInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *assetDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *assetDir) Close() error               { return nil }
+
+func (d *assetDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+// ReadDir lista as entradas do diretório, como em fs.ReadDirFile.
+func (d *assetDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(rest) {
+		n = len(rest)
+	}
+	d.offset += n
+
+	return rest[:n], nil
+}
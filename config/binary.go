@@ -0,0 +1,158 @@
+package config
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// ExactLength é uma ContentCheck que exige exatamente n bytes, como em uma chave HMAC-SHA256 de 32 bytes.
+func ExactLength(n int) ContentCheck {
+	return func(content []byte) error {
+		if len(content) != n {
+			return fmt.Errorf("o conteúdo deve ter %d bytes, tem %d", n, len(content))
+		}
+		return nil
+	}
+}
+
+// MinLength é uma ContentCheck que exige ao menos n bytes, como em um salt.
+func MinLength(n int) ContentCheck {
+	return func(content []byte) error {
+		if len(content) < n {
+			return fmt.Errorf("o conteúdo deve ter ao menos %d bytes, tem %d", n, len(content))
+		}
+		return nil
+	}
+}
+
+// MaxLength é uma ContentCheck que exige no máximo n bytes.
+func MaxLength(n int) ContentCheck {
+	return func(content []byte) error {
+		if len(content) > n {
+			return fmt.Errorf("o conteúdo deve ter no máximo %d bytes, tem %d", n, len(content))
+		}
+		return nil
+	}
+}
+
+/*
+WithHexContent declara uma variável com conteúdo binário em hexadecimal, validada durante o carregamento
+
+Funciona como WithBase64Content, para chaves e salts gerados com ferramentas como openssl rand -hex 32:
+
+	WithHexContent("HMAC_KEY", ExactLength(32))
+
+@param key string - O nome da variável (ex.: HMAC_KEY)
+@param checks ...ContentCheck - As validações do conteúdo decodificado
+
+@return Option - A opção que declara a variável
+*/
+func WithHexContent(key string, checks ...ContentCheck) Option {
+	return func(f *FileEnvLoader) {
+		f.contents = append(f.contents, contentRule{key: key, hex: true, checks: checks})
+	}
+}
+
+/*
+GetBase64 retorna o conteúdo binário de uma variável codificada em base64, aplicando as validações informadas
+
+São aceitas as codificações padrão e URL, com ou sem preenchimento, e segredos isolados com WithSecretIsolation
+também são lidos. As validações do conteúdo decodificado, como ExactLength, servem para chaves e salts:
+
+	key, err := loader.GetBase64("HMAC_KEY", ExactLength(32))
+
+Quando uma validação falha, o conteúdo é apagado da memória antes do retorno. O valor não faz parte das
+mensagens de erro.
+
+@param key string - O nome da variável
+@param checks ...ContentCheck - As validações do conteúdo decodificado
+
+@return []byte - O conteúdo decodificado
+@return error - ErrKeyNotFound se a variável não existir, ou um erro se o valor for inválido ou alguma validação falhar
+*/
+func (f *FileEnvLoader) GetBase64(key string, checks ...ContentCheck) ([]byte, error) {
+	value, err := f.requireContent(key)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := decodeBase64(key, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return checkContent(key, content, checks)
+}
+
+/*
+GetHex retorna o conteúdo binário de uma variável codificada em hexadecimal, aplicando as validações informadas
+
+São aceitas letras maiúsculas e minúsculas, e o prefixo 0x. Segredos isolados com WithSecretIsolation também
+são lidos. Quando uma validação falha, o conteúdo é apagado da memória antes do retorno.
+
+@param key string - O nome da variável
+@param checks ...ContentCheck - As validações do conteúdo decodificado, como ExactLength
+
+@return []byte - O conteúdo decodificado
+@return error - ErrKeyNotFound se a variável não existir, ou um erro se o valor for inválido ou alguma validação falhar
+*/
+func (f *FileEnvLoader) GetHex(key string, checks ...ContentCheck) ([]byte, error) {
+	value, err := f.requireContent(key)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := decodeHex(key, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return checkContent(key, content, checks)
+}
+
+/*
+decodeHex decodifica um valor em hexadecimal, com ou sem o prefixo 0x
+
+@param key string - O nome da variável, usado na mensagem de erro
+@param value string - O valor
+
+@return []byte - O conteúdo decodificado
+@return error - Um erro se o valor não for hexadecimal válido
+*/
+func decodeHex(key string, value string) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	if prefixed, ok := strings.CutPrefix(value, "0x"); ok {
+		value = prefixed
+	} else {
+		value = strings.TrimPrefix(value, "0X")
+	}
+
+	content, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("variável %s: o valor não é hexadecimal válido", key)
+	}
+
+	return content, nil
+}
+
+/*
+checkContent aplica validações a um conteúdo lido por GetBase64 ou GetHex
+
+@param key string - O nome da variável, usado na mensagem de erro
+@param content []byte - O conteúdo decodificado
+@param checks []ContentCheck - As validações
+
+@return []byte - O conteúdo, se todas as validações passarem
+@return error - O erro da primeira validação que falhar, com o conteúdo já apagado
+*/
+func checkContent(key string, content []byte, checks []ContentCheck) ([]byte, error) {
+	for _, check := range checks {
+		if err := check(content); err != nil {
+			zeroBytes(content)
+			return nil, fmt.Errorf("variável %s: %w", key, err)
+		}
+	}
+
+	return content, nil
+}
@@ -0,0 +1,293 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+RedisOptions são as opções de conexão com o Redis, com os mesmos nomes de campo do redis.Options do go-redis
+
+Addr string - O endereço do servidor (host:porta)
+Username string - O usuário (ACL do Redis 6)
+Password string - A senha
+DB int - O número do banco de dados
+TLS bool - Se a conexão usa TLS (rediss://)
+PoolSize int - O tamanho máximo do pool de conexões
+MinIdleConns int - A quantidade mínima de conexões ociosas
+DialTimeout time.Duration - O tempo máximo para estabelecer uma conexão
+ReadTimeout time.Duration - O tempo máximo de leitura
+WriteTimeout time.Duration - O tempo máximo de escrita
+*/
+type RedisOptions struct {
+	Addr         string
+	Username     string
+	Password     string
+	DB           int
+	TLS          bool
+	PoolSize     int
+	MinIdleConns int
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+/*
+GetRedisOptions lê e valida a URL do Redis de uma variável, completando-a com as variáveis complementares
+
+A URL segue o formato redis://[usuário:senha@]host[:porta][/banco], ou rediss:// para TLS. As variáveis
+complementares usam o nome da variável sem o sufixo _URL: com REDIS_URL, são lidas REDIS_POOL_SIZE (padrão: 10
+por CPU, como no go-redis), REDIS_MIN_IDLE_CONNS, REDIS_DIAL_TIMEOUT (padrão: 5s), REDIS_READ_TIMEOUT (padrão: 3s)
+e REDIS_WRITE_TIMEOUT (padrão: o tempo de leitura). As mensagens de erro mascaram a senha.
+
+@param r Reader - A configuração
+@param key string - O nome da variável com a URL (ex.: REDIS_URL)
+
+@return RedisOptions - As opções de conexão
+@return error - ErrKeyNotFound se a variável não existir, ou um erro se a URL ou alguma variável complementar for inválida
+*/
+func GetRedisOptions(r Reader, key string) (RedisOptions, error) {
+	u, err := brokerURL(r, key, "redis", "rediss")
+	if err != nil {
+		return RedisOptions{}, err
+	}
+
+	opts := RedisOptions{
+		Addr:        hostPort(u, "6379"),
+		TLS:         u.Scheme == "rediss",
+		PoolSize:    10 * runtime.GOMAXPROCS(0),
+		DialTimeout: 5 * time.Second,
+		ReadTimeout: 3 * time.Second,
+	}
+	if u.User != nil {
+		opts.Username = u.User.Username()
+		opts.Password, _ = u.User.Password()
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		if opts.DB, err = strconv.Atoi(db); err != nil || opts.DB < 0 {
+			return RedisOptions{}, fmt.Errorf("variável %s: banco de dados %q inválido em %s", key, db, RedactDSN(u.String()))
+		}
+	}
+
+	prefix := strings.TrimSuffix(key, "_URL")
+	c := companions{r: r, prefix: prefix}
+	c.int("POOL_SIZE", &opts.PoolSize)
+	c.int("MIN_IDLE_CONNS", &opts.MinIdleConns)
+	c.duration("DIAL_TIMEOUT", &opts.DialTimeout)
+	c.duration("READ_TIMEOUT", &opts.ReadTimeout)
+	opts.WriteTimeout = opts.ReadTimeout
+	c.duration("WRITE_TIMEOUT", &opts.WriteTimeout)
+	if opts.PoolSize <= 0 {
+		c.problems = append(c.problems, fmt.Sprintf("variável %s_POOL_SIZE deve ser positiva", prefix))
+	}
+	if opts.MinIdleConns > opts.PoolSize {
+		c.problems = append(c.problems, fmt.Sprintf("variável %s_MIN_IDLE_CONNS maior que %s_POOL_SIZE", prefix, prefix))
+	}
+
+	return opts, c.err()
+}
+
+/*
+AMQPURL são os componentes de uma URL do AMQP, com os mesmos nomes de campo do amqp.URI do amqp091-go
+
+Scheme string - amqp ou amqps
+Host string - O endereço do servidor
+Port int - A porta do servidor
+Username string - O usuário
+Password string - A senha
+Vhost string - O virtual host
+Heartbeat time.Duration - O intervalo de heartbeat
+ChannelMax int - A quantidade máxima de canais, zero para o limite do servidor
+Prefetch int - A quantidade de mensagens entregues sem confirmação por consumidor
+*/
+type AMQPURL struct {
+	Scheme     string
+	Host       string
+	Port       int
+	Username   string
+	Password   string
+	Vhost      string
+	Heartbeat  time.Duration
+	ChannelMax int
+	Prefetch   int
+}
+
+/*
+GetAMQPURL lê e valida a URL do AMQP de uma variável, completando-a com as variáveis complementares
+
+A URL segue o formato amqp://[usuário:senha@]host[:porta][/vhost], ou amqps:// para TLS, com os padrões do
+RabbitMQ (guest/guest, vhost /, portas 5672 e 5671). Com AMQP_URL, as variáveis complementares são
+AMQP_HEARTBEAT (padrão: 10s), AMQP_CHANNEL_MAX e AMQP_PREFETCH (padrão: 10). As mensagens de erro mascaram a senha.
+
+@param r Reader - A configuração
+@param key string - O nome da variável com a URL (ex.: AMQP_URL)
+
+@return AMQPURL - Os componentes da URL
+@return error - ErrKeyNotFound se a variável não existir, ou um erro se a URL ou alguma variável complementar for inválida
+*/
+func GetAMQPURL(r Reader, key string) (AMQPURL, error) {
+	u, err := brokerURL(r, key, "amqp", "amqps")
+	if err != nil {
+		return AMQPURL{}, err
+	}
+
+	a := AMQPURL{Scheme: u.Scheme, Host: u.Hostname(), Port: 5672, Username: "guest", Password: "guest", Vhost: "/", Heartbeat: 10 * time.Second, Prefetch: 10}
+	if u.Scheme == "amqps" {
+		a.Port = 5671
+	}
+	if port := u.Port(); port != "" {
+		if a.Port, err = strconv.Atoi(port); err != nil {
+			return AMQPURL{}, fmt.Errorf("variável %s: porta %q inválida em %s", key, port, RedactDSN(u.String()))
+		}
+	}
+	if u.User != nil {
+		a.Username = u.User.Username()
+		a.Password, _ = u.User.Password()
+	}
+	if vhost := strings.TrimPrefix(u.Path, "/"); vhost != "" {
+		a.Vhost = vhost
+	}
+
+	c := companions{r: r, prefix: strings.TrimSuffix(key, "_URL")}
+	c.duration("HEARTBEAT", &a.Heartbeat)
+	c.int("CHANNEL_MAX", &a.ChannelMax)
+	c.int("PREFETCH", &a.Prefetch)
+	if a.Prefetch < 0 || a.ChannelMax < 0 {
+		c.problems = append(c.problems, fmt.Sprintf("variáveis %s_PREFETCH e %s_CHANNEL_MAX não podem ser negativas", c.prefix, c.prefix))
+	}
+
+	return a, c.err()
+}
+
+/*
+String retorna a URL completa, no formato esperado pelo amqp.Dial
+
+Como a senha é incluída, o resultado não deve ser registrado em logs; use Redacted.
+
+@return string - A URL
+*/
+func (a AMQPURL) String() string {
+	return a.format(url.UserPassword(a.Username, a.Password))
+}
+
+/*
+Redacted retorna a URL com a senha mascarada, própria para logs
+
+@return string - A URL mascarada
+*/
+func (a AMQPURL) Redacted() string {
+	return a.format(url.UserPassword(a.Username, maskedValue))
+}
+
+// format monta a URL com as credenciais informadas.
+func (a AMQPURL) format(user *url.Userinfo) string {
+	u := url.URL{Scheme: a.Scheme, User: user, Host: net.JoinHostPort(a.Host, strconv.Itoa(a.Port)), Path: "/" + a.Vhost}
+	u.RawPath = "/" + url.PathEscape(a.Vhost)
+
+	return u.String()
+}
+
+/*
+brokerURL lê e interpreta a URL de um serviço a partir de uma variável
+
+@param r Reader - A configuração
+@param key string - O nome da variável
+@param schemes ...string - Os esquemas aceitos
+
+@return *url.URL - A URL interpretada
+@return error - ErrKeyNotFound se a variável não existir, ou um erro se a URL for inválida
+*/
+func brokerURL(r Reader, key string, schemes ...string) (*url.URL, error) {
+	raw, ok := lookupValue(r, key)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("variável %s: URL %s inválida", key, RedactDSN(raw))
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme && u.Hostname() != "" {
+			return u, nil
+		}
+	}
+
+	return nil, fmt.Errorf("variável %s: URL %s inválida, esperado %s://host", key, RedactDSN(raw), strings.Join(schemes, ":// ou "))
+}
+
+/*
+hostPort retorna o endereço host:porta de uma URL, com a porta padrão quando ela não é informada
+
+@param u *url.URL - A URL
+@param defaultPort string - A porta padrão
+
+@return string - O endereço
+*/
+func hostPort(u *url.URL, defaultPort string) string {
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+/*
+companions lê as variáveis complementares de uma URL, reunindo os problemas encontrados
+
+r Reader - A configuração
+prefix string - O prefixo das variáveis complementares
+problems []string - Os problemas encontrados
+*/
+type companions struct {
+	r        Reader
+	prefix   string
+	problems []string
+}
+
+// key retorna o nome completo de uma variável complementar.
+func (c *companions) key(name string) string {
+	if c.prefix == "" {
+		return name
+	}
+
+	return c.prefix + "_" + name
+}
+
+// int lê uma variável complementar inteira, mantendo o padrão quando ela não existe.
+func (c *companions) int(name string, dst *int) {
+	n, err := c.r.GetInt(c.key(name))
+	switch {
+	case err == nil:
+		*dst = n
+	case !errors.Is(err, ErrKeyNotFound):
+		c.problems = append(c.problems, err.Error())
+	}
+}
+
+// duration lê uma variável complementar de duração, mantendo o padrão quando ela não existe.
+func (c *companions) duration(name string, dst *time.Duration) {
+	d, err := c.r.GetDuration(c.key(name))
+	switch {
+	case err == nil:
+		*dst = d
+	case !errors.Is(err, ErrKeyNotFound):
+		c.problems = append(c.problems, err.Error())
+	}
+}
+
+// err retorna um *ValidationError com os problemas encontrados, ou nil.
+func (c *companions) err() error {
+	if len(c.problems) > 0 {
+		return &ValidationError{Problems: c.problems}
+	}
+
+	return nil
+}
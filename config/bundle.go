@@ -0,0 +1,248 @@
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+/*
+BundleFormat é o formato dos arquivos gerados por RenderBundle
+*/
+type BundleFormat string
+
+const (
+	// BundleDotenv gera <ambiente>/config.env e, com IncludeSecrets, <ambiente>/secrets.env.
+	BundleDotenv BundleFormat = "dotenv"
+	// BundleKubernetes gera <ambiente>/configmap.yaml e, com IncludeSecrets, <ambiente>/secret.yaml.
+	BundleKubernetes BundleFormat = "kubernetes"
+	// BundleHelm gera <ambiente>/values.yaml e, com IncludeSecrets, <ambiente>/secrets.yaml, no formato de values do Helm.
+	BundleHelm BundleFormat = "helm"
+)
+
+// bundleHeader é o comentário no início de cada arquivo gerado por RenderBundle.
+const bundleHeader = "# Gerado por locenv render a partir de %s; não edite manualmente.\n"
+
+/*
+BundleOptions configura RenderBundle
+
+Format BundleFormat - O formato dos arquivos; vazio para BundleDotenv
+Name string - Com BundleKubernetes, o nome do ConfigMap e do Secret; vazio para app
+Namespace string - Com BundleKubernetes, o namespace do ConfigMap e do Secret, ou vazio
+IncludeSecrets bool - Se os segredos são gerados; sem ela, são omitidos e listados em Bundle.Omitted
+Helm HelmMapping - Com BundleHelm, os caminhos de values.yaml das variáveis
+*/
+type BundleOptions struct {
+	Format         BundleFormat
+	Name           string
+	Namespace      string
+	IncludeSecrets bool
+	Helm           HelmMapping
+}
+
+/*
+BundleFile é um arquivo gerado por RenderBundle
+
+Path string - O caminho relativo ao diretório de destino, com "/" como separador
+Content []byte - O conteúdo
+Secret bool - Se o arquivo contém segredos
+*/
+type BundleFile struct {
+	Path    string
+	Content []byte
+	Secret  bool
+}
+
+/*
+Bundle é a configuração resolvida de todos os ambientes de um diretório, pronta para um repositório GitOps
+
+Envs []string - Os ambientes, em ordem alfabética
+Files []BundleFile - Os arquivos, ordenados pelo caminho
+Omitted map[string][]string - Os segredos omitidos de cada ambiente, em ordem alfabética
+*/
+type Bundle struct {
+	Envs    []string
+	Files   []BundleFile
+	Omitted map[string][]string
+}
+
+/*
+RenderBundle resolve cada arquivo .env.<ambiente> de um diretório e gera um diretório por ambiente
+
+Os ambientes são os de ValidateAll e são resolvidos da mesma forma, com as regras de opts, sem o ambiente do
+processo, e sem gravar nada nem perguntar valores, como em Plan; um ambiente que não resolve interrompe a geração. A saída é determinística: as variáveis aparecem em
+ordem alfabética, sem datas nem outras informações do ambiente de quem gera, de modo que um commit só muda quando
+a configuração muda. Os segredos (de WithSecretKeys ou cifrados) vão para um arquivo separado, com permissão 0600
+em Write, e só são gerados com IncludeSecrets. Com BundleHelm, as variáveis formam um values.yaml por ambiente,
+nos caminhos de Helm, para que o chart e o ambiente da aplicação deixem de ser mantidos em dois lugares.
+
+@param dir string - O diretório com os arquivos .env
+@param bundle BundleOptions - O formato e os nomes dos arquivos gerados
+@param opts ...Option - As opções aplicadas a cada ambiente
+
+@return *Bundle - Os arquivos gerados
+@return error - Um erro se o diretório não tiver ambientes, o formato for desconhecido ou um ambiente não resolver
+*/
+func RenderBundle(dir string, bundle BundleOptions, opts ...Option) (*Bundle, error) {
+	if bundle.Format == "" {
+		bundle.Format = BundleDotenv
+	}
+	if bundle.Format != BundleDotenv && bundle.Format != BundleKubernetes && bundle.Format != BundleHelm {
+		return nil, fmt.Errorf("formato de bundle desconhecido: %q", bundle.Format)
+	}
+	if err := bundle.Helm.validate(); err != nil {
+		return nil, err
+	}
+	if bundle.Name == "" {
+		bundle.Name = "app"
+	}
+
+	envs, err := profilesIn(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(envs) == 0 {
+		return nil, fmt.Errorf("%w em %s", ErrEnvNotFound, dir)
+	}
+
+	b := &Bundle{Envs: envs, Omitted: make(map[string][]string)}
+	fsys := os.DirFS(dir)
+	for _, env := range envs {
+		loaderOpts := append(append([]Option(nil), opts...), WithFS(fsys, "."), WithEnv(env), WithNoProcessEnv(), WithSilent())
+		res, err := NewEnvLoader(loaderOpts...).(*FileEnvLoader).dryResolve()
+		if err != nil {
+			return nil, fmt.Errorf("ambiente %s: %w", env, err)
+		}
+
+		values := make(map[string]string, len(res.values))
+		for key, value := range res.values {
+			if _, secret := res.secrets[key]; !secret {
+				values[key] = value
+			}
+		}
+		source := strings.Join(res.files, ", ")
+		file, err := bundle.render(env, source, values, false)
+		if err != nil {
+			return nil, fmt.Errorf("ambiente %s: %w", env, err)
+		}
+		b.Files = append(b.Files, file)
+
+		switch {
+		case len(res.secrets) == 0:
+		case bundle.IncludeSecrets:
+			if file, err = bundle.render(env, source, res.secrets, true); err != nil {
+				return nil, fmt.Errorf("ambiente %s: %w", env, err)
+			}
+			b.Files = append(b.Files, file)
+		default:
+			b.Omitted[env] = sortedKeys(res.secrets)
+		}
+	}
+	sort.Slice(b.Files, func(i, j int) bool { return b.Files[i].Path < b.Files[j].Path })
+
+	return b, nil
+}
+
+/*
+render gera o arquivo das variáveis ou dos segredos de um ambiente
+
+@param env string - O ambiente
+@param source string - Os arquivos .env de origem, para o comentário do cabeçalho
+@param values map[string]string - As variáveis
+@param secret bool - Se as variáveis são segredos
+
+@return BundleFile - O arquivo
+@return error - Com BundleHelm, um erro se duas variáveis ocuparem o mesmo caminho de values.yaml
+*/
+func (o BundleOptions) render(env string, source string, values map[string]string, secret bool) (BundleFile, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, bundleHeader, source)
+
+	switch o.Format {
+	case BundleDotenv:
+		for _, key := range sortedKeys(values) {
+			fmt.Fprintf(&b, "%s=%s\n", key, QuoteValue(values[key]))
+		}
+		name := "config.env"
+		if secret {
+			name = "secrets.env"
+		}
+		return BundleFile{Path: env + "/" + name, Content: []byte(b.String()), Secret: secret}, nil
+	case BundleHelm:
+		tree, err := helmValues(values, o.Helm)
+		if err != nil {
+			return BundleFile{}, err
+		}
+		writeHelmValues(&b, tree, "")
+		name := "values.yaml"
+		if secret {
+			name = "secrets.yaml"
+		}
+		return BundleFile{Path: env + "/" + name, Content: []byte(b.String()), Secret: secret}, nil
+	}
+
+	kind, field, name := "ConfigMap", "data", "configmap.yaml"
+	if secret {
+		kind, name = "Secret", "secret.yaml"
+	}
+	fmt.Fprintf(&b, "apiVersion: v1\nkind: %s\nmetadata:\n  name: %s\n", kind, yamlString(o.Name))
+	if o.Namespace != "" {
+		fmt.Fprintf(&b, "  namespace: %s\n", yamlString(o.Namespace))
+	}
+	fmt.Fprintf(&b, "  labels:\n    app.kubernetes.io/managed-by: locenv\n    locenv.dev/env: %s\n", yamlString(env))
+	if secret {
+		b.WriteString("type: Opaque\n")
+	}
+	if len(values) == 0 {
+		fmt.Fprintf(&b, "%s: {}\n", field)
+	} else {
+		fmt.Fprintf(&b, "%s:\n", field)
+	}
+	for _, key := range sortedKeys(values) {
+		value := values[key]
+		if secret {
+			value = base64.StdEncoding.EncodeToString([]byte(value))
+		}
+		fmt.Fprintf(&b, "  %s: %s\n", key, yamlString(value))
+	}
+
+	return BundleFile{Path: env + "/" + name, Content: []byte(b.String()), Secret: secret}, nil
+}
+
+/*
+Write grava os arquivos do bundle no diretório de destino, criando os diretórios dos ambientes
+
+Cada arquivo é gravado com uma troca atômica, com permissão 0644, ou 0600 para os segredos. Os arquivos que já
+existem no destino e não fazem parte do bundle não são alterados.
+
+@param target string - O diretório de destino
+
+@return error - Um erro se algum diretório ou arquivo não puder ser gravado
+*/
+func (b *Bundle) Write(target string) error {
+	for _, file := range b.Files {
+		path := filepath.Join(target, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		perm := os.FileMode(0o644)
+		if file.Secret {
+			perm = 0o600
+		}
+		if err := writeFileAtomic(path, file.Content, perm); err != nil {
+			return fmt.Errorf("não foi possível gravar %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// yamlString retorna um valor como uma string YAML entre aspas duplas, que também é uma string JSON válida.
+func yamlString(value string) string {
+	quoted, _ := json.Marshal(value)
+	return string(quoted)
+}
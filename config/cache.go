@@ -0,0 +1,470 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jonh-dev/go-logger/logger"
+)
+
+/*
+snapshotCache guarda a configuração do cache em disco de um carregador
+
+path string - O caminho do arquivo do cache, ou uma string vazia para o caminho padrão de cada projeto e ambiente
+key []byte - A chave AES que cifra o arquivo
+fallback bool - Se o cache é usado quando um valor cifrado não pode ser decifrado
+maxStale time.Duration - A idade máxima do cache aceita no modo offline; zero aceita qualquer idade
+digest string - O resumo do último cache gravado, com os segredos, para evitar regravar um cache que não mudou
+*/
+type snapshotCache struct {
+	path     string
+	key      []byte
+	fallback bool
+	maxStale time.Duration
+	digest   string
+}
+
+/*
+cachedSnapshot é o conteúdo do cache em disco, o resultado de uma resolução bem-sucedida
+
+SavedAt time.Time - O momento em que o cache foi escrito
+Revision string - A revisão da configuração carregada, como em Revision
+Env string - O ambiente do arquivo .env carregado
+Requested string - O ambiente solicitado
+Files []string - Os arquivos carregados
+Values map[string]string - As variáveis aplicadas ao ambiente do processo
+Secrets map[string]string - As variáveis classificadas como segredo
+Origins map[string]cachedOrigin - A declaração de cada variável
+*/
+type cachedSnapshot struct {
+	SavedAt   time.Time               `json:"saved_at"`
+	Revision  string                  `json:"revision"`
+	Env       string                  `json:"env"`
+	Requested string                  `json:"requested"`
+	Files     []string                `json:"files"`
+	Values    map[string]string       `json:"values"`
+	Secrets   map[string]string       `json:"secrets"`
+	Origins   map[string]cachedOrigin `json:"origins"`
+}
+
+// cachedOrigin é a forma serializada de origin.
+type cachedOrigin struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Rank int    `json:"rank"`
+}
+
+/*
+DefaultCachePath retorna o caminho padrão do cache em disco de um projeto e de um ambiente
+
+O nome do arquivo é um hash do caminho absoluto do diretório do arquivo .env e do nome do ambiente, para que
+projetos e ambientes diferentes nunca compartilhem o mesmo cache.
+
+@param dir string - O diretório do arquivo .env base
+@param env string - O ambiente
+
+@return string - O caminho <cache do usuário>/locenv/<hash>.cache, ou .locenv-<hash>.cache se o diretório não existir
+*/
+func DefaultCachePath(dir string, env string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	sum := sha256.Sum256([]byte(dir + "\x00" + normalizeEnv(env)))
+	name := hex.EncodeToString(sum[:16]) + ".cache"
+
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return ".locenv-" + name
+	}
+
+	return filepath.Join(base, "locenv", name)
+}
+
+/*
+cachePath retorna o caminho do cache de uma resolução: o informado na opção ou o padrão do projeto e do ambiente
+
+@param res *resolution - O resultado, possivelmente parcial, da resolução
+
+@return string - O caminho do arquivo do cache
+*/
+func (c *snapshotCache) cachePath(res *resolution) string {
+	if c.path != "" {
+		return c.path
+	}
+
+	return DefaultCachePath(filepath.Dir(res.files[0]), res.env)
+}
+
+/*
+WithSnapshotCache grava em disco um cache cifrado da configuração resolvida
+
+Depois de cada carregamento bem-sucedido, as variáveis resolvidas, inclusive os segredos já decifrados, são
+gravadas em path, cifradas com AES-GCM e com permissão 0600, junto com a revisão da configuração. Sem path, o
+cache fica em DefaultCachePath do diretório do arquivo .env e do ambiente carregado. O arquivo só é
+regravado quando o conteúdo muda, inclusive quando apenas um segredo é rotacionado, e quem o lê com LoadSnapshotCache pode comparar a revisão gravada com a atual
+para descartar um cache desatualizado. O cache é usado por WithOfflineFallback e por locenv export -from-cache.
+
+@param path string - O caminho do arquivo do cache, ou uma string vazia para DefaultCachePath
+@param key []byte - A chave AES de 16, 24 ou 32 bytes que cifra o cache
+
+@return Option - A opção que habilita o cache
+*/
+func WithSnapshotCache(path string, key []byte) Option {
+	return func(f *FileEnvLoader) {
+		f.cache = &snapshotCache{path: path, key: key}
+	}
+}
+
+/*
+LoadSnapshotCache lê o cache gravado por WithSnapshotCache como uma visão imutável
+
+A revisão da visão é a da configuração gravada, de modo que EqualRevision indica se o cache ainda corresponde à
+configuração atual. Sem path, o cache lido é o do projeto do diretório atual e do ambiente de APP_ENV, localizados
+pela mesma descoberta de LoadEnv, sem ler nem decifrar os arquivos .env.
+
+@param path string - O caminho do arquivo do cache, ou uma string vazia para DefaultCachePath
+@param key []byte - A chave AES que cifra o cache
+
+@return ConfigView - A configuração gravada
+@return time.Time - O momento em que o cache foi gravado
+@return error - Um erro se o arquivo não existir, a chave for inválida ou o conteúdo não puder ser decifrado
+*/
+func LoadSnapshotCache(path string, key []byte) (ConfigView, time.Time, error) {
+	if path == "" {
+		loader := NewEnvLoader(WithSilent()).(*FileEnvLoader)
+		files, env, err := loader.layerFiles()
+		if err != nil {
+			return ConfigView{}, time.Time{}, err
+		}
+		path = DefaultCachePath(filepath.Dir(files[0]), env)
+	}
+
+	cached, err := readSnapshotCache(path, key)
+	if err != nil {
+		return ConfigView{}, time.Time{}, err
+	}
+
+	view := &FileEnvLoader{
+		Env:          cached.Env,
+		values:       cached.Values,
+		secrets:      cached.Secrets,
+		sources:      make(map[string]string, len(cached.Origins)),
+		lines:        make(map[string]int, len(cached.Origins)),
+		order:        make(map[string]int, len(cached.Origins)),
+		revision:     cached.Revision,
+		noProcessEnv: true,
+	}
+	for key, o := range cached.Origins {
+		view.sources[key] = o.File
+		view.lines[key] = o.Line
+		view.order[key] = o.Rank
+	}
+
+	return ConfigView{loader: view}, cached.SavedAt, nil
+}
+
+/*
+WithDecryptRetry repete as chamadas aos Decrypters que falham, com espera exponencial entre as tentativas
+
+Falhas transitórias de provedores remotos, como um KMS ou um Vault momentaneamente inacessível, deixam de
+interromper o carregamento na primeira tentativa. A espera começa em initial e dobra a cada nova tentativa.
+
+@param attempts int - O número total de tentativas, incluindo a primeira
+@param initial time.Duration - A espera antes da segunda tentativa
+
+@return Option - A opção que habilita as novas tentativas
+*/
+func WithDecryptRetry(attempts int, initial time.Duration) Option {
+	return func(f *FileEnvLoader) {
+		f.decryptAttempts = attempts
+		f.decryptBackoff = initial
+	}
+}
+
+/*
+decryptWithRetry chama o Decrypter, repetindo a chamada conforme WithDecryptRetry
+
+@param backend string - O nome do backend, usado nos avisos
+@param d Decrypter - O Decrypter
+@param ciphertext []byte - O texto cifrado
+
+@return []byte - O texto em claro
+@return error - O erro da última tentativa
+*/
+func (f *FileEnvLoader) decryptWithRetry(backend string, d Decrypter, ciphertext []byte) ([]byte, error) {
+	wait := f.decryptBackoff
+	for attempt := 1; ; attempt++ {
+		plaintext, err := d.Decrypt(ciphertext)
+		if err == nil || attempt >= f.decryptAttempts {
+			return plaintext, err
+		}
+
+		f.warn(Warning{
+			Kind:    WarningRetry,
+			Message: fmt.Sprintf("Falha ao decifrar com o backend %q (tentativa %d de %d): %s; nova tentativa em %s", backend, attempt, f.decryptAttempts, err.Error(), wait),
+		})
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+/*
+WithOfflineFallback habilita o modo offline com um cache cifrado da última configuração carregada
+
+O cache é gravado como em WithSnapshotCache. Se um carregamento posterior falhar porque um valor
+cifrado não pôde ser decifrado, como quando o KMS ou o Vault está inacessível na inicialização, a configuração do
+cache é aplicada em seu lugar, desde que não seja mais antiga que maxStale e tenha sido gravada para o mesmo
+ambiente e os mesmos arquivos. O uso do cache é registrado como aviso
+e incluído nos avisos do resultado; erros de sintaxe e de validação nunca usam o cache.
+
+@param path string - O caminho do arquivo do cache, ou uma string vazia para DefaultCachePath
+@param key []byte - A chave AES de 16, 24 ou 32 bytes que cifra o cache
+@param maxStale time.Duration - A idade máxima do cache aceita; zero aceita qualquer idade
+
+@return Option - A opção que habilita o modo offline
+*/
+func WithOfflineFallback(path string, key []byte, maxStale time.Duration) Option {
+	return func(f *FileEnvLoader) {
+		WithSnapshotCache(path, key)(f)
+		f.cache.fallback = true
+		f.cache.maxStale = maxStale
+	}
+}
+
+/*
+resolveOrFallback resolve a configuração e, se um valor cifrado não puder ser decifrado, recorre ao cache offline
+
+O cache só é usado se tiver sido gravado para o mesmo ambiente e os mesmos arquivos da resolução que falhou. O
+ambiente solicitado deve ser o gravado ou o ambiente encontrado naquela resolução, que é o solicitado por Reload
+depois de um carregamento por apelido.
+
+@return *resolution - O resultado da resolução, com fromCache verdadeiro quando veio do cache
+@return error - O erro da resolução, se o cache não puder ser usado
+*/
+func (f *FileEnvLoader) resolveOrFallback() (*resolution, error) {
+	res, err := f.resolve()
+	if err == nil {
+		return res, nil
+	}
+	var decryptErr *DecryptError
+	if f.cache == nil || !f.cache.fallback || !errors.As(err, &decryptErr) || res == nil {
+		return nil, err
+	}
+
+	path := f.cache.cachePath(res)
+	cached, cacheErr := readSnapshotCache(path, f.cache.key)
+	if cacheErr != nil {
+		if f.logs(LogError) {
+			logger.Error(fmt.Sprintf("Cache offline indisponível: %s", cacheErr.Error()))
+		}
+		return nil, err
+	}
+	if mismatch := cacheMismatch(cached, res); mismatch != "" {
+		if f.logs(LogError) {
+			logger.Error(fmt.Sprintf("Cache offline %s ignorado: %s", path, mismatch))
+		}
+		return nil, err
+	}
+	age := time.Since(cached.SavedAt)
+	if f.cache.maxStale > 0 && age > f.cache.maxStale {
+		if f.logs(LogError) {
+			logger.Error(fmt.Sprintf("Cache offline de %s ignorado: idade %s acima do limite de %s", cached.SavedAt.Format(time.RFC3339), age.Round(time.Second), f.cache.maxStale))
+		}
+		return nil, err
+	}
+
+	warning := Warning{
+		Kind:    WarningCache,
+		File:    path,
+		Message: fmt.Sprintf("configuração carregada do cache offline de %s (idade %s): %s", cached.SavedAt.Format(time.RFC3339), age.Round(time.Second), err.Error()),
+	}
+	f.warn(warning)
+
+	origins := make(map[string]origin, len(cached.Origins))
+	for key, o := range cached.Origins {
+		origins[key] = origin{file: o.File, line: o.Line, rank: o.Rank}
+	}
+
+	return &resolution{
+		files:     cached.Files,
+		env:       cached.Env,
+		requested: cached.Requested,
+		values:    cached.Values,
+		origins:   origins,
+		secrets:   cached.Secrets,
+		warnings:  []Warning{warning},
+		fromCache: true,
+	}, nil
+}
+
+/*
+cacheMismatch compara o cache offline com a resolução que falhou
+
+@param cached *cachedSnapshot - O conteúdo do cache
+@param res *resolution - O resultado parcial da resolução
+
+@return string - O motivo pelo qual o cache não corresponde à resolução, ou uma string vazia
+*/
+func cacheMismatch(cached *cachedSnapshot, res *resolution) string {
+	if cached.Env != res.env {
+		return fmt.Sprintf("gravado para o ambiente %q, e não %q", cached.Env, res.env)
+	}
+	if res.requested != cached.Requested && res.requested != cached.Env {
+		return fmt.Sprintf("gravado para o ambiente solicitado %q, e não %q", cached.Requested, res.requested)
+	}
+	if strings.Join(cached.Files, "\x00") != strings.Join(res.files, "\x00") {
+		return fmt.Sprintf("gravado para os arquivos %s, e não %s", strings.Join(cached.Files, ", "), strings.Join(res.files, ", "))
+	}
+
+	return ""
+}
+
+/*
+storeCache grava no cache em disco o resultado de uma resolução aplicada com sucesso
+
+O cache não é regravado quando o seu conteúdo não mudou, comparando cacheDigest, que inclui os segredos omitidos
+da revisão pública. Falhas na gravação não interrompem o carregamento e são registradas como avisos.
+
+@param res *resolution - O resultado da resolução
+*/
+func (f *FileEnvLoader) storeCache(res *resolution) {
+	if f.cache == nil || res.fromCache {
+		return
+	}
+	path := f.cache.cachePath(res)
+	digest := cacheDigest(f.cache.key, path, res)
+	if digest == f.cache.digest {
+		return
+	}
+
+	snapshot := cachedSnapshot{
+		SavedAt:   time.Now().UTC(),
+		Revision:  f.revision,
+		Env:       res.env,
+		Requested: res.requested,
+		Files:     res.files,
+		Values:    res.values,
+		Secrets:   res.secrets,
+		Origins:   make(map[string]cachedOrigin, len(res.origins)),
+	}
+	for key, o := range res.origins {
+		snapshot.Origins[key] = cachedOrigin{File: o.file, Line: o.line, Rank: o.rank}
+	}
+
+	if err := writeSnapshotCache(path, f.cache.key, snapshot); err != nil {
+		f.warn(Warning{
+			Kind:    WarningCache,
+			File:    path,
+			Message: fmt.Sprintf("Não foi possível gravar o cache %s: %s", path, err.Error()),
+		})
+		return
+	}
+	f.cache.digest = digest
+}
+
+/*
+cacheDigest resume o conteúdo que storeCache gravaria, com um HMAC pela chave do cache
+
+Ao contrário da revisão pública, o resumo inclui os valores dos segredos, para que uma rotação regrave o cache.
+
+@param key []byte - A chave AES do cache
+@param path string - O caminho do arquivo do cache
+@param res *resolution - O resultado da resolução
+
+@return string - O resumo
+*/
+func cacheDigest(key []byte, path string, res *resolution) string {
+	scope := strings.Join(append([]string{path, res.requested}, res.files...), "\x00")
+
+	return configRevision(key, res.env+"\x00"+scope, res.values, res.secrets)
+}
+
+/*
+writeSnapshotCache cifra e grava o cache de forma atômica, com permissão 0600
+
+@param path string - O caminho do arquivo do cache
+@param key []byte - A chave AES
+@param snapshot cachedSnapshot - O conteúdo do cache
+
+@return error - Um erro se a chave for inválida ou o arquivo não puder ser gravado
+*/
+func writeSnapshotCache(path string, key []byte, snapshot cachedSnapshot) error {
+	cipher, err := NewLocalKeyDecrypter(key)
+	if err != nil {
+		return fmt.Errorf("chave do cache inválida: %w", err)
+	}
+
+	plaintext, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	defer zeroBytes(plaintext)
+
+	ciphertext, err := cipher.Encrypt(plaintext)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(ciphertext); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+/*
+readSnapshotCache lê e decifra o cache
+
+@param path string - O caminho do arquivo do cache
+@param key []byte - A chave AES
+
+@return *cachedSnapshot - O conteúdo do cache
+@return error - Um erro se o arquivo não existir, a chave for inválida ou o conteúdo não puder ser decifrado
+*/
+func readSnapshotCache(path string, key []byte) (*cachedSnapshot, error) {
+	cipher, err := NewLocalKeyDecrypter(key)
+	if err != nil {
+		return nil, fmt.Errorf("chave do cache inválida: %w", err)
+	}
+
+	ciphertext, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := cipher.Decrypt(ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("não foi possível decifrar o cache %s: %w", path, err)
+	}
+	defer zeroBytes(plaintext)
+
+	var snapshot cachedSnapshot
+	if err := json.Unmarshal(plaintext, &snapshot); err != nil {
+		return nil, fmt.Errorf("cache %s inválido: %w", path, err)
+	}
+
+	return &snapshot, nil
+}
@@ -0,0 +1,284 @@
+package config
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"time"
+)
+
+// CaptureVersion é a versão do formato das capturas produzidas por NewCapture.
+const CaptureVersion = 1
+
+// ErrInvalidCapture é retornado por Verify e LoadFromCapture quando a assinatura da captura não confere.
+var ErrInvalidCapture = errors.New("assinatura da captura inválida: o arquivo foi alterado ou a chave não confere")
+
+/*
+CapturedValue é uma variável registrada em uma captura
+
+Key string - O nome da variável
+Value string - O valor efetivo, ou o valor mascarado
+Secret bool - Se a variável é um segredo
+Masked bool - Se o valor foi mascarado na captura
+Source string - O arquivo que definiu o valor, ou SourceProcess se o valor do processo prevaleceu
+Line int - A linha da declaração no arquivo, ou zero
+*/
+type CapturedValue struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Secret bool   `json:"secret,omitempty"`
+	Masked bool   `json:"masked,omitempty"`
+	Source string `json:"source"`
+	Line   int    `json:"line,omitempty"`
+}
+
+/*
+Capture é um retrato da configuração efetiva de uma máquina, para ser anexado a relatos de problemas
+
+Version int - A versão do formato, CaptureVersion
+CapturedAt time.Time - O momento da captura
+GOOS string - O sistema operacional da máquina
+GOARCH string - A arquitetura da máquina
+AppEnv string - O valor normalizado de APP_ENV
+Env string - O ambiente do arquivo .env carregado
+Revision string - A revisão da configuração, como em Revision, calculada com os valores sem máscara
+Candidates []Candidate - Os arquivos considerados durante a descoberta
+Files []string - Os arquivos carregados, na ordem em que foram aplicados
+Values []CapturedValue - As variáveis efetivas, em ordem alfabética
+Warnings []string - Os avisos do carregamento
+Error string - O erro do carregamento, se houver
+Signature string - A assinatura HMAC-SHA256 do conteúdo, em hexadecimal
+*/
+type Capture struct {
+	Version    int             `json:"version"`
+	CapturedAt time.Time       `json:"captured_at"`
+	GOOS       string          `json:"goos"`
+	GOARCH     string          `json:"goarch"`
+	AppEnv     string          `json:"app_env"`
+	Env        string          `json:"env"`
+	Revision   string          `json:"revision,omitempty"`
+	Candidates []Candidate     `json:"candidates"`
+	Files      []string        `json:"files"`
+	Values     []CapturedValue `json:"values"`
+	Warnings   []string        `json:"warnings,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Signature  string          `json:"signature,omitempty"`
+}
+
+/*
+NewCapture resolve a configuração e registra o resultado em uma captura, sem alterar o ambiente do processo
+
+A captura contém a trilha da descoberta (os arquivos candidatos e a decisão sobre cada um), os arquivos
+carregados e, para cada variável, o valor efetivo e a sua origem. Os segredos são mascarados, assim como a senha
+de valores com formato de string de conexão (veja RedactDSN), para que a captura possa ser anexada a um chamado
+de suporte. Um carregamento que falha também é capturado, com o erro em Error.
+
+@param opts ...Option - As opções do carregador a ser capturado
+
+@return *Capture - A captura, ainda sem assinatura
+*/
+func NewCapture(opts ...Option) *Capture {
+	f := NewEnvLoader(opts...).(*FileEnvLoader)
+
+	c := &Capture{
+		Version:    CaptureVersion,
+		CapturedAt: time.Now().UTC(),
+		GOOS:       runtime.GOOS,
+		GOARCH:     runtime.GOARCH,
+		AppEnv:     f.Env,
+		Candidates: []Candidate{},
+		Files:      []string{},
+		Values:     []CapturedValue{},
+	}
+
+	f.candidates = &c.Candidates
+	res, err := f.resolve()
+	f.candidates = nil
+	if err != nil {
+		c.Error = err.Error()
+		return c
+	}
+
+	for i := range c.Candidates {
+		c.Candidates[i].Selected = c.Candidates[i].Path == res.files[0]
+	}
+	c.Env = res.env
+	c.Files = res.files
+	c.Warnings = warningMessages(res.warnings)
+
+	effective := make(map[string]string, len(res.values))
+	for _, key := range sortedKeys(res.values) {
+		v := CapturedValue{Key: key, Value: res.values[key], Source: res.origins[key].file, Line: res.origins[key].line}
+		if processValue, ok := os.LookupEnv(key); ok && !f.noProcessEnv && f.precedence != FileWins && processValue != v.Value {
+			v.Value, v.Source, v.Line = processValue, SourceProcess, 0
+		}
+		effective[key] = v.Value
+		c.Values = append(c.Values, maskCaptured(v, res.secrets))
+	}
+	for _, key := range sortedKeys(res.secrets) {
+		if _, ok := res.values[key]; ok {
+			continue
+		}
+		v := CapturedValue{Key: key, Value: res.secrets[key], Source: res.origins[key].file, Line: res.origins[key].line}
+		c.Values = append(c.Values, maskCaptured(v, res.secrets))
+	}
+	c.Revision = configRevision(f.revisionKey, res.env, effective, res.secrets)
+
+	return c
+}
+
+/*
+maskCaptured mascara o valor de uma variável capturada, se for um segredo ou contiver uma senha
+
+@param v CapturedValue - A variável com o valor em claro
+@param secrets map[string]string - Os segredos da configuração
+
+@return CapturedValue - A variável com o valor mascarado, se necessário
+*/
+func maskCaptured(v CapturedValue, secrets map[string]string) CapturedValue {
+	if _, ok := secrets[v.Key]; ok {
+		v.Secret, v.Masked, v.Value = true, true, maskedValue
+		return v
+	}
+
+	if redacted := RedactDSN(v.Value); redacted != v.Value {
+		v.Masked, v.Value = true, redacted
+	}
+
+	return v
+}
+
+/*
+Sign assina a captura com HMAC-SHA256, para que alterações posteriores no arquivo sejam detectadas
+
+Sem uma chave compartilhada com a equipe de suporte, a assinatura detecta apenas alterações acidentais.
+
+@param key []byte - A chave da assinatura, que pode ser vazia
+*/
+func (c *Capture) Sign(key []byte) {
+	c.Signature = hex.EncodeToString(c.mac(key))
+}
+
+/*
+Verify confere a assinatura da captura
+
+@param key []byte - A chave usada em Sign
+
+@return error - ErrInvalidCapture se a captura não estiver assinada ou a assinatura não conferir
+*/
+func (c *Capture) Verify(key []byte) error {
+	signature, err := hex.DecodeString(c.Signature)
+	if err != nil || c.Signature == "" || !hmac.Equal(signature, c.mac(key)) {
+		return ErrInvalidCapture
+	}
+
+	return nil
+}
+
+/*
+mac calcula o HMAC-SHA256 do conteúdo da captura, sem a assinatura
+
+@param key []byte - A chave da assinatura
+
+@return []byte - O HMAC do conteúdo
+*/
+func (c *Capture) mac(key []byte) []byte {
+	unsigned := *c
+	unsigned.Signature = ""
+	content, _ := json.Marshal(unsigned)
+
+	h := hmac.New(sha256.New, key)
+	h.Write(content)
+	return h.Sum(nil)
+}
+
+/*
+WriteTo escreve a captura em JSON indentado
+
+@param w io.Writer - A saída em que a captura é escrita
+
+@return int64 - O número de bytes escritos
+@return error - Um erro se a captura não puder ser escrita
+*/
+func (c *Capture) WriteTo(w io.Writer) (int64, error) {
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(append(content, '\n'))
+	return int64(n), err
+}
+
+/*
+ReadCapture lê uma captura escrita por WriteTo, sem conferir a assinatura
+
+@param r io.Reader - A origem da captura
+
+@return *Capture - A captura lida
+@return error - Um erro se o conteúdo não for uma captura válida ou tiver uma versão mais nova que CaptureVersion
+*/
+func ReadCapture(r io.Reader) (*Capture, error) {
+	var c Capture
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&c); err != nil {
+		return nil, fmt.Errorf("captura inválida: %w", err)
+	}
+	if c.Version < 1 || c.Version > CaptureVersion {
+		return nil, fmt.Errorf("versão da captura %d não suportada: a versão máxima é %d", c.Version, CaptureVersion)
+	}
+
+	return &c, nil
+}
+
+/*
+LoadFromCapture reproduz localmente a configuração registrada em uma captura
+
+A assinatura é conferida antes do carregamento. As variáveis são definidas em um MapLoader com o ambiente e a
+origem de cada uma conforme a captura, sem tocar o ambiente do processo. Os segredos e os valores mascarados não
+podem ser recuperados: os segredos ficam de fora e devem ser definidos com SetSecret, e os demais valores
+mascarados são carregados com a máscara. Por isso, a revisão do MapLoader só coincide com Capture.Revision quando
+nenhum valor foi mascarado.
+
+@param path string - O caminho do arquivo da captura
+@param key []byte - A chave usada na assinatura
+
+@return *MapLoader - A configuração capturada
+@return *Capture - A captura lida, com a trilha da descoberta e os avisos
+@return error - Um erro se o arquivo não puder ser lido ou ErrInvalidCapture se a assinatura não conferir
+*/
+func LoadFromCapture(path string, key []byte) (*MapLoader, *Capture, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao ler a captura %s: %w", path, err)
+	}
+
+	c, err := ReadCapture(bytes.NewReader(content))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.Verify(key); err != nil {
+		return nil, nil, err
+	}
+
+	m := NewMapLoader(c.Env, nil)
+	m.lines = make(map[string]int, len(c.Values))
+	for _, v := range c.Values {
+		if v.Secret {
+			continue
+		}
+		m.Set(v.Key, v.Value)
+		m.sources[v.Key] = v.Source
+		m.lines[v.Key] = v.Line
+	}
+
+	return m, c, nil
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+/*
+LayerConflict descreve uma variável redefinida com outro valor por uma camada posterior da cascata
+
+Key string - O nome da variável
+First string - A declaração original, no formato arquivo:linha
+Second string - A declaração que a sobrescreve, no formato arquivo:linha
+*/
+type LayerConflict struct {
+	Key    string
+	First  string
+	Second string
+}
+
+/*
+CascadeError é o erro retornado no modo estrito quando camadas da cascata definem a mesma variável com valores diferentes
+
+Conflicts []LayerConflict - Os conflitos encontrados, na ordem em que foram lidos
+*/
+type CascadeError struct {
+	Conflicts []LayerConflict
+}
+
+// Error lista todos os conflitos encontrados.
+func (e *CascadeError) Error() string {
+	problems := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		problems[i] = fmt.Sprintf("%s definida em %s é sobrescrita com outro valor em %s", c.Key, c.First, c.Second)
+	}
+
+	return fmt.Sprintf("conflito entre camadas: %s", strings.Join(problems, "; "))
+}
+
+/*
+WithStrictCascade torna erro a redefinição de uma variável com outro valor por uma camada posterior
+
+Quando o arquivo do ambiente é combinado com sobreposições (WithPlatformOverlays, WithUserOverlays), uma camada
+que redefine uma variável com outro valor normalmente prevalece em silêncio. No modo estrito, LoadEnv e Plan
+retornam um *CascadeError com o arquivo e a linha das duas declarações, evitando sobreposições acidentais.
+As variáveis cujo nome corresponde a algum dos padrões informados (sintaxe de path.Match) podem ser
+sobrescritas livremente. Redefinições dentro do mesmo arquivo e com o mesmo valor não são conflitos.
+
+@param overridable ...string - Os padrões de nomes das variáveis que podem ser sobrescritas
+
+@return Option - A opção que ativa o modo estrito
+*/
+func WithStrictCascade(overridable ...string) Option {
+	return func(f *FileEnvLoader) {
+		f.strictCascade = true
+		f.overridable = append(f.overridable, overridable...)
+	}
+}
+
+/*
+layerConflict verifica se uma declaração redefine, no modo estrito, uma variável de uma camada anterior
+
+@param e entry - A declaração lida
+@param file string - O arquivo da declaração
+@param values map[string]string - As variáveis das camadas anteriores
+@param origins map[string]origin - As declarações das camadas anteriores
+
+@return LayerConflict - O conflito encontrado
+@return bool - Se a declaração é um conflito
+*/
+func (f *FileEnvLoader) layerConflict(e entry, file string, values map[string]string, origins map[string]origin) (LayerConflict, bool) {
+	if !f.strictCascade {
+		return LayerConflict{}, false
+	}
+
+	previous, exists := origins[e.key]
+	if !exists || previous.file == file || values[e.key] == e.value {
+		return LayerConflict{}, false
+	}
+
+	for _, pattern := range f.overridable {
+		if matched, _ := path.Match(pattern, e.key); matched {
+			return LayerConflict{}, false
+		}
+	}
+
+	return LayerConflict{
+		Key:    e.key,
+		First:  fmt.Sprintf("%s:%d", previous.file, previous.line),
+		Second: fmt.Sprintf("%s:%d", file, e.line),
+	}, true
+}
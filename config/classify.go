@@ -0,0 +1,61 @@
+package config
+
+// As classes de ambiente reconhecidas por IsProduction, IsDevelopment e IsTest.
+const (
+	ClassProduction  = "production"
+	ClassDevelopment = "development"
+	ClassTest        = "test"
+)
+
+/*
+WithClassAliases adiciona apelidos a uma classe de ambiente
+
+Por padrão, "production", "prod" e "prd" pertencem à classe de produção; "development", "dev" e "local"
+à de desenvolvimento; e "test" e "testing" à de teste. Por exemplo, WithClassAliases(ClassProduction, "live")
+faz com que IsProduction retorne true quando o ambiente for "live".
+
+@param class string - A classe de ambiente (ClassProduction, ClassDevelopment ou ClassTest)
+@param aliases ...string - Os nomes de ambiente que pertencem à classe
+
+@return Option - A opção que adiciona os apelidos
+*/
+func WithClassAliases(class string, aliases ...string) Option {
+	return func(f *FileEnvLoader) {
+		for _, alias := range aliases {
+			f.classAliases[class] = append(f.classAliases[class], normalizeEnv(alias))
+		}
+	}
+}
+
+/*
+isClass verifica se o ambiente atual pertence à classe informada
+
+@param class string - A classe de ambiente
+
+@return bool - Se o ambiente atual é um dos apelidos da classe
+*/
+func (f *FileEnvLoader) isClass(class string) bool {
+	env := normalizeEnv(f.Env)
+	for _, alias := range f.classAliases[class] {
+		if alias == env {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsProduction informa se o ambiente atual pertence à classe de produção.
+func (f *FileEnvLoader) IsProduction() bool {
+	return f.isClass(ClassProduction)
+}
+
+// IsDevelopment informa se o ambiente atual pertence à classe de desenvolvimento.
+func (f *FileEnvLoader) IsDevelopment() bool {
+	return f.isClass(ClassDevelopment)
+}
+
+// IsTest informa se o ambiente atual pertence à classe de teste.
+func (f *FileEnvLoader) IsTest() bool {
+	return f.isClass(ClassTest)
+}
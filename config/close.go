@@ -0,0 +1,182 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// ErrClosed é retornado pelas operações de um carregador encerrado com Close.
+var ErrClosed = errors.New("o carregador foi encerrado")
+
+/*
+lifecycle controla as goroutines em segundo plano de um carregador, encerradas por Close
+
+mu sync.Mutex - Protege closed
+closed bool - Se o carregador foi encerrado
+done chan struct{} - Fechado por Close, para avisar as goroutines
+wg sync.WaitGroup - As goroutines em execução
+*/
+type lifecycle struct {
+	mu     sync.Mutex
+	closed bool
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// newLifecycle cria o controle de goroutines de um novo carregador.
+func newLifecycle() *lifecycle {
+	return &lifecycle{done: make(chan struct{})}
+}
+
+/*
+track registra uma goroutine em segundo plano, que deve chamar a função de término ao encerrar
+
+O contexto retornado é cancelado com a causa ErrClosed quando o carregador é encerrado. Carregadores criados
+sem NewEnvLoader, como as visões de Snapshot, não são rastreados.
+
+@param ctx context.Context - O contexto da goroutine
+
+@return context.Context - O contexto derivado, cancelado também por Close
+@return func() - A função de término, chamada pela goroutine ao encerrar
+@return error - ErrClosed se o carregador já foi encerrado
+*/
+func (l *lifecycle) track(ctx context.Context) (context.Context, func(), error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	if l == nil {
+		return ctx, func() { cancel(nil) }, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		cancel(nil)
+		return nil, nil, ErrClosed
+	}
+
+	l.wg.Add(2)
+	go func() {
+		defer l.wg.Done()
+		select {
+		case <-l.done:
+			cancel(ErrClosed)
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		cancel(nil)
+		l.wg.Done()
+	}, nil
+}
+
+// isClosed informa se o carregador foi encerrado com Close.
+func (l *lifecycle) isClosed() bool {
+	if l == nil {
+		return false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.closed
+}
+
+/*
+close marca o carregador como encerrado e avisa as goroutines
+
+@return bool - Se esta chamada encerrou o carregador; false se ele já estava encerrado
+*/
+func (l *lifecycle) close() bool {
+	if l == nil {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return false
+	}
+	l.closed = true
+	close(l.done)
+
+	return true
+}
+
+/*
+Close encerra os componentes em segundo plano do carregador
+
+As verificações de WatchFrozen e as assinaturas de ListenSSE de um PushListener deste carregador terminam, as
+rotações de segredos ainda no intervalo de WithRotationDebounce são entregues imediatamente aos callbacks e os
+Decrypters que implementam io.Closer, como os plugins de WithPlugin, são encerrados. Close aguarda o fim das
+goroutines e dos callbacks em andamento, de modo que nenhuma goroutine da biblioteca continue em execução
+depois dele (o que ferramentas como o goleak verificam nos testes).
+
+Depois de Close, LoadEnv, LoadEnvResult, Reload, Plan, WatchFrozen e ListenSSE retornam ErrClosed; as
+variáveis já carregadas continuam disponíveis para leitura. Chamadas repetidas retornam nil.
+
+@param ctx context.Context - O contexto que limita a espera pelas goroutines
+
+@return error - O erro do contexto, se o prazo terminar antes das goroutines, ou os erros ao encerrar os Decrypters
+*/
+func (f *FileEnvLoader) Close(ctx context.Context) error {
+	if !f.lifecycle.close() {
+		return nil
+	}
+
+	var errs []error
+	if f.rotation != nil {
+		f.rotation.flush()
+		if err := waitGroup(ctx, &f.rotation.wg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if f.lifecycle != nil {
+		if err := waitGroup(ctx, &f.lifecycle.wg); err != nil && len(errs) == 0 {
+			errs = append(errs, err)
+		}
+	}
+
+	backends := make([]string, 0, len(f.decrypters))
+	for backend := range f.decrypters {
+		backends = append(backends, backend)
+	}
+	sort.Strings(backends)
+	for _, backend := range backends {
+		if closer, ok := f.decrypters[backend].(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("erro ao encerrar o backend %s: %w", backend, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+/*
+waitGroup aguarda um sync.WaitGroup, limitado por um contexto
+
+@param ctx context.Context - O contexto que limita a espera
+@param wg *sync.WaitGroup - O grupo aguardado
+
+@return error - O erro do contexto, se ele terminar antes do grupo
+*/
+func waitGroup(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
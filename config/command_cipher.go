@@ -0,0 +1,72 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+/*
+CommandCipher é um Encrypter e Decrypter que delega a cifra a um programa externo
+
+O programa recebe o conteúdo na entrada padrão e escreve o resultado na saída padrão, como fazem age, gpg e
+as CLIs de KMS. Isso permite usar esses backends sem dependências adicionais, por exemplo:
+
+	CommandCipher{
+		EncryptCommand: []string{"age", "-r", "age1..."},
+		DecryptCommand: []string{"age", "-d", "-i", "key.txt"},
+	}
+
+EncryptCommand []string - O programa e os argumentos usados para cifrar
+DecryptCommand []string - O programa e os argumentos usados para decifrar
+*/
+type CommandCipher struct {
+	EncryptCommand []string
+	DecryptCommand []string
+}
+
+// Name retorna o nome do provedor de comandos externos.
+func (c CommandCipher) Name() string {
+	return "command"
+}
+
+// Encrypt executa EncryptCommand com o texto em claro na entrada padrão.
+func (c CommandCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	return runCipherCommand(c.EncryptCommand, plaintext)
+}
+
+// Decrypt executa DecryptCommand com o texto cifrado na entrada padrão.
+func (c CommandCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	return runCipherCommand(c.DecryptCommand, ciphertext)
+}
+
+/*
+runCipherCommand executa um programa externo de cifra
+
+@param command []string - O programa e os argumentos
+@param input []byte - O conteúdo enviado na entrada padrão
+
+@return []byte - A saída padrão do programa
+@return error - Um erro com a saída de erros do programa, se ele falhar
+*/
+func runCipherCommand(command []string, input []byte) ([]byte, error) {
+	if len(command) == 0 {
+		return nil, fmt.Errorf("nenhum comando configurado")
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %w: %s", command[0], err, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", command[0], err)
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,222 @@
+package config
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+/*
+ContentCheck valida o conteúdo binário de uma variável durante o carregamento
+
+@param content []byte - O conteúdo decodificado ou lido do arquivo
+
+@return error - Um erro se o conteúdo for inválido
+*/
+type ContentCheck func(content []byte) error
+
+/*
+contentRule é uma variável de conteúdo binário validada durante o carregamento
+
+key string - O nome da variável
+file bool - Se o valor é o caminho de um arquivo
+hex bool - Se o valor é o conteúdo em hexadecimal; sem file nem hex, é o conteúdo em base64
+checks []ContentCheck - As validações do conteúdo
+*/
+type contentRule struct {
+	key    string
+	file   bool
+	hex    bool
+	checks []ContentCheck
+}
+
+/*
+PEMCheck é uma ContentCheck que exige ao menos um bloco PEM no conteúdo, como em certificados e chaves
+
+@param content []byte - O conteúdo
+
+@return error - Um erro se nenhum bloco PEM for encontrado
+*/
+func PEMCheck(content []byte) error {
+	if block, _ := pem.Decode(content); block == nil {
+		return errors.New("nenhum bloco PEM encontrado")
+	}
+
+	return nil
+}
+
+/*
+WithBase64Content declara uma variável com conteúdo binário em base64, validada durante o carregamento
+
+Se a variável estiver definida, LoadEnv verifica se ela pode ser decodificada e aplica as validações
+informadas (ex.: PEMCheck). Os problemas são reunidos no *ValidationError do carregamento, para que um
+certificado inválido seja detectado na inicialização e não na primeira conexão.
+
+@param key string - O nome da variável (ex.: TLS_CERT_B64)
+@param checks ...ContentCheck - As validações do conteúdo decodificado
+
+@return Option - A opção que declara a variável
+*/
+func WithBase64Content(key string, checks ...ContentCheck) Option {
+	return func(f *FileEnvLoader) {
+		f.contents = append(f.contents, contentRule{key: key, checks: checks})
+	}
+}
+
+/*
+WithFileContent declara uma variável com o caminho de um arquivo, cujo conteúdo é validado durante o carregamento
+
+Se a variável estiver definida, LoadEnv verifica se o arquivo pode ser lido e aplica as validações informadas
+(ex.: PEMCheck), reunindo os problemas no *ValidationError do carregamento.
+
+@param key string - O nome da variável (ex.: CA_BUNDLE_PATH)
+@param checks ...ContentCheck - As validações do conteúdo do arquivo
+
+@return Option - A opção que declara a variável
+*/
+func WithFileContent(key string, checks ...ContentCheck) Option {
+	return func(f *FileEnvLoader) {
+		f.contents = append(f.contents, contentRule{key: key, file: true, checks: checks})
+	}
+}
+
+/*
+GetBytesBase64 retorna o conteúdo binário de uma variável codificada em base64
+
+É GetBase64 sem validações. São aceitas as codificações padrão e URL, com ou sem preenchimento. Segredos isolados
+com WithSecretIsolation também são lidos. O valor não faz parte das mensagens de erro, pois costuma ser uma chave
+ou um certificado.
+
+@param key string - O nome da variável
+
+@return []byte - O conteúdo decodificado
+@return error - ErrKeyNotFound se a variável não existir, ou um erro se o valor não for base64 válido
+*/
+func (f *FileEnvLoader) GetBytesBase64(key string) ([]byte, error) {
+	return f.GetBase64(key)
+}
+
+/*
+GetFileContent retorna o conteúdo do arquivo cujo caminho está em uma variável
+
+@param key string - O nome da variável
+
+@return []byte - O conteúdo do arquivo
+@return error - ErrKeyNotFound se a variável não existir, ou um erro se o arquivo não puder ser lido
+*/
+func (f *FileEnvLoader) GetFileContent(key string) ([]byte, error) {
+	value, err := f.requireContent(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return readContentFile(key, value)
+}
+
+/*
+requireContent lê uma variável de conteúdo, recorrendo aos segredos quando ela não é encontrada por Lookup
+
+@param key string - O nome da variável
+
+@return string - O valor da variável
+@return error - ErrKeyNotFound se a variável não existir
+*/
+func (f *FileEnvLoader) requireContent(key string) (string, error) {
+	if value, ok := f.Lookup(key); ok {
+		return value, nil
+	}
+	if value, ok := f.GetSecret(key); ok {
+		return value, nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+}
+
+/*
+decodeBase64 decodifica um valor em base64 nas codificações padrão ou URL, com ou sem preenchimento
+
+@param key string - O nome da variável, usado na mensagem de erro
+@param value string - O valor
+
+@return []byte - O conteúdo decodificado
+@return error - Um erro se o valor não for base64 válido
+*/
+func decodeBase64(key string, value string) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if content, err := enc.DecodeString(value); err == nil {
+			return content, nil
+		}
+	}
+
+	return nil, fmt.Errorf("variável %s: o valor não é base64 válido", key)
+}
+
+/*
+readContentFile lê o arquivo indicado por uma variável
+
+@param key string - O nome da variável, usado na mensagem de erro
+@param file string - O caminho do arquivo
+
+@return []byte - O conteúdo do arquivo
+@return error - Um erro se o arquivo não puder ser lido
+*/
+func readContentFile(key string, file string) ([]byte, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("variável %s: %w", key, err)
+	}
+
+	return content, nil
+}
+
+/*
+validateContents verifica as variáveis declaradas com WithBase64Content, WithHexContent e WithFileContent
+
+@param values map[string]string - As variáveis a serem aplicadas
+@param secrets map[string]string - As variáveis classificadas como segredo
+
+@return []string - Os problemas encontrados
+*/
+func (f *FileEnvLoader) validateContents(values map[string]string, secrets map[string]string) []string {
+	var problems []string
+	for _, rule := range f.contents {
+		value, ok := values[rule.key]
+		if !ok {
+			if value, ok = secrets[rule.key]; !ok {
+				value, ok = os.LookupEnv(rule.key)
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		var content []byte
+		var err error
+		switch {
+		case rule.file:
+			content, err = readContentFile(rule.key, value)
+		case rule.hex:
+			content, err = decodeHex(rule.key, value)
+		default:
+			content, err = decodeBase64(rule.key, value)
+		}
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+
+		for _, check := range rule.checks {
+			if err := check(content); err != nil {
+				problems = append(problems, fmt.Sprintf("variável %s: %s", rule.key, err.Error()))
+				break
+			}
+		}
+		zeroBytes(content)
+	}
+
+	return problems
+}
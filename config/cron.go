@@ -0,0 +1,273 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+cronField descreve um campo de uma expressão cron
+
+name string - O nome do campo, usado nas mensagens
+min int - O menor valor aceito
+max int - O maior valor aceito
+names []string - Os nomes aceitos no lugar dos números, a partir de min, ou nil
+*/
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names []string
+}
+
+// cronFields são os cinco campos de uma expressão cron padrão, na ordem da expressão.
+var cronFields = []cronField{
+	{name: "minuto", min: 0, max: 59},
+	{name: "hora", min: 0, max: 23},
+	{name: "dia do mês", min: 1, max: 31},
+	{name: "mês", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{name: "dia da semana", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// cronMacros são os atalhos aceitos no lugar dos cinco campos.
+var cronMacros = map[string]bool{
+	"@yearly": true, "@annually": true, "@monthly": true, "@weekly": true,
+	"@daily": true, "@midnight": true, "@hourly": true,
+}
+
+/*
+cronRule é uma variável com uma expressão cron validada durante o carregamento, declarada com WithCron ou
+WithCronEvery
+
+key string - O nome da variável
+every bool - Se o atalho @every <duração> é aceito
+*/
+type cronRule struct {
+	key   string
+	every bool
+}
+
+/*
+WithCron declara variáveis com expressões cron, validadas durante o carregamento
+
+Sem a validação, uma expressão inválida só falha quando o agendador tenta disparar a tarefa pela primeira vez,
+às vezes horas depois da implantação. Se a variável estiver definida, LoadEnv verifica a sintaxe padrão de
+cinco campos (minuto, hora, dia do mês, mês e dia da semana), com listas, intervalos, passos e os nomes dos
+meses e dos dias (JAN, MON), além dos atalhos @yearly, @annually, @monthly, @weekly, @daily, @midnight e
+@hourly. Os problemas são reunidos no *ValidationError do carregamento.
+
+@param keys ...string - Os nomes das variáveis (ex.: CLEANUP_SCHEDULE)
+
+@return Option - A opção que declara as variáveis
+*/
+func WithCron(keys ...string) Option {
+	return func(f *FileEnvLoader) {
+		for _, key := range keys {
+			f.crons = append(f.crons, cronRule{key: key})
+		}
+	}
+}
+
+/*
+WithCronEvery declara variáveis com expressões cron que também aceitam o atalho @every <duração>
+
+Funciona como WithCron, aceitando ainda intervalos fixos, como @every 90s, no formato de time.ParseDuration.
+Use apenas com agendadores que entendem o atalho.
+
+@param keys ...string - Os nomes das variáveis (ex.: SYNC_SCHEDULE)
+
+@return Option - A opção que declara as variáveis
+*/
+func WithCronEvery(keys ...string) Option {
+	return func(f *FileEnvLoader) {
+		for _, key := range keys {
+			f.crons = append(f.crons, cronRule{key: key, every: true})
+		}
+	}
+}
+
+/*
+GetCron retorna o valor de uma variável como uma expressão cron válida
+
+A expressão é validada como em WithCron; o atalho @every só é aceito se a variável tiver sido declarada com
+WithCronEvery. O valor é retornado sem os espaços nas extremidades, pronto para o agendador.
+
+@param key string - O nome da variável
+
+@return string - A expressão cron
+@return error - ErrKeyNotFound se a variável não existir, ou um erro que descreve o problema da expressão
+*/
+func (f *FileEnvLoader) GetCron(key string) (string, error) {
+	value, err := f.require(key)
+	if err != nil {
+		return "", err
+	}
+
+	expr := strings.TrimSpace(value)
+	if err := checkCron(expr, f.cronEvery(key)); err != nil {
+		return "", fmt.Errorf("variável %s: expressão cron %q inválida: %w", key, value, err)
+	}
+
+	return expr, nil
+}
+
+/*
+cronEvery informa se uma variável foi declarada com WithCronEvery
+
+@param key string - O nome da variável
+
+@return bool - Se o atalho @every é aceito
+*/
+func (f *FileEnvLoader) cronEvery(key string) bool {
+	for _, rule := range f.crons {
+		if rule.key == key && rule.every {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+checkCron valida uma expressão cron
+
+@param expr string - A expressão, sem os espaços nas extremidades
+@param every bool - Se o atalho @every <duração> é aceito
+
+@return error - Um erro que descreve o primeiro problema, ou nil
+*/
+func checkCron(expr string, every bool) error {
+	if strings.HasPrefix(expr, "@") {
+		if rest, ok := strings.CutPrefix(expr, "@every"); ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+			if !every {
+				return errors.New("o atalho @every não é aceito; declare a variável com WithCronEvery")
+			}
+			d, err := time.ParseDuration(strings.TrimSpace(rest))
+			if err != nil || d <= 0 {
+				return fmt.Errorf("duração %q inválida em @every", strings.TrimSpace(rest))
+			}
+			return nil
+		}
+		if !cronMacros[strings.ToLower(expr)] {
+			return fmt.Errorf("atalho %s desconhecido", expr)
+		}
+		return nil
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("esperados %d campos, encontrados %d", len(cronFields), len(fields))
+	}
+	for i, text := range fields {
+		if err := cronFields[i].check(text); err != nil {
+			return fmt.Errorf("campo %s: %w", cronFields[i].name, err)
+		}
+	}
+
+	return nil
+}
+
+/*
+check valida o texto de um campo: uma lista de *, valores ou intervalos, cada um com um passo opcional
+
+@param text string - O texto do campo
+
+@return error - Um erro que descreve o problema, ou nil
+*/
+func (c cronField) check(text string) error {
+	for _, item := range strings.Split(text, ",") {
+		span, step, stepped := strings.Cut(item, "/")
+		if stepped {
+			if n, err := strconv.Atoi(step); err != nil || n < 1 {
+				return fmt.Errorf("passo %q inválido", step)
+			}
+		}
+		if span == "*" {
+			continue
+		}
+
+		low, high, ranged := strings.Cut(span, "-")
+		from, err := c.value(low)
+		if err != nil {
+			return err
+		}
+		if !ranged {
+			continue
+		}
+		to, err := c.value(high)
+		if err != nil {
+			return err
+		}
+		if from > to {
+			return fmt.Errorf("intervalo %q invertido", span)
+		}
+	}
+
+	return nil
+}
+
+/*
+value converte um valor de um campo, numérico ou por nome
+
+@param text string - O valor
+
+@return int - O valor numérico
+@return error - Um erro se o valor for inválido ou estiver fora dos limites do campo
+*/
+func (c cronField) value(text string) (int, error) {
+	for i, name := range c.names {
+		if strings.EqualFold(text, name) {
+			return c.min + i, nil
+		}
+	}
+
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("valor %q inválido", text)
+	}
+	if n < c.min || n > c.max {
+		return 0, fmt.Errorf("valor %d fora do intervalo %d-%d", n, c.min, c.max)
+	}
+
+	return n, nil
+}
+
+/*
+validateCrons verifica as variáveis declaradas com WithCron e WithCronEvery
+
+@param values map[string]string - As variáveis a serem aplicadas
+@param secrets map[string]string - As variáveis classificadas como segredo
+
+@return []string - Os problemas encontrados
+*/
+func (f *FileEnvLoader) validateCrons(values map[string]string, secrets map[string]string) []string {
+	var problems []string
+	seen := make(map[string]bool, len(f.crons))
+	for _, rule := range f.crons {
+		if seen[rule.key] {
+			continue
+		}
+		seen[rule.key] = true
+
+		value, ok := values[rule.key]
+		if !ok {
+			value, ok = secrets[rule.key]
+		}
+		if !ok && !f.noProcessEnv {
+			value, ok = os.LookupEnv(rule.key)
+		}
+		if !ok {
+			continue
+		}
+
+		if err := checkCron(strings.TrimSpace(value), f.cronEvery(rule.key)); err != nil {
+			problems = append(problems, fmt.Sprintf("variável %s: expressão cron %q inválida: %s", rule.key, value, err))
+		}
+	}
+
+	return problems
+}
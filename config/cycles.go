@@ -0,0 +1,190 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+/*
+ExpansionCycleError é o erro retornado quando as referências ${VAR} de um arquivo .env formam um ciclo
+
+Como cada referência é expandida com o valor declarado antes dela, um ciclo como A=${B} e B=${A} não causa
+recursão infinita, mas produziria valores vazios sem nenhum aviso. O erro informa a cadeia de variáveis
+envolvidas, na ordem das referências, para que o ciclo possa ser desfeito.
+
+File string - O caminho do arquivo, ou vazio quando o conteúdo não veio de um arquivo
+Keys []string - As variáveis do ciclo; a primeira se repete no fim (ex.: A, B, A)
+Lines []int - A linha da declaração de cada variável de Keys
+*/
+type ExpansionCycleError struct {
+	File  string
+	Keys  []string
+	Lines []int
+}
+
+// Error formata o erro no padrão arquivo:linha, com a cadeia de referências.
+func (e *ExpansionCycleError) Error() string {
+	file := e.File
+	if file == "" {
+		file = "<entrada>"
+	}
+
+	chain := make([]string, len(e.Keys))
+	for i, key := range e.Keys {
+		chain[i] = fmt.Sprintf("%s (linha %d)", key, e.Lines[i])
+	}
+
+	return fmt.Sprintf("%s:%d: referência circular na expansão: %s", file, e.Lines[0], strings.Join(chain, " -> "))
+}
+
+/*
+cycleGraph guarda as referências entre as declarações de um arquivo que podem fazer parte de um ciclo
+
+Todo ciclo passa por uma referência a uma variável declarada depois (referência adiante), já que as demais
+apontam para declarações anteriores. Por isso, o grafo registra apenas as declarações que dependem, direta ou
+indiretamente, de uma referência adiante, e os arquivos sem referências adiante não fazem nenhuma alocação.
+Cada declaração é identificada pela sua linha.
+
+key string - A variável da declaração atual
+refs []string - As referências da declaração atual que precisam ser registradas
+tainted map[string]int - A linha da última declaração de cada variável que depende de uma referência adiante
+pending map[string][]int - As linhas das declarações com uma referência adiante, por variável aguardada
+edges map[int][]int - As declarações referenciadas por cada declaração
+keys map[int]string - A variável de cada declaração do grafo
+*/
+type cycleGraph struct {
+	key     string
+	refs    []string
+	tainted map[string]int
+	pending map[string][]int
+	edges   map[int][]int
+	keys    map[int]string
+}
+
+/*
+noteRef registra uma referência da declaração atual, se ela puder fazer parte de um ciclo
+
+Uma referência da variável a si mesma sem declaração anterior, como PATH=${PATH}:/bin, não é uma referência
+adiante: ela se refere a um valor anterior que não existe e continua sendo expandida como vazia.
+
+@param name string - A variável referenciada
+@param declared bool - Se a variável já foi declarada antes no arquivo
+*/
+func (p *parser) noteRef(name string, declared bool) {
+	if !declared {
+		if name == p.cycles.key {
+			return
+		}
+		p.cycles.refs = append(p.cycles.refs, name)
+		return
+	}
+	if _, ok := p.cycles.tainted[name]; ok {
+		p.cycles.refs = append(p.cycles.refs, name)
+	}
+}
+
+/*
+declare adiciona ao grafo a declaração de uma variável, depois da expansão do seu valor
+
+As referências adiante que aguardavam a variável passam a apontar para esta declaração, e as referências
+registradas por noteRef passam a sair dela.
+
+@param key string - A variável declarada
+@param line int - A linha da declaração
+*/
+func (p *parser) declare(key string, line int) {
+	g := &p.cycles
+	if sources, ok := g.pending[key]; ok {
+		for _, source := range sources {
+			g.edges[source] = append(g.edges[source], line)
+		}
+		g.keys[line] = key
+		delete(g.pending, key)
+	}
+
+	if len(g.refs) == 0 {
+		delete(g.tainted, key)
+		return
+	}
+
+	if g.tainted == nil {
+		g.tainted = make(map[string]int)
+		g.pending = make(map[string][]int)
+		g.edges = make(map[int][]int)
+		g.keys = make(map[int]string)
+	}
+	for _, name := range g.refs {
+		if _, declared := p.vars[name]; declared {
+			g.edges[line] = append(g.edges[line], g.tainted[name])
+			continue
+		}
+		g.pending[name] = append(g.pending[name], line)
+	}
+	g.keys[line] = key
+	g.tainted[key] = line
+	g.refs = g.refs[:0]
+}
+
+/*
+checkCycles procura um ciclo no grafo de referências, ao fim do arquivo
+
+@return error - Um *ExpansionCycleError com o primeiro ciclo encontrado, na ordem das linhas, ou nil
+*/
+func (p *parser) checkCycles() error {
+	g := &p.cycles
+	if len(g.edges) == 0 {
+		return nil
+	}
+
+	lines := make([]int, 0, len(g.edges))
+	for line := range g.edges {
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[int]int)
+	var stack []int
+
+	var visit func(line int) []int
+	visit = func(line int) []int {
+		state[line] = visiting
+		stack = append(stack, line)
+		for _, next := range g.edges[line] {
+			switch state[next] {
+			case visiting:
+				for i, l := range stack {
+					if l == next {
+						return append(append([]int(nil), stack[i:]...), next)
+					}
+				}
+			case 0:
+				if cycle := visit(next); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[line] = done
+		return nil
+	}
+
+	for _, line := range lines {
+		if state[line] != 0 {
+			continue
+		}
+		if cycle := visit(line); cycle != nil {
+			err := &ExpansionCycleError{File: p.file, Lines: cycle}
+			for _, l := range cycle {
+				err.Keys = append(err.Keys, g.keys[l])
+			}
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/*
+WithDecimalComma aceita a vírgula como separador decimal em GetFloat (ex.: RATE=0,5)
+
+É indicado para arquivos editados por pessoas que não são desenvolvedoras, em locais que escrevem números com
+vírgula. Sem o modo estrito, a vírgula é o separador decimal e o ponto é o separador de milhares quando os dois
+aparecem (1.234,5), e o último separador decide nos demais casos (1,234.5). No modo estrito, valores ambíguos
+são rejeitados: aqueles com os dois separadores, com mais de um separador ou com exatamente três dígitos após
+o único separador (1,234 e 1.234 podem ser mil e duzentos e trinta e quatro). O erro informa o arquivo e a linha
+que definiram a variável.
+
+@param strict bool - Se os valores ambíguos devem ser rejeitados
+
+@return Option - A opção que ativa a vírgula decimal
+*/
+func WithDecimalComma(strict bool) Option {
+	return func(f *FileEnvLoader) {
+		f.decimalComma = true
+		f.strictDecimals = strict
+	}
+}
+
+/*
+parseDecimal converte um valor que pode usar a vírgula como separador decimal
+
+@param key string - O nome da variável, usado na mensagem de erro
+@param value string - O valor
+
+@return float64 - O valor convertido
+@return error - Um erro de conversão, ou de ambiguidade no modo estrito
+*/
+func (f *FileEnvLoader) parseDecimal(key string, value string) (float64, error) {
+	s := strings.TrimSpace(value)
+	commas, dots := strings.Count(s, ","), strings.Count(s, ".")
+
+	ambiguous := commas+dots > 1
+	if commas+dots == 1 {
+		sep := strings.LastIndexAny(s, ",.")
+		ambiguous = len(s)-sep-1 == 3
+	}
+	if f.strictDecimals && ambiguous {
+		return 0, fmt.Errorf("variável %s%s: valor %q é ambíguo; use um único separador decimal, sem separador de milhares", key, f.location(key), value)
+	}
+
+	decimalSep, groupSep := ",", "."
+	switch {
+	case dots > 0 && strings.LastIndex(s, ".") > strings.LastIndex(s, ",") && (commas > 0 || dots == 1):
+		decimalSep, groupSep = ".", ","
+	case commas > 1 && dots == 0:
+		groupSep = ","
+	}
+	s = strings.ReplaceAll(strings.ReplaceAll(s, groupSep, ""), decimalSep, ".")
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("variável %s%s: valor %q não é um float válido", key, f.location(key), value)
+	}
+
+	return n, nil
+}
+
+/*
+location retorna a posição em que uma variável foi definida, no formato " (arquivo:linha)"
+
+@param key string - O nome da variável
+
+@return string - A posição, ou uma string vazia se a variável não veio de um arquivo
+*/
+func (f *FileEnvLoader) location(key string) string {
+	line, ok := f.lines[key]
+	if !ok || f.sources[key] == SourceProcess {
+		return ""
+	}
+
+	return fmt.Sprintf(" (%s:%d)", f.sources[key], line)
+}
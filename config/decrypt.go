@@ -0,0 +1,298 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// encryptedPrefix é o marcador que identifica um valor cifrado no arquivo .env (ex.: enc:kms:AQICAH...)
+const encryptedPrefix = "enc:"
+
+/*
+Decrypter é uma interface que define como um valor cifrado é decifrado
+
+Cada backend (AWS KMS, GCP KMS, chave local etc.) implementa Decrypter e é registrado no carregador
+com a opção WithDecrypter, associado ao nome usado no marcador "enc:<backend>:".
+
+Decrypt recebe o texto cifrado já decodificado de base64 e retorna o texto em claro.
+@param ciphertext []byte - O texto cifrado
+@return []byte - O texto em claro
+@return error - Um erro se o valor não puder ser decifrado
+*/
+type Decrypter interface {
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+/*
+DecrypterFunc é um adaptador que permite usar uma função comum como Decrypter
+
+@param ciphertext []byte - O texto cifrado
+
+@return []byte - O texto em claro
+@return error - Um erro se o valor não puder ser decifrado
+*/
+type DecrypterFunc func(ciphertext []byte) ([]byte, error)
+
+// Decrypt chama a própria função com o texto cifrado recebido.
+func (fn DecrypterFunc) Decrypt(ciphertext []byte) ([]byte, error) {
+	return fn(ciphertext)
+}
+
+/*
+Encrypter é uma interface que define como um valor é cifrado, usada para produzir valores "enc:<backend>:"
+
+Encrypt recebe o texto em claro e retorna o texto cifrado, que será codificado em base64.
+@param plaintext []byte - O texto em claro
+@return []byte - O texto cifrado
+@return error - Um erro se o valor não puder ser cifrado
+*/
+type Encrypter interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+}
+
+/*
+EncrypterFunc é um adaptador que permite usar uma função comum como Encrypter
+
+@param plaintext []byte - O texto em claro
+
+@return []byte - O texto cifrado
+@return error - Um erro se o valor não puder ser cifrado
+*/
+type EncrypterFunc func(plaintext []byte) ([]byte, error)
+
+// Encrypt chama a própria função com o texto em claro recebido.
+func (fn EncrypterFunc) Encrypt(plaintext []byte) ([]byte, error) {
+	return fn(plaintext)
+}
+
+/*
+DecryptError é o erro retornado quando o valor cifrado de uma variável não pode ser decifrado
+
+Key string - O nome da variável
+Err error - O erro do formato, do registro de backends ou do Decrypter
+*/
+type DecryptError struct {
+	Key string
+	Err error
+}
+
+// Error identifica a variável e o motivo da falha.
+func (e *DecryptError) Error() string {
+	return fmt.Sprintf("erro ao decifrar a variável %s: %s", e.Key, e.Err.Error())
+}
+
+// Unwrap retorna o erro original, para uso com errors.Is e errors.As.
+func (e *DecryptError) Unwrap() error {
+	return e.Err
+}
+
+/*
+IsEncrypted informa se um valor possui o marcador de cifra "enc:"
+
+@param value string - O valor
+
+@return bool - Se o valor está cifrado
+*/
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+/*
+EncryptValue cifra um valor e o formata como "enc:<backend>:<base64>", pronto para ser escrito em um arquivo .env
+
+@param backend string - O nome do backend usado no marcador
+@param e Encrypter - O Encrypter responsável pelo backend
+@param plaintext string - O valor em claro
+
+@return string - O valor cifrado com o marcador
+@return error - Um erro se o valor não puder ser cifrado
+*/
+func EncryptValue(backend string, e Encrypter, plaintext string) (string, error) {
+	if backend == "" || strings.Contains(backend, ":") {
+		return "", fmt.Errorf("nome de backend inválido: %q", backend)
+	}
+
+	input := []byte(plaintext)
+	defer zeroBytes(input)
+
+	ciphertext, err := e.Encrypt(input)
+	if err != nil {
+		return "", err
+	}
+
+	return encryptedPrefix + backend + ":" + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+/*
+DecryptValue decifra um único valor no formato "enc:<backend>:<base64>" com o Decrypter informado
+
+@param value string - O valor com o marcador de cifra
+@param backend string - O backend que o Decrypter atende
+@param d Decrypter - O Decrypter responsável pelo backend
+
+@return string - O valor em claro
+@return error - Um erro se o formato for inválido, o valor usar outro backend ou a decifragem falhar
+*/
+func DecryptValue(value string, backend string, d Decrypter) (string, error) {
+	return (&FileEnvLoader{decrypters: map[string]Decrypter{backend: d}}).decryptValue(value)
+}
+
+/*
+WithDecrypter registra um Decrypter para o backend informado
+
+Os valores no formato "enc:<backend>:<base64>" são decifrados pelo Decrypter registrado para <backend>.
+Por exemplo, WithDecrypter("kms", awsKMS) faz com que DB_PASSWORD=enc:kms:AQICAH... seja decifrado por awsKMS.
+
+@param backend string - O nome do backend usado no marcador
+@param d Decrypter - O Decrypter responsável pelo backend
+
+@return Option - A opção que registra o Decrypter
+*/
+func WithDecrypter(backend string, d Decrypter) Option {
+	return func(f *FileEnvLoader) {
+		f.decrypters[backend] = d
+	}
+}
+
+/*
+decryptValues decifra, no próprio mapa, todos os valores que possuem o marcador "enc:<backend>:"
+
+Valores sem o marcador permanecem inalterados. Se um valor cifrado usar um backend sem Decrypter registrado,
+ou se o conteúdo não puder ser decodificado ou decifrado, a função retorna um *DecryptError que identifica a variável.
+
+@param values map[string]string - As variáveis lidas do arquivo .env
+
+@return map[string]bool - As chaves cujos valores estavam cifrados
+@return error - Um erro se algum valor cifrado não puder ser decifrado
+*/
+func (f *FileEnvLoader) decryptValues(values map[string]string) (map[string]bool, error) {
+	encrypted := make(map[string]bool)
+
+	for key, value := range values {
+		if !strings.HasPrefix(value, encryptedPrefix) {
+			continue
+		}
+
+		plaintext, err := f.decryptValue(value)
+		if err != nil {
+			return nil, &DecryptError{Key: key, Err: err}
+		}
+		values[key] = plaintext
+		encrypted[key] = true
+	}
+
+	return encrypted, nil
+}
+
+/*
+decryptValue decifra um único valor no formato "enc:<backend>:<base64>"
+
+@param value string - O valor com o marcador de cifra
+
+@return string - O valor em claro
+@return error - Um erro se o formato for inválido, o backend não estiver registrado ou a decifragem falhar
+*/
+func (f *FileEnvLoader) decryptValue(value string) (string, error) {
+	backend, payload, found := strings.Cut(strings.TrimPrefix(value, encryptedPrefix), ":")
+	if !found || backend == "" || payload == "" {
+		return "", fmt.Errorf("formato inválido, esperado enc:<backend>:<base64>")
+	}
+
+	d, ok := f.decrypters[backend]
+	if !ok {
+		return "", fmt.Errorf("nenhum Decrypter registrado para o backend %q", backend)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", fmt.Errorf("conteúdo base64 inválido: %s", err.Error())
+	}
+
+	plaintext, err := f.decryptWithRetry(backend, d, ciphertext)
+	if err != nil {
+		zeroBytes(ciphertext)
+		return "", err
+	}
+
+	value = string(plaintext)
+	zeroBytes(plaintext)
+	zeroBytes(ciphertext)
+
+	return value, nil
+}
+
+/*
+LocalKeyDecrypter é um Decrypter que usa uma chave simétrica local (AES-GCM)
+
+É indicado para desenvolvimento e para times que distribuem a chave por fora do repositório.
+O texto cifrado deve conter o nonce seguido do conteúdo selado por AES-GCM. LocalKeyDecrypter também
+implementa Encrypter, produzindo valores no mesmo formato.
+*/
+type LocalKeyDecrypter struct {
+	aead cipher.AEAD
+}
+
+/*
+NewLocalKeyDecrypter cria um LocalKeyDecrypter a partir de uma chave AES de 16, 24 ou 32 bytes
+
+@param key []byte - A chave AES
+
+@return *LocalKeyDecrypter - O Decrypter criado
+@return error - Um erro se a chave tiver um tamanho inválido
+*/
+func NewLocalKeyDecrypter(key []byte) (*LocalKeyDecrypter, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LocalKeyDecrypter{aead: aead}, nil
+}
+
+// Name retorna o nome do provedor de chave local.
+func (d *LocalKeyDecrypter) Name() string {
+	return "local"
+}
+
+/*
+Decrypt separa o nonce do início do texto cifrado e abre o conteúdo com AES-GCM
+
+@param ciphertext []byte - O nonce seguido do conteúdo selado
+
+@return []byte - O texto em claro
+@return error - Um erro se o texto cifrado for curto demais ou não puder ser autenticado
+*/
+func (d *LocalKeyDecrypter) Decrypt(ciphertext []byte) ([]byte, error) {
+	nonceSize := d.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, fmt.Errorf("texto cifrado menor que o nonce")
+	}
+
+	return d.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], nil)
+}
+
+/*
+Encrypt sela o texto em claro com AES-GCM e um nonce aleatório, que é colocado no início do resultado
+
+@param plaintext []byte - O texto em claro
+
+@return []byte - O nonce seguido do conteúdo selado
+@return error - Um erro se o nonce não puder ser gerado
+*/
+func (d *LocalKeyDecrypter) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, d.aead.NonceSize(), d.aead.NonceSize()+len(plaintext)+d.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return d.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+/*
+deprecation é uma variável renomeada
+
+old string - O nome antigo
+new string - O nome novo
+since string - A versão em que o nome antigo se tornou obsoleto
+*/
+type deprecation struct {
+	old   string
+	new   string
+	since string
+}
+
+/*
+Deprecate declara que uma variável foi renomeada
+
+Quando o nome antigo é definido em um arquivo .env ou no processo e o novo não, o carregador define o nome novo
+com o mesmo valor, para que o código leia apenas o nome novo. Em todos os casos, o uso do nome antigo gera um
+aviso com o arquivo e a linha, registrado no log e em Result.Warnings. Se os dois nomes forem definidos, o novo
+prevalece. Com WithStrictDeprecations, o uso do nome antigo passa a ser um erro depois do prazo.
+
+@param oldKey string - O nome antigo
+@param newKey string - O nome novo
+@param since string - A versão em que o nome antigo se tornou obsoleto (ex.: v2.0)
+
+@return Option - A opção que declara a renomeação
+*/
+func Deprecate(oldKey string, newKey string, since string) Option {
+	return func(f *FileEnvLoader) {
+		f.deprecations = append(f.deprecations, deprecation{old: oldKey, new: newKey, since: since})
+	}
+}
+
+/*
+WithStrictDeprecations faz com que o uso de variáveis obsoletas seja um erro a partir de uma data
+
+Até a data, o uso gera apenas avisos, dando tempo para que todos os serviços migrem. Depois dela, LoadEnv
+retorna um *ValidationError com as variáveis obsoletas encontradas, sem aplicar nada ao ambiente do processo.
+
+@param after time.Time - O fim do período de transição; o valor zero torna a verificação estrita imediatamente
+
+@return Option - A opção que ativa a verificação estrita
+*/
+func WithStrictDeprecations(after time.Time) Option {
+	return func(f *FileEnvLoader) {
+		f.strictDeprecations = true
+		f.deprecationDeadline = after
+	}
+}
+
+/*
+applyDeprecations mapeia as variáveis obsoletas para os nomes novos
+
+@param values map[string]string - As variáveis lidas dos arquivos, alteradas no próprio mapa
+@param origins map[string]origin - A declaração que definiu cada variável, alterada no próprio mapa
+
+@return []Warning - Os avisos gerados
+@return error - Um *ValidationError se a verificação estrita estiver vencida e alguma variável obsoleta for usada
+*/
+func (f *FileEnvLoader) applyDeprecations(values map[string]string, origins map[string]origin) ([]Warning, error) {
+	strict := f.strictDeprecations && !time.Now().Before(f.deprecationDeadline)
+
+	var warnings []Warning
+	var problems []string
+	for _, d := range f.deprecations {
+		value, ok := values[d.old]
+		where := ""
+		w := Warning{Kind: WarningDeprecated, Key: d.old}
+		if ok {
+			where = fmt.Sprintf(" (%s:%d)", origins[d.old].file, origins[d.old].line)
+			w.File, w.Line = origins[d.old].file, origins[d.old].line
+		} else if value, ok = os.LookupEnv(d.old); ok {
+			where = " (processo)"
+		} else {
+			continue
+		}
+
+		message := fmt.Sprintf("variável %s%s está obsoleta desde %s; use %s", d.old, where, d.since, d.new)
+		if strict {
+			problems = append(problems, message)
+			continue
+		}
+		w.Message = message
+		f.warn(w)
+		warnings = append(warnings, w)
+
+		if _, defined := values[d.new]; defined {
+			continue
+		}
+		if _, defined := os.LookupEnv(d.new); defined {
+			continue
+		}
+		values[d.new] = value
+		if o, fromFile := origins[d.old]; fromFile {
+			origins[d.new] = o
+		} else {
+			origins[d.new] = origin{file: SourceProcess, rank: len(origins)}
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	return warnings, nil
+}
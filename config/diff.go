@@ -0,0 +1,191 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+/*
+DiffKind descreve como uma variável mudou entre duas configurações
+*/
+type DiffKind string
+
+const (
+	// DiffAdded indica que a variável existe apenas na segunda configuração.
+	DiffAdded DiffKind = "added"
+	// DiffRemoved indica que a variável existe apenas na primeira configuração.
+	DiffRemoved DiffKind = "removed"
+	// DiffChanged indica que a variável existe nas duas configurações com valores diferentes.
+	DiffChanged DiffKind = "changed"
+)
+
+/*
+KeyChange descreve a mudança de uma variável entre duas configurações
+
+Key string - O nome da variável
+Kind DiffKind - Como a variável mudou
+Old string - O valor na primeira configuração, vazio se a variável foi adicionada (mascarado se for um segredo)
+New string - O valor na segunda configuração, vazio se a variável foi removida (mascarado se for um segredo)
+Secret bool - Se a variável é classificada como segredo em alguma das configurações
+*/
+type KeyChange struct {
+	Key    string   `json:"key"`
+	Kind   DiffKind `json:"kind"`
+	Old    string   `json:"old"`
+	New    string   `json:"new"`
+	Secret bool     `json:"secret"`
+}
+
+/*
+ConfigDiff é o resultado da comparação entre duas configurações
+
+OldEnv string - O ambiente da primeira configuração
+NewEnv string - O ambiente da segunda configuração
+Changes []KeyChange - As mudanças, ordenadas pelo nome da variável
+*/
+type ConfigDiff struct {
+	OldEnv  string      `json:"old_env"`
+	NewEnv  string      `json:"new_env"`
+	Changes []KeyChange `json:"changes"`
+}
+
+/*
+Diff compara duas configurações e retorna as variáveis adicionadas, removidas e alteradas
+
+Os segredos, inclusive os isolados com WithSecretIsolation, fazem parte da comparação, mas os seus valores são
+mascarados, de modo que o resultado pode ser registrado em logs. Para registrar o que uma recarga mudou, basta
+comparar as visões obtidas antes e depois dela:
+
+	before := loader.Snapshot()
+	if err := loader.Reload(); err == nil {
+		log.Print(config.Diff(before, loader.Snapshot()))
+	}
+
+@param a ConfigView - A primeira configuração, como a anterior a uma recarga
+@param b ConfigView - A segunda configuração
+
+@return ConfigDiff - As diferenças entre as configurações
+*/
+func Diff(a ConfigView, b ConfigView) ConfigDiff {
+	ra, rb := a.reader(), b.reader()
+	oldValues, newValues := ra.effectiveValues(), rb.effectiveValues()
+	diff := ConfigDiff{OldEnv: ra.Env, NewEnv: rb.Env}
+
+	for key, old := range oldValues {
+		_, secretA := ra.secrets[key]
+		_, secretB := rb.secrets[key]
+		change := KeyChange{Key: key, Old: old, Secret: secretA || secretB}
+
+		current, ok := newValues[key]
+		switch {
+		case !ok:
+			change.Kind = DiffRemoved
+		case current != old:
+			change.Kind, change.New = DiffChanged, current
+		default:
+			continue
+		}
+		diff.Changes = append(diff.Changes, change.masked())
+	}
+
+	for key, current := range newValues {
+		if _, ok := oldValues[key]; ok {
+			continue
+		}
+		_, secret := rb.secrets[key]
+		diff.Changes = append(diff.Changes, KeyChange{Key: key, Kind: DiffAdded, New: current, Secret: secret}.masked())
+	}
+
+	sort.Slice(diff.Changes, func(i, j int) bool {
+		return diff.Changes[i].Key < diff.Changes[j].Key
+	})
+
+	return diff
+}
+
+/*
+effectiveValues retorna as variáveis carregadas junto com os segredos isolados
+
+@return map[string]string - Todas as variáveis conhecidas pelo carregador
+*/
+func (f *FileEnvLoader) effectiveValues() map[string]string {
+	values := make(map[string]string, len(f.values)+len(f.secrets))
+	for key, value := range f.values {
+		values[key] = value
+	}
+	for key, value := range f.secrets {
+		values[key] = value
+	}
+
+	return values
+}
+
+// masked retorna a mudança com os valores substituídos por maskedValue quando a variável é um segredo.
+func (c KeyChange) masked() KeyChange {
+	if !c.Secret {
+		return c
+	}
+	if c.Kind != DiffAdded {
+		c.Old = maskedValue
+	}
+	if c.Kind != DiffRemoved {
+		c.New = maskedValue
+	}
+
+	return c
+}
+
+/*
+Empty informa se as configurações são idênticas
+
+@return bool - Se não há nenhuma mudança, nem de ambiente
+*/
+func (d ConfigDiff) Empty() bool {
+	return d.OldEnv == d.NewEnv && len(d.Changes) == 0
+}
+
+// Added retorna os nomes das variáveis adicionadas.
+func (d ConfigDiff) Added() []string { return d.keys(DiffAdded) }
+
+// Removed retorna os nomes das variáveis removidas.
+func (d ConfigDiff) Removed() []string { return d.keys(DiffRemoved) }
+
+// Changed retorna os nomes das variáveis alteradas.
+func (d ConfigDiff) Changed() []string { return d.keys(DiffChanged) }
+
+// keys retorna os nomes das variáveis com a mudança informada, na ordem do resultado.
+func (d ConfigDiff) keys(kind DiffKind) []string {
+	var keys []string
+	for _, c := range d.Changes {
+		if c.Kind == kind {
+			keys = append(keys, c.Key)
+		}
+	}
+
+	return keys
+}
+
+/*
+String formata as diferenças em uma linha por mudança, no estilo de um diff, própria para logs e revisões
+
+@return string - As diferenças, ou uma string vazia se as configurações forem idênticas
+*/
+func (d ConfigDiff) String() string {
+	var b strings.Builder
+	if d.OldEnv != d.NewEnv {
+		fmt.Fprintf(&b, "~ APP_ENV: %s -> %s\n", d.OldEnv, d.NewEnv)
+	}
+	for _, c := range d.Changes {
+		switch c.Kind {
+		case DiffAdded:
+			fmt.Fprintf(&b, "+ %s=%s\n", c.Key, c.New)
+		case DiffRemoved:
+			fmt.Fprintf(&b, "- %s=%s\n", c.Key, c.Old)
+		case DiffChanged:
+			fmt.Fprintf(&b, "~ %s: %s -> %s\n", c.Key, c.Old, c.New)
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,37 @@
+/*
+Package config localiza, lê e aplica a configuração de arquivos .env, com validação, segredos e recarga
+
+# Concorrência
+
+Um FileEnvLoader pertence à goroutine que o carrega. LoadEnv, LoadEnvResult, Reload e Plan alteram o estado do
+carregador e não podem ser chamados ao mesmo tempo entre si nem com as leituras do próprio carregador (GetString,
+Lookup, All, Warnings, Tenant e as demais); as leituras podem ser feitas de várias goroutines enquanto nenhum
+carregamento estiver em andamento, por exemplo depois da inicialização.
+
+Para ler a configuração de várias goroutines enquanto ela é recarregada, publique uma ConfigView: Snapshot copia
+as variáveis no momento da chamada e a visão é imutável, segura para leitura concorrente sem bloqueios. Chame
+Snapshot na goroutine que recarrega, como em OnReload de um PushListener, e publique a visão com um
+atomic.Pointer:
+
+	var current atomic.Pointer[config.ConfigView]
+	listener := &config.PushListener{Loader: loader, Secret: secret, OnReload: func(config.ChangeNotification, error) {
+		view := loader.Snapshot()
+		current.Store(&view)
+	}}
+
+São seguros a partir de qualquer goroutine, inclusive durante um carregamento: Health e HealthContext, Freeze,
+Frozen e Close; EnsureLoaded; os métodos de ConfigView e de Plugin; e Accept e ServeHTTP de PushListener, que
+serializam as suas recargas. FrozenDrift e WatchFrozen são seguros depois de Freeze, quando os carregamentos
+passam a ser recusados. Tenant é seguro para chamadas concorrentes entre si, mas não durante um Reload.
+
+Alguns componentes executam código da aplicação em goroutines da biblioteca: os callbacks de WithRotationHook
+(depois do intervalo de WithRotationDebounce), a função de WatchFrozen e as recargas e o OnReload de um
+PushListener (nas goroutines do servidor HTTP ou de ListenSSE). As funções de WithWarningHandler e
+WithErrorHandler podem ser chamadas a partir dessas goroutines, inclusive ao mesmo tempo, e devem ser seguras
+para uso concorrente. Close encerra essas goroutines e aguarda o seu término.
+
+O diretório test contém uma suíte de concorrência que verifica esse modelo com o detector de corridas e o goleak:
+
+	go test -race ./test
+*/
+package config
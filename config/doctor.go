@@ -0,0 +1,194 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+)
+
+/*
+Candidate é um arquivo considerado durante a descoberta
+
+Path string - O caminho do arquivo
+Profile string - O perfil do arquivo, como aparece no nome
+Selected bool - Se o arquivo foi selecionado para o carregamento
+Reason string - A decisão da descoberta e o seu motivo
+*/
+type Candidate struct {
+	Path     string `json:"path"`
+	Profile  string `json:"profile"`
+	Selected bool   `json:"selected"`
+	Reason   string `json:"reason"`
+}
+
+/*
+Diagnosis é o relatório produzido por Diagnose
+
+AppEnv string - O valor normalizado de APP_ENV
+Candidates []Candidate - Os arquivos considerados durante a descoberta
+Files []string - Os arquivos que seriam carregados, na ordem em que seriam aplicados
+Problems []string - Os problemas encontrados, cada um com a ação necessária
+Warnings []string - Os avisos, que não impedem o carregamento
+*/
+type Diagnosis struct {
+	AppEnv     string      `json:"app_env"`
+	Candidates []Candidate `json:"candidates"`
+	Files      []string    `json:"files"`
+	Problems   []string    `json:"problems"`
+	Warnings   []string    `json:"warnings"`
+}
+
+// OK informa se o diagnóstico não encontrou problemas.
+func (d *Diagnosis) OK() bool {
+	return len(d.Problems) == 0
+}
+
+/*
+Diagnose verifica a configuração do ambiente e produz um relatório único e acionável
+
+O diagnóstico verifica se APP_ENV está definido, lista os arquivos candidatos e quais seriam selecionados,
+valida a sintaxe de todos os candidatos, verifica as variáveis obrigatórias (WithRequired) e as permissões
+dos arquivos selecionados. Nada é aplicado ao ambiente do processo.
+
+@param opts ...Option - As opções do carregador a ser diagnosticado
+
+@return *Diagnosis - O relatório do diagnóstico
+*/
+func Diagnose(opts ...Option) *Diagnosis {
+	f := NewEnvLoader(opts...).(*FileEnvLoader)
+
+	d := &Diagnosis{AppEnv: f.Env}
+	if f.Env == "" {
+		d.Problems = append(d.Problems, "APP_ENV não está definido: defina APP_ENV com o ambiente desejado (ex.: APP_ENV=development)")
+	}
+
+	f.candidates = &d.Candidates
+	envFile, _, err := f.findEnvFile()
+	f.candidates = nil
+
+	for i := range d.Candidates {
+		d.Candidates[i].Selected = d.Candidates[i].Path == envFile
+		if _, err := f.loadEnvFile(d.Candidates[i].Path); err != nil {
+			var parseErr *ParseError
+			if errors.As(err, &parseErr) {
+				err = parseErr
+			}
+			d.Problems = append(d.Problems, fmt.Sprintf("sintaxe inválida: %s", err))
+		}
+	}
+
+	switch {
+	case err != nil:
+		d.Problems = append(d.Problems, fmt.Sprintf("erro durante a descoberta: %s", err))
+		return d
+	case envFile == "":
+		d.Problems = append(d.Problems, fmt.Sprintf("nenhum arquivo .env.%s encontrado: crie o arquivo ou verifique o diretório de trabalho e o valor de APP_ENV", f.Env))
+		return d
+	}
+
+	d.Files = []string{envFile}
+	res, err := f.resolve()
+	if err == nil {
+		d.Files = res.files
+		d.Warnings = warningMessages(res.warnings)
+	}
+
+	var validationErr *ValidationError
+	var parseErr *ParseError
+	switch {
+	case errors.As(err, &validationErr):
+		d.Problems = append(d.Problems, validationErr.Problems...)
+	case errors.As(err, &parseErr):
+		// Os erros de sintaxe já foram relatados na validação dos candidatos.
+	case err != nil:
+		d.Problems = append(d.Problems, err.Error())
+	}
+
+	for _, file := range d.Files {
+		d.Problems = append(d.Problems, permissionProblems(file, f.fileHasSecrets(file))...)
+	}
+
+	return d
+}
+
+/*
+fileHasSecrets informa se um arquivo .env declara alguma variável classificada como segredo
+
+@param file string - O caminho do arquivo
+
+@return bool - Se o arquivo declara algum segredo
+*/
+func (f *FileEnvLoader) fileHasSecrets(file string) bool {
+	entries, err := f.loadEnvFile(file)
+	if err != nil {
+		return false
+	}
+
+	for _, e := range entries {
+		if strings.HasPrefix(e.value, encryptedPrefix) || f.isSecret(e.key, nil) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+permissionProblems verifica as permissões de um arquivo .env
+
+Arquivos graváveis por outros usuários são sempre um problema; arquivos com segredos não devem ser legíveis
+por outros usuários. A verificação é ignorada no Windows, onde as permissões Unix não se aplicam.
+
+@param file string - O caminho do arquivo
+@param secrets bool - Se o arquivo contém segredos
+
+@return []string - Os problemas de permissão encontrados
+*/
+func permissionProblems(file string, secrets bool) []string {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return []string{fmt.Sprintf("não foi possível verificar as permissões de %s: %s", file, err)}
+	}
+
+	mode := info.Mode().Perm()
+	var problems []string
+	if mode&0o022 != 0 {
+		problems = append(problems, fmt.Sprintf("%s pode ser alterado por outros usuários (%#o): execute chmod go-w %s", file, mode, file))
+	}
+	if secrets && mode&0o004 != 0 {
+		problems = append(problems, fmt.Sprintf("%s contém segredos e pode ser lido por outros usuários (%#o): execute chmod 600 %s", file, mode, file))
+	}
+
+	return problems
+}
+
+/*
+ExampleKeys retorna os nomes das variáveis declaradas em um arquivo de exemplo, como o .env.example
+
+O arquivo de exemplo funciona como um esquema simples: as variáveis declaradas nele podem ser exigidas
+com WithRequired(keys...), como faz o comando locenv doctor.
+
+@param path string - O caminho do arquivo de exemplo
+
+@return []string - Os nomes das variáveis, na ordem do arquivo
+@return error - Um erro se o arquivo não puder ser lido ou interpretado
+*/
+func ExampleKeys(path string) ([]string, error) {
+	entries, err := (&FileEnvLoader{}).loadEnvFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(entries))
+	for _, e := range entries {
+		keys = append(keys, e.key)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,421 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DriverPostgres identifica as strings de conexão do PostgreSQL (postgres://).
+	DriverPostgres = "postgres"
+	// DriverMySQL identifica as strings de conexão do MySQL, no formato do go-sql-driver (user:pass@tcp(host:port)/db).
+	DriverMySQL = "mysql"
+	// DriverMongo identifica as strings de conexão do MongoDB (mongodb:// ou mongodb+srv://).
+	DriverMongo = "mongodb"
+)
+
+// defaultDBPorts são as portas usadas quando a string de conexão ou <prefixo>_PORT não informam uma.
+var defaultDBPorts = map[string]int{DriverPostgres: 5432, DriverMySQL: 3306, DriverMongo: 27017}
+
+// passwordParams são os parâmetros que carregam senhas, mascarados por Redacted e RedactDSN.
+var passwordParams = map[string]bool{"password": true, "pass": true, "pwd": true, "sslpassword": true}
+
+// passwordParamPattern encontra os parâmetros de senha em uma query (?password=x&) ou no formato chave=valor (password='x y').
+var passwordParamPattern = regexp.MustCompile(`(?i)(^|[?&;\s])(password|pass|pwd|sslpassword)(\s*=\s*)('(?:\\.|[^'\\])*'|[^&;\s]*)`)
+
+// postgresSSLModes são os valores aceitos por sslmode no PostgreSQL.
+var postgresSSLModes = map[string]bool{"disable": true, "allow": true, "prefer": true, "require": true, "verify-ca": true, "verify-full": true}
+
+/*
+DSN são os componentes de uma string de conexão com um banco de dados
+
+Driver string - DriverPostgres, DriverMySQL ou DriverMongo
+Host string - O endereço do servidor
+Port int - A porta do servidor, zero em conexões mongodb+srv
+User string - O usuário
+Password string - A senha, nunca incluída em Redacted nem nas mensagens de erro
+Database string - O nome do banco de dados
+Params map[string]string - Os parâmetros adicionais (ex.: sslmode)
+SRV bool - Se a conexão do MongoDB usa registros SRV (mongodb+srv://)
+*/
+type DSN struct {
+	Driver   string
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Database string
+	Params   map[string]string
+	SRV      bool
+}
+
+/*
+ParseDSN separa uma string de conexão nos seus componentes
+
+São aceitos os formatos postgres:// e postgresql://, mongodb:// e mongodb+srv://, e o formato do go-sql-driver
+para o MySQL (user:pass@tcp(host:port)/db?params), com ou sem o prefixo mysql://. As mensagens de erro nunca
+incluem a string recebida, já que uma string malformada pode não ter a senha onde a máscara a procuraria.
+
+@param raw string - A string de conexão
+
+@return DSN - Os componentes
+@return error - Um erro se o formato não for reconhecido ou a string for inválida
+*/
+func ParseDSN(raw string) (DSN, error) {
+	scheme, _, hasScheme := strings.Cut(raw, "://")
+	switch {
+	case hasScheme && (scheme == "postgres" || scheme == "postgresql"):
+		return parseURLDSN(DriverPostgres, raw)
+	case hasScheme && (scheme == "mongodb" || scheme == "mongodb+srv"):
+		return parseURLDSN(DriverMongo, raw)
+	case hasScheme && scheme == "mysql":
+		return parseMySQLDSN(strings.TrimPrefix(raw, "mysql://"))
+	case !hasScheme && strings.Contains(raw, "@tcp("):
+		return parseMySQLDSN(raw)
+	}
+
+	return DSN{}, errors.New("string de conexão em formato não reconhecido")
+}
+
+/*
+parseURLDSN separa uma string de conexão no formato de URL (PostgreSQL e MongoDB)
+
+@param driver string - O driver da string de conexão
+@param raw string - A string de conexão
+
+@return DSN - Os componentes
+@return error - Um erro se a URL for inválida
+*/
+func parseURLDSN(driver string, raw string) (DSN, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return DSN{}, fmt.Errorf("string de conexão %s inválida", driver)
+	}
+
+	d := DSN{Driver: driver, Host: u.Hostname(), Database: strings.TrimPrefix(u.Path, "/"), SRV: u.Scheme == "mongodb+srv"}
+	if u.User != nil {
+		d.User = u.User.Username()
+		d.Password, _ = u.User.Password()
+	}
+	if port := u.Port(); port != "" {
+		if d.Port, err = strconv.Atoi(port); err != nil {
+			return DSN{}, fmt.Errorf("string de conexão %s: porta %q inválida", driver, port)
+		}
+	}
+	d.Params = queryParams(u.Query())
+
+	return d, d.Validate()
+}
+
+/*
+parseMySQLDSN separa uma string de conexão no formato do go-sql-driver (user:pass@tcp(host:port)/db?params)
+
+@param raw string - A string de conexão
+
+@return DSN - Os componentes
+@return error - Um erro se a string for inválida
+*/
+func parseMySQLDSN(raw string) (DSN, error) {
+	invalid := errors.New("string de conexão mysql inválida, esperado user:pass@tcp(host:port)/db")
+
+	at := strings.LastIndex(raw, "@tcp(")
+	end := strings.Index(raw[at+1:], ")")
+	if at < 0 || end < 0 {
+		return DSN{}, invalid
+	}
+
+	d := DSN{Driver: DriverMySQL}
+	d.User, d.Password, _ = strings.Cut(raw[:at], ":")
+
+	address := raw[at+len("@tcp(") : at+1+end]
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		host, port = address, ""
+	}
+	d.Host = host
+	if port != "" {
+		if d.Port, err = strconv.Atoi(port); err != nil {
+			return DSN{}, invalid
+		}
+	}
+
+	rest := strings.TrimPrefix(raw[at+2+end:], "/")
+	database, query, _ := strings.Cut(rest, "?")
+	d.Database = database
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return DSN{}, invalid
+	}
+	d.Params = queryParams(values)
+
+	return d, d.Validate()
+}
+
+/*
+queryParams converte os parâmetros de uma URL em um mapa, mantendo o primeiro valor de cada parâmetro
+
+@param values url.Values - Os parâmetros
+
+@return map[string]string - Os parâmetros, ou nil se não houver nenhum
+*/
+func queryParams(values url.Values) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	params := make(map[string]string, len(values))
+	for key := range values {
+		params[key] = values.Get(key)
+	}
+
+	return params
+}
+
+/*
+DSNFromEnv monta uma string de conexão a partir das variáveis de um prefixo
+
+Se <prefixo>_URL estiver definida, ela é usada e separada com ParseDSN. Caso contrário, os componentes são lidos de
+<prefixo>_HOST, <prefixo>_PORT, <prefixo>_USER, <prefixo>_PASS (ou <prefixo>_PASSWORD), <prefixo>_NAME e
+<prefixo>_SSLMODE; a porta padrão do driver é usada quando <prefixo>_PORT não existe. A senha também é lida dos
+segredos isolados com WithSecretIsolation.
+
+@param r Reader - A configuração
+@param driver string - DriverPostgres, DriverMySQL ou DriverMongo
+@param prefix string - O prefixo das variáveis, ou uma string vazia para DB
+
+@return DSN - Os componentes validados
+@return error - Um erro se alguma variável estiver ausente ou inválida
+*/
+func DSNFromEnv(r Reader, driver string, prefix string) (DSN, error) {
+	if prefix == "" {
+		prefix = "DB"
+	}
+
+	if raw, ok := lookupValue(r, prefix+"_URL"); ok {
+		d, err := ParseDSN(raw)
+		if err == nil && d.Driver != driver {
+			err = fmt.Errorf("variável %s_URL: esperada uma string de conexão %s, obtida %s", prefix, driver, d.Driver)
+		}
+		return d, err
+	}
+
+	d := DSN{Driver: driver, Host: r.GetString(prefix + "_HOST"), User: r.GetString(prefix + "_USER"), Database: r.GetString(prefix + "_NAME")}
+	if port, ok := r.Lookup(prefix + "_PORT"); ok {
+		n, err := strconv.Atoi(port)
+		if err != nil {
+			return DSN{}, conversionError(prefix+"_PORT", port, "int")
+		}
+		d.Port = n
+	}
+	for _, key := range []string{prefix + "_PASS", prefix + "_PASSWORD"} {
+		if password, ok := lookupValue(r, key); ok {
+			d.Password = password
+			break
+		}
+	}
+	if mode, ok := r.Lookup(prefix + "_SSLMODE"); ok {
+		d.setSSLMode(mode)
+	}
+
+	return d, d.Validate()
+}
+
+/*
+setSSLMode converte um sslmode no estilo do PostgreSQL para o parâmetro equivalente do driver
+
+@param mode string - O modo (disable, require, verify-ca, verify-full etc.)
+*/
+func (d *DSN) setSSLMode(mode string) {
+	if d.Params == nil {
+		d.Params = make(map[string]string)
+	}
+
+	switch d.Driver {
+	case DriverMySQL:
+		switch mode {
+		case "disable":
+			d.Params["tls"] = "false"
+		case "verify-ca", "verify-full":
+			d.Params["tls"] = "true"
+		default:
+			d.Params["tls"] = "skip-verify"
+		}
+	case DriverMongo:
+		d.Params["tls"] = strconv.FormatBool(mode != "disable")
+	default:
+		d.Params["sslmode"] = mode
+	}
+}
+
+/*
+Validate verifica se os componentes formam uma string de conexão válida
+
+@return error - Um erro que descreve o primeiro problema encontrado, sem expor a senha
+*/
+func (d DSN) Validate() error {
+	if _, ok := defaultDBPorts[d.Driver]; !ok {
+		return fmt.Errorf("driver de banco de dados %q desconhecido", d.Driver)
+	}
+	if d.Host == "" {
+		return fmt.Errorf("string de conexão %s: endereço do servidor não informado", d.Redacted())
+	}
+	if d.Port < 0 || d.Port > 65535 || (d.SRV && d.Port != 0) {
+		return fmt.Errorf("string de conexão %s: porta %d inválida", d.Redacted(), d.Port)
+	}
+	if d.Password != "" && d.User == "" {
+		return fmt.Errorf("string de conexão %s: senha informada sem usuário", d.Redacted())
+	}
+	if mode, ok := d.Params["sslmode"]; ok && d.Driver == DriverPostgres && !postgresSSLModes[mode] {
+		return fmt.Errorf("string de conexão %s: sslmode %q inválido", d.Redacted(), mode)
+	}
+
+	return nil
+}
+
+/*
+String retorna a string de conexão completa, no formato esperado pelo driver
+
+Como a senha é incluída, o resultado não deve ser registrado em logs; use Redacted.
+
+@return string - A string de conexão
+*/
+func (d DSN) String() string {
+	return d.format(false)
+}
+
+/*
+Redacted retorna a string de conexão com a senha mascarada, própria para logs e mensagens de erro
+
+Os parâmetros password, pass, pwd e sslpassword também são mascarados, e a máscara é escrita sem escape.
+
+@return string - A string de conexão mascarada
+*/
+func (d DSN) Redacted() string {
+	return d.format(true)
+}
+
+/*
+format monta a string de conexão
+
+@param redact bool - Se a senha e os parâmetros de senha devem ser mascarados
+
+@return string - A string de conexão
+*/
+func (d DSN) format(redact bool) string {
+	port := d.Port
+	if port == 0 && !d.SRV {
+		port = defaultDBPorts[d.Driver]
+	}
+	address := d.Host
+	if port != 0 {
+		address = net.JoinHostPort(d.Host, strconv.Itoa(port))
+	}
+	query := d.encodeParams(redact)
+
+	if d.Driver == DriverMySQL {
+		var b strings.Builder
+		if d.User != "" {
+			b.WriteString(d.User)
+			switch {
+			case d.Password != "" && redact:
+				b.WriteString(":" + maskedValue)
+			case d.Password != "":
+				b.WriteString(":" + d.Password)
+			}
+			b.WriteString("@")
+		}
+		fmt.Fprintf(&b, "tcp(%s)/%s", address, d.Database)
+		if query != "" {
+			b.WriteString("?" + query)
+		}
+		return b.String()
+	}
+
+	u := url.URL{Scheme: d.Driver, Host: address, RawQuery: query}
+	if d.SRV {
+		u.Scheme = "mongodb+srv"
+	}
+	if d.Database != "" {
+		u.Path = "/" + d.Database
+	}
+	switch {
+	case d.User != "" && d.Password != "" && redact:
+		u.User = url.User(d.User)
+		scheme, rest, _ := strings.Cut(u.String(), "://")
+		user, host, _ := strings.Cut(rest, "@")
+		return scheme + "://" + user + ":" + maskedValue + "@" + host
+	case d.User != "" && d.Password != "":
+		u.User = url.UserPassword(d.User, d.Password)
+	case d.User != "":
+		u.User = url.User(d.User)
+	}
+
+	return u.String()
+}
+
+/*
+encodeParams codifica os parâmetros em ordem alfabética, como url.Values.Encode
+
+@param redact bool - Se os parâmetros de senha devem ser mascarados
+
+@return string - A query, sem o "?"
+*/
+func (d DSN) encodeParams(redact bool) string {
+	keys := make([]string, 0, len(d.Params))
+	for key := range d.Params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		if b.Len() > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(url.QueryEscape(key) + "=")
+		if redact && passwordParams[strings.ToLower(key)] {
+			b.WriteString(maskedValue)
+			continue
+		}
+		b.WriteString(url.QueryEscape(d.Params[key]))
+	}
+
+	return b.String()
+}
+
+/*
+RedactDSN mascara a senha de uma string de conexão qualquer, mesmo que ela não possa ser separada por ParseDSN
+
+São mascarados a senha de user:pass@host e os parâmetros password, pass, pwd e sslpassword, tanto na query
+(?password=x) quanto no formato chave=valor do PostgreSQL (host=db password='x y').
+
+@param raw string - A string de conexão
+
+@return string - A string de conexão com a senha mascarada
+*/
+func RedactDSN(raw string) string {
+	masked := passwordParamPattern.ReplaceAllString(raw, "${1}${2}${3}"+maskedValue)
+
+	start := 0
+	if i := strings.Index(masked, "://"); i >= 0 {
+		start = i + len("://")
+	}
+
+	at := strings.LastIndex(masked, "@")
+	if at < start {
+		return masked
+	}
+
+	user, _, hasPassword := strings.Cut(masked[start:at], ":")
+	if !hasPassword {
+		return masked
+	}
+
+	return masked[:start] + user + ":" + maskedValue + masked[at:]
+}
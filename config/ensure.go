@@ -0,0 +1,220 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"unsafe"
+)
+
+/*
+ensuredLoad guarda o resultado de um carregamento feito por EnsureLoaded para uma configuração
+
+once sync.Once - Garante que o carregamento seja executado uma única vez
+loader IEnvLoader - O carregador usado no carregamento
+err error - O erro retornado pelo carregamento
+*/
+type ensuredLoad struct {
+	once   sync.Once
+	loader IEnvLoader
+	err    error
+}
+
+var (
+	ensureMu sync.Mutex
+	ensured  = make(map[string]*ensuredLoad)
+)
+
+/*
+EnsureLoaded carrega as variáveis de ambiente uma única vez por processo para cada configuração
+
+Bibliotecas diferentes que usam go-locEnv no mesmo processo podem chamar EnsureLoaded sem repetir a descoberta
+nem duplicar os logs: a primeira chamada executa o carregamento e as seguintes, com as mesmas opções, reutilizam
+o carregador e o erro da primeira. Chamadas com opções diferentes executam o seu próprio carregamento.
+EnsureLoaded é seguro para uso concorrente; chamadas simultâneas aguardam o término do primeiro carregamento.
+
+@param opts ...Option - As opções do carregador
+
+@return IEnvLoader - O carregador usado no primeiro carregamento com as mesmas opções
+@return error - O erro retornado pelo primeiro carregamento com as mesmas opções
+*/
+func EnsureLoaded(opts ...Option) (IEnvLoader, error) {
+	loader := NewEnvLoader(opts...)
+	key := loader.(*FileEnvLoader).fingerprint()
+
+	ensureMu.Lock()
+	load, ok := ensured[key]
+	if !ok {
+		load = &ensuredLoad{loader: loader}
+		ensured[key] = load
+	}
+	ensureMu.Unlock()
+
+	load.once.Do(func() {
+		load.err = load.loader.LoadEnv()
+	})
+
+	return load.loader, load.err
+}
+
+/*
+fingerprint retorna um hash das entradas da configuração de um carregador recém-criado
+
+Dois carregadores criados com as mesmas opções, no mesmo diretório de trabalho, possuem o mesmo hash. O hash é
+calculado campo a campo sobre as entradas das opções, como o diretório, o ambiente, os arquivos e as regras; o
+estado de execução, como os valores carregados, a saúde, o congelamento e o encerramento, não participa. Funções,
+Decrypters, hooks e handlers entram pela identidade (veja identity), de modo que dois closures diferentes nunca
+compartilham o carregamento. Os valores, inclusive os de WithValues e as chaves, só entram no hash.
+
+@return string - O hash da configuração, em hexadecimal
+*/
+func (f *FileEnvLoader) fingerprint() string {
+	h := sha256.New()
+	add := func(name string, v any) {
+		fmt.Fprintf(h, "%s=%#v\x00", name, v)
+	}
+	addID := func(name string, v any) {
+		fmt.Fprintf(h, "%s=%s\x00", name, identity(v))
+	}
+
+	dir, err := f.workingDir()
+	add("dir", dir)
+	add("dirErr", err != nil)
+	add("env", f.Env)
+	add("appEnv", f.appEnv)
+	add("envPinned", f.envPinned)
+	add("envChangePolicy", f.envChangePolicy)
+	add("files", f.files)
+	add("envDirs", f.envDirs)
+	add("fsDir", f.fsDir)
+	addID("fsys", f.fsys)
+	add("noDiscovery", f.noDiscovery)
+	add("noProcessEnv", f.noProcessEnv)
+	add("literals", f.literals)
+
+	add("trace", f.trace)
+	add("classAliases", f.classAliases)
+	add("profileAliases", f.profileAliases)
+	add("platformOverlays", f.platformOverlays)
+	add("userOverlays", f.userOverlays)
+	add("strictCascade", f.strictCascade)
+	add("overridable", f.overridable)
+	add("keyAliases", f.keyAliases)
+	add("tenant", f.tenant)
+	add("gitSafetyCheck", f.gitSafetyCheck)
+	add("required", f.required)
+	add("schema", f.schema)
+	for _, rule := range f.contents {
+		add("content", []any{rule.key, rule.file, rule.hex})
+		for _, check := range rule.checks {
+			addID("contentCheck", check)
+		}
+	}
+	add("decimalComma", f.decimalComma)
+	add("strictDecimals", f.strictDecimals)
+	add("deprecations", f.deprecations)
+	add("strictDeprecations", f.strictDeprecations)
+	add("deprecationDeadline", f.deprecationDeadline.UnixNano())
+	add("refuseExpired", f.refuseExpired)
+	addID("candidates", f.candidates)
+	backends := make([]string, 0, len(f.decrypters))
+	for backend := range f.decrypters {
+		backends = append(backends, backend)
+	}
+	sort.Strings(backends)
+	for _, backend := range backends {
+		addID("decrypter "+backend, f.decrypters[backend])
+	}
+	add("decryptAttempts", f.decryptAttempts)
+	add("decryptBackoff", f.decryptBackoff)
+	if f.cache != nil {
+		add("cache", []any{f.cache.path, f.cache.key, f.cache.fallback, f.cache.maxStale})
+	}
+	add("revisionKey", f.revisionKey)
+	add("secretPatterns", f.secretPatterns)
+	add("isolateSecrets", f.isolateSecrets)
+	add("keyOrder", f.keyOrder)
+	add("summaryLevel", f.summaryLevel)
+	add("previewKeys", f.previewKeys)
+	add("logLevel", f.logLevel)
+	add("precedence", f.precedence)
+	if f.rotation != nil {
+		add("rotationDebounce", f.rotation.debounce)
+		for _, hook := range f.rotation.hooks {
+			add("rotationHook", hook.pattern)
+			addID("rotationFn", hook.fn)
+		}
+	}
+	add("limits", f.limits)
+	add("allowedCommands", f.allowedCommands)
+	add("commandTimeout", f.commandTimeout)
+	add("generateSeed", f.generateSeed)
+	add("persistGenerated", f.persistGenerated)
+	add("placeholderCheck", f.placeholderCheck)
+	addID("prompter", f.prompter)
+	add("persistPrompt", f.persistPrompt)
+	stages := make([]int, 0, len(f.hooks))
+	for stage := range f.hooks {
+		stages = append(stages, int(stage))
+	}
+	sort.Ints(stages)
+	for _, stage := range stages {
+		for _, hook := range f.hooks[HookStage(stage)] {
+			addID(fmt.Sprintf("hook %d", stage), hook)
+		}
+	}
+	add("allowKeys", f.allowKeys)
+	add("denyKeys", f.denyKeys)
+	add("mergeRules", f.mergeRules)
+	for _, rule := range f.transforms {
+		add("transform", rule.patterns)
+		for _, transformer := range rule.transformers {
+			addID("transformer", transformer)
+		}
+	}
+	add("existing", f.existing)
+	add("crons", f.crons)
+	add("jsonRules", f.jsonRules)
+	add("keyGroups", f.keyGroups)
+	add("exclusive", f.exclusive)
+	add("conditions", f.conditions)
+	addID("warningHandler", f.warningHandler)
+	addID("errorHandler", f.errorHandler)
+	addID("setenv", f.setenv)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+/*
+identity descreve um valor pela sua identidade, para fingerprint
+
+Uma função é identificada pelo seu closure, que é único para cada instância com variáveis capturadas e comum a
+todas as referências a uma mesma função sem elas; o endereço do código, usado por fmt e reflect, não distingue
+dois closures criados pela mesma expressão. Ponteiros, mapas e canais são identificados pelo endereço, e os
+demais valores, como os.DirFS ou CommandCipher, pelo conteúdo.
+
+@param v any - O valor
+
+@return string - A descrição do valor
+*/
+func identity(v any) string {
+	if v == nil {
+		return "nil"
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Func:
+		if rv.IsNil() {
+			return "nil"
+		}
+		return fmt.Sprintf("%T@%p", v, (*[2]unsafe.Pointer)(unsafe.Pointer(&v))[1])
+	case reflect.Pointer, reflect.Map, reflect.Chan, reflect.UnsafePointer:
+		return fmt.Sprintf("%T@%#x", v, rv.Pointer())
+	default:
+		return fmt.Sprintf("%#v", v)
+	}
+}
@@ -3,29 +3,181 @@ package config
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
-	"github.com/joho/godotenv"
 	"github.com/jonh-dev/go-logger/logger"
 )
 
 var ErrEnvFound = errors.New("env found")
 
+// ErrEnvNotFound indica que nenhum arquivo .env foi encontrado para o ambiente solicitado.
+var ErrEnvNotFound = errors.New("arquivo .env não encontrado")
+
 /*
-IEnvLoader é uma interface que define as funções necessárias para carregar variáveis de ambiente de um arquivo .env
+Loader é uma interface que define as funções necessárias para carregar variáveis de ambiente de um arquivo .env
 
 LoadEnv é responsável por localizar e carregar o arquivo .env apropriado com base no ambiente atual.
 Se o arquivo .env não puder ser encontrado ou carregado, ele retornará um erro.
 @return error - Um erro se o arquivo .env não puder ser encontrado ou carregado
 
+LoadEnvResult carrega o arquivo .env como LoadEnv e retorna um resumo estruturado do carregamento.
+@return *Result - O resumo do carregamento
+@return error - Um erro se o arquivo .env não puder ser encontrado ou carregado
+
+Reload repete a descoberta e o carregamento, atualizando as variáveis definidas pelo próprio carregador.
+@return error - Um erro se o arquivo .env não puder ser encontrado ou carregado
+
+Plan simula o carregamento e retorna as alterações que LoadEnv faria, sem aplicá-las.
+@return *Plan - As alterações que seriam feitas
+@return error - Um erro se o arquivo .env não puder ser encontrado, lido ou decifrado
+
+Conflicts retorna as variáveis em que o processo e o arquivo .env divergiram no último carregamento.
+@return ConflictReport - O relatório de conflitos
+
+Health retorna o estado da configuração: último sucesso, última falha, validação e acessibilidade dos provedores.
+@return Health - O relatório de saúde
+
+Loader também implementa HealthReporter, cujo HealthContext verifica os provedores com um contexto.
+*/
+type Loader interface {
+	LoadEnv() error
+	LoadEnvResult() (*Result, error)
+	Reload() error
+	Plan() (*Plan, error)
+	Conflicts() ConflictReport
+	Health() Health
+	HealthReporter
+}
+
+/*
+Reader é uma interface que define as funções necessárias para ler a configuração carregada
+
+Componentes que apenas leem a configuração devem depender de Reader, que é facilmente substituído em testes.
+
 GetEnv retorna o ambiente atual que foi definido ao carregar o arquivo .env.
 @return string - O ambiente atual
+
+IsProduction, IsDevelopment e IsTest informam se o ambiente atual pertence à classe correspondente, considerando os apelidos configurados.
+@return bool - Se o ambiente atual pertence à classe
+
+Lookup, GetString, GetInt, GetFloat, GetBool, GetDuration e GetStringSlice leem as variáveis carregadas, convertendo-as para o tipo correspondente.
+@param key string - O nome da variável
+
+GetSecret retorna o valor de uma variável classificada como segredo, mesmo quando ela não foi aplicada ao ambiente do processo.
+@param key string - O nome da variável
+@return string - O valor do segredo
+@return bool - Se o segredo foi carregado
+
+GetBytesBase64 retorna o conteúdo binário de uma variável codificada em base64.
+@param key string - O nome da variável
+@return []byte - O conteúdo decodificado
+@return error - ErrKeyNotFound se a variável não existir, ou um erro se o valor não for base64 válido
+
+GetBase64 e GetHex retornam o conteúdo binário de uma variável codificada em base64 ou em hexadecimal, aplicando as validações informadas.
+@param key string - O nome da variável
+@param checks ...ContentCheck - As validações do conteúdo decodificado, como ExactLength
+@return []byte - O conteúdo decodificado
+@return error - ErrKeyNotFound se a variável não existir, ou um erro se o valor for inválido ou alguma validação falhar
+
+GetFileContent retorna o conteúdo do arquivo cujo caminho está em uma variável.
+@param key string - O nome da variável
+@return []byte - O conteúdo do arquivo
+@return error - ErrKeyNotFound se a variável não existir, ou um erro se o arquivo não puder ser lido
+
+GetPath retorna o valor de uma variável como um caminho, resolvido em relação ao arquivo .env que a definiu.
+@param key string - O nome da variável
+@param checks ...PathCheck - As verificações do caminho, como PathIsDir
+@return string - O caminho resolvido
+@return error - ErrKeyNotFound se a variável não existir, ou um erro se alguma verificação falhar
+
+GetExistingDir e GetExistingFile retornam o caminho de um diretório ou de um arquivo existente e acessível, resolvido como em GetPath.
+@param key string - O nome da variável
+@return string - O caminho resolvido
+@return error - ErrKeyNotFound se a variável não existir, ou um erro se o caminho não existir ou não for do tipo esperado
+
+GetJSON converte o valor JSON de uma variável em target, validando-o pelo esquema de WithJSONSchema.
+@param key string - O nome da variável
+@param target any - Um ponteiro para o destino, como uma struct ou um mapa
+@return error - ErrKeyNotFound se a variável não existir, ou um erro se o valor for inválido ou não puder ser convertido
+
+GetCron retorna o valor de uma variável como uma expressão cron válida, aceitando @every apenas com WithCronEvery.
+@param key string - O nome da variável
+@return string - A expressão cron, sem os espaços nas extremidades
+@return error - ErrKeyNotFound se a variável não existir, ou um erro que descreve o problema da expressão
+
+GetSemver e GetSemverConstraint convertem o valor de uma variável em uma versão semântica ou em uma restrição de versões.
+@param key string - O nome da variável
+@return error - ErrKeyNotFound se a variável não existir, ou um erro com o arquivo e a linha se o valor for inválido
+
+All retorna uma cópia das variáveis carregadas.
+@return map[string]string - As variáveis carregadas e seus valores efetivos
+
+Source retorna a origem do valor efetivo de uma variável carregada.
+@param key string - O nome da variável
+@return string - O caminho do arquivo que definiu a variável, ou SourceProcess
+@return bool - Se a variável foi carregada
+
+Snapshot retorna uma visão imutável das variáveis carregadas, segura para leitura concorrente sem bloqueios.
+@return ConfigView - A visão congelada
+
+Sub retorna uma visão imutável das variáveis de uma seção, com os nomes relativos à seção.
+@param prefix string - O nome da seção (ex.: database ou database.pool)
+@return ConfigView - A visão da seção
+
+Revision retorna um hash estável da configuração efetiva, incluindo os segredos.
+@return string - A revisão, ou uma string vazia se nada foi carregado
+
+EqualRevision informa se outra configuração possui a mesma revisão.
+@param other Reader - A outra configuração
+@return bool - Se as duas configurações são idênticas
 */
-type IEnvLoader interface {
-	LoadEnv() error
+type Reader interface {
 	GetEnv() string
+	IsProduction() bool
+	IsDevelopment() bool
+	IsTest() bool
+	Lookup(key string) (string, bool)
+	GetString(key string) string
+	GetInt(key string) (int, error)
+	GetFloat(key string) (float64, error)
+	GetBool(key string) (bool, error)
+	GetDuration(key string) (time.Duration, error)
+	GetStringSlice(key string) []string
+	GetSecret(key string) (string, bool)
+	GetBytesBase64(key string) ([]byte, error)
+	GetBase64(key string, checks ...ContentCheck) ([]byte, error)
+	GetHex(key string, checks ...ContentCheck) ([]byte, error)
+	GetFileContent(key string) ([]byte, error)
+	GetPath(key string, checks ...PathCheck) (string, error)
+	GetExistingDir(key string) (string, error)
+	GetExistingFile(key string) (string, error)
+	GetJSON(key string, target any) error
+	GetCron(key string) (string, error)
+	GetSemver(key string) (Semver, error)
+	GetSemverConstraint(key string) (SemverConstraint, error)
+	All() map[string]string
+	Keys() []string
+	Dump() string
+	Source(key string) (string, bool)
+	Snapshot() ConfigView
+	Sub(prefix string) ConfigView
+	Revision() string
+	EqualRevision(other Reader) bool
+}
+
+/*
+IEnvLoader é uma interface que reúne Loader e Reader, implementada pelos carregadores de ambiente
+
+Prefira depender apenas de Loader ou de Reader quando um componente usar somente uma das partes.
+*/
+type IEnvLoader interface {
+	Loader
+	Reader
 }
 
 /*
@@ -37,6 +189,90 @@ Env string - O ambiente atual, que é definido ao carregar o arquivo .env
 */
 type FileEnvLoader struct {
 	Env string
+
+	trace               bool
+	classAliases        map[string][]string
+	profileAliases      map[string][]string
+	platformOverlays    bool
+	userOverlays        bool
+	strictCascade       bool
+	overridable         []string
+	keyAliases          map[string][]string
+	tenant              string
+	gitSafetyCheck      bool
+	required            []string
+	schema              []KeySpec
+	contents            []contentRule
+	decimalComma        bool
+	strictDecimals      bool
+	deprecations        []deprecation
+	strictDeprecations  bool
+	deprecationDeadline time.Time
+	refuseExpired       bool
+	candidates          *[]Candidate
+	decrypters          map[string]Decrypter
+	decryptAttempts     int
+	decryptBackoff      time.Duration
+	cache               *snapshotCache
+	revisionKey         []byte
+	secretPatterns      []string
+	isolateSecrets      bool
+	secrets             map[string]string
+	values              map[string]string
+	sources             map[string]string
+	lines               map[string]int
+	order               map[string]int
+	keyOrder            KeyOrder
+	summaryLevel        SummaryLevel
+	previewKeys         []string
+	logLevel            LogLevel
+	appEnv              string
+	envPinned           bool
+	envChangePolicy     EnvChangePolicy
+	envWarned           string
+	owned               map[string]bool
+	shadowed            map[string]string
+	precedence          Precedence
+	conflicts           []Conflict
+	rotation            *rotationHooks
+	health              *healthState
+	revision            string
+	baseFile            string
+	tenants             *tenantCache
+	freeze              *freezeState
+	noProcessEnv        bool
+	noDiscovery         bool
+	literals            map[string]string
+	fsys                fs.FS
+	fsDir               string
+	files               []string
+	envDirs             []string
+	limits              Limits
+	allowedCommands     map[string]bool
+	commandTimeout      time.Duration
+	generateSeed        []byte
+	persistGenerated    string
+	placeholderCheck    bool
+	prompter            Prompter
+	persistPrompt       bool
+	prompted            map[string]string
+	hooks               map[HookStage][]Hook
+	allowKeys           []string
+	denyKeys            []string
+	mergeRules          []mergeRule
+	transforms          []transformRule
+	existing            []pathRule
+	crons               []cronRule
+	jsonRules           []jsonRule
+	keyGroups           [][]string
+	exclusive           [][]string
+	conditions          []condition
+	warningHandler      func(Warning)
+	warnings            []Warning
+	errorHandler        func(error)
+	setenv              func(key string, value string)
+	dryRun              bool
+	lifecycle           *lifecycle
 }
 
 /*
@@ -44,49 +280,366 @@ NewEnvLoader cria um novo carregador de ambiente que implementa a interface IEnv
 
 A função NewEnvLoader cria uma nova instância de FileEnvLoader, que implementa a interface IEnvLoader.
 Ela define o ambiente atual chamando a função getEnvironment e armazena o resultado no campo Env da nova instância de FileEnvLoader.
+O rastreamento da descoberta é ativado quando a variável de ambiente LOCENV_DEBUG é igual a "1".
+Em seguida, aplica as opções recebidas, na ordem em que foram informadas.
+
+@param opts ...Option - As opções que personalizam o comportamento do carregador
 
 @return IEnvLoader - Uma nova instância de FileEnvLoader que implementa a interface IEnvLoader
 */
-func NewEnvLoader() IEnvLoader {
-	return &FileEnvLoader{
-		Env: getEnvironment(),
+func NewEnvLoader(opts ...Option) IEnvLoader {
+	env := getEnvironment()
+	f := &FileEnvLoader{
+		Env:      env,
+		appEnv:   env,
+		trace:    os.Getenv(traceEnvVar) == "1",
+		logLevel: defaultLogLevel(),
+		limits:   DefaultLimits,
+		profileAliases: map[string][]string{
+			"prod":        {"production"},
+			"production":  {"prod"},
+			"dev":         {"development"},
+			"development": {"dev"},
+			"stage":       {"staging"},
+			"stg":         {"staging"},
+			"staging":     {"stage", "stg"},
+			"test":        {"testing"},
+			"testing":     {"test"},
+		},
+		classAliases: map[string][]string{
+			ClassProduction:  {"production", "prod", "prd"},
+			ClassDevelopment: {"development", "dev", "local"},
+			ClassTest:        {"test", "testing"},
+		},
+		decrypters: make(map[string]Decrypter),
+		secrets:    make(map[string]string),
+		owned:      make(map[string]bool),
+		shadowed:   make(map[string]string),
+		health:     &healthState{},
+		tenants:    &tenantCache{},
+		freeze:     &freezeState{},
+		lifecycle:  newLifecycle(),
+	}
+
+	for _, opt := range opts {
+		opt(f)
 	}
+
+	return f
 }
 
 /*
-getEnvironment obtém o ambiente atual a partir da variável de ambiente GO_ENV
+getEnvironment obtém o ambiente atual a partir da variável de ambiente APP_ENV
 
-A função getEnvironment tenta obter o valor da variável de ambiente GO_ENV.
-Se GO_ENV estiver definido, ele chama as funções productionWarning e developmentProfile para registrar mensagens de aviso ou perfil, dependendo do valor de GO_ENV.
-Finalmente, ele retorna o valor de GO_ENV.
+A função getEnvironment obtém o valor da variável de ambiente APP_ENV e o normaliza, removendo espaços
+nas extremidades e convertendo-o para letras minúsculas, para que " Production" e "production" sejam o mesmo ambiente.
 
-@return string - O valor da variável de ambiente GO_ENV
+@return string - O valor normalizado da variável de ambiente APP_ENV
 */
 func getEnvironment() string {
-	env := os.Getenv("APP_ENV")
-	return env
+	return normalizeEnv(os.Getenv("APP_ENV"))
+}
+
+/*
+normalizeEnv remove os espaços nas extremidades e converte o nome de um ambiente para letras minúsculas
+
+@param env string - O nome do ambiente
+
+@return string - O nome normalizado
+*/
+func normalizeEnv(env string) string {
+	return strings.ToLower(strings.TrimSpace(env))
 }
 
 /*
 LoadEnv carrega as variáveis de ambiente a partir de um arquivo .env
 
-A função LoadEnv primeiro chama a função findEnvFile para localizar o arquivo .env apropriado e obter o ambiente correspondente.
-Se findEnvFile não encontrar um arquivo .env ou ocorrer um erro durante a busca, LoadEnv retorna um erro.
+A função LoadEnv primeiro chama a função resolve, que localiza o arquivo .env apropriado, lê as variáveis, decifra os valores cifrados e separa os segredos.
+Se resolve não encontrar um arquivo .env ou ocorrer um erro durante o processo, LoadEnv retorna um erro.
 
-Se um arquivo .env for encontrado, LoadEnv define o campo Env da estrutura FileEnvLoader para o ambiente obtido e carrega as variáveis de ambiente do arquivo .env chamando a função loadEnvFile.
+Em seguida, LoadEnv chama a função apply, que define o campo Env da estrutura FileEnvLoader para o ambiente obtido e aplica as variáveis ao ambiente do processo.
+LoadEnv é equivalente a LoadEnvResult descartando o resultado.
 
 @return error - Um erro se o arquivo .env não puder ser encontrado, ocorrer um erro durante a busca, ou o arquivo .env não puder ser carregado
 */
 func (f *FileEnvLoader) LoadEnv() error {
+	_, err := f.LoadEnvResult()
+	return err
+}
+
+/*
+resolution é uma estrutura que guarda o resultado da resolução de um arquivo .env, antes de ele ser aplicado
+
+files []string - Os arquivos .env carregados, na ordem em que foram aplicados (o arquivo base e as sobreposições)
+env string - O ambiente correspondente ao arquivo .env encontrado
+requested string - O ambiente solicitado, que difere de env quando o arquivo foi encontrado por um apelido
+values map[string]string - As variáveis a serem aplicadas ao ambiente do processo
+origins map[string]origin - A declaração que definiu o valor final de cada variável
+secrets map[string]string - As variáveis classificadas como segredo
+warnings []Warning - Os avisos gerados durante a resolução
+filtered []string - As variáveis descartadas por WithAllowKeys e WithDenyKeys
+fromCache bool - Se o resultado veio do cache offline
+*/
+type resolution struct {
+	files     []string
+	env       string
+	requested string
+	values    map[string]string
+	origins   map[string]origin
+	secrets   map[string]string
+	warnings  []Warning
+	filtered  []string
+	fromCache bool
+}
+
+/*
+origin identifica a declaração de uma variável
+
+file string - O caminho do arquivo .env
+line int - A linha da declaração
+rank int - A posição da primeira declaração da variável entre todos os arquivos, usada por Keys
+*/
+type origin struct {
+	file string
+	line int
+	rank int
+}
+
+/*
+resolve localiza, lê e prepara as variáveis de um arquivo .env sem alterar o ambiente do processo
+
+A função resolve chama layerFiles para localizar o arquivo .env e as sobreposições habilitadas, ou os arquivos
+de WithFiles, loadLayers para ler as variáveis de todos os arquivos, os hooks de WithHook, filterKeys para
+aplicar WithAllowKeys e WithDenyKeys, applyDeprecations para mapear as variáveis obsoletas para os nomes novos,
+decryptValues para decifrar os valores cifrados, promptMissing para pedir as variáveis obrigatórias ausentes,
+separateSecrets para separar os segredos, validate para verificar as variáveis obrigatórias e gitWarnings para
+verificar os arquivos com segredos no git.
+
+Um pânico em um provedor, hook ou transformação é recuperado e retornado como *PanicError. Um carregador
+encerrado com Close retorna ErrClosed, sem iniciar de novo os plugins.
+
+Quando um valor não pode ser decifrado, o resultado parcial com os arquivos e os ambientes é retornado junto com
+o erro, para que resolveOrFallback verifique se o cache offline corresponde a esta resolução.
+
+@return *resolution - O resultado da resolução
+@return error - Um erro se o arquivo .env não puder ser encontrado, lido, decifrado ou validado, ou ErrClosed
+*/
+func (f *FileEnvLoader) resolve() (_ *resolution, err error) {
+	defer recoverPanic("resolução da configuração", &err)
+
+	if f.lifecycle.isClosed() {
+		return nil, ErrClosed
+	}
+
+	files, env, err := f.layerFiles()
+	if err != nil {
+		return nil, err
+	}
+	if len(f.hooks[BeforeLoad]) > 0 {
+		ctx := &HookContext{Stage: BeforeLoad, Env: env, Files: files}
+		if err := f.runHooks(ctx); err != nil {
+			return nil, err
+		}
+		if files = ctx.Files; len(files) == 0 {
+			return nil, ErrEnvNotFound
+		}
+	}
+
+	values, origins, expired, err := f.loadLayers(files)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.hookValues(AfterParse, env, files, values, nil, origins); err != nil {
+		return nil, err
+	}
+	filtered := f.filterKeys(values, origins)
+
+	deprecated, err := f.applyDeprecations(values, origins)
+	if err != nil {
+		if f.logs(LogError) {
+			logger.Error(fmt.Sprintf("Erro ao validar variáveis de ambiente: %s", err.Error()))
+		}
+		return nil, err
+	}
+
+	encrypted, err := f.decryptValues(values)
+	if err != nil {
+		if f.logs(LogError) {
+			logger.Error(fmt.Sprintf("Erro ao decifrar variáveis de ambiente: %s", err.Error()))
+		}
+		return &resolution{files: files, env: env, requested: f.Env}, err
+	}
+
+	if err := f.promptMissing(values, origins, files[0], encrypted); err != nil {
+		if f.logs(LogError) {
+			logger.Error(fmt.Sprintf("Erro ao carregar variáveis de ambiente: %s", err.Error()))
+		}
+		return nil, err
+	}
+
+	secrets := f.separateSecrets(values, encrypted)
+	if err := f.validate(values, secrets, origins); err != nil {
+		if f.logs(LogError) {
+			logger.Error(fmt.Sprintf("Erro ao validar variáveis de ambiente: %s", err.Error()))
+		}
+		return nil, err
+	}
+	if secrets, err = f.hookValues(BeforeApply, env, files, values, secrets, origins); err != nil {
+		return nil, err
+	}
+
+	return &resolution{
+		files:     files,
+		env:       env,
+		requested: f.Env,
+		values:    values,
+		origins:   origins,
+		secrets:   secrets,
+		warnings:  append(append(expired, deprecated...), f.gitWarnings(origins, secrets, encrypted)...),
+		filtered:  filtered,
+	}, nil
+}
+
+/*
+dryResolve executa resolve sem efeitos colaterais, para Plan e RenderBundle
+
+A resolução é feita em uma cópia do carregador em modo de simulação: os valores de @generate não são gravados
+com WithPersistGenerated nem a semente local é criada, e WithPrompt não pergunta nada nem grava a sobreposição,
+usando apenas as respostas já dadas a este carregador. A substituição de comandos continua sendo executada,
+porque os comandos foram autorizados explicitamente com WithAllowCommandSubstitution.
+
+@return *resolution - O resultado da resolução
+@return error - Um erro como os de resolve
+*/
+func (f *FileEnvLoader) dryResolve() (*resolution, error) {
+	dry := *f
+	dry.dryRun = true
+
+	return dry.resolve()
+}
+
+/*
+layerFiles retorna os arquivos a carregar, na ordem em que devem ser aplicados
+
+Com WithFiles, os arquivos são os informados, e o ambiente é o do carregador. Caso contrário, o arquivo base é
+localizado com findEnvFile e seguido pelos fragmentos de WithEnvDir e pelas sobreposições habilitadas. As
+variáveis de WithValues formam a última camada, com o nome SourceMemory; com WithNoDiscovery, ela está sempre
+presente, mesmo vazia, e a descoberta não é feita.
+
+@return []string - Os arquivos
+@return string - O ambiente normalizado dos arquivos
+@return error - Um erro se nenhum arquivo for encontrado ou a busca falhar
+*/
+func (f *FileEnvLoader) layerFiles() ([]string, string, error) {
+	if f.noDiscovery || len(f.files) > 0 {
+		var files []string
+		if len(f.files) > 0 {
+			var err error
+			if files, err = f.explicitFiles(); err != nil {
+				return nil, "", err
+			}
+		}
+		if f.noDiscovery || f.literals != nil {
+			files = append(files, SourceMemory)
+		}
+		return files, normalizeEnv(f.Env), nil
+	}
+
 	envFile, env, err := f.findEnvFile()
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 	if envFile == "" {
-		return fmt.Errorf("arquivo .env não encontrado")
+		f.tracef("nenhum arquivo .env encontrado para o ambiente %q", f.Env)
+		return nil, "", ErrEnvNotFound
 	}
-	f.Env = env
-	return f.loadEnvFile(envFile)
+	f.tracef("decisão final: carregando %s (ambiente %q, solicitado %q)", envFile, env, f.Env)
+
+	files := append([]string{envFile}, f.envDirFiles(envFile)...)
+	files = append(files, f.overlayFiles(envFile, env)...)
+	if f.literals != nil {
+		files = append(files, SourceMemory)
+	}
+	return files, normalizeEnv(env), nil
+}
+
+/*
+apply aplica o resultado de uma resolução ao carregador e ao ambiente do processo
+
+@param res *resolution - O resultado da resolução
+
+@return *Result - O resumo do carregamento
+@return error - Um erro se alguma variável não puder ser definida
+*/
+func (f *FileEnvLoader) apply(res *resolution) (*Result, error) {
+	result := &Result{
+		Files:        res.files,
+		Env:          res.env,
+		RequestedEnv: res.requested,
+		Secrets:      len(res.secrets),
+		Warnings:     warningMessages(res.warnings),
+		Filtered:     res.filtered,
+	}
+
+	conflicts := f.detectConflicts(res)
+	if f.precedence == ErrorOnConflict && len(conflicts) > 0 {
+		err := &ConflictError{Conflicts: conflicts}
+		if f.logs(LogError) {
+			logger.Error(fmt.Sprintf("Erro ao aplicar variáveis de ambiente: %s", err.Error()))
+		}
+		return nil, err
+	}
+	f.Env = res.env
+	f.baseFile = res.files[0]
+	f.notifyRotations(f.secrets, res.secrets)
+	f.secrets = res.secrets
+	f.conflicts = conflicts
+	result.Conflicts = conflicts
+
+	var skipped []string
+	if !f.noProcessEnv {
+		var err error
+		if skipped, err = f.applyValues(res.values); err != nil {
+			return nil, err
+		}
+	}
+
+	f.values = make(map[string]string, len(res.values))
+	f.sources = make(map[string]string, len(res.values))
+	f.lines = make(map[string]int, len(res.values))
+	f.order = make(map[string]int, len(res.values))
+	for key, value := range res.values {
+		if !f.noProcessEnv {
+			value = os.Getenv(key)
+		}
+		f.values[key] = value
+		f.sources[key] = res.origins[key].file
+		f.lines[key] = res.origins[key].line
+		f.order[key] = res.origins[key].rank
+	}
+	for _, key := range skipped {
+		f.sources[key] = SourceProcess
+	}
+	f.revision = configRevision(f.revisionKey, f.Env, f.values, f.secrets)
+	result.Revision = f.revision
+	result.Preview = f.preview()
+
+	result.Loaded = len(res.values) - len(skipped)
+	result.Skipped = skipped
+	f.warnings = append([]Warning(nil), res.warnings...)
+	for _, c := range conflicts {
+		w := Warning{Kind: WarningConflict, Key: c.Key, File: c.File, Line: c.Line}
+		if f.precedence == FileWins {
+			w.Message = fmt.Sprintf("variável %s já definida no processo com outro valor; o valor do processo foi sobrescrito", c.Key)
+		} else {
+			w.Message = fmt.Sprintf("variável %s já definida no processo com outro valor; o valor do arquivo foi ignorado", c.Key)
+		}
+		f.warn(w)
+		result.Warnings = append(result.Warnings, w.Message)
+		f.warnings = append(f.warnings, w)
+	}
+
+	return result, nil
 }
 
 /*
@@ -104,7 +657,7 @@ func (f *FileEnvLoader) findEnvFile() (string, string, error) {
 	filePath := ""
 	env := ""
 
-	currentDir, err := os.Getwd()
+	currentDir, err := f.workingDir()
 	if err != nil {
 		return "", "", err
 	}
@@ -122,9 +675,9 @@ searchInCurrentAndParentDirectories é um método da estrutura FileEnvLoader que
 
 O método recebe o diretório atual como argumento. Inicializa duas variáveis, filePath e env, para armazenar o caminho do arquivo .env encontrado e o ambiente correspondente.
 
-Em seguida, entra em um loop infinito. Dentro do loop, o método chama a função searchInDirectory, passando o diretório atual. Se um arquivo .env for encontrado, o loop é interrompido.
+Em seguida, entra em um loop infinito. Dentro do loop, o método chama a função searchInDirectory, passando o diretório atual e o diretório já pesquisado na iteração anterior, que não precisa ser percorrido novamente. Se um arquivo .env for encontrado, o loop é interrompido.
 
-Se nenhum arquivo .env for encontrado, o método obtém o diretório pai do diretório atual. Se o diretório pai for a raiz ("/") ou o diretório atual (".") o loop é interrompido.
+Se nenhum arquivo .env for encontrado, o método obtém o diretório pai do diretório atual. Se o diretório pai for a raiz ("/") ou o diretório atual (".") o loop é interrompido; com WithFS, a raiz do sistema de arquivos virtual também é pesquisada.
 
 Se ocorrer um erro durante a busca, o método retorna esse erro.
 
@@ -135,10 +688,12 @@ Se ocorrer um erro durante a busca, o método retorna esse erro.
 func (f *FileEnvLoader) searchInCurrentAndParentDirectories(currentDir string) (string, string, error) {
 	filePath := ""
 	env := ""
+	searched := ""
 
 	for {
 		var err error
-		filePath, env, err = f.searchInDirectory(currentDir)
+		f.tracef("visitando o diretório %s", currentDir)
+		filePath, env, err = f.searchInDirectory(currentDir, searched)
 		if err != nil {
 			return "", "", err
 		}
@@ -146,10 +701,12 @@ func (f *FileEnvLoader) searchInCurrentAndParentDirectories(currentDir string) (
 			break
 		}
 
-		currentDir = filepath.Dir(currentDir)
-		if currentDir == "/" || currentDir == "." {
+		searched = currentDir
+		parent := f.dirOf(currentDir)
+		if parent == currentDir || (f.fsys == nil && (parent == "/" || parent == ".")) {
 			break
 		}
+		currentDir = parent
 	}
 
 	return filePath, env, nil
@@ -158,36 +715,67 @@ func (f *FileEnvLoader) searchInCurrentAndParentDirectories(currentDir string) (
 /*
 searchInDirectory é um método da estrutura FileEnvLoader que procura um arquivo .env no diretório fornecido.
 
-O método recebe o diretório como argumento. Inicializa duas variáveis, filePath e env, para armazenar o caminho do arquivo .env encontrado e o ambiente correspondente.
+O método recebe o diretório e um subdiretório já pesquisado como argumentos. Inicializa duas variáveis, filePath e env, para armazenar o caminho do arquivo .env encontrado e o ambiente correspondente.
 
-Em seguida, o método chama a função filepath.Walk, passando o diretório e uma função anônima. A função anônima é chamada para cada arquivo e diretório no diretório fornecido.
+Em seguida, o método chama a função filepath.WalkDir, passando o diretório e uma função anônima. A função anônima é chamada para cada arquivo e diretório no diretório fornecido.
+filepath.WalkDir é usado no lugar de filepath.Walk porque não precisa obter as informações (os.Lstat) de cada arquivo visitado.
 
-Se o arquivo atual for um diretório, a função anônima retorna e passa para o próximo arquivo. Se o arquivo atual for um arquivo e seu nome começar com ".env.", a função anônima verifica se o ambiente correspondente ao arquivo .env (obtido removendo ".env." do nome do arquivo) corresponde ao ambiente atual. Se corresponder, define filePath para o caminho do arquivo e env para o ambiente correspondente, e retorna um erro especial para parar a função filepath.Walk.
+Se o arquivo atual for o subdiretório já pesquisado, ele é ignorado por completo. Se for outro diretório, a função anônima retorna e passa para o próximo arquivo. Se o arquivo atual for um arquivo e seu nome começar com ".env.", a função anônima verifica se o ambiente correspondente ao arquivo .env (obtido removendo ".env." do nome do arquivo) corresponde ao ambiente atual. Se corresponder, define filePath para o caminho do arquivo e env para o ambiente correspondente, e retorna um erro especial para parar a função filepath.WalkDir.
+
+Se o ambiente do arquivo for um apelido do ambiente atual (veja WithProfileAliases), o arquivo é guardado e a busca continua, pois um arquivo com o nome exato, ou com um apelido de maior prioridade, ainda pode ser encontrado no mesmo diretório.
 
 Se ocorrer um erro durante a busca, o método retorna esse erro.
 
+@param dir string - O diretório a ser pesquisado
+@param skip string - O subdiretório já pesquisado, ou uma string vazia
+
 @return string - O caminho do arquivo .env encontrado
 @return string - O ambiente correspondente ao arquivo .env encontrado
 @return error - Um erro se ocorrer um erro durante a busca
 */
-func (f *FileEnvLoader) searchInDirectory(dir string) (string, string, error) {
+func (f *FileEnvLoader) searchInDirectory(dir string, skip string) (string, string, error) {
 	filePath := ""
 	env := ""
+	bestRank := -1
+	candidates := f.profileCandidates()
 
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+	err := f.walkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil && path != dir && errors.Is(err, fs.ErrPermission) {
+			f.tracef("ignorando %s: %s", path, err)
+			return filepath.SkipDir
+		}
 		if err != nil {
 			return err
 		}
 
-		if info.IsDir() {
+		if d.IsDir() {
+			if path == skip {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
-		if strings.HasPrefix(info.Name(), ".env.") {
-			env = strings.TrimPrefix(info.Name(), ".env.")
-			if env == f.Env {
-				filePath = path
+		if strings.HasPrefix(d.Name(), ".env.") {
+			profile := strings.TrimPrefix(d.Name(), ".env.")
+			rank := profileRank(candidates, normalizeEnv(profile))
+			switch {
+			case rank == 0 && bestRank != 0:
+				f.noteCandidate(path, profile, fmt.Sprintf("selecionado: corresponde ao ambiente %q", f.Env))
+				filePath, env, bestRank = path, profile, 0
+				if f.candidates != nil {
+					// Durante um diagnóstico, a busca continua para listar todos os candidatos.
+					return nil
+				}
 				return ErrEnvFound
+			case rank == 0:
+				f.noteCandidate(path, profile, "ignorado: já existe um arquivo selecionado")
+			case rank > 0 && (bestRank < 0 || rank < bestRank):
+				f.noteCandidate(path, profile, fmt.Sprintf("aceito: %q é um apelido do ambiente %q", profile, f.Env))
+				filePath, env, bestRank = path, profile, rank
+			case rank > 0:
+				f.noteCandidate(path, profile, "ignorado: já existe um apelido de maior prioridade")
+			default:
+				f.noteCandidate(path, profile, fmt.Sprintf("ignorado: ambiente %q difere de %q", profile, f.Env))
 			}
 		}
 
@@ -202,34 +790,238 @@ func (f *FileEnvLoader) searchInDirectory(dir string) (string, string, error) {
 }
 
 /*
-loadEnvFile carrega as variáveis de ambiente de um arquivo .env específico
+loadLayers lê as variáveis de uma sequência de arquivos .env, em que cada arquivo sobrepõe os anteriores ou,
+conforme WithMergeStrategy, estende os seus valores
+
+@param files []string - Os arquivos .env, na ordem em que devem ser aplicados
+
+@return map[string]string - As variáveis resultantes
+@return map[string]origin - A declaração que definiu o valor final de cada variável
+@return []Warning - Os avisos sobre declarações vencidas e anotações inválidas
+@return error - Um erro se algum arquivo não puder ser lido ou interpretado, ou um *CascadeError no modo estrito
+*/
+func (f *FileEnvLoader) loadLayers(files []string) (map[string]string, map[string]origin, []Warning, error) {
+	values := make(map[string]string)
+	origins := make(map[string]origin)
 
-A função loadEnvFile usa a biblioteca godotenv para carregar as variáveis de ambiente do arquivo .env especificado.
-Se ocorrer um erro ao carregar o arquivo .env, ele registra o erro e retorna um erro.
+	var conflicts []LayerConflict
+	var warnings []Warning
+	generated := make(map[string]string)
+	var generatedOrder []string
 
-Se o arquivo .env for carregado com sucesso, ele registra uma mensagem de sucesso indicando que o ambiente foi carregado.
+	for _, file := range files {
+		entries, err := f.loadEnvFile(file)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		for _, e := range entries {
+			apply, warning := f.checkExpiry(e, file)
+			if warning != nil {
+				warnings = append(warnings, *warning)
+			}
+			if !apply {
+				continue
+			}
+			value, isGenerated, err := f.generateValue(e, file)
+			if err != nil {
+				if f.logs(LogError) {
+					logger.Error(fmt.Sprintf("Erro ao carregar variáveis de ambiente: %s", err.Error()))
+				}
+				return nil, nil, nil, err
+			}
+			e.value = value
+			e.value = f.relativeValue(e, file)
+			if len(f.transforms) > 0 {
+				if e.value, err = f.transformValue(e, file); err != nil {
+					if f.logs(LogError) {
+						logger.Error(fmt.Sprintf("Erro ao carregar variáveis de ambiente: %s", err.Error()))
+					}
+					return nil, nil, nil, err
+				}
+			}
+			if isGenerated {
+				generatedOrder = append(generatedOrder, e.key)
+				generated[e.key] = value
+			} else {
+				delete(generated, e.key)
+			}
+			merged, isMerged, warning := f.mergeValue(e, file, values)
+			if warning != nil {
+				warnings = append(warnings, *warning)
+			}
+			if conflict, ok := f.layerConflict(e, file, values, origins); ok && !isMerged {
+				conflicts = append(conflicts, conflict)
+			}
+			e.value = merged
+			rank := len(origins)
+			if previous, ok := origins[e.key]; ok {
+				rank = previous.rank
+			}
+			values[e.key] = e.value
+			origins[e.key] = origin{file: file, line: e.line, rank: rank}
+		}
+		if f.limits.MaxKeys > 0 && len(values) > f.limits.MaxKeys {
+			err := fmt.Errorf("%w: as camadas até %s declaram mais de %d variáveis", ErrLimitExceeded, file, f.limits.MaxKeys)
+			if f.logs(LogError) {
+				logger.Error(fmt.Sprintf("Erro ao combinar arquivos .env: %s", err.Error()))
+			}
+			return nil, nil, nil, err
+		}
+	}
+
+	if len(conflicts) > 0 {
+		err := &CascadeError{Conflicts: conflicts}
+		if f.logs(LogError) {
+			logger.Error(fmt.Sprintf("Erro ao combinar arquivos .env: %s", err.Error()))
+		}
+		return nil, nil, nil, err
+	}
+
+	var persisted []string
+	seen := make(map[string]bool)
+	for _, key := range generatedOrder {
+		if _, ok := generated[key]; ok && !seen[key] {
+			seen[key] = true
+			persisted = append(persisted, key)
+		}
+	}
+	if err := f.persistGeneratedValues(files[0], generated, persisted); err != nil {
+		warnings = append(warnings, Warning{
+			Kind:    WarningGenerated,
+			File:    f.persistGenerated,
+			Message: fmt.Sprintf("não foi possível gravar os valores gerados em %s: %s", f.persistGenerated, err),
+		})
+	}
+	f.warn(warnings...)
+
+	return values, origins, warnings, nil
+}
+
+/*
+loadEnvFile lê as variáveis de ambiente de um arquivo .env específico
+
+A função loadEnvFile abre o arquivo .env especificado e usa a função parseEntries para interpretá-lo linha a linha,
+sem carregar o arquivo inteiro na memória. O buffer de leitura é zerado logo após a interpretação.
+Erros de sintaxe são retornados como *ParseError, com o arquivo, a linha e a coluna do problema.
+Se ocorrer um erro ao ler o arquivo .env, ele registra o erro e retorna um erro.
 
 @param envFile string - O caminho do arquivo .env a ser carregado
 
-@return error - Um erro se o arquivo .env não puder ser carregado
+@return []entry - As variáveis lidas do arquivo .env, na ordem em que foram declaradas
+@return error - Um erro se o arquivo .env não puder ser lido ou interpretado
 */
-func (f *FileEnvLoader) loadEnvFile(envFile string) error {
-	err := godotenv.Load(envFile)
+func (f *FileEnvLoader) loadEnvFile(envFile string) ([]entry, error) {
+	if envFile == SourceMemory {
+		return f.literalEntries(), nil
+	}
+
+	file, err := f.openFile(envFile)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Erro ao carregar variáveis de ambiente: %s", err.Error()))
-		return fmt.Errorf("erro ao carregar variáveis de ambiente: %s", err.Error())
+		if f.logs(LogError) {
+			logger.Error(fmt.Sprintf("Erro ao carregar variáveis de ambiente: %s", err.Error()))
+		}
+		return nil, fmt.Errorf("erro ao carregar variáveis de ambiente: %s", err.Error())
+	}
+	defer file.Close()
+
+	if info, err := file.Stat(); err == nil && f.limits.MaxFileSize > 0 && info.Size() > f.limits.MaxFileSize {
+		err = fileSizeError(envFile, f.limits.MaxFileSize)
+		if f.logs(LogError) {
+			logger.Error(fmt.Sprintf("Erro ao carregar variáveis de ambiente: %s", err.Error()))
+		}
+		return nil, fmt.Errorf("erro ao carregar variáveis de ambiente: %w", err)
+	}
+
+	entries, err := parseEntriesLimited(limitReader(file, envFile, f.limits.MaxFileSize), envFile, f.limits, f.commandSubstituter(envFile))
+	if err != nil {
+		if f.logs(LogError) {
+			logger.Error(fmt.Sprintf("Erro ao carregar variáveis de ambiente: %s", err.Error()))
+		}
+		return nil, fmt.Errorf("erro ao carregar variáveis de ambiente: %w", err)
+	}
+
+	return entries, nil
+}
+
+/*
+applyValues aplica as variáveis ao ambiente do processo
+
+A função applyValues define cada variável com os.Setenv, exceto as que já existem no ambiente do processo,
+mantendo o mesmo comportamento de godotenv.Load, que nunca sobrescreve variáveis existentes. Com FileWins,
+as variáveis existentes são sobrescritas e o valor original é guardado para que o Reload possa restaurá-lo.
+As variáveis definidas pelo próprio carregador em um carregamento anterior podem ser atualizadas, o que permite o Reload.
+Depois de ApplyT, as variáveis são definidas com o t.Setenv do teste.
+
+@param values map[string]string - As variáveis a serem aplicadas
+
+@return []string - As variáveis ignoradas por já existirem no processo, em ordem alfabética
+@return error - Um erro se alguma variável não puder ser definida
+*/
+func (f *FileEnvLoader) applyValues(values map[string]string) ([]string, error) {
+	var skipped []string
+
+	for key, value := range values {
+		if current, exists := os.LookupEnv(key); exists && !f.owned[key] {
+			if f.precedence != FileWins {
+				skipped = append(skipped, key)
+				continue
+			}
+			f.shadowed[key] = current
+		}
+		if f.setenv != nil {
+			f.setenv(key, value)
+		} else if err := os.Setenv(key, value); err != nil {
+			return nil, fmt.Errorf("erro ao definir a variável %s: %s", key, err.Error())
+		}
+		f.owned[key] = true
 	}
 
-	return nil
+	sort.Strings(skipped)
+
+	return skipped, nil
 }
 
 /*
 GetEnv retorna o ambiente atual que foi definido ao carregar o arquivo .env
 
-A função GetEnv retorna o valor do campo Env da estrutura FileEnvLoader, que foi definido ao carregar o arquivo .env.
+A função GetEnv retorna o valor do campo Env da estrutura FileEnvLoader. Após LoadEnv, o campo reflete o perfil
+efetivamente carregado (obtido do nome do arquivo), e não apenas o valor de APP_ENV no momento da construção.
+Se APP_ENV mudar depois, GetEnv continua retornando o ambiente carregado; use EnvChanged para detectar a
+diferença e WithEnvChangePolicy para decidir o que os próximos carregamentos fazem.
 
 @return string - O ambiente atual que foi definido ao carregar o arquivo .env
 */
 func (f *FileEnvLoader) GetEnv() string {
 	return f.Env
 }
+
+/*
+GetSecret retorna o valor de uma variável classificada como segredo
+
+Os segredos são registrados durante o carregamento, estejam eles aplicados ao ambiente do processo ou não.
+Com a opção WithSecretIsolation, esta é a única forma de ler um segredo carregado do arquivo .env.
+Assim como em Lookup, a forma achatada de um nome estruturado e os nomes alternativos registrados com
+WithKeyAliases são tentados, em ordem.
+
+@param key string - O nome da variável, um nome estruturado ou um nome lógico registrado com WithKeyAliases
+
+@return string - O valor do segredo
+@return bool - Se o segredo foi carregado
+*/
+func (f *FileEnvLoader) GetSecret(key string) (string, bool) {
+	if value, ok := f.secrets[key]; ok {
+		return value, true
+	}
+	if value, ok := f.secrets[FlattenKey(key)]; ok {
+		return value, true
+	}
+
+	for _, name := range f.keyAliases[key] {
+		if value, ok := f.secrets[name]; ok {
+			return value, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,74 @@
+package config
+
+import "fmt"
+
+// EnvChangePolicy define o que o carregador faz quando APP_ENV muda depois da sua criação.
+type EnvChangePolicy int
+
+const (
+	// EnvChangeWarn registra um aviso e continua usando o ambiente lido na criação do carregador.
+	EnvChangeWarn EnvChangePolicy = iota
+	// EnvChangeResolve adota o novo valor de APP_ENV e resolve o ambiente de novo.
+	EnvChangeResolve
+	// EnvChangeIgnore mantém o ambiente lido na criação do carregador, sem avisos.
+	EnvChangeIgnore
+)
+
+/*
+WithEnvChangePolicy define o que acontece quando APP_ENV muda entre a criação do carregador e um carregamento
+
+O carregador lê APP_ENV uma única vez, em NewEnvLoader. Se a variável for alterada depois, por exemplo entre
+NewEnvLoader e LoadEnv ou antes de um Reload, GetEnv deixaria de corresponder ao que o processo acredita estar
+usando. Cada carregamento compara APP_ENV com o valor adotado e, conforme a política, registra um aviso uma vez
+para cada novo valor, também incluído em Result.Warnings (EnvChangeWarn, o padrão), adota o novo valor
+(EnvChangeResolve) ou não faz nada (EnvChangeIgnore). Carregadores com o ambiente definido por WithEnv não
+acompanham APP_ENV.
+
+@param policy EnvChangePolicy - A política
+
+@return Option - A opção que define a política
+*/
+func WithEnvChangePolicy(policy EnvChangePolicy) Option {
+	return func(f *FileEnvLoader) {
+		f.envChangePolicy = policy
+	}
+}
+
+/*
+EnvChanged informa se APP_ENV mudou desde que o carregador adotou o seu valor
+
+@return string - O valor atual de APP_ENV, normalizado
+@return bool - Se o valor difere do adotado; sempre false quando o ambiente foi definido por WithEnv
+*/
+func (f *FileEnvLoader) EnvChanged() (string, bool) {
+	current := getEnvironment()
+	return current, !f.envPinned && current != f.appEnv
+}
+
+/*
+checkEnvChange aplica a política de WithEnvChangePolicy antes de um carregamento
+
+@return string - O aviso gerado, ou uma string vazia
+*/
+func (f *FileEnvLoader) checkEnvChange() string {
+	current, changed := f.EnvChanged()
+	if !changed || f.envChangePolicy == EnvChangeIgnore {
+		return ""
+	}
+
+	var warning string
+	switch f.envChangePolicy {
+	case EnvChangeResolve:
+		warning = fmt.Sprintf("APP_ENV mudou de %q para %q; o ambiente foi resolvido de novo", f.appEnv, current)
+		f.Env, f.appEnv = current, current
+	default:
+		if f.envWarned == current {
+			return ""
+		}
+		f.envWarned = current
+		warning = fmt.Sprintf("APP_ENV mudou de %q para %q depois da criação do carregador; o ambiente %q continua em uso (veja WithEnvChangePolicy)", f.appEnv, current, f.Env)
+	}
+
+	f.warn(Warning{Kind: WarningEnvChange, Message: warning})
+	return warning
+}
@@ -0,0 +1,65 @@
+package config
+
+import "path/filepath"
+
+/*
+WithEnvDir habilita diretórios de fragmentos, no estilo conf/env.d/*.env
+
+Depois do arquivo .env.<env>, e antes das demais sobreposições, o carregador aplica todos os arquivos que
+correspondem a cada padrão, em ordem alfabética, como uma única camada: pacotes e operadores podem adicionar
+fragmentos de configuração ao diretório sem editar os arquivos existentes, e a ordem é controlada pelo nome,
+como em 10-database.env e 20-cache.env. Os padrões relativos são resolvidos a partir do diretório do arquivo
+base. Um padrão sem arquivos correspondentes é ignorado, já que o diretório pode estar vazio. Esta opção não se
+aplica aos arquivos de WithFiles, que já podem receber padrões.
+
+@param patterns ...string - Os padrões de filepath.Glob, aplicados na ordem informada
+
+@return Option - A opção que habilita os diretórios de fragmentos
+*/
+func WithEnvDir(patterns ...string) Option {
+	return func(f *FileEnvLoader) {
+		f.envDirs = append(f.envDirs, patterns...)
+	}
+}
+
+/*
+envDirFiles retorna os fragmentos de WithEnvDir que existem a partir do diretório do arquivo base
+
+@param baseFile string - O caminho do arquivo .env base
+
+@return []string - Os fragmentos, em ordem alfabética dentro de cada padrão e sem repetições
+*/
+func (f *FileEnvLoader) envDirFiles(baseFile string) []string {
+	dir := f.dirOf(baseFile)
+
+	var files []string
+	seen := make(map[string]bool)
+	for _, pattern := range f.envDirs {
+		if f.fsys != nil || !filepath.IsAbs(pattern) {
+			pattern = f.joinPath(dir, pattern)
+		}
+		matches, err := f.globFiles(pattern)
+		if err != nil {
+			f.tracef("diretório de fragmentos %s ignorado: %s", pattern, err)
+			continue
+		}
+		if len(matches) == 0 {
+			f.tracef("diretório de fragmentos %s ignorado: nenhum arquivo corresponde ao padrão", pattern)
+			continue
+		}
+
+		for _, file := range matches {
+			if seen[file] {
+				continue
+			}
+			if info, err := f.statFile(file); err != nil || info.IsDir() {
+				continue
+			}
+			seen[file] = true
+			f.tracef("fragmento %s selecionado", file)
+			files = append(files, file)
+		}
+	}
+
+	return files
+}
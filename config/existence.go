@@ -0,0 +1,168 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+/*
+pathRule é uma variável cujo caminho é verificado durante o carregamento, declarada com WithExistingDir ou
+WithExistingFile
+
+key string - O nome da variável
+dir bool - Se o caminho deve ser um diretório; caso contrário, um arquivo regular
+*/
+type pathRule struct {
+	key string
+	dir bool
+}
+
+/*
+WithExistingDir declara variáveis com caminhos de diretórios que precisam existir e ser acessíveis
+
+Se a variável estiver definida, LoadEnv verifica se o caminho, resolvido como em GetPath, é um diretório que
+pode ser lido. Os problemas de todas as variáveis são reunidos no *ValidationError do carregamento, para que
+uma implantação com vários caminhos errados seja corrigida de uma vez, e não um caminho por reinício. Variáveis
+ausentes não são verificadas; combine com WithRequired para exigi-las.
+
+@param keys ...string - Os nomes das variáveis (ex.: TEMPLATES_DIR)
+
+@return Option - A opção que declara as variáveis
+*/
+func WithExistingDir(keys ...string) Option {
+	return func(f *FileEnvLoader) {
+		for _, key := range keys {
+			f.existing = append(f.existing, pathRule{key: key, dir: true})
+		}
+	}
+}
+
+/*
+WithExistingFile declara variáveis com caminhos de arquivos que precisam existir e ser legíveis
+
+Funciona como WithExistingDir, exigindo um arquivo regular que possa ser aberto para leitura.
+
+@param keys ...string - Os nomes das variáveis (ex.: TLS_CERT_FILE)
+
+@return Option - A opção que declara as variáveis
+*/
+func WithExistingFile(keys ...string) Option {
+	return func(f *FileEnvLoader) {
+		for _, key := range keys {
+			f.existing = append(f.existing, pathRule{key: key})
+		}
+	}
+}
+
+/*
+GetExistingDir retorna o caminho de um diretório existente e acessível, resolvido como em GetPath
+
+@param key string - O nome da variável
+
+@return string - O caminho resolvido
+@return error - ErrKeyNotFound se a variável não existir, ou um erro se o caminho não for um diretório acessível
+*/
+func (f *FileEnvLoader) GetExistingDir(key string) (string, error) {
+	resolved, err := f.GetPath(key)
+	if err != nil {
+		return "", err
+	}
+
+	if err := f.checkExisting(key, resolved, true); err != nil {
+		return "", err
+	}
+
+	return resolved, nil
+}
+
+/*
+GetExistingFile retorna o caminho de um arquivo existente e legível, resolvido como em GetPath
+
+@param key string - O nome da variável
+
+@return string - O caminho resolvido
+@return error - ErrKeyNotFound se a variável não existir, ou um erro se o caminho não for um arquivo legível
+*/
+func (f *FileEnvLoader) GetExistingFile(key string) (string, error) {
+	resolved, err := f.GetPath(key)
+	if err != nil {
+		return "", err
+	}
+
+	if err := f.checkExisting(key, resolved, false); err != nil {
+		return "", err
+	}
+
+	return resolved, nil
+}
+
+/*
+checkExisting verifica se um caminho existe, é do tipo esperado e pode ser aberto para leitura
+
+@param key string - O nome da variável, usado nas mensagens
+@param resolved string - O caminho resolvido
+@param dir bool - Se o caminho deve ser um diretório; caso contrário, um arquivo regular
+
+@return error - Um erro que identifica a variável, ou nil
+*/
+func (f *FileEnvLoader) checkExisting(key string, resolved string, dir bool) error {
+	info, err := f.statFile(resolved)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("variável %s: o caminho %s não existe", key, resolved)
+	case err != nil:
+		return fmt.Errorf("variável %s: %w", key, err)
+	case dir && !info.IsDir():
+		return fmt.Errorf("variável %s: %s não é um diretório", key, resolved)
+	case !dir && !info.Mode().IsRegular():
+		return fmt.Errorf("variável %s: %s não é um arquivo regular", key, resolved)
+	}
+
+	file, err := f.openFile(resolved)
+	if err != nil {
+		var pathErr *fs.PathError
+		if errors.As(err, &pathErr) {
+			err = pathErr.Err
+		}
+		return fmt.Errorf("variável %s: %s não pode ser lido: %w", key, resolved, err)
+	}
+
+	return file.Close()
+}
+
+/*
+validatePaths verifica as variáveis declaradas com WithExistingDir e WithExistingFile
+
+@param values map[string]string - As variáveis a serem aplicadas
+@param secrets map[string]string - As variáveis classificadas como segredo
+@param origins map[string]origin - A declaração de cada variável, usada para resolver os caminhos relativos
+
+@return []string - Os problemas encontrados
+*/
+func (f *FileEnvLoader) validatePaths(values map[string]string, secrets map[string]string, origins map[string]origin) []string {
+	var problems []string
+	for _, rule := range f.existing {
+		value, ok := values[rule.key]
+		if !ok {
+			value, ok = secrets[rule.key]
+		}
+		resolved := value
+		if o, declared := origins[rule.key]; ok && declared && f.isFileSource(o.file) {
+			resolved = f.resolvePath(value, o.file)
+		}
+		if !ok && !f.noProcessEnv {
+			resolved, ok = os.LookupEnv(rule.key)
+		}
+		if !ok {
+			continue
+		}
+
+		if err := f.checkExisting(rule.key, resolved, rule.dir); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	return problems
+}
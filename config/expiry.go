@@ -0,0 +1,84 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+// expiresAnnotation é o comentário que define a data de validade de uma declaração.
+const expiresAnnotation = "expires:"
+
+/*
+WithRefuseExpired faz com que as declarações vencidas não sejam aplicadas
+
+Uma declaração pode receber uma data de validade com um comentário imediatamente acima dela:
+
+	# expires: 2025-07-01
+	LOG_LEVEL=debug
+
+A data segue o formato AAAA-MM-DD (vencendo no início do dia, no horário local) ou RFC 3339. Sem esta opção, as
+declarações vencidas geram apenas avisos. Com ela, também são ignoradas, de modo que o valor de uma camada
+anterior (como o arquivo base de uma sobreposição) volta a valer. Isso evita que sobreposições temporárias de
+depuração em arquivos compartilhados fiquem esquecidas.
+
+@return Option - A opção que ignora as declarações vencidas
+*/
+func WithRefuseExpired() Option {
+	return func(f *FileEnvLoader) {
+		f.refuseExpired = true
+	}
+}
+
+/*
+checkExpiry verifica a anotação de validade de uma declaração
+
+@param e entry - A declaração
+@param file string - O arquivo da declaração
+
+@return bool - Se a declaração deve ser aplicada
+@return *Warning - Um aviso sobre a validade, ou nil
+*/
+func (f *FileEnvLoader) checkExpiry(e entry, file string) (bool, *Warning) {
+	raw, ok := commentAnnotation(e.comment, expiresAnnotation)
+	if !ok {
+		return true, nil
+	}
+
+	expires, err := time.ParseInLocation("2006-01-02", raw, time.Local)
+	if err != nil {
+		if expires, err = time.Parse(time.RFC3339, raw); err != nil {
+			w := warningAt(WarningAnnotation, e, file, "data de validade %q inválida para %s, esperado AAAA-MM-DD", raw, e.key)
+			return true, &w
+		}
+	}
+	if time.Now().Before(expires) {
+		return true, nil
+	}
+
+	if f.refuseExpired {
+		w := warningAt(WarningExpired, e, file, "%s venceu em %s e foi ignorada", e.key, raw)
+		return false, &w
+	}
+
+	w := warningAt(WarningExpired, e, file, "%s venceu em %s; remova a declaração ou atualize a data", e.key, raw)
+	return true, &w
+}
+
+/*
+commentAnnotation procura, nos comentários de uma declaração, uma linha iniciada pelo nome informado
+
+@param comment string - Os comentários da declaração, separados por '\n'
+@param name string - O início da linha procurada (ex.: "expires:")
+
+@return string - O restante da linha, sem os espaços nas extremidades
+@return bool - Se a anotação foi encontrada
+*/
+func commentAnnotation(comment string, name string) (string, bool) {
+	for _, line := range strings.Split(comment, "\n") {
+		if rest, ok := strings.CutPrefix(line, name); ok {
+			return strings.TrimSpace(rest), true
+		}
+	}
+
+	return "", false
+}
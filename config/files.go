@@ -0,0 +1,86 @@
+package config
+
+import "fmt"
+
+/*
+WithFiles carrega uma lista explícita de arquivos, na ordem informada, em vez de procurar o arquivo do ambiente
+
+Os arquivos passam pelo mesmo processo de leitura e sobreposição da descoberta: cada arquivo sobrescreve as
+variáveis dos anteriores, e as variáveis obsoletas, os valores cifrados, os segredos e as validações são tratados
+da mesma forma. Cada item pode ser um padrão de filepath.Glob; os arquivos de um padrão são carregados em ordem
+alfabética, e um arquivo que aparece mais de uma vez é carregado apenas na primeira posição. Um padrão sem nenhum
+arquivo correspondente e um caminho inexistente são erros. A descoberta e as sobreposições não são usadas, e
+GetEnv continua informando o ambiente do carregador.
+
+@param patterns ...string - Os arquivos ou padrões, na ordem em que devem ser aplicados
+
+@return Option - A opção que define os arquivos
+*/
+func WithFiles(patterns ...string) Option {
+	return func(f *FileEnvLoader) {
+		f.files = patterns
+	}
+}
+
+/*
+LoadFiles carrega uma lista explícita de arquivos com o carregador padrão, como Load(WithFiles(patterns...))
+
+Pipelines de CI que montam o ambiente a partir de fragmentos conhecidos podem usar
+LoadFiles("./base.env", "./overrides/.env.ci") sem depender da descoberta pelo APP_ENV.
+
+@param patterns ...string - Os arquivos ou padrões, na ordem em que devem ser aplicados
+
+@return error - Um erro se algum arquivo não puder ser encontrado ou carregado
+*/
+func LoadFiles(patterns ...string) error {
+	return Load(WithFiles(patterns...))
+}
+
+/*
+explicitFiles expande os padrões de WithFiles nos arquivos a carregar
+
+@return []string - Os arquivos, na ordem dos padrões e sem repetições
+@return error - Um erro se um padrão for inválido ou não corresponder a nenhum arquivo
+*/
+func (f *FileEnvLoader) explicitFiles() ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+
+	for _, pattern := range f.files {
+		matches := []string{pattern}
+		if hasGlobMeta(pattern) {
+			var err error
+			matches, err = f.globFiles(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("padrão de arquivo inválido %q: %w", pattern, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("nenhum arquivo corresponde a %q", pattern)
+			}
+		}
+
+		for _, file := range matches {
+			key := f.cleanPath(file)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			files = append(files, file)
+		}
+	}
+
+	f.tracef("decisão final: carregando os arquivos de WithFiles %v (ambiente %q)", files, f.Env)
+	return files, nil
+}
+
+// hasGlobMeta informa se um caminho contém algum caractere especial de filepath.Glob.
+func hasGlobMeta(pattern string) bool {
+	for _, c := range pattern {
+		switch c {
+		case '*', '?', '[':
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,82 @@
+package config
+
+import "sort"
+
+/*
+WithAllowKeys restringe as variáveis aplicadas às que correspondem a algum dos padrões
+
+Os padrões seguem path.Match, como os de WithSecretKeys (ex.: "APP_*", "DATABASE_URL"). As demais variáveis dos
+arquivos são descartadas logo após a leitura: não são aplicadas ao processo, não ficam disponíveis nos getters
+e não contam na validação, que passa a considerar apenas o ambiente do processo para elas. Assim, um arquivo
+compartilhado entre vários serviços fornece a cada um apenas o subconjunto aprovado. Chamadas repetidas
+acumulam os padrões; sem esta opção, todas as variáveis são permitidas.
+
+@param patterns ...string - Os padrões das variáveis permitidas
+
+@return Option - A opção que define a lista de permissão
+*/
+func WithAllowKeys(patterns ...string) Option {
+	return func(f *FileEnvLoader) {
+		f.allowKeys = append(f.allowKeys, patterns...)
+	}
+}
+
+/*
+WithDenyKeys descarta as variáveis que correspondem a algum dos padrões
+
+Os padrões seguem path.Match e prevalecem sobre WithAllowKeys. O uso típico é impedir que credenciais de um
+arquivo compartilhado sobrescrevam as do ambiente, por exemplo WithDenyKeys("AWS_*") em um processo que usa um
+papel do IAM. As variáveis descartadas são tratadas como em WithAllowKeys e listadas em Result.Filtered.
+
+@param patterns ...string - Os padrões das variáveis descartadas
+
+@return Option - A opção que define a lista de bloqueio
+*/
+func WithDenyKeys(patterns ...string) Option {
+	return func(f *FileEnvLoader) {
+		f.denyKeys = append(f.denyKeys, patterns...)
+	}
+}
+
+/*
+keyAllowed informa se uma variável passa pelas listas de WithAllowKeys e WithDenyKeys
+
+@param key string - O nome da variável
+
+@return bool - Se a variável pode ser aplicada
+*/
+func (f *FileEnvLoader) keyAllowed(key string) bool {
+	if matchesAny(f.denyKeys, key) {
+		return false
+	}
+
+	return len(f.allowKeys) == 0 || matchesAny(f.allowKeys, key)
+}
+
+/*
+filterKeys remove das variáveis lidas as que não passam por WithAllowKeys e WithDenyKeys
+
+@param values map[string]string - As variáveis combinadas, alteradas no lugar
+@param origins map[string]origin - A declaração de cada variável, alterada no lugar
+
+@return []string - As variáveis removidas, em ordem alfabética
+*/
+func (f *FileEnvLoader) filterKeys(values map[string]string, origins map[string]origin) []string {
+	if len(f.allowKeys) == 0 && len(f.denyKeys) == 0 {
+		return nil
+	}
+
+	var filtered []string
+	for key := range values {
+		if f.keyAllowed(key) {
+			continue
+		}
+		filtered = append(filtered, key)
+		delete(values, key)
+		delete(origins, key)
+		f.tracef("variável %s descartada por WithAllowKeys ou WithDenyKeys", key)
+	}
+	sort.Strings(filtered)
+
+	return filtered
+}
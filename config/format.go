@@ -0,0 +1,255 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// plainValueChars são os caracteres que obrigam um valor a ser escrito entre aspas.
+const plainValueChars = " \t#\"'$\\\n\r"
+
+/*
+formatLine é uma linha do arquivo formatado
+
+text string - A declaração ou o comentário, ou uma string vazia para uma linha em branco
+comment string - O comentário no fim da declaração, com o caractere '#'
+aligned bool - Se o comentário participa do alinhamento do grupo, o que não ocorre em valores com várias linhas
+*/
+type formatLine struct {
+	text    string
+	comment string
+	aligned bool
+}
+
+/*
+Format normaliza o estilo de um arquivo .env, preservando os comentários e o agrupamento por linhas em branco
+
+O estilo canônico é:
+  - declarações sem indentação e sem espaços ao redor do separador, sempre "=", mantendo o prefixo "export";
+  - valores sem aspas quando não precisam delas, e entre aspas duplas quando possuem espaços ou aspas simples;
+    valores com escapes, referências ou várias linhas mantêm as aspas originais;
+  - comentários no fim das declarações alinhados na mesma coluna dentro de cada grupo de linhas;
+  - no máximo uma linha em branco entre os grupos, nenhuma no início ou no fim, e quebras de linha "\n".
+
+Referências ${VAR} nunca são expandidas. O resultado é interpretado de novo e comparado com o original; se
+alguma variável mudasse de valor, o conteúdo não é formatado e um erro é retornado.
+
+@param content []byte - O conteúdo do arquivo .env
+
+@return []byte - O conteúdo formatado
+@return error - Um *ParseError se o conteúdo possuir uma sintaxe inválida, ou um erro se a formatação alterasse algum valor
+*/
+func Format(content []byte) ([]byte, error) {
+	return formatContent(content, "")
+}
+
+/*
+formatContent formata o conteúdo de um arquivo .env, como Format
+
+@param content []byte - O conteúdo do arquivo .env
+@param file string - O caminho do arquivo, usado nos erros de sintaxe
+
+@return []byte - O conteúdo formatado
+@return error - Um erro como em Format
+*/
+func formatContent(content []byte, file string) ([]byte, error) {
+	entries, err := parseEntries(bytes.NewReader(content), file)
+	if err != nil {
+		return nil, err
+	}
+
+	starts := make(map[int]entry, len(entries))
+	for _, e := range entries {
+		starts[e.line] = e
+	}
+
+	lines := strings.SplitAfter(string(content), "\n")
+	var out []formatLine
+	for i := 0; i < len(lines); i++ {
+		if e, ok := starts[i+1]; ok {
+			text, comment := formatDeclaration(lines[e.line-1:e.endLine], e)
+			out = append(out, formatLine{text: text, comment: comment, aligned: e.line == e.endLine})
+			i = e.endLine - 1
+			continue
+		}
+
+		text := strings.TrimFunc(strings.TrimRight(lines[i], "\r\n"), isInlineSpace)
+		if text == "" && (len(out) == 0 || out[len(out)-1].text == "") {
+			continue
+		}
+		out = append(out, formatLine{text: text})
+	}
+	for len(out) > 0 && out[len(out)-1].text == "" {
+		out = out[:len(out)-1]
+	}
+
+	var b strings.Builder
+	b.Grow(len(content))
+	for start := 0; start < len(out); {
+		end := start
+		width := 0
+		for ; end < len(out) && out[end].text != ""; end++ {
+			if out[end].comment != "" && out[end].aligned {
+				if n := utf8.RuneCountInString(out[end].text); n > width {
+					width = n
+				}
+			}
+		}
+
+		for _, l := range out[start:end] {
+			b.WriteString(l.text)
+			if l.comment != "" {
+				if l.aligned {
+					b.WriteString(strings.Repeat(" ", width-utf8.RuneCountInString(l.text)))
+				}
+				b.WriteString(" ")
+				b.WriteString(l.comment)
+			}
+			b.WriteByte('\n')
+		}
+		if end < len(out) {
+			b.WriteByte('\n')
+		}
+		start = end + 1
+	}
+
+	formatted := []byte(b.String())
+	if err := sameEntries(entries, formatted); err != nil {
+		return nil, err
+	}
+
+	return formatted, nil
+}
+
+/*
+formatDeclaration escreve uma declaração no estilo canônico
+
+@param lines []string - As linhas da declaração, com as quebras de linha
+@param e entry - A declaração interpretada
+
+@return string - A declaração formatada, sem o comentário
+@return string - O comentário no fim da declaração, ou uma string vazia
+*/
+func formatDeclaration(lines []string, e entry) (string, string) {
+	first := strings.TrimRight(lines[0], "\r\n")
+	rest := strings.TrimLeftFunc(first, isInlineSpace)
+
+	prefix := ""
+	i := len(first) - len(rest)
+	if !strings.HasPrefix(rest, e.key) {
+		prefix = "export "
+		i += len("export")
+		i = skipInlineSpace(first, i)
+	}
+	i += len(e.key)
+	i += strings.IndexAny(first[i:], "=:") + 1
+	i = skipInlineSpace(first, i)
+
+	raw := first[i:]
+	for _, line := range lines[1:] {
+		raw += "\n" + strings.TrimRight(line, "\r\n")
+	}
+
+	comment := ""
+	if e.quote != 0 {
+		end := closingQuote(raw, 1, e.quote)
+		comment = strings.TrimFunc(raw[end+1:], isInlineSpace)
+		raw = raw[:end+1]
+	} else {
+		for j := 1; j < len(raw); j++ {
+			if raw[j] == '#' && spaceBefore(raw, j) {
+				raw, comment = raw[:j], raw[j:]
+				break
+			}
+		}
+		raw = strings.TrimFunc(raw, isInlineSpace)
+	}
+
+	return prefix + e.key + "=" + canonicalQuoting(raw, e.quote), comment
+}
+
+/*
+canonicalQuoting escolhe as aspas de um valor sem alterar o valor interpretado
+
+@param raw string - O valor como aparece no arquivo, com as aspas
+@param quote byte - As aspas originais, ou 0 para valores sem aspas
+
+@return string - O valor com as aspas canônicas
+*/
+func canonicalQuoting(raw string, quote byte) string {
+	if quote != 0 {
+		if inner := raw[1 : len(raw)-1]; !strings.ContainsAny(inner, plainValueChars) {
+			return inner
+		}
+		return raw
+	}
+
+	if strings.ContainsAny(raw, " \t'") && !strings.ContainsAny(raw, "\"\\") {
+		return `"` + raw + `"`
+	}
+
+	return raw
+}
+
+/*
+sameEntries verifica se o conteúdo formatado declara as mesmas variáveis, na mesma ordem e com os mesmos valores
+
+@param entries []entry - As declarações do conteúdo original
+@param formatted []byte - O conteúdo formatado
+
+@return error - Um erro indicando a primeira variável alterada
+*/
+func sameEntries(entries []entry, formatted []byte) error {
+	check, err := parseEntries(bytes.NewReader(formatted), "")
+	if err != nil {
+		return fmt.Errorf("a formatação produziria um arquivo inválido: %w", err)
+	}
+
+	for i, e := range entries {
+		if i >= len(check) || check[i].key != e.key || check[i].value != e.value {
+			return fmt.Errorf("a formatação alteraria a variável %s (linha %d); o arquivo não foi formatado", e.key, e.line)
+		}
+	}
+	if len(check) != len(entries) {
+		return fmt.Errorf("a formatação alteraria o número de variáveis; o arquivo não foi formatado")
+	}
+
+	return nil
+}
+
+/*
+FormatFile formata um arquivo .env no próprio arquivo, como Format
+
+O arquivo só é regravado quando o conteúdo muda, com a mesma troca atômica e as mesmas permissões de RewriteFile.
+
+@param file string - O caminho do arquivo .env
+
+@return bool - Se o arquivo foi alterado
+@return error - Um erro se o arquivo não puder ser lido, formatado ou gravado
+*/
+func FormatFile(file string) (bool, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return false, err
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return false, err
+	}
+	defer zeroBytes(content)
+
+	formatted, err := formatContent(content, file)
+	if err != nil {
+		return false, err
+	}
+	defer zeroBytes(formatted)
+
+	if bytes.Equal(content, formatted) {
+		return false, nil
+	}
+
+	return true, writeFileAtomic(file, formatted, info.Mode().Perm())
+}
@@ -0,0 +1,241 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrFrozen é retornado pelas operações que alterariam a configuração de um carregador congelado com Freeze.
+var ErrFrozen = errors.New("a configuração está congelada e não pode ser alterada")
+
+/*
+freezeState guarda o estado de congelamento de um carregador
+
+mu sync.RWMutex - Protege os demais campos
+frozen bool - Se o carregador foi congelado
+environ map[string]string - O ambiente do processo no momento do congelamento
+checksum string - O checksum de environ, usado para detectar divergências sem comparar cada variável
+*/
+type freezeState struct {
+	mu       sync.RWMutex
+	frozen   bool
+	environ  map[string]string
+	checksum string
+}
+
+/*
+Freeze congela a configuração do carregador
+
+Depois do congelamento, LoadEnv, LoadEnvResult e Reload retornam ErrFrozen sem alterar o ambiente do processo,
+assim como Load quando o carregador padrão está congelado. O ambiente do processo no momento do congelamento é
+guardado para que FrozenDrift e WatchFrozen detectem alterações feitas por fora da biblioteca. Chamadas
+repetidas não alteram o ambiente guardado.
+*/
+func (f *FileEnvLoader) Freeze() {
+	if f.freeze == nil {
+		f.freeze = &freezeState{}
+	}
+
+	f.freeze.mu.Lock()
+	defer f.freeze.mu.Unlock()
+
+	if f.freeze.frozen {
+		return
+	}
+	f.freeze.frozen = true
+	f.freeze.environ = currentEnviron()
+	f.freeze.checksum = environChecksum(f.freeze.environ)
+}
+
+// Frozen informa se o carregador foi congelado com Freeze.
+func (f *FileEnvLoader) Frozen() bool {
+	if f.freeze == nil {
+		return false
+	}
+
+	f.freeze.mu.RLock()
+	defer f.freeze.mu.RUnlock()
+
+	return f.freeze.frozen
+}
+
+/*
+FrozenDrift compara o ambiente atual do processo com o ambiente guardado por Freeze
+
+Os valores das variáveis classificadas como segredo são mascarados.
+
+@return []KeyChange - As variáveis adicionadas, removidas ou alteradas desde o congelamento, ordenadas pelo nome
+@return error - Um erro se o carregador não estiver congelado
+*/
+func (f *FileEnvLoader) FrozenDrift() ([]KeyChange, error) {
+	if !f.Frozen() {
+		return nil, fmt.Errorf("o carregador não está congelado: chame Freeze antes de verificar divergências")
+	}
+
+	current := currentEnviron()
+	if environChecksum(current) == f.freeze.checksum {
+		return nil, nil
+	}
+
+	return f.environDrift(f.freeze.environ, current), nil
+}
+
+/*
+WatchFrozen verifica periodicamente se o ambiente do processo divergiu do ambiente guardado por Freeze
+
+A cada intervalo, o checksum do ambiente é comparado com o do congelamento; quando ele muda, onDrift recebe as
+variáveis divergentes. Cada divergência é informada uma única vez, até que o ambiente mude de novo. Sem onDrift,
+as divergências são registradas como avisos. Um pânico em onDrift é recuperado e entregue a WithErrorHandler, e a
+verificação continua. A verificação termina quando o contexto é cancelado ou o carregador é encerrado com Close.
+
+@param ctx context.Context - O contexto que encerra a verificação
+@param interval time.Duration - O intervalo entre as verificações
+@param onDrift func([]KeyChange) - A função chamada com as divergências, ou nil para registrar avisos
+
+@return error - Um erro se o carregador não estiver congelado ou o intervalo não for positivo, ou ErrClosed
+*/
+func (f *FileEnvLoader) WatchFrozen(ctx context.Context, interval time.Duration, onDrift func([]KeyChange)) error {
+	if !f.Frozen() {
+		return fmt.Errorf("o carregador não está congelado: chame Freeze antes de iniciar a verificação")
+	}
+	if interval <= 0 {
+		return fmt.Errorf("intervalo de verificação inválido: %s", interval)
+	}
+	if onDrift == nil {
+		onDrift = func(changes []KeyChange) {
+			for _, change := range changes {
+				f.warn(Warning{
+					Kind:    WarningDrift,
+					Key:     change.Key,
+					Message: fmt.Sprintf("A variável %s foi %s após o congelamento da configuração", change.Key, driftVerbs[change.Kind]),
+				})
+			}
+		}
+	}
+
+	ctx, stop, err := f.lifecycle.track(ctx)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer stop()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		reported := f.freeze.checksum
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			current := currentEnviron()
+			checksum := environChecksum(current)
+			if checksum == reported {
+				continue
+			}
+			reported = checksum
+			if checksum != f.freeze.checksum {
+				if err := safeCall("WatchFrozen", func() { onDrift(f.environDrift(f.freeze.environ, current)) }); err != nil {
+					f.reportError(err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// driftVerbs são os particípios usados nos avisos de divergência de WatchFrozen.
+var driftVerbs = map[DiffKind]string{
+	DiffAdded:   "adicionada",
+	DiffRemoved: "removida",
+	DiffChanged: "alterada",
+}
+
+/*
+environDrift compara dois ambientes do processo, mascarando os valores dos segredos
+
+@param frozen map[string]string - O ambiente guardado no congelamento
+@param current map[string]string - O ambiente atual
+
+@return []KeyChange - As variáveis divergentes, ordenadas pelo nome
+*/
+func (f *FileEnvLoader) environDrift(frozen map[string]string, current map[string]string) []KeyChange {
+	var changes []KeyChange
+	add := func(change KeyChange) {
+		_, secret := f.secrets[change.Key]
+		if change.Secret = secret || f.isSecret(change.Key, nil); change.Secret {
+			if change.Old != "" {
+				change.Old = maskedValue
+			}
+			if change.New != "" {
+				change.New = maskedValue
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	for key, old := range frozen {
+		value, ok := current[key]
+		switch {
+		case !ok:
+			add(KeyChange{Key: key, Kind: DiffRemoved, Old: old})
+		case value != old:
+			add(KeyChange{Key: key, Kind: DiffChanged, Old: old, New: value})
+		}
+	}
+	for key, value := range current {
+		if _, ok := frozen[key]; !ok {
+			add(KeyChange{Key: key, Kind: DiffAdded, New: value})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+
+	return changes
+}
+
+// currentEnviron retorna o ambiente do processo como um mapa.
+func currentEnviron() map[string]string {
+	environ := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok && key != "" {
+			environ[key] = value
+		}
+	}
+
+	return environ
+}
+
+/*
+environChecksum calcula um checksum SHA-256 de um ambiente, independente da ordem das variáveis
+
+@param environ map[string]string - O ambiente
+
+@return string - O checksum em hexadecimal
+*/
+func environChecksum(environ map[string]string) string {
+	keys := make([]string, 0, len(environ))
+	for key := range environ {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, key := range keys {
+		fmt.Fprintf(h, "%s=%s\x00", key, environ[key])
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,270 @@
+package config
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// generatePrefix é o início do marcador de valores gerados, como @generate(hex,32).
+	generatePrefix = "@generate("
+	// generateSeedSize é o tamanho, em bytes, da semente local dos valores gerados.
+	generateSeedSize = 32
+	// maxGenerateSize é o tamanho máximo pedido por um marcador @generate.
+	maxGenerateSize = 1024
+	// alnumAlphabet são os caracteres dos valores gerados com o tipo alnum.
+	alnumAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+/*
+WithGenerateSeed define a semente dos valores gerados por marcadores @generate
+
+Um valor declarado como SESSION_SECRET=@generate(hex,32) é substituído, ao ser carregado, por um valor derivado
+com HMAC-SHA256 da semente, do diretório do arquivo e do nome da variável: o valor é o mesmo a cada
+carregamento na mesma máquina, mas diferente entre máquinas e entre projetos, para que as configurações de
+desenvolvimento não compartilhem segredos fracos escritos no arquivo. Os tipos aceitos são hex (n bytes, 2n
+caracteres), base64 (n bytes, sem preenchimento e seguro para URLs) e alnum (n letras e dígitos); o tamanho
+padrão é 32. Sem esta opção, a semente é lida de locenv/generate-seed no diretório de configuração do usuário
+(os.UserConfigDir) e criada com bytes aleatórios, com permissão 0600, no primeiro uso.
+
+@param seed []byte - A semente
+
+@return Option - A opção que define a semente
+*/
+func WithGenerateSeed(seed []byte) Option {
+	return func(f *FileEnvLoader) {
+		f.generateSeed = append([]byte(nil), seed...)
+	}
+}
+
+/*
+WithPersistGenerated grava os valores gerados por marcadores @generate em um arquivo local
+
+Cada valor gerado é acrescentado ao arquivo como KEY=valor, se a variável ainda não estiver declarada nele.
+Um caminho relativo é resolvido a partir do diretório do arquivo .env base, e o arquivo é criado com permissão
+0600. Use um arquivo que não é versionado e que também é carregado, como .env.local.<usuário> com
+WithUserOverlays, para que o valor persistido prevaleça mesmo se a semente mudar.
+
+@param file string - O arquivo em que os valores são gravados
+
+@return Option - A opção que habilita a gravação
+*/
+func WithPersistGenerated(file string) Option {
+	return func(f *FileEnvLoader) {
+		f.persistGenerated = file
+	}
+}
+
+/*
+generateValue substitui um marcador @generate pelo valor gerado
+
+@param e entry - A declaração da variável
+@param file string - O arquivo da declaração
+
+@return string - O valor gerado, ou o valor original se ele não for um marcador
+@return bool - Se o valor era um marcador
+@return error - Um erro com o arquivo e a linha se o marcador for inválido ou a semente não puder ser obtida
+*/
+func (f *FileEnvLoader) generateValue(e entry, file string) (string, bool, error) {
+	spec := strings.TrimSpace(e.value)
+	if e.quote == '\'' || !strings.HasPrefix(spec, generatePrefix) || !strings.HasSuffix(spec, ")") {
+		return e.value, false, nil
+	}
+
+	kind, size, err := parseGenerateSpec(spec[len(generatePrefix) : len(spec)-1])
+	if err != nil {
+		return "", false, fmt.Errorf("%s:%d: marcador inválido em %s: %w", file, e.line, e.key, err)
+	}
+
+	seed, err := f.loadGenerateSeed()
+	if err != nil {
+		return "", false, fmt.Errorf("%s:%d: não foi possível gerar %s: %w", file, e.line, e.key, err)
+	}
+
+	dir := f.dirOf(file)
+	if f.fsys == nil {
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+	}
+	stream := generateStream(seed, dir+"\x00"+e.key)
+
+	switch kind {
+	case "hex":
+		return hex.EncodeToString(stream(size)), true, nil
+	case "base64":
+		return base64.RawURLEncoding.EncodeToString(stream(size)), true, nil
+	default:
+		var b strings.Builder
+		for b.Len() < size {
+			for _, c := range stream(size) {
+				if int(c) < 256-256%len(alnumAlphabet) && b.Len() < size {
+					b.WriteByte(alnumAlphabet[int(c)%len(alnumAlphabet)])
+				}
+			}
+		}
+		return b.String(), true, nil
+	}
+}
+
+/*
+parseGenerateSpec interpreta os argumentos de um marcador @generate, como "hex,32"
+
+@param args string - Os argumentos entre os parênteses
+
+@return string - O tipo (hex, base64 ou alnum)
+@return int - O tamanho
+@return error - Um erro se o tipo ou o tamanho forem inválidos
+*/
+func parseGenerateSpec(args string) (string, int, error) {
+	kind, sizeText, hasSize := strings.Cut(args, ",")
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	switch kind {
+	case "hex", "base64", "alnum":
+	default:
+		return "", 0, fmt.Errorf("tipo %q desconhecido, esperado hex, base64 ou alnum", kind)
+	}
+
+	size := 32
+	if hasSize {
+		n, err := strconv.Atoi(strings.TrimSpace(sizeText))
+		if err != nil || n <= 0 || n > maxGenerateSize {
+			return "", 0, fmt.Errorf("tamanho %q inválido, esperado um número entre 1 e %d", strings.TrimSpace(sizeText), maxGenerateSize)
+		}
+		size = n
+	}
+
+	return kind, size, nil
+}
+
+/*
+generateStream cria um gerador determinístico de bytes a partir da semente e de um rótulo
+
+Os blocos são HMAC-SHA256(semente, rótulo || contador), de modo que cada chamada continua a sequência.
+
+@param seed []byte - A semente
+@param label string - O rótulo, único por projeto e variável
+
+@return func(n int) []byte - A função que retorna os próximos n bytes
+*/
+func generateStream(seed []byte, label string) func(n int) []byte {
+	var counter uint32
+	var pending []byte
+
+	return func(n int) []byte {
+		for len(pending) < n {
+			mac := hmac.New(sha256.New, seed)
+			mac.Write([]byte(label))
+			binary.Write(mac, binary.BigEndian, counter)
+			counter++
+			pending = mac.Sum(pending)
+		}
+		out := pending[:n:n]
+		pending = pending[n:]
+		return out
+	}
+}
+
+/*
+loadGenerateSeed retorna a semente de WithGenerateSeed ou a semente local do usuário, criando-a se necessário
+
+@return []byte - A semente
+@return error - Um erro se o diretório de configuração não puder ser determinado, lido ou gravado
+*/
+func (f *FileEnvLoader) loadGenerateSeed() ([]byte, error) {
+	if f.generateSeed != nil {
+		return f.generateSeed, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	file := filepath.Join(dir, "locenv", "generate-seed")
+
+	seed, err := os.ReadFile(file)
+	if err == nil && len(seed) >= generateSeedSize {
+		f.generateSeed = seed
+		return seed, nil
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	seed = make([]byte, generateSeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+	if f.dryRun {
+		return seed, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(file, seed, 0o600); err != nil {
+		return nil, err
+	}
+
+	f.generateSeed = seed
+	return seed, nil
+}
+
+/*
+persistGeneratedValues acrescenta os valores gerados ao arquivo de WithPersistGenerated
+
+@param baseFile string - O arquivo .env base, cujo diretório resolve os caminhos relativos
+@param generated map[string]string - Os valores gerados neste carregamento
+@param order []string - As variáveis geradas, na ordem em que foram declaradas
+
+@return error - Um erro se o arquivo não puder ser lido ou gravado
+*/
+func (f *FileEnvLoader) persistGeneratedValues(baseFile string, generated map[string]string, order []string) error {
+	if f.persistGenerated == "" || len(order) == 0 || f.fsys != nil || f.dryRun {
+		return nil
+	}
+
+	file := f.persistGenerated
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(filepath.Dir(baseFile), file)
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	existing, err := parseEntries(strings.NewReader(string(content)), file)
+	if err != nil {
+		return err
+	}
+	declared := entriesToMap(existing)
+
+	var b strings.Builder
+	b.Write(content)
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		b.WriteByte('\n')
+	}
+	added := 0
+	for _, key := range order {
+		if _, ok := declared[key]; ok {
+			continue
+		}
+		fmt.Fprintf(&b, "%s=%s\n", key, QuoteValue(generated[key]))
+		added++
+	}
+	if added == 0 {
+		return nil
+	}
+
+	return writeFileAtomic(file, []byte(b.String()), 0o600)
+}
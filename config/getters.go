@@ -0,0 +1,226 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrKeyNotFound = errors.New("variável não encontrada")
+
+/*
+Lookup retorna o valor efetivo de uma variável
+
+O método procura primeiro entre as variáveis carregadas do arquivo .env (já considerando o valor do processo
+quando ele prevaleceu) e, em seguida, no ambiente do processo, exceto em carregadores que não consultam o
+processo, como o MapLoader. Segredos isolados com WithSecretIsolation não são retornados; use GetSecret para lê-los.
+Se a variável não existir, um nome estruturado (database.host) é procurado na forma achatada (DATABASE_HOST)
+e, em seguida, os nomes alternativos registrados com WithKeyAliases são tentados, em ordem.
+
+@param key string - O nome da variável, um nome estruturado ou um nome lógico registrado com WithKeyAliases
+
+@return string - O valor da variável
+@return bool - Se a variável foi encontrada
+*/
+func (f *FileEnvLoader) Lookup(key string) (string, bool) {
+	if value, ok := f.lookupName(key); ok {
+		return value, true
+	}
+
+	if flat := FlattenKey(key); flat != key {
+		if value, ok := f.lookupName(flat); ok {
+			return value, true
+		}
+	}
+
+	for _, name := range f.keyAliases[key] {
+		if value, ok := f.lookupName(name); ok {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+/*
+lookupName retorna o valor efetivo de uma variável pelo nome exato, sem considerar os nomes alternativos
+
+@param key string - O nome da variável
+
+@return string - O valor da variável
+@return bool - Se a variável foi encontrada
+*/
+func (f *FileEnvLoader) lookupName(key string) (string, bool) {
+	if value, ok := f.values[key]; ok {
+		return value, true
+	}
+
+	if f.noProcessEnv {
+		return "", false
+	}
+
+	return os.LookupEnv(key)
+}
+
+/*
+GetString retorna o valor de uma variável como string
+
+@param key string - O nome da variável
+
+@return string - O valor da variável, ou uma string vazia se ela não existir
+*/
+func (f *FileEnvLoader) GetString(key string) string {
+	value, _ := f.Lookup(key)
+	return value
+}
+
+/*
+GetInt retorna o valor de uma variável convertido para int
+
+@param key string - O nome da variável
+
+@return int - O valor convertido
+@return error - ErrKeyNotFound se a variável não existir, ou um erro de conversão que identifica a variável
+*/
+func (f *FileEnvLoader) GetInt(key string) (int, error) {
+	value, err := f.require(key)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, conversionError(key, value, "int")
+	}
+
+	return n, nil
+}
+
+/*
+GetFloat retorna o valor de uma variável convertido para float64
+
+Com WithDecimalComma, valores com vírgula decimal (ex.: 0,5) também são aceitos.
+
+@param key string - O nome da variável
+
+@return float64 - O valor convertido
+@return error - ErrKeyNotFound se a variável não existir, ou um erro de conversão que identifica a variável
+*/
+func (f *FileEnvLoader) GetFloat(key string) (float64, error) {
+	value, err := f.require(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if f.decimalComma {
+		return f.parseDecimal(key, value)
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, conversionError(key, value, "float")
+	}
+
+	return n, nil
+}
+
+/*
+GetBool retorna o valor de uma variável convertido para bool
+
+São aceitos os valores reconhecidos por strconv.ParseBool (1, t, true, 0, f, false etc.).
+
+@param key string - O nome da variável
+
+@return bool - O valor convertido
+@return error - ErrKeyNotFound se a variável não existir, ou um erro de conversão que identifica a variável
+*/
+func (f *FileEnvLoader) GetBool(key string) (bool, error) {
+	value, err := f.require(key)
+	if err != nil {
+		return false, err
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, conversionError(key, value, "bool")
+	}
+
+	return b, nil
+}
+
+/*
+GetDuration retorna o valor de uma variável convertido para time.Duration
+
+O valor deve seguir o formato de time.ParseDuration (ex.: "30s", "1h15m").
+
+@param key string - O nome da variável
+
+@return time.Duration - O valor convertido
+@return error - ErrKeyNotFound se a variável não existir, ou um erro de conversão que identifica a variável
+*/
+func (f *FileEnvLoader) GetDuration(key string) (time.Duration, error) {
+	value, err := f.require(key)
+	if err != nil {
+		return 0, err
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, conversionError(key, value, "duration")
+	}
+
+	return d, nil
+}
+
+/*
+GetStringSlice retorna o valor de uma variável separado por vírgulas
+
+Os espaços nas extremidades de cada item são removidos e os itens vazios são descartados.
+
+@param key string - O nome da variável
+
+@return []string - Os itens da lista, ou nil se a variável não existir ou estiver vazia
+*/
+func (f *FileEnvLoader) GetStringSlice(key string) []string {
+	var items []string
+	for _, item := range strings.Split(f.GetString(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+/*
+require retorna o valor de uma variável ou ErrKeyNotFound se ela não existir
+
+@param key string - O nome da variável
+
+@return string - O valor da variável
+@return error - ErrKeyNotFound, identificando a variável, se ela não existir
+*/
+func (f *FileEnvLoader) require(key string) (string, error) {
+	value, ok := f.Lookup(key)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+
+	return value, nil
+}
+
+/*
+conversionError cria o erro retornado quando o valor de uma variável não pode ser convertido
+
+@param key string - O nome da variável
+@param value string - O valor que não pôde ser convertido
+@param kind string - O tipo esperado
+
+@return error - O erro de conversão
+*/
+func conversionError(key string, value string, kind string) error {
+	return fmt.Errorf("variável %s: valor %q não é um %s válido", key, value, kind)
+}
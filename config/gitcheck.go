@@ -0,0 +1,205 @@
+package config
+
+import (
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultSecretPatterns são os padrões de nomes de variáveis secretas usados pelo comando locenv doctor.
+var DefaultSecretPatterns = []string{"*PASSWORD*", "*PASSWD*", "*SECRET*", "*TOKEN*", "*_KEY", "*PRIVATE*", "*CREDENTIAL*"}
+
+/*
+GitRisk descreve um arquivo .env com segredos em texto claro que pode acabar no repositório git
+
+File string - O caminho do arquivo
+Keys []string - As variáveis secretas em texto claro, em ordem alfabética
+Tracked bool - Se o arquivo já está versionado pelo git
+Ignored bool - Se o arquivo está coberto pelo .gitignore
+*/
+type GitRisk struct {
+	File    string   `json:"file"`
+	Keys    []string `json:"keys"`
+	Tracked bool     `json:"tracked"`
+	Ignored bool     `json:"ignored"`
+}
+
+// String descreve o risco em uma linha, própria para logs e relatórios.
+func (r GitRisk) String() string {
+	reason := "não está coberto pelo .gitignore"
+	if r.Tracked {
+		reason = "está versionado pelo git"
+	}
+
+	return fmt.Sprintf("%s contém segredos em texto claro (%s) e %s", r.File, strings.Join(r.Keys, ", "), reason)
+}
+
+/*
+WithGitSafetyCheck habilita a verificação do git para os arquivos carregados
+
+Com esta opção, cada arquivo carregado que contém segredos em texto claro é verificado com o git: se ele estiver
+versionado ou não estiver coberto pelo .gitignore, um aviso é registrado e adicionado a Result.Warnings e
+Plan.Warnings. Valores cifrados (enc:<backend>:) não geram avisos. Se o git não estiver disponível ou o arquivo
+estiver fora de um repositório, a verificação é ignorada. A verificação executa o git e, por isso, é opcional.
+
+@return Option - A opção que habilita a verificação
+*/
+func WithGitSafetyCheck() Option {
+	return func(f *FileEnvLoader) {
+		f.gitSafetyCheck = true
+	}
+}
+
+/*
+gitWarnings verifica com o git os arquivos que definiram segredos em texto claro
+
+@param origins map[string]origin - A declaração que definiu cada variável
+@param secrets map[string]string - As variáveis classificadas como segredo
+@param encrypted map[string]bool - As chaves cujos valores estavam cifrados
+
+@return []Warning - Os avisos gerados
+*/
+func (f *FileEnvLoader) gitWarnings(origins map[string]origin, secrets map[string]string, encrypted map[string]bool) []Warning {
+	if !f.gitSafetyCheck {
+		return nil
+	}
+
+	byFile := make(map[string][]string)
+	for key := range secrets {
+		if file := origins[key].file; !encrypted[key] && f.isFileSource(file) {
+			byFile[file] = append(byFile[file], key)
+		}
+	}
+
+	var warnings []Warning
+	for _, file := range sortedKeysOf(byFile) {
+		risk, ok := checkGitRisk(file, byFile[file])
+		if !ok {
+			f.tracef("verificação do git ignorada para %s", file)
+			continue
+		}
+		if risk != nil {
+			w := Warning{Kind: WarningExposure, File: file, Message: risk.String()}
+			f.warn(w)
+			warnings = append(warnings, w)
+		}
+	}
+
+	return warnings
+}
+
+/*
+ScanGitRisks procura, a partir do diretório informado, arquivos .env com segredos em texto claro que estão
+versionados pelo git ou não estão cobertos pelo .gitignore
+
+São considerados os arquivos chamados .env ou iniciados por ".env.". Uma variável é secreta quando o seu nome
+corresponde a algum dos padrões (sintaxe de path.Match) e o seu valor não está vazio nem cifrado.
+O diretório .git e os arquivos com sintaxe inválida são ignorados.
+
+@param root string - O diretório inicial da busca
+@param patterns []string - Os padrões de nomes de variáveis secretas
+
+@return []GitRisk - Os riscos encontrados, em ordem de caminho
+@return error - Um erro se a busca falhar
+*/
+func ScanGitRisks(root string, patterns []string) ([]GitRisk, error) {
+	classifier := &FileEnvLoader{secretPatterns: patterns}
+
+	var risks []GitRisk
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isEnvFileName(d.Name()) {
+			return nil
+		}
+
+		entries, err := classifier.loadEnvFile(path)
+		if err != nil {
+			// Arquivos com sintaxe inválida são relatados pela validação de sintaxe, não por esta busca.
+			return nil
+		}
+
+		var keys []string
+		for _, e := range entries {
+			if e.value != "" && !strings.HasPrefix(e.value, encryptedPrefix) && classifier.isSecret(e.key, nil) {
+				keys = append(keys, e.key)
+			}
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+
+		if risk, ok := checkGitRisk(path, keys); ok && risk != nil {
+			risks = append(risks, *risk)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return risks, nil
+}
+
+/*
+checkGitRisk consulta o git sobre um arquivo que contém segredos em texto claro
+
+@param file string - O caminho do arquivo
+@param keys []string - As variáveis secretas em texto claro do arquivo
+
+@return *GitRisk - O risco encontrado, ou nil se o arquivo estiver ignorado e não versionado
+@return bool - Se o git pôde ser consultado (false sem git ou fora de um repositório)
+*/
+func checkGitRisk(file string, keys []string) (*GitRisk, bool) {
+	dir, name := filepath.Dir(file), filepath.Base(file)
+	if exec.Command("git", "-C", dir, "rev-parse", "--is-inside-work-tree").Run() != nil {
+		return nil, false
+	}
+
+	tracked := exec.Command("git", "-C", dir, "ls-files", "--error-unmatch", "--", name).Run() == nil
+	ignored := exec.Command("git", "-C", dir, "check-ignore", "-q", "--", name).Run() == nil
+	if ignored && !tracked {
+		return nil, true
+	}
+
+	sort.Strings(keys)
+	return &GitRisk{File: file, Keys: keys, Tracked: tracked, Ignored: ignored}, true
+}
+
+/*
+isEnvFileName informa se o nome de um arquivo segue a convenção de arquivos .env
+
+@param name string - O nome do arquivo
+
+@return bool - Se o nome é ".env" ou começa com ".env."
+*/
+func isEnvFileName(name string) bool {
+	return name == ".env" || strings.HasPrefix(name, ".env.")
+}
+
+/*
+sortedKeysOf retorna as chaves de um mapa de listas em ordem alfabética
+
+@param m map[string][]string - O mapa
+
+@return []string - As chaves ordenadas
+*/
+func sortedKeysOf(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
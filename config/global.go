@@ -0,0 +1,105 @@
+package config
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	defaultMu     sync.Mutex
+	defaultLoader IEnvLoader
+)
+
+/*
+Default retorna o carregador padrão usado pela API de conveniência do pacote
+
+O carregador padrão é criado de forma preguiçosa, na primeira chamada, com NewEnvLoader sem opções.
+Use Load com opções para recriá-lo com uma configuração diferente.
+
+@return IEnvLoader - O carregador padrão
+*/
+func Default() IEnvLoader {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	if defaultLoader == nil {
+		defaultLoader = NewEnvLoader()
+	}
+
+	return defaultLoader
+}
+
+/*
+Load carrega as variáveis de ambiente com o carregador padrão
+
+Se opções forem informadas, o carregador padrão é recriado com elas antes do carregamento, aceitando
+as mesmas opções de NewEnvLoader. Assim, programas pequenos não precisam criar e repassar um IEnvLoader.
+Se o carregador padrão tiver sido congelado com Freeze, Load retorna ErrFrozen sem recriá-lo.
+
+@param opts ...Option - As opções do carregador padrão
+
+@return error - Um erro se o arquivo .env não puder ser encontrado ou carregado
+*/
+func Load(opts ...Option) error {
+	if len(opts) > 0 {
+		defaultMu.Lock()
+		if f, ok := defaultLoader.(*FileEnvLoader); ok && f.Frozen() {
+			defaultMu.Unlock()
+			return ErrFrozen
+		}
+		defaultLoader = NewEnvLoader(opts...)
+		defaultMu.Unlock()
+	}
+
+	return Default().LoadEnv()
+}
+
+// GetString lê uma variável como string pelo carregador padrão.
+func GetString(key string) string {
+	return Default().GetString(key)
+}
+
+// GetInt lê uma variável como int pelo carregador padrão.
+func GetInt(key string) (int, error) {
+	return Default().GetInt(key)
+}
+
+// GetFloat lê uma variável como float64 pelo carregador padrão.
+func GetFloat(key string) (float64, error) {
+	return Default().GetFloat(key)
+}
+
+// GetBool lê uma variável como bool pelo carregador padrão.
+func GetBool(key string) (bool, error) {
+	return Default().GetBool(key)
+}
+
+// GetDuration lê uma variável como time.Duration pelo carregador padrão.
+func GetDuration(key string) (time.Duration, error) {
+	return Default().GetDuration(key)
+}
+
+// GetStringSlice lê uma variável separada por vírgulas pelo carregador padrão.
+func GetStringSlice(key string) []string {
+	return Default().GetStringSlice(key)
+}
+
+// GetPath lê uma variável como um caminho, resolvido em relação ao arquivo que a definiu, pelo carregador padrão.
+func GetPath(key string, checks ...PathCheck) (string, error) {
+	return Default().GetPath(key, checks...)
+}
+
+// GetBase64 lê o conteúdo binário de uma variável codificada em base64, com validações, pelo carregador padrão.
+func GetBase64(key string, checks ...ContentCheck) ([]byte, error) {
+	return Default().GetBase64(key, checks...)
+}
+
+// GetHex lê o conteúdo binário de uma variável codificada em hexadecimal, com validações, pelo carregador padrão.
+func GetHex(key string, checks ...ContentCheck) ([]byte, error) {
+	return Default().GetHex(key, checks...)
+}
+
+// GetSecret lê um segredo pelo carregador padrão.
+func GetSecret(key string) (string, bool) {
+	return Default().GetSecret(key)
+}
@@ -0,0 +1,261 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+/*
+WithKeyGroup declara um grupo de variáveis que precisam ser definidas em conjunto: todas ou nenhuma
+
+É o caso de integrações opcionais com várias variáveis, como SMTP_HOST, SMTP_USER e SMTP_PASS: sem nenhuma, a
+integração fica desativada; com apenas algumas, a configuração está incompleta e o erro só apareceria no
+primeiro envio. Uma variável conta como definida como em WithRequired, mesmo com o valor vazio. Os grupos
+configurados parcialmente são reunidos no *ValidationError do carregamento, com as variáveis definidas e as
+ausentes de cada um.
+
+@param keys ...string - Os nomes das variáveis do grupo
+
+@return Option - A opção que declara o grupo
+*/
+func WithKeyGroup(keys ...string) Option {
+	return func(f *FileEnvLoader) {
+		if len(keys) > 1 {
+			f.keyGroups = append(f.keyGroups, keys)
+		}
+	}
+}
+
+/*
+validateGroups verifica os grupos declarados com WithKeyGroup
+
+@param values map[string]string - As variáveis a serem aplicadas
+@param secrets map[string]string - As variáveis classificadas como segredo
+
+@return []string - Os problemas encontrados, um por grupo configurado parcialmente
+*/
+func (f *FileEnvLoader) validateGroups(values map[string]string, secrets map[string]string) []string {
+	var problems []string
+	for _, group := range f.keyGroups {
+		var defined, missing []string
+		for _, key := range group {
+			if f.isDefined(key, values, secrets) {
+				defined = append(defined, key)
+			} else {
+				missing = append(missing, key)
+			}
+		}
+		if len(defined) > 0 && len(missing) > 0 {
+			problems = append(problems, fmt.Sprintf("grupo %s configurado parcialmente: definidas %s; ausentes %s",
+				strings.Join(group, ", "), strings.Join(defined, ", "), strings.Join(missing, ", ")))
+		}
+	}
+
+	return problems
+}
+
+/*
+WithMutuallyExclusive declara alternativas das quais no máximo uma pode ser definida
+
+Cada alternativa é o nome de uma variável ou um padrão (sintaxe de path.Match) que representa um conjunto de
+variáveis granulares, como em WithMutuallyExclusive("DATABASE_URL", "DB_*"): a conexão é configurada por uma
+URL ou por DB_HOST, DB_PORT e as demais, mas não pelas duas formas, o que deixaria em dúvida qual delas
+prevalece. Os padrões são comparados com as variáveis dos arquivos e os segredos; os nomes também com o
+ambiente do processo. Quando mais de uma alternativa é definida, o *ValidationError do carregamento informa
+as variáveis de cada uma e onde foram declaradas.
+
+@param alternatives ...string - Os nomes ou padrões das alternativas
+
+@return Option - A opção que declara as alternativas
+*/
+func WithMutuallyExclusive(alternatives ...string) Option {
+	return func(f *FileEnvLoader) {
+		if len(alternatives) > 1 {
+			f.exclusive = append(f.exclusive, alternatives)
+		}
+	}
+}
+
+/*
+validateExclusive verifica as alternativas declaradas com WithMutuallyExclusive
+
+@param values map[string]string - As variáveis a serem aplicadas
+@param secrets map[string]string - As variáveis classificadas como segredo
+@param origins map[string]origin - A declaração de cada variável, usada nas mensagens
+
+@return []string - Os problemas encontrados, um por conjunto de alternativas
+*/
+func (f *FileEnvLoader) validateExclusive(values map[string]string, secrets map[string]string, origins map[string]origin) []string {
+	var problems []string
+	for _, alternatives := range f.exclusive {
+		var chosen, found []string
+		for _, alternative := range alternatives {
+			keys := f.alternativeKeys(alternative, values, secrets)
+			if len(keys) == 0 {
+				continue
+			}
+			chosen = append(chosen, alternative)
+			for _, key := range keys {
+				_, loaded := values[key]
+				_, secret := secrets[key]
+				if o, ok := origins[key]; ok {
+					key = fmt.Sprintf("%s (%s:%d)", key, o.file, o.line)
+				} else if !loaded && !secret {
+					key += " (" + SourceProcess + ")"
+				}
+				found = append(found, key)
+			}
+		}
+		if len(chosen) > 1 {
+			problems = append(problems, fmt.Sprintf("alternativas mutuamente exclusivas %s definidas em conjunto: %s; defina apenas uma delas",
+				strings.Join(chosen, " e "), strings.Join(found, ", ")))
+		}
+	}
+
+	return problems
+}
+
+/*
+alternativeKeys retorna as variáveis definidas que correspondem a uma alternativa de WithMutuallyExclusive
+
+@param alternative string - O nome ou padrão da alternativa
+@param values map[string]string - As variáveis a serem aplicadas
+@param secrets map[string]string - As variáveis classificadas como segredo
+
+@return []string - As variáveis definidas, em ordem alfabética
+*/
+func (f *FileEnvLoader) alternativeKeys(alternative string, values map[string]string, secrets map[string]string) []string {
+	if !strings.ContainsAny(alternative, `*?[\`) {
+		if f.isDefined(alternative, values, secrets) {
+			return []string{alternative}
+		}
+		return nil
+	}
+
+	var keys []string
+	for _, m := range []map[string]string{values, secrets} {
+		for key := range m {
+			if matchesAny([]string{alternative}, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+/*
+Requirement é uma exigência condicional de variáveis, criada por Require
+
+keys []string - Os nomes das variáveis exigidas
+*/
+type Requirement struct {
+	keys []string
+}
+
+/*
+condition é uma exigência condicional declarada com Require(...).If
+
+keys []string - Os nomes das variáveis exigidas
+when string - O nome da variável da condição
+values []string - Os valores da condição que tornam as variáveis obrigatórias, ou nil para qualquer valor
+*/
+type condition struct {
+	keys   []string
+	when   string
+	values []string
+}
+
+/*
+Require inicia a declaração de variáveis que são obrigatórias apenas sob uma condição
+
+	config.Require("TLS_CERT_FILE", "TLS_KEY_FILE").If("TLS_ENABLED", "true", "1")
+
+A condição é avaliada depois da combinação dos arquivos, com os valores efetivos. Para variáveis sempre
+obrigatórias, use WithRequired.
+
+@param keys ...string - Os nomes das variáveis exigidas
+
+@return Requirement - A exigência, completada com If
+*/
+func Require(keys ...string) Requirement {
+	return Requirement{keys: keys}
+}
+
+/*
+If completa a exigência com a sua condição
+
+As variáveis passam a ser obrigatórias quando a variável da condição tem algum dos valores informados,
+comparados sem diferenciar maiúsculas de minúsculas e sem os espaços nas extremidades. Sem valores, basta que a
+variável da condição esteja definida. As variáveis ausentes são reunidas no *ValidationError do carregamento,
+com a condição que as tornou obrigatórias.
+
+@param key string - O nome da variável da condição (ex.: TLS_ENABLED)
+@param values ...string - Os valores que tornam as variáveis obrigatórias
+
+@return Option - A opção que declara a exigência
+*/
+func (r Requirement) If(key string, values ...string) Option {
+	return func(f *FileEnvLoader) {
+		f.conditions = append(f.conditions, condition{keys: r.keys, when: key, values: values})
+	}
+}
+
+/*
+validateConditions verifica as exigências declaradas com Require(...).If
+
+@param values map[string]string - As variáveis a serem aplicadas
+@param secrets map[string]string - As variáveis classificadas como segredo
+
+@return []string - Os problemas encontrados, um por variável ausente
+*/
+func (f *FileEnvLoader) validateConditions(values map[string]string, secrets map[string]string) []string {
+	var problems []string
+	for _, c := range f.conditions {
+		value, ok := values[c.when]
+		if !ok {
+			value, ok = secrets[c.when]
+		}
+		if !ok && !f.noProcessEnv {
+			value, ok = os.LookupEnv(c.when)
+		}
+		if !ok || !c.matches(value) {
+			continue
+		}
+
+		reason := c.when + " definida"
+		if len(c.values) > 0 {
+			reason = c.when + "=" + strings.TrimSpace(value)
+		}
+		for _, key := range c.keys {
+			if !f.isDefined(key, values, secrets) {
+				problems = append(problems, fmt.Sprintf("variável obrigatória %s não definida (exigida porque %s)", key, reason))
+			}
+		}
+	}
+
+	return problems
+}
+
+/*
+matches informa se o valor da variável da condição torna as variáveis obrigatórias
+
+@param value string - O valor da variável da condição
+
+@return bool - Se a condição é atendida
+*/
+func (c condition) matches(value string) bool {
+	if len(c.values) == 0 {
+		return true
+	}
+	for _, candidate := range c.values {
+		if strings.EqualFold(strings.TrimSpace(value), strings.TrimSpace(candidate)) {
+			return true
+		}
+	}
+
+	return false
+}
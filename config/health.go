@@ -0,0 +1,241 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+/*
+HealthStatus resume o estado da configuração de um carregador
+*/
+type HealthStatus string
+
+const (
+	// HealthOK indica que o último carregamento teve sucesso e todos os provedores responderam.
+	HealthOK HealthStatus = "ok"
+	// HealthDegraded indica que a configuração carregada continua em uso, mas a última atualização falhou ou um provedor não respondeu.
+	HealthDegraded HealthStatus = "degraded"
+	// HealthFailing indica que nenhum carregamento teve sucesso.
+	HealthFailing HealthStatus = "failing"
+)
+
+// Os códigos de GRPCServingStatus, iguais aos de grpc_health_v1.HealthCheckResponse_ServingStatus.
+const (
+	GRPCServing    int32 = 1
+	GRPCNotServing int32 = 2
+)
+
+/*
+HealthChecker pode ser implementado por um Decrypter ou provedor remoto para informar se está acessível
+
+CheckHealth verifica se o provedor responde.
+@param ctx context.Context - O contexto da verificação, com o prazo definido pelo chamador
+@return error - Um erro se o provedor não estiver acessível
+*/
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
+/*
+ProviderHealth descreve a acessibilidade de um provedor
+
+Name string - O nome do backend com que o provedor foi registrado
+Reachable bool - Se o provedor respondeu
+Error string - O erro retornado pelo provedor, quando houver
+*/
+type ProviderHealth struct {
+	Name      string `json:"name"`
+	Reachable bool   `json:"reachable"`
+	Error     string `json:"error,omitempty"`
+}
+
+/*
+Health é o relatório de saúde da configuração de um carregador
+
+Status HealthStatus - O estado resumido
+LastSuccess time.Time - O momento do último carregamento ou atualização com sucesso
+LastAttempt time.Time - O momento da última tentativa de carregamento ou atualização
+LastError string - O erro da última tentativa, quando ela falhou
+ValidationProblems []string - Os problemas de validação da última tentativa, quando ela falhou na validação
+Providers []ProviderHealth - A acessibilidade dos provedores que implementam HealthChecker, ordenados pelo nome
+*/
+type Health struct {
+	Status             HealthStatus     `json:"status"`
+	LastSuccess        time.Time        `json:"last_success"`
+	LastAttempt        time.Time        `json:"last_attempt"`
+	LastError          string           `json:"last_error,omitempty"`
+	ValidationProblems []string         `json:"validation_problems,omitempty"`
+	Providers          []ProviderHealth `json:"providers,omitempty"`
+}
+
+/*
+Stale informa se a configuração não é atualizada com sucesso há mais tempo que o permitido
+
+@param maxAge time.Duration - A idade máxima aceita; zero desativa a verificação
+
+@return bool - Se a configuração está desatualizada
+*/
+func (h Health) Stale(maxAge time.Duration) bool {
+	return maxAge > 0 && !h.LastSuccess.IsZero() && time.Since(h.LastSuccess) > maxAge
+}
+
+/*
+Serving informa se a aplicação pode continuar atendendo com a configuração atual
+
+@param maxAge time.Duration - A idade máxima aceita para a configuração; zero desativa a verificação
+
+@return bool - Se o estado não é HealthFailing e a configuração não está desatualizada
+*/
+func (h Health) Serving(maxAge time.Duration) bool {
+	return h.Status != HealthFailing && !h.Stale(maxAge)
+}
+
+/*
+GRPCServingStatus converte o relatório para o estado do protocolo de saúde do gRPC
+
+O valor pode ser convertido diretamente para grpc_health_v1.HealthCheckResponse_ServingStatus e usado em
+health.Server.SetServingStatus, sem que a biblioteca dependa do gRPC.
+
+@param maxAge time.Duration - A idade máxima aceita para a configuração; zero desativa a verificação
+
+@return int32 - GRPCServing ou GRPCNotServing
+*/
+func (h Health) GRPCServingStatus(maxAge time.Duration) int32 {
+	if h.Serving(maxAge) {
+		return GRPCServing
+	}
+
+	return GRPCNotServing
+}
+
+/*
+HealthReporter é implementado pelos carregadores que informam a saúde da configuração
+
+HealthContext verifica os provedores e retorna o relatório de saúde.
+@param ctx context.Context - O contexto da verificação dos provedores
+@return Health - O relatório de saúde
+*/
+type HealthReporter interface {
+	HealthContext(ctx context.Context) Health
+}
+
+/*
+HealthHandler cria um http.Handler para endpoints como /healthz
+
+O handler responde com o relatório em JSON e o código 200 enquanto a configuração puder ser usada, ou 503
+quando nenhum carregamento teve sucesso ou a configuração está desatualizada. A verificação dos provedores
+usa o contexto da requisição.
+
+@param r HealthReporter - O carregador
+@param maxAge time.Duration - A idade máxima aceita para a configuração; zero desativa a verificação
+
+@return http.Handler - O handler do endpoint
+*/
+func HealthHandler(r HealthReporter, maxAge time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		h := r.HealthContext(req.Context())
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if !h.Serving(maxAge) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(h)
+	})
+}
+
+/*
+healthState guarda o resultado das tentativas de carregamento, protegido para leitura concorrente
+
+mu sync.Mutex - Protege os demais campos
+lastSuccess time.Time - O momento do último sucesso
+lastAttempt time.Time - O momento da última tentativa
+lastErr error - O erro da última tentativa
+*/
+type healthState struct {
+	mu          sync.Mutex
+	lastSuccess time.Time
+	lastAttempt time.Time
+	lastErr     error
+}
+
+// record registra o resultado de uma tentativa de carregamento.
+func (s *healthState) record(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastAttempt = time.Now()
+	s.lastErr = err
+	if err == nil {
+		s.lastSuccess = s.lastAttempt
+	}
+}
+
+/*
+Health retorna o relatório de saúde da configuração
+
+@return Health - O relatório de saúde
+*/
+func (f *FileEnvLoader) Health() Health {
+	return f.HealthContext(context.Background())
+}
+
+/*
+HealthContext retorna o relatório de saúde da configuração, verificando os provedores com o contexto informado
+
+Os Decrypters registrados com WithDecrypter que implementam HealthChecker são verificados a cada chamada.
+
+@param ctx context.Context - O contexto da verificação dos provedores
+
+@return Health - O relatório de saúde
+*/
+func (f *FileEnvLoader) HealthContext(ctx context.Context) Health {
+	f.health.mu.Lock()
+	h := Health{LastSuccess: f.health.lastSuccess, LastAttempt: f.health.lastAttempt}
+	lastErr := f.health.lastErr
+	f.health.mu.Unlock()
+
+	if lastErr != nil {
+		h.LastError = lastErr.Error()
+		var validationErr *ValidationError
+		if errors.As(lastErr, &validationErr) {
+			h.ValidationProblems = validationErr.Problems
+		}
+	}
+
+	for name, d := range f.decrypters {
+		checker, ok := d.(HealthChecker)
+		if !ok {
+			continue
+		}
+		provider := ProviderHealth{Name: name, Reachable: true}
+		if err := checker.CheckHealth(ctx); err != nil {
+			provider.Reachable, provider.Error = false, err.Error()
+		}
+		h.Providers = append(h.Providers, provider)
+	}
+	sort.Slice(h.Providers, func(i, j int) bool {
+		return h.Providers[i].Name < h.Providers[j].Name
+	})
+
+	switch {
+	case h.LastSuccess.IsZero():
+		h.Status = HealthFailing
+	case lastErr != nil:
+		h.Status = HealthDegraded
+	default:
+		h.Status = HealthOK
+		for _, p := range h.Providers {
+			if !p.Reachable {
+				h.Status = HealthDegraded
+			}
+		}
+	}
+
+	return h
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// helmDefaultRoot é a seção de values.yaml que recebe as variáveis sem mapeamento em HelmMapping.
+const helmDefaultRoot = "env"
+
+/*
+HelmMapping associa variáveis a caminhos de values.yaml, no formato de --set do Helm (ex.: database.host)
+
+Uma chave é o nome exato de uma variável ou um prefixo terminado em "*". No caminho de um prefixo, "*" é trocado
+pelo restante do nome em lowerCamelCase, a convenção dos charts:
+
+	config.HelmMapping{
+		"DB_HOST":  "database.host",
+		"REDIS_*":  "redis.*", // REDIS_MAX_CONNS vira redis.maxConns
+		"REPLICAS": "replicaCount",
+	}
+
+Os nomes exatos têm precedência sobre os prefixos, e o prefixo mais longo vence. As variáveis sem mapeamento
+ficam em env.<VARIÁVEL>, prontas para um range no template do Deployment.
+*/
+type HelmMapping map[string]string
+
+/*
+validate verifica se cada prefixo tem um caminho com "*" e se os caminhos são bem formados
+
+@return error - Um erro indicando o primeiro mapeamento inválido
+*/
+func (m HelmMapping) validate() error {
+	for _, key := range sortedKeys(m) {
+		target := m[key]
+		prefix, pattern := strings.CutSuffix(key, "*")
+		switch {
+		case strings.ContainsAny(prefix, "*?["):
+			return fmt.Errorf("mapeamento Helm %q: apenas um \"*\" no fim do nome é aceito", key)
+		case pattern != strings.Contains(target, "*"):
+			return fmt.Errorf("mapeamento Helm %q: o caminho %q deve ter \"*\" se, e somente se, o nome terminar em \"*\"", key, target)
+		case strings.Count(target, "*") > 1:
+			return fmt.Errorf("mapeamento Helm %q: o caminho %q tem mais de um \"*\"", key, target)
+		}
+		for _, part := range strings.Split(target, ".") {
+			if part == "" {
+				return fmt.Errorf("mapeamento Helm %q: caminho inválido %q", key, target)
+			}
+		}
+	}
+
+	return nil
+}
+
+/*
+path retorna o caminho de values.yaml de uma variável
+
+@param key string - O nome da variável
+
+@return []string - As partes do caminho
+*/
+func (m HelmMapping) path(key string) []string {
+	if target, ok := m[key]; ok {
+		return strings.Split(target, ".")
+	}
+
+	best := ""
+	for pattern := range m {
+		prefix, ok := strings.CutSuffix(pattern, "*")
+		if ok && strings.HasPrefix(key, prefix) && len(pattern) > len(best) {
+			best = pattern
+		}
+	}
+	rest := lowerCamel(strings.TrimPrefix(key, strings.TrimSuffix(best, "*")))
+	if best == "" || rest == "" {
+		return []string{helmDefaultRoot, key}
+	}
+
+	return strings.Split(strings.Replace(m[best], "*", rest, 1), ".")
+}
+
+/*
+helmValues monta a árvore de values.yaml com as variáveis
+
+@param values map[string]string - As variáveis
+@param mapping HelmMapping - O mapeamento das variáveis para os caminhos
+
+@return map[string]any - A árvore, com mapas nas seções e strings nas folhas
+@return error - Um erro se duas variáveis ocuparem o mesmo caminho, ou uma folha for também uma seção
+*/
+func helmValues(values map[string]string, mapping HelmMapping) (map[string]any, error) {
+	tree := make(map[string]any)
+	owners := make(map[string]string)
+	for _, key := range sortedKeys(values) {
+		parts := mapping.path(key)
+		node := tree
+		for i, part := range parts {
+			at := strings.Join(parts[:i+1], ".")
+			if i == len(parts)-1 {
+				if _, taken := node[part]; taken {
+					return nil, fmt.Errorf("as variáveis %s e %s ocupam o mesmo caminho %s em values.yaml", owners[at], key, at)
+				}
+				node[part] = values[key]
+				owners[at] = key
+				break
+			}
+
+			child, ok := node[part].(map[string]any)
+			if !ok {
+				if _, taken := node[part]; taken {
+					return nil, fmt.Errorf("o caminho %s de %s é o valor de %s em values.yaml", at, key, owners[at])
+				}
+				child = make(map[string]any)
+				node[part] = child
+			}
+			node = child
+		}
+	}
+
+	return tree, nil
+}
+
+/*
+writeHelmValues escreve uma árvore de values.yaml em YAML, com as chaves em ordem alfabética
+
+@param b *strings.Builder - O destino
+@param tree map[string]any - A árvore de helmValues
+@param indent string - O recuo do nível atual
+*/
+func writeHelmValues(b *strings.Builder, tree map[string]any, indent string) {
+	keys := make([]string, 0, len(tree))
+	for key := range tree {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		switch value := tree[key].(type) {
+		case map[string]any:
+			fmt.Fprintf(b, "%s%s:\n", indent, yamlKey(key))
+			writeHelmValues(b, value, indent+"  ")
+		case string:
+			fmt.Fprintf(b, "%s%s: %s\n", indent, yamlKey(key), yamlString(value))
+		}
+	}
+}
+
+/*
+yamlKey retorna uma chave YAML, entre aspas se tiver caracteres além de letras, dígitos, "_" e "-", ou se puder ser
+lida como outro tipo, como on, no ou 123
+
+@param key string - A chave
+
+@return string - A chave, com aspas quando necessário
+*/
+func yamlKey(key string) string {
+	switch strings.ToLower(key) {
+	case "", "y", "n", "yes", "no", "on", "off", "true", "false", "null":
+		return yamlString(key)
+	}
+	if strings.Trim(key, "0123456789") == "" {
+		return yamlString(key)
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return yamlString(key)
+		}
+	}
+
+	return key
+}
+
+// lowerCamel converte um nome como MAX_CONNS para lowerCamelCase (maxConns).
+func lowerCamel(name string) string {
+	var b strings.Builder
+	for i, word := range strings.FieldsFunc(strings.ToLower(name), func(r rune) bool { return r == '_' || r == '-' || r == '.' }) {
+		if i > 0 {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		b.WriteString(word)
+	}
+
+	return b.String()
+}
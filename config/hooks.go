@@ -0,0 +1,195 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/jonh-dev/go-logger/logger"
+)
+
+// SourceHook é a origem informada por Source para as variáveis adicionadas por um Hook.
+const SourceHook = "hook"
+
+// HookStage define o momento do carregamento em que um Hook é chamado.
+type HookStage int
+
+const (
+	// BeforeLoad é chamado antes da leitura, com os arquivos que serão lidos; Values é nil.
+	BeforeLoad HookStage = iota
+	// AfterParse é chamado depois da leitura de todos os arquivos, antes da decifragem e da validação.
+	AfterParse
+	// BeforeApply é chamado depois da validação, com as variáveis e os segredos que serão aplicados.
+	BeforeApply
+	// AfterLoad é chamado depois que as variáveis foram aplicadas, com uma cópia dos valores efetivos.
+	AfterLoad
+)
+
+// String retorna o nome do momento, como usado em WithHook.
+func (s HookStage) String() string {
+	switch s {
+	case BeforeLoad:
+		return "BeforeLoad"
+	case AfterParse:
+		return "AfterParse"
+	case BeforeApply:
+		return "BeforeApply"
+	case AfterLoad:
+		return "AfterLoad"
+	default:
+		return fmt.Sprintf("HookStage(%d)", int(s))
+	}
+}
+
+/*
+HookContext é o estado do carregamento entregue a um Hook
+
+Os campos podem ser alterados pelo hook, e as alterações valem para as etapas seguintes: em BeforeLoad, Files
+define os arquivos lidos; em AfterParse e BeforeApply, as variáveis adicionadas, alteradas ou removidas de
+Values (e, em BeforeApply, de Secrets) são as que seguem adiante. Em AfterLoad, os mapas são cópias e as
+alterações não têm efeito.
+
+Stage HookStage - O momento do carregamento
+Env string - O ambiente carregado
+Files []string - Os arquivos, na ordem em que são aplicados
+Values map[string]string - As variáveis do momento, ou nil em BeforeLoad
+Secrets map[string]string - As variáveis classificadas como segredo, em BeforeApply e AfterLoad
+*/
+type HookContext struct {
+	Stage   HookStage
+	Env     string
+	Files   []string
+	Values  map[string]string
+	Secrets map[string]string
+}
+
+// Hook inspeciona ou transforma o estado de um carregamento; um erro interrompe o carregamento.
+type Hook func(ctx *HookContext) error
+
+/*
+WithHook registra uma função chamada em um momento do carregamento
+
+Os hooks formam uma cadeia: os de um mesmo momento são chamados na ordem em que foram registrados, e cada um
+recebe as alterações do anterior. Com eles, a aplicação decifra valores em um formato próprio, normaliza ou
+filtra variáveis e audita o carregamento sem modificar o carregador. As variáveis adicionadas por um hook
+têm SourceHook como origem. Os hooks de BeforeLoad, AfterParse e BeforeApply também são chamados por Plan e
+Diagnose, que não aplicam nada; um hook de auditoria deve usar AfterLoad. Um erro de AfterLoad é retornado por
+LoadEnv e Reload, mas as variáveis já foram aplicadas.
+
+@param stage HookStage - O momento do carregamento
+@param hook Hook - A função chamada
+
+@return Option - A opção que registra o hook
+*/
+func WithHook(stage HookStage, hook Hook) Option {
+	return func(f *FileEnvLoader) {
+		if f.hooks == nil {
+			f.hooks = make(map[HookStage][]Hook)
+		}
+		f.hooks[stage] = append(f.hooks[stage], hook)
+	}
+}
+
+/*
+runHooks chama os hooks de um momento, em ordem
+
+@param ctx *HookContext - O estado entregue aos hooks
+
+@return error - O erro do primeiro hook que falhar, identificado pelo momento
+*/
+func (f *FileEnvLoader) runHooks(ctx *HookContext) error {
+	for i, hook := range f.hooks[ctx.Stage] {
+		if err := callHook(hook, ctx); err != nil {
+			err = fmt.Errorf("hook %s #%d: %w", ctx.Stage, i+1, err)
+			if f.logs(LogError) {
+				logger.Error(fmt.Sprintf("Erro ao carregar variáveis de ambiente: %s", err.Error()))
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+hookValues chama os hooks de AfterParse ou BeforeApply e atualiza as origens das variáveis alteradas
+
+@param stage HookStage - AfterParse ou BeforeApply
+@param env string - O ambiente carregado
+@param files []string - Os arquivos carregados
+@param values map[string]string - As variáveis, alteradas no lugar
+@param secrets map[string]string - Os segredos, em BeforeApply, ou nil
+@param origins map[string]origin - A declaração de cada variável, atualizada com as variáveis adicionadas e removidas
+
+@return map[string]string - Os segredos após os hooks
+@return error - O erro do primeiro hook que falhar
+*/
+func (f *FileEnvLoader) hookValues(stage HookStage, env string, files []string, values map[string]string, secrets map[string]string, origins map[string]origin) (map[string]string, error) {
+	if len(f.hooks[stage]) == 0 {
+		return secrets, nil
+	}
+
+	ctx := &HookContext{Stage: stage, Env: env, Files: files, Values: values, Secrets: secrets}
+	if err := f.runHooks(ctx); err != nil {
+		return nil, err
+	}
+	if ctx.Values == nil {
+		ctx.Values = map[string]string{}
+	}
+	for key := range values {
+		if _, ok := ctx.Values[key]; !ok {
+			delete(values, key)
+		}
+	}
+	for key, value := range ctx.Values {
+		values[key] = value
+	}
+
+	for key := range origins {
+		_, inValues := values[key]
+		_, inSecrets := ctx.Secrets[key]
+		if !inValues && !inSecrets {
+			delete(origins, key)
+		}
+	}
+	for key := range values {
+		if _, ok := origins[key]; !ok {
+			origins[key] = origin{file: SourceHook, rank: len(origins)}
+		}
+	}
+
+	return ctx.Secrets, nil
+}
+
+/*
+afterLoad chama os hooks de AfterLoad com uma cópia do estado aplicado
+
+@param files []string - Os arquivos carregados
+
+@return error - O erro do primeiro hook que falhar
+*/
+func (f *FileEnvLoader) afterLoad(files []string) error {
+	if len(f.hooks[AfterLoad]) == 0 {
+		return nil
+	}
+
+	return f.runHooks(&HookContext{
+		Stage:   AfterLoad,
+		Env:     f.Env,
+		Files:   append([]string(nil), files...),
+		Values:  f.All(),
+		Secrets: copyMap(f.secrets),
+	})
+}
+
+/*
+callHook chama um hook, convertendo um pânico em um *PanicError
+
+@param hook Hook - O hook
+@param ctx *HookContext - O contexto do estágio
+
+@return error - O erro do hook, ou o *PanicError
+*/
+func callHook(hook Hook, ctx *HookContext) (err error) {
+	defer recoverPanic("hook", &err)
+
+	return hook(ctx)
+}
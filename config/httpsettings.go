@@ -0,0 +1,132 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+/*
+HTTPSettings são as configurações convencionais de um servidor HTTP
+
+Port int - A porta em que o servidor escuta
+ReadTimeout time.Duration - O tempo máximo de leitura da requisição, incluindo o corpo
+ReadHeaderTimeout time.Duration - O tempo máximo de leitura dos cabeçalhos
+WriteTimeout time.Duration - O tempo máximo de escrita da resposta
+IdleTimeout time.Duration - O tempo máximo de uma conexão keep-alive ociosa
+MaxHeaderBytes int - O tamanho máximo dos cabeçalhos da requisição
+ShutdownGrace time.Duration - O tempo dado às requisições em andamento no encerramento
+*/
+type HTTPSettings struct {
+	Port              int
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	ShutdownGrace     time.Duration
+}
+
+/*
+GetHTTPSettings lê as configurações convencionais de um servidor HTTP
+
+As variáveis lidas, com os seus padrões, são HTTP_PORT (8080), READ_TIMEOUT (15s), READ_HEADER_TIMEOUT (5s),
+WRITE_TIMEOUT (15s), IDLE_TIMEOUT (60s), MAX_HEADER_BYTES (1 MiB) e SHUTDOWN_GRACE (10s). Com um prefixo, os
+nomes passam a ser <prefixo>_HTTP_PORT, <prefixo>_READ_TIMEOUT e assim por diante, para servidores adicionais
+(ex.: ADMIN). Todos os problemas encontrados são reunidos em um único *ValidationError.
+
+@param r Reader - A configuração
+@param prefix string - O prefixo das variáveis, ou uma string vazia
+
+@return HTTPSettings - As configurações
+@return error - Um *ValidationError se alguma variável for inválida
+*/
+func GetHTTPSettings(r Reader, prefix string) (HTTPSettings, error) {
+	s := HTTPSettings{
+		Port:              8080,
+		ReadTimeout:       15 * time.Second,
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      15 * time.Second,
+		IdleTimeout:       60 * time.Second,
+		MaxHeaderBytes:    http.DefaultMaxHeaderBytes,
+		ShutdownGrace:     10 * time.Second,
+	}
+
+	c := companions{r: r, prefix: prefix}
+	c.int("HTTP_PORT", &s.Port)
+	c.duration("READ_TIMEOUT", &s.ReadTimeout)
+	c.duration("READ_HEADER_TIMEOUT", &s.ReadHeaderTimeout)
+	c.duration("WRITE_TIMEOUT", &s.WriteTimeout)
+	c.duration("IDLE_TIMEOUT", &s.IdleTimeout)
+	c.int("MAX_HEADER_BYTES", &s.MaxHeaderBytes)
+	c.duration("SHUTDOWN_GRACE", &s.ShutdownGrace)
+
+	if s.Port < 1 || s.Port > 65535 {
+		c.problems = append(c.problems, fmt.Sprintf("variável %s: porta %d fora do intervalo 1-65535", c.key("HTTP_PORT"), s.Port))
+	}
+	durations := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"READ_TIMEOUT", s.ReadTimeout},
+		{"READ_HEADER_TIMEOUT", s.ReadHeaderTimeout},
+		{"WRITE_TIMEOUT", s.WriteTimeout},
+		{"IDLE_TIMEOUT", s.IdleTimeout},
+		{"SHUTDOWN_GRACE", s.ShutdownGrace},
+	}
+	for _, d := range durations {
+		if d.value < 0 {
+			c.problems = append(c.problems, fmt.Sprintf("variável %s não pode ser negativa", c.key(d.name)))
+		}
+	}
+	if s.ReadHeaderTimeout > s.ReadTimeout && s.ReadTimeout > 0 {
+		c.problems = append(c.problems, fmt.Sprintf("variável %s maior que %s", c.key("READ_HEADER_TIMEOUT"), c.key("READ_TIMEOUT")))
+	}
+	if s.MaxHeaderBytes <= 0 {
+		c.problems = append(c.problems, fmt.Sprintf("variável %s deve ser positiva", c.key("MAX_HEADER_BYTES")))
+	}
+
+	return s, c.err()
+}
+
+/*
+Addr retorna o endereço de escuta do servidor, em todas as interfaces
+
+@return string - O endereço no formato :porta
+*/
+func (s HTTPSettings) Addr() string {
+	return net.JoinHostPort("", strconv.Itoa(s.Port))
+}
+
+/*
+ApplyTo aplica as configurações a um http.Server, incluindo o endereço quando ele não foi definido
+
+@param srv *http.Server - O servidor
+*/
+func (s HTTPSettings) ApplyTo(srv *http.Server) {
+	if srv.Addr == "" {
+		srv.Addr = s.Addr()
+	}
+	srv.ReadTimeout = s.ReadTimeout
+	srv.ReadHeaderTimeout = s.ReadHeaderTimeout
+	srv.WriteTimeout = s.WriteTimeout
+	srv.IdleTimeout = s.IdleTimeout
+	srv.MaxHeaderBytes = s.MaxHeaderBytes
+}
+
+/*
+Shutdown encerra o servidor, aguardando as requisições em andamento por até ShutdownGrace
+
+@param srv *http.Server - O servidor
+
+@return error - O erro de http.Server.Shutdown, como context.DeadlineExceeded se o prazo se esgotar
+*/
+func (s HTTPSettings) Shutdown(srv *http.Server) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.ShutdownGrace)
+	defer cancel()
+
+	return srv.Shutdown(ctx)
+}
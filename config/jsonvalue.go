@@ -0,0 +1,412 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+jsonRule é uma variável com um valor JSON validado durante o carregamento, declarada com WithJSONSchema
+
+key string - O nome da variável
+schema []byte - O JSON Schema do valor, ou nil para verificar apenas a sintaxe
+*/
+type jsonRule struct {
+	key    string
+	schema []byte
+}
+
+/*
+WithJSONSchema declara uma variável com um valor JSON, validado durante o carregamento por um JSON Schema
+
+Se a variável estiver definida, LoadEnv verifica se o valor é um JSON válido e se atende ao esquema, reunindo
+todos os problemas no *ValidationError do carregamento, com o caminho de cada um no valor ($.plans[0].price).
+GetJSON aplica o mesmo esquema na leitura. Com schema nil, apenas a sintaxe é verificada.
+
+É implementado o subconjunto do JSON Schema usado para descrever pequenos valores de configuração: type, enum,
+const, properties, required, additionalProperties, items, minItems, maxItems, minLength, maxLength, pattern,
+minimum, maximum, exclusiveMinimum, exclusiveMaximum, allOf, anyOf e oneOf. As demais palavras-chave, como
+$ref e format, são ignoradas.
+
+@param key string - O nome da variável (ex.: FEATURES_JSON)
+@param schema []byte - O JSON Schema, ou nil
+
+@return Option - A opção que declara a variável
+*/
+func WithJSONSchema(key string, schema []byte) Option {
+	return func(f *FileEnvLoader) {
+		f.jsonRules = append(f.jsonRules, jsonRule{key: key, schema: schema})
+	}
+}
+
+/*
+GetJSON converte o valor JSON de uma variável em target, com json.Unmarshal
+
+Se a variável tiver sido declarada com WithJSONSchema, o valor é validado pelo esquema antes da conversão.
+
+@param key string - O nome da variável
+@param target any - Um ponteiro para o destino, como uma struct ou um mapa
+
+@return error - ErrKeyNotFound se a variável não existir, ou um erro com o arquivo e a linha se o valor for
+inválido ou não puder ser convertido
+*/
+func (f *FileEnvLoader) GetJSON(key string, target any) error {
+	value, err := f.require(key)
+	if err != nil {
+		return err
+	}
+
+	if problems := f.checkJSON(key, value); len(problems) > 0 {
+		return fmt.Errorf("variável %s%s: %s", key, f.location(key), strings.Join(problems, "; "))
+	}
+	if err := json.Unmarshal([]byte(value), target); err != nil {
+		return fmt.Errorf("variável %s%s: %w", key, f.location(key), err)
+	}
+
+	return nil
+}
+
+/*
+checkJSON valida o valor JSON de uma variável pelos esquemas declarados para ela
+
+@param key string - O nome da variável
+@param value string - O valor
+
+@return []string - Os problemas encontrados
+*/
+func (f *FileEnvLoader) checkJSON(key string, value string) []string {
+	var decoded any
+	if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+		return []string{"JSON inválido: " + err.Error()}
+	}
+
+	var problems []string
+	for _, rule := range f.jsonRules {
+		if rule.key != key || rule.schema == nil {
+			continue
+		}
+		var schema any
+		if err := json.Unmarshal(rule.schema, &schema); err != nil {
+			problems = append(problems, "esquema JSON inválido: "+err.Error())
+			continue
+		}
+		problems = append(problems, validateJSONSchema(schema, decoded, "$")...)
+	}
+
+	return problems
+}
+
+/*
+validateJSONSchema valida um valor decodificado por um JSON Schema
+
+@param schema any - O esquema decodificado: um objeto ou um booleano
+@param value any - O valor decodificado
+@param path string - O caminho do valor, usado nas mensagens
+
+@return []string - Os problemas encontrados, com o caminho de cada um
+*/
+func validateJSONSchema(schema any, value any, path string) []string {
+	switch s := schema.(type) {
+	case bool:
+		if !s {
+			return []string{path + ": nenhum valor é aceito"}
+		}
+		return nil
+	case map[string]any:
+		return validateJSONObjectSchema(s, value, path)
+	}
+
+	return []string{path + ": o esquema deve ser um objeto ou um booleano"}
+}
+
+/*
+validateJSONObjectSchema valida um valor decodificado por um esquema em forma de objeto
+
+@param s map[string]any - O esquema
+@param value any - O valor
+@param path string - O caminho do valor
+
+@return []string - Os problemas encontrados
+*/
+func validateJSONObjectSchema(s map[string]any, value any, path string) []string {
+	if typ, ok := s["type"]; ok && !matchesJSONType(typ, value) {
+		return []string{fmt.Sprintf("%s: esperado o tipo %s, obtido %s", path, describeJSONType(typ), jsonType(value))}
+	}
+
+	var problems []string
+	if enum, ok := s["enum"].([]any); ok {
+		found := false
+		for _, candidate := range enum {
+			found = found || reflect.DeepEqual(candidate, value)
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("%s: o valor não está entre os permitidos", path))
+		}
+	}
+	if constant, ok := s["const"]; ok && !reflect.DeepEqual(constant, value) {
+		problems = append(problems, fmt.Sprintf("%s: o valor deve ser %s", path, jsonText(constant)))
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := float64(utf8.RuneCountInString(v))
+		if min, ok := schemaNumber(s, "minLength"); ok && length < min {
+			problems = append(problems, fmt.Sprintf("%s: o texto deve ter ao menos %v caracteres", path, min))
+		}
+		if max, ok := schemaNumber(s, "maxLength"); ok && length > max {
+			problems = append(problems, fmt.Sprintf("%s: o texto deve ter no máximo %v caracteres", path, max))
+		}
+		if pattern, ok := s["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			switch {
+			case err != nil:
+				problems = append(problems, fmt.Sprintf("%s: padrão %q inválido no esquema", path, pattern))
+			case !re.MatchString(v):
+				problems = append(problems, fmt.Sprintf("%s: o texto não corresponde ao padrão %q", path, pattern))
+			}
+		}
+	case float64:
+		if min, ok := schemaNumber(s, "minimum"); ok && v < min {
+			problems = append(problems, fmt.Sprintf("%s: o valor deve ser maior ou igual a %v", path, min))
+		}
+		if max, ok := schemaNumber(s, "maximum"); ok && v > max {
+			problems = append(problems, fmt.Sprintf("%s: o valor deve ser menor ou igual a %v", path, max))
+		}
+		if min, ok := schemaNumber(s, "exclusiveMinimum"); ok && v <= min {
+			problems = append(problems, fmt.Sprintf("%s: o valor deve ser maior que %v", path, min))
+		}
+		if max, ok := schemaNumber(s, "exclusiveMaximum"); ok && v >= max {
+			problems = append(problems, fmt.Sprintf("%s: o valor deve ser menor que %v", path, max))
+		}
+	case []any:
+		if min, ok := schemaNumber(s, "minItems"); ok && float64(len(v)) < min {
+			problems = append(problems, fmt.Sprintf("%s: a lista deve ter ao menos %v itens", path, min))
+		}
+		if max, ok := schemaNumber(s, "maxItems"); ok && float64(len(v)) > max {
+			problems = append(problems, fmt.Sprintf("%s: a lista deve ter no máximo %v itens", path, max))
+		}
+		if items, ok := s["items"]; ok {
+			for i, item := range v {
+				problems = append(problems, validateJSONSchema(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case map[string]any:
+		problems = append(problems, validateJSONProperties(s, v, path)...)
+	}
+
+	if all, ok := s["allOf"].([]any); ok {
+		for _, sub := range all {
+			problems = append(problems, validateJSONSchema(sub, value, path)...)
+		}
+	}
+	if anyOf, ok := s["anyOf"].([]any); ok && countJSONMatches(anyOf, value, path) == 0 {
+		problems = append(problems, fmt.Sprintf("%s: o valor não atende a nenhum dos esquemas de anyOf", path))
+	}
+	if oneOf, ok := s["oneOf"].([]any); ok {
+		if n := countJSONMatches(oneOf, value, path); n != 1 {
+			problems = append(problems, fmt.Sprintf("%s: o valor deve atender a exatamente um dos esquemas de oneOf, atende a %d", path, n))
+		}
+	}
+
+	return problems
+}
+
+/*
+validateJSONProperties valida as propriedades de um objeto por required, properties e additionalProperties
+
+@param s map[string]any - O esquema
+@param object map[string]any - O objeto
+@param path string - O caminho do objeto
+
+@return []string - Os problemas encontrados, em ordem alfabética das propriedades
+*/
+func validateJSONProperties(s map[string]any, object map[string]any, path string) []string {
+	var problems []string
+	if required, ok := s["required"].([]any); ok {
+		for _, name := range required {
+			if key, ok := name.(string); ok {
+				if _, exists := object[key]; !exists {
+					problems = append(problems, fmt.Sprintf("%s: propriedade obrigatória %q ausente", path, key))
+				}
+			}
+		}
+	}
+
+	properties, _ := s["properties"].(map[string]any)
+	additional, restricted := s["additionalProperties"]
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		child := path + "." + key
+		if sub, ok := properties[key]; ok {
+			problems = append(problems, validateJSONSchema(sub, object[key], child)...)
+			continue
+		}
+		if !restricted {
+			continue
+		}
+		if allowed, ok := additional.(bool); ok && !allowed {
+			problems = append(problems, fmt.Sprintf("%s: propriedade não permitida", child))
+			continue
+		}
+		problems = append(problems, validateJSONSchema(additional, object[key], child)...)
+	}
+
+	return problems
+}
+
+/*
+countJSONMatches conta os esquemas de anyOf ou oneOf atendidos por um valor
+
+@param schemas []any - Os esquemas
+@param value any - O valor
+@param path string - O caminho do valor
+
+@return int - A quantidade de esquemas atendidos
+*/
+func countJSONMatches(schemas []any, value any, path string) int {
+	n := 0
+	for _, sub := range schemas {
+		if len(validateJSONSchema(sub, value, path)) == 0 {
+			n++
+		}
+	}
+
+	return n
+}
+
+/*
+matchesJSONType verifica a palavra-chave type, com um tipo ou uma lista de tipos
+
+@param typ any - O valor de type no esquema
+@param value any - O valor
+
+@return bool - Se o valor é de algum dos tipos
+*/
+func matchesJSONType(typ any, value any) bool {
+	names, ok := typ.([]any)
+	if !ok {
+		names = []any{typ}
+	}
+
+	actual := jsonType(value)
+	for _, name := range names {
+		switch {
+		case name == actual:
+			return true
+		case name == "number" && actual == "integer":
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+jsonType retorna o tipo JSON de um valor decodificado, com integer para os números sem parte fracionária
+
+@param value any - O valor
+
+@return string - object, array, string, integer, number, boolean ou null
+*/
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case bool:
+		return "boolean"
+	}
+
+	return "null"
+}
+
+// describeJSONType descreve o valor de type de um esquema para as mensagens (ex.: string ou integer).
+func describeJSONType(typ any) string {
+	names, ok := typ.([]any)
+	if !ok {
+		return fmt.Sprint(typ)
+	}
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprint(name)
+	}
+
+	return strings.Join(parts, " ou ")
+}
+
+// jsonText codifica um valor decodificado de volta em JSON, para as mensagens.
+func jsonText(value any) string {
+	b, _ := json.Marshal(value)
+	return string(b)
+}
+
+/*
+schemaNumber lê uma palavra-chave numérica de um esquema
+
+@param s map[string]any - O esquema
+@param name string - A palavra-chave
+
+@return float64 - O valor
+@return bool - Se a palavra-chave está presente e é um número
+*/
+func schemaNumber(s map[string]any, name string) (float64, bool) {
+	n, ok := s[name].(float64)
+	return n, ok
+}
+
+/*
+validateJSONValues verifica as variáveis declaradas com WithJSONSchema
+
+@param values map[string]string - As variáveis a serem aplicadas
+@param secrets map[string]string - As variáveis classificadas como segredo
+
+@return []string - Os problemas encontrados
+*/
+func (f *FileEnvLoader) validateJSONValues(values map[string]string, secrets map[string]string) []string {
+	var problems []string
+	seen := make(map[string]bool, len(f.jsonRules))
+	for _, rule := range f.jsonRules {
+		if seen[rule.key] {
+			continue
+		}
+		seen[rule.key] = true
+
+		value, ok := values[rule.key]
+		if !ok {
+			value, ok = secrets[rule.key]
+		}
+		if !ok && !f.noProcessEnv {
+			value, ok = os.LookupEnv(rule.key)
+		}
+		if !ok {
+			continue
+		}
+
+		for _, problem := range f.checkJSON(rule.key, value) {
+			problems = append(problems, fmt.Sprintf("variável %s: %s", rule.key, problem))
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,28 @@
+package config
+
+/*
+WithKeyAliases define nomes alternativos para as variáveis lidas por Lookup, pelos getters e por GetSecret
+
+Cada chave do mapa é um nome lógico e os valores são os nomes físicos tentados, em ordem de prioridade, quando
+nenhuma variável com o nome lógico existe. Por exemplo, com
+
+	WithKeyAliases(map[string][]string{"db.url": {"DB_URL", "DATABASE_URL", "POSTGRES_URL"}})
+
+GetString("db.url") retorna DB_URL, ou DATABASE_URL na falta dela, e assim por diante. Isso facilita a migração
+entre convenções de nomes: o código passa a usar um único nome enquanto os ambientes ainda usam os antigos.
+As entradas informadas substituem as existentes para o mesmo nome lógico.
+
+@param aliases map[string][]string - Os nomes físicos de cada nome lógico
+
+@return Option - A opção que define os nomes alternativos
+*/
+func WithKeyAliases(aliases map[string][]string) Option {
+	return func(f *FileEnvLoader) {
+		if f.keyAliases == nil {
+			f.keyAliases = make(map[string][]string, len(aliases))
+		}
+		for key, names := range aliases {
+			f.keyAliases[key] = append([]string(nil), names...)
+		}
+	}
+}
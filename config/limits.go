@@ -0,0 +1,108 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrLimitExceeded é o erro retornado quando um arquivo .env excede um dos limites de WithLimits.
+var ErrLimitExceeded = errors.New("limite de configuração excedido")
+
+/*
+Limits define os limites de tamanho aplicados aos arquivos .env carregados
+
+Os limites protegem o serviço de carregar por engano um arquivo de vários gigabytes ou de um valor que cresce
+exponencialmente com referências ${VAR} encadeadas (A=${X}${X}, B=${A}${A}, ...). O limite de valor é verificado
+durante a expansão, antes de o valor ser montado por inteiro. Um campo igual a zero desativa o limite
+correspondente. O tamanho de cada linha continua limitado por MaxLineSize. Não há limite de profundidade: os
+arquivos .env não têm diretivas de inclusão, e a expansão usa apenas os valores já expandidos das variáveis
+declaradas antes, sem recursão.
+
+MaxFileSize int64 - O tamanho máximo de cada arquivo, em bytes
+MaxKeys int - A quantidade máxima de variáveis de cada arquivo e do resultado de todas as camadas
+MaxValueLength int - O tamanho máximo de um valor, em bytes, depois da expansão das referências
+*/
+type Limits struct {
+	MaxFileSize    int64
+	MaxKeys        int
+	MaxValueLength int
+}
+
+// DefaultLimits são os limites usados pelos carregadores sem WithLimits.
+var DefaultLimits = Limits{
+	MaxFileSize:    64 << 20,
+	MaxKeys:        100000,
+	MaxValueLength: 4 << 20,
+}
+
+/*
+WithLimits substitui os limites de tamanho dos arquivos .env (veja Limits e DefaultLimits)
+
+Quando um limite é excedido, o carregamento falha com um erro que satisfaz errors.Is(err, ErrLimitExceeded) e
+informa o arquivo, a linha e o limite. Para desativar todos os limites, use WithLimits(Limits{}).
+
+@param limits Limits - Os limites
+
+@return Option - A opção que define os limites
+*/
+func WithLimits(limits Limits) Option {
+	return func(f *FileEnvLoader) {
+		f.limits = limits
+	}
+}
+
+/*
+limitedReader limita a leitura de um arquivo a MaxFileSize, retornando um erro em vez de truncar o conteúdo
+
+r io.Reader - O conteúdo do arquivo
+file string - O caminho do arquivo, usado na mensagem de erro
+remaining int64 - Os bytes que ainda podem ser lidos
+max int64 - O tamanho máximo do arquivo
+*/
+type limitedReader struct {
+	r         io.Reader
+	file      string
+	remaining int64
+	max       int64
+}
+
+// Read lê do conteúdo e retorna um erro assim que o arquivo ultrapassa o tamanho máximo.
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		var probe [1]byte
+		if n, _ := l.r.Read(probe[:]); n > 0 {
+			return 0, fileSizeError(l.file, l.max)
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
+
+/*
+limitReader aplica MaxFileSize à leitura de um arquivo
+
+@param r io.Reader - O conteúdo do arquivo
+@param file string - O caminho do arquivo
+@param max int64 - O tamanho máximo, ou zero para não limitar
+
+@return io.Reader - O leitor limitado
+*/
+func limitReader(r io.Reader, file string, max int64) io.Reader {
+	if max <= 0 {
+		return r
+	}
+
+	return &limitedReader{r: r, file: file, remaining: max, max: max}
+}
+
+// fileSizeError cria o erro de um arquivo maior que MaxFileSize.
+func fileSizeError(file string, max int64) error {
+	return fmt.Errorf("%w: o arquivo %s excede o tamanho máximo de %d bytes", ErrLimitExceeded, file, max)
+}
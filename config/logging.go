@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// logLevelEnvVar é a variável de ambiente que define o nível de log padrão dos carregadores.
+const logLevelEnvVar = "LOCENV_LOG_LEVEL"
+
+// LogLevel define quais mensagens o carregador registra no log.
+type LogLevel int
+
+const (
+	// LogInfo registra todas as mensagens, inclusive o rastreamento e o resumo de inicialização, quando habilitados.
+	LogInfo LogLevel = iota
+	// LogWarning registra apenas os avisos e os erros.
+	LogWarning
+	// LogError registra apenas os erros.
+	LogError
+	// LogSilent não registra nenhuma mensagem; os erros continuam sendo retornados e os avisos, incluídos em Result.Warnings.
+	LogSilent
+)
+
+// String retorna o nome do nível, no formato aceito por LOCENV_LOG_LEVEL.
+func (l LogLevel) String() string {
+	switch l {
+	case LogWarning:
+		return "warning"
+	case LogError:
+		return "error"
+	case LogSilent:
+		return "silent"
+	default:
+		return "info"
+	}
+}
+
+/*
+WithLogLevel define o nível mínimo das mensagens que o carregador registra no log
+
+Sem esta opção, o nível vem de LOCENV_LOG_LEVEL (info, warning, error ou silent), e o padrão é info. Bibliotecas
+e ferramentas de linha de comando que relatam os erros por conta própria podem usar LogSilent, e testes podem
+definir LOCENV_LOG_LEVEL=silent para manter a saída limpa.
+
+@param level LogLevel - O nível mínimo
+
+@return Option - A opção que define o nível
+*/
+func WithLogLevel(level LogLevel) Option {
+	return func(f *FileEnvLoader) {
+		f.logLevel = level
+	}
+}
+
+/*
+WithSilent impede que o carregador registre qualquer mensagem no log, como WithLogLevel(LogSilent)
+
+@return Option - A opção que silencia o carregador
+*/
+func WithSilent() Option {
+	return WithLogLevel(LogSilent)
+}
+
+/*
+defaultLogLevel lê o nível de log padrão de LOCENV_LOG_LEVEL
+
+@return LogLevel - O nível definido na variável, ou LogInfo se ela estiver vazia ou for inválida
+*/
+func defaultLogLevel() LogLevel {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(logLevelEnvVar))) {
+	case "warning", "warn":
+		return LogWarning
+	case "error":
+		return LogError
+	case "silent", "off", "none":
+		return LogSilent
+	default:
+		return LogInfo
+	}
+}
+
+/*
+logs informa se o carregador registra as mensagens do nível informado
+
+As chamadas ao logger ficam no ponto de origem, protegidas por logs, para que o log continue indicando o
+arquivo e a linha de quem o emitiu.
+
+@param level LogLevel - O nível da mensagem (LogInfo, LogWarning ou LogError)
+
+@return bool - Se a mensagem deve ser registrada
+*/
+func (f *FileEnvLoader) logs(level LogLevel) bool {
+	return f.logLevel <= level
+}
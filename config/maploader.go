@@ -0,0 +1,124 @@
+package config
+
+// SourceMemory é a origem informada por Source para as variáveis de um MapLoader e de WithValues.
+const SourceMemory = "memory"
+
+/*
+MapLoader é um IEnvLoader em memória, próprio para testes unitários
+
+O MapLoader lê as variáveis de um mapa, sem acessar o sistema de arquivos nem o ambiente do processo,
+para que código que recebe um IEnvLoader ou um Reader possa ser testado sem arquivos .env de fixture.
+Os métodos de carregamento não fazem nada além de relatar o conteúdo do mapa.
+*/
+type MapLoader struct {
+	*FileEnvLoader
+}
+
+/*
+NewMapLoader cria um MapLoader com o ambiente e as variáveis informadas
+
+O mapa recebido é copiado, de modo que alterações posteriores nele não afetam o carregador.
+
+@param env string - O ambiente retornado por GetEnv
+@param values map[string]string - As variáveis iniciais
+
+@return *MapLoader - O carregador em memória
+*/
+func NewMapLoader(env string, values map[string]string) *MapLoader {
+	f := NewEnvLoader().(*FileEnvLoader)
+	f.Env = normalizeEnv(env)
+	f.envPinned = true
+	f.noProcessEnv = true
+	f.values = make(map[string]string, len(values))
+	f.sources = make(map[string]string, len(values))
+	f.order = make(map[string]int, len(values))
+
+	m := &MapLoader{FileEnvLoader: f}
+	for _, key := range sortedKeys(values) {
+		m.Set(key, values[key])
+	}
+	f.health.record(nil)
+
+	return m
+}
+
+/*
+Set define uma variável no MapLoader
+
+Em Keys, as variáveis aparecem na ordem em que foram definidas pela primeira vez, mesmo depois de Unset; as do
+mapa de NewMapLoader vêm antes, em ordem alfabética.
+
+@param key string - O nome da variável
+@param value string - O valor da variável
+*/
+func (m *MapLoader) Set(key string, value string) {
+	if _, ok := m.order[key]; !ok {
+		m.order[key] = len(m.order)
+	}
+	m.values[key] = value
+	m.sources[key] = SourceMemory
+	m.revision = configRevision(m.revisionKey, m.Env, m.values, m.secrets)
+}
+
+/*
+SetSecret define um segredo no MapLoader, disponível apenas via GetSecret
+
+@param key string - O nome do segredo
+@param value string - O valor do segredo
+*/
+func (m *MapLoader) SetSecret(key string, value string) {
+	m.secrets[key] = value
+	m.revision = configRevision(m.revisionKey, m.Env, m.values, m.secrets)
+}
+
+/*
+Unset remove uma variável ou um segredo do MapLoader
+
+@param key string - O nome da variável
+*/
+func (m *MapLoader) Unset(key string) {
+	delete(m.values, key)
+	delete(m.sources, key)
+	delete(m.secrets, key)
+	m.revision = configRevision(m.revisionKey, m.Env, m.values, m.secrets)
+}
+
+// LoadEnv não faz nada, pois as variáveis do MapLoader já estão carregadas.
+func (m *MapLoader) LoadEnv() error {
+	return nil
+}
+
+/*
+LoadEnvResult retorna um resumo com o conteúdo atual do MapLoader
+
+@return *Result - O resumo do carregamento
+@return error - Sempre nil
+*/
+func (m *MapLoader) LoadEnvResult() (*Result, error) {
+	return &Result{
+		Files:   []string{},
+		Env:     m.Env,
+		Loaded:  len(m.values),
+		Secrets: len(m.secrets),
+	}, nil
+}
+
+// Reload não faz nada, pois as variáveis do MapLoader são alteradas apenas por Set, SetSecret e Unset.
+func (m *MapLoader) Reload() error {
+	return nil
+}
+
+/*
+Plan retorna um plano em que todas as variáveis do MapLoader seriam definidas
+
+@return *Plan - O plano com as variáveis do MapLoader
+@return error - Sempre nil
+*/
+func (m *MapLoader) Plan() (*Plan, error) {
+	plan := &Plan{Env: m.Env}
+	for _, key := range sortedKeys(m.values) {
+		plan.Changes = append(plan.Changes, PlannedChange{Key: key, Action: ActionSet, Value: m.values[key]})
+	}
+
+	return plan, nil
+}
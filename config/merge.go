@@ -0,0 +1,155 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// mergeAnnotation é o comentário que define a estratégia de combinação de uma declaração.
+const mergeAnnotation = "merge:"
+
+// MergeStrategy define como uma declaração se combina com o valor de uma declaração anterior da mesma variável.
+type MergeStrategy int
+
+const (
+	// MergeReplace substitui o valor anterior, o comportamento padrão.
+	MergeReplace MergeStrategy = iota
+	// MergeAppend acrescenta o valor ao final do anterior, com o separador.
+	MergeAppend
+	// MergePrepend acrescenta o valor ao início do anterior, com o separador.
+	MergePrepend
+)
+
+// String retorna o nome da estratégia, como usado na anotação "# merge:".
+func (s MergeStrategy) String() string {
+	switch s {
+	case MergeAppend:
+		return "append"
+	case MergePrepend:
+		return "prepend"
+	default:
+		return "replace"
+	}
+}
+
+/*
+mergeRule é a estratégia de combinação de um conjunto de variáveis, definida por WithMergeStrategy
+
+patterns []string - Os padrões de nomes das variáveis (sintaxe de path.Match)
+strategy MergeStrategy - A estratégia
+separator string - O separador entre os valores
+*/
+type mergeRule struct {
+	patterns  []string
+	strategy  MergeStrategy
+	separator string
+}
+
+/*
+WithMergeStrategy define como as declarações de variáveis em camadas se combinam
+
+Por padrão, a última declaração de uma variável substitui as anteriores. Com MergeAppend ou MergePrepend, uma
+sobreposição ou um fragmento de WithEnvDir estende o valor da camada anterior em vez de descartá-lo, o que é
+útil para PATH, LD_LIBRARY_PATH e listas de funcionalidades:
+
+	WithMergeStrategy(MergeAppend, "", "PATH", "LD_LIBRARY_PATH")
+	WithMergeStrategy(MergeAppend, ",", "FEATURES")
+
+A estratégia também pode ser definida em cada declaração, com um comentário imediatamente acima dela, que
+prevalece sobre a opção; o separador é opcional:
+
+	# merge: prepend
+	PATH=/opt/tools/bin
+
+O separador vazio equivale ao separador de listas de caminhos do sistema (":" ou ";"). A primeira declaração
+de uma variável é usada como está; para estender o valor do processo, use uma referência, como
+PATH=/opt/bin:${PATH}. As combinações não são conflitos em WithStrictCascade.
+
+@param strategy MergeStrategy - A estratégia
+@param separator string - O separador entre os valores, ou vazio para o separador de caminhos
+@param keys ...string - Os padrões de nomes das variáveis (sintaxe de path.Match)
+
+@return Option - A opção que define a estratégia
+*/
+func WithMergeStrategy(strategy MergeStrategy, separator string, keys ...string) Option {
+	return func(f *FileEnvLoader) {
+		f.mergeRules = append(f.mergeRules, mergeRule{patterns: keys, strategy: strategy, separator: separator})
+	}
+}
+
+/*
+mergeStrategy retorna a estratégia de combinação de uma declaração
+
+A anotação "# merge:" da declaração prevalece; sem ela, vale a última regra de WithMergeStrategy que
+corresponde ao nome da variável.
+
+@param e entry - A declaração
+@param file string - O arquivo da declaração
+
+@return MergeStrategy - A estratégia
+@return string - O separador, já resolvido
+@return *Warning - Um aviso se a anotação for inválida, ou nil
+*/
+func (f *FileEnvLoader) mergeStrategy(e entry, file string) (MergeStrategy, string, *Warning) {
+	strategy, separator := MergeReplace, ""
+	for _, rule := range f.mergeRules {
+		if matchesAny(rule.patterns, e.key) {
+			strategy, separator = rule.strategy, rule.separator
+		}
+	}
+
+	var warning *Warning
+	if raw, ok := commentAnnotation(e.comment, mergeAnnotation); ok {
+		name, sep, _ := strings.Cut(raw, " ")
+		switch name {
+		case "append":
+			strategy, separator = MergeAppend, strings.TrimSpace(sep)
+		case "prepend":
+			strategy, separator = MergePrepend, strings.TrimSpace(sep)
+		case "replace":
+			strategy, separator = MergeReplace, ""
+		default:
+			w := warningAt(WarningAnnotation, e, file, "estratégia de combinação %q inválida para %s, esperado append, prepend ou replace", raw, e.key)
+			warning = &w
+		}
+	}
+	if separator == "" {
+		separator = string(os.PathListSeparator)
+	}
+
+	return strategy, separator, warning
+}
+
+/*
+mergeValue combina uma declaração com o valor anterior da variável, conforme a sua estratégia
+
+@param e entry - A declaração
+@param file string - O arquivo da declaração
+@param values map[string]string - As variáveis das declarações anteriores
+
+@return string - O valor combinado, ou o valor da declaração
+@return bool - Se o valor foi combinado com o anterior
+@return *Warning - Um aviso se a anotação for inválida, ou nil
+*/
+func (f *FileEnvLoader) mergeValue(e entry, file string, values map[string]string) (string, bool, *Warning) {
+	if len(f.mergeRules) == 0 && !strings.Contains(e.comment, mergeAnnotation) {
+		return e.value, false, nil
+	}
+
+	strategy, separator, warning := f.mergeStrategy(e, file)
+	previous, exists := values[e.key]
+	if strategy == MergeReplace || !exists {
+		return e.value, false, warning
+	}
+
+	switch {
+	case previous == "":
+		return e.value, true, warning
+	case e.value == "":
+		return previous, true, warning
+	case strategy == MergeAppend:
+		return previous + separator + e.value, true, warning
+	default:
+		return e.value + separator + previous, true, warning
+	}
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+/*
+FlattenKey converte o caminho de uma chave aninhada para um nome de variável de ambiente
+
+É a convenção usada por locenv migrate ao converter arquivos YAML, JSON e TOML: database.pool.size e
+FlattenKey("database", "pool", "size") resultam em DATABASE_POOL_SIZE.
+
+@param parts ...string - Os componentes do caminho, que também podem ser separados por pontos
+
+@return string - O nome da variável, em maiúsculas, com _ no lugar de separadores e caracteres inválidos
+*/
+func FlattenKey(parts ...string) string {
+	key := strings.ToUpper(strings.Join(parts, "_"))
+	return strings.Map(func(r rune) rune {
+		if r == '_' || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+/*
+Sub retorna uma visão imutável das variáveis de uma seção da configuração, com os nomes relativos à seção
+
+As variáveis achatadas de uma configuração aninhada continuam acessíveis de forma estruturada: com
+DATABASE_HOST carregada, loader.Sub("database").GetString("host") retorna o seu valor, assim como
+loader.GetString("database.host"). Seções podem ser encadeadas (Sub("database").Sub("pool")) ou informadas
+com pontos (Sub("database.pool")). Além das variáveis carregadas, a visão inclui as variáveis do processo com
+o prefixo da seção.
+
+@param prefix string - O nome da seção
+
+@return ConfigView - A visão da seção
+*/
+func (f *FileEnvLoader) Sub(prefix string) ConfigView {
+	if prefix == "" {
+		return f.Snapshot()
+	}
+
+	p := FlattenKey(prefix) + "_"
+	sub := &FileEnvLoader{
+		Env:          f.Env,
+		classAliases: f.classAliases,
+		values:       make(map[string]string),
+		secrets:      make(map[string]string),
+		sources:      make(map[string]string),
+		order:        make(map[string]int),
+		keyOrder:     f.keyOrder,
+		noProcessEnv: true,
+	}
+
+	if !f.noProcessEnv {
+		for _, kv := range os.Environ() {
+			key, value, _ := strings.Cut(kv, "=")
+			if rest, ok := strings.CutPrefix(key, p); ok && rest != "" {
+				sub.values[rest] = value
+				sub.sources[rest] = SourceProcess
+			}
+		}
+	}
+
+	for key, value := range f.values {
+		if rest, ok := strings.CutPrefix(key, p); ok && rest != "" {
+			sub.values[rest] = value
+			sub.sources[rest] = f.sources[key]
+			if rank, ok := f.order[key]; ok {
+				sub.order[rest] = rank
+			}
+		}
+	}
+	for key, value := range f.secrets {
+		if rest, ok := strings.CutPrefix(key, p); ok && rest != "" {
+			sub.secrets[rest] = value
+		}
+	}
+	sub.revision = configRevision(f.revisionKey, sub.Env, sub.values, sub.secrets)
+
+	return ConfigView{loader: sub}
+}
@@ -0,0 +1,9 @@
+package config
+
+/*
+Option é uma função que personaliza um FileEnvLoader durante a sua criação
+
+As opções são recebidas por NewEnvLoader e aplicadas na ordem em que foram informadas,
+depois que os valores padrão do carregador já foram definidos.
+*/
+type Option func(*FileEnvLoader)
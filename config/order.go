@@ -0,0 +1,92 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// KeyOrder define a ordem em que Keys, Dump e locenv export listam as variáveis.
+type KeyOrder int
+
+const (
+	// FileOrder lista as variáveis na ordem da primeira declaração, do arquivo base às sobreposições.
+	FileOrder KeyOrder = iota
+	// LexicalOrder lista as variáveis em ordem alfabética.
+	LexicalOrder
+)
+
+// String retorna o nome da ordem.
+func (o KeyOrder) String() string {
+	if o == LexicalOrder {
+		return "LexicalOrder"
+	}
+	return "FileOrder"
+}
+
+/*
+WithKeyOrder define a ordem das variáveis em Keys e Dump
+
+O padrão é FileOrder.
+
+@param order KeyOrder - A ordem (FileOrder ou LexicalOrder)
+
+@return Option - A opção que define a ordem
+*/
+func WithKeyOrder(order KeyOrder) Option {
+	return func(f *FileEnvLoader) {
+		f.keyOrder = order
+	}
+}
+
+/*
+Keys retorna os nomes das variáveis de All em uma ordem estável
+
+Com FileOrder, a ordem é a da primeira declaração de cada variável: as do arquivo base, na ordem do arquivo,
+seguidas das que só as sobreposições declaram. Uma variável redefinida por uma sobreposição mantém a posição da
+primeira declaração. Variáveis sem declaração em arquivo, como as definidas com Set ou WithOverrides, vêm
+depois, em ordem alfabética. Com LexicalOrder, todas as variáveis são ordenadas alfabeticamente.
+
+@return []string - Os nomes das variáveis
+*/
+func (f *FileEnvLoader) Keys() []string {
+	keys := sortedKeys(f.values)
+	if f.keyOrder == LexicalOrder || len(f.order) == 0 {
+		return keys
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		ri, iok := f.order[keys[i]]
+		rj, jok := f.order[keys[j]]
+		if iok != jok {
+			return iok
+		}
+		return iok && ri < rj
+	})
+
+	return keys
+}
+
+/*
+Dump retorna as variáveis de All no formato de um arquivo .env, na ordem de Keys
+
+Os valores são escritos com QuoteValue e os segredos são mascarados. Como a ordem padrão é a dos arquivos,
+um arquivo gerado por Dump e carregado de novo produz a mesma ordem, e os diffs de arquivos gerados mostram
+apenas as variáveis que mudaram.
+
+@return string - As declarações, uma por linha
+*/
+func (f *FileEnvLoader) Dump() string {
+	var b strings.Builder
+	for _, key := range f.Keys() {
+		value := f.values[key]
+		if _, secret := f.secrets[key]; secret {
+			value = maskedValue
+		}
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(QuoteValue(value))
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
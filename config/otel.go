@@ -0,0 +1,126 @@
+package config
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+/*
+DefaultResourceKeys associa as variáveis mais comuns aos atributos de recurso das convenções semânticas do OpenTelemetry
+*/
+var DefaultResourceKeys = map[string]string{
+	"SERVICE_NAME":      "service.name",
+	"SERVICE_VERSION":   "service.version",
+	"SERVICE_NAMESPACE": "service.namespace",
+	"DEPLOY_ENV":        "deployment.environment",
+	"REGION":            "cloud.region",
+}
+
+/*
+Attribute é um atributo de recurso ou item de baggage do OpenTelemetry
+
+Key string - O nome do atributo (ex.: service.name)
+Value string - O valor do atributo
+*/
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+/*
+Attributes é uma lista de atributos, ordenada pelo nome
+
+Os atributos podem ser convertidos para o SDK sem que a biblioteca dependa dele, por exemplo:
+
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		kvs = append(kvs, attribute.String(a.Key, a.Value))
+	}
+	res := resource.NewWithAttributes(semconv.SchemaURL, kvs...)
+*/
+type Attributes []Attribute
+
+/*
+ResourceAttributes monta os atributos de recurso do OpenTelemetry a partir da configuração carregada
+
+Cada variável presente em mapping vira o atributo correspondente; as ausentes ou vazias são ignoradas. Se
+deployment.environment não for definido por nenhuma variável, o ambiente carregado (GetEnv) é usado. Segredos
+nunca são incluídos, mesmo que estejam no mapeamento, pois os atributos são exportados para o backend de
+observabilidade.
+
+@param r Reader - A configuração carregada
+@param mapping map[string]string - O nome da variável associado ao nome do atributo; nil usa DefaultResourceKeys
+
+@return Attributes - Os atributos, ordenados pelo nome
+*/
+func ResourceAttributes(r Reader, mapping map[string]string) Attributes {
+	if mapping == nil {
+		mapping = DefaultResourceKeys
+	}
+
+	values := make(map[string]string, len(mapping)+1)
+	for key, attr := range mapping {
+		if _, secret := r.GetSecret(key); secret {
+			continue
+		}
+		if value, ok := r.Lookup(key); ok && value != "" {
+			values[attr] = value
+		}
+	}
+	if _, ok := values["deployment.environment"]; !ok && r.GetEnv() != "" {
+		values["deployment.environment"] = r.GetEnv()
+	}
+
+	attrs := make(Attributes, 0, len(values))
+	for key, value := range values {
+		attrs = append(attrs, Attribute{Key: key, Value: value})
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].Key < attrs[j].Key
+	})
+
+	return attrs
+}
+
+/*
+ResourceEnv formata os atributos como o valor de OTEL_RESOURCE_ATTRIBUTES
+
+@return string - Os atributos no formato chave=valor separados por vírgula, com os valores codificados
+*/
+func (a Attributes) ResourceEnv() string {
+	return a.encode()
+}
+
+/*
+Baggage formata os atributos como o cabeçalho baggage do W3C, para propagar a configuração entre serviços
+
+@return string - O valor do cabeçalho baggage
+*/
+func (a Attributes) Baggage() string {
+	return a.encode()
+}
+
+/*
+Map retorna os atributos como um mapa
+
+@return map[string]string - O nome de cada atributo associado ao seu valor
+*/
+func (a Attributes) Map() map[string]string {
+	m := make(map[string]string, len(a))
+	for _, attr := range a {
+		m[attr.Key] = attr.Value
+	}
+
+	return m
+}
+
+// encode escreve os atributos no formato chave=valor separado por vírgulas, com os valores em percent-encoding.
+func (a Attributes) encode() string {
+	parts := make([]string, len(a))
+	for i, attr := range a {
+		parts[i] = attr.Key + "=" + strings.ReplaceAll(url.QueryEscape(attr.Value), "+", "%20")
+	}
+
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"runtime"
+	"strings"
+)
+
+// userEnvVar é a variável de ambiente que define o usuário das sobreposições por usuário.
+const userEnvVar = "LOCENV_USER"
+
+/*
+WithPlatformOverlays habilita as sobreposições por sistema operacional e arquitetura
+
+Depois do arquivo .env.<env>, o carregador aplica, se existirem no mesmo diretório, os arquivos
+.env.<env>.<GOOS> (ex.: .env.development.darwin) e .env.<env>.<GOOS>-<GOARCH> (ex.: .env.development.linux-arm64),
+nessa ordem, para times cujos caminhos de ferramentas locais variam por plataforma.
+
+@return Option - A opção que habilita as sobreposições por plataforma
+*/
+func WithPlatformOverlays() Option {
+	return func(f *FileEnvLoader) {
+		f.platformOverlays = true
+	}
+}
+
+/*
+WithUserOverlays habilita as sobreposições por usuário
+
+Depois do arquivo .env.<env> e das demais sobreposições, o carregador aplica, se existirem no mesmo diretório,
+os arquivos .env.<env>.<usuário> e .env.local.<usuário>, nessa ordem, para que cada desenvolvedor ajuste portas
+e caminhos sem alterar os arquivos compartilhados. Esses arquivos devem ser adicionados ao .gitignore.
+
+O usuário é obtido da variável LOCENV_USER, se definida, ou do usuário do sistema operacional. No Windows,
+o domínio (DOMINIO\usuário) é removido. O nome é convertido para letras minúsculas.
+
+@return Option - A opção que habilita as sobreposições por usuário
+*/
+func WithUserOverlays() Option {
+	return func(f *FileEnvLoader) {
+		f.userOverlays = true
+	}
+}
+
+/*
+overlayNames retorna os nomes dos arquivos de sobreposição habilitados, na ordem em que devem ser aplicados
+
+@param env string - O ambiente do arquivo base, como aparece no nome do arquivo
+
+@return []string - Os nomes dos arquivos de sobreposição
+*/
+func (f *FileEnvLoader) overlayNames(env string) []string {
+	var names []string
+	if f.platformOverlays {
+		names = append(names, ".env."+env+"."+runtime.GOOS, ".env."+env+"."+runtime.GOOS+"-"+runtime.GOARCH)
+	}
+	if f.tenant != "" {
+		names = append(names, ".env."+env+"."+f.tenant)
+	}
+	if f.userOverlays {
+		if username := currentUsername(); username != "" {
+			names = append(names, ".env."+env+"."+username, ".env.local."+username)
+		} else {
+			f.tracef("sobreposições por usuário ignoradas: não foi possível identificar o usuário")
+		}
+	}
+
+	return names
+}
+
+/*
+currentUsername identifica o usuário atual para as sobreposições por usuário
+
+A função consulta LOCENV_USER, o usuário do sistema operacional (os/user) e, por fim, as variáveis
+USER e USERNAME. O domínio de nomes no formato DOMINIO\usuário, comuns no Windows, é removido.
+
+@return string - O nome do usuário em letras minúsculas, ou uma string vazia se não puder ser identificado
+*/
+func currentUsername() string {
+	username := os.Getenv(userEnvVar)
+	if username == "" {
+		if u, err := user.Current(); err == nil {
+			username = u.Username
+		}
+	}
+	if username == "" {
+		username = os.Getenv("USER")
+	}
+	if username == "" {
+		username = os.Getenv("USERNAME")
+	}
+
+	if i := strings.LastIndexAny(username, `\/`); i >= 0 {
+		username = username[i+1:]
+	}
+
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+/*
+overlayFiles retorna as sobreposições habilitadas que existem no diretório do arquivo base
+
+@param baseFile string - O caminho do arquivo .env base
+@param env string - O ambiente do arquivo base, como aparece no nome do arquivo
+
+@return []string - Os caminhos das sobreposições encontradas, na ordem em que devem ser aplicadas
+*/
+func (f *FileEnvLoader) overlayFiles(baseFile string, env string) []string {
+	dir := f.dirOf(baseFile)
+
+	var files []string
+	for _, name := range f.overlayNames(env) {
+		path := f.joinPath(dir, name)
+		if info, err := f.statFile(path); err != nil || info.IsDir() {
+			f.tracef("sobreposição %s ignorada: arquivo inexistente", path)
+			continue
+		}
+		f.tracef("sobreposição %s selecionada", path)
+		files = append(files, path)
+	}
+
+	return files
+}
+
+/*
+EnvFile localiza o arquivo .env do ambiente, como LoadEnv, sem carregá-lo
+
+@return string - O caminho do arquivo
+@return error - ErrEnvNotFound se nenhum arquivo for encontrado, ou um erro se a busca falhar
+*/
+func (f *FileEnvLoader) EnvFile() (string, error) {
+	envFile, _, err := f.findEnvFile()
+	if err != nil {
+		return "", err
+	}
+	if envFile == "" {
+		return "", ErrEnvNotFound
+	}
+
+	return envFile, nil
+}
+
+/*
+LocalOverlayFile retorna o caminho da sobreposição local do usuário, .env.local.<usuário>
+
+O arquivo fica no diretório do arquivo .env do ambiente e é carregado por último com WithUserOverlays, por isso
+é o lugar indicado para os valores que cada desenvolvedor preenche localmente, como fazem locenv placeholders
+e o provedor interativo. O arquivo não precisa existir.
+
+@return string - O caminho do arquivo
+@return error - ErrEnvNotFound se o arquivo do ambiente não for encontrado, ou um erro se o usuário não puder ser identificado
+*/
+func (f *FileEnvLoader) LocalOverlayFile() (string, error) {
+	envFile, err := f.EnvFile()
+	if err != nil {
+		return "", err
+	}
+
+	return f.localOverlayPath(envFile)
+}
+
+/*
+localOverlayPath retorna o caminho de .env.local.<usuário> no diretório do arquivo base
+
+@param baseFile string - O arquivo .env base
+
+@return string - O caminho do arquivo
+@return error - Um erro se o usuário não puder ser identificado
+*/
+func (f *FileEnvLoader) localOverlayPath(baseFile string) (string, error) {
+	username := currentUsername()
+	if username == "" {
+		return "", fmt.Errorf("não foi possível identificar o usuário; defina %s", userEnvVar)
+	}
+
+	return f.joinPath(f.dirOf(baseFile), ".env.local."+username), nil
+}
@@ -0,0 +1,99 @@
+package config
+
+import "context"
+
+// SourceContext é a origem informada por Source para as variáveis sobrescritas com WithOverrides.
+const SourceContext = "context"
+
+// overridesKey e contextReaderKey são as chaves dos valores guardados no contexto por WithOverrides e ContextWithReader.
+type (
+	overridesKey     struct{}
+	contextReaderKey struct{}
+)
+
+/*
+WithOverrides retorna uma cópia do contexto que sobrescreve algumas variáveis da configuração
+
+As sobreposições valem apenas para quem lê a configuração com FromContext e nunca alteram o ambiente do processo,
+de modo que testes paralelos e requisições de inquilinos diferentes podem usar valores distintos ao mesmo tempo.
+Sobreposições aninhadas são combinadas, e as do contexto mais interno prevalecem:
+
+	ctx = config.WithOverrides(ctx, map[string]string{"FEATURE_X": "true"})
+	enabled, _ := config.FromContext(ctx).GetBool("FEATURE_X")
+
+@param ctx context.Context - O contexto original
+@param overrides map[string]string - As variáveis sobrescritas; o mapa é copiado
+
+@return context.Context - O contexto com as sobreposições
+*/
+func WithOverrides(ctx context.Context, overrides map[string]string) context.Context {
+	merged := copyMap(contextOverrides(ctx))
+	for key, value := range overrides {
+		merged[key] = value
+	}
+
+	return context.WithValue(ctx, overridesKey{}, merged)
+}
+
+/*
+ContextWithReader retorna uma cópia do contexto com a configuração usada como base por FromContext
+
+@param ctx context.Context - O contexto original
+@param r Reader - A configuração, como um carregador ou uma ConfigView
+
+@return context.Context - O contexto com a configuração
+*/
+func ContextWithReader(ctx context.Context, r Reader) context.Context {
+	return context.WithValue(ctx, contextReaderKey{}, r)
+}
+
+/*
+FromContext retorna a configuração do contexto, com as sobreposições de WithOverrides aplicadas
+
+A base é a configuração guardada com ContextWithReader ou, na falta dela, o carregador padrão. Sem sobreposições,
+a própria base é retornada. Com sobreposições, o resultado é uma visão imutável da base no momento da chamada, em
+que as variáveis sobrescritas têm a origem SourceContext e os segredos sobrescritos continuam sendo segredos. Ao
+contrário de Snapshot, a visão de um FileEnvLoader continua consultando o ambiente do processo para as variáveis
+que não vieram dos arquivos .env.
+
+@param ctx context.Context - O contexto
+
+@return Reader - A configuração do contexto
+*/
+func FromContext(ctx context.Context) Reader {
+	base, ok := ctx.Value(contextReaderKey{}).(Reader)
+	if !ok || base == nil {
+		base = Default()
+	}
+
+	overrides := contextOverrides(ctx)
+	if len(overrides) == 0 {
+		return base
+	}
+
+	view := *base.Snapshot().reader()
+	if f, ok := base.(*FileEnvLoader); ok {
+		view.noProcessEnv = f.noProcessEnv
+	}
+	view.values = copyMap(view.values)
+	view.secrets = copyMap(view.secrets)
+	view.sources = copyMap(view.sources)
+
+	for key, value := range overrides {
+		if _, secret := view.secrets[key]; secret {
+			view.secrets[key] = value
+		} else {
+			view.values[key] = value
+		}
+		view.sources[key] = SourceContext
+	}
+	view.revision = configRevision(view.revisionKey, view.Env, view.values, view.secrets)
+
+	return ConfigView{loader: &view}
+}
+
+// contextOverrides retorna as sobreposições guardadas no contexto, ou nil se não houver nenhuma.
+func contextOverrides(ctx context.Context) map[string]string {
+	overrides, _ := ctx.Value(overridesKey{}).(map[string]string)
+	return overrides
+}
@@ -0,0 +1,725 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/*
+ParseError é o erro retornado quando um arquivo .env possui uma sintaxe inválida
+
+Todo erro de interpretação informa o arquivo, a linha, a coluna e o trecho que causou o problema,
+para que o arquivo possa ser corrigido sem adivinhação.
+
+File string - O caminho do arquivo, ou vazio quando o conteúdo não veio de um arquivo
+Line int - A linha do erro, a partir de 1
+Column int - A coluna do erro, em caracteres, a partir de 1
+Token string - O trecho que causou o erro
+Msg string - A descrição do erro
+*/
+type ParseError struct {
+	File   string
+	Line   int
+	Column int
+	Token  string
+	Msg    string
+}
+
+// Error formata o erro no padrão arquivo:linha:coluna.
+func (e *ParseError) Error() string {
+	file := e.File
+	if file == "" {
+		file = "<entrada>"
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %s (trecho %q)", file, e.Line, e.Column, e.Msg, e.Token)
+}
+
+/*
+entry é uma variável interpretada de um arquivo .env
+
+key string - O nome da variável
+value string - O valor, já sem aspas, com escapes e referências expandidos
+line int - A linha em que a variável foi declarada
+endLine int - A última linha da declaração, diferente de line em valores entre aspas com várias linhas
+column int - A coluna em que o nome da variável começa
+quote byte - As aspas que delimitavam o valor, ou 0 para valores sem aspas
+comment string - Os comentários imediatamente acima da declaração, sem o caractere '#' e separados por '\n'
+*/
+type entry struct {
+	key     string
+	value   string
+	line    int
+	endLine int
+	column  int
+	quote   byte
+	comment string
+}
+
+/*
+Parse interpreta o conteúdo de um arquivo .env e retorna as variáveis declaradas
+
+A sintaxe aceita é a mesma da biblioteca godotenv: prefixo "export" opcional, separadores "=" ou ":",
+valores entre aspas simples (literais) ou duplas (com escapes e referências ${VAR}), valores multilinha
+entre aspas e comentários iniciados por '#'. Erros de sintaxe são retornados como *ParseError, e referências
+${VAR} que formam um ciclo, como *ExpansionCycleError.
+
+O conteúdo é lido linha a linha (veja ParseStream), sem ser carregado por inteiro na memória.
+
+Orçamento de alocações: a interpretação faz no máximo 4 alocações por variável comentada (a linha da declaração,
+a linha do comentário, o valor expandido e a entrada no mapa), além de um número constante de alocações
+para o buffer de leitura. O orçamento é verificado por TestParseAllocationBudget e medido por BenchmarkParseLargeFile.
+
+@param r io.Reader - O conteúdo a ser interpretado
+
+@return map[string]string - As variáveis declaradas
+@return error - Um *ParseError se o conteúdo possuir uma sintaxe inválida, ou um erro de leitura
+*/
+func Parse(r io.Reader) (map[string]string, error) {
+	entries, err := parseEntries(r, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return entriesToMap(entries), nil
+}
+
+/*
+ParseStream interpreta um arquivo .env linha a linha, chamando fn para cada variável declarada
+
+A memória usada é limitada ao buffer de leitura e às variáveis já declaradas (necessárias para a expansão
+de referências), o que permite interpretar arquivos de vários megabytes. Se fn retornar um erro,
+a interpretação é interrompida imediatamente e o erro é retornado, permitindo abortar na primeira
+variável inválida. Linhas maiores que MaxLineSize são rejeitadas com um *ParseError.
+
+@param r io.Reader - O conteúdo a ser interpretado
+@param fn func(key string, value string, line int) error - A função chamada para cada variável, na ordem do arquivo
+
+@return error - Um *ParseError se o conteúdo possuir uma sintaxe inválida, o erro retornado por fn, ou um erro de leitura
+*/
+func ParseStream(r io.Reader, fn func(key string, value string, line int) error) error {
+	return parseStream(r, "", func(e entry) error {
+		return fn(e.key, e.value, e.line)
+	})
+}
+
+/*
+entriesToMap converte as variáveis interpretadas em um mapa, em que a última declaração de cada chave prevalece
+
+@param entries []entry - As variáveis interpretadas
+
+@return map[string]string - As variáveis e seus valores
+*/
+func entriesToMap(entries []entry) map[string]string {
+	values := make(map[string]string, len(entries))
+	for _, e := range entries {
+		values[e.key] = e.value
+	}
+
+	return values
+}
+
+/*
+parseEntries interpreta o conteúdo de um arquivo .env e retorna as variáveis na ordem em que foram declaradas
+
+@param r io.Reader - O conteúdo a ser interpretado
+@param file string - O caminho do arquivo, usado nas mensagens de erro
+
+@return []entry - As variáveis interpretadas
+@return error - Um *ParseError se o conteúdo possuir uma sintaxe inválida, ou um erro de leitura
+*/
+func parseEntries(r io.Reader, file string) ([]entry, error) {
+	return parseEntriesLimited(r, file, Limits{}, nil)
+}
+
+/*
+parseEntriesLimited interpreta o conteúdo de um arquivo .env como parseEntries, aplicando os limites informados
+
+@param r io.Reader - O conteúdo a ser interpretado
+@param file string - O caminho do arquivo, usado nas mensagens de erro
+@param limits Limits - Os limites de quantidade de variáveis e de tamanho dos valores
+@param substitute func(string) (string, error) - Executa o comando de um trecho $(...), ou nil para mantê-lo literal
+
+@return []entry - As variáveis interpretadas
+@return error - Um *ParseError se o conteúdo possuir uma sintaxe inválida, um erro de ErrLimitExceeded, ou um erro de leitura
+*/
+func parseEntriesLimited(r io.Reader, file string, limits Limits, substitute func(string) (string, error)) ([]entry, error) {
+	var entries []entry
+	err := parseStreamLimited(r, file, limits, substitute, func(e entry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// MaxLineSize é o tamanho máximo, em bytes, de uma linha de um arquivo .env.
+const MaxLineSize = 1 << 20
+
+// readBufferSize é o tamanho inicial do buffer de leitura, que cresce até MaxLineSize se necessário.
+const readBufferSize = 64 * 1024
+
+/*
+parseStream interpreta o conteúdo de um arquivo .env linha a linha, chamando visit para cada variável
+
+O buffer de leitura é zerado ao final, pois pode conter valores secretos.
+
+@param r io.Reader - O conteúdo a ser interpretado
+@param file string - O caminho do arquivo, usado nas mensagens de erro
+@param visit func(entry) error - A função chamada para cada variável; um erro interrompe a interpretação
+
+@return error - Um *ParseError se o conteúdo possuir uma sintaxe inválida, o erro retornado por visit, ou um erro de leitura
+*/
+func parseStream(r io.Reader, file string, visit func(entry) error) error {
+	return parseStreamLimited(r, file, Limits{}, nil, visit)
+}
+
+// parseStreamLimited interpreta o conteúdo como parseStream, aplicando MaxKeys e MaxValueLength e, se substitute não for nil, a substituição de comandos.
+func parseStreamLimited(r io.Reader, file string, limits Limits, substitute func(string) (string, error), visit func(entry) error) error {
+	buf := make([]byte, readBufferSize)
+	defer zeroBytes(buf)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(buf, MaxLineSize)
+
+	p := &parser{
+		file:       file,
+		scanner:    scanner,
+		vars:       make(map[string]string),
+		limits:     limits,
+		substitute: substitute,
+	}
+
+	return p.parse(visit)
+}
+
+/*
+parser guarda o estado da interpretação de um arquivo .env
+
+file string - O caminho do arquivo, usado nas mensagens de erro
+scanner *bufio.Scanner - O leitor das linhas do conteúdo
+lineNo int - O número da última linha lida
+vars map[string]string - As variáveis já declaradas, usadas na expansão de referências
+limits Limits - Os limites de quantidade de variáveis e de tamanho dos valores
+cycles cycleGraph - As referências que podem formar ciclos (veja cycles.go)
+substitute func(string) (string, error) - Executa o comando de um trecho $(...), ou nil para mantê-lo literal (veja substitution.go)
+*/
+type parser struct {
+	file       string
+	scanner    *bufio.Scanner
+	lineNo     int
+	vars       map[string]string
+	limits     Limits
+	cycles     cycleGraph
+	substitute func(string) (string, error)
+}
+
+/*
+nextLine lê a próxima linha do conteúdo, sem a quebra de linha
+
+O caractere '\r' de finais de linha CRLF e a marca de ordem de bytes (BOM) da primeira linha são removidos.
+
+@return string - A linha lida
+@return bool - Se uma linha foi lida; false no fim do conteúdo ou em caso de erro de leitura
+*/
+func (p *parser) nextLine() (string, bool) {
+	if !p.scanner.Scan() {
+		return "", false
+	}
+
+	p.lineNo++
+	line := p.scanner.Text()
+	if p.lineNo == 1 {
+		line = strings.TrimPrefix(line, "\ufeff")
+	}
+
+	return strings.TrimSuffix(line, "\r"), true
+}
+
+/*
+scanError converte um erro de leitura do scanner, se houver, no erro retornado pela interpretação
+
+@return error - Um *ParseError se a linha exceder MaxLineSize, o erro de leitura, ou nil
+*/
+func (p *parser) scanError() error {
+	err := p.scanner.Err()
+	if errors.Is(err, bufio.ErrTooLong) {
+		return &ParseError{
+			File:   p.file,
+			Line:   p.lineNo + 1,
+			Column: 1,
+			Token:  "<linha longa>",
+			Msg:    fmt.Sprintf("linha excede o tamanho máximo de %d bytes", MaxLineSize),
+		}
+	}
+
+	return err
+}
+
+/*
+parse percorre as linhas do conteúdo, acumulando comentários e interpretando cada declaração
+
+Uma linha em branco descarta os comentários acumulados, de modo que apenas os comentários imediatamente
+acima de uma declaração são associados a ela.
+
+@param visit func(entry) error - A função chamada para cada variável; um erro interrompe a interpretação
+
+@return error - Um *ParseError se alguma linha possuir uma sintaxe inválida, o erro retornado por visit, ou um erro de leitura
+*/
+func (p *parser) parse(visit func(entry) error) error {
+	comment := ""
+	commented := false
+
+	for {
+		line, ok := p.nextLine()
+		if !ok {
+			if err := p.scanError(); err != nil {
+				return err
+			}
+			return p.checkCycles()
+		}
+
+		rest := strings.TrimLeftFunc(line, isInlineSpace)
+		switch {
+		case rest == "":
+			comment, commented = "", false
+			continue
+		case rest[0] == '#':
+			if commented {
+				comment += "\n" + strings.TrimSpace(rest[1:])
+			} else {
+				comment, commented = strings.TrimSpace(rest[1:]), true
+			}
+			continue
+		}
+
+		e, err := p.parseDeclaration(line, p.lineNo)
+		if err != nil {
+			return err
+		}
+
+		e.endLine = p.lineNo
+		e.comment = comment
+		comment, commented = "", false
+		if _, ok := p.vars[e.key]; !ok && p.limits.MaxKeys > 0 && len(p.vars) >= p.limits.MaxKeys {
+			return fmt.Errorf("%w: %s:%d: o arquivo declara mais de %d variáveis", ErrLimitExceeded, p.fileName(), e.line, p.limits.MaxKeys)
+		}
+		p.declare(e.key, e.line)
+		p.vars[e.key] = e.value
+		if err := visit(e); err != nil {
+			return err
+		}
+	}
+}
+
+/*
+parseDeclaration interpreta uma declaração KEY=VALUE que começa na linha informada
+
+@param line string - O conteúdo da linha
+@param lineNo int - O número da linha, a partir de 1
+
+@return entry - A variável interpretada
+@return error - Um *ParseError se a declaração possuir uma sintaxe inválida
+*/
+func (p *parser) parseDeclaration(line string, lineNo int) (entry, error) {
+	i := len(line) - len(strings.TrimLeftFunc(line, isInlineSpace))
+
+	if strings.HasPrefix(line[i:], "export") {
+		after := line[i+len("export"):]
+		if trimmed := strings.TrimLeftFunc(after, isInlineSpace); len(trimmed) < len(after) {
+			i = len(line) - len(trimmed)
+		}
+	}
+
+	keyStart := i
+	for i < len(line) {
+		r, size := utf8.DecodeRuneInString(line[i:])
+		if !isKeyRune(r) {
+			break
+		}
+		i += size
+	}
+	key := line[keyStart:i]
+
+	i = skipInlineSpace(line, i)
+
+	if i == len(line) {
+		return entry{}, p.errorAt(line, lineNo, i, key, "esperado '=' após o nome da variável")
+	}
+	if line[i] != '=' && line[i] != ':' {
+		r, _ := utf8.DecodeRuneInString(line[i:])
+		if key == "" {
+			return entry{}, p.errorAt(line, lineNo, i, string(r), "caractere inesperado no nome da variável")
+		}
+		return entry{}, p.errorAt(line, lineNo, i, string(r), "esperado '=' após o nome da variável")
+	}
+	if key == "" {
+		return entry{}, p.errorAt(line, lineNo, i, line[i:i+1], "nome de variável vazio")
+	}
+
+	i++
+	i = skipInlineSpace(line, i)
+
+	e := entry{key: key, line: lineNo, column: columnOf(line, keyStart)}
+	p.cycles.key = key
+
+	if i < len(line) && (line[i] == '"' || line[i] == '\'') {
+		value, err := p.parseQuoted(line, lineNo, i)
+		if err != nil {
+			return entry{}, err
+		}
+		if p.limits.MaxValueLength > 0 && len(value) > p.limits.MaxValueLength {
+			return entry{}, p.valueTooLong(lineNo)
+		}
+		e.quote = line[i]
+		e.value = value
+		return e, nil
+	}
+
+	value, err := p.expand(stripInlineComment(line[i:]), lineNo)
+	if err != nil {
+		return entry{}, err
+	}
+	e.value = value
+
+	return e, nil
+}
+
+/*
+parseQuoted interpreta um valor entre aspas, que pode continuar nas linhas seguintes
+
+Aspas precedidas por '\' não encerram o valor. Após as aspas de fechamento, apenas espaços
+e um comentário são permitidos.
+
+@param line string - A linha em que o valor começa
+@param lineNo int - O número da linha, a partir de 1
+@param start int - A posição das aspas de abertura na linha
+
+@return string - O valor interpretado
+@return error - Um *ParseError se as aspas não forem fechadas ou houver conteúdo após elas
+*/
+func (p *parser) parseQuoted(line string, lineNo int, start int) (string, error) {
+	quote := line[start]
+	current, currentNo := line, lineNo
+
+	var raw string
+	end := closingQuote(line, start+1, quote)
+	if end >= 0 {
+		raw = line[start+1 : end]
+	} else {
+		var b strings.Builder
+		b.WriteString(line[start+1:])
+		for end < 0 {
+			next, ok := p.nextLine()
+			if !ok {
+				if err := p.scanError(); err != nil {
+					return "", err
+				}
+				return "", p.errorAt(line, lineNo, start, string(quote), "valor entre aspas não terminado")
+			}
+			current, currentNo = next, p.lineNo
+
+			end = closingQuote(current, 0, quote)
+			b.WriteByte('\n')
+			if end < 0 {
+				b.WriteString(current)
+			} else {
+				b.WriteString(current[:end])
+			}
+		}
+		raw = b.String()
+	}
+
+	trailing := strings.TrimLeftFunc(current[end+1:], isInlineSpace)
+	if trailing != "" && trailing[0] != '#' {
+		pos := len(current) - len(trailing)
+		r, _ := utf8.DecodeRuneInString(trailing)
+		return "", p.errorAt(current, currentNo, pos, string(r), "conteúdo inesperado após o valor entre aspas")
+	}
+
+	if quote == '\'' {
+		return raw, nil
+	}
+
+	return p.expand(unescapeDoubleQuoted(raw), lineNo)
+}
+
+/*
+expand substitui as referências $VAR e ${VAR} pelos valores das variáveis declaradas anteriormente no arquivo
+
+Referências a variáveis não declaradas são substituídas por uma string vazia, como na biblioteca godotenv,
+e "\$" produz um '$' literal. Com a substituição de comandos habilitada, cada trecho $(...) do texto do arquivo
+é substituído pela saída do comando; os valores das referências e as saídas são inseridos sem nova interpretação,
+de modo que um "$(" vindo de outra variável nunca é executado. O tamanho do valor é verificado a cada referência substituída, de modo que uma
+expansão que ultrapassa MaxValueLength é interrompida antes de o valor ser montado por inteiro.
+
+@param value string - O valor com as referências
+@param lineNo int - A linha da declaração, usada na mensagem de erro
+
+@return string - O valor expandido
+@return error - Um erro de ErrLimitExceeded se o valor expandido exceder MaxValueLength, ou um erro da substituição de comandos
+*/
+func (p *parser) expand(value string, lineNo int) (string, error) {
+	if !strings.Contains(value, "$") {
+		if p.limits.MaxValueLength > 0 && len(value) > p.limits.MaxValueLength {
+			return "", p.valueTooLong(lineNo)
+		}
+		return value, nil
+	}
+
+	var b strings.Builder
+	b.Grow(len(value))
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '\\' && i+1 < len(value) && value[i+1] == '$':
+			b.WriteByte('$')
+			i++
+		case c != '$':
+			b.WriteByte(c)
+		case i+1 < len(value) && value[i+1] == '{':
+			end := strings.IndexByte(value[i+2:], '}')
+			if end < 0 {
+				b.WriteByte(c)
+				continue
+			}
+			if err := p.grow(&b, value[i+2:i+2+end], lineNo); err != nil {
+				return "", err
+			}
+			i += 2 + end
+		case p.substitute != nil && i+1 < len(value) && value[i+1] == '(':
+			end := closingParen(value, i+2)
+			if end < 0 {
+				return "", fmt.Errorf("%s:%d: substituição de comando em %s sem o ')' de fechamento", p.fileName(), lineNo, p.cycles.key)
+			}
+			if err := p.run(&b, value[i+2:end], lineNo); err != nil {
+				return "", err
+			}
+			i = end
+		default:
+			j := i + 1
+			for j < len(value) && isVarNameByte(value[j]) {
+				j++
+			}
+			if j == i+1 {
+				b.WriteByte(c)
+				continue
+			}
+			if err := p.grow(&b, value[i+1:j], lineNo); err != nil {
+				return "", err
+			}
+			i = j - 1
+		}
+	}
+
+	if p.limits.MaxValueLength > 0 && b.Len() > p.limits.MaxValueLength {
+		return "", p.valueTooLong(lineNo)
+	}
+
+	return b.String(), nil
+}
+
+// grow acrescenta o valor da variável referenciada ao valor em expansão, respeitando MaxValueLength.
+func (p *parser) grow(b *strings.Builder, name string, lineNo int) error {
+	value, ok := p.vars[name]
+	p.noteRef(name, ok)
+	if p.limits.MaxValueLength > 0 && b.Len()+len(value) > p.limits.MaxValueLength {
+		return p.valueTooLong(lineNo)
+	}
+
+	b.WriteString(value)
+	return nil
+}
+
+// run acrescenta a saída do comando de uma substituição ao valor em expansão, respeitando MaxValueLength.
+func (p *parser) run(b *strings.Builder, command string, lineNo int) error {
+	output, err := p.substitute(command)
+	if err != nil {
+		return fmt.Errorf("%s:%d: substituição de comando em %s: %w", p.fileName(), lineNo, p.cycles.key, err)
+	}
+	if p.limits.MaxValueLength > 0 && b.Len()+len(output) > p.limits.MaxValueLength {
+		return p.valueTooLong(lineNo)
+	}
+
+	b.WriteString(output)
+	return nil
+}
+
+// valueTooLong cria o erro de um valor maior que MaxValueLength.
+func (p *parser) valueTooLong(lineNo int) error {
+	return fmt.Errorf("%w: %s:%d: o valor excede o tamanho máximo de %d bytes", ErrLimitExceeded, p.fileName(), lineNo, p.limits.MaxValueLength)
+}
+
+// fileName retorna o caminho do arquivo para as mensagens de erro, ou "<entrada>" quando o conteúdo não veio de um arquivo.
+func (p *parser) fileName() string {
+	if p.file == "" {
+		return "<entrada>"
+	}
+
+	return p.file
+}
+
+/*
+errorAt cria um *ParseError para a posição informada
+
+@param line string - A linha do erro
+@param lineNo int - O número da linha, a partir de 1
+@param pos int - A posição do erro na linha, em bytes
+@param token string - O trecho que causou o erro
+@param msg string - A descrição do erro
+
+@return *ParseError - O erro criado
+*/
+func (p *parser) errorAt(line string, lineNo int, pos int, token string, msg string) *ParseError {
+	return &ParseError{
+		File:   p.file,
+		Line:   lineNo,
+		Column: columnOf(line, pos),
+		Token:  token,
+		Msg:    msg,
+	}
+}
+
+/*
+closingQuote procura as aspas de fechamento a partir da posição informada, ignorando as aspas escapadas
+
+@param s string - O texto
+@param from int - A posição inicial da busca
+@param quote byte - As aspas procuradas
+
+@return int - A posição das aspas de fechamento, ou -1 se não forem encontradas
+*/
+func closingQuote(s string, from int, quote byte) int {
+	for i := from; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			return i
+		}
+	}
+
+	return -1
+}
+
+/*
+stripInlineComment remove um comentário no fim de um valor sem aspas e os espaços nas extremidades
+
+Um '#' só inicia um comentário quando é precedido por um espaço, para que valores como "a#b" sejam preservados.
+
+@param value string - O valor sem aspas
+
+@return string - O valor sem o comentário
+*/
+func stripInlineComment(value string) string {
+	for i := 1; i < len(value); i++ {
+		if value[i] == '#' && spaceBefore(value, i) {
+			value = value[:i]
+			break
+		}
+	}
+
+	return strings.TrimFunc(value, isInlineSpace)
+}
+
+/*
+unescapeDoubleQuoted interpreta as sequências de escape de um valor entre aspas duplas
+
+As sequências \n e \r viram quebras de linha; "\$" é preservado para a expansão; qualquer outro
+caractere precedido por '\' é mantido sem a barra.
+
+@param value string - O valor entre aspas duplas, sem as aspas
+
+@return string - O valor com os escapes interpretados
+*/
+func unescapeDoubleQuoted(value string) string {
+	if !strings.Contains(value, "\\") {
+		return value
+	}
+
+	var b strings.Builder
+	b.Grow(len(value))
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			b.WriteByte(value[i])
+			continue
+		}
+
+		i++
+		switch value[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case '$':
+			b.WriteString("\\$")
+		default:
+			b.WriteByte(value[i])
+		}
+	}
+
+	return b.String()
+}
+
+/*
+columnOf converte uma posição em bytes para uma coluna em caracteres, a partir de 1
+
+@param line string - A linha
+@param pos int - A posição em bytes
+
+@return int - A coluna correspondente
+*/
+func columnOf(line string, pos int) int {
+	if pos > len(line) {
+		pos = len(line)
+	}
+
+	return utf8.RuneCountInString(line[:pos]) + 1
+}
+
+// isInlineSpace informa se o caractere é um espaço que não quebra a linha.
+func isInlineSpace(r rune) bool {
+	switch r {
+	case ' ', '\t', '\v', '\f', '\r', 0x85, 0xA0:
+		return true
+	}
+
+	return false
+}
+
+// skipInlineSpace retorna a posição do primeiro caractere a partir de i que não é um espaço na linha.
+func skipInlineSpace(s string, i int) int {
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if !isInlineSpace(r) {
+			break
+		}
+		i += size
+	}
+
+	return i
+}
+
+// spaceBefore informa se o caractere que termina antes da posição i é um espaço na linha, decodificando o UTF-8.
+func spaceBefore(s string, i int) bool {
+	r, _ := utf8.DecodeLastRuneInString(s[:i])
+	return isInlineSpace(r)
+}
+
+// isKeyRune informa se o caractere pode fazer parte do nome de uma variável.
+func isKeyRune(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsNumber(r)
+}
+
+// isVarNameByte informa se o byte pode fazer parte de uma referência $VAR.
+func isVarNameByte(c byte) bool {
+	return c == '_' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+)
+
+// relativeAnnotation é o comentário que resolve o valor de uma declaração em relação ao diretório do arquivo.
+const relativeAnnotation = "@relative"
+
+/*
+PathCheck valida o caminho retornado por GetPath
+
+@param path string - O caminho resolvido
+@param info fs.FileInfo - As informações do arquivo ou diretório
+
+@return error - Um erro se o caminho não atender à verificação
+*/
+type PathCheck func(path string, info fs.FileInfo) error
+
+// PathExists é uma PathCheck que apenas exige que o caminho exista.
+func PathExists(string, fs.FileInfo) error {
+	return nil
+}
+
+// PathIsDir é uma PathCheck que exige que o caminho seja um diretório.
+func PathIsDir(path string, info fs.FileInfo) error {
+	if !info.IsDir() {
+		return fmt.Errorf("%s não é um diretório", path)
+	}
+
+	return nil
+}
+
+// PathIsFile é uma PathCheck que exige que o caminho seja um arquivo regular.
+func PathIsFile(path string, info fs.FileInfo) error {
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s não é um arquivo regular", path)
+	}
+
+	return nil
+}
+
+/*
+GetPath retorna o valor de uma variável como um caminho, resolvido em relação ao arquivo que a definiu
+
+Um caminho relativo em um arquivo .env é interpretado a partir do diretório desse arquivo, e não do diretório
+atual do processo, de modo que TEMPLATES_DIR=templates continua apontando para o mesmo lugar quando o programa
+é executado a partir de outro diretório. Valores definidos pelo processo ou por outras fontes, e caminhos
+absolutos, são retornados como estão. Com verificações, como PathIsDir, o caminho também precisa existir.
+A anotação "# @relative" acima da declaração faz a mesma resolução durante o carregamento, para que o valor
+aplicado ao processo já seja absoluto.
+
+@param key string - O nome da variável
+@param checks ...PathCheck - As verificações do caminho; sem nenhuma, a existência não é verificada
+
+@return string - O caminho resolvido
+@return error - ErrKeyNotFound se a variável não existir, ou um erro se alguma verificação falhar
+*/
+func (f *FileEnvLoader) GetPath(key string, checks ...PathCheck) (string, error) {
+	value, err := f.require(key)
+	if err != nil {
+		return "", err
+	}
+
+	resolved := value
+	if source, ok := f.sources[key]; ok && f.isFileSource(source) {
+		resolved = f.resolvePath(value, source)
+	}
+	if len(checks) == 0 {
+		return resolved, nil
+	}
+
+	info, err := f.statFile(resolved)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("variável %s: o caminho %s não existe", key, resolved)
+		}
+		return "", fmt.Errorf("variável %s: %w", key, err)
+	}
+	for _, check := range checks {
+		if err := check(resolved, info); err != nil {
+			return "", fmt.Errorf("variável %s: %w", key, err)
+		}
+	}
+
+	return resolved, nil
+}
+
+/*
+isFileSource informa se a origem de uma variável é um arquivo .env, e não o processo ou outra fonte
+
+@param source string - A origem informada por Source
+
+@return bool - Se a origem é um arquivo
+*/
+func (f *FileEnvLoader) isFileSource(source string) bool {
+	switch source {
+	case "", SourceProcess, SourceMemory, SourceContext, SourceHook:
+		return false
+	default:
+		return true
+	}
+}
+
+/*
+resolvePath resolve um caminho relativo em relação ao diretório de um arquivo .env
+
+@param value string - O caminho
+@param file string - O arquivo que o declarou
+
+@return string - O caminho resolvido, com a sintaxe do sistema de arquivos do carregador; absoluto fora de WithFS
+*/
+func (f *FileEnvLoader) resolvePath(value string, file string) string {
+	if value == "" || filepath.IsAbs(value) {
+		return value
+	}
+
+	resolved := f.joinPath(f.dirOf(file), value)
+	if f.fsys == nil {
+		if abs, err := filepath.Abs(resolved); err == nil {
+			return abs
+		}
+	}
+
+	return resolved
+}
+
+/*
+relativeValue aplica a anotação "# @relative" a uma declaração
+
+@param e entry - A declaração
+@param file string - O arquivo da declaração
+
+@return string - O valor resolvido em relação ao arquivo, ou o valor original sem a anotação
+*/
+func (f *FileEnvLoader) relativeValue(e entry, file string) string {
+	if e.comment == "" {
+		return e.value
+	}
+	if _, ok := commentAnnotation(e.comment, relativeAnnotation); !ok {
+		return e.value
+	}
+
+	return f.resolvePath(e.value, file)
+}
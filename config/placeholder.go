@@ -0,0 +1,177 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// placeholderWords são os valores que, sozinhos, indicam que a variável ainda não foi preenchida.
+var placeholderWords = map[string]bool{
+	"todo": true, "tbd": true, "fixme": true, "placeholder": true,
+	"changeme": true, "change_me": true, "change-me": true,
+	"replaceme": true, "replace_me": true, "replace-me": true,
+	"fillme": true, "fill_me": true, "fill-me": true,
+}
+
+// placeholderPhrase reconhece frases como your_api_key_here, YOUR-TOKEN-HERE e insert-key-here.
+var placeholderPhrase = regexp.MustCompile(`(?i)^(your|my|insert|put|enter)[-_ ].*[-_ ]here$`)
+
+/*
+IsPlaceholder informa se um valor parece um texto de exemplo que ainda não foi substituído pelo valor real
+
+São reconhecidos os valores entre sinais de menor e maior (<YOUR_KEY_HERE>), os valores que começam com TODO,
+as palavras changeme, replace_me, fixme, tbd e placeholder (e suas variações com '-' e '_'), as frases como
+your_api_key_here e os valores formados apenas pela letra x (xxx, XXXXXXXX).
+
+@param value string - O valor
+
+@return bool - Se o valor é um texto de exemplo
+*/
+func IsPlaceholder(value string) bool {
+	value = strings.TrimSpace(value)
+	if len(value) < 3 {
+		return false
+	}
+
+	lower := strings.ToLower(value)
+	switch {
+	case strings.HasPrefix(value, "<") && strings.HasSuffix(value, ">") && !strings.ContainsAny(value[1:len(value)-1], "<>"):
+		return true
+	case placeholderWords[lower], placeholderPhrase.MatchString(value):
+		return true
+	case strings.Trim(lower, "x") == "":
+		return true
+	case strings.HasPrefix(lower, "todo"):
+		rest := lower[len("todo"):]
+		return rest == "" || !isKeyRune(rune(rest[0]))
+	}
+
+	return false
+}
+
+/*
+WithPlaceholderCheck faz o carregamento falhar se alguma variável tiver um valor de exemplo
+
+Os arquivos .env copiados de um .env.example costumam manter valores como <YOUR_KEY_HERE> ou changeme, que
+passam despercebidos até a primeira chamada que os usa. Com esta opção, LoadEnv e Plan retornam um
+*ValidationError que lista cada variável, com o arquivo e a linha, cujo valor é reconhecido por IsPlaceholder.
+O subcomando locenv placeholders lista esses valores e pode pedir os valores reais interativamente.
+
+@return Option - A opção que habilita a verificação
+*/
+func WithPlaceholderCheck() Option {
+	return func(f *FileEnvLoader) {
+		f.placeholderCheck = true
+	}
+}
+
+/*
+Placeholder é uma variável cujo valor é um texto de exemplo
+
+Key string - O nome da variável
+Value string - O valor de exemplo
+File string - O arquivo que define o valor
+Line int - A linha da declaração
+*/
+type Placeholder struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	File  string `json:"file"`
+	Line  int    `json:"line"`
+}
+
+// String formata a variável no padrão arquivo:linha: KEY=valor.
+func (p Placeholder) String() string {
+	return fmt.Sprintf("%s:%d: %s=%s", p.File, p.Line, p.Key, p.Value)
+}
+
+/*
+FindPlaceholders lista as variáveis com valores de exemplo no resultado dos arquivos .env do ambiente
+
+Os arquivos são localizados e combinados como em LoadEnv, mas sem decifrar os valores, validar ou alterar o
+ambiente do processo; apenas o valor final de cada variável é verificado.
+
+@param opts ...Option - As opções do carregador
+
+@return []Placeholder - As variáveis encontradas, na ordem dos arquivos e das linhas
+@return error - Um erro se os arquivos não puderem ser encontrados ou lidos
+*/
+func FindPlaceholders(opts ...Option) ([]Placeholder, error) {
+	f := NewEnvLoader(opts...).(*FileEnvLoader)
+
+	files, _, err := f.layerFiles()
+	if err != nil {
+		return nil, err
+	}
+	values, origins, _, err := f.loadLayers(files)
+	if err != nil {
+		return nil, err
+	}
+
+	return placeholdersIn(values, origins), nil
+}
+
+/*
+placeholdersIn retorna as variáveis com valores de exemplo, na ordem dos arquivos e das linhas
+
+@param values map[string]string - As variáveis
+@param origins map[string]origin - A declaração de cada variável
+
+@return []Placeholder - As variáveis encontradas
+*/
+func placeholdersIn(values map[string]string, origins map[string]origin) []Placeholder {
+	var found []Placeholder
+	for key, value := range values {
+		if IsPlaceholder(value) {
+			o := origins[key]
+			found = append(found, Placeholder{Key: key, Value: value, File: o.file, Line: o.line})
+		}
+	}
+
+	fileRank := make(map[string]int)
+	for _, o := range origins {
+		if rank, ok := fileRank[o.file]; !ok || o.rank < rank {
+			fileRank[o.file] = o.rank
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].File != found[j].File {
+			return fileRank[found[i].File] < fileRank[found[j].File]
+		}
+		return found[i].Line < found[j].Line
+	})
+
+	return found
+}
+
+/*
+validatePlaceholders verifica os valores de exemplo quando WithPlaceholderCheck está habilitada
+
+@param values map[string]string - As variáveis a serem aplicadas
+@param secrets map[string]string - As variáveis classificadas como segredo
+@param origins map[string]origin - A declaração de cada variável
+
+@return []string - A descrição de cada valor de exemplo encontrado
+*/
+func (f *FileEnvLoader) validatePlaceholders(values map[string]string, secrets map[string]string, origins map[string]origin) []string {
+	if !f.placeholderCheck {
+		return nil
+	}
+
+	all := make(map[string]string, len(values)+len(secrets))
+	for key, value := range values {
+		all[key] = value
+	}
+	for key, value := range secrets {
+		all[key] = value
+	}
+
+	var problems []string
+	for _, p := range placeholdersIn(all, origins) {
+		problems = append(problems, fmt.Sprintf("variável %s (%s:%d) ainda tem o valor de exemplo %q", p.Key, p.File, p.Line, p.Value))
+	}
+
+	return problems
+}
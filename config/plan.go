@@ -0,0 +1,168 @@
+package config
+
+import (
+	"os"
+	"sort"
+)
+
+// maskedValue substitui os valores secretos em saídas destinadas a logs e relatórios.
+const maskedValue = "******"
+
+/*
+ChangeAction descreve o que LoadEnv faria com uma variável do arquivo .env
+*/
+type ChangeAction string
+
+const (
+	// ActionSet indica que a variável não existe no processo e seria definida.
+	ActionSet ChangeAction = "set"
+	// ActionUnchanged indica que a variável já existe no processo com o mesmo valor.
+	ActionUnchanged ChangeAction = "unchanged"
+	// ActionConflict indica que a variável já existe no processo com outro valor, que seria mantido.
+	ActionConflict ChangeAction = "conflict"
+	// ActionOverride indica que a variável já existe no processo com outro valor, que seria sobrescrito (FileWins).
+	ActionOverride ChangeAction = "override"
+	// ActionIsolated indica que a variável é um segredo isolado e ficaria disponível apenas via GetSecret.
+	ActionIsolated ChangeAction = "isolated"
+)
+
+/*
+PlannedChange descreve uma alteração que LoadEnv faria no ambiente do processo
+
+Key string - O nome da variável
+Action ChangeAction - O que seria feito com a variável
+Value string - O valor do arquivo .env (mascarado se for um segredo)
+Current string - O valor atual no processo, quando existir (mascarado se for um segredo)
+Secret bool - Se a variável é classificada como segredo
+*/
+type PlannedChange struct {
+	Key     string
+	Action  ChangeAction
+	Value   string
+	Current string
+	Secret  bool
+}
+
+/*
+Plan é o resultado de um carregamento simulado
+
+Files []string - Os arquivos .env que seriam carregados, na ordem em que seriam aplicados
+Env string - O ambiente correspondente ao arquivo
+RequestedEnv string - O ambiente solicitado por APP_ENV, que difere de Env quando o arquivo foi encontrado por um apelido
+Changes []PlannedChange - As alterações, ordenadas pelo nome da variável
+Warnings []string - Os avisos gerados durante a resolução
+*/
+type Plan struct {
+	Files        []string
+	Env          string
+	RequestedEnv string
+	Changes      []PlannedChange
+	Warnings     []string
+}
+
+/*
+Conflicts retorna apenas as alterações em que o processo e o arquivo .env discordam
+
+@return []PlannedChange - As alterações com ação ActionConflict
+*/
+func (p *Plan) Conflicts() []PlannedChange {
+	var conflicts []PlannedChange
+	for _, change := range p.Changes {
+		if change.Action == ActionConflict {
+			conflicts = append(conflicts, change)
+		}
+	}
+
+	return conflicts
+}
+
+/*
+Plan executa a descoberta, a leitura, a decifragem e a classificação do arquivo .env sem aplicar nada
+
+O método retorna as alterações que LoadEnv faria no ambiente do processo, incluindo os conflitos com
+variáveis já existentes. O carregador e o ambiente do processo não são modificados, e nada é gravado em disco:
+os valores de @generate não são persistidos e as variáveis de WithPrompt ainda não respondidas não são
+perguntadas, o que torna Plan adequado para verificações em CI e para depuração.
+
+@return *Plan - As alterações que seriam feitas
+@return error - Um erro se o arquivo .env não puder ser encontrado, lido ou decifrado
+*/
+func (f *FileEnvLoader) Plan() (*Plan, error) {
+	res, err := f.dryResolve()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Files: res.files, Env: res.env, RequestedEnv: res.requested, Warnings: warningMessages(res.warnings)}
+
+	for key, value := range res.values {
+		_, secret := res.secrets[key]
+		plan.Changes = append(plan.Changes, f.plannedChange(key, value, secret))
+	}
+
+	for key := range res.secrets {
+		if _, applied := res.values[key]; !applied {
+			plan.Changes = append(plan.Changes, PlannedChange{Key: key, Action: ActionIsolated, Value: maskedValue, Secret: true})
+		}
+	}
+
+	sort.Slice(plan.Changes, func(i, j int) bool {
+		return plan.Changes[i].Key < plan.Changes[j].Key
+	})
+
+	return plan, nil
+}
+
+/*
+plannedChange compara o valor do arquivo .env com o ambiente do processo, conforme a precedência do carregador
+
+@param key string - O nome da variável
+@param value string - O valor do arquivo .env
+@param secret bool - Se a variável é um segredo
+
+@return PlannedChange - A alteração planejada
+*/
+func (f *FileEnvLoader) plannedChange(key string, value string, secret bool) PlannedChange {
+	change := PlannedChange{Key: key, Action: ActionSet, Value: value, Secret: secret}
+
+	current, exists := os.LookupEnv(key)
+	if exists {
+		change.Current = current
+		switch {
+		case current == value:
+			change.Action = ActionUnchanged
+		case f.owned[key]:
+			change.Action = ActionSet
+		case f.precedence == FileWins:
+			change.Action = ActionOverride
+		default:
+			change.Action = ActionConflict
+		}
+	}
+
+	if secret {
+		change.Value = maskedValue
+		if exists {
+			change.Current = maskedValue
+		}
+	}
+
+	return change
+}
+
+/*
+sortedKeys retorna as chaves de um mapa em ordem alfabética
+
+@param values map[string]string - O mapa
+
+@return []string - As chaves ordenadas
+*/
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,439 @@
+package config
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// PluginProtocolVersion é a versão do protocolo entre o carregador e os plugins de provedores.
+const PluginProtocolVersion = 1
+
+// DefaultPluginTimeout é o tempo máximo de uma chamada a um plugin sem contexto, como Decrypt, quando Plugin.Timeout é zero.
+const DefaultPluginTimeout = 30 * time.Second
+
+// As capacidades que um plugin anuncia no handshake.
+const (
+	PluginCapDecrypt = "decrypt"
+	PluginCapEncrypt = "encrypt"
+	PluginCapHealth  = "health"
+)
+
+/*
+Provider é a interface estável implementada pelos backends de segredos de terceiros
+
+Um Provider decifra os valores "enc:<backend>:" do backend que ele atende. Se também implementar Encrypter ou
+HealthChecker, essas capacidades são expostas pelo protocolo de plugins. Um Provider pode ser registrado
+diretamente com WithDecrypter ou distribuído como um executável separado com ServePlugin, sem que o repositório
+precise ser modificado ou que as suas dependências entrem no go.sum das aplicações.
+
+Name retorna o nome do backend, usado nos logs e no handshake.
+@return string - O nome do backend
+
+Decrypt recebe o texto cifrado já decodificado de base64 e retorna o texto em claro.
+@param ciphertext []byte - O texto cifrado
+@return []byte - O texto em claro
+@return error - Um erro se o valor não puder ser decifrado
+*/
+type Provider interface {
+	Name() string
+	Decrypter
+}
+
+/*
+pluginMessage é uma mensagem do protocolo de plugins, trocada como uma linha JSON na entrada e na saída padrão
+
+As requisições usam ID, Method, Protocol e Data; as respostas repetem o ID e usam Data, Name, Protocol,
+Capabilities e Error. O conteúdo binário de Data é codificado em base64 pelo JSON.
+
+ID uint64 - O identificador da requisição, repetido na resposta
+Method string - O método: handshake, decrypt, encrypt ou health
+Protocol int - A versão do protocolo, no handshake
+Data []byte - O texto cifrado ou em claro
+Name string - O nome do backend, na resposta ao handshake
+Capabilities []string - As capacidades do plugin, na resposta ao handshake
+Error string - O erro da requisição, na resposta
+*/
+type pluginMessage struct {
+	ID           uint64   `json:"id"`
+	Method       string   `json:"method,omitempty"`
+	Protocol     int      `json:"protocol,omitempty"`
+	Data         []byte   `json:"data,omitempty"`
+	Name         string   `json:"name,omitempty"`
+	Capabilities []string `json:"capabilities,omitempty"`
+	Error        string   `json:"error,omitempty"`
+}
+
+/*
+Plugin é um Decrypter que delega a um provedor executado como um processo separado
+
+O protocolo é semelhante ao dos provedores do Terraform: o carregador inicia o executável, troca uma mensagem
+JSON por linha na entrada e na saída padrão e mantém o processo ativo entre as chamadas. A primeira mensagem é o
+handshake, em que o plugin informa a versão do protocolo, o seu nome e as suas capacidades:
+
+	→ {"id":1,"method":"handshake","protocol":1}
+	← {"id":1,"protocol":1,"name":"vault","capabilities":["decrypt","health"]}
+	→ {"id":2,"method":"decrypt","data":"<base64>"}
+	← {"id":2,"data":"<base64>"}
+
+A saída de erros do plugin é repassada à do processo. Se o plugin terminar, ele é iniciado de novo na chamada
+seguinte. Plugin também implementa Provider, Encrypter e HealthChecker, conforme as capacidades anunciadas, e é
+seguro para uso concorrente; as chamadas são serializadas.
+
+Timeout time.Duration - O tempo máximo de Name, Decrypt e Encrypt, incluindo o handshake; zero usa DefaultPluginTimeout
+*/
+type Plugin struct {
+	Timeout time.Duration
+
+	command []string
+
+	mu           sync.Mutex
+	cmd          *exec.Cmd
+	stdin        io.WriteCloser
+	stdout       *bufio.Scanner
+	nextID       uint64
+	name         string
+	capabilities map[string]bool
+}
+
+/*
+NewPlugin cria um Plugin para o executável informado, que só é iniciado na primeira chamada
+
+@param command ...string - O executável e os seus argumentos
+
+@return *Plugin - O plugin criado
+*/
+func NewPlugin(command ...string) *Plugin {
+	return &Plugin{command: command}
+}
+
+/*
+WithPlugin registra um plugin de provedor como o Decrypter do backend informado
+
+@param backend string - O nome do backend usado no marcador enc:<backend>:
+@param command ...string - O executável do plugin e os seus argumentos
+
+@return Option - A opção que registra o plugin
+*/
+func WithPlugin(backend string, command ...string) Option {
+	return WithDecrypter(backend, NewPlugin(command...))
+}
+
+/*
+Name retorna o nome anunciado no handshake, iniciando o plugin se necessário, como exige Provider
+
+@return string - O nome do backend, ou o nome do executável se o plugin não puder ser iniciado
+*/
+func (p *Plugin) Name() string {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout())
+	defer cancel()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.start(ctx); err != nil || p.name == "" {
+		if len(p.command) == 0 {
+			return ""
+		}
+		return filepath.Base(p.command[0])
+	}
+
+	return p.name
+}
+
+// Decrypt envia o texto cifrado ao plugin e retorna o texto em claro, com o prazo de Timeout.
+func (p *Plugin) Decrypt(ciphertext []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout())
+	defer cancel()
+
+	return p.call(ctx, PluginCapDecrypt, ciphertext)
+}
+
+// Encrypt envia o texto em claro ao plugin e retorna o texto cifrado, com o prazo de Timeout, se o plugin anunciar a capacidade encrypt.
+func (p *Plugin) Encrypt(plaintext []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout())
+	defer cancel()
+
+	return p.call(ctx, PluginCapEncrypt, plaintext)
+}
+
+// timeout retorna Timeout, ou DefaultPluginTimeout se ele não for positivo.
+func (p *Plugin) timeout() time.Duration {
+	if p.Timeout > 0 {
+		return p.Timeout
+	}
+
+	return DefaultPluginTimeout
+}
+
+/*
+CheckHealth verifica se o plugin responde e, se ele anunciar a capacidade health, se o backend está acessível
+
+@param ctx context.Context - O contexto da verificação, incluindo o handshake; o plugin é encerrado se o prazo terminar antes da resposta
+
+@return error - Um erro se o plugin ou o backend não responder
+*/
+func (p *Plugin) CheckHealth(ctx context.Context) error {
+	_, err := p.call(ctx, PluginCapHealth, nil)
+	return err
+}
+
+// Close encerra o processo do plugin, se ele estiver em execução.
+func (p *Plugin) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.stop()
+}
+
+/*
+call envia uma requisição ao plugin, iniciando-o se necessário, e aguarda a resposta
+
+@param ctx context.Context - O contexto da chamada
+@param method string - O método, que deve ser uma das capacidades anunciadas, exceto health
+@param data []byte - O conteúdo da requisição
+
+@return []byte - O conteúdo da resposta
+@return error - Um erro se o plugin falhar, não suportar o método ou retornar um erro
+*/
+func (p *Plugin) call(ctx context.Context, method string, data []byte) ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.start(ctx); err != nil {
+		return nil, err
+	}
+	if !p.capabilities[method] {
+		if method == PluginCapHealth {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("o plugin %s não suporta %s", p.name, method)
+	}
+
+	resp, err := p.roundTrip(ctx, pluginMessage{Method: method, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+
+	return resp.Data, nil
+}
+
+/*
+start inicia o processo do plugin e executa o handshake, se ele ainda não estiver em execução
+
+@param ctx context.Context - O contexto da chamada; o plugin é encerrado se o prazo terminar durante o handshake
+
+@return error - Um erro se o processo não puder ser iniciado ou o handshake falhar
+*/
+func (p *Plugin) start(ctx context.Context) error {
+	if p.cmd != nil {
+		return nil
+	}
+	if len(p.command) == 0 {
+		return fmt.Errorf("nenhum comando de plugin configurado")
+	}
+
+	cmd := exec.Command(p.command[0], p.command[1:]...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("erro ao iniciar o plugin %s: %w", p.command[0], err)
+	}
+
+	p.cmd, p.stdin = cmd, stdin
+	p.stdout = bufio.NewScanner(stdout)
+	p.stdout.Buffer(make([]byte, 64<<10), 16<<20)
+
+	resp, err := p.roundTrip(ctx, pluginMessage{Method: "handshake", Protocol: PluginProtocolVersion})
+	if err == nil && resp.Protocol != PluginProtocolVersion {
+		err = fmt.Errorf("versão de protocolo %d não suportada, esperada %d", resp.Protocol, PluginProtocolVersion)
+	}
+	if err != nil {
+		p.stop()
+		return fmt.Errorf("handshake com o plugin %s falhou: %w", p.command[0], err)
+	}
+
+	p.name = resp.Name
+	p.capabilities = make(map[string]bool, len(resp.Capabilities))
+	for _, capability := range resp.Capabilities {
+		p.capabilities[capability] = true
+	}
+
+	return nil
+}
+
+/*
+roundTrip escreve uma requisição e lê a resposta correspondente
+
+Se a comunicação falhar ou o contexto terminar, o processo é encerrado para ser iniciado de novo na próxima chamada.
+
+@param ctx context.Context - O contexto da chamada
+@param req pluginMessage - A requisição, sem o ID
+
+@return pluginMessage - A resposta
+@return error - Um erro se a comunicação falhar
+*/
+func (p *Plugin) roundTrip(ctx context.Context, req pluginMessage) (pluginMessage, error) {
+	p.nextID++
+	req.ID = p.nextID
+
+	line, err := json.Marshal(req)
+	if err != nil {
+		return pluginMessage{}, err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	cmd := p.cmd
+	go func() {
+		select {
+		case <-ctx.Done():
+			cmd.Process.Kill()
+		case <-done:
+		}
+	}()
+
+	var resp pluginMessage
+	if _, err = p.stdin.Write(append(line, '\n')); err == nil {
+		err = p.readResponse(req.ID, &resp)
+	}
+	if err != nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		p.stop()
+		return pluginMessage{}, fmt.Errorf("erro de comunicação com o plugin: %w", err)
+	}
+
+	return resp, nil
+}
+
+/*
+readResponse lê a próxima linha da saída do plugin e verifica se ela responde à requisição
+
+@param id uint64 - O ID da requisição
+@param resp *pluginMessage - A resposta lida
+
+@return error - Um erro se a saída terminar, não for JSON ou responder a outra requisição
+*/
+func (p *Plugin) readResponse(id uint64, resp *pluginMessage) error {
+	if !p.stdout.Scan() {
+		if err := p.stdout.Err(); err != nil {
+			return err
+		}
+		return io.ErrUnexpectedEOF
+	}
+	if err := json.Unmarshal(p.stdout.Bytes(), resp); err != nil {
+		return err
+	}
+	if resp.ID != id {
+		return fmt.Errorf("resposta %d inesperada para a requisição %d", resp.ID, id)
+	}
+
+	return nil
+}
+
+// stop encerra o processo do plugin e descarta o seu estado.
+func (p *Plugin) stop() error {
+	if p.cmd == nil {
+		return nil
+	}
+
+	p.stdin.Close()
+	err := p.cmd.Process.Kill()
+	p.cmd.Wait()
+	p.cmd, p.stdin, p.stdout = nil, nil, nil
+	if errors.Is(err, os.ErrProcessDone) {
+		err = nil
+	}
+
+	return err
+}
+
+/*
+ServePlugin atende o protocolo de plugins com o Provider informado, para ser chamado no main do executável
+
+	func main() {
+		if err := config.ServePlugin(vaultProvider{}, os.Stdin, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+As capacidades encrypt e health são anunciadas quando o Provider implementa Encrypter ou HealthChecker. Logs do
+plugin devem ser escritos na saída de erros, pois a saída padrão é reservada ao protocolo.
+
+@param provider Provider - O provedor atendido
+@param r io.Reader - A entrada das requisições, normalmente os.Stdin
+@param w io.Writer - A saída das respostas, normalmente os.Stdout
+
+@return error - nil quando a entrada termina, ou um erro de leitura ou escrita
+*/
+func ServePlugin(provider Provider, r io.Reader, w io.Writer) error {
+	capabilities := []string{PluginCapDecrypt}
+	encrypter, canEncrypt := provider.(Encrypter)
+	if canEncrypt {
+		capabilities = append(capabilities, PluginCapEncrypt)
+	}
+	checker, canCheck := provider.(HealthChecker)
+	if canCheck {
+		capabilities = append(capabilities, PluginCapHealth)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 16<<20)
+	encoder := json.NewEncoder(w)
+	for scanner.Scan() {
+		var req pluginMessage
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			return fmt.Errorf("requisição inválida: %w", err)
+		}
+
+		resp := pluginMessage{ID: req.ID}
+		var err error
+		switch req.Method {
+		case "handshake":
+			resp.Protocol, resp.Name, resp.Capabilities = PluginProtocolVersion, provider.Name(), capabilities
+		case PluginCapDecrypt:
+			resp.Data, err = provider.Decrypt(req.Data)
+		case PluginCapEncrypt:
+			if !canEncrypt {
+				err = fmt.Errorf("o provedor %s não suporta encrypt", provider.Name())
+				break
+			}
+			resp.Data, err = encrypter.Encrypt(req.Data)
+		case PluginCapHealth:
+			if canCheck {
+				err = checker.CheckHealth(context.Background())
+			}
+		default:
+			err = fmt.Errorf("método %q desconhecido", req.Method)
+		}
+		if err != nil {
+			resp.Error = err.Error()
+		}
+
+		if err := encoder.Encode(resp); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+/*
+Precedence define quem prevalece quando o ambiente do processo e o arquivo .env definem a mesma variável
+
+Orquestradores de contêineres e arquivos locais frequentemente definem as mesmas variáveis (ex.: DATABASE_URL);
+a precedência torna explícito qual valor é usado. Variáveis definidas pelo próprio carregador em um carregamento
+anterior não são consideradas do processo e são sempre atualizadas.
+*/
+type Precedence int
+
+const (
+	// ProcessWins mantém o valor do processo e ignora o do arquivo, como godotenv.Load. É o padrão.
+	ProcessWins Precedence = iota
+	// FileWins sobrescreve o valor do processo com o do arquivo.
+	FileWins
+	// ErrorOnConflict faz o carregamento falhar, sem aplicar nada, se algum valor do processo divergir do arquivo.
+	ErrorOnConflict
+)
+
+// String retorna o nome da precedência.
+func (p Precedence) String() string {
+	switch p {
+	case ProcessWins:
+		return "ProcessWins"
+	case FileWins:
+		return "FileWins"
+	case ErrorOnConflict:
+		return "ErrorOnConflict"
+	default:
+		return fmt.Sprintf("Precedence(%d)", int(p))
+	}
+}
+
+/*
+WithPrecedence define quem prevalece quando o processo e o arquivo .env divergem
+
+@param p Precedence - A precedência (ProcessWins, FileWins ou ErrorOnConflict)
+
+@return Option - A opção que define a precedência
+*/
+func WithPrecedence(p Precedence) Option {
+	return func(f *FileEnvLoader) {
+		f.precedence = p
+	}
+}
+
+/*
+Conflict descreve uma variável definida com valores diferentes no processo e no arquivo .env
+
+Key string - O nome da variável
+File string - O arquivo que declarou a variável
+Line int - A linha da declaração
+ProcessValue string - O valor do processo (mascarado se for um segredo)
+FileValue string - O valor do arquivo (mascarado se for um segredo)
+Secret bool - Se a variável é classificada como segredo
+*/
+type Conflict struct {
+	Key          string
+	File         string
+	Line         int
+	ProcessValue string
+	FileValue    string
+	Secret       bool
+}
+
+/*
+ConflictReport lista as variáveis em que o processo e o arquivo .env divergiram no último carregamento
+
+Precedence Precedence - A precedência usada para resolver os conflitos
+Conflicts []Conflict - Os conflitos, ordenados pelo nome da variável
+*/
+type ConflictReport struct {
+	Precedence Precedence
+	Conflicts  []Conflict
+}
+
+/*
+Keys retorna os nomes das variáveis em conflito
+
+@return []string - Os nomes, em ordem alfabética
+*/
+func (r ConflictReport) Keys() []string {
+	keys := make([]string, len(r.Conflicts))
+	for i, c := range r.Conflicts {
+		keys[i] = c.Key
+	}
+
+	return keys
+}
+
+/*
+ConflictError é o erro retornado com ErrorOnConflict quando o processo e o arquivo .env divergem
+
+Conflicts []Conflict - Os conflitos encontrados
+*/
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+// Error lista as variáveis em conflito e as declarações do arquivo.
+func (e *ConflictError) Error() string {
+	problems := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		problems[i] = fmt.Sprintf("%s (%s:%d)", c.Key, c.File, c.Line)
+	}
+
+	return fmt.Sprintf("variáveis definidas no processo com valores diferentes do arquivo: %s", strings.Join(problems, ", "))
+}
+
+/*
+Conflicts retorna as variáveis em que o processo e o arquivo .env divergiram no último carregamento
+
+@return ConflictReport - O relatório de conflitos
+*/
+func (f *FileEnvLoader) Conflicts() ConflictReport {
+	return ConflictReport{Precedence: f.precedence, Conflicts: append([]Conflict(nil), f.conflicts...)}
+}
+
+/*
+detectConflicts compara as variáveis resolvidas com o ambiente do processo
+
+@param res *resolution - O resultado da resolução
+
+@return []Conflict - As variáveis que o processo define com outro valor, ordenadas pelo nome
+*/
+func (f *FileEnvLoader) detectConflicts(res *resolution) []Conflict {
+	if f.noProcessEnv {
+		return nil
+	}
+
+	var conflicts []Conflict
+	for key, value := range res.values {
+		current, exists := os.LookupEnv(key)
+		if !exists || f.owned[key] || current == value {
+			continue
+		}
+
+		conflict := Conflict{Key: key, File: res.origins[key].file, Line: res.origins[key].line, ProcessValue: current, FileValue: value}
+		if _, secret := res.secrets[key]; secret {
+			conflict.Secret = true
+			conflict.ProcessValue, conflict.FileValue = maskedValue, maskedValue
+		}
+		conflicts = append(conflicts, conflict)
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Key < conflicts[j].Key
+	})
+
+	return conflicts
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+/*
+CheckEnvContent verifica o conteúdo de um arquivo .env antes de um commit
+
+São relatados, cada um com o arquivo e a linha:
+  - erros de sintaxe;
+  - conteúdo fora do estilo canônico de Format;
+  - variáveis secretas (nomes que correspondem a patterns) com valores em texto claro, exceto em arquivos de
+    exemplo como o .env.example;
+  - variáveis ausentes do arquivo de exemplo, quando exampleKeys não é nil.
+
+@param file string - O caminho do arquivo, usado nos relatos
+@param content []byte - O conteúdo do arquivo
+@param patterns []string - Os padrões de nomes de variáveis secretas, na sintaxe de path.Match
+@param exampleKeys []string - As variáveis do arquivo de exemplo, ou nil para não verificar a sincronia
+
+@return []string - Os problemas encontrados
+*/
+func CheckEnvContent(file string, content []byte, patterns []string, exampleKeys []string) []string {
+	entries, err := parseEntries(bytes.NewReader(content), file)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	var problems []string
+	if formatted, err := formatContent(content, file); err != nil {
+		problems = append(problems, fmt.Sprintf("%s: %s", file, err))
+	} else if !bytes.Equal(formatted, content) {
+		problems = append(problems, fmt.Sprintf("%s: fora do estilo canônico: execute locenv fmt %s", file, file))
+	}
+
+	if isExampleFileName(filepath.Base(file)) {
+		return problems
+	}
+
+	classifier := &FileEnvLoader{secretPatterns: patterns}
+	declared := make(map[string]bool, len(exampleKeys))
+	for _, key := range exampleKeys {
+		declared[key] = true
+	}
+	for _, e := range entries {
+		if e.value != "" && !strings.HasPrefix(e.value, encryptedPrefix) && classifier.isSecret(e.key, nil) {
+			problems = append(problems, fmt.Sprintf("%s:%d: segredo %s em texto claro: cifre o valor com locenv encrypt", file, e.line, e.key))
+		}
+		if exampleKeys != nil && !declared[e.key] {
+			problems = append(problems, fmt.Sprintf("%s:%d: variável %s ausente do arquivo de exemplo", file, e.line, e.key))
+		}
+	}
+
+	return problems
+}
+
+/*
+CheckStagedEnvFiles executa CheckEnvContent sobre os arquivos .env preparados para o próximo commit
+
+O conteúdo verificado é o do índice do git, e não o da cópia de trabalho, de modo que alterações não preparadas
+não mascaram nem causam problemas. As variáveis do arquivo de exemplo também são lidas do índice; se ele não
+estiver versionado, a sincronia não é verificada.
+
+@param dir string - Um diretório do repositório git
+@param patterns []string - Os padrões de nomes de variáveis secretas
+@param example string - O caminho do arquivo de exemplo, relativo à raiz do repositório (ex.: .env.example)
+
+@return []string - Os arquivos .env verificados, relativos à raiz do repositório
+@return []string - Os problemas encontrados
+@return error - Um erro se o git não puder ser consultado
+*/
+func CheckStagedEnvFiles(dir string, patterns []string, example string) ([]string, []string, error) {
+	out, err := gitOutput(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, nil, err
+	}
+	root := strings.TrimSpace(string(out))
+
+	out, err = gitOutput(root, "diff", "--cached", "--name-only", "--diff-filter=ACMR", "-z")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var exampleKeys []string
+	if content, err := gitOutput(root, "show", ":"+filepath.ToSlash(example)); err == nil {
+		entries, err := parseEntries(bytes.NewReader(content), example)
+		if err == nil {
+			exampleKeys = make([]string, 0, len(entries))
+			for _, e := range entries {
+				exampleKeys = append(exampleKeys, e.key)
+			}
+		}
+	}
+
+	var files, problems []string
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name == "" || !isEnvFileName(filepath.Base(name)) {
+			continue
+		}
+
+		content, err := gitOutput(root, "show", ":"+name)
+		if err != nil {
+			return nil, nil, err
+		}
+		files = append(files, name)
+		problems = append(problems, CheckEnvContent(name, content, patterns, exampleKeys)...)
+		zeroBytes(content)
+	}
+
+	return files, problems, nil
+}
+
+/*
+gitOutput executa um comando do git e retorna a sua saída padrão
+
+@param dir string - O diretório em que o git é executado
+@param args ...string - Os argumentos do git
+
+@return []byte - A saída padrão
+@return error - Um erro com a saída de erros do git, se o comando falhar
+*/
+func gitOutput(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+		}
+		return nil, err
+	}
+
+	return out, nil
+}
+
+/*
+isExampleFileName informa se o nome de um arquivo .env é o de um arquivo de exemplo
+
+@param name string - O nome do arquivo
+
+@return bool - Se o nome termina com .example, .sample ou .template
+*/
+func isExampleFileName(name string) bool {
+	for _, suffix := range []string{".example", ".sample", ".template"} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
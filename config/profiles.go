@@ -0,0 +1,55 @@
+package config
+
+/*
+WithProfileAliases define apelidos de perfil usados na descoberta do arquivo .env
+
+Cada chave do mapa é um valor de APP_ENV e os valores são os perfis alternativos aceitos, em ordem de prioridade.
+Por exemplo, WithProfileAliases(map[string][]string{"prod": {"production"}}) faz com que APP_ENV=prod
+encontre o arquivo .env.production quando não houver um .env.prod. Um arquivo com o nome exato sempre prevalece.
+
+Os apelidos comuns (prod/production, dev/development, stage/stg/staging e test/testing) já vêm configurados;
+as entradas informadas substituem as existentes para a mesma chave. A resolução é registrada em
+Result.RequestedEnv e Plan.RequestedEnv.
+
+@param aliases map[string][]string - Os perfis alternativos de cada valor de APP_ENV
+
+@return Option - A opção que define os apelidos
+*/
+func WithProfileAliases(aliases map[string][]string) Option {
+	return func(f *FileEnvLoader) {
+		for env, profiles := range aliases {
+			normalized := make([]string, 0, len(profiles))
+			for _, profile := range profiles {
+				normalized = append(normalized, normalizeEnv(profile))
+			}
+			f.profileAliases[normalizeEnv(env)] = normalized
+		}
+	}
+}
+
+/*
+profileCandidates retorna os perfis aceitos para o ambiente atual, começando pelo próprio ambiente
+
+@return []string - Os perfis aceitos, em ordem de prioridade
+*/
+func (f *FileEnvLoader) profileCandidates() []string {
+	return append([]string{f.Env}, f.profileAliases[f.Env]...)
+}
+
+/*
+profileRank retorna a prioridade de um perfil entre os candidatos
+
+@param candidates []string - Os perfis aceitos, em ordem de prioridade
+@param profile string - O perfil de um arquivo .env
+
+@return int - A posição do perfil entre os candidatos (0 para o nome exato), ou -1 se ele não for aceito
+*/
+func profileRank(candidates []string, profile string) int {
+	for i, candidate := range candidates {
+		if candidate == profile {
+			return i
+		}
+	}
+
+	return -1
+}
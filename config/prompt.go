@@ -0,0 +1,174 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ErrNoTerminal é o erro retornado por NewTerminalPrompter quando o processo não tem um terminal interativo.
+var ErrNoTerminal = errors.New("nenhum terminal interativo disponível")
+
+/*
+Prompter pede ao usuário o valor de uma variável obrigatória ausente
+
+Prompt recebe o nome da variável e se ela é um segredo, caso em que a resposta não deve ser exibida, e retorna
+o valor informado. Uma resposta vazia mantém a variável ausente.
+*/
+type Prompter interface {
+	Prompt(key string, secret bool) (string, error)
+}
+
+// PromptFunc adapta uma função ao Prompter, por exemplo para testes ou para uma interface gráfica.
+type PromptFunc func(key string, secret bool) (string, error)
+
+// Prompt chama a própria função.
+func (fn PromptFunc) Prompt(key string, secret bool) (string, error) {
+	return fn(key, secret)
+}
+
+/*
+WithPrompt pede no início do programa os valores das variáveis obrigatórias ausentes
+
+Depois de ler os arquivos e antes da validação, cada variável de WithRequired que não foi definida nos arquivos
+nem no ambiente do processo é pedida a p, normalmente o NewTerminalPrompter, para que a primeira execução de
+uma ferramenta de linha de comando não termine com um erro de configuração. As respostas são reutilizadas nos
+carregamentos seguintes do mesmo carregador. Com persist, as respostas também são gravadas na sobreposição
+local do usuário (veja LocalOverlayFile), que passa a fornecer os valores nas próximas execuções com
+WithUserOverlays. As variáveis que continuarem ausentes são reportadas pela validação, como de costume.
+
+@param p Prompter - O provedor das respostas
+@param persist bool - Se as respostas devem ser gravadas em .env.local.<usuário>
+
+@return Option - A opção que habilita as perguntas
+*/
+func WithPrompt(p Prompter, persist bool) Option {
+	return func(f *FileEnvLoader) {
+		f.prompter = p
+		f.persistPrompt = persist
+	}
+}
+
+/*
+promptMissing pede os valores das variáveis obrigatórias ausentes e os adiciona às variáveis combinadas
+
+@param values map[string]string - As variáveis combinadas
+@param origins map[string]origin - A declaração de cada variável
+@param baseFile string - O arquivo .env base, cujo diretório recebe a sobreposição local
+@param encrypted map[string]bool - As chaves cujos valores estavam cifrados
+
+@return error - Um erro se o provedor falhar ou as respostas não puderem ser gravadas
+*/
+func (f *FileEnvLoader) promptMissing(values map[string]string, origins map[string]origin, baseFile string, encrypted map[string]bool) error {
+	if f.prompter == nil {
+		return nil
+	}
+
+	target := "<resposta interativa>"
+	if f.persistPrompt {
+		local, err := f.localOverlayPath(baseFile)
+		if err != nil {
+			return err
+		}
+		target = local
+	}
+
+	answers := make(map[string]string)
+	for _, key := range f.required {
+		if f.isDefined(key, values, nil) {
+			continue
+		}
+
+		value, ok := f.prompted[key]
+		if !ok && f.dryRun {
+			continue
+		}
+		if !ok {
+			var err error
+			if value, err = f.prompter.Prompt(key, f.isSecret(key, encrypted)); err != nil {
+				return fmt.Errorf("erro ao pedir o valor de %s: %w", key, err)
+			}
+			if value == "" {
+				continue
+			}
+			if f.prompted == nil {
+				f.prompted = make(map[string]string)
+			}
+			f.prompted[key] = value
+			answers[key] = value
+		}
+
+		values[key] = value
+		origins[key] = origin{file: target, rank: len(origins)}
+	}
+
+	if f.persistPrompt && f.fsys == nil && !f.dryRun {
+		if err := SetValues(target, answers); err != nil {
+			return fmt.Errorf("erro ao gravar as respostas em %s: %w", target, err)
+		}
+	}
+
+	return nil
+}
+
+/*
+terminalPrompter é o Prompter que pergunta no terminal do processo
+
+tty *os.File - O terminal, aberto para leitura e escrita
+reader *bufio.Reader - O leitor das respostas
+*/
+type terminalPrompter struct {
+	tty    *os.File
+	reader *bufio.Reader
+}
+
+/*
+NewTerminalPrompter cria um Prompter que pergunta no terminal do processo
+
+As perguntas são feitas em /dev/tty, mesmo que a entrada e a saída padrão estejam redirecionadas, e a digitação
+dos segredos não é exibida (com stty -echo). Em processos sem terminal, como serviços, pipelines de CI e
+contêineres, e em sistemas sem /dev/tty, como o Windows, a função retorna ErrNoTerminal, e o programa pode
+seguir sem WithPrompt.
+
+@return Prompter - O provedor interativo
+@return error - ErrNoTerminal se não houver um terminal interativo
+*/
+func NewTerminalPrompter() (Prompter, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoTerminal, err)
+	}
+
+	return &terminalPrompter{tty: tty, reader: bufio.NewReader(tty)}, nil
+}
+
+// Prompt escreve o nome da variável no terminal e lê a resposta, sem exibi-la se a variável for um segredo.
+func (p *terminalPrompter) Prompt(key string, secret bool) (string, error) {
+	fmt.Fprintf(p.tty, "Valor de %s (obrigatória): ", key)
+	if secret {
+		if err := p.stty("-echo"); err != nil {
+			return "", fmt.Errorf("não foi possível ocultar a digitação: %w", err)
+		}
+		defer func() {
+			p.stty("echo")
+			fmt.Fprintln(p.tty)
+		}()
+	}
+
+	answer, err := p.reader.ReadString('\n')
+	if err != nil && answer == "" {
+		return "", err
+	}
+
+	return strings.TrimRight(answer, "\r\n"), nil
+}
+
+// stty altera o modo do terminal, por exemplo para ocultar a digitação.
+func (p *terminalPrompter) stty(mode string) error {
+	cmd := exec.Command("stty", mode)
+	cmd.Stdin = p.tty
+	return cmd.Run()
+}
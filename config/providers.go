@@ -0,0 +1,53 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// init registra os provedores e as lojas que não possuem dependências além da biblioteca padrão.
+func init() {
+	RegisterProvider("local", openLocalProvider)
+	RegisterProvider("command", openCommandProvider)
+	RegisterStore("file", openFileStore)
+	RegisterStore("command", openCommandStore)
+}
+
+/*
+openLocalProvider cria um LocalKeyDecrypter a partir da opção key, com a chave AES em base64
+
+@param options map[string]string - As opções do provedor
+
+@return Provider - O provedor criado
+@return error - Um erro se a chave não for informada ou for inválida
+*/
+func openLocalProvider(options map[string]string) (Provider, error) {
+	encoded := strings.TrimSpace(options["key"])
+	if encoded == "" {
+		return nil, fmt.Errorf("provedor local: opção key não informada")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("provedor local: chave inválida: %w", err)
+	}
+
+	return NewLocalKeyDecrypter(key)
+}
+
+/*
+openCommandProvider cria um CommandCipher a partir das opções encrypt e decrypt, com os comandos separados por espaços
+
+@param options map[string]string - As opções do provedor
+
+@return Provider - O provedor criado
+@return error - Um erro se o comando de decifragem não for informado
+*/
+func openCommandProvider(options map[string]string) (Provider, error) {
+	if strings.TrimSpace(options["decrypt"]) == "" {
+		return nil, fmt.Errorf("provedor command: opção decrypt não informada")
+	}
+
+	return CommandCipher{EncryptCommand: strings.Fields(options["encrypt"]), DecryptCommand: strings.Fields(options["decrypt"])}, nil
+}
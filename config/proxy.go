@@ -0,0 +1,223 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+/*
+ProxySettings são as configurações de proxy HTTP lidas da configuração
+
+HTTPProxy string - O proxy das requisições http (HTTP_PROXY)
+HTTPSProxy string - O proxy das requisições https (HTTPS_PROXY)
+NoProxy string - Os destinos acessados sem proxy, separados por vírgula (NO_PROXY)
+*/
+type ProxySettings struct {
+	HTTPProxy  string
+	HTTPSProxy string
+	NoProxy    string
+}
+
+/*
+GetProxySettings lê e valida HTTP_PROXY, HTTPS_PROXY e NO_PROXY, aceitando também as variantes em minúsculas
+
+Como no restante do ecossistema Go, a variante em maiúsculas prevalece quando as duas existem. Ao contrário de
+http.ProxyFromEnvironment, que lê o ambiente do processo uma única vez, as configurações vêm do carregador e
+acompanham as recargas. Os proxies sem esquema são tratados como http://; são aceitos http, https, socks5 e
+socks5h. As mensagens de erro mascaram a senha.
+
+@param r Reader - A configuração
+
+@return ProxySettings - As configurações de proxy
+@return error - Um *ValidationError se algum proxy for inválido
+*/
+func GetProxySettings(r Reader) (ProxySettings, error) {
+	get := func(key string) string {
+		if value, ok := r.Lookup(key); ok {
+			return value
+		}
+		return r.GetString(strings.ToLower(key))
+	}
+
+	p := ProxySettings{HTTPProxy: get("HTTP_PROXY"), HTTPSProxy: get("HTTPS_PROXY"), NoProxy: get("NO_PROXY")}
+
+	var problems []string
+	for key, raw := range map[string]string{"HTTP_PROXY": p.HTTPProxy, "HTTPS_PROXY": p.HTTPSProxy} {
+		if _, err := parseProxy(raw); err != nil {
+			problems = append(problems, fmt.Sprintf("variável %s: %s", key, err.Error()))
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return p, &ValidationError{Problems: problems}
+	}
+
+	return p, nil
+}
+
+/*
+parseProxy interpreta o endereço de um proxy
+
+@param raw string - O endereço, com ou sem esquema
+
+@return *url.URL - O endereço interpretado, ou nil se raw estiver vazio
+@return error - Um erro se o endereço for inválido
+*/
+func parseProxy(raw string) (*url.URL, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || !strings.Contains(raw, "://") {
+		u, err = url.Parse("http://" + raw)
+	}
+	if err != nil || u.Hostname() == "" {
+		return nil, fmt.Errorf("proxy %s inválido", RedactDSN(raw))
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+		return u, nil
+	}
+
+	return nil, fmt.Errorf("proxy %s com esquema %q não suportado", RedactDSN(raw), u.Scheme)
+}
+
+/*
+ProxyFunc retorna uma função de proxy para http.Transport.Proxy
+
+Requisições para localhost, endereços de loopback e destinos de NoProxy não usam proxy. NoProxy aceita "*",
+domínios (que também correspondem aos subdomínios, com ou sem o ponto inicial), domínios com porta,
+endereços IP e blocos CIDR.
+
+@return func(*http.Request) (*url.URL, error) - A função de proxy
+*/
+func (p ProxySettings) ProxyFunc() func(*http.Request) (*url.URL, error) {
+	httpProxy, httpErr := parseProxy(p.HTTPProxy)
+	httpsProxy, httpsErr := parseProxy(p.HTTPSProxy)
+
+	return func(req *http.Request) (*url.URL, error) {
+		proxy, err := httpProxy, httpErr
+		if req.URL.Scheme == "https" {
+			proxy, err = httpsProxy, httpsErr
+		}
+		if err != nil || proxy == nil || !p.useProxy(req.URL) {
+			return nil, err
+		}
+
+		return proxy, nil
+	}
+}
+
+/*
+useProxy informa se um destino deve ser acessado pelo proxy, conforme NoProxy
+
+@param target *url.URL - O destino da requisição
+
+@return bool - Se o proxy deve ser usado
+*/
+func (p ProxySettings) useProxy(target *url.URL) bool {
+	host := strings.ToLower(target.Hostname())
+	port := target.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443"}[target.Scheme]
+	}
+
+	ip := net.ParseIP(host)
+	if host == "localhost" || (ip != nil && ip.IsLoopback()) {
+		return false
+	}
+
+	for _, entry := range strings.Split(strings.ToLower(p.NoProxy), ",") {
+		entry = strings.TrimSpace(entry)
+		switch {
+		case entry == "":
+			continue
+		case entry == "*":
+			return false
+		}
+
+		if _, block, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && block.Contains(ip) {
+				return false
+			}
+			continue
+		}
+
+		entryHost, entryPort, err := net.SplitHostPort(entry)
+		if err != nil {
+			entryHost, entryPort = entry, ""
+		}
+		if entryPort != "" && entryPort != port {
+			continue
+		}
+		entryHost = strings.TrimPrefix(entryHost, "*")
+		if host == strings.TrimPrefix(entryHost, ".") || strings.HasSuffix(host, "."+strings.TrimPrefix(entryHost, ".")) {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+Vars retorna as variáveis de proxy nas variantes em maiúsculas e minúsculas, para serem repassadas a outros processos
+
+@return map[string]string - As variáveis definidas
+*/
+func (p ProxySettings) Vars() map[string]string {
+	vars := make(map[string]string)
+	for key, value := range map[string]string{"HTTP_PROXY": p.HTTPProxy, "HTTPS_PROXY": p.HTTPSProxy, "NO_PROXY": p.NoProxy} {
+		if value != "" {
+			vars[key] = value
+			vars[strings.ToLower(key)] = value
+		}
+	}
+
+	return vars
+}
+
+/*
+CommandEnv define variáveis apenas para um processo filho, sem alterar o ambiente do processo atual
+
+O ambiente do comando parte de cmd.Env ou, se ele estiver vazio, do ambiente do processo; as variáveis
+informadas substituem as existentes com o mesmo nome. Por exemplo, para que apenas um comando use o proxy:
+
+	proxy, _ := config.GetProxySettings(loader)
+	cmd := exec.Command("git", "fetch")
+	config.CommandEnv(cmd, proxy.Vars())
+
+@param cmd *exec.Cmd - O comando
+@param vars map[string]string - As variáveis a definir
+*/
+func CommandEnv(cmd *exec.Cmd, vars map[string]string) {
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+
+	merged := make([]string, 0, len(env)+len(vars))
+	for _, kv := range env {
+		key, _, _ := strings.Cut(kv, "=")
+		if _, ok := vars[key]; !ok {
+			merged = append(merged, kv)
+		}
+	}
+
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		merged = append(merged, key+"="+vars[key])
+	}
+
+	cmd.Env = merged
+}
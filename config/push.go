@@ -0,0 +1,388 @@
+package config
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonh-dev/go-logger/logger"
+)
+
+// Os valores padrão de PushListener.
+const (
+	DefaultPushMaxSkew    = 5 * time.Minute
+	DefaultPushRetryDelay = 3 * time.Second
+)
+
+// ChangeEvent é o tipo de evento SSE aceito por PushListener, além dos eventos sem tipo.
+const ChangeEvent = "config-changed"
+
+var (
+	// ErrInvalidSignature é retornado quando a assinatura de uma notificação não confere.
+	ErrInvalidSignature = errors.New("assinatura da notificação inválida")
+	// ErrReplayedNotification é retornado quando uma notificação já recebida, ou fora da janela de tempo, é reenviada.
+	ErrReplayedNotification = errors.New("notificação repetida ou fora da janela de tempo")
+)
+
+/*
+ChangeNotification é o aviso de que a configuração remota mudou, enviado por webhook ou SSE
+
+O mesmo JSON é usado no corpo do webhook e no campo data dos eventos SSE.
+
+ID string - O identificador único da notificação, usado na proteção contra repetição
+Timestamp int64 - O momento do envio, em segundos desde a época Unix
+Reason string - O motivo da mudança, registrado nos logs
+Signature string - O HMAC-SHA256 em hexadecimal, calculado com o segredo compartilhado, de "<n>:<id>.<timestamp>.<m>:<reason>",
+em que n e m são os tamanhos em bytes do ID e do motivo; sem os tamanhos, um ponto no ID ou no motivo permitiria
+mover texto de um campo para o outro sem invalidar a assinatura
+*/
+type ChangeNotification struct {
+	ID        string `json:"id"`
+	Timestamp int64  `json:"timestamp"`
+	Reason    string `json:"reason,omitempty"`
+	Signature string `json:"signature"`
+}
+
+/*
+SignNotification assina uma notificação com o segredo compartilhado, para uso pelos publicadores
+
+@param secret []byte - O segredo compartilhado
+@param n ChangeNotification - A notificação sem assinatura
+
+@return ChangeNotification - A notificação com o campo Signature preenchido
+*/
+func SignNotification(secret []byte, n ChangeNotification) ChangeNotification {
+	n.Signature = hex.EncodeToString(notificationMAC(secret, n))
+	return n
+}
+
+// notificationMAC calcula o HMAC-SHA256 de uma notificação, com o ID e o motivo prefixados pelos seus tamanhos.
+func notificationMAC(secret []byte, n ChangeNotification) []byte {
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "%d:%s.%d.%d:%s", len(n.ID), n.ID, n.Timestamp, len(n.Reason), n.Reason)
+	return mac.Sum(nil)
+}
+
+/*
+PushListener recebe notificações assinadas de mudança e recarrega a configuração imediatamente
+
+Em vez de aguardar a próxima verificação periódica, o servidor recebe o aviso por webhook (PushListener é um
+http.Handler) ou por uma assinatura SSE (ListenSSE) e chama Reload. Notificações com assinatura inválida, com
+horário fora de MaxSkew ou com um ID já recebido dentro dessa janela são recusadas. Um pânico em Reload é
+convertido em um *PanicError, retornado por Accept e passado a OnReload; um pânico em OnReload é entregue a
+WithErrorHandler. As recargas são serializadas: notificações simultâneas, como as de requisições paralelas ao
+webhook, nunca executam Reload ou OnReload ao mesmo tempo.
+
+Loader Loader - O carregador recarregado a cada notificação aceita
+Secret []byte - O segredo compartilhado com o publicador
+MaxSkew time.Duration - A diferença máxima aceita entre o horário da notificação e o local (padrão DefaultPushMaxSkew)
+RetryDelay time.Duration - O intervalo entre reconexões de ListenSSE, quando o servidor não informa outro (padrão DefaultPushRetryDelay)
+Client *http.Client - O cliente usado por ListenSSE (padrão http.DefaultClient)
+OnReload func(ChangeNotification, error) - Chamada após cada recarga, com o erro de Reload, ou nil
+*/
+type PushListener struct {
+	Loader     Loader
+	Secret     []byte
+	MaxSkew    time.Duration
+	RetryDelay time.Duration
+	Client     *http.Client
+	OnReload   func(ChangeNotification, error)
+
+	mu       sync.Mutex
+	seen     map[string]time.Time
+	reloadMu sync.Mutex
+}
+
+/*
+Accept verifica uma notificação e, se ela for válida, recarrega a configuração
+
+@param n ChangeNotification - A notificação recebida
+
+@return error - ErrInvalidSignature, ErrReplayedNotification ou o erro de Reload
+*/
+func (p *PushListener) Accept(n ChangeNotification) error {
+	if err := p.verify(n); err != nil {
+		return err
+	}
+
+	p.reloadMu.Lock()
+	defer p.reloadMu.Unlock()
+
+	var err error
+	if panicked := safeCall("recarga após a notificação "+n.ID, func() { err = p.Loader.Reload() }); panicked != nil {
+		err = panicked
+	}
+	if err != nil {
+		if p.logs(LogError) {
+			logger.Error(fmt.Sprintf("Erro ao recarregar a configuração após a notificação %s: %s", n.ID, err.Error()))
+		}
+	} else {
+		if p.logs(LogInfo) {
+			logger.Info(fmt.Sprintf("Configuração recarregada após a notificação %s %s", n.ID, n.Reason))
+		}
+	}
+	if p.OnReload != nil {
+		if panicked := safeCall("OnReload", func() { p.OnReload(n, err) }); panicked != nil {
+			p.reportError(panicked)
+		}
+	}
+
+	return err
+}
+
+/*
+verify confere a assinatura, o horário e o ID de uma notificação, registrando o ID como recebido
+
+@param n ChangeNotification - A notificação recebida
+
+@return error - ErrInvalidSignature ou ErrReplayedNotification, se a notificação for recusada
+*/
+func (p *PushListener) verify(n ChangeNotification) error {
+	signature, err := hex.DecodeString(n.Signature)
+	if err != nil || n.ID == "" || !hmac.Equal(signature, notificationMAC(p.Secret, n)) {
+		return ErrInvalidSignature
+	}
+
+	skew := p.MaxSkew
+	if skew <= 0 {
+		skew = DefaultPushMaxSkew
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	sent := time.Unix(n.Timestamp, 0)
+	if sent.Before(now.Add(-skew)) || sent.After(now.Add(skew)) {
+		return ErrReplayedNotification
+	}
+
+	if p.seen == nil {
+		p.seen = make(map[string]time.Time)
+	}
+	for id, at := range p.seen {
+		if at.Before(now.Add(-2 * skew)) {
+			delete(p.seen, id)
+		}
+	}
+	if _, ok := p.seen[n.ID]; ok {
+		return ErrReplayedNotification
+	}
+	p.seen[n.ID] = now
+
+	return nil
+}
+
+/*
+ServeHTTP recebe uma notificação por webhook
+
+O corpo deve ser o JSON de uma ChangeNotification, enviado com POST. A resposta é 204 quando a configuração é
+recarregada, 400 para corpos inválidos, 401 para assinaturas inválidas, 409 para notificações repetidas e 500
+quando Reload falha.
+
+@param w http.ResponseWriter - A resposta
+@param req *http.Request - A requisição
+*/
+func (p *PushListener) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var n ChangeNotification
+	if err := json.NewDecoder(io.LimitReader(req.Body, 64<<10)).Decode(&n); err != nil {
+		http.Error(w, "notificação inválida", http.StatusBadRequest)
+		return
+	}
+
+	switch err := p.Accept(n); {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, ErrInvalidSignature):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, ErrReplayedNotification):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, "erro ao recarregar a configuração", http.StatusInternalServerError)
+	}
+}
+
+/*
+ListenSSE assina um fluxo SSE de notificações e recarrega a configuração a cada notificação aceita
+
+São considerados os eventos sem tipo e os do tipo ChangeEvent, cujo campo data deve ser o JSON de uma
+ChangeNotification. Quando a conexão cai, ListenSSE se reconecta após RetryDelay, ou após o intervalo informado
+pelo servidor no campo retry. Notificações recusadas são registradas como avisos. Quando o Loader é um
+*FileEnvLoader, a assinatura também termina quando ele é encerrado com Close.
+
+@param ctx context.Context - O contexto que encerra a assinatura
+@param url string - O endereço do fluxo SSE
+
+@return error - O erro do contexto, quando ele é cancelado, ou ErrClosed
+*/
+func (p *PushListener) ListenSSE(ctx context.Context, url string) error {
+	if f, ok := p.Loader.(*FileEnvLoader); ok {
+		var stop func()
+		var err error
+		if ctx, stop, err = f.lifecycle.track(ctx); err != nil {
+			return err
+		}
+		defer stop()
+	}
+
+	delay := p.RetryDelay
+	if delay <= 0 {
+		delay = DefaultPushRetryDelay
+	}
+
+	for {
+		retry, err := p.streamSSE(ctx, url)
+		if ctx.Err() != nil {
+			return context.Cause(ctx)
+		}
+		if retry > 0 {
+			delay = retry
+		}
+		if err != nil {
+			p.warn(fmt.Sprintf("Conexão com o fluxo de notificações %s interrompida: %s", url, err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-time.After(delay):
+		}
+	}
+}
+
+/*
+streamSSE lê um fluxo SSE até que a conexão seja encerrada
+
+@param ctx context.Context - O contexto da conexão
+@param url string - O endereço do fluxo SSE
+
+@return time.Duration - O intervalo de reconexão informado pelo servidor, ou zero
+@return error - Um erro se a conexão falhar ou o servidor responder com outro status
+*/
+func (p *PushListener) streamSSE(ctx context.Context, url string) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("status inesperado %s", resp.Status)
+	}
+
+	var retry time.Duration
+	var event string
+	var data []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(data) > 0 && (event == "" || event == ChangeEvent) {
+				p.dispatchSSE(strings.Join(data, "\n"))
+			}
+			event, data = "", nil
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			data = append(data, value)
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+				retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+
+	return retry, scanner.Err()
+}
+
+// dispatchSSE interpreta o campo data de um evento SSE e o entrega a Accept.
+func (p *PushListener) dispatchSSE(data string) {
+	var n ChangeNotification
+	if err := json.Unmarshal([]byte(data), &n); err != nil {
+		p.warn(fmt.Sprintf("Notificação SSE inválida ignorada: %s", err.Error()))
+		return
+	}
+
+	if err := p.Accept(n); errors.Is(err, ErrInvalidSignature) || errors.Is(err, ErrReplayedNotification) {
+		p.warn(fmt.Sprintf("Notificação SSE %s recusada: %s", n.ID, err.Error()))
+	}
+}
+
+/*
+logs informa se o listener registra as mensagens do nível informado
+
+O nível é o do carregador, quando ele é um *FileEnvLoader, ou o de LOCENV_LOG_LEVEL.
+
+@param level LogLevel - O nível da mensagem
+
+@return bool - Se a mensagem deve ser registrada
+*/
+func (p *PushListener) logs(level LogLevel) bool {
+	if f, ok := p.Loader.(*FileEnvLoader); ok {
+		return f.logs(level)
+	}
+
+	return defaultLogLevel() <= level
+}
+
+/*
+warn entrega um aviso do listener à função de WithWarningHandler do carregador ou, sem ela, o registra no log
+
+@param message string - A mensagem do aviso
+*/
+func (p *PushListener) warn(message string) {
+	if f, ok := p.Loader.(*FileEnvLoader); ok {
+		f.warn(Warning{Kind: WarningNotification, Message: message})
+		return
+	}
+	if p.logs(LogWarning) {
+		logger.Warning(message)
+	}
+}
+
+/*
+reportError entrega um erro do listener à função de WithErrorHandler do carregador ou, sem ela, o registra no log
+
+@param err error - O erro
+*/
+func (p *PushListener) reportError(err error) {
+	if f, ok := p.Loader.(*FileEnvLoader); ok {
+		f.reportError(err)
+		return
+	}
+	if p.logs(LogError) {
+		logger.Error(err.Error())
+	}
+}
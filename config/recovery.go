@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/jonh-dev/go-logger/logger"
+)
+
+/*
+PanicError é o erro que substitui um pânico recuperado em um provedor, hook ou callback
+
+Where string - Onde o pânico ocorreu (ex.: callback de rotação de DB_PASSWORD)
+Value any - O valor passado a panic
+Stack []byte - A pilha de chamadas no momento do pânico
+*/
+type PanicError struct {
+	Where string
+	Value any
+	Stack []byte
+}
+
+// Error descreve o pânico, sem a pilha de chamadas.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("pânico recuperado em %s: %v", e.Where, e.Value)
+}
+
+// Unwrap retorna o valor do pânico quando ele é um erro, para uso com errors.Is e errors.As.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+/*
+WithErrorHandler entrega a uma função os erros das goroutines de observação e recarga
+
+O carregador garante que um pânico em um provedor, Decrypter, hook, transformação ou callback não encerra o
+processo: o pânico é recuperado e convertido em um *PanicError, com a pilha de chamadas. Nas chamadas
+síncronas, como LoadEnv, Reload e Plan, o erro é retornado normalmente. Nas goroutines em segundo plano (os
+callbacks de WithRotationHook, a verificação de WatchFrozen e as recargas de um PushListener), não há a quem
+retornar o erro, e ele é entregue a esta função; sem ela, é registrado no log. As goroutines continuam
+funcionando depois de um pânico: a próxima divergência, rotação ou notificação é tratada normalmente.
+
+@param fn func(error) - A função que recebe os erros
+
+@return Option - A opção que define a função
+*/
+func WithErrorHandler(fn func(error)) Option {
+	return func(f *FileEnvLoader) {
+		f.errorHandler = fn
+	}
+}
+
+/*
+recoverPanic converte um pânico em um *PanicError, quando chamada diretamente por defer
+
+@param where string - Onde o pânico pode ocorrer, usado na mensagem
+@param err *error - O erro de retorno, substituído pelo *PanicError
+*/
+func recoverPanic(where string, err *error) {
+	if r := recover(); r != nil {
+		*err = &PanicError{Where: where, Value: r, Stack: debug.Stack()}
+	}
+}
+
+/*
+safeCall chama uma função, convertendo um pânico em um *PanicError
+
+@param where string - Onde o pânico pode ocorrer, usado na mensagem
+@param fn func() - A função
+
+@return error - O *PanicError, ou nil se a função retornar normalmente
+*/
+func safeCall(where string, fn func()) (err error) {
+	defer recoverPanic(where, &err)
+	fn()
+
+	return nil
+}
+
+/*
+reportError entrega um erro de uma goroutine em segundo plano à função de WithErrorHandler ou o registra no log
+
+Um pânico na própria função de WithErrorHandler também é recuperado e registrado no log.
+
+@param err error - O erro
+*/
+func (f *FileEnvLoader) reportError(err error) {
+	if f.errorHandler != nil {
+		if err = safeCall("WithErrorHandler", func() { f.errorHandler(err) }); err == nil {
+			return
+		}
+	}
+	if f.logs(LogError) {
+		logger.Error(err.Error())
+	}
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+/*
+ProviderFactory cria um Provider a partir das suas opções
+
+@param options map[string]string - As opções do provedor, como endereço, região ou chave
+@return Provider - O provedor criado
+@return error - Um erro se as opções forem inválidas ou o provedor não puder ser criado
+*/
+type ProviderFactory func(options map[string]string) (Provider, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]ProviderFactory)
+)
+
+/*
+RegisterProvider registra a fábrica de um provedor com o nome informado
+
+Os provedores se registram na função init do próprio pacote, como os drivers de database/sql. Para que o núcleo
+da biblioteca continue sem dependências, os provedores que usam SDKs de nuvem (AWS, GCP, Azure, Vault) ficam em
+módulos separados, que a aplicação importa apenas quando precisa, opcionalmente atrás de uma tag de build:
+
+	//go:build locenv_vault
+
+	package main
+
+	import _ "github.com/exemplo/locenv-vault"
+
+Assim, o go.sum de quem não usa um provedor nunca inclui o seu SDK. Os provedores sem dependências (local e
+command) são registrados pelo próprio pacote config.
+
+@param name string - O nome do provedor, usado em WithProvider e OpenProvider
+@param factory ProviderFactory - A fábrica do provedor
+
+Registrar o mesmo nome duas vezes, ou uma fábrica nil, causa um panic.
+*/
+func RegisterProvider(name string, factory ProviderFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic("config: RegisterProvider com fábrica nil para " + name)
+	}
+	if _, exists := registry[name]; exists {
+		panic("config: RegisterProvider chamado duas vezes para " + name)
+	}
+	registry[name] = factory
+}
+
+/*
+Providers retorna os nomes dos provedores registrados
+
+@return []string - Os nomes, em ordem alfabética
+*/
+func Providers() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+/*
+OpenProvider cria um provedor registrado
+
+@param name string - O nome do provedor
+@param options map[string]string - As opções do provedor
+
+@return Provider - O provedor criado
+@return error - Um erro se o provedor não estiver registrado ou não puder ser criado
+*/
+func OpenProvider(name string, options map[string]string) (Provider, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("provedor %q não registrado: importe o pacote do provedor (registrados: %v)", name, Providers())
+	}
+
+	return factory(options)
+}
+
+/*
+WithProvider registra um provedor do registro como o Decrypter do backend informado
+
+O provedor é criado com OpenProvider no primeiro valor cifrado que precisar dele, de modo que provedores não
+usados não são iniciados; um erro na criação é retornado pelo carregamento como um *DecryptError.
+
+@param backend string - O nome do backend usado no marcador enc:<backend>:
+@param name string - O nome do provedor registrado
+@param options map[string]string - As opções do provedor
+
+@return Option - A opção que registra o provedor
+*/
+func WithProvider(backend string, name string, options map[string]string) Option {
+	return WithDecrypter(backend, &lazyProvider{name: name, options: options})
+}
+
+/*
+lazyProvider é um Decrypter que cria um provedor do registro na primeira chamada
+
+name string - O nome do provedor
+options map[string]string - As opções do provedor
+once sync.Once - Garante uma única criação
+provider Provider - O provedor criado
+err error - O erro da criação
+*/
+type lazyProvider struct {
+	name     string
+	options  map[string]string
+	once     sync.Once
+	provider Provider
+	err      error
+}
+
+// open cria o provedor na primeira chamada e retorna o resultado guardado nas seguintes.
+func (l *lazyProvider) open() (Provider, error) {
+	l.once.Do(func() {
+		l.provider, l.err = OpenProvider(l.name, l.options)
+	})
+
+	return l.provider, l.err
+}
+
+// Decrypt cria o provedor, se necessário, e delega a ele.
+func (l *lazyProvider) Decrypt(ciphertext []byte) ([]byte, error) {
+	provider, err := l.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.Decrypt(ciphertext)
+}
+
+// CheckHealth cria o provedor, se necessário, e delega a ele quando ele implementa HealthChecker.
+func (l *lazyProvider) CheckHealth(ctx context.Context) error {
+	provider, err := l.open()
+	if err != nil {
+		return err
+	}
+	if checker, ok := provider.(HealthChecker); ok {
+		return checker.CheckHealth(ctx)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"os"
+)
+
+// SourceProcess é a origem informada por Source quando o valor do ambiente do processo prevaleceu sobre o arquivo.
+const SourceProcess = "process"
+
+/*
+Reload repete a descoberta e o carregamento do arquivo .env
+
+As variáveis que o próprio carregador definiu em um carregamento anterior são atualizadas com os novos valores,
+e as que deixaram de existir no arquivo são removidas do ambiente do processo. Variáveis que já existiam
+no processo antes do primeiro carregamento continuam prevalecendo, exceto com FileWins; nesse caso, quando
+deixam de existir no arquivo, o valor original do processo é restaurado. Um carregador congelado com Freeze
+retorna ErrFrozen.
+
+@return error - Um erro se o arquivo .env não puder ser encontrado ou carregado, ou ErrFrozen
+*/
+func (f *FileEnvLoader) Reload() error {
+	if f.Frozen() {
+		return ErrFrozen
+	}
+
+	f.checkEnvChange()
+	res, err := f.resolveOrFallback()
+	if err != nil {
+		f.health.record(err)
+		return err
+	}
+
+	for key := range f.owned {
+		if _, ok := res.values[key]; ok {
+			continue
+		}
+		if original, ok := f.shadowed[key]; ok {
+			if err := os.Setenv(key, original); err != nil {
+				return fmt.Errorf("erro ao restaurar a variável %s: %s", key, err.Error())
+			}
+			delete(f.shadowed, key)
+		} else if err := os.Unsetenv(key); err != nil {
+			return fmt.Errorf("erro ao remover a variável %s: %s", key, err.Error())
+		}
+		delete(f.owned, key)
+	}
+
+	_, err = f.apply(res)
+	f.health.record(err)
+	if err == nil {
+		f.storeCache(res)
+		err = f.afterLoad(res.files)
+	}
+
+	return err
+}
+
+/*
+All retorna uma cópia das variáveis carregadas do arquivo .env com os seus valores efetivos
+
+Segredos isolados com WithSecretIsolation não fazem parte do resultado. Um mapa não tem ordem; use Keys para
+percorrer as variáveis em uma ordem estável.
+
+@return map[string]string - As variáveis carregadas
+*/
+func (f *FileEnvLoader) All() map[string]string {
+	all := make(map[string]string, len(f.values))
+	for key, value := range f.values {
+		all[key] = value
+	}
+
+	return all
+}
+
+/*
+Source retorna a origem do valor efetivo de uma variável carregada
+
+@param key string - O nome da variável
+
+@return string - O caminho do arquivo que definiu a variável, ou SourceProcess se o valor do processo prevaleceu
+@return bool - Se a variável foi carregada
+*/
+func (f *FileEnvLoader) Source(key string) (string, bool) {
+	source, ok := f.sources[key]
+	return source, ok
+}
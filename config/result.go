@@ -0,0 +1,67 @@
+package config
+
+/*
+Result é o resumo estruturado de um carregamento, próprio para um log de inicialização
+
+Files []string - Os arquivos carregados, na ordem em que foram aplicados
+Env string - O ambiente carregado
+RequestedEnv string - O ambiente solicitado por APP_ENV, que difere de Env quando o arquivo foi encontrado por um apelido
+Loaded int - A quantidade de variáveis definidas no ambiente do processo
+Skipped []string - As variáveis ignoradas por já existirem no processo
+Filtered []string - As variáveis dos arquivos descartadas por WithAllowKeys e WithDenyKeys
+Secrets int - A quantidade de variáveis classificadas como segredo
+Conflicts []Conflict - As variáveis que o processo definia com outro valor, resolvidas conforme a precedência
+Warnings []string - Os avisos gerados durante o carregamento
+Revision string - A revisão da configuração efetiva, para correlacionar logs com recargas
+Preview map[string]string - Os valores das variáveis de WithStartupSummary, com os segredos mascarados
+*/
+type Result struct {
+	Files        []string
+	Env          string
+	RequestedEnv string
+	Loaded       int
+	Skipped      []string
+	Filtered     []string
+	Secrets      int
+	Conflicts    []Conflict
+	Warnings     []string
+	Revision     string
+	Preview      map[string]string
+}
+
+/*
+LoadEnvResult carrega as variáveis de ambiente como LoadEnv e retorna um resumo do carregamento
+
+O resumo inclui os arquivos escolhidos, o ambiente, a quantidade de variáveis definidas, as variáveis
+ignoradas por já existirem no processo e os avisos, para que a aplicação registre um resumo estruturado
+da sua inicialização. Um carregador congelado com Freeze retorna ErrFrozen.
+
+@return *Result - O resumo do carregamento
+@return error - Um erro se o arquivo .env não puder ser encontrado, ocorrer um erro durante a busca, ou o arquivo .env não puder ser carregado
+*/
+func (f *FileEnvLoader) LoadEnvResult() (*Result, error) {
+	if f.Frozen() {
+		return nil, ErrFrozen
+	}
+
+	warning := f.checkEnvChange()
+	res, err := f.resolveOrFallback()
+	if err != nil {
+		f.health.record(err)
+		return nil, err
+	}
+
+	result, err := f.apply(res)
+	f.health.record(err)
+	if err == nil {
+		if warning != "" {
+			result.Warnings = append(result.Warnings, warning)
+			f.warnings = append(f.warnings, Warning{Kind: WarningEnvChange, Message: warning})
+		}
+		f.storeCache(res)
+		f.logSummary(result)
+		err = f.afterLoad(res.files)
+	}
+
+	return result, err
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+)
+
+const (
+	// RevisionHeader é o cabeçalho HTTP em que RevisionMiddleware informa a revisão da configuração.
+	RevisionHeader = "X-Config-Revision"
+
+	// RevisionMetadataKey é a chave de metadados gRPC equivalente a RevisionHeader.
+	RevisionMetadataKey = "x-config-revision"
+)
+
+// revisionKey é a chave do contexto que guarda a revisão da configuração.
+type revisionKey struct{}
+
+/*
+WithRevisionKey define a chave da implantação usada para calcular a revisão da configuração com HMAC-SHA256
+
+Sem a chave, a revisão é um hash público, que pode ser exposto por RevisionMiddleware; por isso, os valores dos
+segredos ficam fora dela e só os seus nomes são considerados. Com a chave, os segredos também entram no cálculo,
+de modo que uma rotação muda a revisão sem que ela possa ser usada para adivinhar o valor. Todas as instâncias de
+uma implantação devem usar a mesma chave para que as revisões sejam comparáveis.
+
+@param key []byte - A chave da implantação
+
+@return Option - A opção que define a chave
+*/
+func WithRevisionKey(key []byte) Option {
+	return func(f *FileEnvLoader) {
+		f.revisionKey = append([]byte(nil), key...)
+	}
+}
+
+/*
+configRevision calcula a revisão de uma configuração, um hash SHA-256 ou HMAC-SHA256 do ambiente e das variáveis
+
+As variáveis são ordenadas pelo nome, de modo que a mesma configuração sempre produz a mesma revisão,
+independentemente da ordem de carregamento. Sem chave, os valores dos segredos são omitidos, inclusive os que
+também estão em values; com a chave de WithRevisionKey, o hash é um HMAC e inclui os segredos, para que uma
+rotação também mude a revisão.
+
+@param key []byte - A chave de WithRevisionKey, ou nil
+@param env string - O ambiente carregado
+@param values map[string]string - As variáveis carregadas
+@param secrets map[string]string - Os segredos carregados
+
+@return string - Os 16 primeiros dígitos hexadecimais do hash
+*/
+func configRevision(key []byte, env string, values map[string]string, secrets map[string]string) string {
+	h := sha256.New()
+	if len(key) > 0 {
+		h = hmac.New(sha256.New, key)
+	}
+	h.Write([]byte(env))
+	h.Write([]byte{0})
+
+	for _, m := range []map[string]string{values, secrets} {
+		for _, name := range sortedKeys(m) {
+			h.Write([]byte(name))
+			h.Write([]byte{0})
+			if _, secret := secrets[name]; !secret || len(key) > 0 {
+				h.Write([]byte(m[name]))
+			}
+			h.Write([]byte{0})
+		}
+		h.Write([]byte{1})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+/*
+Revision retorna a revisão da configuração efetiva, que muda sempre que um carregamento altera alguma variável
+
+A revisão é um hash do ambiente e dos valores efetivos (inclusive os que vieram do processo); os segredos só
+entram no cálculo com WithRevisionKey. Ela não depende da ordem de carregamento, do caminho dos arquivos nem da máquina, de modo que duas
+instâncias com a configuração idêntica possuem a mesma revisão, como em uma implantação blue/green.
+
+@return string - A revisão, ou uma string vazia se nada foi carregado
+*/
+func (f *FileEnvLoader) Revision() string {
+	return f.revision
+}
+
+/*
+EqualRevision informa se outra configuração possui a mesma revisão que o carregador
+
+Duas configurações sem nenhum carregamento não são consideradas iguais.
+
+@param other Reader - A outra configuração, como um carregador ou uma ConfigView
+
+@return bool - Se as duas configurações são idênticas
+*/
+func (f *FileEnvLoader) EqualRevision(other Reader) bool {
+	return EqualRevision(f, other)
+}
+
+/*
+EqualRevision informa se duas configurações possuem a mesma revisão
+
+Pode ser usada por ferramentas de implantação para confirmar que duas instâncias executam a mesma configuração,
+comparando as revisões obtidas, por exemplo, do cabeçalho RevisionHeader.
+
+@param a Reader - A primeira configuração
+@param b Reader - A segunda configuração
+
+@return bool - Se as duas configurações foram carregadas e são idênticas
+*/
+func EqualRevision(a Reader, b Reader) bool {
+	if a == nil || b == nil {
+		return false
+	}
+
+	revision := a.Revision()
+	return revision != "" && revision == b.Revision()
+}
+
+/*
+ContextWithRevision retorna uma cópia do contexto com a revisão da configuração
+
+Interceptadores gRPC podem usá-la junto com RevisionMetadataKey, sem que a biblioteca dependa do gRPC:
+
+	func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		rev := loader.Revision()
+		grpc.SetHeader(ctx, metadata.Pairs(config.RevisionMetadataKey, rev))
+		return handler(config.ContextWithRevision(ctx, rev), req)
+	}
+
+@param ctx context.Context - O contexto original
+@param revision string - A revisão da configuração
+
+@return context.Context - O contexto com a revisão
+*/
+func ContextWithRevision(ctx context.Context, revision string) context.Context {
+	return context.WithValue(ctx, revisionKey{}, revision)
+}
+
+/*
+RevisionFromContext retorna a revisão da configuração guardada no contexto, para ser incluída nos logs
+
+@param ctx context.Context - O contexto da requisição
+
+@return string - A revisão, ou uma string vazia se o contexto não possuir uma
+*/
+func RevisionFromContext(ctx context.Context) string {
+	revision, _ := ctx.Value(revisionKey{}).(string)
+	return revision
+}
+
+/*
+RevisionMiddleware cria um middleware HTTP que expõe a revisão da configuração em cada resposta
+
+A revisão vigente no início da requisição é escrita no cabeçalho RevisionHeader e guardada no contexto da
+requisição, de onde os logs podem obtê-la com RevisionFromContext. Assim, mudanças de comportamento podem ser
+correlacionadas com as recargas de configuração em toda a frota.
+
+@param r Reader - A configuração, como um carregador ou uma ConfigView
+@param next http.Handler - O handler seguinte
+
+@return http.Handler - O handler com o middleware
+*/
+func RevisionMiddleware(r Reader, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		revision := r.Revision()
+		if revision != "" {
+			w.Header().Set(RevisionHeader, revision)
+		}
+		next.ServeHTTP(w, req.WithContext(ContextWithRevision(req.Context(), revision)))
+	})
+}
@@ -0,0 +1,245 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+QuoteValue formata um valor para ser escrito em um arquivo .env
+
+O valor é escrito sem aspas quando isso é seguro. Caso contrário, é envolvido em aspas duplas, com '\', '"', '$'
+e as quebras de linha escapados, de modo que a leitura do arquivo devolva exatamente o valor original.
+
+@param value string - O valor
+
+@return string - O valor pronto para ser escrito após o sinal de igual
+*/
+func QuoteValue(value string) string {
+	if !strings.ContainsAny(value, " \t#\"'$\\\n\r") {
+		return value
+	}
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "\n", `\n`, "\r", `\r`).Replace(value) + `"`
+}
+
+/*
+RewriteFile reescreve no próprio arquivo os valores de variáveis de um arquivo .env
+
+A função fn é chamada para cada declaração, na ordem do arquivo, com o nome e o valor interpretado da variável,
+e decide se o valor deve ser substituído. Apenas os valores substituídos mudam: comentários, linhas em branco,
+o prefixo "export", o separador e os comentários no fim da linha são preservados. O novo conteúdo é gravado em
+um arquivo temporário e renomeado sobre o original, mantendo as permissões, para que uma falha não deixe o
+arquivo pela metade.
+
+@param file string - O caminho do arquivo .env
+@param fn func(key, value string) (string, bool, error) - Retorna o novo valor e se ele deve substituir o atual
+
+@return int - A quantidade de valores substituídos
+@return error - Um erro se o arquivo não puder ser lido, interpretado ou gravado, ou o erro retornado por fn
+*/
+func RewriteFile(file string, fn func(key, value string) (string, bool, error)) (int, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return 0, err
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+	defer zeroBytes(content)
+
+	entries, err := parseEntries(bytes.NewReader(content), file)
+	if err != nil {
+		return 0, err
+	}
+
+	replacements := make(map[int]string)
+	for _, e := range entries {
+		value, change, err := fn(e.key, e.value)
+		if err != nil {
+			return 0, fmt.Errorf("variável %s (%s:%d): %w", e.key, file, e.line, err)
+		}
+		if change {
+			replacements[e.line] = value
+		}
+	}
+	if len(replacements) == 0 {
+		return 0, nil
+	}
+
+	lines := strings.SplitAfter(string(content), "\n")
+	var out strings.Builder
+	out.Grow(len(content))
+
+	next := 0
+	for _, e := range entries {
+		value, ok := replacements[e.line]
+		if !ok {
+			continue
+		}
+		for ; next < e.line-1; next++ {
+			out.WriteString(lines[next])
+		}
+		out.WriteString(rewriteDeclaration(lines[e.line-1:e.endLine], e, value))
+		next = e.endLine
+	}
+	for ; next < len(lines); next++ {
+		out.WriteString(lines[next])
+	}
+
+	if err := writeFileAtomic(file, []byte(out.String()), info.Mode().Perm()); err != nil {
+		return 0, err
+	}
+
+	return len(replacements), nil
+}
+
+/*
+SetValues define variáveis em um arquivo .env, criando o arquivo se necessário
+
+As variáveis já declaradas têm o valor substituído com RewriteFile, preservando o restante do arquivo, e as
+demais são acrescentadas ao fim, em ordem alfabética. Um arquivo novo é criado com permissão 0600, já que
+costuma receber valores locais e segredos.
+
+@param file string - O caminho do arquivo .env
+@param values map[string]string - As variáveis e os novos valores
+
+@return error - Um erro se o arquivo não puder ser lido, interpretado ou gravado
+*/
+func SetValues(file string, values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	defer func() { zeroBytes(content) }()
+
+	declared := make(map[string]bool)
+	if err == nil {
+		_, err = RewriteFile(file, func(key, value string) (string, bool, error) {
+			newValue, ok := values[key]
+			declared[key] = declared[key] || ok
+			return newValue, ok && newValue != value, nil
+		})
+		if err != nil {
+			return err
+		}
+		if content, err = os.ReadFile(file); err != nil {
+			return err
+		}
+	}
+
+	var missing []string
+	for key := range values {
+		if !declared[key] {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+
+	var b strings.Builder
+	b.Write(content)
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		b.WriteByte('\n')
+	}
+	for _, key := range missing {
+		fmt.Fprintf(&b, "%s=%s\n", key, QuoteValue(values[key]))
+	}
+
+	perm := os.FileMode(0o600)
+	if info, err := os.Stat(file); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	return writeFileAtomic(file, []byte(b.String()), perm)
+}
+
+/*
+writeFileAtomic grava um arquivo em um arquivo temporário no mesmo diretório e o renomeia sobre o original
+
+@param file string - O caminho do arquivo
+@param content []byte - O novo conteúdo
+@param perm os.FileMode - As permissões do arquivo gravado
+
+@return error - Um erro se o arquivo temporário não puder ser gravado ou renomeado
+*/
+func writeFileAtomic(file string, content []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".locenv-rewrite-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), file)
+}
+
+/*
+rewriteDeclaration substitui o valor de uma declaração, preservando o que vem antes dele e o comentário no fim da linha
+
+@param lines []string - As linhas da declaração, com as quebras de linha
+@param e entry - A declaração interpretada
+@param value string - O novo valor
+
+@return string - A declaração reescrita
+*/
+func rewriteDeclaration(lines []string, e entry, value string) string {
+	first, last := lines[0], lines[len(lines)-1]
+
+	body := strings.TrimRight(last, "\r\n")
+	ending := last[len(body):]
+
+	keyEnd := strings.Index(first, e.key) + len(e.key)
+	valueStart := keyEnd + strings.IndexAny(first[keyEnd:], "=:") + 1
+	valueStart = skipInlineSpace(first, valueStart)
+
+	var rest string
+	switch {
+	case e.quote != 0:
+		from := 0
+		if len(lines) == 1 {
+			from = valueStart + 1
+		}
+		if end := closingQuote(body, from, e.quote); end >= 0 {
+			rest = body[end+1:]
+		}
+	case len(body) > valueStart:
+		raw := body[valueStart:]
+		for i := 1; i < len(raw); i++ {
+			if raw[i] == '#' && spaceBefore(raw, i) {
+				j := i
+				for j > 0 && spaceBefore(raw, j) {
+					_, size := utf8.DecodeLastRuneInString(raw[:j])
+					j -= size
+				}
+				rest = raw[j:]
+				break
+			}
+		}
+	}
+
+	return first[:valueStart] + QuoteValue(value) + rest + ending
+}
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"path"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultRotationDebounce é o intervalo padrão em que rotações seguidas de um mesmo segredo são agrupadas.
+const DefaultRotationDebounce = time.Second
+
+/*
+SecretRotation descreve a troca do valor de um segredo entre dois carregamentos
+
+Key string - O nome do segredo
+Old string - O valor anterior
+New string - O novo valor, vazio se o segredo foi removido
+Removed bool - Se o segredo deixou de existir
+*/
+type SecretRotation struct {
+	Key     string
+	Old     string
+	New     string
+	Removed bool
+}
+
+/*
+rotationHooks guarda os callbacks de rotação e as notificações aguardando o fim do intervalo de agrupamento
+
+mu sync.Mutex - Protege pending e closed
+debounce time.Duration - O intervalo de agrupamento
+hooks []rotationHook - Os callbacks registrados
+pending map[string]*pendingRotation - As rotações ainda não entregues, por segredo
+report func(error) - A função que recebe os pânicos recuperados nos callbacks
+wg sync.WaitGroup - As entregas agendadas ou em andamento, aguardadas por Close
+closed bool - Se o carregador foi encerrado; novas rotações são descartadas
+*/
+type rotationHooks struct {
+	mu       sync.Mutex
+	debounce time.Duration
+	hooks    []rotationHook
+	pending  map[string]*pendingRotation
+	report   func(error)
+	wg       sync.WaitGroup
+	closed   bool
+}
+
+type rotationHook struct {
+	pattern string
+	fn      func(SecretRotation)
+}
+
+type pendingRotation struct {
+	rotation SecretRotation
+	timer    *time.Timer
+}
+
+/*
+WithRotationHook registra um callback chamado quando o valor de um segredo muda em um novo carregamento
+
+Sempre que Reload (ou um provedor remoto) traz um valor diferente para um segredo já carregado, ou o remove,
+os callbacks cujo padrão (sintaxe de path.Match) corresponde ao nome do segredo são chamados com os valores
+antigo e novo, por exemplo para recriar um pool de conexões sem reiniciar o processo. Rotações seguidas de um
+mesmo segredo dentro do intervalo de WithRotationDebounce são agrupadas em uma única chamada, com o primeiro
+valor antigo e o último valor novo. Os callbacks são chamados em outra goroutine; segredos que aparecem pela
+primeira vez não são rotações. Um pânico em um callback é recuperado e entregue a WithErrorHandler.
+
+@param pattern string - O padrão de nomes dos segredos observados
+@param fn func(SecretRotation) - O callback
+
+@return Option - A opção que registra o callback
+*/
+func WithRotationHook(pattern string, fn func(SecretRotation)) Option {
+	return func(f *FileEnvLoader) {
+		f.rotationHooks().hooks = append(f.rotationHooks().hooks, rotationHook{pattern: pattern, fn: fn})
+	}
+}
+
+/*
+WithRotationDebounce define o intervalo em que rotações seguidas de um mesmo segredo são agrupadas
+
+@param d time.Duration - O intervalo; zero entrega cada rotação imediatamente (padrão: DefaultRotationDebounce)
+
+@return Option - A opção que define o intervalo
+*/
+func WithRotationDebounce(d time.Duration) Option {
+	return func(f *FileEnvLoader) {
+		f.rotationHooks().debounce = d
+	}
+}
+
+// rotationHooks retorna os callbacks de rotação do carregador, criando-os na primeira chamada.
+func (f *FileEnvLoader) rotationHooks() *rotationHooks {
+	if f.rotation == nil {
+		f.rotation = &rotationHooks{debounce: DefaultRotationDebounce, pending: make(map[string]*pendingRotation), report: f.reportError}
+	}
+
+	return f.rotation
+}
+
+/*
+notifyRotations compara os segredos de dois carregamentos e agenda os callbacks das rotações encontradas
+
+@param old map[string]string - Os segredos do carregamento anterior
+@param current map[string]string - Os segredos do novo carregamento
+*/
+func (f *FileEnvLoader) notifyRotations(old map[string]string, current map[string]string) {
+	if f.rotation == nil || len(f.rotation.hooks) == 0 {
+		return
+	}
+
+	for key, previous := range old {
+		value, exists := current[key]
+		if exists && value == previous {
+			continue
+		}
+		f.rotation.schedule(SecretRotation{Key: key, Old: previous, New: value, Removed: !exists})
+	}
+}
+
+/*
+schedule agrupa uma rotação com as pendentes do mesmo segredo e reinicia o intervalo de agrupamento
+
+@param r SecretRotation - A rotação encontrada
+*/
+func (h *rotationHooks) schedule(r SecretRotation) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return
+	}
+	if p, ok := h.pending[r.Key]; ok {
+		if p.timer.Stop() {
+			h.wg.Done()
+		}
+		r.Old = p.rotation.Old
+	}
+	if r.Old == r.New && !r.Removed {
+		// O segredo voltou ao valor original dentro do intervalo.
+		delete(h.pending, r.Key)
+		return
+	}
+
+	p := &pendingRotation{rotation: r}
+	h.wg.Add(1)
+	p.timer = time.AfterFunc(h.debounce, func() {
+		defer h.wg.Done()
+		h.deliver(p)
+	})
+	h.pending[r.Key] = p
+}
+
+/*
+deliver entrega uma rotação aos callbacks cujo padrão corresponde ao segredo
+
+@param p *pendingRotation - A rotação pendente cujo intervalo terminou
+*/
+func (h *rotationHooks) deliver(p *pendingRotation) {
+	h.mu.Lock()
+	if h.pending[p.rotation.Key] != p {
+		h.mu.Unlock()
+		return
+	}
+	delete(h.pending, p.rotation.Key)
+	hooks := h.hooks
+	h.mu.Unlock()
+
+	for _, hook := range hooks {
+		if matched, _ := path.Match(hook.pattern, p.rotation.Key); matched {
+			if err := safeCall("callback de rotação de "+p.rotation.Key, func() { hook.fn(p.rotation) }); err != nil && h.report != nil {
+				h.report(err)
+			}
+		}
+	}
+}
+
+/*
+flush entrega imediatamente as rotações pendentes, em ordem alfabética, e descarta as rotações seguintes
+
+As entregas que já estavam em andamento não são aguardadas; Close as aguarda pelo WaitGroup.
+*/
+func (h *rotationHooks) flush() {
+	h.mu.Lock()
+	h.closed = true
+	var due []*pendingRotation
+	for _, p := range h.pending {
+		if p.timer.Stop() {
+			due = append(due, p)
+		}
+	}
+	h.mu.Unlock()
+
+	sort.Slice(due, func(i, j int) bool { return due[i].rotation.Key < due[j].rotation.Key })
+	for _, p := range due {
+		h.deliver(p)
+		h.wg.Done()
+	}
+}
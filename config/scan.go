@@ -0,0 +1,206 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Tipos de credenciais relatados por ScanValue.
+const (
+	CredentialAWSAccessKey = "aws-access-key"
+	CredentialAWSSecretKey = "aws-secret-key"
+	CredentialJWT          = "jwt"
+	CredentialPrivateKey   = "private-key"
+	CredentialDSNPassword  = "dsn-password"
+	CredentialHighEntropy  = "high-entropy"
+)
+
+var (
+	awsAccessKeyPattern = regexp.MustCompile(`\b(AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA)[0-9A-Z]{16}\b`)
+	awsSecretKeyPattern = regexp.MustCompile(`^[A-Za-z0-9/+]{40}$`)
+	jwtPattern          = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`)
+	privateKeyPattern   = regexp.MustCompile(`-----BEGIN ([A-Z]+ )*PRIVATE KEY( BLOCK)?-----`)
+	hexPattern          = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+)
+
+/*
+CredentialFinding é um valor que parece ser uma credencial real, encontrado por ScanContent
+
+File string - O caminho do arquivo
+Line int - A linha da declaração
+Key string - O nome da variável
+Kind string - O tipo da credencial, uma das constantes Credential*
+*/
+type CredentialFinding struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Key  string `json:"key"`
+	Kind string `json:"kind"`
+}
+
+// String descreve o achado em uma linha, sem o valor.
+func (c CredentialFinding) String() string {
+	return fmt.Sprintf("%s:%d: %s parece conter uma credencial real (%s)", c.File, c.Line, c.Key, c.Kind)
+}
+
+/*
+ScanValue verifica se um valor parece ser uma credencial real, independentemente do nome da variável
+
+Ao contrário da classificação por nome (WithSecretKeys), a verificação olha apenas para o conteúdo, e por isso
+encontra credenciais em variáveis com nomes inocentes. São reconhecidos, nesta ordem:
+  - chaves de acesso da AWS (AKIA..., ASIA...) e chaves secretas da AWS (40 caracteres base64 de alta entropia);
+  - tokens JWT (três partes base64url, as duas primeiras iniciadas por "eyJ");
+  - cabeçalhos de chaves privadas PEM (-----BEGIN ... PRIVATE KEY-----);
+  - strings de conexão com senha (veja RedactDSN);
+  - strings longas de alta entropia: ao menos 20 caracteres sem espaços e entropia de Shannon de ao menos 4 bits
+    por caractere, ou ao menos 32 dígitos hexadecimais com 3 bits por caractere.
+
+Valores cifrados (enc:<backend>:) e valores com referências ${VAR} nunca são relatados.
+
+@param value string - O valor
+
+@return string - O tipo da credencial, uma das constantes Credential*
+@return bool - Se o valor parece ser uma credencial
+*/
+func ScanValue(value string) (string, bool) {
+	if value == "" || strings.HasPrefix(value, encryptedPrefix) || strings.Contains(value, "${") {
+		return "", false
+	}
+
+	switch {
+	case awsAccessKeyPattern.MatchString(value):
+		return CredentialAWSAccessKey, true
+	case awsSecretKeyPattern.MatchString(value) && shannonEntropy(value) >= 4:
+		return CredentialAWSSecretKey, true
+	case jwtPattern.MatchString(value):
+		return CredentialJWT, true
+	case privateKeyPattern.MatchString(value):
+		return CredentialPrivateKey, true
+	case RedactDSN(value) != value:
+		return CredentialDSNPassword, true
+	case isHighEntropy(value):
+		return CredentialHighEntropy, true
+	}
+
+	return "", false
+}
+
+/*
+isHighEntropy informa se um valor é uma string longa e aleatória o bastante para ser uma credencial
+
+URLs e caminhos são ignorados, pois a sua entropia é alta sem que sejam segredos.
+
+@param value string - O valor
+
+@return bool - Se o valor possui alta entropia
+*/
+func isHighEntropy(value string) bool {
+	if len(value) < 20 || strings.ContainsAny(value, " \t\n") || strings.Contains(value, "://") || strings.HasPrefix(value, "/") || strings.HasPrefix(value, "./") {
+		return false
+	}
+
+	if hexPattern.MatchString(value) {
+		return len(value) >= 32 && shannonEntropy(value) >= 3
+	}
+
+	return shannonEntropy(value) >= 4
+}
+
+/*
+shannonEntropy calcula a entropia de Shannon de uma string, em bits por caractere
+
+@param value string - A string
+
+@return float64 - A entropia
+*/
+func shannonEntropy(value string) float64 {
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range value {
+		counts[r]++
+		total++
+	}
+
+	entropy := 0.0
+	for _, n := range counts {
+		p := float64(n) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy
+}
+
+/*
+ScanContent procura, com ScanValue, valores que parecem credenciais reais no conteúdo de um arquivo .env
+
+@param file string - O caminho do arquivo, usado nos achados
+@param content []byte - O conteúdo do arquivo
+
+@return []CredentialFinding - Os achados, na ordem do arquivo
+@return error - Um *ParseError se o conteúdo possuir uma sintaxe inválida
+*/
+func ScanContent(file string, content []byte) ([]CredentialFinding, error) {
+	entries, err := parseEntries(bytes.NewReader(content), file)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []CredentialFinding
+	for _, e := range entries {
+		if kind, ok := ScanValue(e.value); ok {
+			findings = append(findings, CredentialFinding{File: file, Line: e.line, Key: e.key, Kind: kind})
+		}
+	}
+
+	return findings, nil
+}
+
+/*
+ScanTrackedFiles procura credenciais reais nos arquivos .env versionados pelo git
+
+São verificados os arquivos chamados .env ou iniciados por ".env." que o git versiona, inclusive os arquivos de
+exemplo, onde uma credencial real costuma ser esquecida. O conteúdo verificado é o da cópia de trabalho.
+Arquivos com sintaxe inválida são ignorados.
+
+@param dir string - Um diretório do repositório git
+
+@return []CredentialFinding - Os achados, com os caminhos relativos à raiz do repositório
+@return error - Um erro se o git não puder ser consultado
+*/
+func ScanTrackedFiles(dir string) ([]CredentialFinding, error) {
+	out, err := gitOutput(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	root := strings.TrimSpace(string(out))
+
+	out, err = gitOutput(root, "ls-files", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []CredentialFinding
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name == "" || !isEnvFileName(filepath.Base(name)) {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(name)))
+		if err != nil {
+			continue
+		}
+		found, err := ScanContent(name, content)
+		zeroBytes(content)
+		if err != nil {
+			continue
+		}
+		findings = append(findings, found...)
+	}
+
+	return findings, nil
+}
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// schemaTypes são os tipos aceitos pela anotação @type, com a validação de cada um.
+var schemaTypes = map[string]func(string) bool{
+	"string":   func(string) bool { return true },
+	"int":      func(v string) bool { _, err := strconv.Atoi(v); return err == nil },
+	"float":    func(v string) bool { _, err := strconv.ParseFloat(v, 64); return err == nil },
+	"bool":     func(v string) bool { _, err := strconv.ParseBool(v); return err == nil },
+	"duration": func(v string) bool { _, err := time.ParseDuration(v); return err == nil },
+	"url": func(v string) bool {
+		u, err := url.Parse(v)
+		return err == nil && u.Scheme != "" && u.Host != ""
+	},
+}
+
+/*
+KeySpec descreve uma variável declarada em um esquema, como o .env.example
+
+Key string - O nome da variável
+Type string - O tipo declarado com @type (string, int, float, bool, duration ou url), ou vazio
+Description string - A descrição declarada com @description ou, na falta dela, os demais comentários
+Example string - O valor de exemplo declarado no esquema
+Secret bool - Se a variável foi anotada com @secret
+Required bool - Se a variável é obrigatória
+Line int - A linha da declaração no esquema
+*/
+type KeySpec struct {
+	Key         string
+	Type        string
+	Description string
+	Example     string
+	Secret      bool
+	Required    bool
+	Line        int
+}
+
+/*
+LoadSchema lê as variáveis e as anotações de um arquivo de esquema, como o .env.example
+
+As anotações são comentários imediatamente acima da declaração:
+
+	# URL do banco de dados principal
+	# @required
+	# @secret
+	# @type url
+	DATABASE_URL=postgres://localhost/app
+
+São aceitas @secret, @required, @optional, @type <tipo> e @description <texto>; os comentários sem @ formam a
+descrição quando @description não é usada. Para manter a compatibilidade com esquemas sem anotações, em que
+todas as variáveis são obrigatórias, as variáveis só deixam de ser obrigatórias quando o arquivo usa @required
+em alguma delas ou quando são anotadas com @optional.
+
+@param path string - O caminho do esquema
+
+@return []KeySpec - As variáveis, na ordem do arquivo
+@return error - Um erro se o arquivo não puder ser lido ou possuir uma anotação inválida
+*/
+func LoadSchema(path string) ([]KeySpec, error) {
+	entries, err := (&FileEnvLoader{}).loadEnvFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := make([]KeySpec, 0, len(entries))
+	explicit := false
+	optional := make(map[string]bool)
+	for _, e := range entries {
+		spec := KeySpec{Key: e.key, Example: e.value, Line: e.line}
+
+		var description []string
+		for _, line := range strings.Split(e.comment, "\n") {
+			if !strings.HasPrefix(line, "@") {
+				if line != "" {
+					description = append(description, line)
+				}
+				continue
+			}
+
+			name, arg, _ := strings.Cut(line[1:], " ")
+			arg = strings.TrimSpace(arg)
+			switch name {
+			case "secret":
+				spec.Secret = true
+			case "required":
+				spec.Required, explicit = true, true
+			case "optional":
+				optional[e.key] = true
+			case "type":
+				if _, ok := schemaTypes[arg]; !ok {
+					return nil, fmt.Errorf("%s:%d: tipo %q desconhecido em %s", path, e.line, arg, e.key)
+				}
+				spec.Type = arg
+			case "description":
+				spec.Description = arg
+			default:
+				return nil, fmt.Errorf("%s:%d: anotação @%s desconhecida em %s", path, e.line, name, e.key)
+			}
+		}
+		if spec.Description == "" {
+			spec.Description = strings.Join(description, " ")
+		}
+
+		specs = append(specs, spec)
+	}
+
+	if !explicit {
+		for i := range specs {
+			specs[i].Required = !optional[specs[i].Key]
+		}
+	}
+
+	return specs, nil
+}
+
+/*
+WithSchema aplica as regras de um esquema ao carregador
+
+As variáveis obrigatórias são exigidas como em WithRequired, as anotadas com @secret são classificadas como
+segredo e os valores das variáveis com @type são validados durante o carregamento, com os problemas reunidos no
+*ValidationError do carregamento.
+
+@param specs []KeySpec - As variáveis do esquema, como as retornadas por LoadSchema
+
+@return Option - A opção que aplica o esquema
+*/
+func WithSchema(specs []KeySpec) Option {
+	return func(f *FileEnvLoader) {
+		for _, spec := range specs {
+			if spec.Required {
+				f.required = append(f.required, spec.Key)
+			}
+			if spec.Secret {
+				f.secretPatterns = append(f.secretPatterns, spec.Key)
+			}
+			if spec.Type != "" {
+				f.schema = append(f.schema, spec)
+			}
+		}
+	}
+}
+
+/*
+validateTypes verifica os valores das variáveis tipadas pelo esquema
+
+@param values map[string]string - As variáveis a serem aplicadas
+@param secrets map[string]string - As variáveis classificadas como segredo
+
+@return []string - Os problemas encontrados
+*/
+func (f *FileEnvLoader) validateTypes(values map[string]string, secrets map[string]string) []string {
+	var problems []string
+	for _, spec := range f.schema {
+		value, ok := values[spec.Key]
+		if !ok {
+			value, ok = secrets[spec.Key]
+		}
+		if ok && !schemaTypes[spec.Type](value) {
+			problems = append(problems, fmt.Sprintf("variável %s: o valor não é do tipo %s", spec.Key, spec.Type))
+		}
+	}
+
+	return problems
+}
+
+/*
+SchemaMarkdown gera a documentação das variáveis de um esquema, em uma tabela Markdown
+
+Os valores de exemplo das variáveis secretas não são incluídos.
+
+@param specs []KeySpec - As variáveis do esquema
+
+@return string - A tabela Markdown
+*/
+func SchemaMarkdown(specs []KeySpec) string {
+	var b strings.Builder
+	b.WriteString("| Variável | Tipo | Obrigatória | Exemplo | Descrição |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+
+	for _, spec := range specs {
+		typ, required, example := spec.Type, "não", "`"+spec.Example+"`"
+		if typ == "" {
+			typ = "string"
+		}
+		if spec.Required {
+			required = "sim"
+		}
+		switch {
+		case spec.Secret:
+			example = "(segredo)"
+		case spec.Example == "":
+			example = ""
+		}
+		description := strings.ReplaceAll(spec.Description, "|", "\\|")
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s |\n", spec.Key, typ, required, example, description)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"path"
+)
+
+/*
+WithSecretKeys classifica como segredo as variáveis cujo nome corresponde a algum dos padrões informados
+
+Os padrões seguem a sintaxe de path.Match (ex.: "*_PASSWORD", "API_KEY"). As variáveis cujos valores
+estavam cifrados no arquivo .env são sempre classificadas como segredo, independentemente dos padrões.
+
+@param patterns ...string - Os padrões de nomes de variáveis secretas
+
+@return Option - A opção que registra os padrões
+*/
+func WithSecretKeys(patterns ...string) Option {
+	return func(f *FileEnvLoader) {
+		f.secretPatterns = append(f.secretPatterns, patterns...)
+	}
+}
+
+/*
+WithSecretIsolation impede que os segredos sejam aplicados ao ambiente do processo
+
+Com esta opção, os segredos ficam disponíveis apenas através de GetSecret, não aparecendo em os.Environ,
+em /proc/<pid>/environ nem no ambiente herdado por processos filhos.
+
+@return Option - A opção que ativa o isolamento de segredos
+*/
+func WithSecretIsolation() Option {
+	return func(f *FileEnvLoader) {
+		f.isolateSecrets = true
+	}
+}
+
+/*
+isSecret verifica se uma variável deve ser classificada como segredo
+
+@param key string - O nome da variável
+@param encrypted map[string]bool - As chaves cujos valores estavam cifrados
+
+@return bool - Se a variável é um segredo
+*/
+func (f *FileEnvLoader) isSecret(key string, encrypted map[string]bool) bool {
+	return encrypted[key] || matchesAny(f.secretPatterns, key)
+}
+
+// matchesAny informa se o nome de uma variável corresponde a algum dos padrões de path.Match.
+func matchesAny(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+separateSecrets separa os segredos das variáveis lidas do arquivo .env
+
+Todos os segredos são retornados para que fiquem disponíveis via GetSecret. Com WithSecretIsolation,
+eles também são removidos do mapa de variáveis, para que não sejam aplicados ao ambiente do processo.
+
+@param values map[string]string - As variáveis lidas do arquivo .env
+@param encrypted map[string]bool - As chaves cujos valores estavam cifrados
+
+@return map[string]string - Os segredos encontrados
+*/
+func (f *FileEnvLoader) separateSecrets(values map[string]string, encrypted map[string]bool) map[string]string {
+	secrets := make(map[string]string)
+
+	for key, value := range values {
+		if !f.isSecret(key, encrypted) {
+			continue
+		}
+
+		secrets[key] = value
+		if f.isolateSecrets {
+			delete(values, key)
+		}
+	}
+
+	return secrets
+}
+
+/*
+zeroBytes sobrescreve com zeros um buffer que continha dados sensíveis
+
+Strings em Go são imutáveis e não podem ser zeradas, portanto a limpeza se aplica apenas aos buffers
+intermediários (conteúdo bruto do arquivo, texto cifrado e texto em claro).
+
+@param b []byte - O buffer a ser zerado
+*/
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
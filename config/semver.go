@@ -0,0 +1,519 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/*
+Semver é uma versão semântica, no formato MAJOR.MINOR.PATCH[-PRERELEASE][+BUILD] do SemVer 2.0.0
+
+Major int - A versão principal
+Minor int - A versão secundária
+Patch int - A versão de correção
+Prerelease string - Os identificadores de pré-lançamento, sem o - (ex.: rc.1), ou vazio
+Build string - Os metadados de compilação, sem o + (ex.: 20240101), ou vazio; ignorados nas comparações
+*/
+type Semver struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+	Build      string
+}
+
+/*
+ParseSemver converte um texto em uma versão semântica
+
+O prefixo v, comum em tags do Git (v1.4.2), é aceito. Os três números são obrigatórios e, como no SemVer, não
+podem ter zeros à esquerda.
+
+@param raw string - A versão
+
+@return Semver - A versão convertida
+@return error - Um erro que descreve o problema da versão
+*/
+func ParseSemver(raw string) (Semver, error) {
+	text := strings.TrimPrefix(strings.TrimSpace(raw), "v")
+	core, build, hasBuild := strings.Cut(text, "+")
+	core, pre, hasPre := strings.Cut(core, "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return Semver{}, fmt.Errorf("versão %q inválida: esperado MAJOR.MINOR.PATCH", raw)
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := semverNumber(part)
+		if err != nil {
+			return Semver{}, fmt.Errorf("versão %q inválida: %w", raw, err)
+		}
+		numbers[i] = n
+	}
+	if hasPre {
+		if err := semverIdentifiers(pre, true); err != nil {
+			return Semver{}, fmt.Errorf("versão %q inválida: pré-lançamento %w", raw, err)
+		}
+	}
+	if hasBuild {
+		if err := semverIdentifiers(build, false); err != nil {
+			return Semver{}, fmt.Errorf("versão %q inválida: compilação %w", raw, err)
+		}
+	}
+
+	return Semver{Major: numbers[0], Minor: numbers[1], Patch: numbers[2], Prerelease: pre, Build: build}, nil
+}
+
+/*
+semverNumber converte um número de uma versão, sem sinal e sem zeros à esquerda
+
+@param text string - O número
+
+@return int - O número convertido
+@return error - Um erro se o texto não for um número válido
+*/
+func semverNumber(text string) (int, error) {
+	if text == "" || strings.Trim(text, "0123456789") != "" {
+		return 0, fmt.Errorf("%q não é um número", text)
+	}
+	if len(text) > 1 && text[0] == '0' {
+		return 0, fmt.Errorf("%q tem zeros à esquerda", text)
+	}
+
+	return strconv.Atoi(text)
+}
+
+/*
+semverIdentifiers valida os identificadores separados por ponto do pré-lançamento ou da compilação
+
+@param text string - Os identificadores
+@param numeric bool - Se os identificadores numéricos não podem ter zeros à esquerda, como no pré-lançamento
+
+@return error - Um erro que descreve o identificador inválido
+*/
+func semverIdentifiers(text string, numeric bool) error {
+	for _, id := range strings.Split(text, ".") {
+		if id == "" {
+			return fmt.Errorf("%q tem um identificador vazio", text)
+		}
+		for _, r := range id {
+			if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+				return fmt.Errorf("%q tem o caractere inválido %q", text, r)
+			}
+		}
+		if numeric && len(id) > 1 && id[0] == '0' && strings.Trim(id, "0123456789") == "" {
+			return fmt.Errorf("%q tem zeros à esquerda em %s", text, id)
+		}
+	}
+
+	return nil
+}
+
+// String retorna a versão no formato MAJOR.MINOR.PATCH[-PRERELEASE][+BUILD], sem o prefixo v.
+func (v Semver) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+
+	return s
+}
+
+/*
+Compare compara duas versões pela precedência do SemVer
+
+Uma versão de pré-lançamento vem antes da versão final (1.0.0-rc.1 < 1.0.0) e os metadados de compilação são
+ignorados.
+
+@param other Semver - A outra versão
+
+@return int - -1 se v vier antes de other, 1 se vier depois, ou 0 se tiverem a mesma precedência
+*/
+func (v Semver) Compare(other Semver) int {
+	for _, d := range [3]int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		switch {
+		case d < 0:
+			return -1
+		case d > 0:
+			return 1
+		}
+	}
+
+	switch {
+	case v.Prerelease == other.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case other.Prerelease == "":
+		return -1
+	}
+
+	a, b := strings.Split(v.Prerelease, "."), strings.Split(other.Prerelease, ".")
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := comparePrerelease(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+
+	return 0
+}
+
+/*
+comparePrerelease compara dois identificadores de pré-lançamento
+
+Identificadores numéricos são comparados como números e vêm antes dos alfanuméricos, comparados como texto.
+
+@param a string - O primeiro identificador
+@param b string - O segundo identificador
+
+@return int - -1, 0 ou 1, como em Compare
+*/
+func comparePrerelease(a string, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		}
+		return 0
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+
+	return strings.Compare(a, b)
+}
+
+/*
+semverComparator é uma comparação de uma restrição de versões, como >=1.2.0
+
+op string - O operador: =, !=, >, >=, < ou <=
+version Semver - A versão comparada
+*/
+type semverComparator struct {
+	op      string
+	version Semver
+}
+
+/*
+matches informa se uma versão atende à comparação
+
+@param v Semver - A versão
+
+@return bool - Se a versão atende à comparação
+*/
+func (c semverComparator) matches(v Semver) bool {
+	cmp := v.Compare(c.version)
+	switch c.op {
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	default:
+		return cmp == 0
+	}
+}
+
+// semverOperators são os operadores aceitos nas restrições, com os de dois caracteres primeiro.
+var semverOperators = []string{">=", "<=", "!=", ">", "<", "=", "^", "~"}
+
+/*
+SemverConstraint é uma restrição de versões semânticas, como ">=1.2.0 <2.0.0" ou "^1.4 || ^2.0"
+
+Uma versão atende à restrição se atender a todas as comparações de algum dos grupos separados por ||.
+*/
+type SemverConstraint struct {
+	raw    string
+	groups [][]semverComparator
+}
+
+/*
+ParseSemverConstraint converte um texto em uma restrição de versões semânticas
+
+Cada grupo, separado por ||, é uma lista de comparações separadas por espaços ou vírgulas, todas obrigatórias.
+São aceitos os operadores =, !=, >, >=, < e <=, além de:
+
+	^1.4.2  versões compatíveis, sem mudar o primeiro número diferente de zero (>=1.4.2 <2.0.0)
+	~1.4.2  versões com o mesmo MAJOR.MINOR (>=1.4.2 <1.5.0)
+	1.4.x   qualquer versão 1.4 (>=1.4.0 <1.5.0); 1.4 e 1.4.* são equivalentes, e * aceita qualquer versão
+
+As versões de pré-lançamento são comparadas pela precedência do SemVer, de modo que 2.0.0-rc.1 não atende a
+^1.4 e atende a >=1.4.0.
+
+@param raw string - A restrição
+
+@return SemverConstraint - A restrição convertida
+@return error - Um erro que descreve a comparação inválida
+*/
+func ParseSemverConstraint(raw string) (SemverConstraint, error) {
+	constraint := SemverConstraint{raw: strings.TrimSpace(raw)}
+	for _, group := range strings.Split(raw, "||") {
+		tokens := strings.Fields(strings.ReplaceAll(group, ",", " "))
+		if len(tokens) == 0 {
+			return SemverConstraint{}, fmt.Errorf("restrição %q inválida: grupo vazio", raw)
+		}
+
+		var comparators []semverComparator
+		for i := 0; i < len(tokens); i++ {
+			token := tokens[i]
+			if isSemverOperator(token) && i+1 < len(tokens) {
+				i++
+				token += tokens[i]
+			}
+			expanded, err := expandSemverComparator(token)
+			if err != nil {
+				return SemverConstraint{}, fmt.Errorf("restrição %q inválida: %w", raw, err)
+			}
+			comparators = append(comparators, expanded...)
+		}
+		constraint.groups = append(constraint.groups, comparators)
+	}
+
+	return constraint, nil
+}
+
+/*
+isSemverOperator informa se um texto é apenas um operador, separado da versão por espaço (>= 1.2.0)
+
+@param token string - O texto
+
+@return bool - Se o texto é um operador
+*/
+func isSemverOperator(token string) bool {
+	for _, op := range semverOperators {
+		if token == op {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+expandSemverComparator converte uma comparação, possivelmente com uma versão parcial, em comparações simples
+
+@param token string - A comparação (ex.: ^1.4, >=2, 1.x)
+
+@return []semverComparator - As comparações equivalentes; nenhuma se qualquer versão for aceita
+@return error - Um erro se a comparação for inválida
+*/
+func expandSemverComparator(token string) ([]semverComparator, error) {
+	op := ""
+	for _, candidate := range semverOperators {
+		if strings.HasPrefix(token, candidate) {
+			op = candidate
+			break
+		}
+	}
+
+	v, parts, err := parsePartialSemver(strings.TrimPrefix(token, op))
+	if err != nil {
+		return nil, err
+	}
+	if parts == 0 {
+		switch op {
+		case "", "=", ">=", "<=", "^", "~":
+			return nil, nil
+		}
+		return nil, fmt.Errorf("comparação %q não aceita nenhuma versão", token)
+	}
+
+	// next é a menor versão acima do intervalo da versão parcial, excluindo os pré-lançamentos dela.
+	next := func(parts int) Semver {
+		switch parts {
+		case 1:
+			return Semver{Major: v.Major + 1, Prerelease: "0"}
+		case 2:
+			return Semver{Major: v.Major, Minor: v.Minor + 1, Prerelease: "0"}
+		default:
+			return Semver{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1, Prerelease: "0"}
+		}
+	}
+	lower := semverComparator{op: ">=", version: v}
+
+	switch op {
+	case "", "=":
+		if parts == 3 {
+			return []semverComparator{{op: "=", version: v}}, nil
+		}
+		return []semverComparator{lower, {op: "<", version: next(parts)}}, nil
+	case "!=":
+		if parts < 3 {
+			return nil, fmt.Errorf("comparação %q exige uma versão completa", token)
+		}
+		return []semverComparator{{op: "!=", version: v}}, nil
+	case ">":
+		if parts == 3 {
+			return []semverComparator{{op: ">", version: v}}, nil
+		}
+		return []semverComparator{{op: ">=", version: next(parts)}}, nil
+	case ">=":
+		return []semverComparator{lower}, nil
+	case "<":
+		if parts < 3 {
+			v.Prerelease = "0"
+		}
+		return []semverComparator{{op: "<", version: v}}, nil
+	case "<=":
+		if parts == 3 {
+			return []semverComparator{{op: "<=", version: v}}, nil
+		}
+		return []semverComparator{{op: "<", version: next(parts)}}, nil
+	case "^":
+		switch {
+		case v.Major > 0 || parts == 1:
+			return []semverComparator{lower, {op: "<", version: next(1)}}, nil
+		case v.Minor > 0 || parts == 2:
+			return []semverComparator{lower, {op: "<", version: next(2)}}, nil
+		}
+		return []semverComparator{lower, {op: "<", version: next(3)}}, nil
+	default:
+		if parts == 1 {
+			return []semverComparator{lower, {op: "<", version: next(1)}}, nil
+		}
+		return []semverComparator{lower, {op: "<", version: next(2)}}, nil
+	}
+}
+
+/*
+parsePartialSemver converte uma versão que pode omitir os últimos números ou usar x, X ou * no lugar deles
+
+@param text string - A versão (ex.: 1.4, 1.x, *, 1.4.2-rc.1)
+
+@return Semver - A versão, com zero nos números omitidos
+@return int - A quantidade de números informados, de 0 a 3
+@return error - Um erro se a versão for inválida
+*/
+func parsePartialSemver(text string) (Semver, int, error) {
+	text = strings.TrimPrefix(text, "v")
+	if text == "" {
+		return Semver{}, 0, errors.New("versão ausente")
+	}
+
+	core, _, extended := strings.Cut(text, "-")
+	if !extended {
+		core, _, extended = strings.Cut(text, "+")
+	}
+	if extended || strings.Count(core, ".") == 2 && !strings.ContainsAny(core, "xX*") {
+		v, err := ParseSemver(text)
+		return v, 3, err
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return Semver{}, 0, fmt.Errorf("versão %q inválida", text)
+	}
+	var numbers [3]int
+	count := 0
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			if i < len(parts)-1 && strings.Trim(strings.Join(parts[i+1:], ""), "xX*") != "" {
+				return Semver{}, 0, fmt.Errorf("versão %q inválida: números após um curinga", text)
+			}
+			break
+		}
+		n, err := semverNumber(part)
+		if err != nil {
+			return Semver{}, 0, fmt.Errorf("versão %q inválida: %w", text, err)
+		}
+		numbers[count] = n
+		count++
+	}
+
+	return Semver{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, count, nil
+}
+
+/*
+Check informa se uma versão atende à restrição
+
+@param v Semver - A versão
+
+@return bool - Se a versão atende a todas as comparações de algum dos grupos
+*/
+func (c SemverConstraint) Check(v Semver) bool {
+	for _, group := range c.groups {
+		matched := true
+		for _, comparator := range group {
+			if !comparator.matches(v) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String retorna a restrição como foi informada.
+func (c SemverConstraint) String() string {
+	return c.raw
+}
+
+/*
+GetSemver retorna o valor de uma variável como uma versão semântica
+
+@param key string - O nome da variável (ex.: MIN_CLIENT_VERSION)
+
+@return Semver - A versão
+@return error - ErrKeyNotFound se a variável não existir, ou um erro com o arquivo e a linha se a versão for inválida
+*/
+func (f *FileEnvLoader) GetSemver(key string) (Semver, error) {
+	value, err := f.require(key)
+	if err != nil {
+		return Semver{}, err
+	}
+
+	v, err := ParseSemver(value)
+	if err != nil {
+		return Semver{}, fmt.Errorf("variável %s%s: %w", key, f.location(key), err)
+	}
+
+	return v, nil
+}
+
+/*
+GetSemverConstraint retorna o valor de uma variável como uma restrição de versões, como em ParseSemverConstraint
+
+@param key string - O nome da variável (ex.: SUPPORTED_RANGE)
+
+@return SemverConstraint - A restrição
+@return error - ErrKeyNotFound se a variável não existir, ou um erro com o arquivo e a linha se a restrição for inválida
+*/
+func (f *FileEnvLoader) GetSemverConstraint(key string) (SemverConstraint, error) {
+	value, err := f.require(key)
+	if err != nil {
+		return SemverConstraint{}, err
+	}
+
+	c, err := ParseSemverConstraint(value)
+	if err != nil {
+		return SemverConstraint{}, fmt.Errorf("variável %s%s: %w", key, f.location(key), err)
+	}
+
+	return c, nil
+}
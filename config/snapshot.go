@@ -0,0 +1,196 @@
+package config
+
+import "time"
+
+// emptyView é o carregador usado pelo valor zero de ConfigView, que não contém nenhuma variável.
+var emptyView = &FileEnvLoader{noProcessEnv: true}
+
+/*
+ConfigView é uma visão imutável das variáveis de um carregador, obtida com Snapshot
+
+A visão guarda uma cópia própria das variáveis, dos segredos, das origens e da ordem no momento em que foi criada e nunca
+é alterada: Reload, Set e novos carregamentos produzem novas visões em vez de modificar as existentes. Por isso,
+bibliotecas podem guardar uma ConfigView e lê-la de várias goroutines sem bloqueios. Ao contrário do carregador,
+a visão não consulta o ambiente do processo: variáveis que não vieram dos arquivos .env não fazem parte dela.
+O valor zero é uma visão vazia.
+*/
+type ConfigView struct {
+	loader *FileEnvLoader
+}
+
+/*
+Snapshot cria uma visão imutável das variáveis carregadas
+
+A cópia é feita no momento da chamada; chame Snapshot na goroutine que carrega o ambiente, ou depois dela.
+
+@return ConfigView - A visão congelada
+*/
+func (f *FileEnvLoader) Snapshot() ConfigView {
+	frozen := &FileEnvLoader{
+		Env:            f.Env,
+		classAliases:   f.classAliases,
+		keyAliases:     f.keyAliases,
+		decimalComma:   f.decimalComma,
+		strictDecimals: f.strictDecimals,
+		values:         copyMap(f.values),
+		secrets:        copyMap(f.secrets),
+		sources:        copyMap(f.sources),
+		lines:          copyIntMap(f.lines),
+		order:          copyIntMap(f.order),
+		keyOrder:       f.keyOrder,
+		revision:       f.revision,
+		revisionKey:    f.revisionKey,
+		noProcessEnv:   true,
+		fsys:           f.fsys,
+		crons:          f.crons,
+		jsonRules:      f.jsonRules,
+	}
+
+	return ConfigView{loader: frozen}
+}
+
+/*
+copyMap retorna uma cópia de um mapa de strings
+
+@param m map[string]string - O mapa
+
+@return map[string]string - A cópia, que nunca é nil
+*/
+func copyMap(m map[string]string) map[string]string {
+	c := make(map[string]string, len(m))
+	for key, value := range m {
+		c[key] = value
+	}
+
+	return c
+}
+
+/*
+copyIntMap retorna uma cópia de um mapa de inteiros, como as linhas e a ordem das variáveis
+
+@param m map[string]int - O mapa
+
+@return map[string]int - A cópia, que nunca é nil
+*/
+func copyIntMap(m map[string]int) map[string]int {
+	c := make(map[string]int, len(m))
+	for key, value := range m {
+		c[key] = value
+	}
+
+	return c
+}
+
+// reader retorna o carregador congelado da visão, ou uma visão vazia para o valor zero.
+func (v ConfigView) reader() *FileEnvLoader {
+	if v.loader == nil {
+		return emptyView
+	}
+
+	return v.loader
+}
+
+// GetEnv retorna o ambiente do carregador no momento da visão.
+func (v ConfigView) GetEnv() string { return v.reader().GetEnv() }
+
+// IsProduction verifica se o ambiente da visão é de produção.
+func (v ConfigView) IsProduction() bool { return v.reader().IsProduction() }
+
+// IsDevelopment verifica se o ambiente da visão é de desenvolvimento.
+func (v ConfigView) IsDevelopment() bool { return v.reader().IsDevelopment() }
+
+// IsTest verifica se o ambiente da visão é de teste.
+func (v ConfigView) IsTest() bool { return v.reader().IsTest() }
+
+// Lookup retorna o valor de uma variável da visão.
+func (v ConfigView) Lookup(key string) (string, bool) { return v.reader().Lookup(key) }
+
+// GetString retorna o valor de uma variável da visão como string.
+func (v ConfigView) GetString(key string) string { return v.reader().GetString(key) }
+
+// GetInt retorna o valor de uma variável da visão convertido para int.
+func (v ConfigView) GetInt(key string) (int, error) { return v.reader().GetInt(key) }
+
+// GetFloat retorna o valor de uma variável da visão convertido para float64.
+func (v ConfigView) GetFloat(key string) (float64, error) { return v.reader().GetFloat(key) }
+
+// GetBool retorna o valor de uma variável da visão convertido para bool.
+func (v ConfigView) GetBool(key string) (bool, error) { return v.reader().GetBool(key) }
+
+// GetDuration retorna o valor de uma variável da visão convertido para time.Duration.
+func (v ConfigView) GetDuration(key string) (time.Duration, error) {
+	return v.reader().GetDuration(key)
+}
+
+// GetStringSlice retorna o valor de uma variável da visão separado por vírgulas.
+func (v ConfigView) GetStringSlice(key string) []string { return v.reader().GetStringSlice(key) }
+
+// GetSecret retorna o valor de um segredo da visão.
+func (v ConfigView) GetSecret(key string) (string, bool) { return v.reader().GetSecret(key) }
+
+// GetBytesBase64 retorna o conteúdo binário de uma variável da visão codificada em base64.
+func (v ConfigView) GetBytesBase64(key string) ([]byte, error) { return v.reader().GetBytesBase64(key) }
+
+// GetBase64 retorna o conteúdo binário de uma variável da visão codificada em base64, com validações.
+func (v ConfigView) GetBase64(key string, checks ...ContentCheck) ([]byte, error) {
+	return v.reader().GetBase64(key, checks...)
+}
+
+// GetHex retorna o conteúdo binário de uma variável da visão codificada em hexadecimal, com validações.
+func (v ConfigView) GetHex(key string, checks ...ContentCheck) ([]byte, error) {
+	return v.reader().GetHex(key, checks...)
+}
+
+// GetFileContent retorna o conteúdo do arquivo cujo caminho está em uma variável da visão.
+func (v ConfigView) GetFileContent(key string) ([]byte, error) { return v.reader().GetFileContent(key) }
+
+// GetPath retorna uma variável da visão como um caminho, resolvido em relação ao arquivo que a definiu.
+func (v ConfigView) GetPath(key string, checks ...PathCheck) (string, error) {
+	return v.reader().GetPath(key, checks...)
+}
+
+// GetExistingDir retorna uma variável da visão como o caminho de um diretório existente e acessível.
+func (v ConfigView) GetExistingDir(key string) (string, error) { return v.reader().GetExistingDir(key) }
+
+// GetExistingFile retorna uma variável da visão como o caminho de um arquivo existente e legível.
+func (v ConfigView) GetExistingFile(key string) (string, error) {
+	return v.reader().GetExistingFile(key)
+}
+
+// GetCron retorna uma variável da visão como uma expressão cron válida.
+func (v ConfigView) GetCron(key string) (string, error) { return v.reader().GetCron(key) }
+
+// GetSemver retorna uma variável da visão como uma versão semântica.
+func (v ConfigView) GetSemver(key string) (Semver, error) { return v.reader().GetSemver(key) }
+
+// GetSemverConstraint retorna uma variável da visão como uma restrição de versões semânticas.
+func (v ConfigView) GetSemverConstraint(key string) (SemverConstraint, error) {
+	return v.reader().GetSemverConstraint(key)
+}
+
+// GetJSON converte o valor JSON de uma variável da visão em target.
+func (v ConfigView) GetJSON(key string, target any) error { return v.reader().GetJSON(key, target) }
+
+// All retorna uma cópia das variáveis da visão.
+func (v ConfigView) All() map[string]string { return v.reader().All() }
+
+// Keys retorna os nomes das variáveis da visão na ordem estável do carregador.
+func (v ConfigView) Keys() []string { return v.reader().Keys() }
+
+// Dump retorna as variáveis da visão no formato de um arquivo .env, com os segredos mascarados.
+func (v ConfigView) Dump() string { return v.reader().Dump() }
+
+// Source retorna a origem do valor de uma variável da visão.
+func (v ConfigView) Source(key string) (string, bool) { return v.reader().Source(key) }
+
+// Snapshot retorna a própria visão, que já é imutável.
+func (v ConfigView) Snapshot() ConfigView { return v }
+
+// Sub retorna uma visão das variáveis de uma seção da visão.
+func (v ConfigView) Sub(prefix string) ConfigView { return v.reader().Sub(prefix) }
+
+// Revision retorna a revisão da configuração no momento em que a visão foi criada.
+func (v ConfigView) Revision() string { return v.reader().Revision() }
+
+// EqualRevision informa se outra configuração possui a mesma revisão que a visão.
+func (v ConfigView) EqualRevision(other Reader) bool { return EqualRevision(v, other) }
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+/*
+SplitByPrefix divide as variáveis carregadas em grupos pelo prefixo do nome
+
+É o que um processo supervisor precisa para iniciar vários serviços a partir de um único arquivo .env: com
+PAYMENTS_DB_URL, AUTH_ISSUER e LOG_LEVEL carregadas, SplitByPrefix("PAYMENTS", "AUTH") retorna
+{"PAYMENTS": {PAYMENTS_DB_URL}, "AUTH": {AUTH_ISSUER}, "": {LOG_LEVEL}}. O prefixo é seguido de _ no nome da
+variável; quando mais de um prefixo corresponde, vale o mais longo. As variáveis sem nenhum dos prefixos ficam
+no grupo "", normalmente compartilhado por todos os serviços. Os nomes são mantidos e os segredos, inclusive
+os isolados com WithSecretIsolation, fazem parte dos grupos. Os grupos podem ser gravados com WriteSplitFiles
+ou passados a um processo filho com ApplyToCmd.
+
+@param prefixes ...string - Os prefixos, com ou sem o _ final
+
+@return map[string]map[string]string - As variáveis de cada prefixo, como informado, e as demais em ""
+*/
+func (f *FileEnvLoader) SplitByPrefix(prefixes ...string) map[string]map[string]string {
+	groups := map[string]map[string]string{"": {}}
+	for _, prefix := range prefixes {
+		groups[prefix] = map[string]string{}
+	}
+
+	add := func(key, value string) {
+		group, longest := "", 0
+		for _, prefix := range prefixes {
+			p := strings.TrimSuffix(prefix, "_") + "_"
+			if len(p) > longest && strings.HasPrefix(key, p) {
+				group, longest = prefix, len(p)
+			}
+		}
+		groups[group][key] = value
+	}
+	for key, value := range f.values {
+		add(key, value)
+	}
+	for key, value := range f.secrets {
+		add(key, value)
+	}
+
+	return groups
+}
+
+/*
+WriteSplitFiles grava cada grupo de SplitByPrefix em um arquivo .env próprio
+
+Cada grupo é gravado em <dir>/<prefixo>.env, com o prefixo em minúsculas e sem o _ final (payments.env), e o
+grupo "" em <dir>/shared.env. Os nomes não começam com .env para que os arquivos não sejam confundidos com
+perfis pela descoberta. Os arquivos são gravados de forma atômica, com permissão 0600, porque podem conter
+segredos; os grupos vazios não geram arquivos.
+
+@param dir string - O diretório dos arquivos
+@param groups map[string]map[string]string - Os grupos de SplitByPrefix
+
+@return []string - Os arquivos gravados, em ordem alfabética
+@return error - Um erro se algum arquivo não puder ser gravado
+*/
+func WriteSplitFiles(dir string, groups map[string]map[string]string) ([]string, error) {
+	names := make(map[string]string, len(groups))
+	for prefix, values := range groups {
+		if len(values) == 0 {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSuffix(prefix, "_"))
+		if name == "" {
+			name = "shared"
+		}
+		if strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+			return nil, fmt.Errorf("prefixo %q não pode ser usado como nome de arquivo", prefix)
+		}
+		file := filepath.Join(dir, name+".env")
+		if other, ok := names[file]; ok {
+			return nil, fmt.Errorf("prefixos %q e %q resultam no mesmo arquivo %s", other, prefix, file)
+		}
+		names[file] = prefix
+	}
+
+	files := sortedKeys(names)
+	for _, file := range files {
+		values := groups[names[file]]
+		var b strings.Builder
+		for _, key := range sortedKeys(values) {
+			fmt.Fprintf(&b, "%s=%s\n", key, QuoteValue(values[key]))
+		}
+		if err := writeFileAtomic(file, []byte(b.String()), 0o600); err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+/*
+ApplyToCmd adiciona variáveis ao ambiente de um processo filho
+
+Se cmd.Env for nil, o ambiente parte de os.Environ(), como exec.Cmd faria; as variáveis são acrescentadas em
+ordem alfabética e prevalecem sobre as de mesmo nome já presentes. Para iniciar um serviço com o seu grupo e o
+compartilhado, basta chamar ApplyToCmd com groups[""] e depois com groups["PAYMENTS"].
+
+@param cmd *exec.Cmd - O comando, ainda não iniciado
+@param values map[string]string - As variáveis, normalmente um grupo de SplitByPrefix
+*/
+func ApplyToCmd(cmd *exec.Cmd, values map[string]string) {
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	for _, key := range sortedKeys(values) {
+		cmd.Env = append(cmd.Env, key+"="+values[key])
+	}
+}
@@ -0,0 +1,398 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/*
+SecretStore é uma fonte remota de verdade para os valores de um ambiente, como um Vault ou o SSM
+
+Fetch retorna os valores guardados para o ambiente, e Push grava os valores informados, sem remover os demais.
+As lojas são usadas pelo subcomando locenv sync para comparar os arquivos locais com a fonte de verdade.
+*/
+type SecretStore interface {
+	Fetch(env string) (map[string]string, error)
+	Push(env string, values map[string]string) error
+}
+
+/*
+StoreFactory cria uma SecretStore a partir das suas opções
+
+@param options map[string]string - As opções da loja, como endereço, caminho ou comando
+@return SecretStore - A loja criada
+@return error - Um erro se as opções forem inválidas ou a loja não puder ser criada
+*/
+type StoreFactory func(options map[string]string) (SecretStore, error)
+
+var (
+	storeMu  sync.RWMutex
+	storeReg = make(map[string]StoreFactory)
+)
+
+/*
+RegisterStore registra a fábrica de uma SecretStore com o nome informado
+
+Como em RegisterProvider, as lojas que dependem de SDKs ficam em módulos separados e se registram na função
+init do próprio pacote. As lojas file e command são registradas pelo pacote config.
+
+@param name string - O nome da loja, usado em OpenStore e em locenv sync -provider
+@param factory StoreFactory - A fábrica da loja
+
+Registrar o mesmo nome duas vezes, ou uma fábrica nil, causa um panic.
+*/
+func RegisterStore(name string, factory StoreFactory) {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+
+	if factory == nil {
+		panic("config: RegisterStore com fábrica nil para " + name)
+	}
+	if _, exists := storeReg[name]; exists {
+		panic("config: RegisterStore chamado duas vezes para " + name)
+	}
+	storeReg[name] = factory
+}
+
+/*
+Stores retorna os nomes das lojas registradas
+
+@return []string - Os nomes, em ordem alfabética
+*/
+func Stores() []string {
+	storeMu.RLock()
+	defer storeMu.RUnlock()
+
+	names := make([]string, 0, len(storeReg))
+	for name := range storeReg {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+/*
+OpenStore cria uma loja registrada
+
+@param name string - O nome da loja
+@param options map[string]string - As opções da loja
+
+@return SecretStore - A loja criada
+@return error - Um erro se a loja não estiver registrada ou não puder ser criada
+*/
+func OpenStore(name string, options map[string]string) (SecretStore, error) {
+	storeMu.RLock()
+	factory, ok := storeReg[name]
+	storeMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("loja %q não registrada: importe o pacote da loja (registradas: %v)", name, Stores())
+	}
+
+	return factory(options)
+}
+
+/*
+CompareWithStore compara os valores de um arquivo .env com os da loja para o ambiente informado
+
+No resultado, Old é o valor local e New o valor da loja: DiffAdded indica uma variável que existe apenas na
+loja, DiffRemoved uma que existe apenas no arquivo e DiffChanged um valor diferente. Os valores locais são os
+resolvidos, com os valores enc: decifrados pelos Decrypters de opts e os marcadores @generate substituídos. Os
+valores das variáveis que correspondem a secretPatterns são mascarados.
+
+@param file string - O arquivo .env local
+@param store SecretStore - A loja
+@param env string - O ambiente
+@param secretPatterns []string - Os padrões de nomes das variáveis secretas
+@param opts ...Option - As opções do carregador usadas na resolução, como WithDecrypter
+
+@return ConfigDiff - As diferenças, com OldEnv e NewEnv iguais a env
+@return error - Um erro se o arquivo ou a loja não puderem ser lidos, ou um *DecryptError
+*/
+func CompareWithStore(file string, store SecretStore, env string, secretPatterns []string, opts ...Option) (ConfigDiff, error) {
+	local, _, err := resolveStoreValues(file, opts)
+	if err != nil {
+		return ConfigDiff{}, err
+	}
+	remote, err := store.Fetch(env)
+	if err != nil {
+		return ConfigDiff{}, fmt.Errorf("erro ao ler a loja: %w", err)
+	}
+
+	diff := ConfigDiff{OldEnv: env, NewEnv: env}
+	for key, value := range local {
+		change := KeyChange{Key: key, Old: value, Secret: matchesAny(secretPatterns, key)}
+		current, ok := remote[key]
+		switch {
+		case !ok:
+			change.Kind = DiffRemoved
+		case current != value:
+			change.Kind, change.New = DiffChanged, current
+		default:
+			continue
+		}
+		diff.Changes = append(diff.Changes, change.masked())
+	}
+	for key, value := range remote {
+		if _, ok := local[key]; !ok {
+			diff.Changes = append(diff.Changes, KeyChange{Key: key, Kind: DiffAdded, New: value, Secret: matchesAny(secretPatterns, key)}.masked())
+		}
+	}
+	sort.Slice(diff.Changes, func(i, j int) bool {
+		return diff.Changes[i].Key < diff.Changes[j].Key
+	})
+
+	return diff, nil
+}
+
+/*
+PullFromStore grava no arquivo .env os valores da loja que faltam ou diferem localmente
+
+As variáveis que existem apenas no arquivo são mantidas. O restante do arquivo é preservado (veja SetValues).
+Os valores são comparados com os resolvidos, como em CompareWithStore, e as linhas com valores enc: ou @generate
+nunca são substituídas, para que um segredo em claro não seja gravado no lugar do marcador.
+
+@param file string - O arquivo .env local
+@param store SecretStore - A loja
+@param env string - O ambiente
+@param opts ...Option - As opções do carregador usadas na resolução, como WithDecrypter
+
+@return []string - As variáveis gravadas, em ordem alfabética
+@return error - Um erro se o arquivo ou a loja não puderem ser lidos, ou o arquivo não puder ser gravado
+*/
+func PullFromStore(file string, store SecretStore, env string, opts ...Option) ([]string, error) {
+	local, markers, err := resolveStoreValues(file, opts)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	remote, err := store.Fetch(env)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler a loja: %w", err)
+	}
+
+	changed := make(map[string]string)
+	for key, value := range remote {
+		if markers[key] {
+			continue
+		}
+		if current, ok := local[key]; !ok || current != value {
+			changed[key] = value
+		}
+	}
+	if err := SetValues(file, changed); err != nil {
+		return nil, err
+	}
+
+	return sortedKeys(changed), nil
+}
+
+/*
+PushToStore envia à loja os valores do arquivo .env que faltam ou diferem remotamente
+
+Os valores enviados são os resolvidos, como em CompareWithStore, de modo que a loja nunca recebe um valor enc:.
+
+@param file string - O arquivo .env local
+@param store SecretStore - A loja
+@param env string - O ambiente
+@param opts ...Option - As opções do carregador usadas na resolução, como WithDecrypter
+
+@return []string - As variáveis enviadas, em ordem alfabética
+@return error - Um erro se o arquivo ou a loja não puderem ser lidos, um valor não puder ser decifrado ou a loja não aceitar os valores
+*/
+func PushToStore(file string, store SecretStore, env string, opts ...Option) ([]string, error) {
+	local, _, err := resolveStoreValues(file, opts)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := store.Fetch(env)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler a loja: %w", err)
+	}
+
+	changed := make(map[string]string)
+	for key, value := range local {
+		if current, ok := remote[key]; !ok || current != value {
+			changed[key] = value
+		}
+	}
+	if len(changed) > 0 {
+		if err := store.Push(env, changed); err != nil {
+			return nil, fmt.Errorf("erro ao gravar na loja: %w", err)
+		}
+	}
+
+	return sortedKeys(changed), nil
+}
+
+/*
+resolveStoreValues lê as variáveis de um arquivo .env com os valores resolvidos, para a comparação com uma loja
+
+O arquivo é lido como uma camada de LoadEnv, sem descoberta, validações nem alterações no ambiente do processo: os
+valores enc: são decifrados e os marcadores @generate são substituídos, sem gravar os valores gerados.
+
+@param file string - O arquivo
+@param opts []Option - As opções do carregador, como WithDecrypter
+
+@return map[string]string - As variáveis resolvidas
+@return map[string]bool - As variáveis declaradas com um valor enc: ou @generate
+@return error - Um erro se o arquivo não puder ser lido ou interpretado, ou um *DecryptError
+*/
+func resolveStoreValues(file string, opts []Option) (map[string]string, map[string]bool, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	entries, err := parseEntries(bytes.NewReader(content), file)
+	zeroBytes(content)
+	if err != nil {
+		return nil, nil, err
+	}
+	markers := make(map[string]bool)
+	for _, e := range entries {
+		markers[e.key] = IsEncrypted(e.value) || (e.quote != '\'' && strings.HasPrefix(strings.TrimSpace(e.value), generatePrefix))
+	}
+
+	loader := NewEnvLoader(append(append([]Option(nil), opts...), WithSilent())...).(*FileEnvLoader)
+	loader.dryRun = true
+	values, _, _, err := loader.loadLayers([]string{file})
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := loader.decryptValues(values); err != nil {
+		return nil, nil, err
+	}
+
+	return values, markers, nil
+}
+
+/*
+readValues lê as variáveis de um arquivo .env, sem expandir camadas nem decifrar valores
+
+@param file string - O arquivo
+
+@return map[string]string - As variáveis
+@return error - Um erro se o arquivo não puder ser lido ou interpretado
+*/
+func readValues(file string) (map[string]string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(content)
+
+	entries, err := parseEntries(bytes.NewReader(content), file)
+	if err != nil {
+		return nil, err
+	}
+
+	return entriesToMap(entries), nil
+}
+
+/*
+fileStore é a SecretStore que guarda os valores em um arquivo .env, como um diretório compartilhado montado
+
+path string - O caminho do arquivo, em que {env} é substituído pelo ambiente
+*/
+type fileStore struct {
+	path string
+}
+
+/*
+openFileStore cria uma fileStore a partir da opção path, como /mnt/segredos/.env.{env}
+
+@param options map[string]string - As opções da loja
+
+@return SecretStore - A loja criada
+@return error - Um erro se o caminho não for informado
+*/
+func openFileStore(options map[string]string) (SecretStore, error) {
+	if strings.TrimSpace(options["path"]) == "" {
+		return nil, fmt.Errorf("loja file: opção path não informada")
+	}
+
+	return fileStore{path: options["path"]}, nil
+}
+
+// Fetch lê as variáveis do arquivo do ambiente.
+func (s fileStore) Fetch(env string) (map[string]string, error) {
+	return readValues(strings.ReplaceAll(s.path, "{env}", env))
+}
+
+// Push grava as variáveis no arquivo do ambiente com SetValues.
+func (s fileStore) Push(env string, values map[string]string) error {
+	return SetValues(strings.ReplaceAll(s.path, "{env}", env), values)
+}
+
+/*
+commandStore é a SecretStore que delega a leitura e a gravação a programas externos
+
+Os comandos recebem {env} substituído pelo ambiente. O comando fetch escreve as variáveis no formato .env na
+saída padrão, e o comando push as recebe no mesmo formato na entrada padrão, como um script que envolve a CLI
+do Vault ou da AWS.
+
+fetch []string - O programa e os argumentos da leitura
+push []string - O programa e os argumentos da gravação
+*/
+type commandStore struct {
+	fetch []string
+	push  []string
+}
+
+/*
+openCommandStore cria uma commandStore a partir das opções fetch e push, com os comandos separados por espaços
+
+@param options map[string]string - As opções da loja
+
+@return SecretStore - A loja criada
+@return error - Um erro se o comando de leitura não for informado
+*/
+func openCommandStore(options map[string]string) (SecretStore, error) {
+	if strings.TrimSpace(options["fetch"]) == "" {
+		return nil, fmt.Errorf("loja command: opção fetch não informada")
+	}
+
+	return commandStore{fetch: strings.Fields(options["fetch"]), push: strings.Fields(options["push"])}, nil
+}
+
+// Fetch executa o comando fetch e interpreta a sua saída como um arquivo .env.
+func (s commandStore) Fetch(env string) (map[string]string, error) {
+	out, err := runCipherCommand(withEnvArg(s.fetch, env), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(out)
+
+	return Parse(bytes.NewReader(out))
+}
+
+// Push executa o comando push com as variáveis, no formato .env, na entrada padrão.
+func (s commandStore) Push(env string, values map[string]string) error {
+	if len(s.push) == 0 {
+		return fmt.Errorf("loja command: opção push não informada")
+	}
+
+	var b strings.Builder
+	for _, key := range sortedKeys(values) {
+		fmt.Fprintf(&b, "%s=%s\n", key, QuoteValue(values[key]))
+	}
+	_, err := runCipherCommand(withEnvArg(s.push, env), []byte(b.String()))
+
+	return err
+}
+
+// withEnvArg substitui {env} pelo ambiente nos argumentos de um comando.
+func withEnvArg(command []string, env string) []string {
+	args := make([]string, len(command))
+	for i, arg := range command {
+		args[i] = strings.ReplaceAll(arg, "{env}", env)
+	}
+
+	return args
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// defaultCommandTimeout é o tempo máximo de execução de cada comando de uma substituição, se nenhum for informado.
+const defaultCommandTimeout = 5 * time.Second
+
+/*
+WithAllowCommandSubstitution habilita a substituição de comandos nos valores, como GIT_SHA=$(git rev-parse HEAD)
+
+Cada trecho $(...) dos valores sem aspas ou entre aspas duplas é substituído pela saída padrão do comando, sem
+as quebras de linha finais, como no shell e no direnv; valores entre aspas simples continuam literais, e \$(
+produz um $( literal. A substituição é feita na interpretação do texto do arquivo, por isso um $( que chega ao
+valor pela expansão de outra variável ou pela saída de um comando nunca é executado. O comando é dividido em
+palavras por espaços e executado diretamente, sem um shell, no diretório do arquivo .env, de modo que pipes,
+redirecionamentos e variáveis não são interpretados. Apenas os programas de allowed podem ser executados,
+escritos no valor exatamente como na lista, pelo nome (git) ou pelo caminho completo; qualquer outro comando, um
+comando que falha ou que ultrapassa timeout interrompe o carregamento. Os comandos são executados a cada
+carregamento, por isso a opção é indicada apenas para o desenvolvimento local. Sem esta opção, $(...) é mantido
+literalmente no valor.
+
+@param timeout time.Duration - O tempo máximo de cada comando, ou zero para 5 segundos
+@param allowed ...string - Os programas que podem ser executados
+
+@return Option - A opção que habilita a substituição de comandos
+*/
+func WithAllowCommandSubstitution(timeout time.Duration, allowed ...string) Option {
+	return func(f *FileEnvLoader) {
+		if timeout <= 0 {
+			timeout = defaultCommandTimeout
+		}
+		f.commandTimeout = timeout
+		f.allowedCommands = make(map[string]bool, len(allowed))
+		for _, name := range allowed {
+			f.allowedCommands[name] = true
+		}
+	}
+}
+
+/*
+commandSubstituter retorna a função que executa os comandos das substituições de um arquivo .env durante a
+interpretação, ou nil se a opção não estiver habilitada
+
+@param file string - O arquivo .env, cujo diretório é o diretório de trabalho dos comandos
+
+@return func(string) (string, error) - A função que executa um comando e retorna a sua saída, ou nil
+*/
+func (f *FileEnvLoader) commandSubstituter(file string) func(string) (string, error) {
+	if f.allowedCommands == nil {
+		return nil
+	}
+
+	return func(command string) (string, error) {
+		return f.runSubstitution(command, file)
+	}
+}
+
+/*
+runSubstitution executa o comando de uma substituição
+
+@param command string - O comando, com os argumentos separados por espaços
+@param file string - O arquivo .env, cujo diretório é o diretório de trabalho do comando
+
+@return string - A saída padrão, sem as quebras de linha finais
+@return error - Um erro se o comando não for permitido, falhar ou expirar
+*/
+func (f *FileEnvLoader) runSubstitution(command string, file string) (string, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", fmt.Errorf("comando vazio")
+	}
+	if !f.allowedCommands[args[0]] {
+		return "", fmt.Errorf("o comando %q não está entre os permitidos por WithAllowCommandSubstitution", args[0])
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), f.commandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	if f.fsys == nil {
+		cmd.Dir = filepath.Dir(file)
+	}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	f.tracef("substituição de comando: executando %q", command)
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("%s: tempo limite de %s excedido", args[0], f.commandTimeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %w: %s", args[0], err, msg)
+		}
+		return "", fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
+
+/*
+closingParen encontra o ')' que fecha uma substituição, considerando os parênteses aninhados
+
+@param s string - O valor
+@param from int - A posição logo após "$("
+
+@return int - A posição do ')' de fechamento, ou -1 se não houver
+*/
+func closingParen(s string, from int) int {
+	depth := 0
+	for i := from; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+
+	return -1
+}
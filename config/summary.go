@@ -0,0 +1,124 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jonh-dev/go-logger/logger"
+)
+
+// summaryPreviewLimit é o tamanho máximo, em caracteres, de um valor exibido no resumo de inicialização.
+const summaryPreviewLimit = 64
+
+// SummaryLevel define o detalhamento do resumo registrado após cada carregamento.
+type SummaryLevel int
+
+const (
+	// SummaryOff não registra nenhum resumo.
+	SummaryOff SummaryLevel = iota
+	// SummaryBrief registra uma linha com o perfil, os arquivos, a quantidade de variáveis e de avisos e a revisão.
+	SummaryBrief
+	// SummaryVerbose registra a linha de SummaryBrief seguida da prévia das variáveis e de cada aviso.
+	SummaryVerbose
+)
+
+/*
+WithStartupSummary registra um resumo da configuração após cada carregamento bem-sucedido
+
+O resumo é registrado com logger.Info e mostra o perfil carregado, os arquivos na ordem em que foram aplicados,
+a quantidade de variáveis e de segredos, os avisos e a revisão, para que a operação veja de relance com qual
+configuração um processo iniciou. Com SummaryVerbose, os valores das variáveis de previewKeys também são
+exibidos, com os segredos e as senhas de strings de conexão mascarados e os valores longos truncados.
+O mesmo resumo está disponível em Result.Summary.
+
+@param level SummaryLevel - O detalhamento do resumo
+@param previewKeys ...string - As variáveis cujos valores são exibidos em Result.Preview e no resumo detalhado
+
+@return Option - A opção que habilita o resumo
+*/
+func WithStartupSummary(level SummaryLevel, previewKeys ...string) Option {
+	return func(f *FileEnvLoader) {
+		f.summaryLevel = level
+		f.previewKeys = previewKeys
+	}
+}
+
+/*
+Summary descreve o carregamento em texto, no formato do resumo de WithStartupSummary
+
+@param verbose bool - Se a prévia das variáveis e cada aviso devem ser incluídos
+
+@return string - O resumo, com uma linha por item no modo detalhado
+*/
+func (r *Result) Summary(verbose bool) string {
+	env := r.Env
+	if r.RequestedEnv != "" && r.RequestedEnv != r.Env {
+		env = fmt.Sprintf("%s (solicitado %s)", r.Env, r.RequestedEnv)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "ambiente %s carregado de %d arquivo(s) [%s]: %d variável(is), %d segredo(s), %d aviso(s), revisão %s",
+		env, len(r.Files), strings.Join(r.Files, ", "), r.Loaded, r.Secrets, len(r.Warnings), r.Revision)
+	if !verbose {
+		return b.String()
+	}
+
+	keys := make([]string, 0, len(r.Preview))
+	for key := range r.Preview {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "\n  %s=%s", key, r.Preview[key])
+	}
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(&b, "\n  aviso: %s", warning)
+	}
+
+	return b.String()
+}
+
+/*
+preview retorna os valores das variáveis de WithStartupSummary prontos para serem exibidos
+
+@return map[string]string - Os valores mascarados e truncados; variáveis não definidas aparecem como "<não definida>"
+*/
+func (f *FileEnvLoader) preview() map[string]string {
+	if len(f.previewKeys) == 0 {
+		return nil
+	}
+
+	values := make(map[string]string, len(f.previewKeys))
+	for _, key := range f.previewKeys {
+		value, ok := f.values[key]
+		_, secret := f.secrets[key]
+		switch {
+		case secret:
+			value = maskedValue
+		case !ok:
+			value = "<não definida>"
+		default:
+			value = RedactDSN(value)
+			if runes := []rune(value); len(runes) > summaryPreviewLimit {
+				value = string(runes[:summaryPreviewLimit]) + "…"
+			}
+		}
+		values[key] = value
+	}
+
+	return values
+}
+
+/*
+logSummary registra o resumo do carregamento conforme WithStartupSummary
+
+@param result *Result - O resumo do carregamento
+*/
+func (f *FileEnvLoader) logSummary(result *Result) {
+	if f.summaryLevel == SummaryOff || !f.logs(LogInfo) {
+		return
+	}
+
+	logger.Info("locenv: " + result.Summary(f.summaryLevel == SummaryVerbose))
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/jonh-dev/go-logger/logger"
+)
+
+// DefaultTenantHeader é o cabeçalho lido por TenantMiddleware quando nenhum outro é informado.
+const DefaultTenantHeader = "X-Tenant-ID"
+
+// tenantKey é a chave do nome do inquilino guardado no contexto por TenantMiddleware.
+type tenantKey struct{}
+
+/*
+tenantCache guarda as visões por inquilino de um carregador
+
+mu sync.Mutex - Protege os demais campos
+revision string - A revisão do carregador em que as visões foram criadas
+views map[string]ConfigView - As visões, por inquilino
+*/
+type tenantCache struct {
+	mu       sync.Mutex
+	revision string
+	views    map[string]ConfigView
+}
+
+/*
+WithTenant habilita a sobreposição do inquilino informado
+
+Depois do arquivo .env.<env> e das sobreposições por plataforma, o carregador aplica, se existir no mesmo
+diretório, o arquivo .env.<env>.<inquilino> (ex.: .env.production.acme), para aplicações que executam um
+processo por inquilino. Para atender vários inquilinos no mesmo processo, use Tenant ou TenantMiddleware.
+
+@param tenant string - O nome do inquilino, com letras, dígitos, "-" ou "_"; é convertido para letras minúsculas
+
+@return Option - A opção que habilita a sobreposição do inquilino
+*/
+func WithTenant(tenant string) Option {
+	return func(f *FileEnvLoader) {
+		f.tenant = normalizeEnv(tenant)
+	}
+}
+
+/*
+Tenant retorna a configuração de um inquilino, sem alterar o ambiente do processo
+
+A visão contém as variáveis do último carregamento com as do arquivo .env.<env>.<inquilino>, do diretório do
+arquivo base, aplicadas por cima; os valores cifrados são decifrados e os segredos são classificados como no
+carregamento. Um inquilino sem arquivo próprio recebe a configuração base. As visões são guardadas até a próxima
+recarga que altere a revisão do carregador, e Tenant é seguro para uso concorrente.
+
+@param tenant string - O nome do inquilino, com letras, dígitos, "-" ou "_"
+
+@return ConfigView - A configuração do inquilino
+@return error - Um erro se o nome for inválido, o ambiente não tiver sido carregado ou o arquivo não puder ser lido
+*/
+func (f *FileEnvLoader) Tenant(tenant string) (ConfigView, error) {
+	tenant = normalizeEnv(tenant)
+	if !validTenant(tenant) {
+		return ConfigView{}, fmt.Errorf("nome de inquilino inválido: %q", tenant)
+	}
+	if f.baseFile == "" {
+		return ConfigView{}, fmt.Errorf("o ambiente ainda não foi carregado: chame LoadEnv antes de Tenant")
+	}
+
+	f.tenants.mu.Lock()
+	defer f.tenants.mu.Unlock()
+
+	if f.tenants.revision != f.revision {
+		f.tenants.revision = f.revision
+		f.tenants.views = make(map[string]ConfigView)
+	}
+	if view, ok := f.tenants.views[tenant]; ok {
+		return view, nil
+	}
+
+	view, err := f.tenantView(tenant)
+	if err != nil {
+		return ConfigView{}, err
+	}
+	f.tenants.views[tenant] = view
+
+	return view, nil
+}
+
+/*
+tenantView cria a visão de um inquilino a partir do último carregamento
+
+@param tenant string - O nome do inquilino, já validado
+
+@return ConfigView - A configuração do inquilino
+@return error - Um erro se o arquivo do inquilino não puder ser lido ou decifrado
+*/
+func (f *FileEnvLoader) tenantView(tenant string) (ConfigView, error) {
+	view := *f.Snapshot().reader()
+	view.noProcessEnv = f.noProcessEnv
+
+	env := strings.TrimPrefix(filepath.Base(f.baseFile), ".env.")
+	file := f.joinPath(f.dirOf(f.baseFile), ".env."+env+"."+tenant)
+	if _, err := f.statFile(file); errors.Is(err, fs.ErrNotExist) {
+		return ConfigView{loader: &view}, nil
+	}
+	entries, err := f.loadEnvFile(file)
+	if err != nil {
+		return ConfigView{}, err
+	}
+
+	view.lines = make(map[string]int, len(f.lines)+len(entries))
+	for key, line := range f.lines {
+		view.lines[key] = line
+	}
+
+	for _, e := range entries {
+		value, encrypted := e.value, IsEncrypted(e.value)
+		if encrypted {
+			if value, err = f.decryptValue(value); err != nil {
+				return ConfigView{}, fmt.Errorf("erro ao decifrar a variável %s do inquilino %s: %s", e.key, tenant, err.Error())
+			}
+		}
+
+		_, secret := view.secrets[e.key]
+		if secret = secret || f.isSecret(e.key, map[string]bool{e.key: encrypted}); secret {
+			view.secrets[e.key] = value
+		}
+		if !secret || !f.isolateSecrets {
+			view.values[e.key] = value
+		}
+		view.sources[e.key] = file
+		view.lines[e.key] = e.line
+	}
+	view.revision = configRevision(f.revisionKey, view.Env, view.values, view.secrets)
+
+	return ConfigView{loader: &view}, nil
+}
+
+/*
+TenantMiddleware cria um middleware HTTP que seleciona a configuração do inquilino de cada requisição
+
+O inquilino é lido do cabeçalho informado e a sua configuração, obtida com Tenant, é guardada no contexto da
+requisição, de onde os handlers a leem com FromContext; o nome do inquilino fica disponível em TenantFromContext.
+Requisições sem o cabeçalho recebem a configuração base. Nomes inválidos são recusados com 400.
+
+@param f *FileEnvLoader - O carregador, já carregado
+@param header string - O cabeçalho com o nome do inquilino, ou uma string vazia para DefaultTenantHeader
+@param next http.Handler - O handler seguinte
+
+@return http.Handler - O handler com o middleware
+*/
+func TenantMiddleware(f *FileEnvLoader, header string, next http.Handler) http.Handler {
+	if header == "" {
+		header = DefaultTenantHeader
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		tenant := normalizeEnv(req.Header.Get(header))
+		if tenant == "" {
+			next.ServeHTTP(w, req.WithContext(ContextWithReader(req.Context(), f)))
+			return
+		}
+		if !validTenant(tenant) {
+			http.Error(w, fmt.Sprintf("cabeçalho %s inválido", header), http.StatusBadRequest)
+			return
+		}
+
+		view, err := f.Tenant(tenant)
+		if err != nil {
+			if f.logs(LogError) {
+				logger.Error(fmt.Sprintf("Erro ao carregar a configuração do inquilino %s: %s", tenant, err.Error()))
+			}
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		ctx := context.WithValue(ContextWithReader(req.Context(), view), tenantKey{}, tenant)
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+/*
+TenantFromContext retorna o inquilino selecionado por TenantMiddleware
+
+@param ctx context.Context - O contexto da requisição
+
+@return string - O nome do inquilino, ou uma string vazia se a requisição não informou um
+*/
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+/*
+validTenant verifica se um nome de inquilino pode ser usado em um nome de arquivo
+
+@param tenant string - O nome do inquilino, já normalizado
+
+@return bool - Se o nome possui apenas letras minúsculas, dígitos, "-" ou "_", com até 64 caracteres
+*/
+func validTenant(tenant string) bool {
+	if tenant == "" || len(tenant) > 64 {
+		return false
+	}
+	for _, r := range tenant {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+
+	return true
+}
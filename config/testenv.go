@@ -0,0 +1,46 @@
+package config
+
+/*
+TestingT é o subconjunto de *testing.T usado por ApplyT
+
+Com a interface, o pacote config não depende do pacote testing; *testing.T, *testing.B e *testing.F a
+implementam.
+*/
+type TestingT interface {
+	Helper()
+	Setenv(key string, value string)
+	Fatalf(format string, args ...any)
+}
+
+/*
+ApplyT carrega o ambiente em um teste, aplicando cada variável com t.Setenv
+
+As variáveis definidas pelo carregador voltam ao valor anterior ao fim do teste, e o pacote testing recusa o uso
+em testes paralelos (t.Setenv entra em pânico depois de t.Parallel), que compartilham o ambiente do processo. A
+precedência entre o processo e os arquivos é a mesma de LoadEnv, e os próximos Reload também usam t.Setenv.
+
+Com WithNoProcessEnv ou WithNoDiscovery, o carregador continua sem ler o ambiente do processo, mas as variáveis
+deste carregamento são exportadas com t.Setenv para o código sob teste. Se o carregamento falhar, o teste é
+interrompido com t.Fatalf.
+
+@param t TestingT - O teste, normalmente um *testing.T
+
+@return *Result - O resumo do carregamento
+*/
+func (f *FileEnvLoader) ApplyT(t TestingT) *Result {
+	t.Helper()
+
+	f.setenv = t.Setenv
+	result, err := f.LoadEnvResult()
+	if err != nil {
+		t.Fatalf("locenv: erro ao carregar variáveis de ambiente: %s", err)
+		return nil
+	}
+	if f.noProcessEnv {
+		for _, key := range sortedKeys(f.values) {
+			t.Setenv(key, f.values[key])
+		}
+	}
+
+	return result
+}
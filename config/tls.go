@@ -0,0 +1,158 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultTLSPrefix é o prefixo das variáveis lidas por TLSConfig quando nenhum é informado.
+const DefaultTLSPrefix = "TLS"
+
+// tlsVersions associa os valores aceitos em <prefixo>_MIN_VERSION às versões do crypto/tls.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+/*
+WithTLS declara as variáveis de TLS de um prefixo, para que sejam validadas durante o carregamento
+
+Os arquivos <prefixo>_CERT_FILE, <prefixo>_KEY_FILE e <prefixo>_CA_FILE e os conteúdos em base64
+<prefixo>_CERT_B64, <prefixo>_KEY_B64 e <prefixo>_CA_B64, quando definidos, precisam conter blocos PEM.
+<prefixo>_KEY_B64 é classificada como segredo, pois contém a chave privada.
+
+@param prefix string - O prefixo das variáveis, ou uma string vazia para DefaultTLSPrefix
+
+@return Option - A opção que declara as variáveis
+*/
+func WithTLS(prefix string) Option {
+	if prefix == "" {
+		prefix = DefaultTLSPrefix
+	}
+
+	return func(f *FileEnvLoader) {
+		for _, part := range []string{"CERT", "KEY", "CA"} {
+			WithFileContent(prefix+"_"+part+"_FILE", PEMCheck)(f)
+			WithBase64Content(prefix+"_"+part+"_B64", PEMCheck)(f)
+		}
+		f.secretPatterns = append(f.secretPatterns, prefix+"_KEY_B64")
+	}
+}
+
+/*
+TLSConfig monta um *tls.Config a partir do conjunto convencional de variáveis de TLS
+
+As variáveis lidas, com o prefixo informado, são:
+
+  - <prefixo>_CERT_FILE e <prefixo>_KEY_FILE, ou <prefixo>_CERT_B64 e <prefixo>_KEY_B64: o certificado e a
+    chave privada, que devem ser informados juntos;
+  - <prefixo>_CA_FILE ou <prefixo>_CA_B64: as autoridades certificadoras confiáveis, usadas como RootCAs e
+    ClientCAs;
+  - <prefixo>_SERVER_NAME: o nome esperado no certificado do servidor;
+  - <prefixo>_MIN_VERSION: a versão mínima (1.0, 1.1, 1.2 ou 1.3), 1.2 por padrão;
+  - <prefixo>_INSECURE: desativa a verificação do certificado do servidor, recusada em produção.
+
+Todos os problemas encontrados são reunidos em um único *ValidationError.
+
+@param r Reader - A configuração
+@param prefix string - O prefixo das variáveis, ou uma string vazia para DefaultTLSPrefix
+
+@return *tls.Config - A configuração de TLS
+@return error - Um *ValidationError com os problemas encontrados
+*/
+func TLSConfig(r Reader, prefix string) (*tls.Config, error) {
+	if prefix == "" {
+		prefix = DefaultTLSPrefix
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	var problems []string
+
+	cert, certOK, err := tlsContent(r, prefix+"_CERT")
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	key, keyOK, err := tlsContent(r, prefix+"_KEY")
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	switch {
+	case certOK && keyOK:
+		pair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("par de certificado e chave %s inválido: %s", prefix, err.Error()))
+		} else {
+			cfg.Certificates = []tls.Certificate{pair}
+		}
+	case certOK:
+		problems = append(problems, fmt.Sprintf("certificado %s_CERT informado sem a chave %s_KEY", prefix, prefix))
+	case keyOK:
+		problems = append(problems, fmt.Sprintf("chave %s_KEY informada sem o certificado %s_CERT", prefix, prefix))
+	}
+	zeroBytes(key)
+
+	ca, caOK, err := tlsContent(r, prefix+"_CA")
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	if caOK {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			problems = append(problems, fmt.Sprintf("%s_CA não contém nenhum certificado válido", prefix))
+		}
+		cfg.RootCAs, cfg.ClientCAs = pool, pool
+	}
+
+	cfg.ServerName = r.GetString(prefix + "_SERVER_NAME")
+
+	if version, ok := r.Lookup(prefix + "_MIN_VERSION"); ok {
+		v, known := tlsVersions[strings.TrimPrefix(strings.ToLower(strings.TrimSpace(version)), "tls")]
+		if !known {
+			problems = append(problems, fmt.Sprintf("variável %s_MIN_VERSION: versão %q desconhecida (use 1.0, 1.1, 1.2 ou 1.3)", prefix, version))
+		}
+		cfg.MinVersion = v
+	}
+
+	insecure, err := r.GetBool(prefix + "_INSECURE")
+	switch {
+	case err == nil && insecure && r.IsProduction():
+		problems = append(problems, fmt.Sprintf("%s_INSECURE não é permitido em produção", prefix))
+	case err == nil:
+		cfg.InsecureSkipVerify = insecure
+	case !errors.Is(err, ErrKeyNotFound):
+		problems = append(problems, err.Error())
+	}
+
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	return cfg, nil
+}
+
+/*
+tlsContent lê o conteúdo PEM de <nome>_FILE ou, na falta dele, de <nome>_B64
+
+@param r Reader - A configuração
+@param name string - O nome da variável sem o sufixo (ex.: TLS_CERT)
+
+@return []byte - O conteúdo
+@return bool - Se alguma das variáveis foi definida
+@return error - Um erro se o arquivo não puder ser lido ou o valor não for base64 válido
+*/
+func tlsContent(r Reader, name string) ([]byte, bool, error) {
+	content, err := r.GetFileContent(name + "_FILE")
+	if errors.Is(err, ErrKeyNotFound) {
+		content, err = r.GetBytesBase64(name + "_B64")
+	}
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, false, nil
+	}
+
+	return content, err == nil, err
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/jonh-dev/go-logger/logger"
+)
+
+// traceEnvVar é a variável de ambiente que ativa o rastreamento da descoberta quando igual a "1".
+const traceEnvVar = "LOCENV_DEBUG"
+
+/*
+WithTrace ativa o rastreamento da descoberta do arquivo .env
+
+Com o rastreamento ativo, o carregador registra cada diretório visitado, cada arquivo candidato considerado,
+o motivo de cada candidato ignorado e a decisão final. O mesmo efeito é obtido definindo LOCENV_DEBUG=1.
+
+@return Option - A opção que ativa o rastreamento
+*/
+func WithTrace() Option {
+	return func(f *FileEnvLoader) {
+		f.trace = true
+	}
+}
+
+/*
+tracef registra uma mensagem de rastreamento quando o rastreamento está ativo e o nível de log é LogInfo
+
+@param format string - O formato da mensagem, no padrão de fmt.Sprintf
+@param args ...interface{} - Os argumentos do formato
+*/
+func (f *FileEnvLoader) tracef(format string, args ...interface{}) {
+	if !f.trace || !f.logs(LogInfo) {
+		return
+	}
+
+	logger.Info("locenv: " + fmt.Sprintf(format, args...))
+}
+
+/*
+noteCandidate registra a decisão sobre um arquivo candidato da descoberta
+
+A decisão é registrada no rastreamento e, durante um diagnóstico (veja Diagnose), na lista de candidatos.
+
+@param path string - O caminho do arquivo candidato
+@param profile string - O perfil do arquivo, como aparece no nome
+@param reason string - A decisão e o seu motivo
+*/
+func (f *FileEnvLoader) noteCandidate(path string, profile string, reason string) {
+	f.tracef("candidato %s %s", path, reason)
+
+	if f.candidates != nil {
+		*f.candidates = append(*f.candidates, Candidate{Path: path, Profile: profile, Reason: reason})
+	}
+}
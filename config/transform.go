@@ -0,0 +1,146 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/*
+TransformContext identifica a declaração cujo valor está sendo transformado
+
+Key string - O nome da variável
+File string - O arquivo da declaração
+Line int - A linha da declaração
+*/
+type TransformContext struct {
+	Key  string
+	File string
+	Line int
+}
+
+// Transformer transforma o valor de uma declaração durante o carregamento.
+type Transformer func(value string, ctx TransformContext) (string, error)
+
+/*
+transformRule associa transformações a um conjunto de variáveis, definida por WithTransform
+
+patterns []string - Os padrões de nomes das variáveis (sintaxe de path.Match)
+transformers []Transformer - As transformações, na ordem em que são aplicadas
+*/
+type transformRule struct {
+	patterns     []string
+	transformers []Transformer
+}
+
+/*
+WithTransform aplica transformações aos valores das variáveis que correspondem a algum dos padrões
+
+As transformações são aplicadas a cada declaração, na leitura do arquivo, antes da combinação das camadas,
+para que cada valor seja interpretado em relação ao arquivo que o declarou. Isso corrige uma classe comum de
+erros: um caminho relativo em um arquivo .env que deixa de funcionar quando o programa é executado a partir de
+outro diretório.
+
+	WithTransform([]string{"*_DIR", "*_FILE"}, ExpandHome, AbsPath)
+	WithTransform([]string{"LOG_LEVEL"}, TrimSpace, Lowercase)
+
+Com várias chamadas, as transformações de todas as regras correspondentes são aplicadas, na ordem em que
+foram registradas. Um erro de transformação interrompe o carregamento.
+
+@param keys []string - Os padrões de nomes das variáveis (sintaxe de path.Match)
+@param transformers ...Transformer - As transformações, na ordem em que são aplicadas
+
+@return Option - A opção que registra as transformações
+*/
+func WithTransform(keys []string, transformers ...Transformer) Option {
+	return func(f *FileEnvLoader) {
+		f.transforms = append(f.transforms, transformRule{patterns: keys, transformers: transformers})
+	}
+}
+
+// TrimSpace remove os espaços nas extremidades do valor.
+func TrimSpace(value string, _ TransformContext) (string, error) {
+	return strings.TrimSpace(value), nil
+}
+
+// Lowercase converte o valor para letras minúsculas.
+func Lowercase(value string, _ TransformContext) (string, error) {
+	return strings.ToLower(value), nil
+}
+
+// Uppercase converte o valor para letras maiúsculas.
+func Uppercase(value string, _ TransformContext) (string, error) {
+	return strings.ToUpper(value), nil
+}
+
+/*
+ExpandHome substitui o ~ no início do valor pelo diretório do usuário
+
+Apenas ~ e ~/... são expandidos; ~outro_usuario é mantido.
+
+@param value string - O valor
+@param ctx TransformContext - A declaração
+
+@return string - O valor com o diretório do usuário
+@return error - Um erro se o diretório do usuário não puder ser determinado
+*/
+func ExpandHome(value string, ctx TransformContext) (string, error) {
+	if value != "~" && !strings.HasPrefix(value, "~/") && !strings.HasPrefix(value, `~`+string(filepath.Separator)) {
+		return value, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, value[1:]), nil
+}
+
+/*
+AbsPath converte um caminho relativo em absoluto, em relação ao diretório do arquivo que o declarou
+
+Valores vazios, caminhos absolutos e caminhos iniciados por ~ (veja ExpandHome) são mantidos. Com WithFS, o
+diretório do arquivo é interpretado no sistema de arquivos do sistema operacional.
+
+@param value string - O caminho
+@param ctx TransformContext - A declaração
+
+@return string - O caminho absoluto
+@return error - Um erro se o diretório atual não puder ser determinado
+*/
+func AbsPath(value string, ctx TransformContext) (string, error) {
+	if value == "" || filepath.IsAbs(value) || strings.HasPrefix(value, "~") {
+		return value, nil
+	}
+
+	return filepath.Abs(filepath.Join(filepath.Dir(ctx.File), value))
+}
+
+/*
+transformValue aplica as transformações de WithTransform a uma declaração
+
+@param e entry - A declaração
+@param file string - O arquivo da declaração
+
+@return string - O valor transformado
+@return error - Um erro de alguma transformação, com o arquivo, a linha e a variável
+*/
+func (f *FileEnvLoader) transformValue(e entry, file string) (string, error) {
+	value := e.value
+	ctx := TransformContext{Key: e.key, File: file, Line: e.line}
+	for _, rule := range f.transforms {
+		if !matchesAny(rule.patterns, e.key) {
+			continue
+		}
+		for _, transform := range rule.transformers {
+			var err error
+			if value, err = transform(value, ctx); err != nil {
+				return "", fmt.Errorf("%s:%d: transformação de %s: %w", file, e.line, e.key, err)
+			}
+		}
+	}
+
+	return value, nil
+}
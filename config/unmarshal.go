@@ -0,0 +1,315 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// durationType é o tipo refletido de time.Duration, convertido com time.ParseDuration.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+/*
+Unmarshal preenche uma struct com as variáveis da configuração, conforme as tags env dos seus campos
+
+A tag segue a convenção env:"NOME,opções"; campos sem a tag, com env:"-" ou não exportados são ignorados, e
+structs embutidas sem a tag são preenchidas com os mesmos nomes. A única opção é required, que torna a ausência
+da variável um erro; variáveis opcionais ausentes mantêm o valor atual do campo, que serve como padrão.
+
+Os campos podem ser strings, inteiros, números de ponto flutuante, bool, time.Duration, structs e slices:
+
+  - Uma struct usa o nome da tag como seção: Database `env:"DATABASE"` lê DATABASE_HOST no campo Host `env:"HOST"`.
+  - Um slice de structs usa índices decimais a partir de 0, sem zeros à esquerda: Servers `env:"SERVERS"` lê
+    SERVERS_0_HOST, SERVERS_0_PORT, SERVERS_1_HOST e assim por diante. Índices não contíguos são um erro,
+    para que um item esquecido ou com nome errado seja detectado na inicialização, e uma lista pode ter no máximo
+    10000 itens.
+  - Um slice de valores simples lê a variável separada por vírgulas (HOSTS=a,b) ou, se ela não existir, os
+    itens indexados (HOSTS_0=a, HOSTS_1=b).
+
+Os segredos são lidos com GetSecret quando a variável não é encontrada por Lookup, de modo que segredos isolados
+com WithSecretIsolation também podem ser usados. Todos os problemas encontrados são reunidos em um único
+*ValidationError.
+
+@param r Reader - A configuração
+@param v any - Um ponteiro para a struct a preencher
+
+@return error - Um *ValidationError com os problemas encontrados, ou um erro se v não for um ponteiro para struct
+*/
+func Unmarshal(r Reader, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Unmarshal espera um ponteiro para struct, recebido %T", v)
+	}
+
+	var problems []string
+	decodeStruct(r, "", rv.Elem(), &problems)
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+/*
+decodeStruct preenche os campos de uma struct com as variáveis de uma seção
+
+@param r Reader - A seção da configuração
+@param path string - O prefixo completo da seção, usado nas mensagens de erro
+@param rv reflect.Value - A struct a preencher
+@param problems *[]string - Os problemas encontrados
+*/
+func decodeStruct(r Reader, path string, rv reflect.Value, problems *[]string) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, ok := field.Tag.Lookup("env")
+		if !ok {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				decodeStruct(r, path, rv.Field(i), problems)
+			}
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		decodeField(r, name, path+name, rv.Field(i), opts == "required", problems)
+	}
+}
+
+/*
+decodeField preenche um campo com a variável ou a seção de mesmo nome
+
+@param r Reader - A seção da configuração que contém o campo
+@param name string - O nome da variável, relativo à seção
+@param full string - O nome completo da variável, usado nas mensagens de erro
+@param rv reflect.Value - O campo a preencher
+@param required bool - Se a ausência da variável é um erro
+@param problems *[]string - Os problemas encontrados
+*/
+func decodeField(r Reader, name string, full string, rv reflect.Value, required bool, problems *[]string) {
+	switch {
+	case rv.Kind() == reflect.Struct:
+		sub := r.Sub(name)
+		if required && len(sub.All()) == 0 {
+			*problems = append(*problems, fmt.Sprintf("seção obrigatória %s não definida", full))
+			return
+		}
+		decodeStruct(sub, full+"_", rv, problems)
+		return
+
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Struct:
+		decodeIndexed(r.Sub(name), full, rv, required, problems)
+		return
+
+	case rv.Kind() == reflect.Slice:
+		if value, ok := lookupValue(r, name); ok {
+			var items []string
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			setSlice(rv, full, items, problems)
+			return
+		}
+		decodeIndexed(r.Sub(name), full, rv, required, problems)
+		return
+	}
+
+	value, ok := lookupValue(r, name)
+	if !ok {
+		if required {
+			*problems = append(*problems, fmt.Sprintf("variável obrigatória %s não definida", full))
+		}
+		return
+	}
+	if err := setValue(rv, value); err != nil {
+		*problems = append(*problems, fmt.Sprintf("variável %s: %s", full, err.Error()))
+	}
+}
+
+// maxIndexedItems é o maior número de itens de uma lista indexada, para que um índice como SERVERS_999999999 não aloque a lista inteira.
+const maxIndexedItems = 10000
+
+/*
+decodeIndexed preenche um slice com os itens indexados de uma seção (0, 1, ... ou 0_CAMPO, 1_CAMPO, ...)
+
+@param r Reader - A seção do slice
+@param full string - O nome completo do slice, usado nas mensagens de erro
+@param rv reflect.Value - O slice a preencher
+@param required bool - Se a ausência de itens é um erro
+@param problems *[]string - Os problemas encontrados
+*/
+func decodeIndexed(r Reader, full string, rv reflect.Value, required bool, problems *[]string) {
+	indices := make(map[int]bool)
+	for key := range r.All() {
+		head, _, _ := strings.Cut(key, "_")
+		if n, err := strconv.Atoi(head); err == nil && n >= 0 && strconv.Itoa(n) == head {
+			indices[n] = true
+		}
+	}
+
+	if len(indices) == 0 {
+		if required {
+			*problems = append(*problems, fmt.Sprintf("lista obrigatória %s não definida (esperado %s_0...)", full, full))
+		}
+		return
+	}
+
+	last := 0
+	for n := range indices {
+		if n > last {
+			last = n
+		}
+	}
+	if last >= maxIndexedItems {
+		*problems = append(*problems, fmt.Sprintf("lista %s com índice %d acima do limite de %d itens", full, last, maxIndexedItems))
+		return
+	}
+	if missing := missingRanges(full, indices, last); len(missing) > 0 {
+		*problems = append(*problems, fmt.Sprintf("lista %s com índices ausentes: %s", full, strings.Join(missing, ", ")))
+		return
+	}
+
+	slice := reflect.MakeSlice(rv.Type(), last+1, last+1)
+	for n := 0; n <= last; n++ {
+		index := strconv.Itoa(n)
+		item := slice.Index(n)
+		if item.Kind() == reflect.Struct {
+			decodeStruct(r.Sub(index), full+"_"+index+"_", item, problems)
+			continue
+		}
+		if value, ok := lookupValue(r, index); ok {
+			if err := setValue(item, value); err != nil {
+				*problems = append(*problems, fmt.Sprintf("variável %s_%s: %s", full, index, err.Error()))
+			}
+		}
+	}
+	rv.Set(slice)
+}
+
+/*
+missingRanges lista os índices ausentes de uma lista indexada, agrupando os consecutivos em intervalos
+
+@param full string - O nome completo da lista
+@param indices map[int]bool - Os índices definidos
+@param last int - O maior índice definido
+
+@return []string - Os índices ausentes, como SERVERS_1 ou SERVERS_3..SERVERS_7
+*/
+func missingRanges(full string, indices map[int]bool, last int) []string {
+	var missing []string
+	for n := 0; n <= last; n++ {
+		if indices[n] {
+			continue
+		}
+		end := n
+		for end+1 <= last && !indices[end+1] {
+			end++
+		}
+		if end == n {
+			missing = append(missing, full+"_"+strconv.Itoa(n))
+		} else {
+			missing = append(missing, full+"_"+strconv.Itoa(n)+".."+full+"_"+strconv.Itoa(end))
+		}
+		n = end
+	}
+
+	return missing
+}
+
+/*
+setSlice preenche um slice de valores simples com os itens informados
+
+@param rv reflect.Value - O slice a preencher
+@param full string - O nome completo da variável, usado nas mensagens de erro
+@param items []string - Os itens
+@param problems *[]string - Os problemas encontrados
+*/
+func setSlice(rv reflect.Value, full string, items []string, problems *[]string) {
+	slice := reflect.MakeSlice(rv.Type(), len(items), len(items))
+	for i, item := range items {
+		if err := setValue(slice.Index(i), item); err != nil {
+			*problems = append(*problems, fmt.Sprintf("variável %s, item %d: %s", full, i, err.Error()))
+			return
+		}
+	}
+	rv.Set(slice)
+}
+
+/*
+lookupValue lê uma variável da configuração, recorrendo aos segredos quando ela não é encontrada
+
+@param r Reader - A configuração
+@param key string - O nome da variável
+
+@return string - O valor da variável
+@return bool - Se a variável foi encontrada
+*/
+func lookupValue(r Reader, key string) (string, bool) {
+	if value, ok := r.Lookup(key); ok {
+		return value, true
+	}
+
+	return r.GetSecret(key)
+}
+
+/*
+setValue converte um valor para o tipo do campo e o atribui
+
+@param rv reflect.Value - O campo
+@param value string - O valor lido da configuração
+
+@return error - Um erro se o valor não puder ser convertido ou o tipo não for suportado
+*/
+func setValue(rv reflect.Value, value string) error {
+	if rv.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("valor %q não pode ser convertido para duration", value)
+		}
+		rv.SetInt(int64(d))
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("valor %q não pode ser convertido para bool", value)
+		}
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, rv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("valor %q não pode ser convertido para %s", value, rv.Type())
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, rv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("valor %q não pode ser convertido para %s", value, rv.Type())
+		}
+		rv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, rv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("valor %q não pode ser convertido para %s", value, rv.Type())
+		}
+		rv.SetFloat(n)
+	default:
+		return fmt.Errorf("tipo %s não suportado", rv.Type())
+	}
+
+	return nil
+}
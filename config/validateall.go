@@ -0,0 +1,208 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+EnvValidation é o resultado da validação de um ambiente por ValidateAll
+
+Env string - O ambiente
+Files []string - Os arquivos carregados, relativos ao diretório validado
+Keys []string - As variáveis declaradas, em ordem alfabética
+Missing []string - As variáveis declaradas em outros ambientes e ausentes neste
+Problems []string - Os problemas da validação, como variáveis obrigatórias ausentes e valores inválidos
+*/
+type EnvValidation struct {
+	Env      string   `json:"env"`
+	Files    []string `json:"files"`
+	Keys     []string `json:"keys"`
+	Missing  []string `json:"missing,omitempty"`
+	Problems []string `json:"problems,omitempty"`
+}
+
+/*
+ValidationMatrix é o relatório de ValidateAll, com uma coluna por ambiente e uma linha por variável
+
+Envs []EnvValidation - Os ambientes, em ordem alfabética
+Keys []string - Todas as variáveis declaradas em algum ambiente, em ordem alfabética
+*/
+type ValidationMatrix struct {
+	Envs []EnvValidation `json:"envs"`
+	Keys []string        `json:"keys"`
+}
+
+/*
+ValidateAll carrega e valida cada arquivo .env.<ambiente> de um diretório
+
+Os arquivos de exemplo (.example, .sample, .template), as sobreposições (nomes com mais de um ponto após .env)
+e os arquivos .env.local são ignorados. Cada ambiente é resolvido com WithEnv, em um sistema de arquivos
+restrito ao diretório e sem o ambiente do processo, e passa pelas mesmas regras de opts, como WithSchema e
+WithRequired. Assim, um problema como "staging não tem NEW_FLAG" aparece na CI, antes do dia do deploy. Além
+dos problemas da validação, o relatório aponta em Missing as variáveis que existem em outros ambientes.
+
+@param dir string - O diretório com os arquivos .env
+@param opts ...Option - As opções aplicadas a cada ambiente
+
+@return *ValidationMatrix - O relatório
+@return error - Um erro se o diretório não puder ser lido ou não tiver nenhum arquivo .env.<ambiente>
+*/
+func ValidateAll(dir string, opts ...Option) (*ValidationMatrix, error) {
+	envs, err := profilesIn(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(envs) == 0 {
+		return nil, fmt.Errorf("%w em %s", ErrEnvNotFound, dir)
+	}
+
+	matrix := &ValidationMatrix{}
+	all := make(map[string][]string)
+	fsys := os.DirFS(dir)
+	for _, env := range envs {
+		loaderOpts := append(append([]Option(nil), opts...), WithFS(fsys, "."), WithEnv(env), WithNoProcessEnv(), WithSilent())
+		f := NewEnvLoader(loaderOpts...).(*FileEnvLoader)
+		report := EnvValidation{Env: env}
+
+		files, _, err := f.layerFiles()
+		if err == nil {
+			var values map[string]string
+			if values, _, _, err = f.loadLayers(files); err == nil {
+				report.Files = files
+				report.Keys = sortedKeys(values)
+				for _, key := range report.Keys {
+					all[key] = append(all[key], env)
+				}
+				_, err = f.resolve()
+			}
+		}
+
+		var validation *ValidationError
+		switch {
+		case errors.As(err, &validation):
+			report.Problems = validation.Problems
+		case err != nil:
+			report.Problems = []string{err.Error()}
+		}
+		matrix.Envs = append(matrix.Envs, report)
+	}
+
+	matrix.Keys = sortedKeysOf(all)
+	for i := range matrix.Envs {
+		for _, key := range matrix.Keys {
+			if !matrix.Envs[i].Has(key) && matrix.Envs[i].Files != nil {
+				matrix.Envs[i].Missing = append(matrix.Envs[i].Missing, key)
+			}
+		}
+	}
+
+	return matrix, nil
+}
+
+/*
+profilesIn lista os ambientes dos arquivos .env.<ambiente> de um diretório
+
+@param dir string - O diretório
+
+@return []string - Os ambientes, em ordem alfabética
+@return error - Um erro se o diretório não puder ser lido
+*/
+func profilesIn(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var envs []string
+	for _, e := range entries {
+		name := e.Name()
+		env := strings.TrimPrefix(name, ".env.")
+		if e.IsDir() || env == name || env == "" || env == "local" || strings.Contains(env, ".") || isExampleFileName(name) {
+			continue
+		}
+		envs = append(envs, env)
+	}
+	sort.Strings(envs)
+
+	return envs, nil
+}
+
+// Has informa se a variável é declarada no ambiente.
+func (v EnvValidation) Has(key string) bool {
+	i := sort.SearchStrings(v.Keys, key)
+	return i < len(v.Keys) && v.Keys[i] == key
+}
+
+// OK informa se nenhum ambiente tem problemas de validação; as variáveis de Missing não são consideradas.
+func (m *ValidationMatrix) OK() bool {
+	for _, env := range m.Envs {
+		if len(env.Problems) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+Complete informa se todos os ambientes declaram as mesmas variáveis
+
+@return bool - Se nenhum ambiente tem variáveis em Missing
+*/
+func (m *ValidationMatrix) Complete() bool {
+	for _, env := range m.Envs {
+		if len(env.Missing) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+String formata o relatório como uma tabela, com ✔ para as variáveis declaradas e ✘ para as ausentes, seguida
+dos problemas de cada ambiente
+
+@return string - O relatório
+*/
+func (m *ValidationMatrix) String() string {
+	const header = "VARIÁVEL"
+	width := utf8.RuneCountInString(header)
+	for _, key := range m.Keys {
+		if len(key) > width {
+			width = len(key)
+		}
+	}
+
+	row := func(first string, cells func(env EnvValidation) string) string {
+		line := fmt.Sprintf("%-*s", width, first)
+		for _, env := range m.Envs {
+			line += fmt.Sprintf("  %-*s", utf8.RuneCountInString(env.Env), cells(env))
+		}
+		return strings.TrimRight(line, " ") + "\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(row(header, func(env EnvValidation) string { return env.Env }))
+	for _, key := range m.Keys {
+		b.WriteString(row(key, func(env EnvValidation) string {
+			if env.Has(key) {
+				return "✔"
+			}
+			return "✘"
+		}))
+	}
+
+	for _, env := range m.Envs {
+		for _, problem := range env.Problems {
+			fmt.Fprintf(&b, "\n%s: %s", env.Env, problem)
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
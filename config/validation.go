@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+/*
+ValidationError é o erro retornado quando as variáveis carregadas não atendem às regras de validação
+
+Todos os problemas encontrados são reunidos em um único erro, para que possam ser corrigidos de uma só vez.
+
+Problems []string - A descrição de cada problema encontrado
+*/
+type ValidationError struct {
+	Problems []string
+}
+
+// Error lista todos os problemas encontrados.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("configuração inválida: %s", strings.Join(e.Problems, "; "))
+}
+
+/*
+WithRequired declara variáveis obrigatórias
+
+Depois de ler e combinar os arquivos .env, o carregador verifica se cada variável obrigatória foi definida
+em algum arquivo, como segredo ou no ambiente do processo. Se alguma estiver ausente, LoadEnv e Plan
+retornam um *ValidationError com todas as variáveis ausentes, sem aplicar nada ao ambiente do processo.
+
+@param keys ...string - Os nomes das variáveis obrigatórias
+
+@return Option - A opção que declara as variáveis obrigatórias
+*/
+func WithRequired(keys ...string) Option {
+	return func(f *FileEnvLoader) {
+		f.required = append(f.required, keys...)
+	}
+}
+
+/*
+validate verifica as variáveis combinadas contra as regras de validação do carregador
+
+@param values map[string]string - As variáveis a serem aplicadas
+@param secrets map[string]string - As variáveis classificadas como segredo
+@param origins map[string]origin - A declaração de cada variável
+
+@return error - Um *ValidationError com todos os problemas encontrados, ou nil
+*/
+func (f *FileEnvLoader) validate(values map[string]string, secrets map[string]string, origins map[string]origin) error {
+	var problems []string
+	for _, key := range f.required {
+		if !f.isDefined(key, values, secrets) {
+			problems = append(problems, fmt.Sprintf("variável obrigatória %s não definida", key))
+		}
+	}
+	problems = append(problems, f.validateConditions(values, secrets)...)
+	problems = append(problems, f.validateGroups(values, secrets)...)
+	problems = append(problems, f.validateExclusive(values, secrets, origins)...)
+	problems = append(problems, f.validateTypes(values, secrets)...)
+	problems = append(problems, f.validateContents(values, secrets)...)
+	problems = append(problems, f.validatePaths(values, secrets, origins)...)
+	problems = append(problems, f.validateCrons(values, secrets)...)
+	problems = append(problems, f.validateJSONValues(values, secrets)...)
+	problems = append(problems, f.validatePlaceholders(values, secrets, origins)...)
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+/*
+isDefined verifica se uma variável foi definida nos arquivos, como segredo ou no ambiente do processo
+
+Com WithNoProcessEnv, o ambiente do processo não é consultado.
+
+@param key string - O nome da variável
+@param values map[string]string - As variáveis a serem aplicadas
+@param secrets map[string]string - As variáveis classificadas como segredo
+
+@return bool - Se a variável foi definida
+*/
+func (f *FileEnvLoader) isDefined(key string, values map[string]string, secrets map[string]string) bool {
+	if _, ok := values[key]; ok {
+		return true
+	}
+	if _, ok := secrets[key]; ok {
+		return true
+	}
+	if f.noProcessEnv {
+		return false
+	}
+	_, ok := os.LookupEnv(key)
+
+	return ok
+}
@@ -0,0 +1,68 @@
+package config
+
+/*
+WithValues adiciona variáveis literais ao carregador, como uma camada acima dos arquivos .env
+
+As variáveis passam pelo mesmo pipeline dos arquivos (hooks, filtros, validação e segredos), com Source igual a
+SourceMemory, mas os valores são literais: não há expansão de ${VAR}, substituição de comandos nem geração de
+valores. Com WithNoDiscovery, são a única camada além dos arquivos de WithFiles. O mapa recebido é copiado, e
+chamadas repetidas acumulam as variáveis, prevalecendo a última definição de cada uma.
+
+@param values map[string]string - As variáveis
+
+@return Option - A opção que adiciona as variáveis
+*/
+func WithValues(values map[string]string) Option {
+	return func(f *FileEnvLoader) {
+		if f.literals == nil {
+			f.literals = make(map[string]string, len(values))
+		}
+		for key, value := range values {
+			f.literals[key] = value
+		}
+	}
+}
+
+/*
+WithNoDiscovery desliga a descoberta de arquivos .env e o acesso ao ambiente do processo
+
+O carregador passa a ler apenas os arquivos de WithFiles e as variáveis de WithValues, sem procurar arquivos a
+partir do diretório de trabalho, sem ler APP_ENV (o ambiente fica vazio, a menos que WithEnv seja usada) e com o
+comportamento de WithNoProcessEnv. É indicada para testes unitários construídos inteiramente a partir de literais,
+que não podem depender do sistema de arquivos nem do ambiente de quem os executa:
+
+	loader := config.NewEnvLoader(
+		config.WithNoDiscovery(),
+		config.WithValues(map[string]string{"DB_HOST": "localhost"}),
+	)
+
+Sem arquivos nem variáveis, o carregamento é bem-sucedido e não define nada.
+
+@return Option - A opção que desliga a descoberta
+*/
+func WithNoDiscovery() Option {
+	return func(f *FileEnvLoader) {
+		f.noDiscovery = true
+		f.noProcessEnv = true
+		if !f.envPinned {
+			f.Env, f.appEnv, f.envPinned = "", "", true
+		}
+	}
+}
+
+/*
+literalEntries retorna as variáveis de WithValues como as entradas de um arquivo .env, em ordem alfabética
+
+As entradas são marcadas como literais, entre aspas simples, e numeradas a partir da linha 1.
+
+@return []entry - As entradas
+*/
+func (f *FileEnvLoader) literalEntries() []entry {
+	keys := sortedKeys(f.literals)
+	entries := make([]entry, len(keys))
+	for i, key := range keys {
+		entries[i] = entry{key: key, value: f.literals[key], line: i + 1, endLine: i + 1, column: 1, quote: '\''}
+	}
+
+	return entries
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+/*
+WithFS faz a descoberta e a leitura dos arquivos .env em um sistema de arquivos virtual
+
+A busca começa em dir e sobe até a raiz de fsys, como a busca a partir do diretório de trabalho; as sobreposições
+e os inquilinos são procurados no mesmo fsys. Com um embed.FS, fstest.MapFS ou um fs.FS fornecido pelo
+hospedeiro, o mesmo código de configuração funciona em GOOS=js e GOOS=wasip1, em que não há um diretório de
+trabalho nem um disco de verdade. Os caminhos informados por Source são os caminhos dentro de fsys.
+
+@param fsys fs.FS - O sistema de arquivos
+@param dir string - O diretório inicial da busca, com barras, ou "." para a raiz
+
+@return Option - A opção que define o sistema de arquivos
+*/
+func WithFS(fsys fs.FS, dir string) Option {
+	return func(f *FileEnvLoader) {
+		if dir == "" {
+			dir = "."
+		}
+		f.fsys, f.fsDir = fsys, path.Clean(dir)
+	}
+}
+
+/*
+WithNoProcessEnv mantém as variáveis apenas no carregador, sem ler nem alterar o ambiente do processo
+
+Os valores não são aplicados com os.Setenv e os getters não consultam o ambiente do processo; a precedência entre
+o processo e os arquivos deixa de existir. É indicada para ambientes sem variáveis de processo, como js/wasm e
+edge runtimes, e para testes isolados.
+
+@return Option - A opção que desliga o ambiente do processo
+*/
+func WithNoProcessEnv() Option {
+	return func(f *FileEnvLoader) {
+		f.noProcessEnv = true
+	}
+}
+
+// workingDir retorna o diretório inicial da descoberta: o de WithFS ou o diretório de trabalho.
+func (f *FileEnvLoader) workingDir() (string, error) {
+	if f.fsys != nil {
+		return f.fsDir, nil
+	}
+
+	return os.Getwd()
+}
+
+// walkDir percorre um diretório no sistema de arquivos do carregador.
+func (f *FileEnvLoader) walkDir(dir string, fn fs.WalkDirFunc) error {
+	if f.fsys != nil {
+		return fs.WalkDir(f.fsys, dir, fn)
+	}
+
+	return filepath.WalkDir(dir, fn)
+}
+
+// openFile abre um arquivo no sistema de arquivos do carregador.
+func (f *FileEnvLoader) openFile(name string) (fs.File, error) {
+	if f.fsys != nil {
+		return f.fsys.Open(name)
+	}
+
+	return os.Open(name)
+}
+
+// statFile obtém as informações de um arquivo no sistema de arquivos do carregador.
+func (f *FileEnvLoader) statFile(name string) (fs.FileInfo, error) {
+	if f.fsys != nil {
+		return fs.Stat(f.fsys, name)
+	}
+
+	return os.Stat(name)
+}
+
+// globFiles retorna os arquivos que correspondem a um padrão no sistema de arquivos do carregador, em ordem alfabética.
+func (f *FileEnvLoader) globFiles(pattern string) ([]string, error) {
+	if f.fsys != nil {
+		return fs.Glob(f.fsys, pattern)
+	}
+
+	return filepath.Glob(pattern)
+}
+
+// cleanPath normaliza um caminho com a sintaxe do sistema de arquivos do carregador.
+func (f *FileEnvLoader) cleanPath(name string) string {
+	if f.fsys != nil {
+		return path.Clean(name)
+	}
+
+	return filepath.Clean(name)
+}
+
+// dirOf retorna o diretório de um caminho, com a sintaxe do sistema de arquivos do carregador.
+func (f *FileEnvLoader) dirOf(name string) string {
+	if f.fsys != nil {
+		return path.Dir(name)
+	}
+
+	return filepath.Dir(name)
+}
+
+// joinPath junta os elementos de um caminho, com a sintaxe do sistema de arquivos do carregador.
+func (f *FileEnvLoader) joinPath(elem ...string) string {
+	if f.fsys != nil {
+		return path.Join(elem...)
+	}
+
+	return filepath.Join(elem...)
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/jonh-dev/go-logger/logger"
+)
+
+// WarningKind classifica os avisos entregues por Warnings e WithWarningHandler.
+type WarningKind string
+
+const (
+	// WarningExpired indica uma declaração vencida, anotada com "# expires:".
+	WarningExpired WarningKind = "expired"
+	// WarningDeprecated indica o uso de uma variável obsoleta, declarada com Deprecate.
+	WarningDeprecated WarningKind = "deprecated"
+	// WarningAnnotation indica uma anotação inválida, como uma data de validade ou uma estratégia de combinação.
+	WarningAnnotation WarningKind = "annotation"
+	// WarningConflict indica uma variável definida no processo com um valor diferente do arquivo.
+	WarningConflict WarningKind = "conflict"
+	// WarningExposure indica um arquivo com segredos em texto claro versionado ou fora do .gitignore.
+	WarningExposure WarningKind = "exposure"
+	// WarningGenerated indica uma falha ao gravar os valores gerados com WithPersistGenerated.
+	WarningGenerated WarningKind = "generated"
+	// WarningEnvChange indica uma mudança de APP_ENV depois da criação do carregador.
+	WarningEnvChange WarningKind = "env-change"
+	// WarningCache indica o uso do cache offline ou uma falha ao gravá-lo.
+	WarningCache WarningKind = "cache"
+	// WarningRetry indica uma falha temporária de um backend de decifragem, seguida de uma nova tentativa.
+	WarningRetry WarningKind = "retry"
+	// WarningDrift indica uma variável alterada depois do congelamento da configuração.
+	WarningDrift WarningKind = "drift"
+	// WarningNotification indica uma falha no fluxo de notificações de um PushListener.
+	WarningNotification WarningKind = "notification"
+)
+
+/*
+Warning é um problema que não impede o carregamento, como uma variável obsoleta ou um conflito com o processo
+
+Kind WarningKind - A classificação do aviso
+Key string - A variável relacionada, ou vazio
+File string - O arquivo relacionado, ou vazio
+Line int - A linha da declaração relacionada, ou zero
+Message string - A mensagem, a mesma registrada no log e incluída em Result.Warnings
+*/
+type Warning struct {
+	Kind    WarningKind `json:"kind"`
+	Key     string      `json:"key,omitempty"`
+	File    string      `json:"file,omitempty"`
+	Line    int         `json:"line,omitempty"`
+	Message string      `json:"message"`
+}
+
+// String retorna a mensagem do aviso.
+func (w Warning) String() string {
+	return w.Message
+}
+
+/*
+WithWarningHandler entrega os avisos a uma função, em vez de registrá-los no log da biblioteca
+
+A função é chamada no momento em que cada aviso é gerado, inclusive fora do carregamento (como as divergências
+de WatchFrozen e as falhas de um PushListener), na goroutine que o gerou. Com ela, a aplicação decide como
+apresentar os avisos: no seu próprio log estruturado, em métricas ou como falha em um ambiente de CI. O nível de
+log de WithLogLevel não se aplica aos avisos entregues à função. Os avisos do último carregamento continuam
+disponíveis em Warnings.
+
+@param fn func(Warning) - A função que recebe os avisos
+
+@return Option - A opção que define a função
+*/
+func WithWarningHandler(fn func(Warning)) Option {
+	return func(f *FileEnvLoader) {
+		f.warningHandler = fn
+	}
+}
+
+/*
+Warnings retorna os avisos do último carregamento ou recarga bem-sucedidos
+
+São os mesmos avisos de Result.Warnings, com a classificação, a variável e a declaração de cada um.
+
+@return []Warning - Uma cópia dos avisos, na ordem em que foram gerados
+*/
+func (f *FileEnvLoader) Warnings() []Warning {
+	return append([]Warning(nil), f.warnings...)
+}
+
+/*
+warn entrega avisos à função de WithWarningHandler ou, sem ela, os registra no log conforme o nível de log
+
+@param warnings ...Warning - Os avisos
+*/
+func (f *FileEnvLoader) warn(warnings ...Warning) {
+	for _, w := range warnings {
+		switch {
+		case f.warningHandler != nil:
+			f.warningHandler(w)
+		case f.logs(LogWarning):
+			logger.Warning(w.Message)
+		}
+	}
+}
+
+/*
+warningAt cria um aviso sobre uma declaração, com a mensagem prefixada pelo arquivo e pela linha
+
+@param kind WarningKind - A classificação do aviso
+@param e entry - A declaração
+@param file string - O arquivo da declaração
+@param format string - O formato da mensagem, como em fmt.Sprintf
+@param args ...any - Os argumentos da mensagem
+
+@return Warning - O aviso
+*/
+func warningAt(kind WarningKind, e entry, file string, format string, args ...any) Warning {
+	return Warning{
+		Kind:    kind,
+		Key:     e.key,
+		File:    file,
+		Line:    e.line,
+		Message: fmt.Sprintf("%s:%d: ", file, e.line) + fmt.Sprintf(format, args...),
+	}
+}
+
+/*
+warningMessages retorna as mensagens de uma lista de avisos, como usadas em Result.Warnings
+
+@param warnings []Warning - Os avisos
+
+@return []string - As mensagens, ou nil se não houver avisos
+*/
+func warningMessages(warnings []Warning) []string {
+	if len(warnings) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(warnings))
+	for i, w := range warnings {
+		messages[i] = w.Message
+	}
+
+	return messages
+}
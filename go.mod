@@ -2,7 +2,6 @@ module github.com/jonh-dev/go-locEnv
 
 go 1.20
 
-require (
-	github.com/joho/godotenv v1.5.1
-	github.com/jonh-dev/go-logger v0.0.0-20240106071918-32ecc05d85ca
-)
+require github.com/jonh-dev/go-logger v0.0.0-20240106071918-32ecc05d85ca
+
+require go.uber.org/goleak v1.3.0
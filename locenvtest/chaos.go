@@ -0,0 +1,214 @@
+package locenvtest
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+// ErrChaosUnavailable é o erro padrão das falhas simuladas por ChaosProvider.
+var ErrChaosUnavailable = errors.New("provedor indisponível (falha simulada)")
+
+// Fault é o resultado simulado de uma chamada a um ChaosProvider.
+type Fault int
+
+const (
+	// Pass delega a chamada ao Decrypter real.
+	Pass Fault = iota
+	// Fail retorna o erro de falha, sem chamar o Decrypter real.
+	Fail
+	// Partial retorna apenas a primeira metade do texto em claro, como uma resposta interrompida.
+	Partial
+)
+
+/*
+ChaosProvider é um Decrypter que simula latência, instabilidade e respostas parciais de um provedor remoto
+
+Registre-o com config.WithDecrypter no lugar do backend real, envolvendo um Decrypter que decifra de fato os
+valores (um config.DecrypterFunc que devolve o próprio conteúdo basta para valores criados com
+config.EncryptValue e um EncrypterFunc equivalente). Cada chamada espera Latency mais uma fração aleatória de
+Jitter e então tem o seu resultado decidido, nesta ordem: pelo próximo item de Script; por FlapPeriod, que
+alterna o provedor entre disponível e indisponível; e pelas probabilidades FailureRate e PartialRate. Os sorteios
+usam Seed, de modo que um teste com a mesma configuração produz sempre a mesma sequência.
+
+ChaosProvider também implementa config.HealthChecker, indisponível nos períodos de queda de FlapPeriod, e é
+seguro para uso concorrente.
+
+Decrypter config.Decrypter - O Decrypter real, chamado nas chamadas bem-sucedidas e parciais
+Latency time.Duration - A espera de cada chamada
+Jitter time.Duration - A espera adicional máxima, sorteada a cada chamada
+Script []Fault - Os resultados das primeiras chamadas, na ordem
+FlapPeriod time.Duration - A duração de cada período disponível ou indisponível, a partir da primeira chamada; zero desativa
+FailureRate float64 - A probabilidade de falha de cada chamada, entre 0 e 1
+PartialRate float64 - A probabilidade de resposta parcial de cada chamada, entre 0 e 1
+Seed int64 - A semente dos sorteios
+Err error - O erro das falhas (padrão ErrChaosUnavailable)
+*/
+type ChaosProvider struct {
+	Decrypter   config.Decrypter
+	Latency     time.Duration
+	Jitter      time.Duration
+	Script      []Fault
+	FlapPeriod  time.Duration
+	FailureRate float64
+	PartialRate float64
+	Seed        int64
+	Err         error
+
+	mu      sync.Mutex
+	rng     *rand.Rand
+	started time.Time
+	stats   ChaosStats
+}
+
+/*
+ChaosStats conta as chamadas de um ChaosProvider
+
+Calls int - O total de chamadas
+Failures int - As chamadas que falharam
+Partials int - As chamadas com resposta parcial
+*/
+type ChaosStats struct {
+	Calls    int
+	Failures int
+	Partials int
+}
+
+/*
+Decrypt simula uma chamada ao provedor remoto
+
+@param ciphertext []byte - O texto cifrado
+
+@return []byte - O texto em claro, completo ou parcial
+@return error - O erro de falha, ou o erro do Decrypter real
+*/
+func (c *ChaosProvider) Decrypt(ciphertext []byte) ([]byte, error) {
+	fault, wait := c.next()
+	time.Sleep(wait)
+
+	if fault == Fail {
+		return nil, c.failure()
+	}
+	if c.Decrypter == nil {
+		return nil, errors.New("ChaosProvider sem Decrypter real")
+	}
+	plaintext, err := c.Decrypter.Decrypt(ciphertext)
+	if err != nil || fault != Partial {
+		return plaintext, err
+	}
+
+	return plaintext[:len(plaintext)/2], nil
+}
+
+/*
+CheckHealth informa se o provedor está no período disponível de FlapPeriod
+
+@param ctx context.Context - O contexto da verificação
+
+@return error - O erro de falha nos períodos de queda, ou o erro do contexto
+*/
+func (c *ChaosProvider) CheckHealth(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	down := c.flapping(time.Now())
+	c.mu.Unlock()
+	if down {
+		return c.failure()
+	}
+
+	return nil
+}
+
+// Stats retorna as contagens de chamadas desde a criação ou o último Reset.
+func (c *ChaosProvider) Stats() ChaosStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats
+}
+
+// Reset zera as contagens e reinicia Script, FlapPeriod e os sorteios.
+func (c *ChaosProvider) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rng, c.started, c.stats = nil, time.Time{}, ChaosStats{}
+}
+
+/*
+next decide o resultado e a espera da próxima chamada e atualiza as contagens
+
+@return Fault - O resultado da chamada
+@return time.Duration - A espera antes do resultado
+*/
+func (c *ChaosProvider) next() (Fault, time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.rng == nil {
+		c.rng = rand.New(rand.NewSource(c.Seed))
+		c.started = now
+	}
+
+	wait := c.Latency
+	if c.Jitter > 0 {
+		wait += time.Duration(c.rng.Int63n(int64(c.Jitter) + 1))
+	}
+
+	fault := Pass
+	switch {
+	case c.stats.Calls < len(c.Script):
+		fault = c.Script[c.stats.Calls]
+	case c.flapping(now):
+		fault = Fail
+	default:
+		switch draw := c.rng.Float64(); {
+		case draw < c.FailureRate:
+			fault = Fail
+		case draw < c.FailureRate+c.PartialRate:
+			fault = Partial
+		}
+	}
+
+	c.stats.Calls++
+	switch fault {
+	case Fail:
+		c.stats.Failures++
+	case Partial:
+		c.stats.Partials++
+	}
+
+	return fault, wait
+}
+
+/*
+flapping informa se o provedor está em um período de queda de FlapPeriod; deve ser chamada com mu bloqueado
+
+@param now time.Time - O momento da verificação
+
+@return bool - Se o provedor está indisponível
+*/
+func (c *ChaosProvider) flapping(now time.Time) bool {
+	if c.FlapPeriod <= 0 || c.started.IsZero() {
+		return false
+	}
+
+	return (now.Sub(c.started)/c.FlapPeriod)%2 == 1
+}
+
+// failure retorna o erro das falhas simuladas.
+func (c *ChaosProvider) failure() error {
+	if c.Err != nil {
+		return c.Err
+	}
+
+	return ErrChaosUnavailable
+}
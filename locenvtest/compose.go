@@ -0,0 +1,255 @@
+package locenvtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+// ErrComposeNotFound é retornado por StartCompose quando não há arquivo compose para o ambiente carregado.
+var ErrComposeNotFound = errors.New("nenhum arquivo docker-compose encontrado para o ambiente")
+
+/*
+ComposePort associa uma porta de um serviço do compose às variáveis que recebem o endereço publicado
+
+Service string - O nome do serviço no arquivo compose
+ContainerPort - A porta no contêiner e as variáveis, como em ContainerOverrides
+*/
+type ComposePort struct {
+	Service string
+	ContainerPort
+}
+
+/*
+ComposeOptions configura StartCompose
+
+Boot bool - Se o compose deve ser iniciado com up --wait; sem Boot, os serviços já devem estar em execução
+Project string - O nome do projeto do compose; vazio para o diretório do arquivo seguido do ambiente
+WaitTimeout time.Duration - O tempo máximo de espera pelos healthchecks; zero para 2 minutos
+Ports []ComposePort - As portas publicadas exportadas como variáveis
+Run func(ctx context.Context, args ...string) ([]byte, error) - Executa docker compose com os argumentos; nil para o binário docker
+*/
+type ComposeOptions struct {
+	Boot        bool
+	Project     string
+	WaitTimeout time.Duration
+	Ports       []ComposePort
+	Run         func(ctx context.Context, args ...string) ([]byte, error)
+}
+
+/*
+ComposeStack é um compose localizado, e possivelmente iniciado, por StartCompose
+
+File string - O arquivo compose
+Project string - O nome do projeto
+Env map[string]string - As variáveis de conexão das portas publicadas
+*/
+type ComposeStack struct {
+	File    string
+	Project string
+	Env     map[string]string
+
+	run func(ctx context.Context, args ...string) ([]byte, error)
+}
+
+/*
+StartCompose localiza o compose do ambiente carregado, o inicia e exporta as variáveis de conexão
+
+O arquivo é procurado no diretório do arquivo .env base, com os nomes docker-compose.<ambiente>.yml, .yaml,
+compose.<ambiente>.yml e .yaml, nessa ordem; com APP_ENV=test, .env.test leva a docker-compose.test.yml. Com
+Boot, os serviços são iniciados com docker compose up -d --wait, que aguarda os healthchecks. As portas de Ports
+são consultadas com docker compose port, e os endereços formam Env, que pode ser passado a config.WithValues ou
+config.WithOverrides:
+
+	stack, err := locenvtest.StartCompose(ctx, result, locenvtest.ComposeOptions{
+		Boot:  true,
+		Ports: []locenvtest.ComposePort{{Service: "db", ContainerPort: locenvtest.ContainerPort{Port: "5432", PortKey: "DB_PORT"}}},
+	})
+	t.Cleanup(func() { stack.Down(context.Background()) })
+
+@param ctx context.Context - O contexto dos comandos do compose
+@param result *config.Result - O resultado do carregamento, com os arquivos e o ambiente
+@param opts ComposeOptions - As opções
+
+@return *ComposeStack - O compose
+Com Boot, se o up falhar ou uma porta não puder ser consultada, os serviços são encerrados com Down antes do
+retorno do erro, para que a falha não deixe contêineres em execução.
+
+@return error - ErrComposeNotFound, ou um erro se o compose não iniciar ou uma porta não puder ser consultada
+*/
+func StartCompose(ctx context.Context, result *config.Result, opts ComposeOptions) (*ComposeStack, error) {
+	file, err := findComposeFile(result)
+	if err != nil {
+		return nil, err
+	}
+
+	stack := &ComposeStack{File: file, Project: opts.Project, run: opts.Run}
+	if stack.Project == "" {
+		stack.Project = composeProject(filepath.Base(filepath.Dir(file)) + "-" + result.Env)
+	}
+	if stack.run == nil {
+		stack.run = runDockerCompose
+	}
+
+	if opts.Boot {
+		timeout := opts.WaitTimeout
+		if timeout <= 0 {
+			timeout = 2 * time.Minute
+		}
+		wait := strconv.Itoa(int((timeout + time.Second - 1) / time.Second))
+		if _, err := stack.compose(ctx, "up", "-d", "--wait", "--wait-timeout", wait); err != nil {
+			return nil, stack.abort(fmt.Errorf("não foi possível iniciar %s: %w", file, err))
+		}
+	}
+
+	stack.Env = make(map[string]string)
+	for _, p := range opts.Ports {
+		env, err := ContainerOverrides(ctx, stack.endpoint(p.Service), p.ContainerPort)
+		if err != nil {
+			err = fmt.Errorf("serviço %s: %w", p.Service, err)
+			if opts.Boot {
+				err = stack.abort(err)
+			}
+			return nil, err
+		}
+		for key, value := range env {
+			stack.Env[key] = value
+		}
+	}
+
+	return stack, nil
+}
+
+/*
+Down encerra os serviços do compose e remove os seus volumes
+
+@param ctx context.Context - O contexto do comando
+
+@return error - Um erro se o comando falhar
+*/
+func (s *ComposeStack) Down(ctx context.Context) error {
+	_, err := s.compose(ctx, "down", "--volumes", "--remove-orphans")
+	return err
+}
+
+/*
+abort encerra os serviços depois de uma falha de StartCompose, com um contexto próprio, já que o da chamada pode
+ter sido a causa da falha
+
+@param cause error - O erro que interrompeu StartCompose
+
+@return error - O erro original, acrescido do erro de Down se o encerramento também falhar
+*/
+func (s *ComposeStack) abort(cause error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := s.Down(ctx); err != nil {
+		return errors.Join(cause, fmt.Errorf("não foi possível encerrar %s: %w", s.File, err))
+	}
+
+	return cause
+}
+
+// compose executa um subcomando do docker compose com o arquivo e o projeto da pilha.
+func (s *ComposeStack) compose(ctx context.Context, args ...string) ([]byte, error) {
+	return s.run(ctx, append([]string{"-f", s.File, "-p", s.Project}, args...)...)
+}
+
+/*
+endpoint retorna a EndpointFunc de um serviço, que consulta as portas publicadas com docker compose port
+
+Os endereços publicados em todas as interfaces (0.0.0.0 ou ::) são trocados por 127.0.0.1.
+
+@param service string - O nome do serviço
+
+@return EndpointFunc - A função
+*/
+func (s *ComposeStack) endpoint(service string) EndpointFunc {
+	return func(ctx context.Context, port string) (string, error) {
+		number, protocol, _ := strings.Cut(port, "/")
+		args := []string{"port"}
+		if protocol != "" {
+			args = append(args, "--protocol", protocol)
+		}
+		out, err := s.compose(ctx, append(args, service, number)...)
+		if err != nil {
+			return "", err
+		}
+
+		addr := strings.TrimSpace(string(out))
+		host, mapped, err := net.SplitHostPort(addr)
+		if err != nil {
+			return "", fmt.Errorf("resposta inesperada de docker compose port: %q", addr)
+		}
+		if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+			host = "127.0.0.1"
+		}
+
+		return net.JoinHostPort(host, mapped), nil
+	}
+}
+
+/*
+findComposeFile procura o arquivo compose do ambiente ao lado do arquivo .env base
+
+@param result *config.Result - O resultado do carregamento
+
+@return string - O caminho do arquivo
+@return error - ErrComposeNotFound se nenhum arquivo existir
+*/
+func findComposeFile(result *config.Result) (string, error) {
+	dir := "."
+	for _, file := range result.Files {
+		if file != config.SourceMemory {
+			dir = filepath.Dir(file)
+			break
+		}
+	}
+
+	for _, name := range []string{"docker-compose.%s.yml", "docker-compose.%s.yaml", "compose.%s.yml", "compose.%s.yaml"} {
+		file := filepath.Join(dir, fmt.Sprintf(name, result.Env))
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			return file, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w %q em %s", ErrComposeNotFound, result.Env, dir)
+}
+
+// composeProject converte um nome no formato aceito pelo compose: letras minúsculas, dígitos, "-" e "_".
+func composeProject(name string) string {
+	return strings.Trim(strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '-'
+		}
+	}, name), "-_")
+}
+
+// runDockerCompose executa docker compose, incluindo a saída de erro na mensagem de falha.
+func runDockerCompose(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "docker", append([]string{"compose"}, args...)...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker compose %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
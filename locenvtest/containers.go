@@ -0,0 +1,127 @@
+package locenvtest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"strings"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+ContainerEnv retorna as variáveis de uma configuração no formato de ContainerRequest.Env do testcontainers-go
+
+Sem padrões, todas as variáveis de All são incluídas. Com padrões de path.Match (ex.: DB_*), apenas as que
+correspondem a algum deles. Os segredos isolados com WithSecretIsolation não aparecem em All; eles são incluídos
+quando nomeados exatamente em um padrão, lidos com GetSecret:
+
+	req := testcontainers.ContainerRequest{
+		Image: "postgres:16",
+		Env:   locenvtest.ContainerEnv(loader, "POSTGRES_*", "DB_PASSWORD"),
+	}
+
+@param r config.Reader - A configuração, como um carregador ou uma ConfigView
+@param patterns ...string - Os padrões das variáveis incluídas
+
+@return map[string]string - As variáveis
+*/
+func ContainerEnv(r config.Reader, patterns ...string) map[string]string {
+	env := make(map[string]string)
+	for key, value := range r.All() {
+		if len(patterns) == 0 || matchesAny(patterns, key) {
+			env[key] = value
+		}
+	}
+	for _, pattern := range patterns {
+		if _, ok := env[pattern]; ok || strings.ContainsAny(pattern, `*?[\`) {
+			continue
+		}
+		if value, ok := r.GetSecret(pattern); ok {
+			env[pattern] = value
+		}
+	}
+
+	return env
+}
+
+/*
+EndpointFunc retorna o endereço host:porta em que uma porta de um contêiner está exposta
+
+Com testcontainers-go, o adaptador é:
+
+	func(ctx context.Context, port string) (string, error) {
+		return container.PortEndpoint(ctx, nat.Port(port), "")
+	}
+*/
+type EndpointFunc func(ctx context.Context, port string) (string, error)
+
+/*
+ContainerPort associa uma porta de um contêiner às variáveis que recebem o endereço exposto
+
+Port string - A porta no contêiner, como em ExposedPorts (ex.: 5432/tcp)
+PortKey string - A variável que recebe a porta exposta no host (ex.: DB_PORT), ou vazio
+HostKey string - A variável que recebe o host (ex.: DB_HOST), ou vazio
+AddrKey string - A variável que recebe o endereço host:porta (ex.: DB_ADDR), ou vazio
+*/
+type ContainerPort struct {
+	Port    string
+	PortKey string
+	HostKey string
+	AddrKey string
+}
+
+/*
+ContainerOverrides retorna as variáveis que apontam para as portas expostas por um contêiner
+
+O resultado pode ser aplicado com config.WithOverrides, para quem lê a configuração com FromContext, ou passado a
+config.WithValues, para um carregador que sobrepõe os endereços do contêiner aos arquivos .env:
+
+	overrides, err := locenvtest.ContainerOverrides(ctx, endpoint,
+		locenvtest.ContainerPort{Port: "5432/tcp", HostKey: "DB_HOST", PortKey: "DB_PORT"})
+	loader := config.NewEnvLoader(config.WithValues(overrides))
+
+@param ctx context.Context - O contexto das consultas ao contêiner
+@param endpoint EndpointFunc - A função que consulta o endereço exposto de cada porta
+@param ports ...ContainerPort - As portas e as variáveis correspondentes
+
+@return map[string]string - As variáveis
+@return error - Um erro se alguma porta não puder ser consultada ou o endereço for inválido
+*/
+func ContainerOverrides(ctx context.Context, endpoint EndpointFunc, ports ...ContainerPort) (map[string]string, error) {
+	overrides := make(map[string]string)
+	for _, p := range ports {
+		addr, err := endpoint(ctx, p.Port)
+		if err != nil {
+			return nil, fmt.Errorf("não foi possível obter o endereço da porta %s do contêiner: %w", p.Port, err)
+		}
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, fmt.Errorf("endereço inválido para a porta %s do contêiner: %w", p.Port, err)
+		}
+
+		if p.PortKey != "" {
+			overrides[p.PortKey] = port
+		}
+		if p.HostKey != "" {
+			overrides[p.HostKey] = host
+		}
+		if p.AddrKey != "" {
+			overrides[p.AddrKey] = addr
+		}
+	}
+
+	return overrides, nil
+}
+
+// matchesAny informa se o nome de uma variável corresponde a algum dos padrões de path.Match.
+func matchesAny(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,12 @@
+/*
+Package locenvtest reúne ferramentas para testar aplicações que usam go-locEnv
+
+Os provedores simulados permitem validar, sem acesso à rede, como a aplicação se comporta quando um backend de
+segredos está lento, instável ou responde de forma incompleta, por exemplo para ajustar WithDecryptRetry e
+WithOfflineFallback antes de levá-los à produção.
+
+Nos testes de integração, ContainerEnv e ContainerOverrides ligam a configuração aos contêineres do
+testcontainers-go nos dois sentidos, sem que o pacote dependa dele, e StartCompose inicia o docker-compose do
+ambiente de teste e exporta as variáveis de conexão dos serviços.
+*/
+package locenvtest
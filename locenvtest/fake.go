@@ -0,0 +1,156 @@
+package locenvtest
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrFakeUnavailable é o erro padrão das falhas programadas em FakeVault e FakeSSM.
+var ErrFakeUnavailable = errors.New("provedor falso indisponível (falha programada)")
+
+/*
+RotationEvent descreve uma rotação feita em um provedor falso
+
+Key string - O segredo, o parâmetro ou, na rotação de chave do FakeVault, o nome da chave de transit
+Version int - A nova versão
+Old string - O valor anterior, vazio se não existia ou na rotação de chave
+New string - O novo valor, vazio na rotação de chave
+*/
+type RotationEvent struct {
+	Key     string
+	Version int
+	Old     string
+	New     string
+}
+
+/*
+fakeBackend reúne as falhas programadas, as contagens e os eventos de rotação dos provedores falsos
+
+mu sync.Mutex - Protege os demais campos e o estado do provedor que o incorpora
+failures []error - As falhas programadas para as próximas chamadas, na ordem
+down error - A falha de todas as chamadas, enquanto o provedor estiver indisponível
+calls int - O total de chamadas
+subscribers []func(RotationEvent) - As funções notificadas a cada rotação
+events []RotationEvent - As rotações feitas
+*/
+type fakeBackend struct {
+	mu          sync.Mutex
+	failures    []error
+	down        error
+	calls       int
+	subscribers []func(RotationEvent)
+	events      []RotationEvent
+}
+
+/*
+FailNext programa a falha das próximas n chamadas, de qualquer operação
+
+@param n int - O número de chamadas que falham
+@param err error - O erro retornado, ou nil para ErrFakeUnavailable
+*/
+func (b *fakeBackend) FailNext(n int, err error) {
+	if err == nil {
+		err = ErrFakeUnavailable
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i := 0; i < n; i++ {
+		b.failures = append(b.failures, err)
+	}
+}
+
+/*
+SetUnavailable torna o provedor indisponível, ou disponível de novo
+
+Enquanto indisponível, todas as chamadas e CheckHealth falham com o erro informado. As falhas de FailNext têm
+precedência.
+
+@param err error - O erro das chamadas, ou nil para tornar o provedor disponível
+*/
+func (b *fakeBackend) SetUnavailable(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.down = err
+}
+
+// Calls retorna o total de chamadas ao provedor, incluindo as que falharam.
+func (b *fakeBackend) Calls() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.calls
+}
+
+/*
+OnRotate registra uma função chamada a cada rotação, na goroutine que a fez
+
+@param fn func(RotationEvent) - A função
+*/
+func (b *fakeBackend) OnRotate(fn func(RotationEvent)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscribers = append(b.subscribers, fn)
+}
+
+// Events retorna as rotações feitas, na ordem.
+func (b *fakeBackend) Events() []RotationEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]RotationEvent(nil), b.events...)
+}
+
+/*
+CheckHealth falha enquanto o provedor estiver indisponível
+
+@param ctx context.Context - O contexto da verificação
+
+@return error - O erro de SetUnavailable, ou o erro do contexto
+*/
+func (b *fakeBackend) CheckHealth(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.down
+}
+
+/*
+begin conta uma chamada e retorna a falha programada para ela; deve ser chamada com mu bloqueado
+
+@return error - A falha da chamada, ou nil
+*/
+func (b *fakeBackend) begin() error {
+	b.calls++
+	if len(b.failures) > 0 {
+		err := b.failures[0]
+		b.failures = b.failures[1:]
+		return err
+	}
+
+	return b.down
+}
+
+/*
+rotated registra uma rotação e notifica as funções de OnRotate; deve ser chamada sem mu bloqueado
+
+@param event RotationEvent - A rotação
+*/
+func (b *fakeBackend) rotated(event RotationEvent) {
+	b.mu.Lock()
+	b.events = append(b.events, event)
+	subscribers := b.subscribers
+	b.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(event)
+	}
+}
@@ -0,0 +1,67 @@
+package locenvtest
+
+import (
+	"time"
+)
+
+/*
+SoakReport resume uma execução de Soak
+
+Runs int - O número de execuções
+Failures int - As execuções que retornaram erro
+Errors []error - Os erros retornados, na ordem
+MaxDuration time.Duration - A duração da execução mais lenta
+TotalDuration time.Duration - A soma das durações
+*/
+type SoakReport struct {
+	Runs          int
+	Failures      int
+	Errors        []error
+	MaxDuration   time.Duration
+	TotalDuration time.Duration
+}
+
+// SuccessRate retorna a fração das execuções sem erro, entre 0 e 1.
+func (r SoakReport) SuccessRate() float64 {
+	if r.Runs == 0 {
+		return 0
+	}
+
+	return float64(r.Runs-r.Failures) / float64(r.Runs)
+}
+
+/*
+Soak executa repetidamente uma operação de carregamento e mede o resultado
+
+Normalmente a operação é o Reload de um carregador que usa um ChaosProvider, para verificar se a combinação de
+WithDecryptRetry e WithOfflineFallback mantém a taxa de sucesso e a latência esperadas:
+
+	report := locenvtest.Soak(200, loader.Reload)
+	if report.SuccessRate() < 0.99 || report.MaxDuration > time.Second {
+		t.Errorf("configuração de retry insuficiente: %+v", report)
+	}
+
+@param runs int - O número de execuções
+@param fn func() error - A operação executada
+
+@return SoakReport - O resumo das execuções
+*/
+func Soak(runs int, fn func() error) SoakReport {
+	report := SoakReport{Runs: runs}
+	for i := 0; i < runs; i++ {
+		start := time.Now()
+		err := fn()
+		elapsed := time.Since(start)
+
+		report.TotalDuration += elapsed
+		if elapsed > report.MaxDuration {
+			report.MaxDuration = elapsed
+		}
+		if err != nil {
+			report.Failures++
+			report.Errors = append(report.Errors, err)
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,163 @@
+package locenvtest
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+/*
+FakeSSM é um Parameter Store em memória, com parâmetros versionados nomeados /<ambiente>/<variável>
+
+Implementa config.Provider e config.HealthChecker resolvendo referências: o texto cifrado é o nome de um
+parâmetro, e Decrypt retorna o seu valor mais recente. Com Reference, o arquivo .env aponta para o parâmetro, e
+cada Reload traz o valor atual, de modo que Rotate exercita WithRotationHook sem credenciais de nuvem:
+
+	ssm := locenvtest.NewFakeSSM()
+	ssm.PutParameter("/test/DB_PASSWORD", "v1")
+	// .env.test: DB_PASSWORD=<ssm.Reference("ssm", "/test/DB_PASSWORD")>
+	loader := config.NewEnvLoader(config.WithDecrypter("ssm", ssm))
+
+Também implementa config.SecretStore sobre os parâmetros de cada ambiente. As falhas programadas com FailNext e
+SetUnavailable valem para todas as operações. FakeSSM é seguro para uso concorrente.
+*/
+type FakeSSM struct {
+	fakeBackend
+
+	params map[string][]string
+}
+
+// NewFakeSSM cria um FakeSSM sem parâmetros.
+func NewFakeSSM() *FakeSSM {
+	return &FakeSSM{params: make(map[string][]string)}
+}
+
+// Name retorna o nome do provedor, fake-ssm.
+func (s *FakeSSM) Name() string {
+	return "fake-ssm"
+}
+
+/*
+Reference retorna o valor de um arquivo .env que aponta para um parâmetro
+
+@param backend string - O nome com que o FakeSSM foi registrado em config.WithDecrypter
+@param name string - O nome do parâmetro (ex.: /test/DB_PASSWORD)
+
+@return string - O valor no formato enc:<backend>:<base64>
+*/
+func (s *FakeSSM) Reference(backend string, name string) string {
+	return "enc:" + backend + ":" + base64.StdEncoding.EncodeToString([]byte(name))
+}
+
+/*
+PutParameter grava uma nova versão de um parâmetro, sem gerar um evento de rotação
+
+@param name string - O nome do parâmetro
+@param value string - O valor
+
+@return int - A versão gravada
+*/
+func (s *FakeSSM) PutParameter(name string, value string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.params[name] = append(s.params[name], value)
+
+	return len(s.params[name])
+}
+
+/*
+Rotate grava uma nova versão de um parâmetro e notifica as funções de OnRotate
+
+@param name string - O nome do parâmetro
+@param value string - O novo valor
+
+@return RotationEvent - A rotação feita
+*/
+func (s *FakeSSM) Rotate(name string, value string) RotationEvent {
+	s.mu.Lock()
+	versions := s.params[name]
+	event := RotationEvent{Key: name, Version: len(versions) + 1, New: value}
+	if len(versions) > 0 {
+		event.Old = versions[len(versions)-1]
+	}
+	s.params[name] = append(versions, value)
+	s.mu.Unlock()
+
+	s.rotated(event)
+
+	return event
+}
+
+/*
+Decrypt resolve uma referência, retornando o valor mais recente do parâmetro
+
+@param ciphertext []byte - O nome do parâmetro
+
+@return []byte - O valor do parâmetro
+@return error - A falha programada, ou um erro se o parâmetro não existir
+*/
+func (s *FakeSSM) Decrypt(ciphertext []byte) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.begin(); err != nil {
+		return nil, err
+	}
+
+	versions, ok := s.params[string(ciphertext)]
+	if !ok {
+		return nil, fmt.Errorf("parâmetro %s não encontrado", ciphertext)
+	}
+
+	return []byte(versions[len(versions)-1]), nil
+}
+
+/*
+Fetch retorna o valor mais recente dos parâmetros /<env>/<variável> do ambiente
+
+@param env string - O ambiente
+
+@return map[string]string - Os valores, pelo nome da variável
+@return error - A falha programada, se houver
+*/
+func (s *FakeSSM) Fetch(env string) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.begin(); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for name, versions := range s.params {
+		if key, ok := strings.CutPrefix(name, "/"+env+"/"); ok && key != "" && !strings.Contains(key, "/") {
+			values[key] = versions[len(versions)-1]
+		}
+	}
+
+	return values, nil
+}
+
+/*
+Push grava uma nova versão do parâmetro /<env>/<variável> de cada valor informado, sem remover os demais
+
+@param env string - O ambiente
+@param values map[string]string - Os valores, pelo nome da variável
+
+@return error - A falha programada, se houver
+*/
+func (s *FakeSSM) Push(env string, values map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.begin(); err != nil {
+		return err
+	}
+	for key, value := range values {
+		name := "/" + env + "/" + key
+		s.params[name] = append(s.params[name], value)
+	}
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package locenvtest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"strconv"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+FakeVault é um Vault em memória, com um KV por ambiente e uma chave de transit versionada
+
+Implementa config.Provider, config.Encrypter e config.HealthChecker como o transit do Vault: os valores cifrados
+com Encrypt levam a versão da chave, e as versões anteriores continuam decifrando depois de RotateKey. Também
+implementa config.SecretStore sobre o KV, para os testes de locenv sync, CompareWithStore e PullFromStore.
+
+As falhas programadas com FailNext e SetUnavailable valem para todas as operações, e as rotações de Rotate e
+RotateKey são entregues às funções de OnRotate. FakeVault é seguro para uso concorrente.
+*/
+type FakeVault struct {
+	fakeBackend
+
+	kv   map[string]map[string][]string
+	keys [][]byte
+}
+
+// NewFakeVault cria um FakeVault vazio, com a primeira versão da chave de transit.
+func NewFakeVault() *FakeVault {
+	v := &FakeVault{kv: make(map[string]map[string][]string)}
+	v.keys = append(v.keys, newTransitKey())
+
+	return v
+}
+
+// Name retorna o nome do provedor, fake-vault.
+func (v *FakeVault) Name() string {
+	return "fake-vault"
+}
+
+/*
+Put grava uma nova versão de um segredo no KV, sem gerar um evento de rotação
+
+@param env string - O ambiente
+@param key string - O nome do segredo
+@param value string - O valor
+*/
+func (v *FakeVault) Put(env string, key string, value string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.put(env, key, value)
+}
+
+/*
+Rotate grava uma nova versão de um segredo existente ou novo e notifica as funções de OnRotate
+
+@param env string - O ambiente
+@param key string - O nome do segredo
+@param value string - O novo valor
+
+@return RotationEvent - A rotação feita
+*/
+func (v *FakeVault) Rotate(env string, key string, value string) RotationEvent {
+	v.mu.Lock()
+	versions := v.kv[env][key]
+	event := RotationEvent{Key: key, Version: len(versions) + 1, New: value}
+	if len(versions) > 0 {
+		event.Old = versions[len(versions)-1]
+	}
+	v.put(env, key, value)
+	v.mu.Unlock()
+
+	v.rotated(event)
+
+	return event
+}
+
+/*
+RotateKey cria uma nova versão da chave de transit, usada pelas próximas chamadas de Encrypt
+
+@return RotationEvent - A rotação feita, com Key igual a transit
+*/
+func (v *FakeVault) RotateKey() RotationEvent {
+	v.mu.Lock()
+	v.keys = append(v.keys, newTransitKey())
+	event := RotationEvent{Key: "transit", Version: len(v.keys)}
+	v.mu.Unlock()
+
+	v.rotated(event)
+
+	return event
+}
+
+/*
+Fetch retorna a versão mais recente de cada segredo do ambiente
+
+@param env string - O ambiente
+
+@return map[string]string - Os segredos
+@return error - A falha programada, se houver
+*/
+func (v *FakeVault) Fetch(env string) (map[string]string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := v.begin(); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(v.kv[env]))
+	for key, versions := range v.kv[env] {
+		values[key] = versions[len(versions)-1]
+	}
+
+	return values, nil
+}
+
+/*
+Push grava uma nova versão de cada segredo informado, sem remover os demais
+
+@param env string - O ambiente
+@param values map[string]string - Os segredos
+
+@return error - A falha programada, se houver
+*/
+func (v *FakeVault) Push(env string, values map[string]string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := v.begin(); err != nil {
+		return err
+	}
+	for key, value := range values {
+		v.put(env, key, value)
+	}
+
+	return nil
+}
+
+/*
+Encrypt cifra o texto em claro com a versão mais recente da chave de transit
+
+@param plaintext []byte - O texto em claro
+
+@return []byte - O texto cifrado, prefixado por v<versão>:
+@return error - A falha programada, se houver
+*/
+func (v *FakeVault) Encrypt(plaintext []byte) ([]byte, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := v.begin(); err != nil {
+		return nil, err
+	}
+
+	version := len(v.keys)
+	d, err := config.NewLocalKeyDecrypter(v.keys[version-1])
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := d.Encrypt(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte("v"+strconv.Itoa(version)+":"), sealed...), nil
+}
+
+/*
+Decrypt decifra um texto cifrado por Encrypt, com a versão da chave indicada no prefixo
+
+@param ciphertext []byte - O texto cifrado
+
+@return []byte - O texto em claro
+@return error - A falha programada, ou um erro se a versão não existir ou o conteúdo for inválido
+*/
+func (v *FakeVault) Decrypt(ciphertext []byte) ([]byte, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := v.begin(); err != nil {
+		return nil, err
+	}
+
+	prefix, sealed, ok := bytes.Cut(ciphertext, []byte(":"))
+	version, err := strconv.Atoi(string(bytes.TrimPrefix(prefix, []byte("v"))))
+	if !ok || !bytes.HasPrefix(prefix, []byte("v")) || err != nil || version < 1 || version > len(v.keys) {
+		return nil, fmt.Errorf("versão de chave inválida no texto cifrado: %q", prefix)
+	}
+	d, err := config.NewLocalKeyDecrypter(v.keys[version-1])
+	if err != nil {
+		return nil, err
+	}
+
+	return d.Decrypt(sealed)
+}
+
+// put grava uma nova versão de um segredo; deve ser chamada com mu bloqueado.
+func (v *FakeVault) put(env string, key string, value string) {
+	if v.kv[env] == nil {
+		v.kv[env] = make(map[string][]string)
+	}
+	v.kv[env][key] = append(v.kv[env][key], value)
+}
+
+// newTransitKey gera uma chave AES-256 aleatória para o transit do FakeVault.
+func newTransitKey() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic("locenvtest: não foi possível gerar a chave de transit: " + err.Error())
+	}
+
+	return key
+}
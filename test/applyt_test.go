@@ -0,0 +1,92 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+recordingT é um config.TestingT que registra as chamadas de Setenv e Fatalf, delegando Setenv ao teste real
+
+t *testing.T - O teste real
+keys []string - As variáveis definidas, na ordem
+fatal string - A mensagem de Fatalf, se houver
+*/
+type recordingT struct {
+	t     *testing.T
+	keys  []string
+	fatal string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Setenv(key string, value string) {
+	r.keys = append(r.keys, key)
+	r.t.Setenv(key, value)
+}
+
+func (r *recordingT) Fatalf(format string, args ...any) {
+	r.fatal = fmt.Sprintf(format, args...)
+}
+
+/*
+TestApplyTRevertsVariables verifica se ApplyT define as variáveis com t.Setenv, respeitando as variáveis do
+processo, e se elas são revertidas ao fim do subteste.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestApplyTRevertsVariables(t *testing.T) {
+	setupEnvDir(t, "applyt", "APPLYT_HOST=from-file\nAPPLYT_PORT=5432\n")
+	t.Setenv("APPLYT_PORT", "9999")
+
+	t.Run("load", func(t *testing.T) {
+		recorder := &recordingT{t: t}
+		loader := config.NewEnvLoader(config.WithSilent()).(*config.FileEnvLoader)
+		result := loader.ApplyT(recorder)
+		if recorder.fatal != "" || result == nil {
+			t.Fatalf("Erro ao carregar variáveis de ambiente: %s", recorder.fatal)
+		}
+		if len(recorder.keys) != 1 || recorder.keys[0] != "APPLYT_HOST" || len(result.Skipped) != 1 {
+			t.Errorf("Esperava apenas APPLYT_HOST definida com t.Setenv, obteve %v", recorder.keys)
+		}
+		if os.Getenv("APPLYT_HOST") != "from-file" || os.Getenv("APPLYT_PORT") != "9999" {
+			t.Errorf("Valores inesperados no processo: %q e %q", os.Getenv("APPLYT_HOST"), os.Getenv("APPLYT_PORT"))
+		}
+	})
+
+	if _, ok := os.LookupEnv("APPLYT_HOST"); ok {
+		t.Error("APPLYT_HOST deveria ter sido removida ao fim do subteste")
+	}
+}
+
+/*
+TestApplyTWithNoDiscovery verifica se ApplyT exporta as variáveis literais de um carregador sem descoberta e se
+um carregamento com erro interrompe o teste com Fatalf.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestApplyTWithNoDiscovery(t *testing.T) {
+	t.Run("literals", func(t *testing.T) {
+		loader := config.NewEnvLoader(
+			config.WithSilent(),
+			config.WithNoDiscovery(),
+			config.WithValues(map[string]string{"APPLYT_LITERAL": "on"}),
+		).(*config.FileEnvLoader)
+		loader.ApplyT(t)
+		if os.Getenv("APPLYT_LITERAL") != "on" {
+			t.Errorf("Esperava APPLYT_LITERAL exportada, obteve %q", os.Getenv("APPLYT_LITERAL"))
+		}
+	})
+	if _, ok := os.LookupEnv("APPLYT_LITERAL"); ok {
+		t.Error("APPLYT_LITERAL deveria ter sido removida ao fim do subteste")
+	}
+
+	recorder := &recordingT{t: t}
+	failing := config.NewEnvLoader(config.WithSilent(), config.WithNoDiscovery(), config.WithRequired("APPLYT_MISSING"))
+	if result := failing.(*config.FileEnvLoader).ApplyT(recorder); result != nil || recorder.fatal == "" {
+		t.Errorf("Esperava Fatalf com a variável obrigatória ausente, obteve %+v", result)
+	}
+}
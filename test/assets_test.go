@@ -0,0 +1,42 @@
+package test
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestAssetBundle verifica se um AssetBundle preenchido a partir de bytes é um fs.FS válido e se WithAssets e WithEnv
+carregam os arquivos do pacote sem alterar o ambiente do processo.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestAssetBundle(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+
+	bundle := config.NewAssetBundle()
+	bundle.Add(".env.production", []byte("ASSET_API_URL=https://api.exemplo.com\nASSET_PASSWORD=s3cr3t\n"))
+	bundle.Add("/.env.development", []byte("ASSET_API_URL=http://10.0.2.2:8080\n"))
+	bundle.Add("img/logo.png", []byte{0x89, 'P', 'N', 'G'})
+
+	if err := fstest.TestFS(bundle, ".env.production", ".env.development", "img/logo.png"); err != nil {
+		t.Fatalf("AssetBundle não é um fs.FS válido: %v", err)
+	}
+
+	loader := config.NewEnvLoader(config.WithAssets(bundle), config.WithEnv("Production"), config.WithSecretKeys("*PASSWORD"))
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+	if loader.GetEnv() != "production" || loader.GetString("ASSET_API_URL") != "https://api.exemplo.com" {
+		t.Errorf("Configuração inesperada: %s %v", loader.GetEnv(), loader.All())
+	}
+	if secret, ok := loader.GetSecret("ASSET_PASSWORD"); !ok || secret != "s3cr3t" {
+		t.Errorf("ASSET_PASSWORD deveria ser um segredo")
+	}
+	if _, exists := os.LookupEnv("ASSET_API_URL"); exists {
+		t.Errorf("WithAssets não deveria alterar o ambiente do processo")
+	}
+}
@@ -0,0 +1,111 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+largeEnvContent gera o conteúdo de um arquivo .env com a quantidade de variáveis informada
+
+@params keys int - A quantidade de variáveis
+
+@return string - O conteúdo gerado
+*/
+func largeEnvContent(keys int) string {
+	var b strings.Builder
+	for i := 0; i < keys; i++ {
+		fmt.Fprintf(&b, "# variável %d\nBENCH_KEY_%d=\"value-%d ${BENCH_KEY_0}\"\n", i, i, i)
+	}
+
+	return b.String()
+}
+
+/*
+BenchmarkParseLargeFile mede a interpretação de um arquivo .env com 10 mil variáveis.
+
+@params b *testing.B - Um ponteiro para o objeto de benchmark
+*/
+func BenchmarkParseLargeFile(b *testing.B) {
+	content := largeEnvContent(10000)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(content)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := config.Parse(strings.NewReader(content)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+/*
+BenchmarkDiscoveryDeepTree mede a descoberta de um arquivo .env na raiz de uma árvore com 25 níveis,
+partindo do diretório mais profundo, em que cada nível possui também diretórios irmãos com arquivos.
+
+@params b *testing.B - Um ponteiro para o objeto de benchmark
+*/
+func BenchmarkDiscoveryDeepTree(b *testing.B) {
+	root := b.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ".env.bench"), []byte("BENCH_DISCOVERY=1"), 0644); err != nil {
+		b.Fatal(err)
+	}
+
+	dir := root
+	for level := 0; level < 25; level++ {
+		for sibling := 0; sibling < 3; sibling++ {
+			siblingDir := filepath.Join(dir, fmt.Sprintf("sibling%d", sibling))
+			if err := os.MkdirAll(siblingDir, 0755); err != nil {
+				b.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(siblingDir, "file.txt"), []byte("x"), 0644); err != nil {
+				b.Fatal(err)
+			}
+		}
+		dir = filepath.Join(dir, fmt.Sprintf("level%d", level))
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		b.Fatal(err)
+	}
+
+	b.Setenv("APP_ENV", "bench")
+	if err := os.Chdir(dir); err != nil {
+		b.Fatal(err)
+	}
+
+	loader := config.NewEnvLoader()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := loader.Plan(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+/*
+TestParseAllocationBudget garante o orçamento de alocações documentado em config.Parse:
+no máximo 4 alocações por variável comentada em um arquivo grande.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestParseAllocationBudget(t *testing.T) {
+	const keys = 1000
+	content := largeEnvContent(keys)
+
+	allocs := testing.AllocsPerRun(10, func() {
+		if _, err := config.Parse(strings.NewReader(content)); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	if perKey := allocs / keys; perKey > 4 {
+		t.Errorf("Orçamento excedido: %.2f alocações por variável (máximo 4)", perKey)
+	}
+}
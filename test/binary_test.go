@@ -0,0 +1,68 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestBinaryGetters verifica se GetBase64 e GetHex decodificam o material binário e aplicam as validações de
+tamanho, pelo carregador, pelo Reader e pela API do pacote, e se WithHexContent detecta valores inválidos
+durante o carregamento.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestBinaryGetters(t *testing.T) {
+	key := bytes.Repeat([]byte{0xab}, 32)
+	setupEnvDir(t, "binary", strings.Join([]string{
+		"BIN_HMAC_KEY=0x" + strings.Repeat("AB", 32),
+		"BIN_SALT=q6urq6urq6s=",
+		"BIN_SHORT=abcd",
+		"BIN_BAD=xyz",
+	}, "\n")+"\n")
+
+	base := []config.Option{config.WithNoProcessEnv(), config.WithSilent(), config.WithHexContent("BIN_HMAC_KEY", config.ExactLength(32))}
+	err := config.NewEnvLoader(append(base, config.WithHexContent("BIN_SHORT", config.MinLength(16)), config.WithHexContent("BIN_BAD"))...).LoadEnv()
+	var validation *config.ValidationError
+	if !errors.As(err, &validation) || len(validation.Problems) != 2 {
+		t.Fatalf("Esperava dois problemas de conteúdo, obteve %v", err)
+	}
+	if !strings.Contains(validation.Problems[0], "ao menos 16 bytes, tem 2") || !strings.Contains(validation.Problems[1], "não é hexadecimal") {
+		t.Errorf("Problemas inesperados: %v", validation.Problems)
+	}
+
+	loader := config.NewEnvLoader(base...).(*config.FileEnvLoader)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if got, err := loader.GetHex("BIN_HMAC_KEY", config.ExactLength(32)); err != nil || !bytes.Equal(got, key) {
+		t.Errorf("Esperava a chave HMAC, obteve %x, %v", got, err)
+	}
+	if got, err := loader.GetBase64("BIN_SALT", config.MinLength(8), config.MaxLength(8)); err != nil || len(got) != 8 {
+		t.Errorf("Esperava o salt de 8 bytes, obteve %x, %v", got, err)
+	}
+	if _, err := loader.GetBase64("BIN_SALT", config.ExactLength(16)); err == nil || !strings.Contains(err.Error(), "BIN_SALT: o conteúdo deve ter 16 bytes, tem 8") {
+		t.Errorf("Esperava erro de tamanho, obteve %v", err)
+	}
+	if _, err := loader.Snapshot().GetHex("BIN_MISSING"); !errors.Is(err, config.ErrKeyNotFound) {
+		t.Errorf("Esperava ErrKeyNotFound, obteve %v", err)
+	}
+
+	var reader config.Reader = loader
+	if got, err := reader.GetBytesBase64("BIN_SALT"); err != nil || len(got) != 8 {
+		t.Errorf("Esperava o salt pelo Reader, obteve %x, %v", got, err)
+	}
+	if err := config.Load(base...); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if got, err := config.GetHex("BIN_HMAC_KEY", config.ExactLength(32)); err != nil || !bytes.Equal(got, key) {
+		t.Errorf("Esperava a chave HMAC pelo carregador padrão, obteve %x, %v", got, err)
+	}
+	if _, err := config.GetBase64("BIN_SALT", config.ExactLength(16)); err == nil {
+		t.Errorf("Esperava erro de tamanho pelo carregador padrão")
+	}
+}
@@ -0,0 +1,117 @@
+package test
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestRenderBundleIsDeterministic verifica se RenderBundle gera um diretório por ambiente, separa os segredos,
+omite-os sem IncludeSecrets e produz o mesmo conteúdo a cada geração.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestRenderBundleIsDeterministic(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		".env.production": "BND_HOST=prod.internal\nBND_NAME=\"my app\"\nBND_PASSWORD=s3cr3t\n",
+		".env.staging":    "BND_HOST=staging.internal\nBND_NAME=app\n",
+		".env.example":    "BND_HOST=\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(path.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Não foi possível criar o arquivo .env: %v", err)
+		}
+	}
+	secrets := config.WithSecretKeys("*_PASSWORD")
+
+	dotenv, err := config.RenderBundle(dir, config.BundleOptions{}, secrets)
+	if err != nil {
+		t.Fatalf("Erro ao gerar o bundle: %s", err)
+	}
+	var paths []string
+	for _, file := range dotenv.Files {
+		paths = append(paths, file.Path)
+	}
+	if !reflect.DeepEqual(paths, []string{"production/config.env", "staging/config.env"}) {
+		t.Errorf("Arquivos inesperados: %v", paths)
+	}
+	if !reflect.DeepEqual(dotenv.Omitted, map[string][]string{"production": {"BND_PASSWORD"}}) {
+		t.Errorf("Segredos omitidos inesperados: %v", dotenv.Omitted)
+	}
+	if content := string(dotenv.Files[0].Content); !strings.HasSuffix(content, "BND_HOST=prod.internal\nBND_NAME=\"my app\"\n") {
+		t.Errorf("Conteúdo inesperado:\n%s", content)
+	}
+
+	opts := config.BundleOptions{Format: config.BundleKubernetes, Name: "api", Namespace: "prod", IncludeSecrets: true}
+	first, err := config.RenderBundle(dir, opts, secrets)
+	if err != nil {
+		t.Fatalf("Erro ao gerar o bundle: %s", err)
+	}
+	second, _ := config.RenderBundle(dir, opts, secrets)
+	if !reflect.DeepEqual(first, second) {
+		t.Error("Duas gerações deveriam produzir o mesmo bundle")
+	}
+	secret := string(first.Files[1].Content)
+	if first.Files[1].Path != "production/secret.yaml" || !strings.Contains(secret, "kind: Secret") ||
+		!strings.Contains(secret, `BND_PASSWORD: "czNjcjN0"`) || !strings.Contains(secret, `namespace: "prod"`) {
+		t.Errorf("Secret inesperado:\n%s", secret)
+	}
+
+	target := t.TempDir()
+	if err := first.Write(target); err != nil {
+		t.Fatalf("Erro ao gravar o bundle: %s", err)
+	}
+	info, err := os.Stat(path.Join(target, "production", "secret.yaml"))
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("O Secret deveria ser gravado com permissão 0600: %v %v", info, err)
+	}
+	if content, _ := os.ReadFile(path.Join(target, "staging", "configmap.yaml")); !strings.Contains(string(content), `BND_HOST: "staging.internal"`) {
+		t.Errorf("ConfigMap inesperado:\n%s", content)
+	}
+}
+
+/*
+TestRenderBundleHelmValues verifica se o formato Helm coloca as variáveis nos caminhos de HelmMapping, com os
+prefixos em lowerCamelCase e as demais em env, e se dois mapeamentos para o mesmo caminho são rejeitados.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestRenderBundleHelmValues(t *testing.T) {
+	dir := t.TempDir()
+	content := "HLM_DB_HOST=db.internal\nHLM_REDIS_MAX_CONNS=10\nHLM_REDIS_URL=redis://r\nHLM_REPLICAS=3\nHLM_DEBUG=on\n"
+	if err := os.WriteFile(path.Join(dir, ".env.prod"), []byte(content), 0644); err != nil {
+		t.Fatalf("Não foi possível criar o arquivo .env: %v", err)
+	}
+
+	mapping := config.HelmMapping{"HLM_DB_HOST": "database.host", "HLM_REDIS_*": "redis.*", "HLM_REPLICAS": "replicaCount"}
+	bundle, err := config.RenderBundle(dir, config.BundleOptions{Format: config.BundleHelm, Helm: mapping})
+	if err != nil {
+		t.Fatalf("Erro ao gerar o bundle: %s", err)
+	}
+	want := `database:
+  host: "db.internal"
+env:
+  HLM_DEBUG: "on"
+redis:
+  maxConns: "10"
+  url: "redis://r"
+replicaCount: "3"
+`
+	if len(bundle.Files) != 1 || bundle.Files[0].Path != "prod/values.yaml" || !strings.HasSuffix(string(bundle.Files[0].Content), "\n"+want) {
+		t.Errorf("values.yaml inesperado:\n%s", bundle.Files[0].Content)
+	}
+
+	mapping["HLM_DEBUG"] = "database.host"
+	if _, err := config.RenderBundle(dir, config.BundleOptions{Format: config.BundleHelm, Helm: mapping}); err == nil || !strings.Contains(err.Error(), "database.host") {
+		t.Errorf("Esperava um erro de caminho repetido, obteve %v", err)
+	}
+	if _, err := config.RenderBundle(dir, config.BundleOptions{Format: config.BundleHelm, Helm: config.HelmMapping{"HLM_*": "app"}}); err == nil {
+		t.Error("Esperava um erro para o prefixo sem \"*\" no caminho")
+	}
+}
@@ -0,0 +1,256 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestOfflineFallback verifica se, com o KMS inacessível, o carregador tenta novamente e recorre ao cache cifrado da
+última configuração carregada, respeitando o limite de idade do cache.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestOfflineFallback(t *testing.T) {
+	key := make([]byte, 32)
+	local, err := config.NewLocalKeyDecrypter(key)
+	if err != nil {
+		t.Fatalf("Erro ao criar a chave: %v", err)
+	}
+	encrypted, err := config.EncryptValue("kms", local, "s3cr3t")
+	if err != nil {
+		t.Fatalf("Erro ao cifrar o valor: %v", err)
+	}
+
+	dir := setupEnvDir(t, "test", "OFFLINE_HOST=db\nOFFLINE_PASSWORD="+encrypted+"\n")
+	t.Cleanup(func() {
+		os.Unsetenv("OFFLINE_HOST")
+		os.Unsetenv("OFFLINE_PASSWORD")
+	})
+
+	var down atomic.Bool
+	var calls atomic.Int32
+	kms := config.DecrypterFunc(func(ciphertext []byte) ([]byte, error) {
+		calls.Add(1)
+		if down.Load() {
+			return nil, errors.New("kms inacessível")
+		}
+		return local.Decrypt(ciphertext)
+	})
+
+	cacheFile := path.Join(dir, "cache", "locenv.cache")
+	loader := config.NewEnvLoader(
+		config.WithDecrypter("kms", kms),
+		config.WithDecryptRetry(2, time.Millisecond),
+		config.WithOfflineFallback(cacheFile, key, time.Hour),
+	)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+
+	content, err := os.ReadFile(cacheFile)
+	if err != nil || strings.Contains(string(content), "s3cr3t") {
+		t.Fatalf("O cache deveria existir e estar cifrado (%v)", err)
+	}
+	if info, _ := os.Stat(cacheFile); info.Mode().Perm() != 0o600 {
+		t.Errorf("Permissão inesperada do cache: %v", info.Mode().Perm())
+	}
+
+	down.Store(true)
+	calls.Store(0)
+	result, err := loader.LoadEnvResult()
+	if err != nil {
+		t.Fatalf("O cache offline deveria ser usado: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Esperadas 2 tentativas de decifrar, obtidas %d", calls.Load())
+	}
+	if os.Getenv("OFFLINE_PASSWORD") != "s3cr3t" || len(result.Warnings) == 0 || !strings.Contains(result.Warnings[0], "cache offline") {
+		t.Errorf("Resultado inesperado do modo offline: %+v", result)
+	}
+
+	stale := config.NewEnvLoader(config.WithDecrypter("kms", kms), config.WithOfflineFallback(cacheFile, key, time.Nanosecond))
+	var decryptErr *config.DecryptError
+	if err := stale.LoadEnv(); !errors.As(err, &decryptErr) || decryptErr.Key != "OFFLINE_PASSWORD" {
+		t.Errorf("Esperado um *DecryptError com o cache vencido, obtido %v", err)
+	}
+}
+
+/*
+TestSnapshotCache verifica se WithSnapshotCache grava a configuração resolvida e se LoadSnapshotCache a lê com a
+revisão do carregamento, permitindo descartar caches desatualizados.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestSnapshotCache(t *testing.T) {
+	dir := setupEnvDir(t, "test", "SNAPCACHE_HOST=db\nSNAPCACHE_TOKEN=abc\n")
+	t.Cleanup(func() {
+		os.Unsetenv("SNAPCACHE_HOST")
+		os.Unsetenv("SNAPCACHE_TOKEN")
+	})
+
+	key := make([]byte, 16)
+	cacheFile := path.Join(dir, "snapshot.cache")
+	loader := config.NewEnvLoader(config.WithSecretKeys("*TOKEN"), config.WithSnapshotCache(cacheFile, key))
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+
+	view, savedAt, err := config.LoadSnapshotCache(cacheFile, key)
+	if err != nil {
+		t.Fatalf("Erro ao ler o cache: %v", err)
+	}
+	if time.Since(savedAt) > time.Minute || view.GetString("SNAPCACHE_HOST") != "db" || view.GetEnv() != "test" {
+		t.Errorf("Conteúdo inesperado do cache: %v (%s)", view.All(), savedAt)
+	}
+	if token, ok := view.GetSecret("SNAPCACHE_TOKEN"); !ok || token != "abc" {
+		t.Errorf("O segredo deveria fazer parte do cache")
+	}
+	if !view.EqualRevision(loader) {
+		t.Errorf("A revisão do cache deveria ser igual à do carregamento")
+	}
+
+	if err := os.WriteFile(path.Join(dir, ".env.test"), []byte("SNAPCACHE_HOST=replica\nSNAPCACHE_TOKEN=abc\n"), 0644); err != nil {
+		t.Fatalf("Não foi possível reescrever o arquivo .env: %v", err)
+	}
+	if err := loader.Reload(); err != nil {
+		t.Fatalf("Erro ao recarregar o ambiente: %v", err)
+	}
+	if view.EqualRevision(loader) {
+		t.Errorf("O cache antigo deveria ser reconhecido como desatualizado")
+	}
+	if updated, _, _ := config.LoadSnapshotCache(cacheFile, key); !updated.EqualRevision(loader) {
+		t.Errorf("O cache deveria ter sido regravado com a nova revisão")
+	}
+
+	if _, _, err := config.LoadSnapshotCache(cacheFile, make([]byte, 32)); err == nil {
+		t.Errorf("Esperado um erro ao ler o cache com outra chave")
+	}
+}
+
+/*
+TestOfflineFallbackRejectsOtherEnv verifica se o cache offline gravado para outro ambiente nunca é aplicado, mesmo
+quando os dois carregadores usam o mesmo arquivo de cache.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestOfflineFallbackRejectsOtherEnv(t *testing.T) {
+	key := make([]byte, 32)
+	local, err := config.NewLocalKeyDecrypter(key)
+	if err != nil {
+		t.Fatalf("Erro ao criar a chave: %v", err)
+	}
+	encrypted, err := config.EncryptValue("kms", local, "s3cr3t")
+	if err != nil {
+		t.Fatalf("Erro ao cifrar o valor: %v", err)
+	}
+
+	dir := setupEnvDir(t, "dev", "OFFENV_HOST=dev-host\nOFFENV_PASSWORD="+encrypted+"\n")
+	if err := os.WriteFile(path.Join(dir, ".env.prod"), []byte("OFFENV_HOST=prod-host\nOFFENV_PASSWORD="+encrypted+"\n"), 0644); err != nil {
+		t.Fatalf("Não foi possível criar o arquivo .env: %v", err)
+	}
+	t.Cleanup(func() {
+		os.Unsetenv("OFFENV_HOST")
+		os.Unsetenv("OFFENV_PASSWORD")
+	})
+
+	cacheFile := path.Join(dir, "shared.cache")
+	dev := config.NewEnvLoader(config.WithDecrypter("kms", local), config.WithOfflineFallback(cacheFile, key, 0))
+	if err := dev.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+	os.Unsetenv("OFFENV_HOST")
+	os.Unsetenv("OFFENV_PASSWORD")
+
+	t.Setenv("APP_ENV", "prod")
+	down := config.DecrypterFunc(func([]byte) ([]byte, error) { return nil, errors.New("kms inacessível") })
+	prod := config.NewEnvLoader(config.WithDecrypter("kms", down), config.WithOfflineFallback(cacheFile, key, 0))
+	var decryptErr *config.DecryptError
+	if err := prod.LoadEnv(); !errors.As(err, &decryptErr) {
+		t.Errorf("Esperado um *DecryptError em vez do cache de dev, obtido %v", err)
+	}
+	if host := os.Getenv("OFFENV_HOST"); host != "" || prod.GetEnv() == "dev" {
+		t.Errorf("O cache de dev não deveria ser aplicado em prod: OFFENV_HOST=%q, ambiente %q", host, prod.GetEnv())
+	}
+}
+
+/*
+TestDefaultCachePath verifica se o caminho padrão do cache é diferente para cada projeto e ambiente, e se
+LoadSnapshotCache o encontra sem um caminho explícito.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestDefaultCachePath(t *testing.T) {
+	dir := setupEnvDir(t, "dev", "DEFCACHE_HOST=db\n")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { os.Unsetenv("DEFCACHE_HOST") })
+
+	paths := map[string]bool{
+		config.DefaultCachePath(dir, "dev"):                 true,
+		config.DefaultCachePath(dir, "prod"):                true,
+		config.DefaultCachePath(path.Join(dir, "b"), "dev"): true,
+	}
+	if len(paths) != 3 {
+		t.Errorf("Esperados caminhos diferentes para cada projeto e ambiente, obtidos %v", paths)
+	}
+
+	key := make([]byte, 16)
+	if err := config.NewEnvLoader(config.WithSnapshotCache("", key)).LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+	if _, err := os.Stat(config.DefaultCachePath(dir, "dev")); err != nil {
+		t.Errorf("O cache deveria estar no caminho padrão do projeto: %v", err)
+	}
+	view, _, err := config.LoadSnapshotCache("", key)
+	if err != nil || view.GetString("DEFCACHE_HOST") != "db" {
+		t.Errorf("Esperado o cache do projeto, obtido %v (%v)", view.All(), err)
+	}
+}
+
+/*
+TestSnapshotCacheSecretRotation verifica se o cache é regravado quando apenas um segredo muda, mesmo que a revisão
+pública, que omite os valores dos segredos, continue a mesma.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestSnapshotCacheSecretRotation(t *testing.T) {
+	dir := setupEnvDir(t, "test", "ROTCACHE_HOST=db\nROTCACHE_DB_PASSWORD=v1\n")
+	t.Cleanup(func() {
+		os.Unsetenv("ROTCACHE_HOST")
+		os.Unsetenv("ROTCACHE_DB_PASSWORD")
+	})
+
+	key := make([]byte, 16)
+	cacheFile := path.Join(dir, "snapshot.cache")
+	loader := config.NewEnvLoader(config.WithSecretKeys("*_PASSWORD"), config.WithSnapshotCache(cacheFile, key))
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+	revision := loader.Revision()
+
+	if err := os.WriteFile(path.Join(dir, ".env.test"), []byte("ROTCACHE_HOST=db\nROTCACHE_DB_PASSWORD=v2\n"), 0644); err != nil {
+		t.Fatalf("Não foi possível reescrever o arquivo .env: %v", err)
+	}
+	if err := loader.Reload(); err != nil {
+		t.Fatalf("Erro ao recarregar o ambiente: %v", err)
+	}
+	if loader.Revision() != revision {
+		t.Fatalf("A revisão pública não deveria depender do valor do segredo")
+	}
+
+	view, _, err := config.LoadSnapshotCache(cacheFile, key)
+	if err != nil {
+		t.Fatalf("Erro ao ler o cache: %v", err)
+	}
+	if password, _ := view.GetSecret("ROTCACHE_DB_PASSWORD"); password != "v2" {
+		t.Errorf("Esperado o segredo rotacionado no cache, obtido %q", password)
+	}
+}
@@ -0,0 +1,88 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+func TestCaptureMasksSecretsAndReplays(t *testing.T) {
+	dir := setupEnvDir(t, "development", "CAP_HOST=localhost\nCAP_DB=postgres://app:hunter2@db/app\nCAP_API_TOKEN=tok-123\nCAP_PROCESS=file\n")
+	os.Setenv("CAP_PROCESS", "process")
+	t.Cleanup(func() {
+		for _, key := range []string{"CAP_HOST", "CAP_DB", "CAP_API_TOKEN", "CAP_PROCESS"} {
+			os.Unsetenv(key)
+		}
+	})
+
+	key := []byte("chave-do-suporte")
+	c := config.NewCapture(config.WithSecretKeys("*_TOKEN"))
+	if c.Error != "" {
+		t.Fatalf("Não esperava erro na captura, obteve %s", c.Error)
+	}
+	c.Sign(key)
+
+	file := path.Join(dir, "capture.json")
+	out, err := os.Create(file)
+	if err != nil {
+		t.Fatalf("Não foi possível criar a captura: %v", err)
+	}
+	if _, err := c.WriteTo(out); err != nil {
+		t.Fatalf("Erro ao escrever a captura: %v", err)
+	}
+	out.Close()
+
+	content, _ := os.ReadFile(file)
+	for _, leaked := range []string{"tok-123", "hunter2"} {
+		if strings.Contains(string(content), leaked) {
+			t.Errorf("A captura não deveria conter %q:\n%s", leaked, content)
+		}
+	}
+	if len(c.Candidates) != 1 || !c.Candidates[0].Selected {
+		t.Errorf("Esperava o arquivo .env.development como candidato selecionado, obteve %+v", c.Candidates)
+	}
+
+	if _, _, err := config.LoadFromCapture(file, []byte("outra-chave")); !errors.Is(err, config.ErrInvalidCapture) {
+		t.Errorf("Esperava ErrInvalidCapture com a chave errada, obteve %v", err)
+	}
+
+	m, replayed, err := config.LoadFromCapture(file, key)
+	if err != nil {
+		t.Fatalf("Erro ao carregar a captura: %v", err)
+	}
+	if replayed.Revision != c.Revision || m.GetEnv() != "development" {
+		t.Errorf("Captura reproduzida diferente da original: %+v", replayed)
+	}
+	if got, _ := m.Lookup("CAP_HOST"); got != "localhost" {
+		t.Errorf("Esperava CAP_HOST=localhost, obteve %q", got)
+	}
+	if source, _ := m.Source("CAP_HOST"); !strings.HasSuffix(source, ".env.development") {
+		t.Errorf("Esperava a origem no arquivo .env.development, obteve %q", source)
+	}
+	if got, _ := m.Lookup("CAP_PROCESS"); got != "process" {
+		t.Errorf("Esperava o valor do processo em CAP_PROCESS, obteve %q", got)
+	}
+	if source, _ := m.Source("CAP_PROCESS"); source != config.SourceProcess {
+		t.Errorf("Esperava a origem %q para CAP_PROCESS, obteve %q", config.SourceProcess, source)
+	}
+	if _, ok := m.Lookup("CAP_API_TOKEN"); ok {
+		t.Error("Os segredos não deveriam ser reproduzidos")
+	}
+}
+
+func TestCaptureRecordsLoadError(t *testing.T) {
+	setupEnvDir(t, "development", "CAP_PRESENT=1\n")
+	t.Cleanup(func() { os.Unsetenv("CAP_PRESENT") })
+
+	c := config.NewCapture(config.WithRequired("CAP_MISSING"))
+	if !strings.Contains(c.Error, "CAP_MISSING") {
+		t.Errorf("Esperava o erro de validação na captura, obteve %q", c.Error)
+	}
+	if len(c.Candidates) == 0 {
+		t.Error("Esperava a trilha da descoberta mesmo com erro")
+	}
+}
@@ -0,0 +1,46 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestStrictCascadeReportsConflicts verifica se, com WithStrictCascade, uma sobreposição que redefine uma variável
+com outro valor gera um *CascadeError com as duas declarações, exceto para as variáveis marcadas como sobrescrevíveis.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestStrictCascadeReportsConflicts(t *testing.T) {
+	dir := setupEnvDir(t, "cascade", "CASCADE_SAME=1\nCASCADE_PORT=8080\nCASCADE_LOG=info")
+	t.Setenv("LOCENV_USER", "bob")
+
+	overlay := "CASCADE_SAME=1\nCASCADE_LOG=debug\nCASCADE_PORT=9090"
+	if err := os.WriteFile(path.Join(dir, ".env.cascade.bob"), []byte(overlay), 0644); err != nil {
+		t.Fatalf("Não foi possível criar a sobreposição: %v", err)
+	}
+
+	_, err := config.NewEnvLoader(config.WithUserOverlays(), config.WithStrictCascade("*_LOG")).Plan()
+
+	var cascadeErr *config.CascadeError
+	if !errors.As(err, &cascadeErr) {
+		t.Fatalf("Esperava CascadeError, obteve %v", err)
+	}
+	if len(cascadeErr.Conflicts) != 1 {
+		t.Fatalf("Esperava 1 conflito, obteve %v", cascadeErr.Conflicts)
+	}
+
+	conflict := cascadeErr.Conflicts[0]
+	if conflict.Key != "CASCADE_PORT" || !strings.HasSuffix(conflict.First, ".env.cascade:2") || !strings.HasSuffix(conflict.Second, ".env.cascade.bob:3") {
+		t.Errorf("Conflito inesperado: %+v", conflict)
+	}
+
+	if _, err := config.NewEnvLoader(config.WithUserOverlays()).Plan(); err != nil {
+		t.Errorf("Sem o modo estrito, a sobreposição deveria prevalecer: %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/go-locEnv/config"
+	"github.com/jonh-dev/go-locEnv/locenvtest"
+)
+
+// passthrough é o Decrypter real dos testes de caos: os valores são o próprio texto em claro em base64.
+var passthrough = config.DecrypterFunc(func(ciphertext []byte) ([]byte, error) {
+	return append([]byte(nil), ciphertext...), nil
+})
+
+// chaosValue retorna o valor cifrado do backend chaos para o texto em claro informado.
+func chaosValue(plaintext string) string {
+	return "enc:chaos:" + base64.StdEncoding.EncodeToString([]byte(plaintext))
+}
+
+/*
+TestChaosProviderScriptAndRetry verifica se as falhas programadas em Script são superadas por WithDecryptRetry,
+com um aviso por nova tentativa, e se as respostas parciais são detectadas pela validação do conteúdo.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestChaosProviderScriptAndRetry(t *testing.T) {
+	hmac := strings.Repeat("ab", 32)
+	setupEnvDir(t, "chaos", "CHAOS_PASSWORD="+chaosValue("s3cr3t")+"\nCHAOS_HMAC="+chaosValue(hmac)+"\n")
+	t.Cleanup(func() { os.Unsetenv("CHAOS_PASSWORD"); os.Unsetenv("CHAOS_HMAC") })
+
+	chaos := &locenvtest.ChaosProvider{Decrypter: passthrough, Script: []locenvtest.Fault{locenvtest.Fail, locenvtest.Fail}}
+	var retries int
+	loader := config.NewEnvLoader(
+		config.WithNoProcessEnv(),
+		config.WithDecrypter("chaos", chaos),
+		config.WithDecryptRetry(3, time.Millisecond),
+		config.WithHexContent("CHAOS_HMAC", config.ExactLength(32)),
+		config.WithWarningHandler(func(w config.Warning) {
+			if w.Kind == config.WarningRetry {
+				retries++
+			}
+		}),
+	)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("As novas tentativas deveriam superar as falhas programadas: %s", err)
+	}
+	if stats := chaos.Stats(); stats.Calls != 4 || stats.Failures != 2 || retries != 2 {
+		t.Errorf("Contagens inesperadas: %+v, %d novas tentativas", stats, retries)
+	}
+	if got := loader.GetString("CHAOS_PASSWORD"); got != "s3cr3t" {
+		t.Errorf("Valor inesperado: %q", got)
+	}
+
+	chaos.Reset()
+	chaos.Script = []locenvtest.Fault{locenvtest.Partial, locenvtest.Partial}
+	err := loader.Reload()
+	var validation *config.ValidationError
+	if !errors.As(err, &validation) || len(validation.Problems) != 1 || !strings.Contains(validation.Problems[0], "CHAOS_HMAC") {
+		t.Errorf("Esperava a resposta parcial detectada na validação, obteve %v", err)
+	}
+}
+
+/*
+TestChaosProviderFallbackAndSoak verifica se, com o provedor sempre indisponível, WithOfflineFallback mantém os
+carregamentos de Soak bem-sucedidos, e se a latência simulada e as quedas de FlapPeriod são observadas.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestChaosProviderFallbackAndSoak(t *testing.T) {
+	dir := setupEnvDir(t, "soak", "SOAK_TOKEN="+chaosValue("t0k3n")+"\n")
+	t.Cleanup(func() { os.Unsetenv("SOAK_TOKEN") })
+
+	chaos := &locenvtest.ChaosProvider{Decrypter: passthrough, Latency: 2 * time.Millisecond, Jitter: time.Millisecond}
+	loader := config.NewEnvLoader(
+		config.WithSilent(),
+		config.WithDecrypter("chaos", chaos),
+		config.WithOfflineFallback(path.Join(dir, "soak.cache"), make([]byte, 32), time.Hour),
+	)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	chaos.FailureRate = 1
+	report := locenvtest.Soak(5, loader.Reload)
+	if report.Runs != 5 || report.SuccessRate() != 1 {
+		t.Errorf("O cache offline deveria manter todas as recargas, obteve %+v", report)
+	}
+	if report.MaxDuration < 2*time.Millisecond {
+		t.Errorf("A latência simulada deveria ser observada, duração máxima %s", report.MaxDuration)
+	}
+	if stats := chaos.Stats(); stats.Failures != 5 {
+		t.Errorf("Esperava 5 falhas, obteve %+v", stats)
+	}
+
+	without := locenvtest.Soak(3, config.NewEnvLoader(config.WithSilent(), config.WithDecrypter("chaos", chaos)).LoadEnv)
+	var decryptErr *config.DecryptError
+	if without.SuccessRate() != 0 || !errors.As(without.Errors[0], &decryptErr) || !errors.Is(without.Errors[0], locenvtest.ErrChaosUnavailable) {
+		t.Errorf("Sem o cache offline, esperava apenas falhas, obteve %+v", without)
+	}
+
+	flapping := &locenvtest.ChaosProvider{Decrypter: passthrough, FlapPeriod: 100 * time.Millisecond}
+	if _, err := flapping.Decrypt([]byte("x")); err != nil {
+		t.Fatalf("O provedor deveria começar disponível: %s", err)
+	}
+	time.Sleep(120 * time.Millisecond)
+	if err := flapping.CheckHealth(context.Background()); !errors.Is(err, locenvtest.ErrChaosUnavailable) {
+		t.Errorf("Esperava o provedor indisponível no segundo período, obteve %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestEnvironmentClassification verifica se APP_ENV é normalizado e se os apelidos padrão e configurados
+são considerados por IsProduction, IsDevelopment e IsTest.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestEnvironmentClassification(t *testing.T) {
+	t.Setenv("APP_ENV", " Prod ")
+	loader := config.NewEnvLoader()
+	if loader.GetEnv() != "prod" {
+		t.Errorf("Esperado %s, obtido %s", "prod", loader.GetEnv())
+	}
+	if !loader.IsProduction() || loader.IsDevelopment() || loader.IsTest() {
+		t.Errorf("Esperado apenas produção para o ambiente %s", loader.GetEnv())
+	}
+
+	t.Setenv("APP_ENV", "live")
+	loader = config.NewEnvLoader(config.WithClassAliases(config.ClassProduction, "live"))
+	if !loader.IsProduction() {
+		t.Errorf("Esperado produção para o apelido configurado %s", loader.GetEnv())
+	}
+}
@@ -0,0 +1,115 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/go-locEnv/config"
+	"go.uber.org/goleak"
+)
+
+// closerDecrypter é um Decrypter que registra o seu encerramento.
+type closerDecrypter struct {
+	closed bool
+}
+
+func (d *closerDecrypter) Decrypt(ciphertext []byte) ([]byte, error) {
+	return ciphertext, nil
+}
+
+func (d *closerDecrypter) Close() error {
+	d.closed = true
+	return nil
+}
+
+/*
+TestCloseStopsBackgroundComponents verifica se Close entrega as rotações pendentes, encerra WatchFrozen e
+ListenSSE, fecha os Decrypters e não deixa goroutines da biblioteca em execução.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestCloseStopsBackgroundComponents(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+	dir := setupEnvDir(t, "close", "CLS_DB_PASSWORD=v1\n")
+	t.Cleanup(func() { os.Unsetenv("CLS_DB_PASSWORD") })
+
+	decrypter := &closerDecrypter{}
+	rotations := make(chan config.SecretRotation, 1)
+	loader := config.NewEnvLoader(
+		config.WithSilent(),
+		config.WithSecretKeys("*_PASSWORD"),
+		config.WithDecrypter("test", decrypter),
+		config.WithRotationDebounce(time.Hour),
+		config.WithRotationHook("*_PASSWORD", func(r config.SecretRotation) { rotations <- r }),
+	).(*config.FileEnvLoader)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if err := os.WriteFile(path.Join(dir, ".env.close"), []byte("CLS_DB_PASSWORD=v2\n"), 0644); err != nil {
+		t.Fatalf("Não foi possível atualizar o arquivo .env: %v", err)
+	}
+	if err := loader.Reload(); err != nil {
+		t.Fatalf("Erro ao recarregar variáveis de ambiente: %s", err)
+	}
+
+	frozen := config.NewEnvLoader(config.WithSilent()).(*config.FileEnvLoader)
+	frozen.Freeze()
+	if err := frozen.WatchFrozen(context.Background(), time.Millisecond, func([]config.KeyChange) {}); err != nil {
+		t.Fatalf("Erro ao iniciar WatchFrozen: %s", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	listened := make(chan error, 1)
+	listener := &config.PushListener{Loader: loader, Secret: []byte("compartilhado")}
+	go func() { listened <- listener.ListenSSE(context.Background(), server.URL) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := loader.Close(ctx); err != nil {
+		t.Fatalf("Erro ao encerrar o carregador: %s", err)
+	}
+	if err := frozen.Close(ctx); err != nil {
+		t.Fatalf("Erro ao encerrar o carregador congelado: %s", err)
+	}
+
+	select {
+	case r := <-rotations:
+		if r.Old != "v1" || r.New != "v2" {
+			t.Errorf("Rotação inesperada: %+v", r)
+		}
+	default:
+		t.Error("Close deveria entregar a rotação pendente")
+	}
+	if err := <-listened; !errors.Is(err, config.ErrClosed) {
+		t.Errorf("Esperava ErrClosed de ListenSSE, obteve %v", err)
+	}
+	if !decrypter.closed {
+		t.Error("Close deveria encerrar o Decrypter")
+	}
+	if err := loader.Reload(); !errors.Is(err, config.ErrClosed) {
+		t.Errorf("Esperava ErrClosed de Reload, obteve %v", err)
+	}
+	if err := frozen.WatchFrozen(context.Background(), time.Millisecond, nil); !errors.Is(err, config.ErrClosed) {
+		t.Errorf("Esperava ErrClosed de WatchFrozen, obteve %v", err)
+	}
+	if err := loader.Close(ctx); err != nil {
+		t.Errorf("Esperava nil em uma segunda chamada de Close, obteve %s", err)
+	}
+	if got := loader.GetString("CLS_DB_PASSWORD"); got != "v2" {
+		t.Errorf("As variáveis deveriam continuar disponíveis, obteve %q", got)
+	}
+
+	server.Close()
+	http.DefaultClient.CloseIdleConnections()
+}
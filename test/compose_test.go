@@ -0,0 +1,113 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+	"github.com/jonh-dev/go-locEnv/locenvtest"
+)
+
+/*
+TestStartComposeFindsFileAndExportsPorts verifica se StartCompose encontra o compose do ambiente ao lado do
+arquivo .env, inicia os serviços aguardando os healthchecks e exporta as portas publicadas como variáveis.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestStartComposeFindsFileAndExportsPorts(t *testing.T) {
+	dir := setupEnvDir(t, "test", "CMP_DB_HOST=db\n")
+	t.Cleanup(func() { os.Unsetenv("CMP_DB_HOST") })
+
+	loader := config.NewEnvLoader(config.WithSilent())
+	result, err := loader.LoadEnvResult()
+	if err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if _, err := locenvtest.StartCompose(context.Background(), result, locenvtest.ComposeOptions{}); !errors.Is(err, locenvtest.ErrComposeNotFound) {
+		t.Errorf("Esperava ErrComposeNotFound sem o arquivo compose, obteve %v", err)
+	}
+	if err := os.WriteFile(path.Join(dir, "docker-compose.test.yml"), []byte("services: {}\n"), 0644); err != nil {
+		t.Fatalf("Não foi possível criar o arquivo compose: %v", err)
+	}
+
+	var calls []string
+	run := func(ctx context.Context, args ...string) ([]byte, error) {
+		calls = append(calls, strings.Join(args, " "))
+		if args[4] == "port" {
+			return []byte("0.0.0.0:49160\n"), nil
+		}
+		return nil, nil
+	}
+	stack, err := locenvtest.StartCompose(context.Background(), result, locenvtest.ComposeOptions{
+		Boot:    true,
+		Project: "cmp",
+		Run:     run,
+		Ports: []locenvtest.ComposePort{{
+			Service:       "db",
+			ContainerPort: locenvtest.ContainerPort{Port: "5432/tcp", HostKey: "CMP_DB_HOST", PortKey: "CMP_DB_PORT"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Erro ao iniciar o compose: %s", err)
+	}
+	if filepath.Base(stack.File) != "docker-compose.test.yml" || stack.Env["CMP_DB_HOST"] != "127.0.0.1" || stack.Env["CMP_DB_PORT"] != "49160" {
+		t.Errorf("Compose inesperado: %+v", stack)
+	}
+	if err := stack.Down(context.Background()); err != nil {
+		t.Fatalf("Erro ao encerrar o compose: %s", err)
+	}
+
+	file := stack.File
+	want := []string{
+		"-f " + file + " -p cmp up -d --wait --wait-timeout 120",
+		"-f " + file + " -p cmp port --protocol tcp db 5432",
+		"-f " + file + " -p cmp down --volumes --remove-orphans",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Comandos inesperados:\n%s", strings.Join(calls, "\n"))
+	}
+}
+
+/*
+TestStartComposeDownOnPortFailure verifica se StartCompose encerra os serviços iniciados com Boot quando uma porta
+não pode ser consultada, em vez de deixá-los em execução.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestStartComposeDownOnPortFailure(t *testing.T) {
+	dir := setupEnvDir(t, "test", "CMP_FAIL_HOST=db\n")
+	t.Cleanup(func() { os.Unsetenv("CMP_FAIL_HOST") })
+	if err := os.WriteFile(path.Join(dir, "compose.test.yaml"), []byte("services: {}\n"), 0644); err != nil {
+		t.Fatalf("Não foi possível criar o arquivo compose: %v", err)
+	}
+
+	result, err := config.NewEnvLoader(config.WithSilent()).LoadEnvResult()
+	if err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	var calls []string
+	run := func(ctx context.Context, args ...string) ([]byte, error) {
+		calls = append(calls, args[4])
+		if args[4] == "port" {
+			return nil, errors.New("no container found for db_1")
+		}
+		return nil, nil
+	}
+	stack, err := locenvtest.StartCompose(context.Background(), result, locenvtest.ComposeOptions{
+		Boot:  true,
+		Run:   run,
+		Ports: []locenvtest.ComposePort{{Service: "db", ContainerPort: locenvtest.ContainerPort{Port: "5432", PortKey: "CMP_FAIL_PORT"}}},
+	})
+	if err == nil || stack != nil || !strings.Contains(err.Error(), "serviço db") {
+		t.Fatalf("Esperava o erro da porta, obteve %v, %v", stack, err)
+	}
+	if strings.Join(calls, ",") != "up,port,down" {
+		t.Errorf("Esperava up, port e down, obteve %v", calls)
+	}
+}
@@ -0,0 +1,137 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/go-locEnv/config"
+	"go.uber.org/goleak"
+)
+
+/*
+TestConcurrentSnapshotReadsDuringReloads verifica se visões publicadas com Snapshot podem ser lidas por várias
+goroutines enquanto a configuração é recarregada, sem corridas e com valores sempre consistentes.
+
+Como os demais testes deste arquivo, deve ser executado com go test -race.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestConcurrentSnapshotReadsDuringReloads(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+	dir := setupEnvDir(t, "race", "RACE_VERSION=0\nRACE_TWICE=0\n")
+	t.Cleanup(func() { os.Unsetenv("RACE_VERSION"); os.Unsetenv("RACE_TWICE") })
+
+	loader := config.NewEnvLoader(config.WithSilent()).(*config.FileEnvLoader)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	var current atomic.Pointer[config.ConfigView]
+	view := loader.Snapshot()
+	current.Store(&view)
+
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				view := current.Load()
+				if version, twice := view.GetString("RACE_VERSION"), view.GetString("RACE_TWICE"); version != twice {
+					t.Errorf("Visão inconsistente: RACE_VERSION=%s e RACE_TWICE=%s", version, twice)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 1; i <= 20; i++ {
+		content := fmt.Sprintf("RACE_VERSION=%d\nRACE_TWICE=%d\n", i, i)
+		if err := os.WriteFile(path.Join(dir, ".env.race"), []byte(content), 0644); err != nil {
+			t.Fatalf("Não foi possível atualizar o arquivo .env: %v", err)
+		}
+		if err := loader.Reload(); err != nil {
+			t.Fatalf("Erro ao recarregar variáveis de ambiente: %s", err)
+		}
+		view := loader.Snapshot()
+		current.Store(&view)
+	}
+	close(done)
+	readers.Wait()
+
+	if got := current.Load().GetString("RACE_VERSION"); got != "20" {
+		t.Errorf("Esperava a última versão publicada, obteve %s", got)
+	}
+}
+
+/*
+TestConcurrentPushNotifications verifica se notificações simultâneas de um PushListener serializam as recargas,
+se os métodos seguros podem ser chamados durante elas e se Close não deixa goroutines em execução.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestConcurrentPushNotifications(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+	setupEnvDir(t, "racepush", "RACE_PUSH_LEVEL=info\n")
+	t.Cleanup(func() { os.Unsetenv("RACE_PUSH_LEVEL") })
+
+	loader := config.NewEnvLoader(config.WithSilent()).(*config.FileEnvLoader)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	secret := []byte("compartilhado")
+	var reloading, overlaps, reloads atomic.Int32
+	listener := &config.PushListener{Loader: loader, Secret: secret, OnReload: func(_ config.ChangeNotification, err error) {
+		if reloading.Add(1) > 1 {
+			overlaps.Add(1)
+		}
+		if err == nil {
+			reloads.Add(1)
+		}
+		time.Sleep(time.Millisecond)
+		reloading.Add(-1)
+	}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			n := config.SignNotification(secret, config.ChangeNotification{ID: fmt.Sprintf("race-%d", i), Timestamp: time.Now().Unix()})
+			if err := listener.Accept(n); err != nil {
+				t.Errorf("Erro ao aceitar a notificação %d: %s", i, err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			loader.Health()
+			loader.Frozen()
+		}()
+	}
+	wg.Wait()
+
+	if overlaps.Load() != 0 {
+		t.Errorf("As recargas deveriam ser serializadas, obteve %d sobreposições", overlaps.Load())
+	}
+	if reloads.Load() != 16 {
+		t.Errorf("Esperava 16 recargas, obteve %d", reloads.Load())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := loader.Close(ctx); err != nil {
+		t.Fatalf("Erro ao encerrar o carregador: %s", err)
+	}
+}
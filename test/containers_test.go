@@ -0,0 +1,57 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+	"github.com/jonh-dev/go-locEnv/locenvtest"
+)
+
+/*
+TestContainerEnvAndOverrides verifica se ContainerEnv seleciona as variáveis e os segredos isolados nomeados, e
+se as portas expostas por ContainerOverrides sobrepõem os arquivos quando passadas a WithValues.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestContainerEnvAndOverrides(t *testing.T) {
+	setupEnvDir(t, "containers", "CTR_DB_HOST=db.internal\nCTR_DB_PORT=5432\nCTR_DB_PASSWORD=s3cr3t\nCTR_OTHER=x\n")
+
+	loader := config.NewEnvLoader(config.WithSilent(), config.WithNoProcessEnv(), config.WithSecretKeys("*_PASSWORD"), config.WithSecretIsolation())
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	want := map[string]string{"CTR_DB_HOST": "db.internal", "CTR_DB_PORT": "5432", "CTR_DB_PASSWORD": "s3cr3t"}
+	if got := locenvtest.ContainerEnv(loader, "CTR_DB_*", "CTR_DB_PASSWORD"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Esperava %v, obteve %v", want, got)
+	}
+	if got := locenvtest.ContainerEnv(loader); len(got) != 3 {
+		t.Errorf("Sem padrões, esperava as três variáveis não isoladas, obteve %v", got)
+	}
+
+	endpoint := func(ctx context.Context, port string) (string, error) {
+		if port != "5432/tcp" {
+			return "", errors.New("porta não exposta")
+		}
+		return "127.0.0.1:49153", nil
+	}
+	overrides, err := locenvtest.ContainerOverrides(context.Background(), endpoint,
+		locenvtest.ContainerPort{Port: "5432/tcp", HostKey: "CTR_DB_HOST", PortKey: "CTR_DB_PORT", AddrKey: "CTR_DB_ADDR"})
+	if err != nil {
+		t.Fatalf("Erro ao obter as portas do contêiner: %s", err)
+	}
+	bridged := config.NewEnvLoader(config.WithSilent(), config.WithNoProcessEnv(), config.WithValues(overrides))
+	if err := bridged.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if bridged.GetString("CTR_DB_HOST") != "127.0.0.1" || bridged.GetString("CTR_DB_PORT") != "49153" ||
+		bridged.GetString("CTR_DB_ADDR") != "127.0.0.1:49153" || bridged.GetString("CTR_OTHER") != "x" {
+		t.Errorf("Valores inesperados: %v", bridged.All())
+	}
+
+	if _, err := locenvtest.ContainerOverrides(context.Background(), endpoint, locenvtest.ContainerPort{Port: "6379/tcp"}); err == nil {
+		t.Error("Esperava um erro para a porta não exposta")
+	}
+}
@@ -0,0 +1,55 @@
+package test
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestContentGetters verifica se GetBytesBase64 e GetFileContent decodificam e leem o conteúdo das variáveis, e se
+WithBase64Content e WithFileContent detectam conteúdos inválidos durante o carregamento.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestContentGetters(t *testing.T) {
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("conteúdo")})
+	encoded := base64.StdEncoding.EncodeToString(cert)
+
+	dir := setupEnvDir(t, "test", "CONTENT_CERT_B64="+encoded+"\nCONTENT_CA_PATH=ca.pem\nCONTENT_BAD_B64=bm90IHBlbQ==\n")
+	if err := os.WriteFile(path.Join(dir, "ca.pem"), cert, 0644); err != nil {
+		t.Fatalf("Não foi possível criar o arquivo: %v", err)
+	}
+	t.Cleanup(func() {
+		for _, key := range []string{"CONTENT_CERT_B64", "CONTENT_CA_PATH", "CONTENT_BAD_B64"} {
+			os.Unsetenv(key)
+		}
+	})
+
+	loader := config.NewEnvLoader(
+		config.WithBase64Content("CONTENT_CERT_B64", config.PEMCheck),
+		config.WithFileContent("CONTENT_CA_PATH", config.PEMCheck),
+	)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+
+	if got, err := loader.GetBytesBase64("CONTENT_CERT_B64"); err != nil || string(got) != string(cert) {
+		t.Errorf("Conteúdo base64 inesperado: %q (%v)", got, err)
+	}
+	if got, err := loader.GetFileContent("CONTENT_CA_PATH"); err != nil || string(got) != string(cert) {
+		t.Errorf("Conteúdo do arquivo inesperado: %q (%v)", got, err)
+	}
+
+	err := config.NewEnvLoader(config.WithBase64Content("CONTENT_BAD_B64", config.PEMCheck)).LoadEnv()
+	var validationErr *config.ValidationError
+	if !errors.As(err, &validationErr) || !strings.Contains(err.Error(), "CONTENT_BAD_B64") {
+		t.Errorf("Esperado um *ValidationError para CONTENT_BAD_B64, obtido %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestCronValidation verifica se WithCron e WithCronEvery reúnem as expressões cron inválidas em um único erro de
+validação, e se GetCron aceita o atalho @every apenas nas variáveis declaradas com WithCronEvery.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestCronValidation(t *testing.T) {
+	setupEnvDir(t, "cron", strings.Join([]string{
+		`CRN_CLEANUP="*/15 2-4 * JAN-mar mon,fri"`,
+		"CRN_DAILY=@daily",
+		"CRN_SYNC=@every 90s",
+		`CRN_MINUTE="61 * * * *"`,
+		`CRN_FIELDS="* * * *"`,
+		"CRN_EVERY=@every 5m",
+		`CRN_RANGE="0 0 * * 5-1"`,
+	}, "\n")+"\n")
+
+	valid := []config.Option{config.WithNoProcessEnv(), config.WithSilent(), config.WithCron("CRN_CLEANUP", "CRN_DAILY", "CRN_UNSET"), config.WithCronEvery("CRN_SYNC")}
+	err := config.NewEnvLoader(append(valid, config.WithCron("CRN_MINUTE", "CRN_FIELDS", "CRN_EVERY", "CRN_RANGE"))...).LoadEnv()
+	var validation *config.ValidationError
+	if !errors.As(err, &validation) || len(validation.Problems) != 4 {
+		t.Fatalf("Esperava quatro problemas de cron, obteve %v", err)
+	}
+	for i, want := range []string{"fora do intervalo 0-59", "esperados 5 campos", "WithCronEvery", "invertido"} {
+		if !strings.Contains(validation.Problems[i], want) {
+			t.Errorf("Esperava %q no problema %d, obteve %s", want, i, validation.Problems[i])
+		}
+	}
+
+	loader := config.NewEnvLoader(valid...)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	var reader config.Reader = loader
+	if got, err := reader.GetCron("CRN_CLEANUP"); err != nil || got != "*/15 2-4 * JAN-mar mon,fri" {
+		t.Errorf("Esperava a expressão de limpeza, obteve %q, %v", got, err)
+	}
+	if got, err := reader.GetCron("CRN_SYNC"); err != nil || got != "@every 90s" {
+		t.Errorf("Esperava o atalho @every, obteve %q, %v", got, err)
+	}
+	if _, err := reader.GetCron("CRN_EVERY"); err == nil {
+		t.Error("Esperava erro para @every em uma variável sem WithCronEvery")
+	}
+	if _, err := reader.GetCron("CRN_UNSET"); !errors.Is(err, config.ErrKeyNotFound) {
+		t.Errorf("Esperava ErrKeyNotFound, obteve %v", err)
+	}
+}
+
+/*
+TestCronExpressions verifica, expressão por expressão, se GetCron e a validação do carregamento aceitam listas,
+intervalos, nomes, passos e atalhos, e rejeitam campos inválidos, valores fora dos limites e passos malformados.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestCronExpressions(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		every bool
+		want  string
+	}{
+		{"lista e intervalo", "0,30 9-17 * * 1-5", false, ""},
+		{"nomes", "0 0 1 jan,JUL SUN", false, ""},
+		{"domingo como 7", "0 0 * * 7", false, ""},
+		{"passo em *", "*/5 * * * *", false, ""},
+		{"passo em intervalo", "0-30/10 */2 1-31/7 * *", false, ""},
+		{"passo a partir de um valor", "5/15 * * * *", false, ""},
+		{"atalho", "@hourly", false, ""},
+		{"atalho em maiúsculas", "@Weekly", false, ""},
+		{"@every declarado", "@every 1h30m", true, ""},
+		{"campos a menos", "* * * *", false, "esperados 5 campos, encontrados 4"},
+		{"campos a mais", "0 0 * * * 2024", false, "esperados 5 campos, encontrados 6"},
+		{"valor não numérico", "x * * * *", false, `campo minuto: valor "x" inválido`},
+		{"nome em campo numérico", "0 mon * * *", false, `campo hora: valor "mon" inválido`},
+		{"item vazio na lista", "0 1,,2 * * *", false, `campo hora: valor "" inválido`},
+		{"minuto fora do intervalo", "60 * * * *", false, "campo minuto: valor 60 fora do intervalo 0-59"},
+		{"hora fora do intervalo", "0 24 * * *", false, "campo hora: valor 24 fora do intervalo 0-23"},
+		{"dia zero", "0 0 0 * *", false, "campo dia do mês: valor 0 fora do intervalo 1-31"},
+		{"mês fora do intervalo", "0 0 1 13 *", false, "campo mês: valor 13 fora do intervalo 1-12"},
+		{"dia da semana fora do intervalo", "0 0 * * 8", false, "campo dia da semana: valor 8 fora do intervalo 0-7"},
+		{"fim do intervalo fora dos limites", "0 20-25 * * *", false, "valor 25 fora do intervalo 0-23"},
+		{"intervalo invertido", "0 0 * * fri-mon", false, `intervalo "fri-mon" invertido`},
+		{"passo zero", "*/0 * * * *", false, `passo "0" inválido`},
+		{"passo negativo", "*/-2 * * * *", false, `passo "-2" inválido`},
+		{"passo vazio", "0-30/ * * * *", false, `passo "" inválido`},
+		{"passo não numérico", "*/abc * * * *", false, `passo "abc" inválido`},
+		{"atalho desconhecido", "@sometimes", false, "atalho @sometimes desconhecido"},
+		{"@every sem WithCronEvery", "@every 5m", false, "WithCronEvery"},
+		{"@every sem duração", "@every", true, `duração "" inválida`},
+		{"@every com duração negativa", "@every -5m", true, `duração "-5m" inválida`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupEnvDir(t, "cron", `CRN_EXPR="`+tt.value+`"`+"\n")
+			rule := config.WithCron("CRN_EXPR")
+			if tt.every {
+				rule = config.WithCronEvery("CRN_EXPR")
+			}
+
+			loader := config.NewEnvLoader(config.WithNoProcessEnv(), config.WithSilent(), rule)
+			loadErr := loader.LoadEnv()
+			if tt.want == "" {
+				if loadErr != nil {
+					t.Fatalf("Erro ao carregar variáveis de ambiente: %s", loadErr)
+				}
+				if got, err := config.Reader(loader).GetCron("CRN_EXPR"); err != nil || got != tt.value {
+					t.Errorf("Esperava a expressão %q, obteve %q, %v", tt.value, got, err)
+				}
+				return
+			}
+
+			var validation *config.ValidationError
+			if !errors.As(loadErr, &validation) || len(validation.Problems) != 1 || !strings.Contains(validation.Problems[0], tt.want) {
+				t.Errorf("Esperava um problema de validação com %q, obteve %v", tt.want, loadErr)
+			}
+			if tt.every {
+				return
+			}
+
+			lenient := config.NewEnvLoader(config.WithNoProcessEnv(), config.WithSilent())
+			if err := lenient.LoadEnv(); err != nil {
+				t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+			}
+			if _, err := config.Reader(lenient).GetCron("CRN_EXPR"); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Esperava erro com %q em GetCron, obteve %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestExpansionCycles verifica se os ciclos de referências ${VAR} são reportados com a cadeia de variáveis e se
+as referências adiante sem ciclo e as auto-referências sem declaração anterior continuam aceitas.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestExpansionCycles(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		keys    []string
+		lines   []int
+	}{
+		{"ciclo direto", "A=${B}\nB=${A}\n", []string{"A", "B", "A"}, []int{1, 2, 1}},
+		{"ciclo indireto", "X=1\nA=\"prefixo ${C}\"\nB=$A\nC=${B}/sufixo\n", []string{"A", "C", "B", "A"}, []int{2, 4, 3, 2}},
+		{"sem ciclo", "A=${B}\nB=2\nPATH=${PATH}:/bin\nC=${A}\n", nil, nil},
+		{"redeclaração", "A=${B}\nB=1\nA=${B}\nB=${A}\n", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := config.Parse(strings.NewReader(tt.content))
+			if tt.keys == nil {
+				if err != nil {
+					t.Fatalf("Não esperava erro, obteve %v", err)
+				}
+				return
+			}
+
+			var cycle *config.ExpansionCycleError
+			if !errors.As(err, &cycle) {
+				t.Fatalf("Esperava *ExpansionCycleError, obteve %v", err)
+			}
+			if !reflect.DeepEqual(cycle.Keys, tt.keys) || !reflect.DeepEqual(cycle.Lines, tt.lines) {
+				t.Errorf("Esperado %v nas linhas %v, obtido %v nas linhas %v", tt.keys, tt.lines, cycle.Keys, cycle.Lines)
+			}
+		})
+	}
+
+	setupEnvDir(t, "cycles", "CYC_A=${CYC_B}\nCYC_B=${CYC_A}\n")
+	err := config.NewEnvLoader(config.WithSilent(), config.WithNoProcessEnv()).LoadEnv()
+	if err == nil || !strings.Contains(err.Error(), ".env.cycles:1: referência circular na expansão: CYC_A (linha 1) -> CYC_B (linha 2) -> CYC_A (linha 1)") {
+		t.Errorf("Esperava o ciclo no erro do carregamento, obteve %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestDecimalComma verifica se WithDecimalComma aceita a vírgula decimal e se o modo estrito rejeita valores
+ambíguos informando o arquivo e a linha.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestDecimalComma(t *testing.T) {
+	setupEnvDir(t, "test", "DEC_RATE=0,5\nDEC_TOTAL=1.234,5\nDEC_AMBIGUOUS=1,234\n")
+	unset := func() {
+		for _, key := range []string{"DEC_RATE", "DEC_TOTAL", "DEC_AMBIGUOUS"} {
+			os.Unsetenv(key)
+		}
+	}
+	t.Cleanup(unset)
+
+	lenient := config.NewEnvLoader(config.WithDecimalComma(false))
+	if err := lenient.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+	for key, want := range map[string]float64{"DEC_RATE": 0.5, "DEC_TOTAL": 1234.5, "DEC_AMBIGUOUS": 1.234} {
+		if got, err := lenient.GetFloat(key); err != nil || got != want {
+			t.Errorf("%s: esperado %v, obtido %v (%v)", key, want, got, err)
+		}
+	}
+
+	unset()
+	strict := config.NewEnvLoader(config.WithDecimalComma(true))
+	if err := strict.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+	if got, err := strict.GetFloat("DEC_RATE"); err != nil || got != 0.5 {
+		t.Errorf("DEC_RATE: esperado 0.5, obtido %v (%v)", got, err)
+	}
+	for _, key := range []string{"DEC_TOTAL", "DEC_AMBIGUOUS"} {
+		if _, err := strict.GetFloat(key); err == nil || !strings.Contains(err.Error(), ".env.test:") {
+			t.Errorf("%s: esperado um erro de ambiguidade com a linha, obtido %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package test
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"os"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestLoadEnvDecryptsLocalKeyValues verifica se a função LoadEnv decifra valores com o marcador "enc:local:"
+usando o LocalKeyDecrypter registrado, mantendo os valores sem marcador inalterados.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestLoadEnvDecryptsLocalKeyValues(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	// Cifra o valor com a mesma chave que será usada pelo carregador
+	block, _ := aes.NewCipher(key)
+	aead, _ := cipher.NewGCM(block)
+	nonce := make([]byte, aead.NonceSize())
+	sealed := aead.Seal(nonce, nonce, []byte("s3cr3t"), nil)
+	encoded := base64.StdEncoding.EncodeToString(sealed)
+
+	setupEnvDir(t, "crypt", "DECRYPT_PASSWORD=enc:local:"+encoded+"\nDECRYPT_HOST=localhost")
+
+	decrypter, err := config.NewLocalKeyDecrypter(key)
+	if err != nil {
+		t.Fatalf("Não foi possível criar o Decrypter: %v", err)
+	}
+
+	loader := config.NewEnvLoader(config.WithDecrypter("local", decrypter))
+	err = loader.LoadEnv()
+	if err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	if got := os.Getenv("DECRYPT_PASSWORD"); got != "s3cr3t" {
+		t.Errorf("Esperado %s, obtido %s", "s3cr3t", got)
+	}
+	if got := os.Getenv("DECRYPT_HOST"); got != "localhost" {
+		t.Errorf("Esperado %s, obtido %s", "localhost", got)
+	}
+}
+
+/*
+TestLoadEnvFailsWithoutDecrypter verifica se a função LoadEnv retorna um erro quando um valor cifrado
+usa um backend sem Decrypter registrado.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestLoadEnvFailsWithoutDecrypter(t *testing.T) {
+	setupEnvDir(t, "nokms", "NOKMS_PASSWORD=enc:kms:AQICAH")
+
+	loader := config.NewEnvLoader()
+	if err := loader.LoadEnv(); err == nil {
+		t.Errorf("Esperado erro para backend sem Decrypter, obtido nil")
+	}
+	if _, exists := os.LookupEnv("NOKMS_PASSWORD"); exists {
+		t.Errorf("A variável cifrada não deveria ter sido aplicada")
+	}
+}
@@ -0,0 +1,52 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestDeprecatedKeys verifica se uma variável renomeada é mapeada para o nome novo com um aviso que identifica o
+arquivo e a linha, e se a verificação estrita rejeita o nome antigo depois do prazo.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestDeprecatedKeys(t *testing.T) {
+	setupEnvDir(t, "test", "DEP_PORT=8080\nDEP_OLD_URL=http://legacy\n")
+	unset := func() {
+		for _, key := range []string{"DEP_PORT", "DEP_OLD_URL", "DEP_NEW_URL"} {
+			os.Unsetenv(key)
+		}
+	}
+	t.Cleanup(unset)
+
+	loader := config.NewEnvLoader(
+		config.Deprecate("DEP_OLD_URL", "DEP_NEW_URL", "v2.0"),
+		config.WithStrictDeprecations(time.Now().Add(time.Hour)),
+	)
+	result, err := loader.LoadEnvResult()
+	if err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+	if got := loader.GetString("DEP_NEW_URL"); got != "http://legacy" {
+		t.Errorf("Esperado o valor do nome antigo em DEP_NEW_URL, obtido %q", got)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], ".env.test:2") || !strings.Contains(result.Warnings[0], "v2.0") {
+		t.Errorf("Aviso inesperado: %v", result.Warnings)
+	}
+
+	unset()
+	err = config.NewEnvLoader(
+		config.Deprecate("DEP_OLD_URL", "DEP_NEW_URL", "v2.0"),
+		config.WithStrictDeprecations(time.Now().Add(-time.Hour)),
+	).LoadEnv()
+	var validationErr *config.ValidationError
+	if !errors.As(err, &validationErr) || !strings.Contains(err.Error(), "DEP_OLD_URL") {
+		t.Errorf("Esperado um *ValidationError para DEP_OLD_URL, obtido %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestDiffSnapshots verifica se Diff relata as variáveis adicionadas, removidas e alteradas entre duas visões,
+mascarando os valores dos segredos.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestDiffSnapshots(t *testing.T) {
+	loader := config.NewMapLoader("staging", map[string]string{"HOST": "db", "PORT": "5432", "DEBUG": "true"})
+	loader.SetSecret("DB_PASSWORD", "old-secret")
+	before := loader.Snapshot()
+
+	loader.Set("PORT", "6432")
+	loader.Unset("DEBUG")
+	loader.Set("REPLICA", "db-2")
+	loader.SetSecret("DB_PASSWORD", "new-secret")
+
+	diff := config.Diff(before, loader.Snapshot())
+
+	if got := diff.Added(); !reflect.DeepEqual(got, []string{"REPLICA"}) {
+		t.Errorf("Adicionadas: esperado [REPLICA], obtido %v", got)
+	}
+	if got := diff.Removed(); !reflect.DeepEqual(got, []string{"DEBUG"}) {
+		t.Errorf("Removidas: esperado [DEBUG], obtido %v", got)
+	}
+	if got := diff.Changed(); !reflect.DeepEqual(got, []string{"DB_PASSWORD", "PORT"}) {
+		t.Errorf("Alteradas: esperado [DB_PASSWORD PORT], obtido %v", got)
+	}
+
+	out := diff.String()
+	if strings.Contains(out, "secret") {
+		t.Errorf("O valor do segredo não deveria aparecer no diff:\n%s", out)
+	}
+	if !strings.Contains(out, "~ PORT: 5432 -> 6432") {
+		t.Errorf("Esperada a alteração de PORT no diff:\n%s", out)
+	}
+
+	if !config.Diff(before, before).Empty() {
+		t.Errorf("Esperado um diff vazio entre visões idênticas")
+	}
+}
@@ -0,0 +1,56 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+func TestDiagnoseReportsProblems(t *testing.T) {
+	dir := setupEnvDir(t, "development", "DOC_PRESENT=1\n")
+	if err := os.WriteFile(path.Join(dir, ".env.production"), []byte("DOC BROKEN\n"), 0600); err != nil {
+		t.Fatalf("Não foi possível criar o arquivo .env: %v", err)
+	}
+
+	d := config.Diagnose(config.WithRequired("DOC_PRESENT", "DOC_MISSING"))
+	if d.OK() {
+		t.Fatal("Esperava que o diagnóstico relatasse problemas")
+	}
+
+	if len(d.Candidates) != 2 {
+		t.Fatalf("Esperava 2 candidatos, obteve %d", len(d.Candidates))
+	}
+	for _, c := range d.Candidates {
+		if want := strings.HasSuffix(c.Path, ".env.development"); c.Selected != want {
+			t.Errorf("Candidato %s: esperava Selected=%v", c.Path, want)
+		}
+	}
+
+	problems := strings.Join(d.Problems, "\n")
+	for _, want := range []string{"DOC_MISSING", "sintaxe inválida", ".env.production:1"} {
+		if !strings.Contains(problems, want) {
+			t.Errorf("Esperava problema contendo %q, obteve:\n%s", want, problems)
+		}
+	}
+	if strings.Contains(problems, "DOC_PRESENT") {
+		t.Errorf("Não esperava problema para DOC_PRESENT, obteve:\n%s", problems)
+	}
+}
+
+func TestRequiredKeysFailLoad(t *testing.T) {
+	setupEnvDir(t, "development", "REQ_PRESENT=1\n")
+
+	err := config.NewEnvLoader(config.WithRequired("REQ_PRESENT", "REQ_MISSING")).LoadEnv()
+
+	var validationErr *config.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Esperava ValidationError, obteve %v", err)
+	}
+	if len(validationErr.Problems) != 1 || !strings.Contains(validationErr.Problems[0], "REQ_MISSING") {
+		t.Errorf("Problemas inesperados: %v", validationErr.Problems)
+	}
+}
@@ -0,0 +1,123 @@
+package test
+
+import (
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestEnsureLoadedRunsOncePerConfiguration verifica se chamadas concorrentes de EnsureLoaded com as mesmas opções
+reutilizam o mesmo carregador, e se opções diferentes produzem um carregamento próprio.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestEnsureLoadedRunsOncePerConfiguration(t *testing.T) {
+	setupEnvDir(t, "ensure", "ENSURE_VAR=1")
+
+	loaders := make([]config.IEnvLoader, 8)
+	var wg sync.WaitGroup
+	for i := range loaders {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			loader, err := config.EnsureLoaded(config.WithSecretKeys("ENSURE_*"))
+			if err != nil {
+				t.Errorf("Erro ao carregar variáveis de ambiente: %s", err)
+			}
+			loaders[i] = loader
+		}(i)
+	}
+	wg.Wait()
+
+	for _, loader := range loaders[1:] {
+		if loader != loaders[0] {
+			t.Fatalf("Esperado o mesmo carregador para as mesmas opções")
+		}
+	}
+
+	other, err := config.EnsureLoaded(config.WithSecretKeys("OTHER_*"))
+	if err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if other == loaders[0] {
+		t.Errorf("Esperado um carregador diferente para opções diferentes")
+	}
+}
+
+/*
+TestEnsureLoadedWithPointerOptions verifica se as opções que criam estruturas internas a cada chamada, como o cache,
+o modo offline, a rotação e o sistema de arquivos, produzem o mesmo carregador quando repetidas com os mesmos
+valores, e um carregador diferente quando o caminho ou a chave do cache mudam.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestEnsureLoadedWithPointerOptions(t *testing.T) {
+	dir := setupEnvDir(t, "ensureptr", "ENSURE_PTR=1")
+	key := []byte("0123456789abcdef")
+	options := func(cache string, key []byte) []config.Option {
+		return []config.Option{
+			config.WithSilent(),
+			config.WithSnapshotCache(path.Join(dir, cache), key),
+			config.WithOfflineFallback(path.Join(dir, cache), key, time.Hour),
+			config.WithRotationDebounce(time.Second),
+			config.WithFS(os.DirFS(dir), "."),
+		}
+	}
+
+	first, err := config.EnsureLoaded(options("cache.bin", key)...)
+	if err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	again, err := config.EnsureLoaded(options("cache.bin", append([]byte(nil), key...))...)
+	if err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if again != first {
+		t.Errorf("Esperado o mesmo carregador para as mesmas opções")
+	}
+
+	if other, _ := config.EnsureLoaded(options("other.bin", key)...); other == first {
+		t.Errorf("Esperado um carregador diferente para outro caminho do cache")
+	}
+	if other, _ := config.EnsureLoaded(options("cache.bin", []byte("fedcba9876543210"))...); other == first {
+		t.Errorf("Esperado um carregador diferente para outra chave do cache")
+	}
+}
+
+/*
+TestEnsureLoadedDistinguishesClosures verifica se handlers criados pela mesma expressão, mas com variáveis
+capturadas diferentes, produzem carregadores diferentes, e se a mesma função produz o mesmo carregador.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestEnsureLoadedDistinguishesClosures(t *testing.T) {
+	setupEnvDir(t, "ensurefn", "ENSURE_FN=1")
+
+	handler := func(received *[]config.Warning) config.Option {
+		return config.WithWarningHandler(func(w config.Warning) { *received = append(*received, w) })
+	}
+	var first, second []config.Warning
+	a, err := config.EnsureLoaded(config.WithSilent(), handler(&first))
+	if err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	b, err := config.EnsureLoaded(config.WithSilent(), handler(&second))
+	if err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if a == b {
+		t.Errorf("Esperados carregadores diferentes para handlers diferentes")
+	}
+
+	shared := func(config.Warning) {}
+	c, _ := config.EnsureLoaded(config.WithSilent(), config.WithWarningHandler(shared))
+	d, _ := config.EnsureLoaded(config.WithSilent(), config.WithWarningHandler(shared))
+	if c != d {
+		t.Errorf("Esperado o mesmo carregador para o mesmo handler")
+	}
+}
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestEnvChangeWarnsAndResolves verifica se uma mudança de APP_ENV entre NewEnvLoader e LoadEnv gera um aviso com a
+política padrão e se EnvChangeResolve adota o novo ambiente.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestEnvChangeWarnsAndResolves(t *testing.T) {
+	dir := setupEnvDir(t, "staging", "ENVCHANGE_NAME=staging\n")
+	if err := os.WriteFile(path.Join(dir, ".env.qa"), []byte("ENVCHANGE_NAME=qa\n"), 0644); err != nil {
+		t.Fatalf("Não foi possível criar o arquivo .env: %v", err)
+	}
+	t.Cleanup(func() { os.Unsetenv("ENVCHANGE_NAME") })
+
+	warned := config.NewEnvLoader(config.WithSilent())
+	resolved := config.NewEnvLoader(config.WithSilent(), config.WithEnvChangePolicy(config.EnvChangeResolve))
+	pinned := config.NewEnvLoader(config.WithSilent(), config.WithEnv("staging")).(*config.FileEnvLoader)
+	t.Setenv("APP_ENV", "qa")
+
+	result, err := warned.LoadEnvResult()
+	if err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if warned.GetEnv() != "staging" || len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], `"qa"`) {
+		t.Errorf("Esperava o ambiente staging com um aviso, obteve %s e %v", warned.GetEnv(), result.Warnings)
+	}
+	if current, changed := warned.(*config.FileEnvLoader).EnvChanged(); !changed || current != "qa" {
+		t.Errorf("Esperava EnvChanged = qa, true; obteve %s, %v", current, changed)
+	}
+	os.Unsetenv("ENVCHANGE_NAME")
+
+	if result, err = warned.LoadEnvResult(); err != nil || len(result.Warnings) != 0 {
+		t.Errorf("Esperava o aviso apenas uma vez, obteve %v (erro %v)", result.Warnings, err)
+	}
+	os.Unsetenv("ENVCHANGE_NAME")
+
+	if err := resolved.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if got := resolved.GetString("ENVCHANGE_NAME"); resolved.GetEnv() != "qa" || got != "qa" {
+		t.Errorf("Esperava o ambiente qa, obteve %s com ENVCHANGE_NAME=%s", resolved.GetEnv(), got)
+	}
+
+	if _, changed := pinned.EnvChanged(); changed {
+		t.Error("Um carregador com WithEnv não deveria acompanhar APP_ENV")
+	}
+}
@@ -0,0 +1,53 @@
+package test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestEnvDirLoadsFragmentsInOrder verifica se os fragmentos de WithEnvDir são aplicados em ordem alfabética depois
+do arquivo base e antes das sobreposições, e se um padrão sem arquivos é ignorado.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestEnvDirLoadsFragmentsInOrder(t *testing.T) {
+	dir := setupEnvDir(t, "fragments", "FRAG_HOST=base\nFRAG_PORT=80\nFRAG_USER=base\n")
+	t.Setenv("LOCENV_USER", "frag")
+	files := map[string]string{
+		"conf/env.d/20-cache.env":     "FRAG_PORT=6379\nFRAG_CACHE=1\n",
+		"conf/env.d/10-database.env":  "FRAG_PORT=5432\nFRAG_HOST=db\n",
+		"conf/env.d/README":           "FRAG_HOST=readme\n",
+		".env.fragments.frag":         "FRAG_USER=frag\n",
+		"conf/env.d/30-operator.env/": "",
+	}
+	for name, content := range files {
+		full := path.Join(dir, name)
+		if name[len(name)-1] == '/' {
+			os.MkdirAll(full, 0755)
+			continue
+		}
+		os.MkdirAll(path.Dir(full), 0755)
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatalf("Não foi possível criar %s: %v", name, err)
+		}
+	}
+
+	loader := config.NewEnvLoader(config.WithNoProcessEnv(), config.WithUserOverlays(), config.WithEnvDir("conf/env.d/*.env", "empty.d/*.env"))
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	want := map[string]string{"FRAG_HOST": "db", "FRAG_PORT": "6379", "FRAG_CACHE": "1", "FRAG_USER": "frag"}
+	for key, value := range want {
+		if got := loader.GetString(key); got != value {
+			t.Errorf("Esperado %s=%q, obtido %q", key, value, got)
+		}
+	}
+	if file, _ := loader.Source("FRAG_HOST"); path.Base(file) != "10-database.env" {
+		t.Errorf("Esperava FRAG_HOST vindo de 10-database.env, obteve %q", file)
+	}
+}
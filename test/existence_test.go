@@ -0,0 +1,57 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestExistingPathsValidation verifica se WithExistingDir e WithExistingFile reúnem os problemas de todos os
+caminhos em um único erro de validação, resolvendo os caminhos relativos em relação ao arquivo .env, e se
+GetExistingDir e GetExistingFile verificam os caminhos na leitura.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestExistingPathsValidation(t *testing.T) {
+	dir := setupEnvDir(t, "existence", "EXS_TEMPLATES=templates\nEXS_CERT=cert.pem\nEXS_LOGS=missing\nEXS_KEY=templates\n")
+	if err := os.Mkdir(filepath.Join(dir, "templates"), 0o755); err != nil {
+		t.Fatalf("Não foi possível criar o diretório: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cert.pem"), []byte("cert"), 0o600); err != nil {
+		t.Fatalf("Não foi possível criar o arquivo: %v", err)
+	}
+
+	opts := []config.Option{config.WithNoProcessEnv(), config.WithSilent(), config.WithExistingDir("EXS_TEMPLATES", "EXS_UNSET"), config.WithExistingFile("EXS_CERT")}
+	loader := config.NewEnvLoader(append(opts, config.WithExistingDir("EXS_LOGS"), config.WithExistingFile("EXS_KEY"))...)
+	err := loader.LoadEnv()
+	var validation *config.ValidationError
+	if !errors.As(err, &validation) || len(validation.Problems) != 2 {
+		t.Fatalf("Esperava dois problemas de caminho, obteve %v", err)
+	}
+	if !strings.Contains(validation.Problems[0], "EXS_LOGS") || !strings.Contains(validation.Problems[0], "não existe") {
+		t.Errorf("Problema inesperado para EXS_LOGS: %s", validation.Problems[0])
+	}
+	if !strings.Contains(validation.Problems[1], "EXS_KEY") || !strings.Contains(validation.Problems[1], "não é um arquivo regular") {
+		t.Errorf("Problema inesperado para EXS_KEY: %s", validation.Problems[1])
+	}
+
+	loader = config.NewEnvLoader(opts...)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	var reader config.Reader = loader
+	if got, err := reader.GetExistingDir("EXS_TEMPLATES"); err != nil || got != filepath.Join(dir, "templates") {
+		t.Errorf("Esperava o diretório de templates, obteve %q, %v", got, err)
+	}
+	if got, err := reader.GetExistingFile("EXS_CERT"); err != nil || got != filepath.Join(dir, "cert.pem") {
+		t.Errorf("Esperava o certificado, obteve %q, %v", got, err)
+	}
+	if _, err := reader.GetExistingFile("EXS_TEMPLATES"); err == nil {
+		t.Error("Esperava erro para um diretório lido com GetExistingFile")
+	}
+}
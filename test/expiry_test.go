@@ -0,0 +1,53 @@
+package test
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestExpiredDeclarations verifica se as declarações vencidas geram avisos e, com WithRefuseExpired, deixam de ser
+aplicadas, fazendo valer o valor da camada anterior.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestExpiredDeclarations(t *testing.T) {
+	future := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+	dir := setupEnvDir(t, "test", "EXP_LEVEL=info\nEXP_TRACE=off\n")
+	err := os.WriteFile(path.Join(dir, ".env.test.expuser"), []byte("# expires: 2020-01-01\nEXP_LEVEL=debug\n\n# expires: "+future+"\nEXP_TRACE=on\n"), 0644)
+	if err != nil {
+		t.Fatalf("Não foi possível criar a sobreposição: %v", err)
+	}
+	t.Setenv("LOCENV_USER", "expuser")
+	unset := func() {
+		os.Unsetenv("EXP_LEVEL")
+		os.Unsetenv("EXP_TRACE")
+	}
+	t.Cleanup(unset)
+
+	loader := config.NewEnvLoader(config.WithUserOverlays())
+	result, err := loader.LoadEnvResult()
+	if err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+	if loader.GetString("EXP_LEVEL") != "debug" || loader.GetString("EXP_TRACE") != "on" {
+		t.Errorf("Sem WithRefuseExpired, as declarações deveriam ser aplicadas: %v", loader.All())
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], ".env.test.expuser:2") {
+		t.Errorf("Esperado um aviso para EXP_LEVEL, obtido %v", result.Warnings)
+	}
+
+	unset()
+	loader = config.NewEnvLoader(config.WithUserOverlays(), config.WithRefuseExpired())
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+	if loader.GetString("EXP_LEVEL") != "info" || loader.GetString("EXP_TRACE") != "on" {
+		t.Errorf("A declaração vencida deveria ser ignorada: %v", loader.All())
+	}
+}
@@ -0,0 +1,125 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/go-locEnv/config"
+	"github.com/jonh-dev/go-locEnv/locenvtest"
+)
+
+var (
+	_ config.Provider      = (*locenvtest.FakeVault)(nil)
+	_ config.Encrypter     = (*locenvtest.FakeVault)(nil)
+	_ config.HealthChecker = (*locenvtest.FakeVault)(nil)
+	_ config.SecretStore   = (*locenvtest.FakeVault)(nil)
+	_ config.Provider      = (*locenvtest.FakeSSM)(nil)
+	_ config.HealthChecker = (*locenvtest.FakeSSM)(nil)
+	_ config.SecretStore   = (*locenvtest.FakeSSM)(nil)
+)
+
+/*
+TestFakeSSMRotationsAndFailures verifica se as referências do FakeSSM são resolvidas no carregamento, se Rotate
+chega aos callbacks de WithRotationHook na recarga seguinte e se as falhas programadas chegam ao carregador.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestFakeSSMRotationsAndFailures(t *testing.T) {
+	ssm := locenvtest.NewFakeSSM()
+	ssm.PutParameter("/test/FAKE_DB_PASSWORD", "v1")
+	setupEnvDir(t, "test", "FAKE_DB_PASSWORD="+ssm.Reference("ssm", "/test/FAKE_DB_PASSWORD")+"\n")
+	t.Cleanup(func() { os.Unsetenv("FAKE_DB_PASSWORD") })
+
+	var events []locenvtest.RotationEvent
+	ssm.OnRotate(func(e locenvtest.RotationEvent) { events = append(events, e) })
+	rotations := make(chan config.SecretRotation, 1)
+	loader := config.NewEnvLoader(
+		config.WithSilent(),
+		config.WithDecrypter("ssm", ssm),
+		config.WithDecryptRetry(2, time.Millisecond),
+		config.WithSecretKeys("*_PASSWORD"),
+		config.WithRotationDebounce(0),
+		config.WithRotationHook("*_PASSWORD", func(r config.SecretRotation) { rotations <- r }),
+	)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	ssm.Rotate("/test/FAKE_DB_PASSWORD", "v2")
+	ssm.FailNext(1, nil)
+	if err := loader.Reload(); err != nil {
+		t.Fatalf("A nova tentativa deveria superar a falha programada: %s", err)
+	}
+	select {
+	case r := <-rotations:
+		if r.Old != "v1" || r.New != "v2" {
+			t.Errorf("Rotação inesperada: %+v", r)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("O callback de rotação não foi chamado")
+	}
+	if len(events) != 1 || events[0].Version != 2 || events[0].Old != "v1" || ssm.Calls() != 3 {
+		t.Errorf("Eventos ou chamadas inesperados: %+v, %d chamadas", events, ssm.Calls())
+	}
+
+	ssm.SetUnavailable(errors.New("throttling"))
+	var decryptErr *config.DecryptError
+	if err := loader.Reload(); !errors.As(err, &decryptErr) {
+		t.Errorf("Esperava um *DecryptError com o SSM indisponível, obteve %v", err)
+	}
+	if health := loader.Health(); len(health.Providers) != 1 || health.Providers[0].Reachable {
+		t.Errorf("O SSM deveria aparecer inacessível: %+v", health.Providers)
+	}
+}
+
+/*
+TestFakeVaultTransitAndStore verifica se os valores cifrados pelo FakeVault continuam decifrando depois de
+RotateKey e se o KV funciona como a loja de CompareWithStore e PushToStore.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestFakeVaultTransitAndStore(t *testing.T) {
+	vault := locenvtest.NewFakeVault()
+	before, err := config.EncryptValue("vault", vault, "s3cr3t")
+	if err != nil {
+		t.Fatalf("Erro ao cifrar o valor: %s", err)
+	}
+	vault.RotateKey()
+	after, err := config.EncryptValue("vault", vault, "n3w")
+	if err != nil {
+		t.Fatalf("Erro ao cifrar o valor: %s", err)
+	}
+	for value, want := range map[string]string{before: "s3cr3t", after: "n3w"} {
+		if got, err := config.DecryptValue(value, "vault", vault); err != nil || got != want {
+			t.Errorf("Esperava %q, obteve %q (%v)", want, got, err)
+		}
+	}
+	if events := vault.Events(); len(events) != 1 || events[0].Key != "transit" || events[0].Version != 2 {
+		t.Errorf("Eventos inesperados: %+v", events)
+	}
+
+	dir := t.TempDir()
+	file := path.Join(dir, ".env.staging")
+	if err := os.WriteFile(file, []byte("FAKE_API_KEY=local\nFAKE_REGION=us-east-1\n"), 0644); err != nil {
+		t.Fatalf("Não foi possível criar o arquivo .env: %v", err)
+	}
+	vault.Put("staging", "FAKE_API_KEY", "remote")
+
+	diff, err := config.CompareWithStore(file, vault, "staging", []string{"*_KEY"})
+	if err != nil || len(diff.Changes) != 2 {
+		t.Fatalf("Esperava duas diferenças, obteve %+v (%v)", diff.Changes, err)
+	}
+	vault.FailNext(1, nil)
+	if _, err := config.PushToStore(file, vault, "staging"); !errors.Is(err, locenvtest.ErrFakeUnavailable) {
+		t.Errorf("Esperava a falha programada, obteve %v", err)
+	}
+	if _, err := config.PushToStore(file, vault, "staging"); err != nil {
+		t.Fatalf("Erro ao enviar os valores: %s", err)
+	}
+	if values, _ := vault.Fetch("staging"); values["FAKE_API_KEY"] != "local" || values["FAKE_REGION"] != "us-east-1" {
+		t.Errorf("Valores inesperados no KV: %v", values)
+	}
+}
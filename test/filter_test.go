@@ -0,0 +1,45 @@
+package test
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestAllowAndDenyKeys verifica se apenas as variáveis permitidas e não bloqueadas são aplicadas, se a lista de
+bloqueio prevalece sobre a de permissão e se as variáveis descartadas aparecem em Result.Filtered sem alterar o
+ambiente do processo.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestAllowAndDenyKeys(t *testing.T) {
+	setupEnvDir(t, "filter", "APP_NAME=shared\nAPP_DEBUG_TOKEN=x\nAWS_ACCESS_KEY_ID=AKIAFILE\nOTHER_SERVICE_URL=http://other\n")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIAROLE")
+	t.Cleanup(func() { os.Unsetenv("APP_NAME") })
+
+	loader := config.NewEnvLoader(config.WithSilent(),
+		config.WithAllowKeys("APP_*", "AWS_*"),
+		config.WithDenyKeys("AWS_*", "*_TOKEN"),
+		config.WithRequired("AWS_ACCESS_KEY_ID"),
+	).(*config.FileEnvLoader)
+	result, err := loader.LoadEnvResult()
+	if err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	if want := []string{"APP_DEBUG_TOKEN", "AWS_ACCESS_KEY_ID", "OTHER_SERVICE_URL"}; !reflect.DeepEqual(result.Filtered, want) {
+		t.Errorf("Esperava as variáveis descartadas %v, obteve %v", want, result.Filtered)
+	}
+	if want := map[string]string{"APP_NAME": "shared"}; !reflect.DeepEqual(loader.All(), want) {
+		t.Errorf("Esperava apenas %v, obteve %v", want, loader.All())
+	}
+	if got := os.Getenv("AWS_ACCESS_KEY_ID"); got != "AKIAROLE" {
+		t.Errorf("Esperava que a credencial do processo fosse mantida, obteve %q", got)
+	}
+	if _, ok := os.LookupEnv("OTHER_SERVICE_URL"); ok {
+		t.Error("Não esperava OTHER_SERVICE_URL no ambiente do processo")
+	}
+}
@@ -0,0 +1,69 @@
+package test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestFormatNormalizesStyle verifica se Format normaliza o espaçamento, as aspas e o alinhamento dos comentários,
+preservando os comentários, o agrupamento por linhas em branco e as referências sem expandi-las.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestFormatNormalizesStyle(t *testing.T) {
+	input := "\n\n# Banco de dados\n  DB_HOST = localhost   # host local\nDB_PORT: '5432'\nexport   DB_URL=\"postgres://${DB_HOST}:${DB_PORT}\" # url\n\n\n\nGREETING=hello world\nNAME=\"plain\"\nMULTI=\"linha 1\nlinha 2\"   # várias linhas\n\n"
+	want := "# Banco de dados\nDB_HOST=localhost                                # host local\nDB_PORT=5432\nexport DB_URL=\"postgres://${DB_HOST}:${DB_PORT}\" # url\n\nGREETING=\"hello world\"\nNAME=plain\nMULTI=\"linha 1\nlinha 2\" # várias linhas\n"
+
+	got, err := config.Format([]byte(input))
+	if err != nil {
+		t.Fatalf("Erro ao formatar: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("Esperado:\n%s\nobtido:\n%s", want, got)
+	}
+
+	again, err := config.Format(got)
+	if err != nil || string(again) != string(got) {
+		t.Errorf("Esperava que a formatação fosse idempotente, obteve:\n%s (erro %v)", again, err)
+	}
+
+	if got, err := config.Format([]byte("ACCENT=voilà#1\n")); err != nil || string(got) != "ACCENT=voilà#1\n" {
+		t.Errorf("Esperava que o '#' depois de uma letra acentuada fosse preservado, obteve %q (erro %v)", got, err)
+	}
+
+	if _, err := config.Format([]byte("BROKEN LINE\n")); err == nil {
+		t.Error("Esperava erro de sintaxe")
+	}
+}
+
+/*
+TestFormatFileRewritesOnlyWhenChanged verifica se FormatFile só regrava o arquivo quando o estilo muda.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestFormatFileRewritesOnlyWhenChanged(t *testing.T) {
+	file := path.Join(t.TempDir(), ".env.development")
+	if err := os.WriteFile(file, []byte("A = 1\n"), 0600); err != nil {
+		t.Fatalf("Não foi possível criar o arquivo .env: %v", err)
+	}
+
+	changed, err := config.FormatFile(file)
+	if err != nil || !changed {
+		t.Fatalf("Esperava que o arquivo fosse formatado, obteve changed=%v, erro %v", changed, err)
+	}
+	content, _ := os.ReadFile(file)
+	if string(content) != "A=1\n" {
+		t.Errorf("Esperado %q, obtido %q", "A=1\n", content)
+	}
+	if info, _ := os.Stat(file); info.Mode().Perm() != 0600 {
+		t.Errorf("Esperava as permissões preservadas, obteve %v", info.Mode().Perm())
+	}
+
+	if changed, err := config.FormatFile(file); err != nil || changed {
+		t.Errorf("Não esperava alteração em um arquivo formatado, obteve changed=%v, erro %v", changed, err)
+	}
+}
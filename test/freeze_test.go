@@ -0,0 +1,76 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestFreeze verifica se um carregador congelado recusa novos carregamentos e se FrozenDrift e WatchFrozen detectam
+as alterações feitas no ambiente do processo depois do congelamento, mascarando os segredos.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestFreeze(t *testing.T) {
+	setupEnvDir(t, "test", "FREEZE_HOST=localhost\nFREEZE_PASSWORD=s3cr3t\n")
+	t.Cleanup(func() {
+		for _, key := range []string{"FREEZE_HOST", "FREEZE_PASSWORD", "FREEZE_EXTRA"} {
+			os.Unsetenv(key)
+		}
+	})
+
+	loader := config.NewEnvLoader(config.WithSecretKeys("*PASSWORD")).(*config.FileEnvLoader)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+	if _, err := loader.FrozenDrift(); err == nil {
+		t.Errorf("Esperado um erro de FrozenDrift antes do congelamento")
+	}
+
+	loader.Freeze()
+	if !loader.Frozen() {
+		t.Fatalf("O carregador deveria estar congelado")
+	}
+	if err := loader.Reload(); !errors.Is(err, config.ErrFrozen) {
+		t.Errorf("Esperado ErrFrozen de Reload, obtido %v", err)
+	}
+	if err := loader.LoadEnv(); !errors.Is(err, config.ErrFrozen) {
+		t.Errorf("Esperado ErrFrozen de LoadEnv, obtido %v", err)
+	}
+
+	if drift, err := loader.FrozenDrift(); err != nil || len(drift) != 0 {
+		t.Errorf("Nenhuma divergência esperada, obtido %v (%v)", drift, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	alerts := make(chan []config.KeyChange, 10)
+	if err := loader.WatchFrozen(ctx, 5*time.Millisecond, func(changes []config.KeyChange) { alerts <- changes }); err != nil {
+		t.Fatalf("Erro ao iniciar a verificação: %v", err)
+	}
+
+	os.Setenv("FREEZE_PASSWORD", "outro")
+	os.Setenv("FREEZE_EXTRA", "1")
+
+	var changes []config.KeyChange
+	for len(changes) < 2 {
+		select {
+		case changes = <-alerts:
+		case <-time.After(time.Second):
+			t.Fatalf("Divergências não informadas por WatchFrozen, obtidas %v", changes)
+		}
+	}
+	cancel()
+
+	if changes[0].Key != "FREEZE_EXTRA" || changes[0].Kind != config.DiffAdded {
+		t.Errorf("Divergência inesperada: %+v", changes[0])
+	}
+	if changes[1].Key != "FREEZE_PASSWORD" || !changes[1].Secret || changes[1].New == "outro" {
+		t.Errorf("O segredo deveria estar mascarado: %+v", changes[1])
+	}
+}
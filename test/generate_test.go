@@ -0,0 +1,105 @@
+package test
+
+import (
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestGenerateMarkers verifica se os marcadores @generate produzem valores do tipo e tamanho pedidos, iguais a
+cada carregamento com a mesma semente e diferentes com outra, e se um marcador inválido é um erro.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestGenerateMarkers(t *testing.T) {
+	setupEnvDir(t, "generate", "GEN_HEX=@generate(hex,16)\nGEN_B64=@generate(base64, 12)\nGEN_ALNUM=@generate(alnum,40)\nGEN_DEFAULT=@generate(hex)\nGEN_LITERAL='@generate(hex,8)'\n")
+
+	load := func(seed string) config.IEnvLoader {
+		t.Helper()
+		loader := config.NewEnvLoader(config.WithNoProcessEnv(), config.WithGenerateSeed([]byte(seed)))
+		if err := loader.LoadEnv(); err != nil {
+			t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+		}
+		return loader
+	}
+
+	first, again, other := load("semente-a"), load("semente-a"), load("semente-b")
+	patterns := map[string]string{
+		"GEN_HEX":     `^[0-9a-f]{32}$`,
+		"GEN_B64":     `^[A-Za-z0-9_-]{16}$`,
+		"GEN_ALNUM":   `^[A-Za-z0-9]{40}$`,
+		"GEN_DEFAULT": `^[0-9a-f]{64}$`,
+	}
+	for key, pattern := range patterns {
+		value := first.GetString(key)
+		if !regexp.MustCompile(pattern).MatchString(value) {
+			t.Errorf("Valor %s=%q não corresponde a %s", key, value, pattern)
+		}
+		if again.GetString(key) != value {
+			t.Errorf("Esperava %s igual com a mesma semente", key)
+		}
+		if other.GetString(key) == value {
+			t.Errorf("Esperava %s diferente com outra semente", key)
+		}
+	}
+	if got := first.GetString("GEN_LITERAL"); got != "@generate(hex,8)" {
+		t.Errorf("Esperava o marcador literal entre aspas simples, obteve %q", got)
+	}
+
+	setupEnvDir(t, "generate", "GEN_BAD=@generate(uuid,16)\n")
+	err := config.NewEnvLoader(config.WithSilent(), config.WithNoProcessEnv(), config.WithGenerateSeed([]byte("s"))).LoadEnv()
+	if err == nil || !strings.Contains(err.Error(), `.env.generate:1: marcador inválido em GEN_BAD: tipo "uuid" desconhecido`) {
+		t.Errorf("Esperava erro de marcador inválido, obteve %v", err)
+	}
+}
+
+/*
+TestPersistGeneratedValues verifica se a semente local é criada no diretório de configuração do usuário e se
+os valores gerados são gravados no arquivo de WithPersistGenerated, que prevalece nos carregamentos seguintes.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestPersistGeneratedValues(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	t.Setenv("HOME", configDir)
+	t.Setenv("LOCENV_USER", "dev")
+	dir := setupEnvDir(t, "persist", "GEN_SESSION=@generate(hex,8)\nGEN_PLAIN=1\n")
+
+	opts := []config.Option{config.WithNoProcessEnv(), config.WithUserOverlays(), config.WithPersistGenerated(".env.local.dev")}
+	loader := config.NewEnvLoader(opts...)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	session := loader.GetString("GEN_SESSION")
+
+	seedDir, _ := os.UserConfigDir()
+	if info, err := os.Stat(path.Join(seedDir, "locenv", "generate-seed")); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("Esperava a semente local com permissão 0600, obteve %v, %v", info, err)
+	}
+
+	local := path.Join(dir, ".env.local.dev")
+	content, err := os.ReadFile(local)
+	if err != nil || string(content) != "GEN_SESSION="+session+"\n" {
+		t.Fatalf("Esperava o valor gerado gravado em .env.local.dev, obteve %q, %v", content, err)
+	}
+
+	if err := os.WriteFile(local, []byte("GEN_SESSION=fixo\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := config.NewEnvLoader(opts...)
+	if err := reloaded.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if got := reloaded.GetString("GEN_SESSION"); got != "fixo" {
+		t.Errorf("Esperava o valor persistido, obteve %q", got)
+	}
+	if content, _ := os.ReadFile(local); string(content) != "GEN_SESSION=fixo\n" {
+		t.Errorf("Não esperava alterações no arquivo local, obteve %q", content)
+	}
+}
@@ -0,0 +1,59 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestTypedGetters verifica se os getters tipados convertem os valores carregados do arquivo .env
+e se retornam ErrKeyNotFound para variáveis inexistentes.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestTypedGetters(t *testing.T) {
+	setupEnvDir(t, "getters", "GETTERS_PORT=8080\nGETTERS_DEBUG=true\nGETTERS_TIMEOUT=2s\nGETTERS_HOSTS=a, b,,c\nGETTERS_BAD=x")
+
+	loader := config.NewEnvLoader()
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	if port, err := loader.GetInt("GETTERS_PORT"); err != nil || port != 8080 {
+		t.Errorf("Esperado %d, obtido %d (%v)", 8080, port, err)
+	}
+	if debug, err := loader.GetBool("GETTERS_DEBUG"); err != nil || !debug {
+		t.Errorf("Esperado true, obtido %v (%v)", debug, err)
+	}
+	if timeout, err := loader.GetDuration("GETTERS_TIMEOUT"); err != nil || timeout != 2*time.Second {
+		t.Errorf("Esperado %s, obtido %s (%v)", 2*time.Second, timeout, err)
+	}
+	if hosts := loader.GetStringSlice("GETTERS_HOSTS"); len(hosts) != 3 || hosts[1] != "b" {
+		t.Errorf("Esperado [a b c], obtido %v", hosts)
+	}
+	if _, err := loader.GetInt("GETTERS_BAD"); err == nil {
+		t.Errorf("Esperado erro de conversão, obtido nil")
+	}
+	if _, err := loader.GetInt("GETTERS_MISSING"); !errors.Is(err, config.ErrKeyNotFound) {
+		t.Errorf("Esperado ErrKeyNotFound, obtido %v", err)
+	}
+}
+
+/*
+TestGlobalLoad verifica se a API de conveniência do pacote carrega e lê variáveis pelo carregador padrão.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestGlobalLoad(t *testing.T) {
+	setupEnvDir(t, "global", "GLOBAL_NAME=locenv")
+
+	if err := config.Load(config.WithTrace()); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if got := config.GetString("GLOBAL_NAME"); got != "locenv" {
+		t.Errorf("Esperado %s, obtido %s", "locenv", got)
+	}
+}
@@ -0,0 +1,46 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestScanGitRisksFlagsUnignoredSecrets verifica se a função ScanGitRisks relata um arquivo .env com segredos
+em texto claro que não está coberto pelo .gitignore, e se deixa de relatá-lo depois que ele é ignorado.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestScanGitRisksFlagsUnignoredSecrets(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git não está disponível")
+	}
+
+	dir := setupEnvDir(t, "gitrisk", "GITRISK_DB_PASSWORD=hunter2\nGITRISK_API_TOKEN=enc:kms:AQ==\nGITRISK_HOST=db")
+	if err := exec.Command("git", "-C", dir, "init", "-q").Run(); err != nil {
+		t.Fatalf("Não foi possível criar o repositório git: %v", err)
+	}
+
+	risks, err := config.ScanGitRisks(dir, config.DefaultSecretPatterns)
+	if err != nil {
+		t.Fatalf("Erro ao verificar o repositório: %s", err)
+	}
+	if len(risks) != 1 || len(risks[0].Keys) != 1 || risks[0].Keys[0] != "GITRISK_DB_PASSWORD" {
+		t.Fatalf("Esperado um risco para GITRISK_DB_PASSWORD, obtido %v", risks)
+	}
+
+	if err := os.WriteFile(path.Join(dir, ".gitignore"), []byte(".env.*\n"), 0644); err != nil {
+		t.Fatalf("Não foi possível criar o .gitignore: %v", err)
+	}
+	risks, err = config.ScanGitRisks(dir, config.DefaultSecretPatterns)
+	if err != nil {
+		t.Fatalf("Erro ao verificar o repositório: %s", err)
+	}
+	if len(risks) != 0 {
+		t.Errorf("Esperado nenhum risco após ignorar o arquivo, obtido %v", risks)
+	}
+}
@@ -0,0 +1,95 @@
+package test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestKeyGroups verifica se WithKeyGroup aceita grupos completos ou ausentes e informa as variáveis definidas e
+ausentes dos grupos configurados parcialmente.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestKeyGroups(t *testing.T) {
+	setupEnvDir(t, "groups", "GRP_SMTP_HOST=mail\nGRP_SMTP_USER=app\nGRP_S3_BUCKET=files\nGRP_S3_REGION=\n")
+
+	opts := []config.Option{
+		config.WithNoProcessEnv(), config.WithSilent(),
+		config.WithKeyGroup("GRP_S3_BUCKET", "GRP_S3_REGION"),
+		config.WithKeyGroup("GRP_SENTRY_DSN", "GRP_SENTRY_ENV"),
+	}
+	if err := config.NewEnvLoader(opts...).LoadEnv(); err != nil {
+		t.Fatalf("Esperava grupos completos ou ausentes válidos, obteve %s", err)
+	}
+
+	err := config.NewEnvLoader(append(opts, config.WithKeyGroup("GRP_SMTP_HOST", "GRP_SMTP_USER", "GRP_SMTP_PASS"))...).LoadEnv()
+	var validation *config.ValidationError
+	if !errors.As(err, &validation) || len(validation.Problems) != 1 {
+		t.Fatalf("Esperava um problema de grupo, obteve %v", err)
+	}
+	want := "grupo GRP_SMTP_HOST, GRP_SMTP_USER, GRP_SMTP_PASS configurado parcialmente: definidas GRP_SMTP_HOST, GRP_SMTP_USER; ausentes GRP_SMTP_PASS"
+	if validation.Problems[0] != want {
+		t.Errorf("Problema inesperado: %s", validation.Problems[0])
+	}
+}
+
+/*
+TestMutuallyExclusive verifica se WithMutuallyExclusive aceita uma única alternativa definida e informa as
+variáveis e as declarações quando mais de uma é definida, inclusive com padrões.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestMutuallyExclusive(t *testing.T) {
+	dir := setupEnvDir(t, "exclusive", "MEX_DATABASE_URL=postgres://db/app\nMEX_DB_HOST=db\nMEX_DB_PORT=5432\nMEX_CACHE_URL=redis://cache\n")
+
+	opts := []config.Option{config.WithNoProcessEnv(), config.WithSilent(), config.WithMutuallyExclusive("MEX_CACHE_URL", "MEX_CACHE_HOST")}
+	if err := config.NewEnvLoader(opts...).LoadEnv(); err != nil {
+		t.Fatalf("Esperava uma única alternativa válida, obteve %s", err)
+	}
+
+	err := config.NewEnvLoader(append(opts, config.WithMutuallyExclusive("MEX_DATABASE_URL", "MEX_DB_*"))...).LoadEnv()
+	var validation *config.ValidationError
+	if !errors.As(err, &validation) || len(validation.Problems) != 1 {
+		t.Fatalf("Esperava um problema de alternativas, obteve %v", err)
+	}
+	file := filepath.Join(dir, ".env.exclusive")
+	want := "alternativas mutuamente exclusivas MEX_DATABASE_URL e MEX_DB_* definidas em conjunto: MEX_DATABASE_URL (" + file + ":1), MEX_DB_HOST (" + file + ":2), MEX_DB_PORT (" + file + ":3); defina apenas uma delas"
+	if validation.Problems[0] != want {
+		t.Errorf("Problema inesperado: %s", validation.Problems[0])
+	}
+}
+
+/*
+TestConditionalRequirements verifica se Require(...).If exige as variáveis apenas quando a condição é atendida,
+comparando os valores sem diferenciar maiúsculas de minúsculas, e se informa a condição no problema.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestConditionalRequirements(t *testing.T) {
+	setupEnvDir(t, "conditions", "CND_TLS_ENABLED=TRUE\nCND_TLS_CERT_FILE=cert.pem\nCND_CACHE=off\nCND_PROXY=http://proxy\n")
+
+	err := config.NewEnvLoader(
+		config.WithNoProcessEnv(), config.WithSilent(),
+		config.Require("CND_TLS_CERT_FILE", "CND_TLS_KEY_FILE").If("CND_TLS_ENABLED", "true", "1"),
+		config.Require("CND_CACHE_URL").If("CND_CACHE", "on"),
+		config.Require("CND_PROXY_USER").If("CND_PROXY"),
+		config.Require("CND_METRICS_PORT").If("CND_METRICS"),
+	).LoadEnv()
+	var validation *config.ValidationError
+	if !errors.As(err, &validation) || len(validation.Problems) != 2 {
+		t.Fatalf("Esperava dois problemas de exigências, obteve %v", err)
+	}
+	want := []string{
+		"variável obrigatória CND_TLS_KEY_FILE não definida (exigida porque CND_TLS_ENABLED=TRUE)",
+		"variável obrigatória CND_PROXY_USER não definida (exigida porque CND_PROXY definida)",
+	}
+	for i := range want {
+		if validation.Problems[i] != want[i] {
+			t.Errorf("Problema %d inesperado: %s", i, validation.Problems[i])
+		}
+	}
+}
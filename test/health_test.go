@@ -0,0 +1,70 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+// unreachableDecrypter é um Decrypter cujo provedor nunca responde à verificação de saúde.
+type unreachableDecrypter struct{ config.DecrypterFunc }
+
+func (unreachableDecrypter) CheckHealth(ctx context.Context) error {
+	return errors.New("timeout")
+}
+
+/*
+TestHealthReportsLoadState verifica se Health acompanha os carregamentos, a validação e os provedores,
+e se o HealthHandler responde com 503 enquanto nenhum carregamento teve sucesso.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestHealthReportsLoadState(t *testing.T) {
+	setupEnvDir(t, "health", "HEALTH_PORT=8080")
+
+	loader := config.NewEnvLoader(config.WithRequired("HEALTH_MISSING"), config.WithDecrypter("kms", unreachableDecrypter{}))
+	if err := loader.LoadEnv(); err == nil {
+		t.Fatal("Esperava um erro de validação")
+	}
+
+	h := loader.Health()
+	if h.Status != config.HealthFailing || len(h.ValidationProblems) != 1 || h.LastAttempt.IsZero() {
+		t.Errorf("Relatório inesperado após a falha: %+v", h)
+	}
+	if len(h.Providers) != 1 || h.Providers[0].Reachable {
+		t.Errorf("Esperava o provedor kms inacessível: %+v", h.Providers)
+	}
+
+	rec := httptest.NewRecorder()
+	config.HealthHandler(loader, 0).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Esperado %d, obtido %d", http.StatusServiceUnavailable, rec.Code)
+	}
+
+	t.Setenv("HEALTH_MISSING", "1")
+	if err := loader.Reload(); err != nil {
+		t.Fatalf("Erro ao recarregar variáveis de ambiente: %s", err)
+	}
+	os.Unsetenv("HEALTH_MISSING")
+
+	h = loader.Health()
+	if h.Status != config.HealthDegraded || h.LastSuccess.IsZero() || h.LastError != "" {
+		t.Errorf("Com um provedor inacessível, esperava o estado degradado: %+v", h)
+	}
+	if h.GRPCServingStatus(0) != config.GRPCServing {
+		t.Errorf("A configuração degradada ainda deveria atender")
+	}
+
+	rec = httptest.NewRecorder()
+	config.HealthHandler(loader, 0).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	var body config.Health
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || rec.Code != http.StatusOK || body.Status != config.HealthDegraded {
+		t.Errorf("Resposta inesperada: %d %+v (%v)", rec.Code, body, err)
+	}
+}
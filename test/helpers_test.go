@@ -0,0 +1,36 @@
+package test
+
+import (
+	"os"
+	"path"
+	"testing"
+)
+
+/*
+setupEnvDir cria um diretório temporário contendo um arquivo .env.<env> com o conteúdo informado,
+define APP_ENV para o ambiente informado e altera o diretório de trabalho para o diretório criado.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+@params env string - O ambiente do arquivo .env
+@params content string - O conteúdo do arquivo .env
+
+@return string - O caminho do diretório criado
+*/
+func setupEnvDir(t *testing.T, env string, content string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	err := os.WriteFile(path.Join(dir, ".env."+env), []byte(content), 0644)
+	if err != nil {
+		t.Fatalf("Não foi possível criar o arquivo .env: %v", err)
+	}
+
+	t.Setenv("APP_ENV", env)
+
+	err = os.Chdir(dir)
+	if err != nil {
+		t.Fatalf("Não foi possível alterar o diretório de trabalho: %v", err)
+	}
+
+	return dir
+}
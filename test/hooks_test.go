@@ -0,0 +1,121 @@
+package test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestHooksTransformValues verifica se os hooks são chamados na ordem dos momentos, se as alterações de
+AfterParse e BeforeApply seguem adiante, com SourceHook como origem das variáveis adicionadas, e se AfterLoad
+recebe os valores efetivos.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestHooksTransformValues(t *testing.T) {
+	setupEnvDir(t, "hooks", "HK_NAME=  Local  \nHK_DEBUG_DUMP=1\nHK_TOKEN=rot13:frperg\n")
+
+	var stages []string
+	record := func(ctx *config.HookContext) {
+		stages = append(stages, ctx.Stage.String())
+	}
+	var audited map[string]string
+	loader := config.NewEnvLoader(
+		config.WithNoProcessEnv(), config.WithSilent(), config.WithRequired("HK_TOKEN"),
+		config.WithHook(config.BeforeLoad, func(ctx *config.HookContext) error {
+			record(ctx)
+			if len(ctx.Files) != 1 || ctx.Values != nil || ctx.Env != "hooks" {
+				t.Errorf("Estado inesperado em BeforeLoad: %+v", ctx)
+			}
+			return nil
+		}),
+		config.WithHook(config.AfterParse, func(ctx *config.HookContext) error {
+			record(ctx)
+			for key, value := range ctx.Values {
+				ctx.Values[key] = strings.TrimSpace(value)
+			}
+			if token, ok := strings.CutPrefix(ctx.Values["HK_TOKEN"], "rot13:"); ok {
+				ctx.Values["HK_TOKEN"] = strings.Map(rot13, token)
+			}
+			return nil
+		}),
+		config.WithHook(config.AfterParse, func(ctx *config.HookContext) error {
+			if ctx.Values["HK_NAME"] != "Local" {
+				t.Errorf("Esperava que o segundo hook recebesse o valor normalizado, obteve %q", ctx.Values["HK_NAME"])
+			}
+			delete(ctx.Values, "HK_DEBUG_DUMP")
+			return nil
+		}),
+		config.WithHook(config.BeforeApply, func(ctx *config.HookContext) error {
+			record(ctx)
+			ctx.Values["HK_REGION"] = "sa-east-1"
+			return nil
+		}),
+		config.WithHook(config.AfterLoad, func(ctx *config.HookContext) error {
+			record(ctx)
+			audited = make(map[string]string, len(ctx.Values))
+			for key, value := range ctx.Values {
+				audited[key] = value
+			}
+			ctx.Values["HK_NAME"] = "ignorado"
+			return nil
+		}),
+	)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	if want := []string{"BeforeLoad", "AfterParse", "BeforeApply", "AfterLoad"}; !reflect.DeepEqual(stages, want) {
+		t.Errorf("Esperava os momentos %v, obteve %v", want, stages)
+	}
+	want := map[string]string{"HK_NAME": "Local", "HK_TOKEN": "secret", "HK_REGION": "sa-east-1"}
+	if got := loader.(*config.FileEnvLoader).All(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Esperava %v, obteve %v", want, got)
+	}
+	if !reflect.DeepEqual(audited, want) {
+		t.Errorf("Esperava que AfterLoad recebesse %v, obteve %v", want, audited)
+	}
+	if source, _ := loader.(*config.FileEnvLoader).Source("HK_REGION"); source != config.SourceHook {
+		t.Errorf("Esperava a origem %q para a variável adicionada, obteve %q", config.SourceHook, source)
+	}
+}
+
+/*
+TestHookErrorStopsLoad verifica se o erro de um hook interrompe o carregamento e identifica o momento.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestHookErrorStopsLoad(t *testing.T) {
+	setupEnvDir(t, "hooks", "HK_NAME=local\n")
+
+	errDenied := errors.New("variável proibida")
+	loader := config.NewEnvLoader(config.WithNoProcessEnv(), config.WithSilent(),
+		config.WithHook(config.AfterParse, func(ctx *config.HookContext) error {
+			return errDenied
+		}),
+		config.WithHook(config.AfterLoad, func(ctx *config.HookContext) error {
+			t.Error("Não esperava AfterLoad depois de um erro")
+			return nil
+		}),
+	)
+	err := loader.LoadEnv()
+	if !errors.Is(err, errDenied) || !strings.Contains(err.Error(), "AfterParse") {
+		t.Errorf("Esperava o erro do hook AfterParse, obteve %v", err)
+	}
+}
+
+// rot13 é a cifra usada pelo hook de decifragem do teste.
+func rot13(r rune) rune {
+	switch {
+	case r >= 'a' && r <= 'z':
+		return 'a' + (r-'a'+13)%26
+	case r >= 'A' && r <= 'Z':
+		return 'A' + (r-'A'+13)%26
+	default:
+		return r
+	}
+}
@@ -0,0 +1,41 @@
+package test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestHTTPSettings verifica se GetHTTPSettings lê as variáveis com prefixo, mantém os padrões e aplica as
+configurações a um http.Server.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestHTTPSettings(t *testing.T) {
+	loader := config.NewMapLoader("test", map[string]string{
+		"ADMIN_HTTP_PORT":    "9090",
+		"ADMIN_IDLE_TIMEOUT": "2m",
+	})
+
+	s, err := config.GetHTTPSettings(loader, "ADMIN")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	srv := &http.Server{}
+	s.ApplyTo(srv)
+	if srv.Addr != ":9090" || srv.IdleTimeout != 2*time.Minute || srv.ReadTimeout != 15*time.Second || srv.MaxHeaderBytes != http.DefaultMaxHeaderBytes {
+		t.Errorf("Servidor configurado de forma inesperada: %+v", srv)
+	}
+
+	loader.Set("ADMIN_HTTP_PORT", "70000")
+	loader.Set("ADMIN_WRITE_TIMEOUT", "-1s")
+	_, err = config.GetHTTPSettings(loader, "ADMIN")
+	if err == nil || !strings.Contains(err.Error(), "ADMIN_HTTP_PORT") || !strings.Contains(err.Error(), "ADMIN_WRITE_TIMEOUT") {
+		t.Errorf("Esperados problemas de porta e de tempo de escrita, obtido %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+// featuresSchema é o esquema dos valores JSON usados nos testes de GetJSON.
+const featuresSchema = `{
+	"type": "object",
+	"required": ["plans"],
+	"additionalProperties": false,
+	"properties": {
+		"beta": {"type": "boolean"},
+		"plans": {
+			"type": "array",
+			"minItems": 1,
+			"items": {
+				"type": "object",
+				"required": ["name", "price"],
+				"properties": {
+					"name": {"type": "string", "pattern": "^[a-z]+$"},
+					"price": {"type": "number", "minimum": 0}
+				}
+			}
+		}
+	}
+}`
+
+/*
+TestJSONValues verifica se WithJSONSchema reúne os problemas dos valores JSON, com o caminho de cada um, em um
+único erro de validação, e se GetJSON converte o valor no destino.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestJSONValues(t *testing.T) {
+	setupEnvDir(t, "jsonvalue", strings.Join([]string{
+		`JSV_FEATURES='{"beta": true, "plans": [{"name": "pro", "price": 9.9}]}'`,
+		`JSV_INVALID='{"plans": [{"name": "Pro", "price": -1}], "extra": 1}'`,
+		`JSV_SYNTAX='{"plans": [}'`,
+	}, "\n")+"\n")
+
+	schema := []byte(featuresSchema)
+	valid := []config.Option{config.WithNoProcessEnv(), config.WithSilent(), config.WithJSONSchema("JSV_FEATURES", schema)}
+	err := config.NewEnvLoader(append(valid, config.WithJSONSchema("JSV_INVALID", schema), config.WithJSONSchema("JSV_SYNTAX", nil))...).LoadEnv()
+	var validation *config.ValidationError
+	if !errors.As(err, &validation) || len(validation.Problems) != 4 {
+		t.Fatalf("Esperava quatro problemas de JSON, obteve %v", err)
+	}
+	for i, want := range []string{"$.extra: propriedade não permitida", "$.plans[0].name: o texto não corresponde", "$.plans[0].price: o valor deve ser maior ou igual a 0", "JSV_SYNTAX: JSON inválido"} {
+		if !strings.Contains(validation.Problems[i], want) {
+			t.Errorf("Esperava %q no problema %d, obteve %s", want, i, validation.Problems[i])
+		}
+	}
+
+	loader := config.NewEnvLoader(valid...).(*config.FileEnvLoader)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	var features struct {
+		Beta  bool `json:"beta"`
+		Plans []struct {
+			Name  string  `json:"name"`
+			Price float64 `json:"price"`
+		} `json:"plans"`
+	}
+	var reader config.Reader = loader
+	if err := reader.GetJSON("JSV_FEATURES", &features); err != nil {
+		t.Fatalf("Erro ao ler JSV_FEATURES: %s", err)
+	}
+	if !features.Beta || len(features.Plans) != 1 || features.Plans[0].Name != "pro" || features.Plans[0].Price != 9.9 {
+		t.Errorf("Valor inesperado: %+v", features)
+	}
+
+	var generic map[string]any
+	if err := loader.GetJSON("JSV_SYNTAX", &generic); err == nil || !strings.Contains(err.Error(), ".env.jsonvalue:3") {
+		t.Errorf("Esperava erro com a linha do JSON inválido, obteve %v", err)
+	}
+	if err := loader.Snapshot().GetJSON("JSV_FEATURES", &generic); err != nil || generic["beta"] != true {
+		t.Errorf("Esperava o valor pela visão, obteve %v, %v", generic, err)
+	}
+}
@@ -0,0 +1,46 @@
+package test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestKeyAliases verifica se um nome lógico é resolvido pelos nomes físicos registrados com WithKeyAliases,
+na ordem de prioridade, inclusive para segredos.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestKeyAliases(t *testing.T) {
+	setupEnvDir(t, "test", "KA_DATABASE_URL=postgres://legacy\nKA_POSTGRES_URL=postgres://older\nKA_PG_PASSWORD=s3cret\n")
+	t.Cleanup(func() {
+		for _, key := range []string{"KA_DATABASE_URL", "KA_POSTGRES_URL", "KA_PG_PASSWORD"} {
+			os.Unsetenv(key)
+		}
+	})
+
+	loader := config.NewEnvLoader(config.WithSecretKeys("*PASSWORD"), config.WithKeyAliases(map[string][]string{
+		"db.url":      {"KA_DB_URL", "KA_DATABASE_URL", "KA_POSTGRES_URL"},
+		"db.password": {"KA_DB_PASSWORD", "KA_PG_PASSWORD"},
+	}))
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+
+	if got := loader.GetString("db.url"); got != "postgres://legacy" {
+		t.Errorf("Esperado o valor de KA_DATABASE_URL, obtido %q", got)
+	}
+	if got, ok := loader.GetSecret("db.password"); !ok || got != "s3cret" {
+		t.Errorf("Esperado o segredo de KA_PG_PASSWORD, obtido %q", got)
+	}
+	if _, ok := loader.Lookup("db.host"); ok {
+		t.Errorf("Não era esperado encontrar um nome lógico sem nomes alternativos")
+	}
+
+	t.Setenv("KA_DB_URL", "postgres://current")
+	if got := loader.GetString("db.url"); got != "postgres://current" {
+		t.Errorf("Esperado o valor do nome de maior prioridade, obtido %q", got)
+	}
+}
@@ -0,0 +1,59 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestLimitsRejectOversizedFiles verifica se os limites de tamanho do arquivo, de quantidade de variáveis e de
+tamanho dos valores interrompem o carregamento com ErrLimitExceeded, inclusive em uma expansão exponencial.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestLimitsRejectOversizedFiles(t *testing.T) {
+	var bomb strings.Builder
+	bomb.WriteString("LIM_0=xxxxxxxxxxxxxxxx\n")
+	for i := 1; i <= 64; i++ {
+		fmt.Fprintf(&bomb, "LIM_%d=${LIM_%d}${LIM_%d}\n", i, i-1, i-1)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		limits  config.Limits
+		want    string
+	}{
+		{"tamanho do arquivo", "LIM_A=1\nLIM_B=2\n", config.Limits{MaxFileSize: 10}, "tamanho máximo de 10 bytes"},
+		{"quantidade de variáveis", "LIM_A=1\nLIM_B=2\nLIM_A=3\nLIM_C=4\n", config.Limits{MaxKeys: 2}, "mais de 2 variáveis"},
+		{"tamanho do valor", "LIM_A='" + strings.Repeat("a", 33) + "'\n", config.Limits{MaxValueLength: 32}, ":1: o valor excede"},
+		{"expansão exponencial", bomb.String(), config.DefaultLimits, "o valor excede o tamanho máximo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupEnvDir(t, "limits", tt.content)
+			err := config.NewEnvLoader(config.WithSilent(), config.WithNoProcessEnv(), config.WithLimits(tt.limits)).LoadEnv()
+			if !errors.Is(err, config.ErrLimitExceeded) {
+				t.Fatalf("Esperava ErrLimitExceeded, obteve %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Esperava %q no erro, obteve %q", tt.want, err.Error())
+			}
+		})
+	}
+
+	setupEnvDir(t, "limits", "LIM_A=1\nLIM_B=2\n")
+	t.Cleanup(func() {
+		os.Unsetenv("LIM_A")
+		os.Unsetenv("LIM_B")
+	})
+	if err := config.NewEnvLoader(config.WithLimits(config.Limits{MaxKeys: 2, MaxFileSize: 16, MaxValueLength: 1})).LoadEnv(); err != nil {
+		t.Errorf("Não esperava erro dentro dos limites: %s", err)
+	}
+}
@@ -0,0 +1,64 @@
+package test
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestWithFilesLoadsExplicitList verifica se WithFiles carrega os arquivos na ordem informada, com os posteriores
+sobrescrevendo os anteriores, se os padrões são expandidos em ordem alfabética e se um padrão sem arquivos é um erro.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestWithFilesLoadsExplicitList(t *testing.T) {
+	dir := setupEnvDir(t, "ignored", "FILES_IGNORED=1\n")
+	if err := os.Mkdir(path.Join(dir, "overrides"), 0755); err != nil {
+		t.Fatalf("Não foi possível criar o diretório: %v", err)
+	}
+	files := map[string]string{
+		"base.env":               "FILES_HOST=base\nFILES_PORT=80\nFILES_MODE=base\n",
+		"overrides/.env.ci":      "FILES_PORT=8080\n",
+		"fragments/a.env":        "FILES_MODE=a\n",
+		"fragments/b.env":        "FILES_MODE=b\nFILES_EXTRA=1\n",
+		"fragments/ignored.conf": "FILES_MODE=conf\n",
+	}
+	for name, content := range files {
+		os.MkdirAll(path.Dir(path.Join(dir, name)), 0755)
+		if err := os.WriteFile(path.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Não foi possível criar %s: %v", name, err)
+		}
+	}
+	t.Cleanup(func() {
+		for _, key := range []string{"FILES_IGNORED", "FILES_HOST", "FILES_PORT", "FILES_MODE", "FILES_EXTRA"} {
+			os.Unsetenv(key)
+		}
+	})
+
+	loader := config.NewEnvLoader(config.WithNoProcessEnv(), config.WithFiles("./base.env", "fragments/*.env", "./overrides/.env.ci", "base.env"))
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	want := map[string]string{"FILES_HOST": "base", "FILES_PORT": "8080", "FILES_MODE": "b", "FILES_EXTRA": "1", "FILES_IGNORED": ""}
+	for key, value := range want {
+		if got := loader.GetString(key); got != value {
+			t.Errorf("Esperado %s=%q, obtido %q", key, value, got)
+		}
+	}
+	if file, _ := loader.Source("FILES_PORT"); file != "./overrides/.env.ci" {
+		t.Errorf("Esperava FILES_PORT vindo de ./overrides/.env.ci, obteve %q", file)
+	}
+
+	err := config.NewEnvLoader(config.WithNoProcessEnv(), config.WithFiles("base.env", "missing/*.env")).LoadEnv()
+	if err == nil || !strings.Contains(err.Error(), "missing/*.env") {
+		t.Errorf("Esperava erro para o padrão sem arquivos, obteve %v", err)
+	}
+	if err := config.NewEnvLoader(config.WithSilent(), config.WithFiles("nao-existe.env")).LoadEnv(); err == nil {
+		t.Error("Esperava erro para o arquivo inexistente")
+	}
+}
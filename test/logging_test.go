@@ -0,0 +1,69 @@
+package test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+captureLog redireciona o log padrão, usado pelo go-logger, para um buffer até o fim do teste
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+
+@return *bytes.Buffer - O buffer com as mensagens registradas
+*/
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	return &buf
+}
+
+/*
+TestLogLevelSuppressesMessages verifica se WithSilent impede qualquer mensagem, se LogError mantém apenas os
+erros e se LOCENV_LOG_LEVEL define o nível padrão.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestLogLevelSuppressesMessages(t *testing.T) {
+	setupEnvDir(t, "logging", "LOG_OLD=1\n")
+	t.Cleanup(func() {
+		os.Unsetenv("LOG_OLD")
+		os.Unsetenv("LOG_NEW")
+	})
+
+	buf := captureLog(t)
+	loader := config.NewEnvLoader(config.WithSilent(), config.Deprecate("LOG_OLD", "LOG_NEW", "v2"), config.WithRequired("LOG_MISSING"))
+	if err := loader.LoadEnv(); err == nil {
+		t.Fatal("Esperava erro de validação")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Não esperava mensagens no modo silencioso, obteve:\n%s", buf.String())
+	}
+
+	loader = config.NewEnvLoader(config.WithLogLevel(config.LogError), config.Deprecate("LOG_OLD", "LOG_NEW", "v2"), config.WithRequired("LOG_MISSING"))
+	if err := loader.LoadEnv(); err == nil {
+		t.Fatal("Esperava erro de validação")
+	}
+	if out := buf.String(); strings.Contains(out, "WARNING") || !strings.Contains(out, "ERROR") {
+		t.Errorf("Esperava apenas erros no log, obteve:\n%s", out)
+	}
+
+	buf.Reset()
+	t.Setenv("LOCENV_LOG_LEVEL", "silent")
+	loader = config.NewEnvLoader(config.Deprecate("LOG_OLD", "LOG_NEW", "v2"))
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Não esperava mensagens com LOCENV_LOG_LEVEL=silent, obteve:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,39 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestMapLoaderServesValuesFromMemory verifica se o MapLoader atende à interface IEnvLoader com variáveis
+em memória, sem consultar o ambiente do processo.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestMapLoaderServesValuesFromMemory(t *testing.T) {
+	t.Setenv("MAPLOADER_PROCESS", "process")
+
+	var loader config.IEnvLoader = config.NewMapLoader("test", map[string]string{"MAPLOADER_PORT": "9000"})
+	mock := loader.(*config.MapLoader)
+	mock.SetSecret("MAPLOADER_TOKEN", "abc")
+
+	if port, err := loader.GetInt("MAPLOADER_PORT"); err != nil || port != 9000 {
+		t.Errorf("Esperado %d, obtido %d (%v)", 9000, port, err)
+	}
+	if !loader.IsTest() {
+		t.Errorf("Esperado ambiente de teste, obtido %s", loader.GetEnv())
+	}
+	if _, ok := loader.Lookup("MAPLOADER_PROCESS"); ok {
+		t.Errorf("O MapLoader não deveria consultar o ambiente do processo")
+	}
+	if token, ok := loader.GetSecret("MAPLOADER_TOKEN"); !ok || token != "abc" {
+		t.Errorf("Esperado %s, obtido %s", "abc", token)
+	}
+
+	mock.Unset("MAPLOADER_PORT")
+	if _, ok := loader.Lookup("MAPLOADER_PORT"); ok {
+		t.Errorf("MAPLOADER_PORT deveria ter sido removida")
+	}
+}
@@ -0,0 +1,46 @@
+package test
+
+import (
+	"os"
+	"path"
+	"runtime"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestMergeStrategies verifica se as sobreposições estendem os valores com WithMergeStrategy e com a anotação
+"# merge:", que prevalece sobre a opção, e se as combinações não são conflitos no modo estrito.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestMergeStrategies(t *testing.T) {
+	dir := setupEnvDir(t, "merge", "MRG_PATH=/usr/bin\nMRG_FEATURES=a\nMRG_LIBS=/usr/lib\nMRG_MODE=base\nMRG_NEW=\n")
+	overlay := "# merge: prepend\nMRG_PATH=/opt/bin\nMRG_FEATURES=b\n# merge: replace\nMRG_LIBS=/opt/lib\n# merge: append ;\nMRG_MODE=local\nMRG_NEW=x\n"
+	if err := os.WriteFile(path.Join(dir, ".env.merge."+runtime.GOOS), []byte(overlay), 0o644); err != nil {
+		t.Fatalf("Não foi possível criar a sobreposição: %v", err)
+	}
+
+	loader := config.NewEnvLoader(config.WithNoProcessEnv(), config.WithSilent(), config.WithPlatformOverlays(), config.WithStrictCascade("MRG_NEW", "MRG_LIBS"),
+		config.WithMergeStrategy(config.MergeAppend, "", "MRG_PATH", "MRG_LIBS"),
+		config.WithMergeStrategy(config.MergeAppend, ",", "MRG_FEATURES"),
+	)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	sep := string(os.PathListSeparator)
+	expected := map[string]string{
+		"MRG_PATH":     "/opt/bin" + sep + "/usr/bin",
+		"MRG_FEATURES": "a,b",
+		"MRG_LIBS":     "/opt/lib",
+		"MRG_MODE":     "base;local",
+		"MRG_NEW":      "x",
+	}
+	for key, want := range expected {
+		if got := loader.GetString(key); got != want {
+			t.Errorf("Esperava %s=%q, obteve %q", key, want, got)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestSubStructuredAccess verifica se as variáveis achatadas podem ser lidas de forma estruturada, por nomes com
+pontos e por seções obtidas com Sub.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestSubStructuredAccess(t *testing.T) {
+	loader := config.NewMapLoader("test", map[string]string{
+		"DATABASE_HOST":      "db",
+		"DATABASE_POOL_SIZE": "10",
+		"DATABASEX":          "fora da seção",
+	})
+	loader.SetSecret("DATABASE_PASSWORD", "s3cret")
+
+	if got := loader.GetString("database.host"); got != "db" {
+		t.Errorf("Esperado db, obtido %q", got)
+	}
+
+	db := loader.Sub("database")
+	if got := db.GetString("host"); got != "db" {
+		t.Errorf("Esperado db, obtido %q", got)
+	}
+	if size, err := db.Sub("pool").GetInt("size"); err != nil || size != 10 {
+		t.Errorf("Esperado 10, obtido %d (%v)", size, err)
+	}
+	if got, ok := db.GetSecret("password"); !ok || got != "s3cret" {
+		t.Errorf("Esperado o segredo da seção, obtido %q", got)
+	}
+	if len(db.All()) != 2 {
+		t.Errorf("Esperadas apenas as variáveis da seção, obtido %v", db.All())
+	}
+	if size, _ := loader.Sub("database.pool").GetInt("size"); size != 10 {
+		t.Errorf("Esperado 10 com a seção informada por pontos, obtido %d", size)
+	}
+}
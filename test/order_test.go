@@ -0,0 +1,88 @@
+package test
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestKeysFollowFileOrder verifica se Keys segue a ordem da primeira declaração, com as variáveis que só a
+sobreposição declara no fim, e se Dump produz um arquivo que, carregado de novo, mantém a mesma ordem.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestKeysFollowFileOrder(t *testing.T) {
+	dir := setupEnvDir(t, "ordered", "ORD_ZETA=1\nORD_ALPHA=2\nORD_API_TOKEN=segredo\nORD_MIDDLE=3\n")
+	t.Setenv("LOCENV_USER", "ordem")
+	if err := os.WriteFile(path.Join(dir, ".env.ordered.ordem"), []byte("ORD_BETA=4\nORD_ALPHA=5\n"), 0644); err != nil {
+		t.Fatalf("Não foi possível criar a sobreposição: %v", err)
+	}
+	t.Cleanup(func() {
+		for _, key := range []string{"ORD_ZETA", "ORD_ALPHA", "ORD_API_TOKEN", "ORD_MIDDLE", "ORD_BETA"} {
+			os.Unsetenv(key)
+		}
+	})
+
+	loader := config.NewEnvLoader(config.WithUserOverlays(), config.WithSecretKeys("*_TOKEN"))
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	want := []string{"ORD_ZETA", "ORD_ALPHA", "ORD_API_TOKEN", "ORD_MIDDLE", "ORD_BETA"}
+	if got := loader.Keys(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Esperado %v, obtido %v", want, got)
+	}
+	if got := loader.Snapshot().Keys(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Esperado %v na visão, obtido %v", want, got)
+	}
+
+	dump := loader.Dump()
+	wantDump := "ORD_ZETA=1\nORD_ALPHA=5\nORD_API_TOKEN=******\nORD_MIDDLE=3\nORD_BETA=4\n"
+	if dump != wantDump {
+		t.Errorf("Esperado:\n%s\nobtido:\n%s", wantDump, dump)
+	}
+
+	for _, key := range want {
+		os.Unsetenv(key)
+	}
+	setupEnvDir(t, "regenerated", dump)
+	reloaded := config.NewEnvLoader(config.WithSecretKeys("*_TOKEN"))
+	if err := reloaded.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o arquivo gerado: %s", err)
+	}
+	if got := reloaded.Dump(); got != dump {
+		t.Errorf("Esperava que o arquivo gerado mantivesse a ordem, obteve:\n%s", got)
+	}
+}
+
+/*
+TestKeysLexicalOrder verifica se WithKeyOrder(LexicalOrder) ordena as variáveis alfabeticamente e se o
+MapLoader mantém a ordem de definição.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestKeysLexicalOrder(t *testing.T) {
+	setupEnvDir(t, "lexical", "LEX_B=1\nLEX_A=2\n")
+	t.Cleanup(func() {
+		os.Unsetenv("LEX_A")
+		os.Unsetenv("LEX_B")
+	})
+
+	loader := config.NewEnvLoader(config.WithKeyOrder(config.LexicalOrder))
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if got, want := loader.Keys(), []string{"LEX_A", "LEX_B"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Esperado %v, obtido %v", want, got)
+	}
+
+	m := config.NewMapLoader("test", map[string]string{"M_B": "1", "M_A": "2"})
+	m.Set("M_0", "3")
+	if got, want := m.Keys(), []string{"M_A", "M_B", "M_0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Esperado %v, obtido %v", want, got)
+	}
+}
@@ -0,0 +1,35 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestResourceAttributesFromConfig verifica se ResourceAttributes mapeia as variáveis configuradas, usa o ambiente
+carregado como deployment.environment e nunca exporta segredos.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestResourceAttributesFromConfig(t *testing.T) {
+	loader := config.NewMapLoader("staging", map[string]string{"SERVICE_NAME": "checkout api", "REGION": "sa-east-1"})
+	loader.SetSecret("SERVICE_VERSION", "secret")
+
+	attrs := config.ResourceAttributes(loader, nil)
+
+	got := attrs.Map()
+	want := map[string]string{"service.name": "checkout api", "cloud.region": "sa-east-1", "deployment.environment": "staging"}
+	if len(got) != len(want) {
+		t.Fatalf("Esperado %v, obtido %v", want, got)
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("Atributo %s: esperado %q, obtido %q", key, value, got[key])
+		}
+	}
+
+	if env := attrs.ResourceEnv(); env != "cloud.region=sa-east-1,deployment.environment=staging,service.name=checkout%20api" {
+		t.Errorf("OTEL_RESOURCE_ATTRIBUTES inesperado: %s", env)
+	}
+}
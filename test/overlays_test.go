@@ -0,0 +1,49 @@
+package test
+
+import (
+	"os"
+	"path"
+	"runtime"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestPlatformOverlaysOverrideBase verifica se, com WithPlatformOverlays, as sobreposições .env.<env>.<GOOS> e
+.env.<env>.<GOOS>-<GOARCH> são aplicadas depois do arquivo base, nessa ordem, e se Source aponta para
+o arquivo que definiu cada variável.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestPlatformOverlaysOverrideBase(t *testing.T) {
+	dir := setupEnvDir(t, "overlay", "OVERLAY_A=base\nOVERLAY_B=base\nOVERLAY_C=base")
+
+	osFile := path.Join(dir, ".env.overlay."+runtime.GOOS)
+	archFile := path.Join(dir, ".env.overlay."+runtime.GOOS+"-"+runtime.GOARCH)
+	if err := os.WriteFile(osFile, []byte("OVERLAY_B=os\nOVERLAY_C=os"), 0644); err != nil {
+		t.Fatalf("Não foi possível criar a sobreposição: %v", err)
+	}
+	if err := os.WriteFile(archFile, []byte("OVERLAY_C=arch"), 0644); err != nil {
+		t.Fatalf("Não foi possível criar a sobreposição: %v", err)
+	}
+
+	loader := config.NewEnvLoader(config.WithPlatformOverlays())
+	result, err := loader.LoadEnvResult()
+	if err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	if len(result.Files) != 3 {
+		t.Errorf("Esperado 3 arquivos, obtido %v", result.Files)
+	}
+	expected := map[string]string{"OVERLAY_A": "base", "OVERLAY_B": "os", "OVERLAY_C": "arch"}
+	for key, want := range expected {
+		if got := os.Getenv(key); got != want {
+			t.Errorf("%s: esperado %s, obtido %s", key, want, got)
+		}
+	}
+	if source, _ := loader.Source("OVERLAY_C"); source != archFile {
+		t.Errorf("Esperado %s, obtido %s", archFile, source)
+	}
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestContextOverrides verifica se WithOverrides sobrescreve variáveis apenas para quem lê com FromContext, sem
+alterar o ambiente do processo, e se as sobreposições aninhadas prevalecem sobre as externas.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestContextOverrides(t *testing.T) {
+	setupEnvDir(t, "test", "CTXOV_FEATURE=false\nCTXOV_LIMIT=10\nCTXOV_TOKEN=abc\n")
+	t.Setenv("CTXOV_PROCESS", "processo")
+	t.Cleanup(func() {
+		for _, key := range []string{"CTXOV_FEATURE", "CTXOV_LIMIT", "CTXOV_TOKEN"} {
+			os.Unsetenv(key)
+		}
+	})
+
+	loader := config.NewEnvLoader(config.WithSecretKeys("*TOKEN"))
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+
+	base := config.ContextWithReader(context.Background(), loader)
+	if config.FromContext(base) != config.Reader(loader) {
+		t.Errorf("Sem sobreposições, FromContext deveria retornar a própria base")
+	}
+
+	ctx := config.WithOverrides(base, map[string]string{"CTXOV_FEATURE": "true", "CTXOV_LIMIT": "20"})
+	ctx = config.WithOverrides(ctx, map[string]string{"CTXOV_LIMIT": "30", "CTXOV_TOKEN": "xyz"})
+	r := config.FromContext(ctx)
+
+	if enabled, err := r.GetBool("CTXOV_FEATURE"); err != nil || !enabled {
+		t.Errorf("CTXOV_FEATURE deveria ser true, obtido %v (%v)", enabled, err)
+	}
+	if limit, _ := r.GetInt("CTXOV_LIMIT"); limit != 30 {
+		t.Errorf("A sobreposição interna deveria prevalecer, obtido %d", limit)
+	}
+	if token, ok := r.GetSecret("CTXOV_TOKEN"); !ok || token != "xyz" {
+		t.Errorf("O segredo sobrescrito deveria continuar sendo um segredo, obtido %q", token)
+	}
+	if source, _ := r.Source("CTXOV_LIMIT"); source != config.SourceContext {
+		t.Errorf("Origem inesperada: %q", source)
+	}
+	if got := r.GetString("CTXOV_PROCESS"); got != "processo" {
+		t.Errorf("As variáveis do processo deveriam continuar visíveis, obtido %q", got)
+	}
+	if r.Revision() == loader.Revision() {
+		t.Errorf("A revisão da configuração sobrescrita deveria ser diferente da base")
+	}
+
+	if os.Getenv("CTXOV_FEATURE") != "false" || loader.GetString("CTXOV_LIMIT") != "10" {
+		t.Errorf("As sobreposições não deveriam alterar o ambiente do processo nem o carregador")
+	}
+}
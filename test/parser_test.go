@@ -0,0 +1,163 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestParseSyntax verifica se a função Parse interpreta a sintaxe compatível com a biblioteca godotenv:
+export, aspas simples e duplas, escapes, valores multilinha, comentários e referências ${VAR}.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestParseSyntax(t *testing.T) {
+	content := strings.Join([]string{
+		"# comentário",
+		"export PLAIN=value # comentário no fim",
+		"SPACED = spaced value ",
+		"YAML: yaml",
+		"SINGLE='literal $PLAIN\\n'",
+		`DOUBLE="line\nbreak ${PLAIN} \$PLAIN"`,
+		`MULTI="first`,
+		`second"`,
+		"HASH=a#b",
+		"ACCENT=voilà#1",
+		"NBSP=valor\u00a0# comentário após espaço não separável",
+		"EMPTY=",
+	}, "\r\n")
+
+	values, err := config.Parse(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Erro ao interpretar o conteúdo: %s", err)
+	}
+
+	expected := map[string]string{
+		"PLAIN":  "value",
+		"SPACED": "spaced value",
+		"YAML":   "yaml",
+		"SINGLE": "literal $PLAIN\\n",
+		"DOUBLE": "line\nbreak value $PLAIN",
+		"MULTI":  "first\nsecond",
+		"HASH":   "a#b",
+		"ACCENT": "voilà#1",
+		"NBSP":   "valor",
+		"EMPTY":  "",
+	}
+	for key, want := range expected {
+		if got, ok := values[key]; !ok || got != want {
+			t.Errorf("%s: esperado %q, obtido %q", key, want, got)
+		}
+	}
+	if len(values) != len(expected) {
+		t.Errorf("Esperado %d variáveis, obtido %d", len(expected), len(values))
+	}
+}
+
+/*
+TestParseErrorLocations verifica se os erros de sintaxe informam a linha, a coluna e o trecho que os causou.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestParseErrorLocations(t *testing.T) {
+	tests := []struct {
+		content string
+		line    int
+		column  int
+		token   string
+	}{
+		{"A=1\nBAD KEY=1", 2, 5, "K"},
+		{"A=1\n\n  -X=1", 3, 3, "-"},
+		{"A=1\nNOSEP", 2, 6, "NOSEP"},
+		{"A=\"open\nB=2", 1, 3, "\""},
+		{"A='x' trailing", 1, 7, "t"},
+		{"=1", 1, 1, "="},
+	}
+
+	for _, tt := range tests {
+		_, err := config.Parse(strings.NewReader(tt.content))
+
+		var parseErr *config.ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%q: esperado *ParseError, obtido %v", tt.content, err)
+			continue
+		}
+		if parseErr.Line != tt.line || parseErr.Column != tt.column || parseErr.Token != tt.token {
+			t.Errorf("%q: esperado %d:%d %q, obtido %d:%d %q", tt.content, tt.line, tt.column, tt.token, parseErr.Line, parseErr.Column, parseErr.Token)
+		}
+	}
+}
+
+/*
+FuzzParse verifica que a função Parse nunca entra em pânico e que todo erro retornado é um *ParseError
+com uma posição válida.
+
+@params f *testing.F - Um ponteiro para o objeto de fuzzing
+*/
+func FuzzParse(f *testing.F) {
+	seeds := []string{
+		"A=1",
+		"export A='x'\nB=\"${A}\\n\"",
+		"# c\nA: b # c",
+		"A=\"unterminated",
+		"A B=1",
+		"\ufeffA=1\r\n",
+		"A=$\nB=${\nC=\\$",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, content string) {
+		values, err := config.Parse(strings.NewReader(content))
+		if err != nil {
+			var parseErr *config.ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Esperado *ParseError, obtido %T: %v", err, err)
+			}
+			if parseErr.Line < 1 || parseErr.Column < 1 {
+				t.Fatalf("Posição inválida no erro: %v", parseErr)
+			}
+			return
+		}
+
+		for key := range values {
+			if key == "" {
+				t.Fatalf("Chave vazia interpretada de %q", content)
+			}
+		}
+	})
+}
+
+/*
+TestParseStreamAbortsEarly verifica se a função ParseStream interrompe a interpretação no primeiro erro
+retornado pela função de validação e se rejeita linhas maiores que MaxLineSize.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestParseStreamAbortsEarly(t *testing.T) {
+	errInvalid := errors.New("valor inválido")
+	visited := 0
+
+	err := config.ParseStream(strings.NewReader("A=1\nB=bad\nC=3"), func(key string, value string, line int) error {
+		visited++
+		if value == "bad" {
+			return errInvalid
+		}
+		return nil
+	})
+	if !errors.Is(err, errInvalid) || visited != 2 {
+		t.Errorf("Esperado erro de validação após 2 variáveis, obtido %v após %d", err, visited)
+	}
+
+	long := "A=1\nB=" + strings.Repeat("x", config.MaxLineSize+1)
+	_, err = config.Parse(strings.NewReader(long))
+
+	var parseErr *config.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Line != 2 {
+		t.Errorf("Esperado *ParseError na linha 2, obtido %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestGetPathRelativeToEnvFile verifica se GetPath e a anotação @relative resolvem os caminhos em relação ao
+diretório do arquivo .env, e não ao diretório atual, se os valores do processo são mantidos e se as
+verificações de existência são opcionais.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestGetPathRelativeToEnvFile(t *testing.T) {
+	dir := setupEnvDir(t, "paths", "PTH_TEMPLATES_DIR=templates\n# @relative\nPTH_STATIC_DIR=static\nPTH_MISSING=missing/file.txt\nPTH_ABS=/etc\n")
+	t.Setenv("PTH_FROM_PROCESS", "relative/dir")
+	for _, sub := range []string{"templates", "cmd"} {
+		if err := os.Mkdir(filepath.Join(dir, sub), 0o755); err != nil {
+			t.Fatalf("Não foi possível criar o diretório: %v", err)
+		}
+	}
+	if err := os.Chdir(filepath.Join(dir, "cmd")); err != nil {
+		t.Fatalf("Não foi possível alterar o diretório de trabalho: %v", err)
+	}
+
+	loader := config.NewEnvLoader(config.WithNoProcessEnv(), config.WithSilent()).(*config.FileEnvLoader)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	if got, err := loader.GetPath("PTH_TEMPLATES_DIR", config.PathIsDir); err != nil || got != filepath.Join(dir, "templates") {
+		t.Errorf("Esperava o diretório de templates ao lado do arquivo .env, obteve %q, %v", got, err)
+	}
+	if got := loader.GetString("PTH_STATIC_DIR"); got != filepath.Join(dir, "static") {
+		t.Errorf("Esperava que @relative resolvesse o valor no carregamento, obteve %q", got)
+	}
+	if got, err := loader.GetPath("PTH_MISSING"); err != nil || got != filepath.Join(dir, "missing", "file.txt") {
+		t.Errorf("Esperava o caminho sem verificação de existência, obteve %q, %v", got, err)
+	}
+	if _, err := loader.GetPath("PTH_MISSING", config.PathExists); err == nil || !strings.Contains(err.Error(), "não existe") {
+		t.Errorf("Esperava erro para um caminho inexistente, obteve %v", err)
+	}
+	if _, err := loader.GetPath("PTH_ABS", config.PathIsFile); err == nil {
+		t.Error("Esperava erro para um diretório verificado com PathIsFile")
+	}
+	if _, err := loader.GetPath("PTH_UNDEFINED"); !errors.Is(err, config.ErrKeyNotFound) {
+		t.Errorf("Esperava ErrKeyNotFound, obteve %v", err)
+	}
+
+	processLoader := config.NewEnvLoader(config.WithSilent()).(*config.FileEnvLoader)
+	if err := processLoader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	t.Cleanup(func() {
+		for _, key := range []string{"PTH_TEMPLATES_DIR", "PTH_STATIC_DIR", "PTH_MISSING", "PTH_ABS"} {
+			os.Unsetenv(key)
+		}
+	})
+	if got, _ := processLoader.GetPath("PTH_FROM_PROCESS"); got != "relative/dir" {
+		t.Errorf("Esperava o valor do processo sem alteração, obteve %q", got)
+	}
+}
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestIsPlaceholder verifica os valores reconhecidos como texto de exemplo.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestIsPlaceholder(t *testing.T) {
+	tests := map[string]bool{
+		"<YOUR_KEY_HERE>":     true,
+		"<token>":             true,
+		"TODO":                true,
+		"todo: pedir ao time": true,
+		"changeme":            true,
+		"CHANGE_ME":           true,
+		"your_api_key_here":   true,
+		"XXXXXXXX":            true,
+		"todolist":            false,
+		"<a><b>":              false,
+		"localhost":           false,
+		"s3cr3t-v4lu3":        false,
+		"x":                   false,
+		"":                    false,
+	}
+
+	for value, want := range tests {
+		if got := config.IsPlaceholder(value); got != want {
+			t.Errorf("IsPlaceholder(%q): esperado %v, obtido %v", value, want, got)
+		}
+	}
+}
+
+/*
+TestPlaceholderCheckAndFill verifica se WithPlaceholderCheck lista os valores de exemplo com o arquivo e a linha,
+se FindPlaceholders os encontra na ordem dos arquivos e se os valores gravados com SetValues na sobreposição local
+resolvem o problema.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestPlaceholderCheckAndFill(t *testing.T) {
+	dir := setupEnvDir(t, "placeholder", "PH_HOST=localhost\nPH_API_KEY=<YOUR_KEY_HERE>\n# comentário\nPH_SECRET=changeme\n")
+	t.Setenv("LOCENV_USER", "dev")
+	t.Cleanup(func() {
+		for _, key := range []string{"PH_HOST", "PH_API_KEY", "PH_SECRET"} {
+			os.Unsetenv(key)
+		}
+	})
+
+	err := config.NewEnvLoader(config.WithSilent(), config.WithPlaceholderCheck(), config.WithUserOverlays()).LoadEnv()
+	var validation *config.ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("Esperava *ValidationError, obteve %v", err)
+	}
+	want := []string{
+		`variável PH_API_KEY (` + path.Join(dir, ".env.placeholder") + `:2) ainda tem o valor de exemplo "<YOUR_KEY_HERE>"`,
+		`variável PH_SECRET (` + path.Join(dir, ".env.placeholder") + `:4) ainda tem o valor de exemplo "changeme"`,
+	}
+	if !reflect.DeepEqual(validation.Problems, want) {
+		t.Errorf("Esperado %q, obtido %q", want, validation.Problems)
+	}
+
+	found, err := config.FindPlaceholders(config.WithUserOverlays())
+	if err != nil || len(found) != 2 || found[0].Key != "PH_API_KEY" || found[1].Line != 4 {
+		t.Fatalf("Resultado inesperado de FindPlaceholders: %v, %v", found, err)
+	}
+
+	local, err := config.NewEnvLoader().(*config.FileEnvLoader).LocalOverlayFile()
+	if err != nil || local != path.Join(dir, ".env.local.dev") {
+		t.Fatalf("Esperava %s, obteve %q, %v", path.Join(dir, ".env.local.dev"), local, err)
+	}
+	if err := os.WriteFile(local, []byte("# local\nPH_SECRET=voilà#antigo\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SetValues(local, map[string]string{"PH_SECRET": "s3nh4 real", "PH_API_KEY": "abc"}); err != nil {
+		t.Fatalf("Erro ao gravar os valores: %s", err)
+	}
+	content, _ := os.ReadFile(local)
+	if string(content) != "# local\nPH_SECRET=\"s3nh4 real\"\nPH_API_KEY=abc\n" {
+		t.Errorf("Conteúdo inesperado do arquivo local:\n%s", content)
+	}
+
+	loader := config.NewEnvLoader(config.WithPlaceholderCheck(), config.WithUserOverlays())
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if got := loader.GetString("PH_SECRET"); got != "s3nh4 real" {
+		t.Errorf("Esperava o valor local, obteve %q", got)
+	}
+}
@@ -0,0 +1,110 @@
+package test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestPlanReportsChangesWithoutApplying verifica se a função Plan retorna as alterações e os conflitos
+com o ambiente do processo sem aplicar nenhuma variável.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestPlanReportsChangesWithoutApplying(t *testing.T) {
+	setupEnvDir(t, "plan", "PLAN_NEW=1\nPLAN_URL=file\nPLAN_TOKEN=abc")
+	t.Setenv("PLAN_URL", "process")
+
+	loader := config.NewEnvLoader(config.WithSecretKeys("*_TOKEN"))
+	plan, err := loader.Plan()
+	if err != nil {
+		t.Fatalf("Erro ao planejar o carregamento: %s", err)
+	}
+
+	if _, exists := os.LookupEnv("PLAN_NEW"); exists {
+		t.Errorf("Plan não deveria aplicar variáveis ao ambiente do processo")
+	}
+
+	expected := map[string]config.ChangeAction{
+		"PLAN_NEW":   config.ActionSet,
+		"PLAN_TOKEN": config.ActionSet,
+		"PLAN_URL":   config.ActionConflict,
+	}
+	if len(plan.Changes) != len(expected) {
+		t.Fatalf("Esperado %d alterações, obtido %d", len(expected), len(plan.Changes))
+	}
+	for _, change := range plan.Changes {
+		if change.Action != expected[change.Key] {
+			t.Errorf("%s: esperado %s, obtido %s", change.Key, expected[change.Key], change.Action)
+		}
+		if change.Key == "PLAN_TOKEN" && change.Value == "abc" {
+			t.Errorf("O valor do segredo deveria estar mascarado")
+		}
+	}
+	if len(plan.Conflicts()) != 1 {
+		t.Errorf("Esperado 1 conflito, obtido %d", len(plan.Conflicts()))
+	}
+}
+
+/*
+TestPlanWritesNothing verifica se Plan não grava os valores de @generate, não pergunta as variáveis obrigatórias
+ausentes nem grava a sobreposição local, e se LoadEnv, com as mesmas opções, faz as duas coisas.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestPlanWritesNothing(t *testing.T) {
+	dir := setupEnvDir(t, "plandry", "PLAN_DRY_SESSION=@generate(hex,16)\n")
+	t.Cleanup(func() {
+		os.Unsetenv("PLAN_DRY_SESSION")
+		os.Unsetenv("PLAN_DRY_TOKEN")
+	})
+
+	prompts := 0
+	prompter := config.PromptFunc(func(key string, secret bool) (string, error) {
+		prompts++
+		return "answer", nil
+	})
+	newLoader := func(required ...string) config.IEnvLoader {
+		return config.NewEnvLoader(config.WithSilent(), config.WithGenerateSeed([]byte("plan-dry-seed")),
+			config.WithPersistGenerated(".env.generated"), config.WithRequired(required...), config.WithPrompt(prompter, true))
+	}
+	listing := func() []string {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatalf("Não foi possível ler o diretório: %v", err)
+		}
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		return names
+	}
+
+	plan, err := newLoader().Plan()
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if len(plan.Changes) != 1 || len(plan.Changes[0].Value) != 32 {
+		t.Errorf("Esperado o valor gerado no plano, obtido %+v", plan.Changes)
+	}
+
+	loader := newLoader("PLAN_DRY_TOKEN")
+	if _, err := loader.Plan(); err == nil {
+		t.Errorf("Esperado um erro de validação para PLAN_DRY_TOKEN não perguntada")
+	}
+	if names := listing(); prompts != 0 || len(names) != 1 {
+		t.Fatalf("Plan não deveria perguntar nem gravar arquivos, obtido %d pergunta(s) e %v", prompts, names)
+	}
+
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %v", err)
+	}
+	if names := listing(); prompts != 1 || len(names) != 3 {
+		t.Errorf("LoadEnv deveria perguntar e gravar os arquivos, obtido %d pergunta(s) e %v", prompts, names)
+	}
+	if _, err := loader.Plan(); err != nil || prompts != 1 {
+		t.Errorf("Plan deveria reutilizar a resposta já dada, obtido %v e %d pergunta(s)", err, prompts)
+	}
+}
@@ -0,0 +1,123 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+// reverseProvider é um Provider de teste que "decifra" invertendo os bytes do texto cifrado.
+type reverseProvider struct{}
+
+func (reverseProvider) Name() string { return "reverse" }
+
+func (reverseProvider) Decrypt(ciphertext []byte) ([]byte, error) {
+	if bytes.Equal(ciphertext, []byte("falha")) {
+		return nil, errors.New("valor recusado pelo provedor")
+	}
+	out := make([]byte, len(ciphertext))
+	for i, b := range ciphertext {
+		out[len(ciphertext)-1-i] = b
+	}
+	return out, nil
+}
+
+func (p reverseProvider) Encrypt(plaintext []byte) ([]byte, error) { return p.Decrypt(plaintext) }
+
+/*
+TestPluginHelperProcess não é um teste: é o plugin executado por TestPluginProvider, que reexecuta o binário de
+testes com LOCENV_TEST_PLUGIN=1, ou o plugin que nunca responde, com LOCENV_TEST_PLUGIN=hang.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestPluginHelperProcess(t *testing.T) {
+	switch os.Getenv("LOCENV_TEST_PLUGIN") {
+	case "1":
+	case "hang":
+		io.Copy(io.Discard, os.Stdin)
+		os.Exit(0)
+	default:
+		t.Skip("executado apenas como plugin")
+	}
+
+	if err := config.ServePlugin(reverseProvider{}, os.Stdin, os.Stdout); err != nil {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+/*
+TestPluginProvider verifica se um plugin executado como processo separado decifra os valores do backend
+registrado com WithPlugin, cifra valores, responde às verificações de saúde e relata os erros do provedor.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestPluginProvider(t *testing.T) {
+	t.Setenv("LOCENV_TEST_PLUGIN", "1")
+	command := []string{os.Args[0], "-test.run=^TestPluginHelperProcess$"}
+
+	plugin := config.NewPlugin(command...)
+	defer plugin.Close()
+
+	var provider config.Provider = plugin
+	if name := provider.Name(); name != "reverse" {
+		t.Fatalf("Handshake inesperado: %q", name)
+	}
+	encrypted, err := config.EncryptValue("rev", plugin, "s3cr3t")
+	if err != nil {
+		t.Fatalf("Erro ao cifrar com o plugin: %v", err)
+	}
+	if err := plugin.CheckHealth(context.Background()); err != nil {
+		t.Errorf("O plugin deveria estar saudável: %v", err)
+	}
+	if _, err := plugin.Decrypt([]byte("falha")); err == nil || !strings.Contains(err.Error(), "recusado") {
+		t.Errorf("Esperado o erro do provedor, obtido %v", err)
+	}
+
+	setupEnvDir(t, "test", "PLUGIN_PASSWORD="+encrypted+"\n")
+	t.Cleanup(func() { os.Unsetenv("PLUGIN_PASSWORD") })
+
+	loader := config.NewEnvLoader(config.WithPlugin("rev", command...))
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+	if got := loader.GetString("PLUGIN_PASSWORD"); got != "s3cr3t" {
+		t.Errorf("Valor decifrado inesperado: %q", got)
+	}
+	if health := loader.(*config.FileEnvLoader).Health(); len(health.Providers) != 1 || !health.Providers[0].Reachable {
+		t.Errorf("O plugin deveria aparecer como provedor acessível: %+v", health.Providers)
+	}
+}
+
+/*
+TestPluginTimeout verifica se um plugin que não responde ao handshake é encerrado pelo prazo do contexto de
+CheckHealth e pelo Timeout de Decrypt.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestPluginTimeout(t *testing.T) {
+	t.Setenv("LOCENV_TEST_PLUGIN", "hang")
+	plugin := config.NewPlugin(os.Args[0], "-test.run=^TestPluginHelperProcess$")
+	plugin.Timeout = 200 * time.Millisecond
+	defer plugin.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := plugin.CheckHealth(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Esperado o fim do prazo no handshake, obtido %v", err)
+	}
+	if _, err := plugin.Decrypt([]byte("x")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Esperado o fim do prazo em Decrypt, obtido %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("As chamadas deveriam terminar no prazo, levaram %s", elapsed)
+	}
+}
@@ -0,0 +1,63 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestPrecedenceResolvesProcessConflicts verifica as três precedências entre o processo e o arquivo .env
+e o relatório de conflitos.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestPrecedenceResolvesProcessConflicts(t *testing.T) {
+	setupEnvDir(t, "precedence", "PRECEDENCE_URL=file\nPRECEDENCE_PORT=8080")
+	t.Setenv("PRECEDENCE_URL", "process")
+
+	err := config.NewEnvLoader(config.WithPrecedence(config.ErrorOnConflict)).LoadEnv()
+	var conflictErr *config.ConflictError
+	if !errors.As(err, &conflictErr) || len(conflictErr.Conflicts) != 1 || conflictErr.Conflicts[0].Key != "PRECEDENCE_URL" {
+		t.Fatalf("Esperava ConflictError para PRECEDENCE_URL, obteve %v", err)
+	}
+	if _, exists := os.LookupEnv("PRECEDENCE_PORT"); exists {
+		t.Errorf("Nada deveria ser aplicado quando há conflitos com ErrorOnConflict")
+	}
+
+	loader := config.NewEnvLoader()
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if got := os.Getenv("PRECEDENCE_URL"); got != "process" {
+		t.Errorf("Com ProcessWins, esperado %s, obtido %s", "process", got)
+	}
+	report := loader.Conflicts()
+	if keys := report.Keys(); report.Precedence != config.ProcessWins || len(keys) != 1 || keys[0] != "PRECEDENCE_URL" {
+		t.Errorf("Relatório inesperado: %+v", report)
+	}
+
+	fileWins := config.NewEnvLoader(config.WithPrecedence(config.FileWins))
+	res, err := fileWins.LoadEnvResult()
+	if err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if got := os.Getenv("PRECEDENCE_URL"); got != "file" {
+		t.Errorf("Com FileWins, esperado %s, obtido %s", "file", got)
+	}
+	if len(res.Conflicts) != 1 || res.Conflicts[0].ProcessValue != "process" {
+		t.Errorf("Conflitos inesperados no resultado: %+v", res.Conflicts)
+	}
+
+	if err := os.WriteFile(".env.precedence", []byte("PRECEDENCE_PORT=8080"), 0644); err != nil {
+		t.Fatalf("Não foi possível reescrever o arquivo .env: %v", err)
+	}
+	if err := fileWins.Reload(); err != nil {
+		t.Fatalf("Erro ao recarregar variáveis de ambiente: %s", err)
+	}
+	if got := os.Getenv("PRECEDENCE_URL"); got != "process" {
+		t.Errorf("O Reload deveria restaurar o valor original do processo, obtido %s", got)
+	}
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestCheckStagedEnvFiles verifica se CheckStagedEnvFiles relata, no conteúdo preparado para o commit, os segredos
+em texto claro, o estilo fora do padrão e as variáveis ausentes do .env.example, ignorando alterações não preparadas.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestCheckStagedEnvFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git não está disponível")
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		".env.example":     "PC_HOST=\nPC_DB_PASSWORD=\n",
+		".env.development": "PC_HOST = db\nPC_DB_PASSWORD=hunter2\nPC_EXTRA=1\n",
+		".env.production":  "PC_HOST=db\nPC_DB_PASSWORD=enc:kms:AQ==\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(path.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Não foi possível criar o arquivo %s: %v", name, err)
+		}
+	}
+	for _, args := range [][]string{{"init", "-q"}, {"add", "."}} {
+		if err := exec.Command("git", append([]string{"-C", dir}, args...)...).Run(); err != nil {
+			t.Fatalf("Erro ao executar git %s: %v", args[0], err)
+		}
+	}
+	// A alteração não preparada não deve ser verificada.
+	if err := os.WriteFile(path.Join(dir, ".env.production"), []byte("BROKEN LINE\n"), 0644); err != nil {
+		t.Fatalf("Não foi possível alterar o arquivo: %v", err)
+	}
+
+	checked, problems, err := config.CheckStagedEnvFiles(dir, config.DefaultSecretPatterns, ".env.example")
+	if err != nil {
+		t.Fatalf("Erro ao verificar os arquivos preparados: %v", err)
+	}
+	if len(checked) != 3 {
+		t.Errorf("Esperava 3 arquivos verificados, obteve %v", checked)
+	}
+
+	report := strings.Join(problems, "\n")
+	for _, want := range []string{".env.development: fora do estilo canônico", ".env.development:2: segredo PC_DB_PASSWORD", ".env.development:3: variável PC_EXTRA ausente"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Esperava problema contendo %q, obteve:\n%s", want, report)
+		}
+	}
+	if len(problems) != 3 {
+		t.Errorf("Esperava 3 problemas, obteve:\n%s", report)
+	}
+}
@@ -0,0 +1,42 @@
+package test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestProfileAliasesResolveFile verifica se APP_ENV=prod encontra o arquivo .env.production pelos apelidos padrão,
+se apelidos configurados são aceitos e se a resolução é registrada no resultado.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestProfileAliasesResolveFile(t *testing.T) {
+	dir := setupEnvDir(t, "production", "ALIAS_VAR=production")
+	t.Setenv("APP_ENV", "prod")
+
+	loader := config.NewEnvLoader()
+	result, err := loader.LoadEnvResult()
+	if err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if result.Env != "production" || result.RequestedEnv != "prod" || loader.GetEnv() != "production" {
+		t.Errorf("Resolução inesperada: %+v", result)
+	}
+
+	err = os.WriteFile(path.Join(dir, ".env.live"), []byte("ALIAS_LIVE=1"), 0644)
+	if err != nil {
+		t.Fatalf("Não foi possível criar o arquivo .env: %v", err)
+	}
+	t.Setenv("APP_ENV", "prd")
+	plan, err := config.NewEnvLoader(config.WithProfileAliases(map[string][]string{"prd": {"live"}})).Plan()
+	if err != nil {
+		t.Fatalf("Erro ao planejar o carregamento: %s", err)
+	}
+	if plan.Env != "live" {
+		t.Errorf("Esperado %s, obtido %s", "live", plan.Env)
+	}
+}
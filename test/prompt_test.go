@@ -0,0 +1,80 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestPromptForMissingRequired verifica se WithPrompt pede apenas as variáveis obrigatórias ausentes, informando
+quais são segredos, se as respostas são gravadas na sobreposição local e se uma resposta vazia mantém o erro
+de validação.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestPromptForMissingRequired(t *testing.T) {
+	dir := setupEnvDir(t, "prompt", "PRM_HOST=localhost\n")
+	t.Setenv("LOCENV_USER", "dev")
+	t.Cleanup(func() {
+		for _, key := range []string{"PRM_HOST", "PRM_DB_PASSWORD", "PRM_REGION"} {
+			os.Unsetenv(key)
+		}
+	})
+
+	var asked []string
+	prompter := config.PromptFunc(func(key string, secret bool) (string, error) {
+		asked = append(asked, key)
+		if secret != (key == "PRM_DB_PASSWORD") {
+			t.Errorf("Classificação de segredo inesperada para %s: %v", key, secret)
+		}
+		return map[string]string{"PRM_DB_PASSWORD": "s3nh4", "PRM_REGION": "sa-east-1"}[key], nil
+	})
+
+	opts := []config.Option{config.WithRequired("PRM_HOST", "PRM_DB_PASSWORD", "PRM_REGION"), config.WithSecretKeys("*_PASSWORD"), config.WithUserOverlays()}
+	loader := config.NewEnvLoader(append(opts, config.WithPrompt(prompter, true))...)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if want := []string{"PRM_DB_PASSWORD", "PRM_REGION"}; !reflect.DeepEqual(asked, want) {
+		t.Errorf("Esperava perguntas para %v, obteve %v", want, asked)
+	}
+	if got := loader.GetString("PRM_REGION"); got != "sa-east-1" {
+		t.Errorf("Esperava a resposta em PRM_REGION, obteve %q", got)
+	}
+
+	local := path.Join(dir, ".env.local.dev")
+	info, err := os.Stat(local)
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("Esperava a sobreposição local com permissão 0600, obteve %v, %v", info, err)
+	}
+	if content, _ := os.ReadFile(local); string(content) != "PRM_DB_PASSWORD=s3nh4\nPRM_REGION=sa-east-1\n" {
+		t.Errorf("Conteúdo inesperado da sobreposição local:\n%s", content)
+	}
+
+	for _, key := range []string{"PRM_HOST", "PRM_DB_PASSWORD", "PRM_REGION"} {
+		os.Unsetenv(key)
+	}
+	asked = nil
+	if err := config.NewEnvLoader(append(opts, config.WithPrompt(prompter, false))...).LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if len(asked) != 0 {
+		t.Errorf("Não esperava perguntas com a sobreposição preenchida, obteve %v", asked)
+	}
+
+	os.Remove(local)
+	for _, key := range []string{"PRM_HOST", "PRM_DB_PASSWORD", "PRM_REGION"} {
+		os.Unsetenv(key)
+	}
+	empty := config.PromptFunc(func(string, bool) (string, error) { return "", nil })
+	err = config.NewEnvLoader(append(opts, config.WithSilent(), config.WithPrompt(empty, false))...).LoadEnv()
+	var validation *config.ValidationError
+	if !errors.As(err, &validation) || len(validation.Problems) != 2 {
+		t.Errorf("Esperava erro de validação com duas variáveis ausentes, obteve %v", err)
+	}
+}
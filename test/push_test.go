@@ -0,0 +1,121 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestPushListenerWebhook verifica se o webhook recarrega a configuração com notificações assinadas e recusa
+assinaturas inválidas, notificações repetidas e notificações fora da janela de tempo.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestPushListenerWebhook(t *testing.T) {
+	dir := setupEnvDir(t, "test", "PUSH_LEVEL=info\n")
+	t.Cleanup(func() { os.Unsetenv("PUSH_LEVEL") })
+
+	loader := config.NewEnvLoader()
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+	if err := os.WriteFile(path.Join(dir, ".env.test"), []byte("PUSH_LEVEL=debug\n"), 0644); err != nil {
+		t.Fatalf("Não foi possível reescrever o arquivo .env: %v", err)
+	}
+
+	secret := []byte("compartilhado")
+	listener := &config.PushListener{Loader: loader, Secret: secret}
+	send := func(n config.ChangeNotification) int {
+		body, _ := json.Marshal(n)
+		rec := httptest.NewRecorder()
+		listener.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/hooks/config", bytes.NewReader(body)))
+		return rec.Code
+	}
+
+	n := config.SignNotification(secret, config.ChangeNotification{ID: "n1", Timestamp: time.Now().Unix(), Reason: "vault"})
+	if code := send(n); code != http.StatusNoContent {
+		t.Fatalf("Esperado 204, obtido %d", code)
+	}
+	if got := loader.GetString("PUSH_LEVEL"); got != "debug" {
+		t.Errorf("A configuração deveria ter sido recarregada, obtido %q", got)
+	}
+
+	if code := send(n); code != http.StatusConflict {
+		t.Errorf("Esperado 409 para a notificação repetida, obtido %d", code)
+	}
+	forged := config.SignNotification([]byte("outro"), config.ChangeNotification{ID: "n2", Timestamp: time.Now().Unix()})
+	if code := send(forged); code != http.StatusUnauthorized {
+		t.Errorf("Esperado 401 para a assinatura inválida, obtido %d", code)
+	}
+	now := time.Now().Unix()
+	signed := config.SignNotification(secret, config.ChangeNotification{ID: fmt.Sprintf("n4.%d", now), Timestamp: now, Reason: "r"})
+	shifted := config.ChangeNotification{ID: "n4", Timestamp: now, Reason: fmt.Sprintf("%d.r", now), Signature: signed.Signature}
+	if code := send(shifted); code != http.StatusUnauthorized {
+		t.Errorf("Esperado 401 para a assinatura reaproveitada com os campos deslocados, obtido %d", code)
+	}
+	old := config.SignNotification(secret, config.ChangeNotification{ID: "n3", Timestamp: time.Now().Add(-time.Hour).Unix()})
+	if code := send(old); code != http.StatusConflict {
+		t.Errorf("Esperado 409 para a notificação antiga, obtido %d", code)
+	}
+}
+
+/*
+TestPushListenerSSE verifica se ListenSSE recarrega a configuração ao receber uma notificação assinada do fluxo SSE.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestPushListenerSSE(t *testing.T) {
+	setupEnvDir(t, "test", "PUSH_SSE=1\n")
+	t.Cleanup(func() { os.Unsetenv("PUSH_SSE") })
+
+	loader := config.NewEnvLoader()
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+
+	secret := []byte("compartilhado")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := json.Marshal(config.SignNotification(secret, config.ChangeNotification{ID: "sse-1", Timestamp: time.Now().Unix()}))
+		fmt.Fprintf(w, "event: ping\ndata: {}\n\nevent: %s\ndata: %s\n\n", config.ChangeEvent, body)
+	}))
+	defer server.Close()
+
+	reloads := make(chan string, 10)
+	listener := &config.PushListener{
+		Loader:     loader,
+		Secret:     secret,
+		RetryDelay: 10 * time.Millisecond,
+		OnReload:   func(n config.ChangeNotification, err error) { reloads <- n.ID },
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- listener.ListenSSE(ctx, server.URL) }()
+
+	select {
+	case id := <-reloads:
+		if id != "sse-1" {
+			t.Errorf("Notificação inesperada: %q", id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Nenhuma recarga após a notificação SSE")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Esperado context.Canceled ao encerrar, obtido %v", err)
+	}
+	if len(reloads) != 0 {
+		t.Errorf("A notificação repetida nas reconexões não deveria recarregar a configuração")
+	}
+}
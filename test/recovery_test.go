@@ -0,0 +1,107 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestLoadEnvRecoversProviderPanics verifica se um pânico em um Decrypter é recuperado e retornado por LoadEnv
+como um *PanicError, com a pilha de chamadas, em vez de encerrar o processo.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestLoadEnvRecoversProviderPanics(t *testing.T) {
+	setupEnvDir(t, "panic", "PNC_PASSWORD=enc:boom:AQID\n")
+
+	err := config.NewEnvLoader(
+		config.WithNoProcessEnv(), config.WithSilent(),
+		config.WithDecrypter("boom", config.DecrypterFunc(func([]byte) ([]byte, error) { panic("backend indisponível") })),
+	).LoadEnv()
+	var panicked *config.PanicError
+	if !errors.As(err, &panicked) {
+		t.Fatalf("Esperava um *PanicError, obteve %v", err)
+	}
+	if panicked.Value != "backend indisponível" || len(panicked.Stack) == 0 {
+		t.Errorf("Pânico inesperado: %v, pilha com %d bytes", panicked.Value, len(panicked.Stack))
+	}
+}
+
+/*
+TestWatchCallbacksRecoverPanics verifica se os pânicos nos callbacks de rotação e de um PushListener são
+entregues à função de WithErrorHandler e se as goroutines continuam funcionando depois deles.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestWatchCallbacksRecoverPanics(t *testing.T) {
+	dir := setupEnvDir(t, "recovery", "RCV_DB_PASSWORD=v1\n")
+	file := path.Join(dir, ".env.recovery")
+	t.Cleanup(func() { os.Unsetenv("RCV_DB_PASSWORD") })
+
+	errs := make(chan error, 4)
+	rotations := make(chan string, 4)
+	loader := config.NewEnvLoader(
+		config.WithSilent(),
+		config.WithSecretKeys("*_PASSWORD"),
+		config.WithRotationDebounce(10*time.Millisecond),
+		config.WithRotationHook("*_PASSWORD", func(r config.SecretRotation) {
+			if r.New == "v2" {
+				panic("pool indisponível")
+			}
+			rotations <- r.New
+		}),
+		config.WithErrorHandler(func(err error) { errs <- err }),
+	)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	rotate := func(value string) {
+		if err := os.WriteFile(file, []byte("RCV_DB_PASSWORD="+value+"\n"), 0644); err != nil {
+			t.Fatalf("Não foi possível atualizar o arquivo .env: %v", err)
+		}
+		if err := loader.Reload(); err != nil {
+			t.Fatalf("Erro ao recarregar variáveis de ambiente: %s", err)
+		}
+	}
+	expectPanic := func(value string) {
+		select {
+		case err := <-errs:
+			var panicked *config.PanicError
+			if !errors.As(err, &panicked) || panicked.Value != value {
+				t.Errorf("Esperava o pânico %q, obteve %v", value, err)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("O pânico %q não foi entregue à função de erros", value)
+		}
+	}
+
+	rotate("v2")
+	expectPanic("pool indisponível")
+	rotate("v3")
+	select {
+	case got := <-rotations:
+		if got != "v3" {
+			t.Errorf("Rotação inesperada: %s", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("O callback de rotação deveria continuar sendo chamado depois do pânico")
+	}
+
+	secret := []byte("compartilhado")
+	listener := &config.PushListener{
+		Loader:   loader,
+		Secret:   secret,
+		OnReload: func(config.ChangeNotification, error) { panic("callback quebrado") },
+	}
+	n := config.SignNotification(secret, config.ChangeNotification{ID: "r1", Timestamp: time.Now().Unix()})
+	if err := listener.Accept(n); err != nil {
+		t.Errorf("Esperava a recarga sem erros, obteve %v", err)
+	}
+	expectPanic("callback quebrado")
+}
@@ -0,0 +1,76 @@
+package test
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+// upperProvider é um Provider de teste registrado em init, como fazem os módulos de provedores.
+type upperProvider struct{ prefix string }
+
+func (upperProvider) Name() string { return "upper" }
+
+func (p upperProvider) Decrypt(ciphertext []byte) ([]byte, error) {
+	return []byte(p.prefix + strings.ToUpper(string(ciphertext))), nil
+}
+
+func init() {
+	config.RegisterProvider("test-upper", func(options map[string]string) (config.Provider, error) {
+		return upperProvider{prefix: options["prefix"]}, nil
+	})
+}
+
+/*
+TestProviderRegistry verifica se os provedores registrados em init são listados e usados por WithProvider, se os
+provedores embutidos estão registrados e se um provedor desconhecido resulta em um *DecryptError.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestProviderRegistry(t *testing.T) {
+	names := strings.Join(config.Providers(), ",")
+	for _, name := range []string{"command", "local", "test-upper"} {
+		if !strings.Contains(names, name) {
+			t.Errorf("O provedor %s deveria estar registrado: %s", name, names)
+		}
+	}
+
+	key := base64.StdEncoding.EncodeToString(make([]byte, 32))
+	local, err := config.OpenProvider("local", map[string]string{"key": key})
+	if err != nil || local.Name() != "local" {
+		t.Fatalf("Erro ao abrir o provedor local: %v", err)
+	}
+	encrypted, _ := config.EncryptValue("vault", local.(config.Encrypter), "s3cr3t")
+
+	setupEnvDir(t, "test", "REGISTRY_TOKEN=enc:up:"+base64.StdEncoding.EncodeToString([]byte("abc"))+"\nREGISTRY_PASSWORD="+encrypted+"\n")
+	t.Cleanup(func() {
+		os.Unsetenv("REGISTRY_TOKEN")
+		os.Unsetenv("REGISTRY_PASSWORD")
+	})
+
+	loader := config.NewEnvLoader(
+		config.WithProvider("up", "test-upper", map[string]string{"prefix": "x-"}),
+		config.WithProvider("vault", "local", map[string]string{"key": key}),
+	)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+	if got := loader.GetString("REGISTRY_TOKEN"); got != "x-ABC" {
+		t.Errorf("Valor inesperado de REGISTRY_TOKEN: %q", got)
+	}
+	if got := loader.GetString("REGISTRY_PASSWORD"); got != "s3cr3t" {
+		t.Errorf("Valor inesperado de REGISTRY_PASSWORD: %q", got)
+	}
+
+	os.Unsetenv("REGISTRY_TOKEN")
+	os.Unsetenv("REGISTRY_PASSWORD")
+	err = config.NewEnvLoader(config.WithProvider("up", "inexistente", nil), config.WithProvider("vault", "local", map[string]string{"key": key})).LoadEnv()
+	var decryptErr *config.DecryptError
+	if !errors.As(err, &decryptErr) || !strings.Contains(err.Error(), "não registrado") {
+		t.Errorf("Esperado um *DecryptError para o provedor desconhecido, obtido %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestReloadUpdatesOwnedVariables verifica se a função Reload atualiza as variáveis definidas pelo carregador,
+remove as que deixaram de existir no arquivo e mantém a origem informada por Source.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestReloadUpdatesOwnedVariables(t *testing.T) {
+	dir := setupEnvDir(t, "reload", "RELOAD_A=1\nRELOAD_B=1")
+	t.Setenv("RELOAD_P", "process")
+
+	var reader config.Reader
+	loader := config.NewEnvLoader()
+	reader = loader
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	err := os.WriteFile(path.Join(dir, ".env.reload"), []byte("RELOAD_A=2\nRELOAD_P=file"), 0644)
+	if err != nil {
+		t.Fatalf("Não foi possível atualizar o arquivo .env: %v", err)
+	}
+	if err := loader.Reload(); err != nil {
+		t.Fatalf("Erro ao recarregar variáveis de ambiente: %s", err)
+	}
+
+	if got := os.Getenv("RELOAD_A"); got != "2" {
+		t.Errorf("Esperado %s, obtido %s", "2", got)
+	}
+	if _, exists := os.LookupEnv("RELOAD_B"); exists {
+		t.Errorf("RELOAD_B deveria ter sido removida após o Reload")
+	}
+	if source, _ := reader.Source("RELOAD_P"); source != config.SourceProcess {
+		t.Errorf("Esperado %s, obtido %s", config.SourceProcess, source)
+	}
+	if len(reader.All()) != 2 {
+		t.Errorf("Esperado 2 variáveis, obtido %v", reader.All())
+	}
+}
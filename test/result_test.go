@@ -0,0 +1,37 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestLoadEnvResultReportsSkippedKeys verifica se a função LoadEnvResult retorna o arquivo escolhido,
+a quantidade de variáveis definidas e as variáveis ignoradas por já existirem no processo.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestLoadEnvResultReportsSkippedKeys(t *testing.T) {
+	setupEnvDir(t, "result", "RESULT_A=1\nRESULT_B=2\nRESULT_C=3")
+	t.Setenv("RESULT_B", "process")
+
+	loader := config.NewEnvLoader()
+	result, err := loader.LoadEnvResult()
+	if err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	if result.Env != "result" || len(result.Files) != 1 {
+		t.Errorf("Resultado inesperado: %+v", result)
+	}
+	if result.Loaded != 2 {
+		t.Errorf("Esperado %d, obtido %d", 2, result.Loaded)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0] != "RESULT_B" {
+		t.Errorf("Esperado [RESULT_B], obtido %v", result.Skipped)
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("Esperado 1 aviso, obtido %v", result.Warnings)
+	}
+}
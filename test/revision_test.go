@@ -0,0 +1,106 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestRevisionMiddleware verifica se RevisionMiddleware expõe a revisão no cabeçalho e no contexto da requisição,
+e se a revisão muda quando uma recarga altera a configuração.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestRevisionMiddleware(t *testing.T) {
+	dir := setupEnvDir(t, "test", "REV_MW_PORT=8080\n")
+	t.Cleanup(func() { os.Unsetenv("REV_MW_PORT") })
+
+	loader := config.NewEnvLoader().(*config.FileEnvLoader)
+	result, err := loader.LoadEnvResult()
+	if err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+	first := loader.Revision()
+	if first == "" || result.Revision != first {
+		t.Fatalf("Revisão inesperada: loader %q, resultado %q", first, result.Revision)
+	}
+
+	var fromContext string
+	handler := config.RevisionMiddleware(loader, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fromContext = config.RevisionFromContext(req.Context())
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := rec.Header().Get(config.RevisionHeader); got != first || fromContext != first {
+		t.Errorf("Esperada a revisão %q, obtido cabeçalho %q e contexto %q", first, got, fromContext)
+	}
+
+	if err := os.WriteFile(path.Join(dir, ".env.test"), []byte("REV_MW_PORT=9090\n"), 0644); err != nil {
+		t.Fatalf("Não foi possível alterar o arquivo .env: %v", err)
+	}
+	if err := loader.Reload(); err != nil {
+		t.Fatalf("Erro ao recarregar o ambiente: %v", err)
+	}
+	if loader.Revision() == first {
+		t.Errorf("Esperada uma nova revisão após a recarga")
+	}
+}
+
+/*
+TestEqualRevision verifica se configurações idênticas possuem a mesma revisão, independentemente da ordem em que
+as variáveis foram definidas, e se o valor de um segredo só altera a revisão quando ela é calculada com
+WithRevisionKey.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestEqualRevision(t *testing.T) {
+	blue := config.NewMapLoader("production", map[string]string{"HOST": "db", "PORT": "5432"})
+	blue.SetSecret("DB_PASSWORD", "s3cret")
+
+	green := config.NewMapLoader("production", nil)
+	green.SetSecret("DB_PASSWORD", "s3cret")
+	green.Set("PORT", "5432")
+	green.Set("HOST", "db")
+
+	if !blue.EqualRevision(green) || !config.EqualRevision(blue.Snapshot(), green) {
+		t.Fatalf("Esperadas revisões iguais, obtido %q e %q", blue.Revision(), green.Revision())
+	}
+
+	view := green.Snapshot()
+	green.SetSecret("DB_PASSWORD", "rotated")
+	if !blue.EqualRevision(green) {
+		t.Errorf("Sem WithRevisionKey, o valor do segredo não deveria entrar na revisão pública")
+	}
+	green.Set("PORT", "5433")
+	if blue.EqualRevision(green) {
+		t.Errorf("Esperada uma revisão diferente após a mudança de uma variável")
+	}
+	if !view.EqualRevision(blue) {
+		t.Errorf("Esperado que a visão mantivesse a revisão do momento em que foi criada")
+	}
+
+	dir := setupEnvDir(t, "revkey", "REVKEY_HOST=db\nREVKEY_PASSWORD=s3cret\n")
+	load := func(key string) string {
+		t.Helper()
+		loader := config.NewEnvLoader(config.WithSilent(), config.WithNoProcessEnv(), config.WithSecretKeys("*_PASSWORD"), config.WithRevisionKey([]byte(key)))
+		if err := loader.LoadEnv(); err != nil {
+			t.Fatalf("Erro ao carregar variáveis de ambiente: %v", err)
+		}
+		return loader.Revision()
+	}
+	first := load("deploy-key")
+	if other := load("outra-chave"); other == first {
+		t.Errorf("Esperadas revisões diferentes para chaves diferentes, obtido %q", other)
+	}
+	if err := os.WriteFile(path.Join(dir, ".env.revkey"), []byte("REVKEY_HOST=db\nREVKEY_PASSWORD=rotated\n"), 0644); err != nil {
+		t.Fatalf("Não foi possível alterar o arquivo .env: %v", err)
+	}
+	if rotated := load("deploy-key"); rotated == first {
+		t.Errorf("Com WithRevisionKey, esperada uma revisão diferente após a rotação do segredo")
+	}
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestRewriteFileEncryptsInPlace verifica se RewriteFile, com EncryptValue, cifra apenas os valores escolhidos,
+preservando comentários e o restante das linhas, e se o arquivo resultante é decifrado pelo carregador.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestRewriteFileEncryptsInPlace(t *testing.T) {
+	dir := setupEnvDir(t, "rewrite", "# banco\nexport REWRITE_PASSWORD = \"a b\" # senha\nREWRITE_PORT=8080\n")
+	file := path.Join(dir, ".env.rewrite")
+
+	local, err := config.NewLocalKeyDecrypter([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("Erro ao criar a chave: %v", err)
+	}
+
+	n, err := config.RewriteFile(file, func(key, value string) (string, bool, error) {
+		if key != "REWRITE_PASSWORD" {
+			return "", false, nil
+		}
+		encrypted, err := config.EncryptValue("local", local, value)
+		return encrypted, err == nil, err
+	})
+	if err != nil || n != 1 {
+		t.Fatalf("Esperava 1 valor reescrito, obteve %d (%v)", n, err)
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Erro ao ler o arquivo: %v", err)
+	}
+	values, err := config.Parse(bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("O arquivo reescrito deveria ser válido: %v", err)
+	}
+	if !config.IsEncrypted(values["REWRITE_PASSWORD"]) || values["REWRITE_PORT"] != "8080" {
+		t.Errorf("Valores inesperados após a reescrita: %v", values)
+	}
+	if got := string(content[:len("# banco\nexport REWRITE_PASSWORD = enc:local:")]); got != "# banco\nexport REWRITE_PASSWORD = enc:local:" {
+		t.Errorf("O início da declaração deveria ser preservado, obtido %q", got)
+	}
+	if got := string(content[len(content)-len(" # senha\nREWRITE_PORT=8080\n"):]); got != " # senha\nREWRITE_PORT=8080\n" {
+		t.Errorf("O comentário e as linhas seguintes deveriam ser preservados, obtido %q", got)
+	}
+
+	if err := config.NewEnvLoader(config.WithDecrypter("local", local)).LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if got := os.Getenv("REWRITE_PASSWORD"); got != "a b" {
+		t.Errorf("Esperado %s, obtido %s", "a b", got)
+	}
+}
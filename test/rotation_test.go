@@ -0,0 +1,55 @@
+package test
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestRotationHookDebouncesSecretChanges verifica se os callbacks de rotação recebem os valores antigo e novo,
+se rotações seguidas são agrupadas e se apenas os segredos que correspondem ao padrão são notificados.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestRotationHookDebouncesSecretChanges(t *testing.T) {
+	dir := setupEnvDir(t, "rotation", "ROTATION_DB_PASSWORD=v1\nROTATION_API_TOKEN=t1")
+	file := path.Join(dir, ".env.rotation")
+
+	rotations := make(chan config.SecretRotation, 4)
+	loader := config.NewEnvLoader(
+		config.WithSecretKeys("*_PASSWORD", "*_TOKEN"),
+		config.WithRotationHook("*_PASSWORD", func(r config.SecretRotation) { rotations <- r }),
+		config.WithRotationDebounce(50*time.Millisecond),
+	)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	for _, content := range []string{"ROTATION_DB_PASSWORD=v2\nROTATION_API_TOKEN=t2", "ROTATION_DB_PASSWORD=v3\nROTATION_API_TOKEN=t2"} {
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatalf("Não foi possível atualizar o arquivo .env: %v", err)
+		}
+		if err := loader.Reload(); err != nil {
+			t.Fatalf("Erro ao recarregar variáveis de ambiente: %s", err)
+		}
+	}
+
+	select {
+	case r := <-rotations:
+		if r.Key != "ROTATION_DB_PASSWORD" || r.Old != "v1" || r.New != "v3" || r.Removed {
+			t.Errorf("Rotação inesperada: %+v", r)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("O callback de rotação não foi chamado")
+	}
+
+	select {
+	case r := <-rotations:
+		t.Errorf("Esperava uma única rotação agrupada, obteve também %+v", r)
+	case <-time.After(150 * time.Millisecond):
+	}
+}
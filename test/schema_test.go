@@ -0,0 +1,102 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestSchemaAnnotations verifica se LoadSchema lê as anotações dos comentários do esquema e se WithSchema exige as
+variáveis obrigatórias, classifica os segredos e valida os tipos declarados.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestSchemaAnnotations(t *testing.T) {
+	dir := setupEnvDir(t, "test", "SCHEMA_PORT=abc\nSCHEMA_TOKEN=s3cr3t\n")
+	t.Cleanup(func() {
+		for _, key := range []string{"SCHEMA_PORT", "SCHEMA_TOKEN", "SCHEMA_NAME"} {
+			os.Unsetenv(key)
+		}
+	})
+
+	example := path.Join(dir, ".env.example")
+	content := "# Porta do servidor HTTP\n# @required\n# @type int\nSCHEMA_PORT=8080\n\n" +
+		"# @secret\n# @required\n# @description Token | da API\nSCHEMA_TOKEN=\n\n" +
+		"SCHEMA_NAME=app\n"
+	if err := os.WriteFile(example, []byte(content), 0644); err != nil {
+		t.Fatalf("Não foi possível criar o esquema: %v", err)
+	}
+
+	specs, err := config.LoadSchema(example)
+	if err != nil {
+		t.Fatalf("Erro ao ler o esquema: %v", err)
+	}
+	if len(specs) != 3 {
+		t.Fatalf("Esperadas 3 variáveis, obtidas %d", len(specs))
+	}
+	if port := specs[0]; !port.Required || port.Type != "int" || port.Description != "Porta do servidor HTTP" || port.Example != "8080" {
+		t.Errorf("Anotações inesperadas para SCHEMA_PORT: %+v", port)
+	}
+	if token := specs[1]; !token.Secret || !token.Required || token.Description != "Token | da API" {
+		t.Errorf("Anotações inesperadas para SCHEMA_TOKEN: %+v", token)
+	}
+	if name := specs[2]; name.Required {
+		t.Errorf("SCHEMA_NAME não deveria ser obrigatória quando o esquema usa @required")
+	}
+
+	err = config.NewEnvLoader(config.WithSchema(specs)).LoadEnv()
+	var validationErr *config.ValidationError
+	if !errors.As(err, &validationErr) || !strings.Contains(err.Error(), "SCHEMA_PORT") {
+		t.Fatalf("Esperado um *ValidationError para SCHEMA_PORT, obtido %v", err)
+	}
+
+	if err := os.WriteFile(path.Join(dir, ".env.test"), []byte("SCHEMA_PORT=9090\nSCHEMA_TOKEN=s3cr3t\n"), 0644); err != nil {
+		t.Fatalf("Não foi possível reescrever o arquivo .env: %v", err)
+	}
+	loader := config.NewEnvLoader(config.WithSchema(specs))
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+	if secret, ok := loader.GetSecret("SCHEMA_TOKEN"); !ok || secret != "s3cr3t" {
+		t.Errorf("SCHEMA_TOKEN deveria ser um segredo")
+	}
+
+	doc := config.SchemaMarkdown(specs)
+	if !strings.Contains(doc, "| `SCHEMA_PORT` | int | sim | `8080` | Porta do servidor HTTP |") || strings.Contains(doc, "Token | da") {
+		t.Errorf("Documentação inesperada:\n%s", doc)
+	}
+}
+
+/*
+TestSchemaWithoutAnnotations verifica se, em um esquema sem @required, todas as variáveis continuam obrigatórias,
+exceto as anotadas com @optional, e se anotações desconhecidas são rejeitadas.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestSchemaWithoutAnnotations(t *testing.T) {
+	dir := t.TempDir()
+	example := path.Join(dir, ".env.example")
+	if err := os.WriteFile(example, []byte("PLAIN_A=\n# @optional\nPLAIN_B=\n"), 0644); err != nil {
+		t.Fatalf("Não foi possível criar o esquema: %v", err)
+	}
+
+	specs, err := config.LoadSchema(example)
+	if err != nil {
+		t.Fatalf("Erro ao ler o esquema: %v", err)
+	}
+	if !specs[0].Required || specs[1].Required {
+		t.Errorf("Obrigatoriedade inesperada: %+v", specs)
+	}
+
+	if err := os.WriteFile(example, []byte("# @secreto\nPLAIN_A=\n"), 0644); err != nil {
+		t.Fatalf("Não foi possível reescrever o esquema: %v", err)
+	}
+	if _, err := config.LoadSchema(example); err == nil || !strings.Contains(err.Error(), "@secreto") {
+		t.Errorf("Esperado um erro para a anotação desconhecida, obtido %v", err)
+	}
+}
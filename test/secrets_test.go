@@ -0,0 +1,34 @@
+package test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestSecretIsolationKeepsSecretsOutOfEnviron verifica se, com WithSecretIsolation, as variáveis classificadas
+como segredo ficam disponíveis apenas via GetSecret e não são aplicadas ao ambiente do processo.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestSecretIsolationKeepsSecretsOutOfEnviron(t *testing.T) {
+	setupEnvDir(t, "isolated", "ISOLATED_DB_PASSWORD=hunter2\nISOLATED_DB_HOST=db")
+
+	loader := config.NewEnvLoader(config.WithSecretKeys("*_PASSWORD"), config.WithSecretIsolation())
+	err := loader.LoadEnv()
+	if err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	if _, exists := os.LookupEnv("ISOLATED_DB_PASSWORD"); exists {
+		t.Errorf("O segredo não deveria ter sido aplicado ao ambiente do processo")
+	}
+	if got, ok := loader.GetSecret("ISOLATED_DB_PASSWORD"); !ok || got != "hunter2" {
+		t.Errorf("Esperado %s, obtido %s", "hunter2", got)
+	}
+	if got := os.Getenv("ISOLATED_DB_HOST"); got != "db" {
+		t.Errorf("Esperado %s, obtido %s", "db", got)
+	}
+}
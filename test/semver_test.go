@@ -0,0 +1,231 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestSemverGetters verifica se GetSemver e GetSemverConstraint, acessados pela interface Reader, convertem as
+variáveis e informam o arquivo e a linha dos valores inválidos.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestSemverGetters(t *testing.T) {
+	setupEnvDir(t, "semver", strings.Join([]string{
+		"SMV_MIN=v1.4.2-rc.1+build.7",
+		`SMV_RANGE=">= 1.2, <2 || ^3.1"`,
+		"SMV_BAD=1.04.0",
+		"SMV_BAD_RANGE=>=1.x.2",
+	}, "\n")+"\n")
+
+	loader := config.NewEnvLoader(config.WithNoProcessEnv(), config.WithSilent())
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	var reader config.Reader = loader
+
+	v, err := reader.GetSemver("SMV_MIN")
+	if err != nil || v.Major != 1 || v.Minor != 4 || v.Patch != 2 || v.Prerelease != "rc.1" || v.Build != "build.7" {
+		t.Fatalf("Versão inesperada: %+v, %v", v, err)
+	}
+	if v.String() != "1.4.2-rc.1+build.7" {
+		t.Errorf("Esperava 1.4.2-rc.1+build.7, obteve %s", v)
+	}
+
+	constraint, err := reader.GetSemverConstraint("SMV_RANGE")
+	if err != nil {
+		t.Fatalf("Erro ao ler a restrição: %s", err)
+	}
+	if !constraint.Check(mustSemver(t, "1.9.9")) || constraint.Check(mustSemver(t, "2.0.0")) {
+		t.Errorf("Resultado inesperado da restrição %q", `>= 1.2, <2 || ^3.1`)
+	}
+
+	tests := []struct {
+		name string
+		get  func() error
+		want string
+	}{
+		{"versão inválida", func() error { _, err := reader.GetSemver("SMV_BAD"); return err }, ".env.semver:3"},
+		{"restrição inválida", func() error { _, err := reader.GetSemverConstraint("SMV_BAD_RANGE"); return err }, ".env.semver:4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.get(); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Esperava erro com %q, obteve %v", tt.want, err)
+			}
+		})
+	}
+
+	if _, err := reader.GetSemver("SMV_UNSET"); !errors.Is(err, config.ErrKeyNotFound) {
+		t.Errorf("Esperava ErrKeyNotFound, obteve %v", err)
+	}
+	if _, err := reader.GetSemverConstraint("SMV_UNSET"); !errors.Is(err, config.ErrKeyNotFound) {
+		t.Errorf("Esperava ErrKeyNotFound, obteve %v", err)
+	}
+}
+
+/*
+TestParseSemver verifica se ParseSemver aceita as versões válidas, com o prefixo v opcional, e rejeita números
+incompletos, zeros à esquerda e identificadores vazios.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestParseSemver(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"1.2.3", "1.2.3"},
+		{"v1.2.3", "1.2.3"},
+		{"1.0.0-alpha.1", "1.0.0-alpha.1"},
+		{"1.0.0-0a+exp.sha.5114f85", "1.0.0-0a+exp.sha.5114f85"},
+		{"1.2", ""},
+		{"01.2.3", ""},
+		{"1.2.3.4", ""},
+		{"1.2.3-", ""},
+		{"1.2.3-01", ""},
+		{"1.2.3-a..b", ""},
+		{"1.2.3+", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			v, err := config.ParseSemver(tt.raw)
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("Esperava erro para %q, obteve %s", tt.raw, v)
+				}
+				return
+			}
+			if err != nil || v.String() != tt.want {
+				t.Errorf("Esperava %s, obteve %s (%v)", tt.want, v, err)
+			}
+		})
+	}
+}
+
+/*
+TestSemverPrereleaseOrdering verifica se Compare segue a precedência do SemVer: pré-lançamentos antes da versão
+final, identificadores numéricos antes dos alfanuméricos e metadados de compilação ignorados.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestSemverPrereleaseOrdering(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-alpha.beta", "1.0.0-beta", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0", "1.0.0-rc.1", 1},
+		{"2.0.0-rc.1", "1.9.9", 1},
+		{"1.0.0+build.1", "1.0.0+build.2", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			if got := mustSemver(t, tt.a).Compare(mustSemver(t, tt.b)); got != tt.want {
+				t.Errorf("Compare(%s, %s) = %d, esperava %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+/*
+TestSemverConstraintCheck verifica se as restrições com operadores, ^, ~, curingas e || aceitam apenas as versões
+do intervalo, inclusive os pré-lançamentos nos limites.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestSemverConstraintCheck(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{">= 1.2, <2 || ^3.1", "1.2.0", true},
+		{">= 1.2, <2 || ^3.1", "1.1.9", false},
+		{">= 1.2, <2 || ^3.1", "2.0.0-rc.1", false},
+		{">= 1.2, <2 || ^3.1", "3.9.0", true},
+		{">= 1.2, <2 || ^3.1", "3.0.9", false},
+		{">= 1.2, <2 || ^3.1", "4.0.0", false},
+		{"^1.4", "2.0.0-rc.1", false},
+		{">=1.4.0", "2.0.0-rc.1", true},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"~1.4.2", "1.4.9", true},
+		{"~1.4.2", "1.4.1", false},
+		{"~1.4.2", "1.5.0", false},
+		{"1.x", "1.99.0", true},
+		{"1.x", "2.0.0", false},
+		{"*", "0.0.1", true},
+		{"!=1.0.0", "1.0.0", false},
+		{"!=1.0.0", "1.0.1", true},
+		{">1.4", "1.4.9", false},
+		{">1.4", "1.5.0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint+" "+tt.version, func(t *testing.T) {
+			c, err := config.ParseSemverConstraint(tt.constraint)
+			if err != nil {
+				t.Fatalf("Erro ao converter %q: %s", tt.constraint, err)
+			}
+			if got := c.Check(mustSemver(t, tt.version)); got != tt.want {
+				t.Errorf("%s: Check(%s) = %v, esperava %v", tt.constraint, tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+/*
+TestParseSemverConstraintInvalid verifica se ParseSemverConstraint rejeita grupos vazios, operadores sem versão e
+versões inválidas, descrevendo o problema no erro.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestParseSemverConstraintInvalid(t *testing.T) {
+	tests := []struct {
+		constraint string
+		want       string
+	}{
+		{"", "grupo vazio"},
+		{"1.2.3 ||", "grupo vazio"},
+		{"|| 1.2", "grupo vazio"},
+		{"^", "versão ausente"},
+		{">=", "versão ausente"},
+		{">=1.x.2", "números após um curinga"},
+		{">>1", "não é um número"},
+		{"1.2.3.4", "inválida"},
+		{"1.2 - 2.0", "esperado MAJOR.MINOR.PATCH"},
+		{"<1.0.0-", "identificador vazio"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			_, err := config.ParseSemverConstraint(tt.constraint)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Esperava erro com %q para %q, obteve %v", tt.want, tt.constraint, err)
+			}
+		})
+	}
+}
+
+// mustSemver converte uma versão, interrompendo o teste em caso de erro.
+func mustSemver(t *testing.T, raw string) config.Semver {
+	t.Helper()
+
+	v, err := config.ParseSemver(raw)
+	if err != nil {
+		t.Fatalf("Erro ao converter %q: %s", raw, err)
+	}
+
+	return v
+}
@@ -0,0 +1,60 @@
+package test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestSnapshotIsImmutable verifica se uma ConfigView não é afetada por alterações posteriores no carregador
+e se pode ser lida de várias goroutines enquanto o carregador muda.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestSnapshotIsImmutable(t *testing.T) {
+	loader := config.NewMapLoader("production", map[string]string{"SNAPSHOT_PORT": "8080"})
+	loader.SetSecret("SNAPSHOT_TOKEN", "abc")
+
+	view := loader.Snapshot()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if port, err := view.GetInt("SNAPSHOT_PORT"); err != nil || port != 8080 {
+					t.Errorf("Esperado %d, obtido %d (%v)", 8080, port, err)
+					return
+				}
+				if keys := view.Keys(); len(keys) != 1 {
+					t.Errorf("A visão não deveria ver as variáveis novas, obtido %v", keys)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		loader.Set("SNAPSHOT_PORT", "9090")
+		loader.Set(fmt.Sprintf("SNAPSHOT_NEW_%d", i), "x")
+	}
+	wg.Wait()
+
+	if token, ok := view.GetSecret("SNAPSHOT_TOKEN"); !ok || token != "abc" {
+		t.Errorf("Esperado %s, obtido %s", "abc", token)
+	}
+	if !view.IsProduction() {
+		t.Errorf("Esperado ambiente de produção, obtido %s", view.GetEnv())
+	}
+	if port := loader.Snapshot().GetString("SNAPSHOT_PORT"); port != "9090" {
+		t.Errorf("Uma nova visão deveria refletir a alteração, obtido %s", port)
+	}
+
+	var empty config.ConfigView
+	if _, ok := empty.Lookup("SNAPSHOT_PORT"); ok {
+		t.Errorf("A visão vazia não deveria conter variáveis")
+	}
+}
@@ -0,0 +1,68 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestSplitByPrefix verifica se as variáveis são agrupadas pelo prefixo mais longo, com as demais no grupo "",
+se os grupos são gravados em arquivos 0600 e se ApplyToCmd entrega as variáveis a um processo filho.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestSplitByPrefix(t *testing.T) {
+	setupEnvDir(t, "split", "PAYMENTS_DB_URL=postgres://pay\nPAYMENTS_API_KEY=s3cr3t\nPAYMENTS_WEBHOOK_SECRET=whsec\nAUTH_ISSUER=https://auth\nLOG_LEVEL=debug\nPAYMENTSX=1\n")
+
+	loader := config.NewEnvLoader(config.WithNoProcessEnv(), config.WithSilent(), config.WithSecretKeys("*_KEY", "*_SECRET"), config.WithSecretIsolation()).(*config.FileEnvLoader)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	groups := loader.SplitByPrefix("PAYMENTS", "PAYMENTS_WEBHOOK_", "AUTH_")
+	want := map[string]map[string]string{
+		"PAYMENTS":          {"PAYMENTS_DB_URL": "postgres://pay", "PAYMENTS_API_KEY": "s3cr3t"},
+		"PAYMENTS_WEBHOOK_": {"PAYMENTS_WEBHOOK_SECRET": "whsec"},
+		"AUTH_":             {"AUTH_ISSUER": "https://auth"},
+		"":                  {"LOG_LEVEL": "debug", "PAYMENTSX": "1"},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Fatalf("Esperava os grupos %v, obteve %v", want, groups)
+	}
+
+	dir := t.TempDir()
+	files, err := config.WriteSplitFiles(dir, groups)
+	if err != nil {
+		t.Fatalf("Erro ao gravar os grupos: %s", err)
+	}
+	names := []string{"auth.env", "payments.env", "payments_webhook.env", "shared.env"}
+	for i, name := range names {
+		if files[i] != path.Join(dir, name) {
+			t.Errorf("Esperava o arquivo %s, obteve %v", name, files)
+		}
+	}
+	info, err := os.Stat(path.Join(dir, "payments.env"))
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("Esperava payments.env com permissão 0600, obteve %v, %v", info, err)
+	}
+	if content, _ := os.ReadFile(path.Join(dir, "payments.env")); string(content) != "PAYMENTS_API_KEY=s3cr3t\nPAYMENTS_DB_URL=postgres://pay\n" {
+		t.Errorf("Conteúdo inesperado de payments.env:\n%s", content)
+	}
+
+	cmd := exec.Command("sh", "-c", `printf '%s %s' "$PAYMENTS_DB_URL" "$LOG_LEVEL"`)
+	cmd.Env = []string{"LOG_LEVEL=info"}
+	config.ApplyToCmd(cmd, groups[""])
+	config.ApplyToCmd(cmd, groups["PAYMENTS"])
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Erro ao executar o processo filho: %s", err)
+	}
+	if string(out) != "postgres://pay debug" {
+		t.Errorf("Esperava as variáveis no processo filho, obteve %q", out)
+	}
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestSyncWithStore verifica se CompareWithStore lista as diferenças entre o arquivo local e a loja, com os
+segredos mascarados, e se PullFromStore e PushToStore gravam apenas os valores que faltam ou diferem.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestSyncWithStore(t *testing.T) {
+	dir := t.TempDir()
+	local := path.Join(dir, ".env.staging")
+	if err := os.WriteFile(local, []byte("# local\nSYNC_A=1\nSYNC_B=local\nSYNC_DB_PASSWORD=antiga\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(dir, "remote.staging.env"), []byte("SYNC_B=remoto\nSYNC_C=3\nSYNC_DB_PASSWORD=nova\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := config.OpenStore("file", map[string]string{"path": path.Join(dir, "remote.{env}.env")})
+	if err != nil {
+		t.Fatalf("Erro ao abrir a loja: %s", err)
+	}
+
+	diff, err := config.CompareWithStore(local, store, "staging", []string{"*_PASSWORD"})
+	if err != nil {
+		t.Fatalf("Erro ao comparar com a loja: %s", err)
+	}
+	want := "- SYNC_A=1\n~ SYNC_B: local -> remoto\n+ SYNC_C=3\n~ SYNC_DB_PASSWORD: ****** -> ******"
+	if got := diff.String(); got != want {
+		t.Errorf("Esperado:\n%s\nobtido:\n%s", want, got)
+	}
+
+	pushed, err := config.PushToStore(local, store, "staging")
+	if err != nil || !reflect.DeepEqual(pushed, []string{"SYNC_A", "SYNC_B", "SYNC_DB_PASSWORD"}) {
+		t.Fatalf("Resultado inesperado de PushToStore: %v, %v", pushed, err)
+	}
+	remote, _ := store.Fetch("staging")
+	if want := map[string]string{"SYNC_A": "1", "SYNC_B": "local", "SYNC_C": "3", "SYNC_DB_PASSWORD": "antiga"}; !reflect.DeepEqual(remote, want) {
+		t.Errorf("Esperado %v na loja, obtido %v", want, remote)
+	}
+
+	pulled, err := config.PullFromStore(local, store, "staging")
+	if err != nil || !reflect.DeepEqual(pulled, []string{"SYNC_C"}) {
+		t.Fatalf("Resultado inesperado de PullFromStore: %v, %v", pulled, err)
+	}
+	if content, _ := os.ReadFile(local); string(content) != "# local\nSYNC_A=1\nSYNC_B=local\nSYNC_DB_PASSWORD=antiga\nSYNC_C=3\n" {
+		t.Errorf("Conteúdo inesperado do arquivo local:\n%s", content)
+	}
+
+	if _, err := config.OpenStore("vault", nil); err == nil {
+		t.Error("Esperava erro para uma loja não registrada")
+	}
+}
+
+/*
+TestSyncWithStoreEncrypted verifica se a sincronização compara os valores cifrados já decifrados, se PushToStore
+envia o valor em claro e se PullFromStore mantém a linha com o marcador enc:.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestSyncWithStoreEncrypted(t *testing.T) {
+	local, err := config.NewLocalKeyDecrypter(make([]byte, 32))
+	if err != nil {
+		t.Fatalf("Erro ao criar a chave: %v", err)
+	}
+	password, err := config.EncryptValue("local", local, "s3cr3t")
+	if err != nil {
+		t.Fatalf("Erro ao cifrar o valor: %v", err)
+	}
+	token, err := config.EncryptValue("local", local, "t0k3n")
+	if err != nil {
+		t.Fatalf("Erro ao cifrar o valor: %v", err)
+	}
+
+	dir := t.TempDir()
+	file := path.Join(dir, ".env.staging")
+	content := "SYNCENC_HOST=db\nSYNCENC_DB_PASSWORD=" + password + "\nSYNCENC_API_TOKEN=" + token + "\n"
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path.Join(dir, "remote.staging.env"), []byte("SYNCENC_HOST=db\nSYNCENC_DB_PASSWORD=s3cr3t\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	store, err := config.OpenStore("file", map[string]string{"path": path.Join(dir, "remote.{env}.env")})
+	if err != nil {
+		t.Fatalf("Erro ao abrir a loja: %s", err)
+	}
+	decrypter := config.WithDecrypter("local", local)
+
+	var decryptErr *config.DecryptError
+	if _, err := config.CompareWithStore(file, store, "staging", nil); !errors.As(err, &decryptErr) {
+		t.Errorf("Esperado um *DecryptError sem o Decrypter, obtido %v", err)
+	}
+	diff, err := config.CompareWithStore(file, store, "staging", []string{"*_TOKEN"}, decrypter)
+	if err != nil {
+		t.Fatalf("Erro ao comparar com a loja: %s", err)
+	}
+	if got := diff.String(); got != "- SYNCENC_API_TOKEN=******" {
+		t.Errorf("Esperada apenas a ausência de SYNCENC_API_TOKEN, obtido:\n%s", got)
+	}
+
+	pushed, err := config.PushToStore(file, store, "staging", decrypter)
+	if err != nil || !reflect.DeepEqual(pushed, []string{"SYNCENC_API_TOKEN"}) {
+		t.Fatalf("Resultado inesperado de PushToStore: %v, %v", pushed, err)
+	}
+	if remote, _ := store.Fetch("staging"); remote["SYNCENC_API_TOKEN"] != "t0k3n" {
+		t.Errorf("A loja deveria receber o valor em claro, obteve %q", remote["SYNCENC_API_TOKEN"])
+	}
+
+	if err := store.Push("staging", map[string]string{"SYNCENC_DB_PASSWORD": "nova"}); err != nil {
+		t.Fatal(err)
+	}
+	pulled, err := config.PullFromStore(file, store, "staging", decrypter)
+	if err != nil || len(pulled) != 0 {
+		t.Fatalf("Resultado inesperado de PullFromStore: %v, %v", pulled, err)
+	}
+	if got, _ := os.ReadFile(file); string(got) != content {
+		t.Errorf("As linhas cifradas não deveriam ser alteradas:\n%s", got)
+	}
+}
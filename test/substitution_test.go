@@ -0,0 +1,113 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestCommandSubstitution verifica se os trechos $(...) são substituídos pela saída dos comandos permitidos, se
+os valores entre aspas simples e os carregadores sem a opção mantêm o texto literal e se os comandos não
+permitidos e os que excedem o tempo limite interrompem o carregamento.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestCommandSubstitution(t *testing.T) {
+	for _, name := range []string{"echo", "sleep"} {
+		if _, err := exec.LookPath(name); err != nil {
+			t.Skipf("Comando %s indisponível: %v", name, err)
+		}
+	}
+
+	setupEnvDir(t, "subst", "SUB_SHA=$(echo abc123)\nSUB_MIXED=\"v-$(echo 1 2)-x\"\nSUB_LITERAL='$(echo nao)'\n")
+	t.Cleanup(func() {
+		for _, key := range []string{"SUB_SHA", "SUB_MIXED", "SUB_LITERAL"} {
+			os.Unsetenv(key)
+		}
+	})
+
+	loader := config.NewEnvLoader(config.WithNoProcessEnv(), config.WithAllowCommandSubstitution(time.Second, "echo"))
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	want := map[string]string{"SUB_SHA": "abc123", "SUB_MIXED": "v-1 2-x", "SUB_LITERAL": "$(echo nao)"}
+	for key, value := range want {
+		if got := loader.GetString(key); got != value {
+			t.Errorf("Esperado %s=%q, obtido %q", key, value, got)
+		}
+	}
+
+	plain := config.NewEnvLoader(config.WithNoProcessEnv())
+	if err := plain.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if got := plain.GetString("SUB_SHA"); got != "$(echo abc123)" {
+		t.Errorf("Esperava o valor literal sem a opção, obteve %q", got)
+	}
+
+	err := config.NewEnvLoader(config.WithSilent(), config.WithNoProcessEnv(), config.WithAllowCommandSubstitution(time.Second, "git")).LoadEnv()
+	if err == nil || !strings.Contains(err.Error(), `o comando "echo" não está entre os permitidos`) {
+		t.Errorf("Esperava erro de comando não permitido, obteve %v", err)
+	}
+
+	setupEnvDir(t, "subst", "SUB_SLOW=$(sleep 5)\n")
+	err = config.NewEnvLoader(config.WithSilent(), config.WithNoProcessEnv(), config.WithAllowCommandSubstitution(50*time.Millisecond, "sleep")).LoadEnv()
+	if err == nil || !strings.Contains(err.Error(), ".env.subst:1: substituição de comando em SUB_SLOW: sleep: tempo limite de 50ms excedido") {
+		t.Errorf("Esperava erro de tempo limite, obteve %v", err)
+	}
+}
+
+/*
+TestCommandSubstitutionSourceOnly verifica se apenas os trechos $(...) escritos no arquivo, sem aspas ou entre
+aspas duplas, são executados: valores entre aspas simples, escapados com \$( ou vindos da expansão de outra
+variável ou da saída de um comando continuam literais.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestCommandSubstitutionSourceOnly(t *testing.T) {
+	if _, err := exec.LookPath("echo"); err != nil {
+		t.Skipf("Comando echo indisponível: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"aspas simples", `SUB_VALUE='$(echo x)'`, "$(echo x)"},
+		{"escape sem aspas", `SUB_VALUE=\$(echo x)`, "$(echo x)"},
+		{"escape entre aspas duplas", `SUB_VALUE="a-\$(echo x)"`, "a-$(echo x)"},
+		{"referência sem aspas", "SUB_RAW='$(echo x)'\nSUB_VALUE=${SUB_RAW}", "$(echo x)"},
+		{"referência entre aspas duplas", "SUB_RAW='$(echo x)'\nSUB_VALUE=\"a-$SUB_RAW\"", "a-$(echo x)"},
+		{"saída do comando", `SUB_VALUE=$(echo $(echo x))`, "$(echo x)"},
+		{"aspas duplas", `SUB_VALUE="a-$(echo x)"`, "a-x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupEnvDir(t, "subst", tt.content+"\n")
+			loader := config.NewEnvLoader(config.WithSilent(), config.WithNoProcessEnv(), config.WithAllowCommandSubstitution(time.Second, "echo"))
+			if err := loader.LoadEnv(); err != nil {
+				t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+			}
+			t.Cleanup(func() {
+				os.Unsetenv("SUB_RAW")
+				os.Unsetenv("SUB_VALUE")
+			})
+			if got := loader.GetString("SUB_VALUE"); got != tt.want {
+				t.Errorf("Esperado SUB_VALUE=%q, obtido %q", tt.want, got)
+			}
+		})
+	}
+
+	setupEnvDir(t, "subst", "SUB_OPEN=$(echo x\n")
+	err := config.NewEnvLoader(config.WithSilent(), config.WithNoProcessEnv(), config.WithAllowCommandSubstitution(time.Second, "echo")).LoadEnv()
+	if err == nil || !strings.Contains(err.Error(), ".env.subst:1: substituição de comando em SUB_OPEN sem o ')' de fechamento") {
+		t.Errorf("Esperava erro de parêntese não fechado, obteve %v", err)
+	}
+}
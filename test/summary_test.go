@@ -0,0 +1,57 @@
+package test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestStartupSummaryMasksPreview verifica se o resumo de inicialização mostra o perfil, os arquivos e a contagem
+de variáveis, e se a prévia mascara os segredos e as senhas de strings de conexão.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestStartupSummaryMasksPreview(t *testing.T) {
+	setupEnvDir(t, "summary", "SUM_HOST=localhost\nSUM_DB=postgres://app:hunter2@db/app\nSUM_API_TOKEN=tok\n")
+	t.Cleanup(func() {
+		for _, key := range []string{"SUM_HOST", "SUM_DB", "SUM_API_TOKEN"} {
+			os.Unsetenv(key)
+		}
+	})
+
+	loader := config.NewEnvLoader(config.WithSecretKeys("*_TOKEN"), config.WithStartupSummary(config.SummaryVerbose, "SUM_HOST", "SUM_DB", "SUM_API_TOKEN", "SUM_MISSING"))
+	result, err := loader.LoadEnvResult()
+	if err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	want := map[string]string{
+		"SUM_HOST":      "localhost",
+		"SUM_DB":        "postgres://app:******@db/app",
+		"SUM_API_TOKEN": "******",
+		"SUM_MISSING":   "<não definida>",
+	}
+	for key, value := range want {
+		if got := result.Preview[key]; got != value {
+			t.Errorf("Prévia de %s: esperado %q, obtido %q", key, value, got)
+		}
+	}
+
+	brief := result.Summary(false)
+	for _, part := range []string{"ambiente summary", ".env.summary", "3 variável(is)", "1 segredo(s)"} {
+		if !strings.Contains(brief, part) {
+			t.Errorf("Esperava %q no resumo, obtido %q", part, brief)
+		}
+	}
+	if strings.Contains(brief, "SUM_HOST") {
+		t.Errorf("O resumo breve não deveria conter a prévia: %q", brief)
+	}
+
+	verbose := result.Summary(true)
+	if !strings.Contains(verbose, "\n  SUM_HOST=localhost") || strings.Contains(verbose, "hunter2") || strings.Contains(verbose, "tok\n") {
+		t.Errorf("Resumo detalhado inesperado:\n%s", verbose)
+	}
+}
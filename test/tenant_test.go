@@ -0,0 +1,84 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestTenantOverlays verifica se WithTenant aplica a sobreposição do inquilino ao processo e se Tenant e
+TenantMiddleware expõem a configuração de cada inquilino sem alterar o ambiente do processo.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestTenantOverlays(t *testing.T) {
+	dir := setupEnvDir(t, "test", "TENANT_LIMIT=10\nTENANT_THEME=light\n")
+	if err := os.WriteFile(path.Join(dir, ".env.test.acme"), []byte("TENANT_LIMIT=50\nTENANT_API_TOKEN=acme-token\n"), 0644); err != nil {
+		t.Fatalf("Não foi possível criar o arquivo do inquilino: %v", err)
+	}
+	unset := func() {
+		for _, key := range []string{"TENANT_LIMIT", "TENANT_THEME", "TENANT_API_TOKEN"} {
+			os.Unsetenv(key)
+		}
+	}
+	t.Cleanup(unset)
+
+	if err := config.NewEnvLoader(config.WithTenant("ACME")).LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+	if got := os.Getenv("TENANT_LIMIT"); got != "50" {
+		t.Errorf("A sobreposição do inquilino deveria ser aplicada, obtido %q", got)
+	}
+	unset()
+
+	loader := config.NewEnvLoader(config.WithSecretKeys("*TOKEN")).(*config.FileEnvLoader)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+
+	acme, err := loader.Tenant("acme")
+	if err != nil {
+		t.Fatalf("Erro ao obter a configuração do inquilino: %v", err)
+	}
+	if limit, _ := acme.GetInt("TENANT_LIMIT"); limit != 50 || acme.GetString("TENANT_THEME") != "light" {
+		t.Errorf("Configuração inesperada do inquilino: %v", acme.All())
+	}
+	if token, ok := acme.GetSecret("TENANT_API_TOKEN"); !ok || token != "acme-token" {
+		t.Errorf("TENANT_API_TOKEN deveria ser um segredo do inquilino")
+	}
+	if os.Getenv("TENANT_LIMIT") != "10" {
+		t.Errorf("Tenant não deveria alterar o ambiente do processo")
+	}
+	if other, err := loader.Tenant("globex"); err != nil || other.GetString("TENANT_LIMIT") != "10" {
+		t.Errorf("Um inquilino sem arquivo deveria receber a configuração base (%v)", err)
+	}
+	if _, err := loader.Tenant("../acme"); err == nil {
+		t.Errorf("Esperado um erro para um nome de inquilino inválido")
+	}
+
+	handler := config.TenantMiddleware(loader, "", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(config.TenantFromContext(req.Context()) + ":" + config.FromContext(req.Context()).GetString("TENANT_LIMIT")))
+	}))
+	for header, want := range map[string]string{"acme": "acme:50", "": ":10"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(config.DefaultTenantHeader, header)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Body.String() != want {
+			t.Errorf("Resposta inesperada para o inquilino %q: %q", header, rec.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(config.DefaultTenantHeader, "a/b")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Esperado 400 para um inquilino inválido, obtido %d", rec.Code)
+	}
+}
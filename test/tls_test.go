@@ -0,0 +1,97 @@
+package test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+writeTestCertificate cria um certificado autoassinado e a sua chave em arquivos PEM
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+@params dir string - O diretório dos arquivos
+
+@return string - O caminho do certificado
+@return string - O caminho da chave
+*/
+func writeTestCertificate(t *testing.T, dir string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Não foi possível gerar a chave: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "locenv"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Não foi possível criar o certificado: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Não foi possível serializar a chave: %v", err)
+	}
+
+	certFile, keyFile := path.Join(dir, "cert.pem"), path.Join(dir, "key.pem")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+
+	return certFile, keyFile
+}
+
+/*
+TestTLSConfig verifica se TLSConfig monta o *tls.Config a partir das variáveis convencionais e rejeita
+combinações inválidas.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestTLSConfig(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t, t.TempDir())
+
+	loader := config.NewMapLoader("development", map[string]string{
+		"TLS_CERT_FILE":   certFile,
+		"TLS_KEY_FILE":    keyFile,
+		"TLS_CA_FILE":     certFile,
+		"TLS_MIN_VERSION": "1.3",
+		"TLS_INSECURE":    "true",
+	})
+
+	cfg, err := config.TLSConfig(loader, "")
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if len(cfg.Certificates) != 1 || cfg.RootCAs == nil || cfg.MinVersion != tls.VersionTLS13 || !cfg.InsecureSkipVerify {
+		t.Errorf("Configuração inesperada: %+v", cfg)
+	}
+
+	prod := config.NewMapLoader("production", map[string]string{
+		"TLS_CERT_FILE":   certFile,
+		"TLS_MIN_VERSION": "1.4",
+		"TLS_INSECURE":    "true",
+	})
+	_, err = config.TLSConfig(prod, "")
+	for _, want := range []string{"sem a chave", "1.4", "produção"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Esperado %q no erro, obtido %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestTransformValues verifica se as transformações de WithTransform são aplicadas em ordem, se os caminhos
+relativos são resolvidos em relação ao arquivo que os declarou, mesmo com outro diretório atual, e se um erro
+de transformação identifica a declaração.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestTransformValues(t *testing.T) {
+	dir := setupEnvDir(t, "transform", "TRF_LEVEL=\"  DEBUG \"\nTRF_DATA_DIR=data/db\nTRF_CACHE_DIR=~/cache\nTRF_ABS_DIR=/srv/app\nTRF_NAME=\" App \"\n")
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	sub := path.Join(dir, "cmd")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatalf("Não foi possível criar o diretório: %v", err)
+	}
+	if err := os.Chdir(sub); err != nil {
+		t.Fatalf("Não foi possível alterar o diretório de trabalho: %v", err)
+	}
+
+	loader := config.NewEnvLoader(config.WithNoProcessEnv(), config.WithSilent(),
+		config.WithTransform([]string{"TRF_LEVEL"}, config.TrimSpace, config.Lowercase),
+		config.WithTransform([]string{"*_DIR"}, config.ExpandHome, config.AbsPath),
+	)
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	expected := map[string]string{
+		"TRF_LEVEL":     "debug",
+		"TRF_DATA_DIR":  filepath.Join(dir, "data", "db"),
+		"TRF_CACHE_DIR": filepath.Join(home, "cache"),
+		"TRF_ABS_DIR":   "/srv/app",
+		"TRF_NAME":      " App ",
+	}
+	for key, want := range expected {
+		if got := loader.GetString(key); got != want {
+			t.Errorf("Esperava %s=%q, obteve %q", key, want, got)
+		}
+	}
+
+	errInvalid := errors.New("valor inválido")
+	loader = config.NewEnvLoader(config.WithNoProcessEnv(), config.WithSilent(),
+		config.WithTransform([]string{"TRF_NAME"}, func(value string, ctx config.TransformContext) (string, error) {
+			return "", errInvalid
+		}),
+	)
+	err := loader.LoadEnv()
+	if !errors.Is(err, errInvalid) || !strings.Contains(err.Error(), ".env.transform:5: transformação de TRF_NAME") {
+		t.Errorf("Esperava o erro de transformação com a declaração, obteve %v", err)
+	}
+}
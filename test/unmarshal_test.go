@@ -0,0 +1,109 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+type unmarshalServer struct {
+	Host string `env:"HOST,required"`
+	Port int    `env:"PORT"`
+}
+
+type unmarshalConfig struct {
+	Name     string            `env:"APP_NAME,required"`
+	Timeout  time.Duration     `env:"TIMEOUT"`
+	Retries  int               `env:"RETRIES"`
+	Tags     []string          `env:"TAGS"`
+	Zones    []string          `env:"ZONES"`
+	Servers  []unmarshalServer `env:"SERVERS"`
+	Database struct {
+		Host     string `env:"HOST"`
+		Password string `env:"PASSWORD"`
+	} `env:"DATABASE"`
+}
+
+/*
+TestUnmarshalIndexedSlices verifica se Unmarshal preenche campos simples, seções e slices indexados, mantendo
+os valores padrão das variáveis ausentes.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestUnmarshalIndexedSlices(t *testing.T) {
+	loader := config.NewMapLoader("test", map[string]string{
+		"APP_NAME":       "api",
+		"TIMEOUT":        "5s",
+		"TAGS":           "a, b",
+		"ZONES_0":        "sa-east-1a",
+		"ZONES_1":        "sa-east-1b",
+		"SERVERS_0_HOST": "10.0.0.1",
+		"SERVERS_0_PORT": "8080",
+		"SERVERS_1_HOST": "10.0.0.2",
+		"DATABASE_HOST":  "db",
+	})
+	loader.SetSecret("DATABASE_PASSWORD", "s3cret")
+
+	cfg := unmarshalConfig{Retries: 3}
+	if err := config.Unmarshal(loader, &cfg); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if cfg.Name != "api" || cfg.Timeout != 5*time.Second || cfg.Retries != 3 {
+		t.Errorf("Campos simples inesperados: %+v", cfg)
+	}
+	if strings.Join(cfg.Tags, "|") != "a|b" || strings.Join(cfg.Zones, "|") != "sa-east-1a|sa-east-1b" {
+		t.Errorf("Listas inesperadas: %v %v", cfg.Tags, cfg.Zones)
+	}
+	if len(cfg.Servers) != 2 || cfg.Servers[0].Port != 8080 || cfg.Servers[1].Host != "10.0.0.2" {
+		t.Errorf("Servidores inesperados: %+v", cfg.Servers)
+	}
+	if cfg.Database.Host != "db" || cfg.Database.Password != "s3cret" {
+		t.Errorf("Seção inesperada: %+v", cfg.Database)
+	}
+}
+
+/*
+TestUnmarshalDetectsIndexGaps verifica se Unmarshal relata índices ausentes e campos obrigatórios dos itens.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestUnmarshalDetectsIndexGaps(t *testing.T) {
+	loader := config.NewMapLoader("test", map[string]string{
+		"APP_NAME":       "api",
+		"SERVERS_0_PORT": "8080",
+		"SERVERS_2_HOST": "10.0.0.3",
+	})
+
+	var cfg unmarshalConfig
+	err := config.Unmarshal(loader, &cfg)
+
+	var validationErr *config.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Esperado um *ValidationError, obtido %v", err)
+	}
+	if !strings.Contains(err.Error(), "SERVERS_1") {
+		t.Errorf("Esperado o índice ausente SERVERS_1 no erro: %v", err)
+	}
+
+	loader.Set("SERVERS_1_HOST", "10.0.0.2")
+	err = config.Unmarshal(loader, &cfg)
+	if err == nil || !strings.Contains(err.Error(), "SERVERS_0_HOST") {
+		t.Errorf("Esperada a ausência de SERVERS_0_HOST no erro: %v", err)
+	}
+
+	loader.Set("SERVERS_7_HOST", "10.0.0.8")
+	err = config.Unmarshal(loader, &cfg)
+	if err == nil || !strings.Contains(err.Error(), "índices ausentes: SERVERS_3..SERVERS_6") {
+		t.Errorf("Esperado o intervalo SERVERS_3..SERVERS_6 no erro: %v", err)
+	}
+
+	loader.Set("SERVERS_999999999_HOST", "10.0.0.9")
+	err = config.Unmarshal(loader, &cfg)
+	if err == nil || !strings.Contains(err.Error(), "acima do limite") {
+		t.Errorf("Esperado o erro de índice acima do limite: %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestUserOverlaysAreLoadedLast verifica se, com WithUserOverlays, os arquivos .env.<env>.<usuário> e
+.env.local.<usuário> são aplicados por último, e se o domínio do nome de usuário é removido.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestUserOverlaysAreLoadedLast(t *testing.T) {
+	dir := setupEnvDir(t, "useroverlay", "USER_OVERLAY_PORT=8080\nUSER_OVERLAY_PATH=/shared")
+	t.Setenv("LOCENV_USER", `CORP\Alice`)
+
+	if err := os.WriteFile(path.Join(dir, ".env.useroverlay.alice"), []byte("USER_OVERLAY_PORT=9090"), 0644); err != nil {
+		t.Fatalf("Não foi possível criar a sobreposição: %v", err)
+	}
+	if err := os.WriteFile(path.Join(dir, ".env.local.alice"), []byte("USER_OVERLAY_PATH=/home/alice"), 0644); err != nil {
+		t.Fatalf("Não foi possível criar a sobreposição: %v", err)
+	}
+
+	loader := config.NewEnvLoader(config.WithUserOverlays())
+	if err := loader.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	if got := os.Getenv("USER_OVERLAY_PORT"); got != "9090" {
+		t.Errorf("Esperado %s, obtido %s", "9090", got)
+	}
+	if got := os.Getenv("USER_OVERLAY_PATH"); got != "/home/alice" {
+		t.Errorf("Esperado %s, obtido %s", "/home/alice", got)
+	}
+}
@@ -0,0 +1,63 @@
+package test
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestValidateAllBuildsMatrix verifica se ValidateAll valida cada arquivo .env.<ambiente>, ignorando exemplos e
+sobreposições e sem consultar o ambiente do processo, e se o relatório aponta as variáveis ausentes.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestValidateAllBuildsMatrix(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		".env.development":   "VA_HOST=localhost\nVA_NEW_FLAG=1\n",
+		".env.staging":       "VA_HOST=staging\n",
+		".env.production":    "VA_HOST=prod\nVA_NEW_FLAG=0\n",
+		".env.staging.linux": "VA_EXTRA=1\n",
+		".env.example":       "VA_HOST=\n",
+		".env.local":         "VA_LOCAL=1\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(path.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("VA_NEW_FLAG", "processo")
+
+	matrix, err := config.ValidateAll(dir, config.WithRequired("VA_HOST", "VA_NEW_FLAG"))
+	if err != nil {
+		t.Fatalf("Erro ao validar os ambientes: %s", err)
+	}
+
+	var envs []string
+	for _, env := range matrix.Envs {
+		envs = append(envs, env.Env)
+	}
+	if want := []string{"development", "production", "staging"}; !reflect.DeepEqual(envs, want) {
+		t.Fatalf("Esperava os ambientes %v, obteve %v", want, envs)
+	}
+	staging := matrix.Envs[2]
+	if want := []string{"variável obrigatória VA_NEW_FLAG não definida"}; !reflect.DeepEqual(staging.Problems, want) {
+		t.Errorf("Esperava os problemas %v em staging, obteve %v", want, staging.Problems)
+	}
+	if !reflect.DeepEqual(staging.Missing, []string{"VA_NEW_FLAG"}) || staging.Has("VA_EXTRA") {
+		t.Errorf("Resultado inesperado para staging: %+v", staging)
+	}
+	if matrix.OK() || matrix.Complete() {
+		t.Error("Esperava que o relatório indicasse problemas e variáveis ausentes")
+	}
+
+	want := "VARIÁVEL     development  production  staging\nVA_HOST      ✔            ✔           ✔\nVA_NEW_FLAG  ✔            ✔           ✘"
+	if got := matrix.String(); !strings.HasPrefix(got, want) {
+		t.Errorf("Esperado:\n%s\nobtido:\n%s", want, got)
+	}
+}
@@ -0,0 +1,82 @@
+package test
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestNoDiscoveryWithValues verifica se, com WithNoDiscovery e WithValues, o carregador ignora o arquivo .env do
+diretório de trabalho, APP_ENV e o ambiente do processo, usando apenas as variáveis literais.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestNoDiscoveryWithValues(t *testing.T) {
+	setupEnvDir(t, "dev", "LITERAL_HOST=from-file\nLITERAL_ONLY_FILE=1\n")
+	t.Setenv("LITERAL_PORT", "9999")
+
+	loader := config.NewEnvLoader(
+		config.WithSilent(),
+		config.WithNoDiscovery(),
+		config.WithValues(map[string]string{"LITERAL_HOST": "localhost", "LITERAL_PORT": "5432"}),
+		config.WithValues(map[string]string{"LITERAL_REF": "${LITERAL_HOST}"}),
+	)
+	result, err := loader.LoadEnvResult()
+	if err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if result.Env != "" || !reflect.DeepEqual(result.Files, []string{config.SourceMemory}) {
+		t.Errorf("Esperava apenas a camada literal, sem ambiente, obteve %q e %v", result.Env, result.Files)
+	}
+
+	want := map[string]string{"LITERAL_HOST": "localhost", "LITERAL_PORT": "5432", "LITERAL_REF": "${LITERAL_HOST}"}
+	if got := loader.All(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Esperava %v, obteve %v", want, got)
+	}
+	if source, ok := loader.Source("LITERAL_HOST"); !ok || source != config.SourceMemory {
+		t.Errorf("Esperava a origem %q, obteve %q", config.SourceMemory, source)
+	}
+	if _, ok := os.LookupEnv("LITERAL_HOST"); ok || os.Getenv("LITERAL_PORT") != "9999" {
+		t.Error("O ambiente do processo não deveria ser lido nem alterado")
+	}
+
+	empty := config.NewEnvLoader(config.WithSilent(), config.WithNoDiscovery())
+	if err := empty.LoadEnv(); err != nil || len(empty.All()) != 0 {
+		t.Errorf("Sem arquivos nem valores, esperava um carregamento vazio, obteve %v (%v)", empty.All(), err)
+	}
+}
+
+/*
+TestValuesOverrideFiles verifica se as variáveis de WithValues prevalecem sobre os arquivos informados com
+WithFiles e sobre os descobertos.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestValuesOverrideFiles(t *testing.T) {
+	dir := setupEnvDir(t, "dev", "LAYER_HOST=from-file\nLAYER_NAME=app\n")
+	explicit := path.Join(dir, "base.env")
+	if err := os.WriteFile(explicit, []byte("LAYER_HOST=explicit\nLAYER_NAME=base\n"), 0644); err != nil {
+		t.Fatalf("Não foi possível criar o arquivo .env: %v", err)
+	}
+	values := config.WithValues(map[string]string{"LAYER_HOST": "literal"})
+
+	pinned := config.NewEnvLoader(config.WithSilent(), config.WithEnv("test"), config.WithNoDiscovery(), config.WithFiles(explicit), values)
+	if err := pinned.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if pinned.GetEnv() != "test" || pinned.GetString("LAYER_HOST") != "literal" || pinned.GetString("LAYER_NAME") != "base" {
+		t.Errorf("Valores inesperados: ambiente %q, %v", pinned.GetEnv(), pinned.All())
+	}
+
+	discovered := config.NewEnvLoader(config.WithSilent(), config.WithNoProcessEnv(), values)
+	if err := discovered.LoadEnv(); err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+	if discovered.GetString("LAYER_HOST") != "literal" || discovered.GetString("LAYER_NAME") != "app" {
+		t.Errorf("Valores inesperados: %v", discovered.All())
+	}
+}
@@ -0,0 +1,48 @@
+package test
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestVirtualFilesystem verifica se WithFS descobre os arquivos .env subindo a partir do diretório informado em um
+sistema de arquivos virtual, com as sobreposições, e se WithNoProcessEnv mantém as variáveis fora do processo.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestVirtualFilesystem(t *testing.T) {
+	t.Setenv("APP_ENV", "test")
+	t.Setenv("VFS_PROCESS", "processo")
+
+	fsys := fstest.MapFS{
+		".env.test":         {Data: []byte("VFS_HOST=edge\nVFS_PORT=8080\nVFS_PROCESS=arquivo\n")},
+		".env.test.acme":    {Data: []byte("VFS_PORT=9090\n")},
+		"services/api/x.go": {Data: []byte("package api\n")},
+	}
+
+	loader := config.NewEnvLoader(config.WithFS(fsys, "services/api"), config.WithTenant("acme"), config.WithNoProcessEnv())
+	result, err := loader.LoadEnvResult()
+	if err != nil {
+		t.Fatalf("Erro ao carregar o ambiente: %v", err)
+	}
+
+	if len(result.Files) != 2 || result.Files[0] != ".env.test" || result.Files[1] != ".env.test.acme" {
+		t.Errorf("Arquivos inesperados: %v", result.Files)
+	}
+	if loader.GetString("VFS_HOST") != "edge" || loader.GetString("VFS_PORT") != "9090" {
+		t.Errorf("Variáveis inesperadas: %v", loader.All())
+	}
+	if loader.GetString("VFS_PROCESS") != "arquivo" {
+		t.Errorf("Com WithNoProcessEnv, o valor do arquivo deveria ser usado")
+	}
+	if _, exists := os.LookupEnv("VFS_HOST"); exists || os.Getenv("VFS_PROCESS") != "processo" {
+		t.Errorf("WithNoProcessEnv não deveria alterar o ambiente do processo")
+	}
+	if source, _ := loader.Source("VFS_PORT"); source != ".env.test.acme" {
+		t.Errorf("Origem inesperada: %q", source)
+	}
+}
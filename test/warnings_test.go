@@ -0,0 +1,56 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jonh-dev/go-locEnv/config"
+)
+
+/*
+TestWarningHandler verifica se os avisos do carregamento são entregues à função de WithWarningHandler, com a
+classificação, a variável e a declaração, e se Warnings retorna os mesmos avisos de Result.Warnings.
+
+@params t *testing.T - Um ponteiro para o objeto de teste
+*/
+func TestWarningHandler(t *testing.T) {
+	dir := setupEnvDir(t, "warnings", "WRN_OLD=legacy\n# expires: 2000-01-01\nWRN_DEBUG=true\n# merge: sideways\nWRN_PATH=/bin\nWRN_PORT=8080\n")
+	t.Setenv("WRN_PORT", "9090")
+	t.Cleanup(func() {
+		for _, key := range []string{"WRN_OLD", "WRN_NEW", "WRN_DEBUG", "WRN_PATH"} {
+			os.Unsetenv(key)
+		}
+	})
+
+	var delivered []config.Warning
+	loader := config.NewEnvLoader(
+		config.Deprecate("WRN_OLD", "WRN_NEW", "v3"),
+		config.WithWarningHandler(func(w config.Warning) { delivered = append(delivered, w) }),
+	).(*config.FileEnvLoader)
+	result, err := loader.LoadEnvResult()
+	if err != nil {
+		t.Fatalf("Erro ao carregar variáveis de ambiente: %s", err)
+	}
+
+	file := filepath.Join(dir, ".env.warnings")
+	want := []config.Warning{
+		{Kind: config.WarningExpired, Key: "WRN_DEBUG", File: file, Line: 3},
+		{Kind: config.WarningAnnotation, Key: "WRN_PATH", File: file, Line: 5},
+		{Kind: config.WarningDeprecated, Key: "WRN_OLD", File: file, Line: 1},
+		{Kind: config.WarningConflict, Key: "WRN_PORT", File: file, Line: 6},
+	}
+	warnings := loader.Warnings()
+	if len(delivered) != len(want) || len(warnings) != len(want) || len(result.Warnings) != len(want) {
+		t.Fatalf("Esperava %d avisos, obteve %v, %v e %v", len(want), delivered, warnings, result.Warnings)
+	}
+	for i, w := range want {
+		got := warnings[i]
+		if got.Kind != w.Kind || got.Key != w.Key || got.File != w.File || got.Line != w.Line {
+			t.Errorf("Aviso %d inesperado: %+v", i, got)
+		}
+		if delivered[i] != got || result.Warnings[i] != got.Message {
+			t.Errorf("Aviso %d diferente entre a função, Warnings e Result: %+v, %+v, %q", i, delivered[i], got, result.Warnings[i])
+		}
+	}
+}